@@ -17,6 +17,9 @@ import (
 
 // GetAccount looks up the config.Account object referenced by the given accountID, with access rules applied
 func GetAccount(ctx context.Context, cfg *config.Configuration, fetcher stored_requests.AccountFetcher, accountID string, me metrics.MetricsEngine) (account *config.Account, errs []error) {
+	// account_defaults may have been swapped by a config reload
+	cfg = cfg.Current()
+
 	if cfg.AccountRequired && accountID == metrics.PublisherUnknown {
 		return nil, []error{&errortypes.AcctRequired{
 			Message: "Prebid-server has been configured to discard requests without a valid Account ID. Please reach out to the prebid server host.",
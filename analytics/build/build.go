@@ -8,16 +8,18 @@ import (
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/analytics/agma"
 	"github.com/prebid/prebid-server/v3/analytics/clients"
+	"github.com/prebid/prebid-server/v3/analytics/eventlog"
 	"github.com/prebid/prebid-server/v3/analytics/filesystem"
 	"github.com/prebid/prebid-server/v3/analytics/pubstack"
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/ortb"
 	"github.com/prebid/prebid-server/v3/privacy"
 )
 
 // Modules that need to be logged to need to be initialized here
-func New(analytics *config.Analytics) analytics.Runner {
+func New(analytics *config.Analytics, me metrics.MetricsEngine) analytics.Runner {
 	modules := make(enabledAnalytics, 0)
 	if len(analytics.File.Filename) > 0 {
 		if mod, err := filesystem.NewFileLogger(analytics.File.Filename); err == nil {
@@ -56,6 +58,15 @@ func New(analytics *config.Analytics) analytics.Runner {
 		}
 	}
 
+	if analytics.EventLog.Enabled {
+		eventLogModule, err := eventlog.NewModule(analytics.EventLog, clock.New(), me)
+		if err == nil {
+			modules["eventlog"] = eventLogModule
+		} else {
+			glog.Errorf("Could not initialize EventLog Analytics: %v", err)
+		}
+	}
+
 	return modules
 }
 
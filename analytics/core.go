@@ -32,6 +32,30 @@ type AuctionObject struct {
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	Floors               *FloorsObject
+}
+
+// FloorsObject describes how price floors were applied to an auction
+type FloorsObject struct {
+	ModelVersion string
+	Location     string
+	FetchStatus  string
+	Skipped      bool
+	Enforced     bool
+	RejectedBids []FloorsBid
+	WinningBids  []FloorsBid
+}
+
+// FloorsBid is a bid priced against a floor, with the floor that applied to it
+type FloorsBid struct {
+	Bidder   string
+	ImpID    string
+	BidID    string
+	Rule     string
+	Floor    float64
+	FloorCur string
+	Price    float64
+	Currency string
 }
 
 // Loggable object of a transaction at /openrtb2/amp endpoint
@@ -49,30 +73,33 @@ type AmpObject struct {
 
 // Loggable object of a transaction at /openrtb2/video endpoint
 type VideoObject struct {
-	Status         int
-	Errors         []error
-	Response       *openrtb2.BidResponse
-	VideoRequest   *openrtb_ext.BidRequestVideo
-	VideoResponse  *openrtb_ext.BidResponseVideo
-	StartTime      time.Time
-	SeatNonBid     []openrtb_ext.SeatNonBid
-	RequestWrapper *openrtb_ext.RequestWrapper
+	Status               int
+	Errors               []error
+	Response             *openrtb2.BidResponse
+	VideoRequest         *openrtb_ext.BidRequestVideo
+	VideoResponse        *openrtb_ext.BidResponseVideo
+	StartTime            time.Time
+	HookExecutionOutcome []hookexecution.StageOutcome
+	SeatNonBid           []openrtb_ext.SeatNonBid
+	RequestWrapper       *openrtb_ext.RequestWrapper
 }
 
 // Loggable object of a transaction at /setuid
 type SetUIDObject struct {
-	Status  int
-	Bidder  string
-	UID     string
-	Errors  []error
-	Success bool
+	Status               int
+	Bidder               string
+	UID                  string
+	Errors               []error
+	Success              bool
+	HookExecutionOutcome []hookexecution.StageOutcome
 }
 
 // Loggable object of a transaction at /cookie_sync
 type CookieSyncObject struct {
-	Status       int
-	Errors       []error
-	BidderStatus []*CookieSyncBidder
+	Status               int
+	Errors               []error
+	BidderStatus         []*CookieSyncBidder
+	HookExecutionOutcome []hookexecution.StageOutcome
 }
 
 type CookieSyncBidder struct {
@@ -89,6 +116,7 @@ type UsersyncInfo struct {
 
 // NotificationEvent object of a transaction at /event
 type NotificationEvent struct {
-	Request *EventRequest   `json:"request"`
-	Account *config.Account `json:"account"`
+	Request              *EventRequest                `json:"request"`
+	Account              *config.Account              `json:"account"`
+	HookExecutionOutcome []hookexecution.StageOutcome `json:"-"`
 }
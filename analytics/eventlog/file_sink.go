@@ -0,0 +1,146 @@
+package eventlog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/docker/go-units"
+	"github.com/prebid/prebid-server/v3/config"
+)
+
+// fileSink writes records as JSON lines, and moves on to a new file once the current one reaches the max
+// size or the rotation interval. Rotation is checked when a record is written, so an idle file stays open
+// until the next record comes in.
+type fileSink struct {
+	clock            clock.Clock
+	directory        string
+	prefix           string
+	maxSize          int64
+	rotationInterval time.Duration
+	compress         bool
+
+	file     *os.File
+	counter  *countingWriter
+	gzip     *gzip.Writer
+	writer   *bufio.Writer
+	openedAt time.Time
+}
+
+// NewFileSink creates a sink which writes to rotated files in the configured directory.
+func NewFileSink(cfg config.EventLogFile, clock clock.Clock) (Sink, error) {
+	if cfg.Directory == "" {
+		return nil, errors.New("analytics.eventlog.file.directory must be set")
+	}
+	maxSize, err := units.FromHumanSize(cfg.MaxSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid analytics.eventlog.file.max_size: %v", err)
+	}
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("analytics.eventlog.file.max_size must be > 0. Got %s", cfg.MaxSize)
+	}
+	rotationInterval, err := time.ParseDuration(cfg.RotationInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid analytics.eventlog.file.rotation_interval: %v", err)
+	}
+	if rotationInterval <= 0 {
+		return nil, fmt.Errorf("analytics.eventlog.file.rotation_interval must be > 0. Got %s", cfg.RotationInterval)
+	}
+	if err := os.MkdirAll(cfg.Directory, 0755); err != nil {
+		return nil, err
+	}
+
+	return &fileSink{
+		clock:            clock,
+		directory:        cfg.Directory,
+		prefix:           cfg.Prefix,
+		maxSize:          maxSize,
+		rotationInterval: rotationInterval,
+		compress:         cfg.Compress,
+	}, nil
+}
+
+func (s *fileSink) Write(key string, record []byte) error {
+	if s.file == nil || s.shouldRotate() {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	if _, err := s.writer.Write(record); err != nil {
+		return err
+	}
+	return s.writer.WriteByte('\n')
+}
+
+func (s *fileSink) Close() error {
+	return s.closeFile()
+}
+
+// shouldRotate compares the max size with the bytes which reached the file. With compression, those lag
+// behind what was written, so files may end up slightly larger than the max size.
+func (s *fileSink) shouldRotate() bool {
+	return s.counter.count+int64(s.writer.Buffered()) >= s.maxSize || s.clock.Since(s.openedAt) >= s.rotationInterval
+}
+
+func (s *fileSink) rotate() error {
+	if err := s.closeFile(); err != nil {
+		return err
+	}
+
+	s.openedAt = s.clock.Now()
+	name := fmt.Sprintf("%s-%s.jsonl", s.prefix, s.openedAt.UTC().Format("20060102T150405.000000000"))
+	if s.compress {
+		name += ".gz"
+	}
+	file, err := os.OpenFile(filepath.Join(s.directory, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	s.file = file
+	s.counter = &countingWriter{writer: file}
+	var out io.Writer = s.counter
+	if s.compress {
+		s.gzip = gzip.NewWriter(s.counter)
+		out = s.gzip
+	}
+	s.writer = bufio.NewWriter(out)
+	return nil
+}
+
+func (s *fileSink) closeFile() error {
+	if s.file == nil {
+		return nil
+	}
+	var errs []error
+	if err := s.writer.Flush(); err != nil {
+		errs = append(errs, err)
+	}
+	if s.gzip != nil {
+		if err := s.gzip.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := s.file.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	s.file, s.counter, s.gzip, s.writer = nil, nil, nil, nil
+	return errors.Join(errs...)
+}
+
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count += int64(n)
+	return n, err
+}
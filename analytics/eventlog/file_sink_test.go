@@ -0,0 +1,145 @@
+package eventlog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFileSinkErrors(t *testing.T) {
+	dir := t.TempDir()
+	testCases := []struct {
+		description string
+		cfg         config.EventLogFile
+		expectedErr string
+	}{
+		{
+			description: "no-directory",
+			cfg:         config.EventLogFile{MaxSize: "1MB", RotationInterval: "1h"},
+			expectedErr: "analytics.eventlog.file.directory must be set",
+		},
+		{
+			description: "bad-max-size",
+			cfg:         config.EventLogFile{Directory: dir, MaxSize: "lots", RotationInterval: "1h"},
+			expectedErr: "invalid analytics.eventlog.file.max_size: invalid size: 'lots'",
+		},
+		{
+			description: "bad-rotation-interval",
+			cfg:         config.EventLogFile{Directory: dir, MaxSize: "1MB", RotationInterval: "hourly"},
+			expectedErr: `invalid analytics.eventlog.file.rotation_interval: time: invalid duration "hourly"`,
+		},
+		{
+			description: "zero-rotation-interval",
+			cfg:         config.EventLogFile{Directory: dir, MaxSize: "1MB", RotationInterval: "0s"},
+			expectedErr: "analytics.eventlog.file.rotation_interval must be > 0. Got 0s",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := NewFileSink(test.cfg, clock.NewMock())
+			assert.EqualError(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	testCases := []struct {
+		description string
+		compress    bool
+		extension   string
+	}{
+		{description: "plain", compress: false, extension: ".jsonl"},
+		{description: "compressed", compress: true, extension: ".jsonl.gz"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			dir := t.TempDir()
+			clk := clock.NewMock()
+			clk.Set(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+			sink, err := NewFileSink(config.EventLogFile{
+				Directory:        dir,
+				Prefix:           "events",
+				MaxSize:          "1MB",
+				RotationInterval: "1h",
+				Compress:         test.compress,
+			}, clk)
+			require.NoError(t, err)
+
+			require.NoError(t, sink.Write("", []byte(`{"n":1}`)))
+			require.NoError(t, sink.Write("", []byte(`{"n":2}`)))
+			clk.Add(time.Hour)
+			require.NoError(t, sink.Write("", []byte(`{"n":3}`)))
+			require.NoError(t, sink.Close())
+
+			files, err := filepath.Glob(filepath.Join(dir, "*"))
+			require.NoError(t, err)
+			assert.Equal(t, []string{
+				filepath.Join(dir, "events-20240102T030405.000000000"+test.extension),
+				filepath.Join(dir, "events-20240102T040405.000000000"+test.extension),
+			}, files)
+			assert.Equal(t, []string{`{"n":1}`, `{"n":2}`}, readLines(t, files[0], test.compress))
+			assert.Equal(t, []string{`{"n":3}`}, readLines(t, files[1], test.compress))
+		})
+	}
+}
+
+func TestFileSinkMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	clk := clock.NewMock()
+	sink, err := NewFileSink(config.EventLogFile{
+		Directory:        dir,
+		Prefix:           "events",
+		MaxSize:          "10B",
+		RotationInterval: "1h",
+	}, clk)
+	require.NoError(t, err)
+
+	for _, record := range []string{`{"n":1}`, `{"n":2}`, `{"n":3}`} {
+		require.NoError(t, sink.Write("", []byte(record)))
+		clk.Add(time.Second)
+	}
+	require.NoError(t, sink.Close())
+
+	// Files are rotated once they reach the max size, so the first one goes over it
+	files, err := filepath.Glob(filepath.Join(dir, "events-*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, []string{`{"n":1}`, `{"n":2}`}, readLines(t, files[0], false))
+	assert.Equal(t, []string{`{"n":3}`}, readLines(t, files[1], false))
+}
+
+func readLines(t *testing.T, path string, compressed bool) []string {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var reader io.Reader = file
+	if compressed {
+		gzipReader, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		reader = gzipReader
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
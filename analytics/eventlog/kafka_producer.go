@@ -0,0 +1,81 @@
+package eventlog
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaProducer is the Producer built from analytics.eventlog.kafka. Messages are sent asynchronously in
+// batches, and hashed on their key so that the records of an account go to the same partition.
+type kafkaProducer struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaProducer creates a producer for the configured brokers. It doesn't connect until the first
+// message is produced.
+func NewKafkaProducer(cfg config.EventLogKafka) (Producer, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("analytics.eventlog.kafka.brokers must not be empty")
+	}
+	transport := &kafka.Transport{}
+	if cfg.TLS.Enabled {
+		tlsConfig, err := newKafkaTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLS = tlsConfig
+	}
+
+	return &kafkaProducer{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireOne,
+			Async:        true,
+			Transport:    transport,
+			Completion: func(messages []kafka.Message, err error) {
+				if err != nil {
+					glog.Errorf("[EventLog] Error sending %d events to Kafka: %v", len(messages), err)
+				}
+			},
+		},
+	}, nil
+}
+
+func newKafkaTLSConfig(cfg config.EventLogKafkaTLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		ca, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid analytics.eventlog.kafka.tls.ca_file: %v", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("invalid analytics.eventlog.kafka.tls.ca_file: no certificate found in %s", cfg.CAFile)
+		}
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid analytics.eventlog.kafka.tls client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+func (p *kafkaProducer) Produce(topic string, key []byte, value []byte) error {
+	return p.writer.WriteMessages(context.Background(), kafka.Message{Topic: topic, Key: key, Value: value})
+}
+
+func (p *kafkaProducer) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,59 @@
+package eventlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKafkaProducerTLS(t *testing.T) {
+	directory := t.TempDir()
+	notPEM := filepath.Join(directory, "ca.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0600))
+
+	testCases := []struct {
+		description   string
+		givenTLS      config.EventLogKafkaTLS
+		expectedError string
+	}{
+		{
+			description: "system-roots",
+			givenTLS:    config.EventLogKafkaTLS{Enabled: true},
+		},
+		{
+			description:   "missing-ca-file",
+			givenTLS:      config.EventLogKafkaTLS{Enabled: true, CAFile: filepath.Join(directory, "missing.pem")},
+			expectedError: "invalid analytics.eventlog.kafka.tls.ca_file: open " + filepath.Join(directory, "missing.pem") + ": no such file or directory",
+		},
+		{
+			description:   "invalid-ca-file",
+			givenTLS:      config.EventLogKafkaTLS{Enabled: true, CAFile: notPEM},
+			expectedError: "invalid analytics.eventlog.kafka.tls.ca_file: no certificate found in " + notPEM,
+		},
+		{
+			description:   "invalid-client-certificate",
+			givenTLS:      config.EventLogKafkaTLS{Enabled: true, CertFile: notPEM, KeyFile: notPEM},
+			expectedError: "invalid analytics.eventlog.kafka.tls client certificate: tls: failed to find any PEM data in certificate input",
+		},
+		{
+			description: "disabled",
+			givenTLS:    config.EventLogKafkaTLS{Enabled: false, CAFile: notPEM},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			producer, err := NewKafkaProducer(config.EventLogKafka{Brokers: []string{"localhost:9093"}, Topic: "pbs-events", TLS: test.givenTLS})
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, producer.Close())
+		})
+	}
+}
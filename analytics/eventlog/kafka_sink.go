@@ -0,0 +1,35 @@
+package eventlog
+
+// Producer is the part of a Kafka client which the Kafka sink needs. NewKafkaProducer builds the bundled one
+// from analytics.eventlog.kafka, tests replace it with a fake.
+type Producer interface {
+	// Produce sends a message to the topic. It may return before the message is acknowledged by the broker.
+	Produce(topic string, key []byte, value []byte) error
+	Close() error
+}
+
+type kafkaSink struct {
+	producer Producer
+	topic    string
+}
+
+// NewKafkaSink creates a sink which sends every record to the topic, keyed by account id so that the records
+// of an account stay ordered within a partition.
+func NewKafkaSink(producer Producer, topic string) Sink {
+	return &kafkaSink{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+func (s *kafkaSink) Write(key string, record []byte) error {
+	var keyBytes []byte
+	if key != "" {
+		keyBytes = []byte(key)
+	}
+	return s.producer.Produce(s.topic, keyBytes, record)
+}
+
+func (s *kafkaSink) Close() error {
+	return s.producer.Close()
+}
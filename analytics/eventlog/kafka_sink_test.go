@@ -0,0 +1,72 @@
+package eventlog
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryBroker is an in-memory stand-in for a Kafka broker, which keeps the messages produced to each topic.
+type memoryBroker struct {
+	mux      sync.Mutex
+	messages map[string][]memoryMessage
+	err      error
+	closed   bool
+}
+
+type memoryMessage struct {
+	key   string
+	value string
+}
+
+func newMemoryBroker() *memoryBroker {
+	return &memoryBroker{messages: make(map[string][]memoryMessage)}
+}
+
+func (b *memoryBroker) Produce(topic string, key []byte, value []byte) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.err != nil {
+		return b.err
+	}
+	b.messages[topic] = append(b.messages[topic], memoryMessage{key: string(key), value: string(value)})
+	return nil
+}
+
+func (b *memoryBroker) Close() error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.closed = true
+	return nil
+}
+
+func (b *memoryBroker) topic(topic string) []memoryMessage {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return append([]memoryMessage(nil), b.messages[topic]...)
+}
+
+func TestKafkaSink(t *testing.T) {
+	broker := newMemoryBroker()
+	sink := NewKafkaSink(broker, "pbs-events")
+
+	assert.NoError(t, sink.Write("acct", []byte(`{"type":"auction"}`)))
+	assert.NoError(t, sink.Write("", []byte(`{"type":"setuid"}`)))
+	assert.NoError(t, sink.Close())
+
+	assert.Equal(t, []memoryMessage{
+		{key: "acct", value: `{"type":"auction"}`},
+		{key: "", value: `{"type":"setuid"}`},
+	}, broker.topic("pbs-events"))
+	assert.True(t, broker.closed)
+}
+
+func TestKafkaSinkError(t *testing.T) {
+	broker := newMemoryBroker()
+	broker.err = errors.New("broker unavailable")
+	sink := NewKafkaSink(broker, "pbs-events")
+
+	assert.EqualError(t, sink.Write("acct", []byte(`{}`)), "broker unavailable")
+}
@@ -0,0 +1,313 @@
+package eventlog
+
+import (
+	"sort"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// SchemaVersion is written in every record. It is bumped whenever a field is removed or changes meaning,
+// but not when fields are added.
+const SchemaVersion = 1
+
+type EventType string
+
+const (
+	EventTypeAuction      EventType = "auction"
+	EventTypeAmp          EventType = "amp"
+	EventTypeVideo        EventType = "video"
+	EventTypeCookieSync   EventType = "cookie_sync"
+	EventTypeSetUID       EventType = "setuid"
+	EventTypeNotification EventType = "notification"
+)
+
+// Record is the envelope every event is written in. Exactly one of the payloads is set: Auction for
+// auction, amp and video events, and the payload named after the type for the others.
+type Record struct {
+	SchemaVersion int                 `json:"schema_version"`
+	Type          EventType           `json:"type"`
+	Timestamp     time.Time           `json:"timestamp"`
+	Auction       *AuctionRecord      `json:"auction,omitempty"`
+	CookieSync    *CookieSyncRecord   `json:"cookie_sync,omitempty"`
+	SetUID        *SetUIDRecord       `json:"setuid,omitempty"`
+	Notification  *NotificationRecord `json:"notification,omitempty"`
+}
+
+type AuctionRecord struct {
+	Status    int    `json:"status"`
+	RequestID string `json:"request_id,omitempty"`
+	AccountID string `json:"account_id,omitempty"`
+	// Origin is the AMP page origin, only set for amp events.
+	Origin         string             `json:"origin,omitempty"`
+	DurationMillis int64              `json:"duration_ms"`
+	ImpCount       int                `json:"imp_count"`
+	Errors         []string           `json:"errors,omitempty"`
+	Bidders        []BidderRecord     `json:"bidders,omitempty"`
+	SeatNonBids    []SeatNonBidRecord `json:"seat_non_bids,omitempty"`
+	Hooks          []HookRecord       `json:"hooks,omitempty"`
+}
+
+// BidderRecord holds the response time reported for a bidder, and the bids returned in its seat.
+type BidderRecord struct {
+	Bidder        string      `json:"bidder"`
+	LatencyMillis int         `json:"latency_ms"`
+	Bids          []BidRecord `json:"bids,omitempty"`
+}
+
+type BidRecord struct {
+	ImpID    string   `json:"imp_id"`
+	BidID    string   `json:"bid_id"`
+	Price    float64  `json:"price"`
+	Currency string   `json:"currency"`
+	DealID   string   `json:"deal_id,omitempty"`
+	W        int64    `json:"w,omitempty"`
+	H        int64    `json:"h,omitempty"`
+	ADomain  []string `json:"adomain,omitempty"`
+}
+
+type SeatNonBidRecord struct {
+	Seat       string `json:"seat"`
+	ImpID      string `json:"imp_id"`
+	StatusCode int    `json:"status_code"`
+}
+
+type HookRecord struct {
+	Stage               string `json:"stage"`
+	Entity              string `json:"entity"`
+	Module              string `json:"module"`
+	Hook                string `json:"hook"`
+	Status              string `json:"status"`
+	Action              string `json:"action,omitempty"`
+	ExecutionTimeMillis int64  `json:"execution_time_ms"`
+}
+
+type CookieSyncRecord struct {
+	Status  int                      `json:"status"`
+	Errors  []string                 `json:"errors,omitempty"`
+	Bidders []CookieSyncBidderRecord `json:"bidders,omitempty"`
+}
+
+type CookieSyncBidderRecord struct {
+	Bidder   string `json:"bidder"`
+	NoCookie bool   `json:"no_cookie"`
+}
+
+// SetUIDRecord deliberately leaves out the user id which was set.
+type SetUIDRecord struct {
+	Status  int      `json:"status"`
+	Bidder  string   `json:"bidder"`
+	Success bool     `json:"success"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+type NotificationRecord struct {
+	Type           string `json:"type"`
+	BidID          string `json:"bid_id,omitempty"`
+	AccountID      string `json:"account_id,omitempty"`
+	Bidder         string `json:"bidder,omitempty"`
+	Integration    string `json:"integration,omitempty"`
+	VastEvent      string `json:"vast_event,omitempty"`
+	EventTimestamp int64  `json:"event_timestamp,omitempty"`
+}
+
+// auctionObject holds the parts shared by the auction, amp and video objects.
+type auctionObject struct {
+	status               int
+	errors               []error
+	response             *openrtb2.BidResponse
+	account              *config.Account
+	origin               string
+	startTime            time.Time
+	hookExecutionOutcome []hookexecution.StageOutcome
+	seatNonBid           []openrtb_ext.SeatNonBid
+	requestWrapper       *openrtb_ext.RequestWrapper
+}
+
+func newAuctionRecord(ao auctionObject, now time.Time) *AuctionRecord {
+	record := &AuctionRecord{
+		Status:      ao.status,
+		AccountID:   accountID(ao.account, ao.requestWrapper),
+		Origin:      ao.origin,
+		Errors:      errorStrings(ao.errors),
+		Bidders:     bidderRecords(ao.response),
+		SeatNonBids: seatNonBidRecords(ao.seatNonBid),
+		Hooks:       hookRecords(ao.hookExecutionOutcome),
+	}
+	if !ao.startTime.IsZero() {
+		record.DurationMillis = now.Sub(ao.startTime).Milliseconds()
+	}
+	if ao.requestWrapper != nil && ao.requestWrapper.BidRequest != nil {
+		record.RequestID = ao.requestWrapper.ID
+		record.ImpCount = len(ao.requestWrapper.Imp)
+	}
+	return record
+}
+
+func accountID(account *config.Account, requestWrapper *openrtb_ext.RequestWrapper) string {
+	if account != nil && account.ID != "" {
+		return account.ID
+	}
+	if requestWrapper == nil || requestWrapper.BidRequest == nil {
+		return ""
+	}
+	if requestWrapper.Site != nil && requestWrapper.Site.Publisher != nil {
+		return requestWrapper.Site.Publisher.ID
+	}
+	if requestWrapper.App != nil && requestWrapper.App.Publisher != nil {
+		return requestWrapper.App.Publisher.ID
+	}
+	if requestWrapper.DOOH != nil && requestWrapper.DOOH.Publisher != nil {
+		return requestWrapper.DOOH.Publisher.ID
+	}
+	return ""
+}
+
+func errorStrings(errs []error) []string {
+	if len(errs) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	return messages
+}
+
+// bidderRecords merges the bidder response times from the response ext with the bids in each seat.
+// Bidders are sorted by name so that records are stable.
+func bidderRecords(response *openrtb2.BidResponse) []BidderRecord {
+	if response == nil {
+		return nil
+	}
+
+	var ext struct {
+		ResponseTimeMillis map[string]int `json:"responsetimemillis"`
+	}
+	if len(response.Ext) > 0 {
+		// A malformed ext only costs the latencies, the bids are still recorded.
+		_ = jsonutil.Unmarshal(response.Ext, &ext)
+	}
+
+	bidders := make(map[string]*BidderRecord)
+	get := func(name string) *BidderRecord {
+		if bidder, ok := bidders[name]; ok {
+			return bidder
+		}
+		bidder := &BidderRecord{Bidder: name}
+		bidders[name] = bidder
+		return bidder
+	}
+	for name, latency := range ext.ResponseTimeMillis {
+		get(name).LatencyMillis = latency
+	}
+	for _, seatBid := range response.SeatBid {
+		bidder := get(seatBid.Seat)
+		for _, bid := range seatBid.Bid {
+			bidder.Bids = append(bidder.Bids, BidRecord{
+				ImpID:    bid.ImpID,
+				BidID:    bid.ID,
+				Price:    bid.Price,
+				Currency: response.Cur,
+				DealID:   bid.DealID,
+				W:        bid.W,
+				H:        bid.H,
+				ADomain:  bid.ADomain,
+			})
+		}
+	}
+
+	if len(bidders) == 0 {
+		return nil
+	}
+	records := make([]BidderRecord, 0, len(bidders))
+	for _, bidder := range bidders {
+		records = append(records, *bidder)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Bidder < records[j].Bidder })
+	return records
+}
+
+func seatNonBidRecords(seatNonBids []openrtb_ext.SeatNonBid) []SeatNonBidRecord {
+	var records []SeatNonBidRecord
+	for _, seatNonBid := range seatNonBids {
+		for _, nonBid := range seatNonBid.NonBid {
+			records = append(records, SeatNonBidRecord{
+				Seat:       seatNonBid.Seat,
+				ImpID:      nonBid.ImpId,
+				StatusCode: nonBid.StatusCode,
+			})
+		}
+	}
+	return records
+}
+
+func hookRecords(stageOutcomes []hookexecution.StageOutcome) []HookRecord {
+	var records []HookRecord
+	for _, stageOutcome := range stageOutcomes {
+		for _, groupOutcome := range stageOutcome.Groups {
+			for _, hookOutcome := range groupOutcome.InvocationResults {
+				records = append(records, HookRecord{
+					Stage:               stageOutcome.Stage,
+					Entity:              string(stageOutcome.Entity),
+					Module:              hookOutcome.HookID.ModuleCode,
+					Hook:                hookOutcome.HookID.HookImplCode,
+					Status:              string(hookOutcome.Status),
+					Action:              string(hookOutcome.Action),
+					ExecutionTimeMillis: hookOutcome.ExecutionTimeMillis.Milliseconds(),
+				})
+			}
+		}
+	}
+	return records
+}
+
+func newCookieSyncRecord(cso *analytics.CookieSyncObject) *CookieSyncRecord {
+	record := &CookieSyncRecord{
+		Status: cso.Status,
+		Errors: errorStrings(cso.Errors),
+	}
+	for _, bidder := range cso.BidderStatus {
+		if bidder == nil {
+			continue
+		}
+		record.Bidders = append(record.Bidders, CookieSyncBidderRecord{
+			Bidder:   bidder.BidderCode,
+			NoCookie: bidder.NoCookie,
+		})
+	}
+	return record
+}
+
+func newSetUIDRecord(so *analytics.SetUIDObject) *SetUIDRecord {
+	return &SetUIDRecord{
+		Status:  so.Status,
+		Bidder:  so.Bidder,
+		Success: so.Success,
+		Errors:  errorStrings(so.Errors),
+	}
+}
+
+func newNotificationRecord(ne *analytics.NotificationEvent) *NotificationRecord {
+	record := &NotificationRecord{}
+	if ne.Request != nil {
+		record.Type = string(ne.Request.Type)
+		record.BidID = ne.Request.BidID
+		record.AccountID = ne.Request.AccountID
+		record.Bidder = ne.Request.Bidder
+		record.Integration = ne.Request.Integration
+		record.VastEvent = string(ne.Request.VType)
+		record.EventTimestamp = ne.Request.Timestamp
+	}
+	if record.AccountID == "" && ne.Account != nil {
+		record.AccountID = ne.Account.ID
+	}
+	return record
+}
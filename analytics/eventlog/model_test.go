@@ -0,0 +1,101 @@
+package eventlog
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuctionRecord(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ao := auctionObject{
+		status: 200,
+		errors: []error{errors.New("some error")},
+		response: &openrtb2.BidResponse{
+			Cur: "USD",
+			SeatBid: []openrtb2.SeatBid{
+				{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "bid-2", ImpID: "imp-2", Price: 0.5, W: 728, H: 90}}},
+				{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "bid-1", ImpID: "imp-1", Price: 1.25, DealID: "deal", ADomain: []string{"ad.com"}}}},
+			},
+			Ext: []byte(`{"responsetimemillis":{"appnexus":85,"rubicon":120,"openx":40}}`),
+		},
+		origin:    "https://publisher.com",
+		startTime: now.Add(-250 * time.Millisecond),
+		hookExecutionOutcome: []hookexecution.StageOutcome{{
+			Stage:  "entrypoint",
+			Entity: "http-request",
+			Groups: []hookexecution.GroupOutcome{{
+				InvocationResults: []hookexecution.HookOutcome{{
+					ExecutionTime: hookexecution.ExecutionTime{ExecutionTimeMillis: 3 * time.Millisecond},
+					HookID:        hookexecution.HookID{ModuleCode: "acme", HookImplCode: "validator"},
+					Status:        hookexecution.StatusSuccess,
+					Action:        hookexecution.ActionNone,
+				}},
+			}},
+		}},
+		seatNonBid: []openrtb_ext.SeatNonBid{{Seat: "openx", NonBid: []openrtb_ext.NonBid{{ImpId: "imp-1", StatusCode: 101}}}},
+		requestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			ID:   "req",
+			Imp:  []openrtb2.Imp{{ID: "imp-1"}, {ID: "imp-2"}},
+			Site: &openrtb2.Site{Publisher: &openrtb2.Publisher{ID: "pub"}},
+		}},
+	}
+
+	expected := &AuctionRecord{
+		Status:         200,
+		RequestID:      "req",
+		AccountID:      "pub",
+		Origin:         "https://publisher.com",
+		DurationMillis: 250,
+		ImpCount:       2,
+		Errors:         []string{"some error"},
+		Bidders: []BidderRecord{
+			{Bidder: "appnexus", LatencyMillis: 85, Bids: []BidRecord{{ImpID: "imp-1", BidID: "bid-1", Price: 1.25, Currency: "USD", DealID: "deal", ADomain: []string{"ad.com"}}}},
+			{Bidder: "openx", LatencyMillis: 40},
+			{Bidder: "rubicon", LatencyMillis: 120, Bids: []BidRecord{{ImpID: "imp-2", BidID: "bid-2", Price: 0.5, Currency: "USD", W: 728, H: 90}}},
+		},
+		SeatNonBids: []SeatNonBidRecord{{Seat: "openx", ImpID: "imp-1", StatusCode: 101}},
+		Hooks: []HookRecord{{
+			Stage:               "entrypoint",
+			Entity:              "http-request",
+			Module:              "acme",
+			Hook:                "validator",
+			Status:              "success",
+			Action:              "no_action",
+			ExecutionTimeMillis: 3,
+		}},
+	}
+	assert.Equal(t, expected, newAuctionRecord(ao, now))
+}
+
+func TestNewAuctionRecordEmpty(t *testing.T) {
+	assert.Equal(t, &AuctionRecord{Status: 400}, newAuctionRecord(auctionObject{status: 400}, time.Now()))
+}
+
+func TestNewNotificationRecord(t *testing.T) {
+	record := newNotificationRecord(&analytics.NotificationEvent{
+		Request: &analytics.EventRequest{
+			Type:        analytics.Vast,
+			BidID:       "bid",
+			Bidder:      "appnexus",
+			Integration: "web",
+			VType:       analytics.Complete,
+			Timestamp:   1700000000,
+		},
+		Account: nil,
+	})
+	assert.Equal(t, &NotificationRecord{
+		Type:           "vast",
+		BidID:          "bid",
+		Bidder:         "appnexus",
+		Integration:    "web",
+		VastEvent:      "complete",
+		EventTimestamp: 1700000000,
+	}, record)
+}
@@ -0,0 +1,206 @@
+package eventlog
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const moduleName = "eventlog"
+
+type event struct {
+	key    string
+	record []byte
+}
+
+// EventLogModule turns every analytics object into a versioned Record and writes it to a Sink.
+//
+// Records are queued in a buffer of a fixed size, and written by a single goroutine. When the sink can't
+// keep up and the buffer is full, new records are dropped rather than slowing down the requests which
+// produced them. Both the buffer depth and the dropped records are reported as metrics.
+type EventLogModule struct {
+	sink   Sink
+	clock  clock.Clock
+	me     metrics.MetricsEngine
+	events chan event
+	done   chan struct{}
+	mux    sync.RWMutex
+	closed bool
+}
+
+// NewModule creates the module with the file or Kafka sink selected by analytics.eventlog.sink.
+func NewModule(cfg config.EventLog, clock clock.Clock, me metrics.MetricsEngine) (analytics.Module, error) {
+	sink, err := newSink(cfg, clock)
+	if err != nil {
+		return nil, err
+	}
+	return NewModuleWithSink(sink, cfg.BufferSize, clock, me)
+}
+
+func newSink(cfg config.EventLog, clock clock.Clock) (Sink, error) {
+	switch cfg.Sink {
+	case config.EventLogSinkFile, "":
+		return NewFileSink(cfg.File, clock)
+	case config.EventLogSinkKafka:
+		if cfg.Kafka.Topic == "" {
+			return nil, errors.New("analytics.eventlog.kafka.topic must be set")
+		}
+		producer, err := NewKafkaProducer(cfg.Kafka)
+		if err != nil {
+			return nil, err
+		}
+		return NewKafkaSink(producer, cfg.Kafka.Topic), nil
+	default:
+		return nil, fmt.Errorf("unknown analytics.eventlog.sink %s", cfg.Sink)
+	}
+}
+
+// NewModuleWithSink creates the module with any sink, such as a Kafka sink, and starts writing to it.
+func NewModuleWithSink(sink Sink, bufferSize int, clock clock.Clock, me metrics.MetricsEngine) (analytics.Module, error) {
+	return newEventLogModule(sink, bufferSize, clock, me)
+}
+
+func newEventLogModule(sink Sink, bufferSize int, clock clock.Clock, me metrics.MetricsEngine) (*EventLogModule, error) {
+	if bufferSize <= 0 {
+		return nil, fmt.Errorf("analytics.eventlog.buffer_size must be > 0. Got %d", bufferSize)
+	}
+	m := &EventLogModule{
+		sink:   sink,
+		clock:  clock,
+		me:     me,
+		events: make(chan event, bufferSize),
+		done:   make(chan struct{}),
+	}
+	go m.start()
+	return m, nil
+}
+
+func (m *EventLogModule) start() {
+	defer close(m.done)
+	for e := range m.events {
+		m.me.RecordAnalyticsBufferedEvents(moduleName, len(m.events))
+		if err := m.sink.Write(e.key, e.record); err != nil {
+			glog.Errorf("[EventLog] Error writing event: %v", err)
+		}
+	}
+	if err := m.sink.Close(); err != nil {
+		glog.Errorf("[EventLog] Error closing sink: %v", err)
+	}
+}
+
+func (m *EventLogModule) log(key string, record *Record) {
+	record.SchemaVersion = SchemaVersion
+	data, err := jsonutil.Marshal(record)
+	if err != nil {
+		glog.Errorf("[EventLog] Error serializing %s event: %v", record.Type, err)
+		return
+	}
+
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	if m.closed {
+		return
+	}
+	select {
+	case m.events <- event{key: key, record: data}:
+		m.me.RecordAnalyticsBufferedEvents(moduleName, len(m.events))
+	default:
+		m.me.RecordAnalyticsEventDropped(moduleName)
+	}
+}
+
+func (m *EventLogModule) LogAuctionObject(ao *analytics.AuctionObject) {
+	if ao == nil {
+		return
+	}
+	now := m.clock.Now()
+	auction := newAuctionRecord(auctionObject{
+		status:               ao.Status,
+		errors:               ao.Errors,
+		response:             ao.Response,
+		account:              ao.Account,
+		startTime:            ao.StartTime,
+		hookExecutionOutcome: ao.HookExecutionOutcome,
+		seatNonBid:           ao.SeatNonBid,
+		requestWrapper:       ao.RequestWrapper,
+	}, now)
+	m.log(auction.AccountID, &Record{Type: EventTypeAuction, Timestamp: now, Auction: auction})
+}
+
+func (m *EventLogModule) LogAmpObject(ao *analytics.AmpObject) {
+	if ao == nil {
+		return
+	}
+	now := m.clock.Now()
+	auction := newAuctionRecord(auctionObject{
+		status:               ao.Status,
+		errors:               ao.Errors,
+		response:             ao.AuctionResponse,
+		origin:               ao.Origin,
+		startTime:            ao.StartTime,
+		hookExecutionOutcome: ao.HookExecutionOutcome,
+		seatNonBid:           ao.SeatNonBid,
+		requestWrapper:       ao.RequestWrapper,
+	}, now)
+	m.log(auction.AccountID, &Record{Type: EventTypeAmp, Timestamp: now, Auction: auction})
+}
+
+func (m *EventLogModule) LogVideoObject(vo *analytics.VideoObject) {
+	if vo == nil {
+		return
+	}
+	now := m.clock.Now()
+	auction := newAuctionRecord(auctionObject{
+		status:         vo.Status,
+		errors:         vo.Errors,
+		response:       vo.Response,
+		startTime:      vo.StartTime,
+		seatNonBid:     vo.SeatNonBid,
+		requestWrapper: vo.RequestWrapper,
+	}, now)
+	m.log(auction.AccountID, &Record{Type: EventTypeVideo, Timestamp: now, Auction: auction})
+}
+
+func (m *EventLogModule) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+	if cso == nil {
+		return
+	}
+	m.log("", &Record{Type: EventTypeCookieSync, Timestamp: m.clock.Now(), CookieSync: newCookieSyncRecord(cso)})
+}
+
+func (m *EventLogModule) LogSetUIDObject(so *analytics.SetUIDObject) {
+	if so == nil {
+		return
+	}
+	m.log("", &Record{Type: EventTypeSetUID, Timestamp: m.clock.Now(), SetUID: newSetUIDRecord(so)})
+}
+
+func (m *EventLogModule) LogNotificationEventObject(ne *analytics.NotificationEvent) {
+	if ne == nil {
+		return
+	}
+	notification := newNotificationRecord(ne)
+	m.log(notification.AccountID, &Record{Type: EventTypeNotification, Timestamp: m.clock.Now(), Notification: notification})
+}
+
+// Shutdown stops accepting new records, and waits until the buffered ones are written and the sink is closed.
+func (m *EventLogModule) Shutdown() {
+	m.mux.Lock()
+	if m.closed {
+		m.mux.Unlock()
+		return
+	}
+	m.closed = true
+	close(m.events)
+	m.mux.Unlock()
+
+	glog.Info("[EventLog] Shutdown, writing the buffered events")
+	<-m.done
+}
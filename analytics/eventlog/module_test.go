@@ -0,0 +1,152 @@
+package eventlog
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingSink holds every write until it's released, to fill up the module's buffer.
+type blockingSink struct {
+	release chan struct{}
+	mux     sync.Mutex
+	records []string
+}
+
+func (s *blockingSink) Write(key string, record []byte) error {
+	<-s.release
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.records = append(s.records, string(record))
+	return nil
+}
+
+func (s *blockingSink) Close() error {
+	return nil
+}
+
+func TestNewModuleErrors(t *testing.T) {
+	_, err := NewModule(config.EventLog{BufferSize: 10}, clock.NewMock(), &metrics.MetricsEngineMock{})
+	assert.EqualError(t, err, "analytics.eventlog.file.directory must be set")
+
+	_, err = NewModuleWithSink(NewKafkaSink(newMemoryBroker(), "topic"), 0, clock.NewMock(), &metrics.MetricsEngineMock{})
+	assert.EqualError(t, err, "analytics.eventlog.buffer_size must be > 0. Got 0")
+}
+
+func TestNewSink(t *testing.T) {
+	directory := t.TempDir()
+
+	sink, err := newSink(config.EventLog{Sink: config.EventLogSinkFile, File: config.EventLogFile{Directory: directory, MaxSize: "1MB", RotationInterval: "1h"}}, clock.NewMock())
+	require.NoError(t, err)
+	assert.IsType(t, &fileSink{}, sink)
+	assert.NoError(t, sink.Close())
+
+	sink, err = newSink(config.EventLog{Sink: config.EventLogSinkKafka, Kafka: config.EventLogKafka{Brokers: []string{"localhost:9092"}, Topic: "pbs-events"}}, clock.NewMock())
+	require.NoError(t, err)
+	require.IsType(t, &kafkaSink{}, sink)
+	assert.IsType(t, &kafkaProducer{}, sink.(*kafkaSink).producer)
+	assert.Equal(t, "pbs-events", sink.(*kafkaSink).topic)
+	assert.NoError(t, sink.Close())
+
+	_, err = newSink(config.EventLog{Sink: config.EventLogSinkKafka, Kafka: config.EventLogKafka{Brokers: []string{"localhost:9092"}}}, clock.NewMock())
+	assert.EqualError(t, err, "analytics.eventlog.kafka.topic must be set")
+
+	_, err = newSink(config.EventLog{Sink: config.EventLogSinkKafka, Kafka: config.EventLogKafka{Topic: "pbs-events"}}, clock.NewMock())
+	assert.EqualError(t, err, "analytics.eventlog.kafka.brokers must not be empty")
+
+	_, err = newSink(config.EventLog{Sink: "s3"}, clock.NewMock())
+	assert.EqualError(t, err, "unknown analytics.eventlog.sink s3")
+}
+
+func TestModuleWritesRecords(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAnalyticsBufferedEvents", "eventlog", mock.Anything).Return()
+
+	clk := clock.NewMock()
+	clk.Set(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	broker := newMemoryBroker()
+	module, err := NewModuleWithSink(NewKafkaSink(broker, "pbs-events"), 10, clk, me)
+	require.NoError(t, err)
+
+	module.LogAuctionObject(&analytics.AuctionObject{
+		Status:         http.StatusOK,
+		Account:        &config.Account{ID: "acct"},
+		StartTime:      clk.Now().Add(-120 * time.Millisecond),
+		RequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req", Imp: []openrtb2.Imp{{ID: "imp-1"}}}},
+	})
+	module.LogSetUIDObject(&analytics.SetUIDObject{Status: http.StatusOK, Bidder: "appnexus", UID: "secret", Success: true})
+	module.LogCookieSyncObject(&analytics.CookieSyncObject{Status: http.StatusOK, Errors: []error{errors.New("bad request")}})
+	module.LogNotificationEventObject(&analytics.NotificationEvent{Request: &analytics.EventRequest{Type: analytics.Win, BidID: "bid", AccountID: "acct"}})
+	module.LogAmpObject(nil)
+	module.Shutdown()
+
+	// Logging after shutdown is ignored
+	module.LogSetUIDObject(&analytics.SetUIDObject{Status: http.StatusOK})
+
+	messages := broker.topic("pbs-events")
+	require.Len(t, messages, 4)
+	assert.Equal(t, "acct", messages[0].key)
+	assert.JSONEq(t, `{
+		"schema_version": 1,
+		"type": "auction",
+		"timestamp": "2024-01-02T03:04:05Z",
+		"auction": {"status": 200, "request_id": "req", "account_id": "acct", "duration_ms": 120, "imp_count": 1}
+	}`, messages[0].value)
+	assert.Equal(t, "", messages[1].key)
+	assert.JSONEq(t, `{
+		"schema_version": 1,
+		"type": "setuid",
+		"timestamp": "2024-01-02T03:04:05Z",
+		"setuid": {"status": 200, "bidder": "appnexus", "success": true}
+	}`, messages[1].value)
+	assert.JSONEq(t, `{
+		"schema_version": 1,
+		"type": "cookie_sync",
+		"timestamp": "2024-01-02T03:04:05Z",
+		"cookie_sync": {"status": 200, "errors": ["bad request"]}
+	}`, messages[2].value)
+	assert.Equal(t, "acct", messages[3].key)
+	assert.JSONEq(t, `{
+		"schema_version": 1,
+		"type": "notification",
+		"timestamp": "2024-01-02T03:04:05Z",
+		"notification": {"type": "win", "bid_id": "bid", "account_id": "acct"}
+	}`, messages[3].value)
+	assert.True(t, broker.closed)
+}
+
+func TestModuleDropsEventsWhenBufferIsFull(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAnalyticsBufferedEvents", "eventlog", mock.Anything).Return()
+	me.On("RecordAnalyticsEventDropped", "eventlog").Return()
+
+	sink := &blockingSink{release: make(chan struct{})}
+	module, err := newEventLogModule(sink, 1, clock.NewMock(), me)
+	require.NoError(t, err)
+
+	// The first event is taken by the writer, which blocks on the sink. The second one fills the buffer.
+	module.LogSetUIDObject(&analytics.SetUIDObject{Bidder: "a"})
+	assert.Eventually(t, func() bool { return len(module.events) == 0 }, time.Second, time.Millisecond)
+	module.LogSetUIDObject(&analytics.SetUIDObject{Bidder: "b"})
+	module.LogSetUIDObject(&analytics.SetUIDObject{Bidder: "c"})
+	module.LogSetUIDObject(&analytics.SetUIDObject{Bidder: "d"})
+
+	close(sink.release)
+	module.Shutdown()
+
+	assert.Len(t, sink.records, 2)
+	me.AssertNumberOfCalls(t, "RecordAnalyticsEventDropped", 2)
+	me.AssertCalled(t, "RecordAnalyticsBufferedEvents", "eventlog", 1)
+}
@@ -0,0 +1,9 @@
+package eventlog
+
+// Sink is where the eventlog module writes its records. Write is only ever called from a single goroutine.
+type Sink interface {
+	// Write writes a single JSON encoded record. The key is the account id of the record, when it has one.
+	Write(key string, record []byte) error
+	// Close flushes any pending records and releases the sink's resources.
+	Close() error
+}
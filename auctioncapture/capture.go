@@ -0,0 +1,73 @@
+package auctioncapture
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/adapters"
+)
+
+// Version is the version of the capture format, bumped on incompatible changes.
+const Version = 1
+
+// Capture holds everything needed to replay an auction: the request as it was given to the exchange, the
+// account it ran for, the currency rates in effect, every call made to a bidder and the response sent back.
+type Capture struct {
+	Version        int                           `json:"version"`
+	Time           time.Time                     `json:"time"`
+	AccountID      string                        `json:"account_id"`
+	Account        json.RawMessage               `json:"account"`
+	Request        json.RawMessage               `json:"request"`
+	FirstPartyData json.RawMessage               `json:"first_party_data,omitempty"`
+	CurrencyRates  map[string]map[string]float64 `json:"currency_rates,omitempty"`
+	BidderCalls    []BidderCall                  `json:"bidder_calls"`
+	Response       json.RawMessage               `json:"response"`
+}
+
+// BidderCall is one HTTP call made to a bidder. Response is nil if the call failed, in which case Error
+// holds the reason.
+type BidderCall struct {
+	Bidder   string        `json:"bidder"`
+	Request  HTTPRequest   `json:"request"`
+	Response *HTTPResponse `json:"response,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// HTTPRequest is the capture of an adapters.RequestData.
+type HTTPRequest struct {
+	Method  string      `json:"method"`
+	URI     string      `json:"uri"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// HTTPResponse is the capture of an adapters.ResponseData.
+type HTTPResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+func newBidderCall(bidder string, req *adapters.RequestData, resp *adapters.ResponseData, err error) BidderCall {
+	call := BidderCall{Bidder: bidder}
+	if req != nil {
+		call.Request = HTTPRequest{
+			Method:  req.Method,
+			URI:     req.Uri,
+			Headers: req.Headers.Clone(),
+			Body:    string(req.Body),
+		}
+	}
+	if resp != nil {
+		call.Response = &HTTPResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    resp.Headers.Clone(),
+			Body:       string(resp.Body),
+		}
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	return call
+}
@@ -0,0 +1,207 @@
+package auctioncapture
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/firstpartydata"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// Capturer decides which auctions are captured, and saves them once they're done.
+// A nil *Capturer is valid, and never captures anything.
+type Capturer struct {
+	store  Store
+	random func() float64
+	now    func() time.Time
+
+	mu           sync.Mutex
+	maxPerMinute int
+	windowStart  time.Time
+	windowCount  int
+}
+
+// NewCapturer creates a capturer for the host config, or returns nil if auction capture is disabled.
+func NewCapturer(cfg config.AuctionCapture) *Capturer {
+	if !cfg.Enabled {
+		return nil
+	}
+	return newCapturer(NewFileStore(cfg.Directory, time.Duration(cfg.RetentionHours)*time.Hour), cfg.MaxPerMinute)
+}
+
+func newCapturer(store Store, maxPerMinute int) *Capturer {
+	return &Capturer{
+		store:        store,
+		random:       rand.Float64,
+		now:          time.Now,
+		maxPerMinute: maxPerMinute,
+	}
+}
+
+// component is the privacy component the transmitUfpd and transmitPreciseGeo activities are checked for.
+var component = privacy.Component{Type: privacy.ComponentTypeGeneral, Name: "auctioncapture"}
+
+// Start samples the auction, and returns the session recording it, or nil if it isn't captured.
+// The request is serialized right away, so later changes made by the exchange aren't part of the capture.
+//
+// When the transmitUfpd or transmitPreciseGeo activity is denied for the auctioncapture component, the
+// request is scrubbed like it would be for a bidder, the first party data is left out, and only the method
+// and url of the bidder calls are recorded. Replays then match the calls on their url alone.
+func (c *Capturer) Start(account *config.Account, request *openrtb_ext.RequestWrapper, activities privacy.ActivityControl, firstPartyData map[openrtb_ext.BidderName]*firstpartydata.ResolvedFirstPartyData, rates map[string]map[string]float64) *Session {
+	if c == nil || account == nil || request == nil || !account.AuctionCapture.Enabled {
+		return nil
+	}
+	if c.random() >= account.AuctionCapture.SampleRate || !c.allow() {
+		return nil
+	}
+
+	if err := request.RebuildRequest(); err != nil {
+		return nil
+	}
+	activityRequest := privacy.NewRequestFromBidRequest(*request)
+	blockUserFPD := !activities.Allow(privacy.ActivityTransmitUserFPD, component, activityRequest)
+	blockPreciseGeo := !activities.Allow(privacy.ActivityTransmitPreciseGeo, component, activityRequest)
+	if blockUserFPD || blockPreciseGeo {
+		request = scrub(request, activities, blockUserFPD, blockPreciseGeo)
+		if request == nil {
+			return nil
+		}
+	}
+	if blockUserFPD {
+		firstPartyData = nil
+	}
+
+	requestJSON, err := jsonutil.Marshal(request.BidRequest)
+	if err != nil {
+		return nil
+	}
+	accountJSON, err := jsonutil.Marshal(account)
+	if err != nil {
+		return nil
+	}
+	capture := Capture{
+		Version:       Version,
+		Time:          c.now().UTC(),
+		AccountID:     account.ID,
+		Account:       accountJSON,
+		Request:       requestJSON,
+		CurrencyRates: rates,
+	}
+	if len(firstPartyData) > 0 {
+		if fpdJSON, err := jsonutil.Marshal(firstPartyData); err == nil {
+			capture.FirstPartyData = fpdJSON
+		}
+	}
+	return &Session{capture: capture, store: c.store, redactBidderRequests: blockUserFPD || blockPreciseGeo}
+}
+
+// scrub returns a copy of the request without the data the activities don't allow to be captured.
+func scrub(request *openrtb_ext.RequestWrapper, activities privacy.ActivityControl, blockUserFPD, blockPreciseGeo bool) *openrtb_ext.RequestWrapper {
+	scrubbed := &openrtb_ext.RequestWrapper{BidRequest: ortb.CloneBidRequestPartial(request.BidRequest)}
+	if blockUserFPD {
+		privacy.ScrubUserFPD(scrubbed)
+	}
+	if blockPreciseGeo {
+		privacy.ScrubGeoAndDeviceIP(scrubbed, privacy.IPConf{IPV6: activities.IPv6Config, IPV4: activities.IPv4Config})
+	}
+	if err := scrubbed.RebuildRequest(); err != nil {
+		return nil
+	}
+	return scrubbed
+}
+
+// allow enforces max_per_minute over fixed one minute windows.
+func (c *Capturer) allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if now.Sub(c.windowStart) >= time.Minute {
+		c.windowStart = now
+		c.windowCount = 0
+	}
+	if c.windowCount >= c.maxPerMinute {
+		return false
+	}
+	c.windowCount++
+	return true
+}
+
+// Session records a single captured auction. A nil *Session is valid, and records nothing.
+type Session struct {
+	mu       sync.Mutex
+	capture  Capture
+	store    Store
+	finished bool
+	// redactBidderRequests leaves the headers and bodies of the bidder requests out of the capture.
+	redactBidderRequests bool
+}
+
+// RecordBidderCall adds a call made to a bidder to the capture. It's safe to call concurrently.
+func (s *Session) RecordBidderCall(bidder string, req *adapters.RequestData, resp *adapters.ResponseData, err error) {
+	if s == nil {
+		return
+	}
+	call := newBidderCall(bidder, req, resp, err)
+	if s.redactBidderRequests {
+		call.Request.Headers = nil
+		call.Request.Body = ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.finished {
+		return
+	}
+	s.capture.BidderCalls = append(s.capture.BidderCalls, call)
+}
+
+// Finish adds the auction response to the capture and saves it in the background.
+func (s *Session) Finish(response *openrtb2.BidResponse) {
+	if s == nil {
+		return
+	}
+	responseJSON, err := jsonutil.Marshal(response)
+	if err != nil {
+		glog.Errorf("auction capture: failed to marshal the response: %v", err)
+		return
+	}
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return
+	}
+	s.finished = true
+	s.capture.Response = responseJSON
+	capture := s.capture
+	s.mu.Unlock()
+
+	go func() {
+		if err := s.store.Save(&capture); err != nil {
+			glog.Errorf("auction capture: failed to save the capture: %v", err)
+		}
+	}()
+}
+
+type sessionContextKey struct{}
+
+// WithSession returns a context carrying the session, so that bidder calls made with it are recorded.
+func WithSession(ctx context.Context, s *Session) context.Context {
+	if s == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, sessionContextKey{}, s)
+}
+
+// FromContext returns the session carried by the context, or nil if the auction isn't captured.
+func FromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionContextKey{}).(*Session)
+	return s
+}
@@ -0,0 +1,241 @@
+package auctioncapture
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/firstpartydata"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	mu    sync.Mutex
+	saved []*Capture
+	done  chan struct{}
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{done: make(chan struct{}, 10)}
+}
+
+func (s *fakeStore) Save(capture *Capture) error {
+	s.mu.Lock()
+	s.saved = append(s.saved, capture)
+	s.mu.Unlock()
+	s.done <- struct{}{}
+	return nil
+}
+
+func TestNewCapturerDisabled(t *testing.T) {
+	assert.Nil(t, NewCapturer(config.AuctionCapture{Enabled: false}))
+	assert.NotNil(t, NewCapturer(config.AuctionCapture{Enabled: true, Directory: t.TempDir(), MaxPerMinute: 1, RetentionHours: 1}))
+}
+
+func TestCapturerStartSampling(t *testing.T) {
+	testCases := []struct {
+		name       string
+		capture    config.AccountAuctionCapture
+		random     float64
+		expSampled bool
+	}{
+		{
+			name:    "account-not-opted-in",
+			capture: config.AccountAuctionCapture{Enabled: false, SampleRate: 1},
+			random:  0,
+		},
+		{
+			name:    "not-sampled",
+			capture: config.AccountAuctionCapture{Enabled: true, SampleRate: 0.1},
+			random:  0.5,
+		},
+		{
+			name:       "sampled",
+			capture:    config.AccountAuctionCapture{Enabled: true, SampleRate: 0.1},
+			random:     0.05,
+			expSampled: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newCapturer(newFakeStore(), 10)
+			c.random = func() float64 { return tc.random }
+			account := &config.Account{ID: "acct", AuctionCapture: tc.capture}
+			request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req"}}
+
+			session := c.Start(account, request, privacy.ActivityControl{}, nil, nil)
+			assert.Equal(t, tc.expSampled, session != nil)
+		})
+	}
+}
+
+func TestCapturerMaxPerMinute(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newCapturer(newFakeStore(), 2)
+	c.random = func() float64 { return 0 }
+	c.now = func() time.Time { return now }
+	account := &config.Account{ID: "acct", AuctionCapture: config.AccountAuctionCapture{Enabled: true, SampleRate: 1}}
+	start := func() *Session {
+		return c.Start(account, &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req"}}, privacy.ActivityControl{}, nil, nil)
+	}
+
+	assert.NotNil(t, start())
+	assert.NotNil(t, start())
+	assert.Nil(t, start(), "third capture within the minute")
+
+	now = now.Add(time.Minute)
+	assert.NotNil(t, start(), "first capture of the next minute")
+}
+
+func TestNilCapturerAndSession(t *testing.T) {
+	var c *Capturer
+	assert.Nil(t, c.Start(&config.Account{}, &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}, privacy.ActivityControl{}, nil, nil))
+
+	var s *Session
+	s.RecordBidderCall("appnexus", &adapters.RequestData{}, nil, nil)
+	s.Finish(&openrtb2.BidResponse{})
+
+	ctx := WithSession(context.Background(), nil)
+	assert.Nil(t, FromContext(ctx))
+}
+
+func TestSessionRecordsAuction(t *testing.T) {
+	store := newFakeStore()
+	c := newCapturer(store, 10)
+	c.random = func() float64 { return 0 }
+	c.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+	account := &config.Account{ID: "acct", AuctionCapture: config.AccountAuctionCapture{Enabled: true, SampleRate: 1}}
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req"}}
+	rates := map[string]map[string]float64{"USD": {"EUR": 0.9}}
+
+	session := c.Start(account, request, privacy.ActivityControl{}, nil, rates)
+	require.NotNil(t, session)
+	ctx := WithSession(context.Background(), session)
+	assert.Same(t, session, FromContext(ctx))
+
+	// the capture must not see changes made to the request once the auction started
+	request.ID = "changed"
+
+	FromContext(ctx).RecordBidderCall("appnexus",
+		&adapters.RequestData{Method: "POST", Uri: "https://bidder.com/bid", Body: []byte(`{"id":"req"}`)},
+		&adapters.ResponseData{StatusCode: 204},
+		nil)
+	FromContext(ctx).RecordBidderCall("rubicon",
+		&adapters.RequestData{Method: "POST", Uri: "https://other.com/bid"},
+		nil,
+		errors.New("timeout"))
+	session.Finish(&openrtb2.BidResponse{ID: "req"})
+	<-store.done
+
+	require.Len(t, store.saved, 1)
+	capture := store.saved[0]
+	assert.Equal(t, Version, capture.Version)
+	assert.Equal(t, "acct", capture.AccountID)
+	assert.JSONEq(t, `{"id":"req","imp":null}`, string(capture.Request))
+	assert.JSONEq(t, `{"id":"req"}`, string(capture.Response))
+	assert.Equal(t, rates, capture.CurrencyRates)
+	assert.Equal(t, []BidderCall{
+		{
+			Bidder:   "appnexus",
+			Request:  HTTPRequest{Method: "POST", URI: "https://bidder.com/bid", Body: `{"id":"req"}`},
+			Response: &HTTPResponse{StatusCode: 204},
+		},
+		{
+			Bidder:  "rubicon",
+			Request: HTTPRequest{Method: "POST", URI: "https://other.com/bid"},
+			Error:   "timeout",
+		},
+	}, capture.BidderCalls)
+
+	// calls made once the auction is done are not recorded, and the capture is only saved once
+	session.RecordBidderCall("late", &adapters.RequestData{}, nil, nil)
+	session.Finish(&openrtb2.BidResponse{ID: "req"})
+	assert.Len(t, capture.BidderCalls, 2)
+	assert.Len(t, store.done, 0)
+}
+
+func TestCapturerStartScrubsRequest(t *testing.T) {
+	deny := func(activity *config.Activity) {
+		*activity = config.Activity{
+			Default: ptrutil.ToPtr(true),
+			Rules:   []config.ActivityRule{{Allow: false, Condition: config.ActivityCondition{ComponentName: []string{"auctioncapture"}, ComponentType: []string{"general"}}}},
+		}
+	}
+
+	testCases := []struct {
+		description     string
+		denyUserFPD     bool
+		denyPreciseGeo  bool
+		expectedRequest string
+		expectedFPD     bool
+		expectedBody    string
+	}{
+		{
+			description:     "allowed",
+			expectedRequest: `{"id":"req","imp":null,"device":{"geo":{"lat":52.5123,"lon":13.4123},"ip":"192.168.1.123"},"user":{"id":"user-1","buyeruid":"buyer-1"}}`,
+			expectedFPD:     true,
+			expectedBody:    `{"id":"req"}`,
+		},
+		{
+			description:     "transmit-ufpd-denied",
+			denyUserFPD:     true,
+			expectedRequest: `{"id":"req","imp":null,"device":{"geo":{"lat":52.5123,"lon":13.4123},"ip":"192.168.1.123"},"user":{}}`,
+		},
+		{
+			description:     "transmit-precise-geo-denied",
+			denyPreciseGeo:  true,
+			expectedRequest: `{"id":"req","imp":null,"device":{"geo":{"lat":52.51,"lon":13.41},"ip":"192.168.1.0"},"user":{"id":"user-1","buyeruid":"buyer-1"}}`,
+			expectedFPD:     true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			activities := &config.AllowActivities{}
+			if test.denyUserFPD {
+				deny(&activities.TransmitUserFPD)
+			}
+			if test.denyPreciseGeo {
+				deny(&activities.TransmitPreciseGeo)
+			}
+			activityControl := privacy.NewActivityControl(&config.AccountPrivacy{
+				AllowActivities: activities,
+				IPv4Config:      config.IPv4{AnonKeepBits: 24},
+			})
+
+			store := newFakeStore()
+			c := newCapturer(store, 10)
+			c.random = func() float64 { return 0 }
+			account := &config.Account{ID: "acct", AuctionCapture: config.AccountAuctionCapture{Enabled: true, SampleRate: 1}}
+			request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				ID:     "req",
+				Device: &openrtb2.Device{IP: "192.168.1.123", Geo: &openrtb2.Geo{Lat: ptrutil.ToPtr(52.5123), Lon: ptrutil.ToPtr(13.4123)}},
+				User:   &openrtb2.User{ID: "user-1", BuyerUID: "buyer-1"},
+			}}
+			firstPartyData := map[openrtb_ext.BidderName]*firstpartydata.ResolvedFirstPartyData{"appnexus": {User: &openrtb2.User{ID: "user-1"}}}
+
+			session := c.Start(account, request, activityControl, firstPartyData, nil)
+			require.NotNil(t, session)
+			session.RecordBidderCall("appnexus", &adapters.RequestData{Method: "POST", Uri: "https://bidder.com/bid", Body: []byte(`{"id":"req"}`)}, &adapters.ResponseData{StatusCode: 204}, nil)
+			session.Finish(&openrtb2.BidResponse{ID: "req"})
+			<-store.done
+
+			capture := store.saved[0]
+			assert.JSONEq(t, test.expectedRequest, string(capture.Request))
+			assert.Equal(t, test.expectedFPD, capture.FirstPartyData != nil)
+			assert.Equal(t, test.expectedBody, capture.BidderCalls[0].Request.Body)
+			assert.Equal(t, "https://bidder.com/bid", capture.BidderCalls[0].Request.URI)
+			assert.Equal(t, "user-1", request.User.ID, "the auction request must not be scrubbed")
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package auctioncapture
+
+import (
+	"bytes"
+	"encoding/json"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v5"
+)
+
+// Diff returns the JSON merge patch (RFC 7396) turning the recorded response into the replayed one, or nil
+// if they're the same. Keys removed by the replay show up with a null value.
+func Diff(recorded, replayed json.RawMessage) (json.RawMessage, error) {
+	if len(recorded) == 0 {
+		recorded = json.RawMessage("{}")
+	}
+	if len(replayed) == 0 {
+		replayed = json.RawMessage("{}")
+	}
+	patch, err := jsonpatch.CreateMergePatch(recorded, replayed)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(bytes.TrimSpace(patch), []byte("{}")) {
+		return nil, nil
+	}
+	return patch, nil
+}
@@ -0,0 +1,46 @@
+package auctioncapture
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		recorded string
+		replayed string
+		expDiff  string
+	}{
+		{
+			name:     "same",
+			recorded: `{"id":"req","cur":"USD"}`,
+			replayed: `{"cur":"USD","id":"req"}`,
+		},
+		{
+			name:     "changed-and-removed",
+			recorded: `{"id":"req","cur":"USD","seatbid":[{"seat":"appnexus"}]}`,
+			replayed: `{"id":"req","cur":"EUR"}`,
+			expDiff:  `{"cur":"EUR","seatbid":null}`,
+		},
+		{
+			name:     "nothing-recorded",
+			replayed: `{"id":"req"}`,
+			expDiff:  `{"id":"req"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff, err := Diff(json.RawMessage(tc.recorded), json.RawMessage(tc.replayed))
+			assert.NoError(t, err)
+			if tc.expDiff == "" {
+				assert.Nil(t, diff)
+				return
+			}
+			assert.JSONEq(t, tc.expDiff, string(diff))
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package auctioncapture
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Transport is an http.RoundTripper which serves bidder calls from a capture instead of the network, so an
+// auction can be replayed without reaching any bidder.
+//
+// A call is matched to a recorded one with the same method and URL. If several were recorded, the one with
+// the same body is preferred, and otherwise they're served in the order they were recorded. Each recorded
+// call is served at most once.
+type Transport struct {
+	mu    sync.Mutex
+	calls map[string][]*BidderCall
+}
+
+// NewTransport creates a transport serving the bidder calls of the capture.
+func NewTransport(capture *Capture) *Transport {
+	t := &Transport{calls: make(map[string][]*BidderCall)}
+	for i := range capture.BidderCalls {
+		call := &capture.BidderCalls[i]
+		key := callKey(call.Request.Method, call.Request.URI)
+		t.calls[key] = append(t.calls[key], call)
+	}
+	return t
+}
+
+// Unused returns the recorded calls which haven't been served, which usually means the replayed auction
+// didn't call a bidder the original one did.
+func (t *Transport) Unused() []BidderCall {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var unused []BidderCall
+	for _, calls := range t.calls {
+		for _, call := range calls {
+			unused = append(unused, *call)
+		}
+	}
+	return unused
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	call := t.take(callKey(req.Method, req.URL.String()), body)
+	if call == nil {
+		return nil, fmt.Errorf("no captured call for %s %s", req.Method, req.URL.String())
+	}
+	if call.Response == nil {
+		if call.Error != "" {
+			return nil, errors.New(call.Error)
+		}
+		return nil, fmt.Errorf("captured call for %s %s has no response", req.Method, req.URL.String())
+	}
+
+	headers := call.Response.Headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	// The captured body was already decompressed by the HTTP client
+	headers.Del("Content-Encoding")
+	headers.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", call.Response.StatusCode, http.StatusText(call.Response.StatusCode)),
+		StatusCode:    call.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          io.NopCloser(strings.NewReader(call.Response.Body)),
+		ContentLength: int64(len(call.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *Transport) take(key string, body []byte) *BidderCall {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	calls := t.calls[key]
+	if len(calls) == 0 {
+		return nil
+	}
+	index := 0
+	for i, call := range calls {
+		if call.Request.Body == string(body) {
+			index = i
+			break
+		}
+	}
+	call := calls[index]
+	t.calls[key] = append(calls[:index], calls[index+1:]...)
+	return call
+}
+
+// readRequestBody returns the body as the adapter built it, undoing the gzip compression bidders can opt in to.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if req.Header.Get("Content-Encoding") != "gzip" {
+		return body, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func callKey(method, uri string) string {
+	return method + " " + uri
+}
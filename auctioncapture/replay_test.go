@@ -0,0 +1,67 @@
+package auctioncapture
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	capture := &Capture{
+		BidderCalls: []BidderCall{
+			{
+				Bidder:   "appnexus",
+				Request:  HTTPRequest{Method: "POST", URI: "https://bidder.com/bid", Body: `{"id":"first"}`},
+				Response: &HTTPResponse{StatusCode: 200, Headers: http.Header{"Content-Encoding": []string{"gzip"}}, Body: `{"seatbid":[]}`},
+			},
+			{
+				Bidder:   "appnexus",
+				Request:  HTTPRequest{Method: "POST", URI: "https://bidder.com/bid", Body: `{"id":"second"}`},
+				Response: &HTTPResponse{StatusCode: 204},
+			},
+			{
+				Bidder:  "rubicon",
+				Request: HTTPRequest{Method: "GET", URI: "https://other.com/bid"},
+				Error:   "context deadline exceeded",
+			},
+		},
+	}
+	client := &http.Client{Transport: NewTransport(capture)}
+
+	// the body match is preferred over the recorded order
+	resp, err := client.Post("https://bidder.com/bid", "application/json", strings.NewReader(`{"id":"second"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 204, resp.StatusCode)
+
+	// gzipped bodies are matched once decompressed
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write([]byte(`{"id":"first"}`))
+	writer.Close()
+	req, _ := http.NewRequest("POST", "https://bidder.com/bid", &compressed)
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, `{"seatbid":[]}`, string(body))
+
+	// each recorded call is served once
+	_, err = client.Post("https://bidder.com/bid", "application/json", strings.NewReader(`{"id":"first"}`))
+	assert.ErrorContains(t, err, "no captured call for POST https://bidder.com/bid")
+
+	transport := client.Transport.(*Transport)
+	assert.Len(t, transport.Unused(), 1)
+
+	// failed calls fail again
+	_, err = client.Get("https://other.com/bid")
+	assert.ErrorContains(t, err, "context deadline exceeded")
+	assert.Empty(t, transport.Unused())
+}
@@ -0,0 +1,118 @@
+package auctioncapture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Store saves finished captures.
+type Store interface {
+	Save(capture *Capture) error
+}
+
+// FileStore saves each capture as an indented JSON file in a directory, and deletes the files older than
+// its retention.
+type FileStore struct {
+	dir       string
+	retention time.Duration
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// pruneInterval is how often the directory is scanned for the captures to delete.
+const pruneInterval = time.Minute
+
+// NewFileStore creates a store writing to dir. The directory is created on the first save if needed.
+func NewFileStore(dir string, retention time.Duration) *FileStore {
+	return &FileStore{dir: dir, retention: retention}
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// Save writes the capture to a file named after its time and account. The file is written under a temporary
+// name first, so readers never see a partial capture.
+func (s *FileStore) Save(capture *Capture) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(capture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	account := unsafeFileNameChars.ReplaceAllString(capture.AccountID, "_")
+	name := fmt.Sprintf("%d-%s.json", capture.Time.UnixNano(), account)
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, name)); err != nil {
+		return err
+	}
+	return s.prune(time.Now())
+}
+
+// prune deletes the captures, and the temporary files left behind by failed saves, last modified before the
+// retention. It only scans the directory once per pruneInterval.
+func (s *FileStore) prune(now time.Time) error {
+	s.mu.Lock()
+	if now.Sub(s.lastPrune) < pruneInterval {
+		s.mu.Unlock()
+		return nil
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || (filepath.Ext(entry.Name()) != ".json" && filepath.Ext(entry.Name()) != ".tmp") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if now.Sub(info.ModTime()) > s.retention {
+			if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Load reads a capture written by a FileStore.
+func Load(path string) (*Capture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var capture Capture
+	if err := json.Unmarshal(data, &capture); err != nil {
+		return nil, fmt.Errorf("invalid capture %s: %v", path, err)
+	}
+	if capture.Version != Version {
+		return nil, fmt.Errorf("capture %s has version %d, expected %d", path, capture.Version, Version)
+	}
+	return &capture, nil
+}
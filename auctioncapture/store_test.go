@@ -0,0 +1,88 @@
+package auctioncapture
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStoreSaveAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "captures")
+	capture := &Capture{
+		Version:   Version,
+		Time:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		AccountID: "acct/1",
+		Account:   json.RawMessage(`{"id":"acct/1"}`),
+		Request:   json.RawMessage(`{"id":"req"}`),
+		BidderCalls: []BidderCall{
+			{Bidder: "appnexus", Request: HTTPRequest{Method: "POST", URI: "https://bidder.com"}, Response: &HTTPResponse{StatusCode: 204}},
+		},
+		Response: json.RawMessage(`{"id":"req"}`),
+	}
+
+	require.NoError(t, NewFileStore(dir, time.Hour).Save(capture))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "1704067200000000000-acct_1.json", files[0].Name())
+
+	loaded, err := Load(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Equal(t, capture.AccountID, loaded.AccountID)
+	assert.JSONEq(t, string(capture.Request), string(loaded.Request))
+	assert.JSONEq(t, string(capture.Response), string(loaded.Response))
+	assert.Equal(t, capture.BidderCalls, loaded.BidderCalls)
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{`), 0o644))
+	otherVersion := filepath.Join(dir, "version.json")
+	require.NoError(t, os.WriteFile(otherVersion, []byte(`{"version":99}`), 0o644))
+
+	_, err := Load(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+	_, err = Load(invalid)
+	assert.ErrorContains(t, err, "invalid capture")
+	_, err = Load(otherVersion)
+	assert.ErrorContains(t, err, "has version 99, expected 1")
+}
+
+func TestFileStorePrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	write := func(name string, modTime time.Time) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(`{}`), 0o644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	write("old.json", now.Add(-2*time.Hour))
+	write("old.json.123.tmp", now.Add(-2*time.Hour))
+	write("old.txt", now.Add(-2*time.Hour))
+	write("recent.json", now.Add(-30*time.Minute))
+
+	store := NewFileStore(dir, time.Hour)
+	require.NoError(t, store.prune(now))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	assert.Equal(t, []string{"old.txt", "recent.json"}, names)
+
+	// the directory is only scanned once a minute
+	write("old.json", now.Add(-2*time.Hour))
+	require.NoError(t, store.prune(now.Add(30*time.Second)))
+	assert.FileExists(t, filepath.Join(dir, "old.json"))
+	require.NoError(t, store.prune(now.Add(time.Minute)))
+	assert.NoFileExists(t, filepath.Join(dir, "old.json"))
+}
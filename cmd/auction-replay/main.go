@@ -0,0 +1,85 @@
+// Command auction-replay replays an auction captured by a host with auction_capture enabled. The auction
+// runs through the exchange with the host config, but every bidder call is served from the capture, so no
+// bidder is reached. The difference between the recorded and the replayed response is printed as a JSON
+// merge patch, and the exit code is 1 if they differ.
+//
+// Stored responses, the uids cookie and the impression ext details resolved by the endpoint aren't part of
+// a capture, so auctions relying on them may not replay identically.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prebid/prebid-server/v3/auctioncapture"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	jsoniter.RegisterExtension(&jsonutil.RawMessageExtension{})
+}
+
+func main() {
+	capturePath := flag.String("capture", "", "path of the capture to replay")
+	configName := flag.String("config", "pbs", "name of the host config file, looked up like prebid-server does")
+	infoDirectory := flag.String("bidder-info", "./static/bidder-info", "directory of the bidder info files")
+	paramsDirectory := flag.String("bidder-params", "./static/bidder-params", "directory of the bidder params schemas")
+	outPath := flag.String("out", "", "optional path to write the replayed response to")
+	flag.Parse()
+
+	if *capturePath == "" {
+		fmt.Fprintln(os.Stderr, "usage: auction-replay -capture <file> [-config pbs] [-out response.json]")
+		os.Exit(2)
+	}
+
+	capture, err := auctioncapture.Load(*capturePath)
+	if err != nil {
+		glog.Exitf("Unable to load the capture: %v", err)
+	}
+	cfg, err := loadConfig(*configName, *infoDirectory)
+	if err != nil {
+		glog.Exitf("Configuration could not be loaded or did not pass validation: %v", err)
+	}
+
+	result, err := replay(context.Background(), cfg, *paramsDirectory, capture)
+	if err != nil {
+		glog.Exitf("Unable to replay the auction: %v", err)
+	}
+
+	if *outPath != "" {
+		if err := os.WriteFile(*outPath, result.Response, 0o644); err != nil {
+			glog.Exitf("Unable to write the replayed response: %v", err)
+		}
+	}
+	for _, call := range result.UnusedCalls {
+		fmt.Fprintf(os.Stderr, "captured call not replayed: %s %s %s\n", call.Bidder, call.Request.Method, call.Request.URI)
+	}
+	if result.Diff == nil {
+		fmt.Println("responses match")
+		return
+	}
+	fmt.Println(string(result.Diff))
+	os.Exit(1)
+}
+
+func loadConfig(configName, infoDirectory string) (*config.Configuration, error) {
+	bidderInfoPath, err := filepath.Abs(infoDirectory)
+	if err != nil {
+		return nil, err
+	}
+	bidderInfos, err := config.LoadBidderInfoFromDisk(bidderInfoPath)
+	if err != nil {
+		return nil, err
+	}
+	v := viper.New()
+	config.SetupViper(v, configName, bidderInfos)
+	return config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/auctioncapture"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange"
+	"github.com/prebid/prebid-server/v3/experiment/adscert"
+	"github.com/prebid/prebid-server/v3/firstpartydata"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/macros"
+	"github.com/prebid/prebid-server/v3/metrics"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	pbc "github.com/prebid/prebid-server/v3/prebid_cache_client"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/file_fetcher"
+	"github.com/prebid/prebid-server/v3/usersync"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// replayResult is the outcome of a replay.
+type replayResult struct {
+	Response    json.RawMessage
+	Diff        json.RawMessage
+	UnusedCalls []auctioncapture.BidderCall
+}
+
+// replay runs the captured auction through an exchange built from the host config, with the bidder calls
+// served from the capture.
+func replay(ctx context.Context, cfg *config.Configuration, paramsDirectory string, capture *auctioncapture.Capture) (*replayResult, error) {
+	// a replayed auction must not be captured again
+	cfg.AuctionCapture.Enabled = false
+
+	transport := auctioncapture.NewTransport(capture)
+	replayClient := &http.Client{Transport: transport}
+	me := &metricsConf.NilMetricsEngine{}
+
+	adapters, errs := exchange.BuildAdapters(replayClient, cfg, cfg.BidderInfos, me)
+	if len(errs) > 0 {
+		return nil, errortypes.NewAggregateError("Failed to initialize adapters", errs)
+	}
+	syncersByBidder, errs := usersync.BuildSyncers(cfg, cfg.BidderInfos)
+	if len(errs) > 0 {
+		return nil, errortypes.NewAggregateError("user sync", errs)
+	}
+	paramsValidator, err := openrtb_ext.NewBidderParamsValidator(paramsDirectory)
+	if err != nil {
+		return nil, err
+	}
+	requestValidator := ortb.NewRequestValidator(exchange.GetActiveBidders(cfg.BidderInfos), exchange.GetDisabledBidderWarningMessages(cfg.BidderInfos), paramsValidator)
+	categoriesFetcher, err := newCategoriesFetcher(cfg)
+	if err != nil {
+		return nil, err
+	}
+	adsCertSigner, err := adscert.NewAdCertsSigner(cfg.Experiment.AdCerts)
+	if err != nil {
+		return nil, err
+	}
+	rateConverter, err := newRateConverter(capture.CurrencyRates)
+	if err != nil {
+		return nil, err
+	}
+
+	vendorListFetcher := gdpr.NewVendorListFetcher(ctx, cfg.GDPR, &http.Client{}, gdpr.VendorListURLMaker, me)
+	gdprPermsBuilder := gdpr.NewPermissionsBuilder(cfg.GDPR, cfg.BidderInfos.ToGVLVendorIDMap(), vendorListFetcher)
+	cacheClient := pbc.NewClient(replayClient, &cfg.CacheURL, &cfg.ExtCacheURL, me)
+
+	ex := exchange.NewExchange(adapters, cacheClient, cfg, requestValidator, syncersByBidder, me, cfg.BidderInfos, gdprPermsBuilder, rateConverter, categoriesFetcher, adsCertSigner, macros.NewStringIndexBasedReplacer(), nil, nil)
+
+	auctionRequest, err := buildAuctionRequest(cfg, capture)
+	if err != nil {
+		return nil, err
+	}
+	if tmax := auctionRequest.BidRequestWrapper.TMax; tmax > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, auctionRequest.StartTime.Add(time.Duration(tmax)*time.Millisecond))
+		defer cancel()
+	}
+
+	auctionResponse, err := ex.HoldAuction(ctx, auctionRequest, &exchange.DebugLog{})
+	if err != nil {
+		return nil, err
+	}
+	response, err := jsonutil.Marshal(auctionResponse.BidResponse)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := auctioncapture.Diff(capture.Response, response)
+	if err != nil {
+		return nil, err
+	}
+	return &replayResult{Response: response, Diff: diff, UnusedCalls: transport.Unused()}, nil
+}
+
+// buildAuctionRequest rebuilds what the auction endpoint passed to HoldAuction from the capture.
+func buildAuctionRequest(cfg *config.Configuration, capture *auctioncapture.Capture) (*exchange.AuctionRequest, error) {
+	var account config.Account
+	if err := jsonutil.UnmarshalValid(capture.Account, &account); err != nil {
+		return nil, fmt.Errorf("invalid captured account: %v", err)
+	}
+	var request openrtb2.BidRequest
+	if err := jsonutil.UnmarshalValid(capture.Request, &request); err != nil {
+		return nil, fmt.Errorf("invalid captured request: %v", err)
+	}
+	var fpd map[openrtb_ext.BidderName]*firstpartydata.ResolvedFirstPartyData
+	if len(capture.FirstPartyData) > 0 {
+		if err := jsonutil.UnmarshalValid(capture.FirstPartyData, &fpd); err != nil {
+			return nil, fmt.Errorf("invalid captured first party data: %v", err)
+		}
+	}
+
+	requestType, source := metrics.ReqTypeORTB2Web, metrics.DemandWeb
+	if request.App != nil {
+		requestType, source = metrics.ReqTypeORTB2App, metrics.DemandApp
+	}
+	labels := metrics.Labels{
+		Source:        source,
+		RType:         requestType,
+		PubID:         capture.AccountID,
+		RequestStatus: metrics.RequestStatusOK,
+	}
+
+	return &exchange.AuctionRequest{
+		BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &request},
+		Account:           account,
+		UserSyncs:         usersync.NewCookie(),
+		RequestType:       requestType,
+		StartTime:         time.Now(),
+		TCF2Config:        gdpr.NewTCF2Config(cfg.GDPR.TCF2, account.GDPR),
+		Activities:        privacy.NewActivityControl(&account.Privacy),
+		LegacyLabels:      labels,
+		FirstPartyData:    fpd,
+		PubID:             capture.AccountID,
+		HookExecutor:      &hookexecution.EmptyHookExecutor{},
+		TmaxAdjustments:   exchange.ProcessTMaxAdjustments(cfg.TmaxAdjustments),
+	}, nil
+}
+
+// newCategoriesFetcher reads the category mappings from disk when the host does. Other backends aren't
+// supported, so requests relying on them replay without brand categories.
+func newCategoriesFetcher(cfg *config.Configuration) (stored_requests.CategoryFetcher, error) {
+	if !cfg.CategoryMapping.Files.Enabled {
+		return nil, nil
+	}
+	fetcher, err := file_fetcher.NewFileFetcher(cfg.CategoryMapping.Files.Path)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.(stored_requests.CategoryFetcher), nil
+}
+
+// newRateConverter creates a converter holding the currency rates which were in effect for the auction.
+func newRateConverter(rates map[string]map[string]float64) (*currency.RateConverter, error) {
+	converter := currency.NewRateConverter(staticRatesClient{rates: rates}, "http://replay/rates", 0)
+	if err := converter.Run(); err != nil {
+		return nil, err
+	}
+	return converter, nil
+}
+
+// staticRatesClient serves the captured rates to the rate converter.
+type staticRatesClient struct {
+	rates map[string]map[string]float64
+}
+
+func (c staticRatesClient) Do(req *http.Request) (*http.Response, error) {
+	body, err := jsonutil.Marshal(currency.NewRates(c.rates))
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/auctioncapture"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildAuctionRequest(t *testing.T) {
+	capture := &auctioncapture.Capture{
+		AccountID:      "acct",
+		Account:        json.RawMessage(`{"id":"acct","price_floors":{"enabled":true}}`),
+		Request:        json.RawMessage(`{"id":"req","app":{"id":"app"},"imp":[{"id":"imp"}]}`),
+		FirstPartyData: json.RawMessage(`{"appnexus":{"App":{"id":"fpd-app"}}}`),
+	}
+
+	auctionRequest, err := buildAuctionRequest(&config.Configuration{}, capture)
+	require.NoError(t, err)
+	assert.Equal(t, "req", auctionRequest.BidRequestWrapper.ID)
+	assert.Equal(t, "acct", auctionRequest.Account.ID)
+	assert.True(t, auctionRequest.Account.PriceFloors.Enabled)
+	assert.Equal(t, metrics.ReqTypeORTB2App, auctionRequest.RequestType)
+	assert.Equal(t, metrics.DemandApp, auctionRequest.LegacyLabels.Source)
+	require.Contains(t, auctionRequest.FirstPartyData, openrtb_ext.BidderAppnexus)
+	assert.Equal(t, "fpd-app", auctionRequest.FirstPartyData[openrtb_ext.BidderAppnexus].App.ID)
+	assert.NotNil(t, auctionRequest.HookExecutor)
+
+	capture.Request = json.RawMessage(`{`)
+	_, err = buildAuctionRequest(&config.Configuration{}, capture)
+	assert.ErrorContains(t, err, "invalid captured request")
+}
+
+func TestNewRateConverter(t *testing.T) {
+	converter, err := newRateConverter(map[string]map[string]float64{"USD": {"EUR": 0.9}})
+	require.NoError(t, err)
+
+	rate, err := converter.Rates().GetRate("USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, 0.9, rate)
+}
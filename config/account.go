@@ -42,6 +42,9 @@ type Account struct {
 	DefaultBidLimit         int                                         `mapstructure:"default_bid_limit" json:"default_bid_limit"`
 	BidAdjustments          *openrtb_ext.ExtRequestPrebidBidAdjustments `mapstructure:"bidadjustments" json:"bidadjustments"`
 	Privacy                 AccountPrivacy                              `mapstructure:"privacy" json:"privacy"`
+	TrafficShaping          AccountTrafficShaping                       `mapstructure:"traffic_shaping" json:"traffic_shaping"`
+	AuctionCapture          AccountAuctionCapture                       `mapstructure:"auction_capture" json:"auction_capture"`
+	VASTUnwrap              AccountVASTUnwrap                           `mapstructure:"vast_unwrap" json:"vast_unwrap"`
 }
 
 // CookieSync represents the account-level defaults for the cookie sync endpoint.
@@ -125,6 +128,73 @@ func (pf *AccountPriceFloors) validate(errs []error) []error {
 	return errs
 }
 
+// AccountTrafficShaping configures when calls to a bidder are skipped because of its historical bid rate
+// on similar traffic. It only has an effect if traffic_shaping is enabled for the host.
+type AccountTrafficShaping struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// MinRequests is the number of calls in the window needed before a bid rate is trusted.
+	MinRequests int `mapstructure:"min_requests" json:"min_requests"`
+	// BidRateThreshold is the bid rate, between 0 and 1, below which calls are throttled.
+	BidRateThreshold float64 `mapstructure:"bid_rate_threshold" json:"bid_rate_threshold"`
+	// ExplorationRate is the share of throttled calls, between 0 and 1, which are made anyway so that
+	// the bid rate keeps getting measured.
+	ExplorationRate float64 `mapstructure:"exploration_rate" json:"exploration_rate"`
+}
+
+func (ts *AccountTrafficShaping) validate(errs []error) []error {
+	if ts.MinRequests < 0 {
+		errs = append(errs, fmt.Errorf(`account_defaults.traffic_shaping.min_requests should be greater than or equal to 0`))
+	}
+
+	if ts.BidRateThreshold < 0 || ts.BidRateThreshold > 1 {
+		errs = append(errs, fmt.Errorf(`account_defaults.traffic_shaping.bid_rate_threshold should be between 0 and 1`))
+	}
+
+	if ts.ExplorationRate < 0 || ts.ExplorationRate > 1 {
+		errs = append(errs, fmt.Errorf(`account_defaults.traffic_shaping.exploration_rate should be between 0 and 1`))
+	}
+
+	return errs
+}
+
+// AccountAuctionCapture opts an account in to the capture of its auctions, so that they can be replayed
+// offline. It only has an effect if auction_capture is enabled for the host.
+type AccountAuctionCapture struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// SampleRate is the share of the account's auctions, between 0 and 1, which are captured.
+	SampleRate float64 `mapstructure:"sample_rate" json:"sample_rate"`
+}
+
+func (ac *AccountAuctionCapture) validate(errs []error) []error {
+	if ac.SampleRate < 0 || ac.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf(`account_defaults.auction_capture.sample_rate should be between 0 and 1`))
+	}
+	return errs
+}
+
+// AccountVASTUnwrap opts an account in to the server-side resolution of the VAST wrappers in its video bids.
+// It only has an effect if vast_unwrap is enabled for the host.
+type AccountVASTUnwrap struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// Enforce rejects the bids whose VAST is invalid or doesn't match the imp. Otherwise they only get a warning.
+	Enforce bool `mapstructure:"enforce" json:"enforce"`
+	// Inline caches the resolved inline VAST of the bids in place of their adm, so that the players fetching
+	// the cached creative don't need the wrapper hops. The adm of the response is left as is.
+	Inline bool `mapstructure:"inline" json:"inline"`
+}
+
+// Validate checks the settings of an account which has been merged with the account defaults, as the
+// account_defaults are checked at startup. The errors are named after the account_defaults keys.
+func (a *Account) Validate(errs []error) []error {
+	errs = a.PriceFloors.validate(errs)
+	errs = a.TrafficShaping.validate(errs)
+	errs = a.AuctionCapture.validate(errs)
+	errs = a.Privacy.IPv6Config.Validate(errs)
+	errs = a.Privacy.IPv4Config.Validate(errs)
+	errs = a.Privacy.USNat.validate(errs)
+	return errs
+}
+
 func (pf *AccountPriceFloors) IsAdjustForBidAdjustmentEnabled() bool {
 	return pf.AdjustForBidAdjustment
 }
@@ -340,6 +410,28 @@ type AccountPrivacy struct {
 	IPv6Config      IPv6             `mapstructure:"ipv6" json:"ipv6"`
 	IPv4Config      IPv4             `mapstructure:"ipv4" json:"ipv4"`
 	PrivacySandbox  PrivacySandbox   `mapstructure:"privacysandbox" json:"privacysandbox"`
+	USNat           AccountUSNat     `mapstructure:"usnat" json:"usnat"`
+}
+
+// AccountUSNat configures the enforcement of the GPP US National and state sections. When enabled, the sections of
+// the request listed in the GPP SID decide the user sync, user FPD, precise geo and unique request ID activities
+// which no account activity rule matched.
+type AccountUSNat struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// Sections limits the enforcement to the listed GPP section IDs. All the US sections are enforced if empty.
+	Sections []int `mapstructure:"sections" json:"sections"`
+	// EnforceGPC treats the Global Privacy Control signal of a section as an opt out.
+	EnforceGPC bool `mapstructure:"enforce_gpc" json:"enforce_gpc"`
+}
+
+func (un *AccountUSNat) validate(errs []error) []error {
+	for _, section := range un.Sections {
+		// the US National section is 7 and the state sections follow it up to Connecticut
+		if section < 7 || section > 12 {
+			errs = append(errs, fmt.Errorf(`account_defaults.privacy.usnat.sections must only list GPP US sections 7 to 12, found %d`, section))
+		}
+	}
+	return errs
 }
 
 type PrivacySandbox struct {
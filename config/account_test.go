@@ -969,6 +969,91 @@ func TestAccountPriceFloorsValidate(t *testing.T) {
 	}
 }
 
+func TestAccountTrafficShapingValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		ts          *AccountTrafficShaping
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			ts: &AccountTrafficShaping{
+				MinRequests:      100,
+				BidRateThreshold: 0.01,
+				ExplorationRate:  0.1,
+			},
+		},
+		{
+			description: "Invalid min_requests",
+			ts: &AccountTrafficShaping{
+				MinRequests:      -1,
+				BidRateThreshold: 0.01,
+				ExplorationRate:  0.1,
+			},
+			want: []error{errors.New("account_defaults.traffic_shaping.min_requests should be greater than or equal to 0")},
+		},
+		{
+			description: "Invalid bid_rate_threshold",
+			ts: &AccountTrafficShaping{
+				MinRequests:      100,
+				BidRateThreshold: 1.5,
+				ExplorationRate:  0.1,
+			},
+			want: []error{errors.New("account_defaults.traffic_shaping.bid_rate_threshold should be between 0 and 1")},
+		},
+		{
+			description: "Invalid exploration_rate",
+			ts: &AccountTrafficShaping{
+				MinRequests:      100,
+				BidRateThreshold: 0.01,
+				ExplorationRate:  -0.1,
+			},
+			want: []error{errors.New("account_defaults.traffic_shaping.exploration_rate should be between 0 and 1")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.ts.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountValidate(t *testing.T) {
+	validAccount := func() *Account {
+		return &Account{
+			PriceFloors: AccountPriceFloors{
+				EnforceFloorsRate: 100,
+				MaxRule:           100,
+				MaxSchemaDims:     5,
+				Fetcher:           AccountFloorFetch{Timeout: 100, MaxAge: 600, Period: 300},
+			},
+			Privacy: AccountPrivacy{
+				IPv6Config: IPv6{AnonKeepBits: 56},
+				IPv4Config: IPv4{AnonKeepBits: 24},
+			},
+		}
+	}
+
+	assert.Empty(t, validAccount().Validate(nil))
+
+	account := validAccount()
+	account.PriceFloors.EnforceFloorsRate = 101
+	account.TrafficShaping.ExplorationRate = 2
+	account.AuctionCapture.SampleRate = -0.5
+	account.Privacy.IPv4Config.AnonKeepBits = 33
+	account.Privacy.USNat.Sections = []int{7, 2}
+	expected := []error{
+		errors.New("account_defaults.price_floors.enforce_floors_rate should be between 0 and 100"),
+		errors.New("account_defaults.traffic_shaping.exploration_rate should be between 0 and 1"),
+		errors.New("account_defaults.auction_capture.sample_rate should be between 0 and 1"),
+		errors.New("bits cannot exceed 32 in ipv4 address, or be less than 0"),
+		errors.New("account_defaults.privacy.usnat.sections must only list GPP US sections 7 to 12, found 2"),
+	}
+	assert.Equal(t, expected, account.Validate(nil))
+}
+
 func TestIPMaskingValidate(t *testing.T) {
 	tests := []struct {
 		name    string
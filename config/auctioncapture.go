@@ -0,0 +1,36 @@
+package config
+
+import "fmt"
+
+// AuctionCapture configures the capture of sampled auctions, with the calls made to bidders, so that they can
+// be replayed offline by cmd/auction-replay. Accounts opt in, and choose a sample rate, in their own
+// auction_capture settings.
+//
+// Captures hold the requests as they reached the exchange, so they may contain personal data. They're only
+// taken where the host may store it, and are deleted once they're older than RetentionHours.
+type AuctionCapture struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Directory is where the captures are written, one JSON file per auction.
+	Directory string `mapstructure:"directory"`
+	// MaxPerMinute caps the number of auctions captured each minute, across all accounts.
+	MaxPerMinute int `mapstructure:"max_per_minute"`
+	// RetentionHours is how long the captures are kept. Older captures are deleted from the directory as new
+	// ones are saved, so they can outlive it while no auction is captured.
+	RetentionHours int `mapstructure:"retention_hours"`
+}
+
+func (cfg *AuctionCapture) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Directory == "" {
+		errs = append(errs, fmt.Errorf("auction_capture.directory must be set when auction_capture is enabled"))
+	}
+	if cfg.MaxPerMinute <= 0 {
+		errs = append(errs, fmt.Errorf("auction_capture.max_per_minute must be > 0. Got %d", cfg.MaxPerMinute))
+	}
+	if cfg.RetentionHours <= 0 {
+		errs = append(errs, fmt.Errorf("auction_capture.retention_hours must be > 0. Got %d", cfg.RetentionHours))
+	}
+	return errs
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuctionCaptureValidate(t *testing.T) {
+	testCases := []struct {
+		description  string
+		cfg          AuctionCapture
+		expectedErrs []error
+	}{
+		{
+			description: "valid",
+			cfg:         AuctionCapture{Enabled: true, Directory: "/var/captures", MaxPerMinute: 60, RetentionHours: 72},
+		},
+		{
+			description: "disabled-not-validated",
+			cfg:         AuctionCapture{Enabled: false},
+		},
+		{
+			description: "all-invalid",
+			cfg:         AuctionCapture{Enabled: true, MaxPerMinute: -1},
+			expectedErrs: []error{
+				errors.New("auction_capture.directory must be set when auction_capture is enabled"),
+				errors.New("auction_capture.max_per_minute must be > 0. Got -1"),
+				errors.New("auction_capture.retention_hours must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.cfg.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
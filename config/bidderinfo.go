@@ -13,6 +13,7 @@ import (
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 
 	validator "github.com/asaskevich/govalidator"
+	"golang.org/x/text/currency"
 	"gopkg.in/yaml.v3"
 )
 
@@ -45,6 +46,9 @@ type BidderInfo struct {
 	// EndpointCompression determines, if set, the type of compression the bid request will undergo before being sent to the corresponding bid server
 	EndpointCompression string       `yaml:"endpointCompression" mapstructure:"endpointCompression"`
 	OpenRTB             *OpenRTBInfo `yaml:"openrtb" mapstructure:"openrtb"`
+	// FloorCurrency, if set, is the currency the bidder expects imp.bidfloor in. Floors in other currencies
+	// are converted to it before the request is sent.
+	FloorCurrency string `yaml:"floorCurrency" mapstructure:"floorCurrency"`
 }
 
 type aliasNillableFields struct {
@@ -338,6 +342,9 @@ func processBidderAliases(aliasNillableFieldsByBidder map[string]aliasNillableFi
 		if aliasBidderInfo.ExtraAdapterInfo == "" {
 			aliasBidderInfo.ExtraAdapterInfo = parentBidderInfo.ExtraAdapterInfo
 		}
+		if aliasBidderInfo.FloorCurrency == "" {
+			aliasBidderInfo.FloorCurrency = parentBidderInfo.FloorCurrency
+		}
 		if aliasBidderInfo.GVLVendorID == 0 {
 			aliasBidderInfo.GVLVendorID = parentBidderInfo.GVLVendorID
 		}
@@ -467,6 +474,9 @@ func validateInfo(bidder BidderInfo, infos BidderInfos, bidderName string) error
 	if err := validateCapabilities(bidder.Capabilities, bidderName); err != nil {
 		return err
 	}
+	if err := validateFloorCurrency(bidder.FloorCurrency, bidderName); err != nil {
+		return err
+	}
 	if len(bidder.AliasOf) > 0 {
 		if err := validateAliasCapabilities(bidder, infos, bidderName); err != nil {
 			return err
@@ -475,6 +485,16 @@ func validateInfo(bidder BidderInfo, infos BidderInfos, bidderName string) error
 	return nil
 }
 
+func validateFloorCurrency(floorCurrency string, bidderName string) error {
+	if floorCurrency == "" {
+		return nil
+	}
+	if _, err := currency.ParseISO(floorCurrency); err != nil {
+		return fmt.Errorf("invalid floorCurrency %s for adapter: %s, %v", floorCurrency, bidderName, err)
+	}
+	return nil
+}
+
 func validateMaintainer(info *MaintainerInfo, bidderName string) error {
 	if info == nil || info.Email == "" {
 		return fmt.Errorf("missing required field: maintainer.email for adapter: %s", bidderName)
@@ -661,6 +681,9 @@ func applyBidderInfoConfigOverrides(configBidderInfos nillableFieldBidderInfos,
 		if configBidderInfo.bidderInfo.OpenRTB != nil {
 			mergedBidderInfo.OpenRTB = configBidderInfo.bidderInfo.OpenRTB
 		}
+		if configBidderInfo.bidderInfo.FloorCurrency != "" {
+			mergedBidderInfo.FloorCurrency = configBidderInfo.bidderInfo.FloorCurrency
+		}
 
 		mergedBidderInfos[string(normalizedBidderName)] = mergedBidderInfo
 	}
@@ -307,6 +307,7 @@ func TestProcessAliasBidderInfo(t *testing.T) {
 			},
 		},
 		ExtraAdapterInfo: "extra-info",
+		FloorCurrency:    "EUR",
 		GVLVendorID:      42,
 		Maintainer: &MaintainerInfo{
 			Email: "some-email@domain.com",
@@ -354,6 +355,7 @@ func TestProcessAliasBidderInfo(t *testing.T) {
 			},
 		},
 		ExtraAdapterInfo: "alias-extra-info",
+		FloorCurrency:    "USD",
 		GVLVendorID:      43,
 		Maintainer: &MaintainerInfo{
 			Email: "alias-email@domain.com",
@@ -724,6 +726,28 @@ func TestBidderInfoValidationNegative(t *testing.T) {
 		bidderInfos  BidderInfos
 		expectErrors []error
 	}{
+		{
+			"One bidder invalid floor currency",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					FloorCurrency: "dollars",
+				},
+			},
+			[]error{
+				errors.New("invalid floorCurrency dollars for adapter: bidderA, currency: tag is not well-formed"),
+			},
+		},
 		{
 			"One bidder incorrect url",
 			BidderInfos{
@@ -1791,6 +1815,18 @@ func TestApplyBidderInfoConfigOverrides(t *testing.T) {
 			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{EndpointCompression: "LZ77", Syncer: &Syncer{Key: "override"}}}},
 			expectedBidderInfos:    BidderInfos{"a": {EndpointCompression: "LZ77", Syncer: &Syncer{Key: "override"}}},
 		},
+		{
+			description:            "Don't override FloorCurrency",
+			givenFsBidderInfos:     BidderInfos{"a": {FloorCurrency: "EUR"}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {FloorCurrency: "EUR", Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override FloorCurrency",
+			givenFsBidderInfos:     BidderInfos{"a": {FloorCurrency: "EUR"}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{FloorCurrency: "USD", Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {FloorCurrency: "USD", Syncer: &Syncer{Key: "override"}}},
+		},
 		{
 			description:            "Don't override Disabled",
 			givenFsBidderInfos:     BidderInfos{"a": {Disabled: true}},
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// CircuitBreaker configures the circuit breakers which stop PBS from calling bidders that keep failing.
+//
+// Every bidder gets its own breaker (or one per endpoint host, if PerHost is set). A breaker opens once
+// the ratio of failed calls over the sliding window reaches ErrorRatio, where timeouts, connection errors
+// and 5xx responses count as failures. While open, calls to the bidder are skipped and its imps are
+// reported as seat non bids. After OpenDuration the breaker lets HalfOpenProbes calls through, and closes
+// again if all of them succeed.
+type CircuitBreaker struct {
+	Enabled bool `mapstructure:"enabled"`
+	// PerHost keeps a separate breaker for each endpoint host a bidder calls, so that an outage of one
+	// regional endpoint doesn't block the others. Note that adapters which build the host from request
+	// parameters will get a breaker for every distinct host.
+	PerHost bool `mapstructure:"per_host"`
+	// WindowSeconds is the length of the sliding window the error ratio is computed over.
+	WindowSeconds int `mapstructure:"window_seconds"`
+	// MinRequests is the number of calls which must be made within the window before the breaker may open.
+	MinRequests int `mapstructure:"min_requests"`
+	// ErrorRatio is the ratio of failed calls within the window, between 0 and 1, which opens the breaker.
+	ErrorRatio float64 `mapstructure:"error_ratio"`
+	// OpenDuration is the number of milliseconds the breaker stays open before it lets probe calls through.
+	OpenDuration int `mapstructure:"open_duration_ms"`
+	// HalfOpenProbes is the number of successful probe calls needed to close the breaker again.
+	HalfOpenProbes int `mapstructure:"half_open_probes"`
+}
+
+// Window returns the length of the sliding window as a time.Duration.
+func (cfg *CircuitBreaker) Window() time.Duration {
+	return time.Duration(cfg.WindowSeconds) * time.Second
+}
+
+// OpenDurationTime returns how long the breaker stays open as a time.Duration.
+func (cfg *CircuitBreaker) OpenDurationTime() time.Duration {
+	return time.Duration(cfg.OpenDuration) * time.Millisecond
+}
+
+func (cfg *CircuitBreaker) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.WindowSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("circuit_breaker.window_seconds must be > 0. Got %d", cfg.WindowSeconds))
+	}
+	if cfg.MinRequests <= 0 {
+		errs = append(errs, fmt.Errorf("circuit_breaker.min_requests must be > 0. Got %d", cfg.MinRequests))
+	}
+	if cfg.ErrorRatio <= 0 || cfg.ErrorRatio > 1 {
+		errs = append(errs, fmt.Errorf("circuit_breaker.error_ratio must be > 0 and <= 1. Got %f", cfg.ErrorRatio))
+	}
+	if cfg.OpenDuration <= 0 {
+		errs = append(errs, fmt.Errorf("circuit_breaker.open_duration_ms must be > 0. Got %d", cfg.OpenDuration))
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		errs = append(errs, fmt.Errorf("circuit_breaker.half_open_probes must be > 0. Got %d", cfg.HalfOpenProbes))
+	}
+	return errs
+}
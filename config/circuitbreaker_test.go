@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakerValidate(t *testing.T) {
+	validCfg := CircuitBreaker{
+		Enabled:        true,
+		WindowSeconds:  60,
+		MinRequests:    20,
+		ErrorRatio:     0.5,
+		OpenDuration:   30000,
+		HalfOpenProbes: 3,
+	}
+
+	testCases := []struct {
+		description  string
+		cfg          func(CircuitBreaker) CircuitBreaker
+		expectedErrs []error
+	}{
+		{
+			description: "valid",
+			cfg:         func(cfg CircuitBreaker) CircuitBreaker { return cfg },
+		},
+		{
+			description: "disabled-not-validated",
+			cfg:         func(cfg CircuitBreaker) CircuitBreaker { return CircuitBreaker{Enabled: false} },
+		},
+		{
+			description: "ratio-of-one",
+			cfg: func(cfg CircuitBreaker) CircuitBreaker {
+				cfg.ErrorRatio = 1
+				return cfg
+			},
+		},
+		{
+			description: "all-invalid",
+			cfg:         func(cfg CircuitBreaker) CircuitBreaker { return CircuitBreaker{Enabled: true, ErrorRatio: 1.5} },
+			expectedErrs: []error{
+				errors.New("circuit_breaker.window_seconds must be > 0. Got 0"),
+				errors.New("circuit_breaker.min_requests must be > 0. Got 0"),
+				errors.New("circuit_breaker.error_ratio must be > 0 and <= 1. Got 1.500000"),
+				errors.New("circuit_breaker.open_duration_ms must be > 0. Got 0"),
+				errors.New("circuit_breaker.half_open_probes must be > 0. Got 0"),
+			},
+		},
+		{
+			description: "zero-ratio",
+			cfg: func(cfg CircuitBreaker) CircuitBreaker {
+				cfg.ErrorRatio = 0
+				return cfg
+			},
+			expectedErrs: []error{errors.New("circuit_breaker.error_ratio must be > 0 and <= 1. Got 0.000000")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := test.cfg(validCfg)
+			errs := cfg.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
@@ -29,6 +29,8 @@ type Configuration struct {
 	CacheClient      HTTPClient  `mapstructure:"http_client_cache"`
 	Admin            Admin       `mapstructure:"admin"`
 	AdminPort        int         `mapstructure:"admin_port"`
+	GRPC             GRPC        `mapstructure:"grpc"`
+	GRPCPort         int         `mapstructure:"grpc_port"`
 	Compression      Compression `mapstructure:"compression"`
 	// GarbageCollectorThreshold allocates virtual memory (in bytes) which is not used by PBS but
 	// serves as a hack to trigger the garbage collector only when the heap reaches at least this size.
@@ -39,6 +41,7 @@ type Configuration struct {
 	StatusResponse    string          `mapstructure:"status_response"`
 	AuctionTimeouts   AuctionTimeouts `mapstructure:"auction_timeouts_ms"`
 	TmaxAdjustments   TmaxAdjustments `mapstructure:"tmax_adjustments"`
+	CircuitBreaker    CircuitBreaker  `mapstructure:"circuit_breaker"`
 	CacheURL          Cache           `mapstructure:"cache"`
 	ExtCacheURL       ExternalCache   `mapstructure:"external_cache"`
 	RecaptchaSecret   string          `mapstructure:"recaptcha_secret"`
@@ -103,14 +106,64 @@ type Configuration struct {
 	Hooks       Hooks       `mapstructure:"hooks"`
 	Validations Validations `mapstructure:"validations"`
 	PriceFloors PriceFloors `mapstructure:"price_floors"`
+	// TrafficShaping configures the model used to skip calls to bidders which rarely bid on similar traffic
+	TrafficShaping TrafficShaping `mapstructure:"traffic_shaping"`
+	// AuctionCapture configures the sampling of auctions to a local store, for replay with cmd/auction-replay
+	AuctionCapture AuctionCapture `mapstructure:"auction_capture"`
+	// VASTUnwrap configures the server-side resolution and validation of the VAST in video bids
+	VASTUnwrap VASTUnwrap `mapstructure:"vast_unwrap"`
+	// UIDStore configures the server-side storage of bidder UIDs, keyed by a first-party ID
+	UIDStore UIDStore `mapstructure:"uid_store"`
+
+	// live holds the configuration in effect once reloads are enabled, see EnableReload
+	live *liveConfiguration
 }
 
 type Admin struct {
+	Enabled    bool            `mapstructure:"enabled"`
+	StoredData AdminStoredData `mapstructure:"stored_data"`
+}
+
+// AdminStoredData configures the admin API which manages stored requests, imps and accounts
+type AdminStoredData struct {
 	Enabled bool `mapstructure:"enabled"`
+	// AuditLogFile is a file to append a JSON line to for every change. Changes are logged with glog if it's empty.
+	// The admin port isn't authenticated, so the user recorded for a change is the one the caller claims to be.
+	AuditLogFile string `mapstructure:"audit_log_file"`
+}
+
+func (cfg *Admin) validate(errs []error) []error {
+	if cfg.StoredData.Enabled && !cfg.Enabled {
+		errs = append(errs, errors.New("admin.stored_data.enabled requires admin.enabled to be true"))
+	}
+	return errs
 }
+
+// GRPC configures the gRPC server which accepts protobuf auction requests alongside /openrtb2/auction
+type GRPC struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
 type PriceFloors struct {
-	Enabled bool              `mapstructure:"enabled"`
-	Fetcher PriceFloorFetcher `mapstructure:"fetcher"`
+	Enabled   bool                 `mapstructure:"enabled"`
+	Fetcher   PriceFloorFetcher    `mapstructure:"fetcher"`
+	Reporting PriceFloorsReporting `mapstructure:"reporting"`
+}
+
+// PriceFloorsReporting configures the aggregation of requests, floor rejections, wins and revenue per account
+// and floors model version, so that floor models can be A/B tested.
+type PriceFloorsReporting struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxModels caps the number of account and model version combinations tracked in memory. Once it's reached,
+	// auctions for combinations which aren't tracked yet are only counted in the metrics, under the "other" model version.
+	MaxModels int `mapstructure:"max_models"`
+}
+
+func (cfg *PriceFloorsReporting) validate(errs []error) []error {
+	if cfg.Enabled && cfg.MaxModels <= 0 {
+		errs = append(errs, fmt.Errorf("price_floors.reporting.max_models must be > 0. Got %d", cfg.MaxModels))
+	}
+	return errs
 }
 
 type PriceFloorFetcher struct {
@@ -142,14 +195,26 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.CategoryMapping.validate(errs)
 	errs = cfg.StoredVideo.validate(errs)
 	errs = cfg.Metrics.validate(errs)
+	errs = cfg.Admin.validate(errs)
 	if cfg.MaxRequestSize < 0 {
 		errs = append(errs, fmt.Errorf("cfg.max_request_size must be >= 0. Got %d", cfg.MaxRequestSize))
 	}
 	errs = cfg.GDPR.validate(v, errs)
 	errs = cfg.CurrencyConverter.validate(errs)
 	errs = cfg.Debug.validate(errs)
+	errs = cfg.CircuitBreaker.validate(errs)
 	errs = cfg.ExtCacheURL.validate(errs)
 	errs = cfg.AccountDefaults.PriceFloors.validate(errs)
+	errs = cfg.PriceFloors.Reporting.validate(errs)
+	errs = cfg.Analytics.EventLog.validate(errs)
+	errs = cfg.AccountDefaults.TrafficShaping.validate(errs)
+	errs = cfg.TrafficShaping.validate(errs)
+	errs = cfg.AccountDefaults.AuctionCapture.validate(errs)
+	errs = cfg.AuctionCapture.validate(errs)
+	errs = cfg.VASTUnwrap.validate(errs)
+	errs = cfg.UIDStore.validate(errs)
+	errs = cfg.HostCookie.Encryption.validate(errs)
+	errs = cfg.UserSync.ValueRanking.validate(errs)
 	if cfg.AccountDefaults.Disabled {
 		glog.Warning(`With account_defaults.disabled=true, host-defined accounts must exist and have "disabled":false. All other requests will be rejected.`)
 	}
@@ -162,6 +227,7 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.BidderInfos.validate(errs)
 	errs = cfg.AccountDefaults.Privacy.IPv6Config.Validate(errs)
 	errs = cfg.AccountDefaults.Privacy.IPv4Config.Validate(errs)
+	errs = cfg.AccountDefaults.Privacy.USNat.validate(errs)
 
 	return errs
 }
@@ -255,6 +321,19 @@ type GDPR struct {
 	// to DefaultValue
 	EEACountries    []string `mapstructure:"eea_countries"`
 	EEACountriesMap map[string]struct{}
+	VendorLists     GDPRVendorLists `mapstructure:"vendor_lists"`
+}
+
+// GDPRVendorLists configures where the Global Vendor Lists are loaded from besides the network, so that
+// enforcement doesn't depend on the vendor list host being reachable at startup.
+type GDPRVendorLists struct {
+	// Dir persists the fetched vendor lists. The lists in it are loaded at startup, before any fetch.
+	Dir string `mapstructure:"dir"`
+	// Snapshot loads the vendor lists bundled in the binary which couldn't be loaded from Dir or fetched.
+	Snapshot bool `mapstructure:"snapshot"`
+	// FailClosed denies every auction activity of a bidder when the vendor list version of the consent string
+	// isn't loaded, instead of falling back to the default permissions.
+	FailClosed bool `mapstructure:"fail_closed"`
 }
 
 func (cfg *GDPR) validate(v *viper.Viper, errs []error) []error {
@@ -454,6 +533,7 @@ type Analytics struct {
 	File     FileLogs      `mapstructure:"file"`
 	Agma     AgmaAnalytics `mapstructure:"agma"`
 	Pubstack Pubstack      `mapstructure:"pubstack"`
+	EventLog EventLog      `mapstructure:"eventlog"`
 }
 
 type CurrencyConverter struct {
@@ -513,6 +593,82 @@ type PubstackBuffer struct {
 	Timeout    string `mapstructure:"timeout"`
 }
 
+const (
+	EventLogSinkFile  = "file"
+	EventLogSinkKafka = "kafka"
+)
+
+// EventLog configures the eventlog analytics module, which writes a versioned record of every
+// transaction to rotated JSONL files or to a Kafka topic.
+type EventLog struct {
+	Enabled bool `mapstructure:"enabled"`
+	// BufferSize is the number of events which may wait to be written before new ones are dropped.
+	BufferSize int `mapstructure:"buffer_size"`
+	// Sink is where the records are written, either "file" or "kafka".
+	Sink  string        `mapstructure:"sink"`
+	File  EventLogFile  `mapstructure:"file"`
+	Kafka EventLogKafka `mapstructure:"kafka"`
+}
+
+func (cfg *EventLog) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.BufferSize <= 0 {
+		errs = append(errs, fmt.Errorf("analytics.eventlog.buffer_size must be > 0. Got %d", cfg.BufferSize))
+	}
+	switch cfg.Sink {
+	case EventLogSinkFile:
+		if cfg.File.Directory == "" {
+			errs = append(errs, errors.New("analytics.eventlog.file.directory is required when analytics.eventlog.sink is file"))
+		}
+	case EventLogSinkKafka:
+		errs = cfg.Kafka.validate(errs)
+	default:
+		errs = append(errs, fmt.Errorf("analytics.eventlog.sink must be one of [%s, %s]. Got %s", EventLogSinkFile, EventLogSinkKafka, cfg.Sink))
+	}
+	return errs
+}
+
+type EventLogFile struct {
+	Directory string `mapstructure:"directory"`
+	Prefix    string `mapstructure:"prefix"`
+	// MaxSize is the size at which a file is rotated, as a human readable size like "100MB".
+	MaxSize string `mapstructure:"max_size"`
+	// RotationInterval is the age at which a file is rotated, as a Go duration like "1h".
+	RotationInterval string `mapstructure:"rotation_interval"`
+	Compress         bool   `mapstructure:"compress"`
+}
+
+type EventLogKafka struct {
+	// Brokers are the host:port addresses the producer bootstraps from.
+	Brokers []string         `mapstructure:"brokers"`
+	Topic   string           `mapstructure:"topic"`
+	TLS     EventLogKafkaTLS `mapstructure:"tls"`
+}
+
+func (cfg *EventLogKafka) validate(errs []error) []error {
+	if len(cfg.Brokers) == 0 {
+		errs = append(errs, errors.New("analytics.eventlog.kafka.brokers must not be empty when analytics.eventlog.sink is kafka"))
+	}
+	if cfg.Topic == "" {
+		errs = append(errs, errors.New("analytics.eventlog.kafka.topic is required when analytics.eventlog.sink is kafka"))
+	}
+	if (cfg.TLS.CertFile == "") != (cfg.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("analytics.eventlog.kafka.tls.cert_file and analytics.eventlog.kafka.tls.key_file must be set together"))
+	}
+	return errs
+}
+
+// EventLogKafkaTLS configures TLS for the connections to the brokers. The system roots are trusted when
+// CAFile is empty, and a client certificate is only presented when CertFile and KeyFile are set.
+type EventLogKafkaTLS struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	CAFile   string `mapstructure:"ca_file"`
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+}
+
 type VTrack struct {
 	TimeoutMS          int64 `mapstructure:"timeout_ms"`
 	AllowUnknownBidder bool  `mapstructure:"allow_unknown_bidder"`
@@ -532,7 +688,8 @@ type HostCookie struct {
 	MaxCookieSizeBytes int    `mapstructure:"max_cookie_size_bytes"`
 	OptOutCookie       Cookie `mapstructure:"optout_cookie"`
 	// Cookie timeout in days
-	TTL int64 `mapstructure:"ttl_days"`
+	TTL        int64            `mapstructure:"ttl_days"`
+	Encryption CookieEncryption `mapstructure:"encryption"`
 }
 
 func (cfg *HostCookie) TTLDuration() time.Duration {
@@ -692,6 +849,9 @@ type Validations struct {
 	SecureMarkup          string `mapstructure:"secure_markup" json:"secure_markup"`
 	MaxCreativeWidth      int64  `mapstructure:"max_creative_width" json:"max_creative_width"`
 	MaxCreativeHeight     int64  `mapstructure:"max_creative_height" json:"max_creative_height"`
+	// BidConformance checks that bids match the media types, sizes and deals of their imp, and the media
+	// types the bidder declares in its capabilities.
+	BidConformance string `mapstructure:"bid_conformance" json:"bid_conformance"`
 }
 
 const (
@@ -706,6 +866,14 @@ func (host *Validations) SetBannerCreativeMaxSize(account Validations) {
 	}
 }
 
+// BidConformanceMode returns the bid conformance mode of the account, or the host's if the account doesn't set one.
+func (host Validations) BidConformanceMode(account Validations) string {
+	if len(account.BidConformance) > 0 {
+		return account.BidConformance
+	}
+	return host.BidConformance
+}
+
 func (cfg *TimeoutNotification) validate(errs []error) []error {
 	if cfg.SamplingRate < 0.0 || cfg.SamplingRate > 1.0 {
 		errs = append(errs, fmt.Errorf("debug.timeout_notification.sampling_rate must be positive and not greater than 1.0. Got %f", cfg.SamplingRate))
@@ -909,6 +1077,10 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("unix_socket_name", "prebid-server.sock") // path of the socket's file which must be listened.
 	v.SetDefault("admin_port", 6060)
 	v.SetDefault("admin.enabled", true) // boolean to determine if admin listener will be started.
+	v.SetDefault("admin.stored_data.enabled", false)
+	v.SetDefault("admin.stored_data.audit_log_file", "")
+	v.SetDefault("grpc_port", 8001)
+	v.SetDefault("grpc.enabled", false) // boolean to determine if the gRPC auction listener will be started.
 	v.SetDefault("garbage_collector_threshold", 0)
 	v.SetDefault("status_response", "")
 	v.SetDefault("datacenter", "")
@@ -935,9 +1107,13 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("host_cookie.value", "")
 	v.SetDefault("host_cookie.ttl_days", 90)
 	v.SetDefault("host_cookie.max_cookie_size_bytes", 0)
+	v.SetDefault("host_cookie.encryption.enabled", false)
+	v.SetDefault("host_cookie.encryption.active_key_id", "")
+	v.SetDefault("host_cookie.encryption.allow_legacy", true)
 	v.SetDefault("host_schain_node", nil)
 	v.SetDefault("validations.banner_creative_max_size", ValidationSkip)
 	v.SetDefault("validations.secure_markup", ValidationSkip)
+	v.SetDefault("validations.bid_conformance", ValidationSkip)
 	v.SetDefault("validations.max_creative_size.height", 0)
 	v.SetDefault("validations.max_creative_size.width", 0)
 	v.SetDefault("http_client.max_connections_per_host", 0) // unlimited
@@ -969,6 +1145,14 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("category_mapping.filesystem.enabled", true)
 	v.SetDefault("category_mapping.filesystem.directorypath", "./static/category-mapping")
 	v.SetDefault("category_mapping.http.endpoint", "")
+	v.SetDefault("category_mapping.redis.enabled", false)
+	v.SetDefault("category_mapping.redis.address", "")
+	v.SetDefault("category_mapping.redis.username", "")
+	v.SetDefault("category_mapping.redis.password", "")
+	v.SetDefault("category_mapping.redis.db", 0)
+	v.SetDefault("category_mapping.redis.timeout_ms", 100)
+	v.SetDefault("category_mapping.redis.key_prefixes.categories", "categories:")
+	v.SetDefault("category_mapping.redis.events.enabled", false)
 	v.SetDefault("stored_requests_timeout_ms", 50)
 	v.SetDefault("stored_requests.database.connection.driver", "")
 	v.SetDefault("stored_requests.database.connection.dbname", "")
@@ -989,6 +1173,9 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_requests.database.poll_for_updates.timeout_ms", 0)
 	v.SetDefault("stored_requests.database.poll_for_updates.query", "")
 	v.SetDefault("stored_requests.database.poll_for_updates.amp_query", "")
+	v.SetDefault("stored_requests.database.writer.list_query", "")
+	v.SetDefault("stored_requests.database.writer.upsert_query", "")
+	v.SetDefault("stored_requests.database.writer.delete_query", "")
 	v.SetDefault("stored_requests.filesystem.enabled", false)
 	v.SetDefault("stored_requests.filesystem.directorypath", "./stored_requests/data/by_id")
 	v.SetDefault("stored_requests.directorypath", "./stored_requests/data/by_id")
@@ -1004,6 +1191,17 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_requests.http_events.amp_endpoint", "")
 	v.SetDefault("stored_requests.http_events.refresh_rate_seconds", 0)
 	v.SetDefault("stored_requests.http_events.timeout_ms", 0)
+	v.SetDefault("stored_requests.redis.enabled", false)
+	v.SetDefault("stored_requests.redis.address", "")
+	v.SetDefault("stored_requests.redis.username", "")
+	v.SetDefault("stored_requests.redis.password", "")
+	v.SetDefault("stored_requests.redis.db", 0)
+	v.SetDefault("stored_requests.redis.timeout_ms", 100)
+	v.SetDefault("stored_requests.redis.key_prefixes.requests", "stored_requests:")
+	v.SetDefault("stored_requests.redis.key_prefixes.amp_requests", "amp_stored_requests:")
+	v.SetDefault("stored_requests.redis.key_prefixes.imps", "stored_imps:")
+	v.SetDefault("stored_requests.redis.key_prefixes.responses", "stored_responses:")
+	v.SetDefault("stored_requests.redis.events.enabled", false)
 	// stored_video is short for stored_video_requests.
 	// PBS is not in the business of storing video content beyond the normal prebid cache system.
 	v.SetDefault("stored_video_req.database.connection.driver", "")
@@ -1038,6 +1236,15 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_video_req.http_events.endpoint", "")
 	v.SetDefault("stored_video_req.http_events.refresh_rate_seconds", 0)
 	v.SetDefault("stored_video_req.http_events.timeout_ms", 0)
+	v.SetDefault("stored_video_req.redis.enabled", false)
+	v.SetDefault("stored_video_req.redis.address", "")
+	v.SetDefault("stored_video_req.redis.username", "")
+	v.SetDefault("stored_video_req.redis.password", "")
+	v.SetDefault("stored_video_req.redis.db", 0)
+	v.SetDefault("stored_video_req.redis.timeout_ms", 100)
+	v.SetDefault("stored_video_req.redis.key_prefixes.requests", "stored_video_requests:")
+	v.SetDefault("stored_video_req.redis.key_prefixes.imps", "stored_video_imps:")
+	v.SetDefault("stored_video_req.redis.events.enabled", false)
 	v.SetDefault("stored_responses.database.connection.driver", "")
 	v.SetDefault("stored_responses.database.connection.dbname", "")
 	v.SetDefault("stored_responses.database.connection.host", "")
@@ -1070,6 +1277,14 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_responses.http_events.endpoint", "")
 	v.SetDefault("stored_responses.http_events.refresh_rate_seconds", 0)
 	v.SetDefault("stored_responses.http_events.timeout_ms", 0)
+	v.SetDefault("stored_responses.redis.enabled", false)
+	v.SetDefault("stored_responses.redis.address", "")
+	v.SetDefault("stored_responses.redis.username", "")
+	v.SetDefault("stored_responses.redis.password", "")
+	v.SetDefault("stored_responses.redis.db", 0)
+	v.SetDefault("stored_responses.redis.timeout_ms", 100)
+	v.SetDefault("stored_responses.redis.key_prefixes.responses", "stored_responses:")
+	v.SetDefault("stored_responses.redis.events.enabled", false)
 
 	v.SetDefault("vtrack.timeout_ms", 2000)
 	v.SetDefault("vtrack.allow_unknown_bidder", true)
@@ -1078,10 +1293,25 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("event.timeout_ms", 1000)
 
 	v.SetDefault("user_sync.priority_groups", [][]string{})
+	v.SetDefault("user_sync.value_ranking.enabled", false)
+	v.SetDefault("user_sync.value_ranking.min_auctions", 100)
+	v.SetDefault("user_sync.value_ranking.min_syncs", 20)
+	v.SetDefault("user_sync.value_ranking.window", 10000)
+	v.SetDefault("user_sync.value_ranking.max_accounts", 10000)
+	v.SetDefault("user_sync.value_ranking.stale_uid_age_hours", 0)
+	v.SetDefault("user_sync.value_ranking.deterministic", false)
 
 	v.SetDefault("accounts.filesystem.enabled", false)
 	v.SetDefault("accounts.filesystem.directorypath", "./stored_requests/data/by_id")
 	v.SetDefault("accounts.in_memory_cache.type", "none")
+	v.SetDefault("accounts.redis.enabled", false)
+	v.SetDefault("accounts.redis.address", "")
+	v.SetDefault("accounts.redis.username", "")
+	v.SetDefault("accounts.redis.password", "")
+	v.SetDefault("accounts.redis.db", 0)
+	v.SetDefault("accounts.redis.timeout_ms", 100)
+	v.SetDefault("accounts.redis.key_prefixes.accounts", "accounts:")
+	v.SetDefault("accounts.redis.events.enabled", false)
 
 	v.BindEnv("user_sync.external_url")
 	v.BindEnv("user_sync.coop_sync.default")
@@ -1107,6 +1337,20 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("analytics.agma.buffers.count", 100)
 	v.SetDefault("analytics.agma.buffers.timeout", "15m")
 	v.SetDefault("analytics.agma.accounts", []AgmaAnalyticsAccount{})
+	v.SetDefault("analytics.eventlog.enabled", false)
+	v.SetDefault("analytics.eventlog.buffer_size", 10000)
+	v.SetDefault("analytics.eventlog.sink", "file")
+	v.SetDefault("analytics.eventlog.file.directory", "")
+	v.SetDefault("analytics.eventlog.file.prefix", "pbs-events")
+	v.SetDefault("analytics.eventlog.file.max_size", "100MB")
+	v.SetDefault("analytics.eventlog.file.rotation_interval", "1h")
+	v.SetDefault("analytics.eventlog.file.compress", true)
+	v.SetDefault("analytics.eventlog.kafka.brokers", []string{})
+	v.SetDefault("analytics.eventlog.kafka.topic", "")
+	v.SetDefault("analytics.eventlog.kafka.tls.enabled", false)
+	v.SetDefault("analytics.eventlog.kafka.tls.ca_file", "")
+	v.SetDefault("analytics.eventlog.kafka.tls.cert_file", "")
+	v.SetDefault("analytics.eventlog.kafka.tls.key_file", "")
 	v.SetDefault("amp_timeout_adjustment_ms", 0)
 	v.BindEnv("gdpr.default_value")
 	v.SetDefault("gdpr.enabled", true)
@@ -1136,6 +1380,9 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("gdpr.tcf2.purpose9.vendor_exceptions", []string{})
 	v.SetDefault("gdpr.tcf2.purpose10.vendor_exceptions", []string{})
 	v.SetDefault("gdpr.amp_exception", false)
+	v.SetDefault("gdpr.vendor_lists.dir", "")
+	v.SetDefault("gdpr.vendor_lists.snapshot", false)
+	v.SetDefault("gdpr.vendor_lists.fail_closed", false)
 	v.SetDefault("gdpr.eea_countries", []string{"ALA", "AUT", "BEL", "BGR", "HRV", "CYP", "CZE", "DNK", "EST",
 		"FIN", "FRA", "GUF", "DEU", "GIB", "GRC", "GLP", "GGY", "HUN", "ISL", "IRL", "IMN", "ITA", "JEY", "LVA",
 		"LIE", "LTU", "LUX", "MLT", "MTQ", "MYT", "NLD", "NOR", "POL", "PRT", "REU", "ROU", "BLM", "MAF", "SPM",
@@ -1167,6 +1414,15 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("account_defaults.price_floors.fetch.max_age_sec", 86400)
 	v.SetDefault("account_defaults.price_floors.fetch.period_sec", 3600)
 	v.SetDefault("account_defaults.price_floors.fetch.max_schema_dims", 0)
+	v.SetDefault("account_defaults.traffic_shaping.enabled", false)
+	v.SetDefault("account_defaults.traffic_shaping.min_requests", 100)
+	v.SetDefault("account_defaults.traffic_shaping.bid_rate_threshold", 0.01)
+	v.SetDefault("account_defaults.traffic_shaping.exploration_rate", 0.1)
+	v.SetDefault("account_defaults.auction_capture.enabled", false)
+	v.SetDefault("account_defaults.auction_capture.sample_rate", 0.01)
+	v.SetDefault("account_defaults.vast_unwrap.enabled", false)
+	v.SetDefault("account_defaults.vast_unwrap.enforce", false)
+	v.SetDefault("account_defaults.vast_unwrap.inline", false)
 	v.SetDefault("account_defaults.privacy.privacysandbox.topicsdomain", "")
 	v.SetDefault("account_defaults.privacy.privacysandbox.cookiedeprecation.enabled", false)
 	v.SetDefault("account_defaults.privacy.privacysandbox.cookiedeprecation.ttl_sec", 604800)
@@ -1176,6 +1432,8 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.BindEnv("account_defaults.privacy.dsa.gdpr_only")
 	v.SetDefault("account_defaults.privacy.ipv6.anon_keep_bits", 56)
 	v.SetDefault("account_defaults.privacy.ipv4.anon_keep_bits", 24)
+	v.SetDefault("account_defaults.privacy.usnat.enabled", false)
+	v.SetDefault("account_defaults.privacy.usnat.enforce_gpc", false)
 
 	//Defaults for Price floor fetcher
 	v.SetDefault("price_floors.fetcher.worker", 20)
@@ -1186,6 +1444,8 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("price_floors.fetcher.http_client.max_idle_connections_per_host", 2)
 	v.SetDefault("price_floors.fetcher.http_client.idle_connection_timeout_seconds", 60)
 	v.SetDefault("price_floors.fetcher.max_retries", 10)
+	v.SetDefault("price_floors.reporting.enabled", false)
+	v.SetDefault("price_floors.reporting.max_models", 1000)
 
 	v.SetDefault("account_defaults.events_enabled", false)
 	v.SetDefault("compression.response.enable_gzip", false)
@@ -1209,6 +1469,42 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("tmax_adjustments.bidder_network_latency_buffer_ms", 0)
 	v.SetDefault("tmax_adjustments.pbs_response_preparation_duration_ms", 0)
 
+	v.SetDefault("traffic_shaping.enabled", false)
+	v.SetDefault("traffic_shaping.window_minutes", 60)
+	v.SetDefault("traffic_shaping.max_keys", 100000)
+
+	v.SetDefault("auction_capture.enabled", false)
+	v.SetDefault("auction_capture.directory", "")
+	v.SetDefault("auction_capture.max_per_minute", 60)
+	v.SetDefault("auction_capture.retention_hours", 72)
+
+	v.SetDefault("vast_unwrap.enabled", false)
+	v.SetDefault("vast_unwrap.max_wrapper_depth", 5)
+	v.SetDefault("vast_unwrap.timeout_ms", 200)
+	v.SetDefault("vast_unwrap.max_response_bytes", 1048576)
+	v.SetDefault("vast_unwrap.http_client.max_connections_per_host", 0) // unlimited
+	v.SetDefault("vast_unwrap.http_client.max_idle_connections", 100)
+	v.SetDefault("vast_unwrap.http_client.max_idle_connections_per_host", 10)
+	v.SetDefault("vast_unwrap.http_client.idle_connection_timeout_seconds", 60)
+
+	v.SetDefault("uid_store.enabled", false)
+	v.SetDefault("uid_store.type", "memory")
+	v.SetDefault("uid_store.id_cookie_name", "pbs_fpid")
+	v.SetDefault("uid_store.ttl_seconds", 1209600)
+	v.SetDefault("uid_store.max_users", 1000000)
+	v.SetDefault("uid_store.redis.address", "")
+	v.SetDefault("uid_store.redis.db", 0)
+	v.SetDefault("uid_store.redis.timeout_ms", 50)
+	v.SetDefault("uid_store.redis.key_prefix", "uids:")
+
+	v.SetDefault("circuit_breaker.enabled", false)
+	v.SetDefault("circuit_breaker.per_host", false)
+	v.SetDefault("circuit_breaker.window_seconds", 60)
+	v.SetDefault("circuit_breaker.min_requests", 20)
+	v.SetDefault("circuit_breaker.error_ratio", 0.5)
+	v.SetDefault("circuit_breaker.open_duration_ms", 30000)
+	v.SetDefault("circuit_breaker.half_open_probes", 3)
+
 	/* IPv4
 	/*  Site Local: 10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16
 	/*  Link Local: 169.254.0.0/16
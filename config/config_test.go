@@ -146,10 +146,25 @@ func TestDefaults(t *testing.T) {
 
 	cmpInts(t, "port", 8000, cfg.Port)
 	cmpInts(t, "admin_port", 6060, cfg.AdminPort)
+	cmpInts(t, "grpc_port", 8001, cfg.GRPCPort)
+	cmpBools(t, "grpc.enabled", false, cfg.GRPC.Enabled)
+	cmpBools(t, "admin.stored_data.enabled", false, cfg.Admin.StoredData.Enabled)
+	cmpStrings(t, "admin.stored_data.audit_log_file", "", cfg.Admin.StoredData.AuditLogFile)
+	cmpStrings(t, "stored_requests.database.writer.list_query", "", cfg.StoredRequests.Database.WriterQueries.ListQuery)
 	cmpInts(t, "auction_timeouts_ms.max", 0, int(cfg.AuctionTimeouts.Max))
 	cmpInts(t, "max_request_size", 1024*256, int(cfg.MaxRequestSize))
 	cmpInts(t, "host_cookie.ttl_days", 90, int(cfg.HostCookie.TTL))
 	cmpInts(t, "host_cookie.max_cookie_size_bytes", 0, cfg.HostCookie.MaxCookieSizeBytes)
+	cmpBools(t, "host_cookie.encryption.enabled", false, cfg.HostCookie.Encryption.Enabled)
+	cmpStrings(t, "host_cookie.encryption.active_key_id", "", cfg.HostCookie.Encryption.ActiveKeyID)
+	cmpBools(t, "host_cookie.encryption.allow_legacy", true, cfg.HostCookie.Encryption.AllowLegacy)
+	cmpBools(t, "user_sync.value_ranking.enabled", false, cfg.UserSync.ValueRanking.Enabled)
+	cmpInts(t, "user_sync.value_ranking.min_auctions", 100, cfg.UserSync.ValueRanking.MinAuctions)
+	cmpInts(t, "user_sync.value_ranking.min_syncs", 20, cfg.UserSync.ValueRanking.MinSyncs)
+	cmpInts(t, "user_sync.value_ranking.window", 10000, cfg.UserSync.ValueRanking.Window)
+	cmpInts(t, "user_sync.value_ranking.max_accounts", 10000, cfg.UserSync.ValueRanking.MaxAccounts)
+	cmpInts(t, "user_sync.value_ranking.stale_uid_age_hours", 0, cfg.UserSync.ValueRanking.StaleUIDAgeHours)
+	cmpBools(t, "user_sync.value_ranking.deterministic", false, cfg.UserSync.ValueRanking.Deterministic)
 	cmpInts(t, "currency_converter.fetch_interval_seconds", 1800, cfg.CurrencyConverter.FetchIntervalSeconds)
 	cmpStrings(t, "currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json", cfg.CurrencyConverter.FetchURL)
 	cmpBools(t, "account_required", false, cfg.AccountRequired)
@@ -186,6 +201,12 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "price_floors.fetcher.http_client.max_idle_connections_per_host", 2, cfg.PriceFloors.Fetcher.HttpClient.MaxIdleConnsPerHost)
 	cmpInts(t, "price_floors.fetcher.http_client.idle_connection_timeout_seconds", 60, cfg.PriceFloors.Fetcher.HttpClient.IdleConnTimeout)
 	cmpInts(t, "price_floors.fetcher.max_retries", 10, cfg.PriceFloors.Fetcher.MaxRetries)
+	cmpBools(t, "price_floors.reporting.enabled", false, cfg.PriceFloors.Reporting.Enabled)
+	cmpInts(t, "price_floors.reporting.max_models", 1000, cfg.PriceFloors.Reporting.MaxModels)
+
+	cmpStrings(t, "gdpr.vendor_lists.dir", "", cfg.GDPR.VendorLists.Dir)
+	cmpBools(t, "gdpr.vendor_lists.snapshot", false, cfg.GDPR.VendorLists.Snapshot)
+	cmpBools(t, "gdpr.vendor_lists.fail_closed", false, cfg.GDPR.VendorLists.FailClosed)
 
 	// Assert compression related defaults
 	cmpBools(t, "compression.request.enable_gzip", false, cfg.Compression.Request.GZIP)
@@ -206,6 +227,15 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "account_defaults.price_floors.fetch.period_sec", 3600, cfg.AccountDefaults.PriceFloors.Fetcher.Period)
 	cmpInts(t, "account_defaults.price_floors.fetch.max_age_sec", 86400, cfg.AccountDefaults.PriceFloors.Fetcher.MaxAge)
 	cmpInts(t, "account_defaults.price_floors.fetch.max_schema_dims", 0, cfg.AccountDefaults.PriceFloors.Fetcher.MaxSchemaDims)
+	cmpBools(t, "account_defaults.traffic_shaping.enabled", false, cfg.AccountDefaults.TrafficShaping.Enabled)
+	cmpInts(t, "account_defaults.traffic_shaping.min_requests", 100, cfg.AccountDefaults.TrafficShaping.MinRequests)
+	assert.Equal(t, 0.01, cfg.AccountDefaults.TrafficShaping.BidRateThreshold, "account_defaults.traffic_shaping.bid_rate_threshold")
+	assert.Equal(t, 0.1, cfg.AccountDefaults.TrafficShaping.ExplorationRate, "account_defaults.traffic_shaping.exploration_rate")
+	cmpBools(t, "account_defaults.auction_capture.enabled", false, cfg.AccountDefaults.AuctionCapture.Enabled)
+	assert.Equal(t, 0.01, cfg.AccountDefaults.AuctionCapture.SampleRate, "account_defaults.auction_capture.sample_rate")
+	cmpBools(t, "account_defaults.vast_unwrap.enabled", false, cfg.AccountDefaults.VASTUnwrap.Enabled)
+	cmpBools(t, "account_defaults.vast_unwrap.enforce", false, cfg.AccountDefaults.VASTUnwrap.Enforce)
+	cmpBools(t, "account_defaults.vast_unwrap.inline", false, cfg.AccountDefaults.VASTUnwrap.Inline)
 	cmpStrings(t, "account_defaults.privacy.topicsdomain", "", cfg.AccountDefaults.Privacy.PrivacySandbox.TopicsDomain)
 	cmpBools(t, "account_defaults.privacy.privacysandbox.cookiedeprecation.enabled", false, cfg.AccountDefaults.Privacy.PrivacySandbox.CookieDeprecation.Enabled)
 	cmpInts(t, "account_defaults.privacy.privacysandbox.cookiedeprecation.ttl_sec", 604800, cfg.AccountDefaults.Privacy.PrivacySandbox.CookieDeprecation.TTLSec)
@@ -215,6 +245,7 @@ func TestDefaults(t *testing.T) {
 	cmpBools(t, "hooks.enabled", false, cfg.Hooks.Enabled)
 	cmpStrings(t, "validations.banner_creative_max_size", "skip", cfg.Validations.BannerCreativeMaxSize)
 	cmpStrings(t, "validations.secure_markup", "skip", cfg.Validations.SecureMarkup)
+	cmpStrings(t, "validations.bid_conformance", "skip", cfg.Validations.BidConformance)
 	cmpInts(t, "validations.max_creative_width", 0, int(cfg.Validations.MaxCreativeWidth))
 	cmpInts(t, "validations.max_creative_height", 0, int(cfg.Validations.MaxCreativeHeight))
 	cmpBools(t, "account_modules_metrics", false, cfg.Metrics.Disabled.AccountModulesMetrics)
@@ -224,8 +255,40 @@ func TestDefaults(t *testing.T) {
 	cmpUnsignedInts(t, "tmax_adjustments.bidder_network_latency_buffer_ms", 0, cfg.TmaxAdjustments.BidderNetworkLatencyBuffer)
 	cmpUnsignedInts(t, "tmax_adjustments.pbs_response_preparation_duration_ms", 0, cfg.TmaxAdjustments.PBSResponsePreparationDuration)
 
+	cmpBools(t, "traffic_shaping.enabled", false, cfg.TrafficShaping.Enabled)
+	cmpInts(t, "traffic_shaping.window_minutes", 60, cfg.TrafficShaping.WindowMinutes)
+	cmpInts(t, "traffic_shaping.max_keys", 100000, cfg.TrafficShaping.MaxKeys)
+
+	cmpBools(t, "auction_capture.enabled", false, cfg.AuctionCapture.Enabled)
+	cmpStrings(t, "auction_capture.directory", "", cfg.AuctionCapture.Directory)
+	cmpInts(t, "auction_capture.max_per_minute", 60, cfg.AuctionCapture.MaxPerMinute)
+	cmpInts(t, "auction_capture.retention_hours", 72, cfg.AuctionCapture.RetentionHours)
+
+	cmpBools(t, "vast_unwrap.enabled", false, cfg.VASTUnwrap.Enabled)
+	cmpInts(t, "vast_unwrap.max_wrapper_depth", 5, cfg.VASTUnwrap.MaxWrapperDepth)
+	cmpInts(t, "vast_unwrap.timeout_ms", 200, cfg.VASTUnwrap.TimeoutMs)
+	cmpInts(t, "vast_unwrap.max_response_bytes", 1048576, int(cfg.VASTUnwrap.MaxResponseBytes))
+
+	cmpBools(t, "uid_store.enabled", false, cfg.UIDStore.Enabled)
+	cmpStrings(t, "uid_store.type", "memory", cfg.UIDStore.Type)
+	cmpStrings(t, "uid_store.id_cookie_name", "pbs_fpid", cfg.UIDStore.IDCookieName)
+	cmpInts(t, "uid_store.ttl_seconds", 1209600, cfg.UIDStore.TTLSeconds)
+	cmpInts(t, "uid_store.max_users", 1000000, cfg.UIDStore.MaxUsers)
+	cmpInts(t, "uid_store.redis.timeout_ms", 50, cfg.UIDStore.Redis.Timeout)
+	cmpStrings(t, "uid_store.redis.key_prefix", "uids:", cfg.UIDStore.Redis.KeyPrefix)
+
+	cmpBools(t, "circuit_breaker.enabled", false, cfg.CircuitBreaker.Enabled)
+	cmpBools(t, "circuit_breaker.per_host", false, cfg.CircuitBreaker.PerHost)
+	cmpInts(t, "circuit_breaker.window_seconds", 60, cfg.CircuitBreaker.WindowSeconds)
+	cmpInts(t, "circuit_breaker.min_requests", 20, cfg.CircuitBreaker.MinRequests)
+	assert.Equal(t, 0.5, cfg.CircuitBreaker.ErrorRatio, "circuit_breaker.error_ratio")
+	cmpInts(t, "circuit_breaker.open_duration_ms", 30000, cfg.CircuitBreaker.OpenDuration)
+	cmpInts(t, "circuit_breaker.half_open_probes", 3, cfg.CircuitBreaker.HalfOpenProbes)
+
 	cmpInts(t, "account_defaults.privacy.ipv6.anon_keep_bits", 56, cfg.AccountDefaults.Privacy.IPv6Config.AnonKeepBits)
 	cmpInts(t, "account_defaults.privacy.ipv4.anon_keep_bits", 24, cfg.AccountDefaults.Privacy.IPv4Config.AnonKeepBits)
+	cmpBools(t, "account_defaults.privacy.usnat.enabled", false, cfg.AccountDefaults.Privacy.USNat.Enabled)
+	cmpBools(t, "account_defaults.privacy.usnat.enforce_gpc", false, cfg.AccountDefaults.Privacy.USNat.EnforceGPC)
 
 	//Assert purpose VendorExceptionMap hash tables were built correctly
 	cmpBools(t, "analytics.agma.enabled", false, cfg.Analytics.Agma.Enabled)
@@ -236,6 +299,17 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "analytics.agma.buffers.count", 100, cfg.Analytics.Agma.Buffers.EventCount)
 	cmpStrings(t, "analytics.agma.buffers.timeout", "15m", cfg.Analytics.Agma.Buffers.Timeout)
 	cmpInts(t, "analytics.agma.accounts", 0, len(cfg.Analytics.Agma.Accounts))
+	cmpBools(t, "analytics.eventlog.enabled", false, cfg.Analytics.EventLog.Enabled)
+	cmpInts(t, "analytics.eventlog.buffer_size", 10000, cfg.Analytics.EventLog.BufferSize)
+	cmpStrings(t, "analytics.eventlog.file.directory", "", cfg.Analytics.EventLog.File.Directory)
+	cmpStrings(t, "analytics.eventlog.file.prefix", "pbs-events", cfg.Analytics.EventLog.File.Prefix)
+	cmpStrings(t, "analytics.eventlog.file.max_size", "100MB", cfg.Analytics.EventLog.File.MaxSize)
+	cmpStrings(t, "analytics.eventlog.file.rotation_interval", "1h", cfg.Analytics.EventLog.File.RotationInterval)
+	cmpBools(t, "analytics.eventlog.file.compress", true, cfg.Analytics.EventLog.File.Compress)
+	cmpStrings(t, "analytics.eventlog.sink", "file", cfg.Analytics.EventLog.Sink)
+	assert.Empty(t, cfg.Analytics.EventLog.Kafka.Brokers, "analytics.eventlog.kafka.brokers")
+	cmpStrings(t, "analytics.eventlog.kafka.topic", "", cfg.Analytics.EventLog.Kafka.Topic)
+	cmpBools(t, "analytics.eventlog.kafka.tls.enabled", false, cfg.Analytics.EventLog.Kafka.TLS.Enabled)
 	expectedTCF2 := TCF2{
 		Enabled: true,
 		Purpose1: TCF2Purpose{
@@ -467,6 +541,7 @@ host_schain_node:
 validations:
     banner_creative_max_size: "skip"
     secure_markup: "skip"
+    bid_conformance: "warn"
     max_creative_width: 0
     max_creative_height: 0
 experiment:
@@ -494,6 +569,9 @@ price_floors:
         max_idle_connections_per_host: 2
         idle_connection_timeout_seconds: 10
       max_retries: 5
+    reporting:
+      enabled: true
+      max_models: 50
 account_defaults:
     events:
         enabled: true
@@ -638,6 +716,7 @@ func TestFullConfig(t *testing.T) {
 	cmpStrings(t, "datacenter", "1", cfg.DataCenter)
 	cmpStrings(t, "validations.banner_creative_max_size", "skip", cfg.Validations.BannerCreativeMaxSize)
 	cmpStrings(t, "validations.secure_markup", "skip", cfg.Validations.SecureMarkup)
+	cmpStrings(t, "validations.bid_conformance", "warn", cfg.Validations.BidConformance)
 	cmpInts(t, "validations.max_creative_width", 0, int(cfg.Validations.MaxCreativeWidth))
 	cmpInts(t, "validations.max_creative_height", 0, int(cfg.Validations.MaxCreativeHeight))
 	cmpBools(t, "tmax_adjustments.enabled", true, cfg.TmaxAdjustments.Enabled)
@@ -655,6 +734,8 @@ func TestFullConfig(t *testing.T) {
 	cmpInts(t, "price_floors.fetcher.http_client.max_idle_connections_per_host", 2, cfg.PriceFloors.Fetcher.HttpClient.MaxIdleConnsPerHost)
 	cmpInts(t, "price_floors.fetcher.http_client.idle_connection_timeout_seconds", 10, cfg.PriceFloors.Fetcher.HttpClient.IdleConnTimeout)
 	cmpInts(t, "price_floors.fetcher.max_retries", 5, cfg.PriceFloors.Fetcher.MaxRetries)
+	cmpBools(t, "price_floors.reporting.enabled", true, cfg.PriceFloors.Reporting.Enabled)
+	cmpInts(t, "price_floors.reporting.max_models", 50, cfg.PriceFloors.Reporting.MaxModels)
 	cmpBools(t, "account_defaults.price_floors.enabled", true, cfg.AccountDefaults.PriceFloors.Enabled)
 	cmpInts(t, "account_defaults.price_floors.enforce_floors_rate", 50, cfg.AccountDefaults.PriceFloors.EnforceFloorsRate)
 	cmpBools(t, "account_defaults.price_floors.adjust_for_bid_adjustment", false, cfg.AccountDefaults.PriceFloors.AdjustForBidAdjustment)
@@ -1271,6 +1352,14 @@ func TestInvalidEnforceAlgo(t *testing.T) {
 	assert.ElementsMatch(t, errs, expectedErrs, "gdpr.tcf2.purposeX.enforce_algo should prevent invalid values but it doesn't")
 }
 
+func TestAdminStoredDataRequiresAdmin(t *testing.T) {
+	cfg := &Admin{StoredData: AdminStoredData{Enabled: true}}
+	assert.Equal(t, []error{errors.New("admin.stored_data.enabled requires admin.enabled to be true")}, cfg.validate(nil))
+
+	cfg.Enabled = true
+	assert.Empty(t, cfg.validate(nil))
+}
+
 func TestNegativeCurrencyConverterFetchInterval(t *testing.T) {
 	v := viper.New()
 	v.Set("gdpr.default_value", "0")
@@ -1962,3 +2051,59 @@ func TestUnpackDSADefault(t *testing.T) {
 		})
 	}
 }
+
+func TestPriceFloorsReportingValidate(t *testing.T) {
+	assert.Empty(t, (&PriceFloorsReporting{Enabled: false}).validate(nil))
+	assert.Empty(t, (&PriceFloorsReporting{Enabled: true, MaxModels: 10}).validate(nil))
+	assert.Equal(t, []error{errors.New("price_floors.reporting.max_models must be > 0. Got 0")}, (&PriceFloorsReporting{Enabled: true}).validate(nil))
+}
+
+func TestEventLogValidate(t *testing.T) {
+	testCases := []struct {
+		description    string
+		givenEventLog  EventLog
+		expectedErrors []error
+	}{
+		{
+			description:   "disabled",
+			givenEventLog: EventLog{Enabled: false, Sink: "unknown"},
+		},
+		{
+			description:   "file",
+			givenEventLog: EventLog{Enabled: true, BufferSize: 10, Sink: EventLogSinkFile, File: EventLogFile{Directory: "/var/log/pbs"}},
+		},
+		{
+			description:    "file-without-directory",
+			givenEventLog:  EventLog{Enabled: true, BufferSize: 10, Sink: EventLogSinkFile},
+			expectedErrors: []error{errors.New("analytics.eventlog.file.directory is required when analytics.eventlog.sink is file")},
+		},
+		{
+			description: "kafka",
+			givenEventLog: EventLog{Enabled: true, BufferSize: 10, Sink: EventLogSinkKafka, Kafka: EventLogKafka{
+				Brokers: []string{"kafka:9093"},
+				Topic:   "pbs-events",
+				TLS:     EventLogKafkaTLS{Enabled: true, CertFile: "client.pem", KeyFile: "client.key"},
+			}},
+		},
+		{
+			description:   "kafka-invalid",
+			givenEventLog: EventLog{Enabled: true, BufferSize: 10, Sink: EventLogSinkKafka, Kafka: EventLogKafka{TLS: EventLogKafkaTLS{Enabled: true, CertFile: "client.pem"}}},
+			expectedErrors: []error{
+				errors.New("analytics.eventlog.kafka.brokers must not be empty when analytics.eventlog.sink is kafka"),
+				errors.New("analytics.eventlog.kafka.topic is required when analytics.eventlog.sink is kafka"),
+				errors.New("analytics.eventlog.kafka.tls.cert_file and analytics.eventlog.kafka.tls.key_file must be set together"),
+			},
+		},
+		{
+			description:    "unknown-sink",
+			givenEventLog:  EventLog{Enabled: true, BufferSize: 0, Sink: "s3"},
+			expectedErrors: []error{errors.New("analytics.eventlog.buffer_size must be > 0. Got 0"), errors.New("analytics.eventlog.sink must be one of [file, kafka]. Got s3")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrors, test.givenEventLog.validate(nil))
+		})
+	}
+}
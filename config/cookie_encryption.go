@@ -0,0 +1,69 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+)
+
+// MaxCookieEncryptionKeyIDLength is the longest key ID which fits the encrypted uids cookie header.
+const MaxCookieEncryptionKeyIDLength = 255
+
+// CookieEncryption configures the encryption and authentication of the uids cookie. Keys are rotated by
+// adding a new key, making it the active one and removing the old key once the cookies it encrypted expired.
+type CookieEncryption struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Keys maps a key ID to a base64 encoded AES key of 16, 24 or 32 bytes.
+	Keys map[string]string `mapstructure:"keys"`
+	// ActiveKeyID is the ID of the key which encrypts the cookies written. Every key in Keys decrypts.
+	ActiveKeyID string `mapstructure:"active_key_id"`
+	// AllowLegacy accepts the plain base64 cookies written before the encryption was enabled. When disabled, only
+	// the opt-out of a legacy cookie is kept.
+	AllowLegacy bool `mapstructure:"allow_legacy"`
+}
+
+// DecodedKeys returns the AES keys by ID. Keys which don't decode are left out, they're reported by validate.
+func (cfg *CookieEncryption) DecodedKeys() map[string][]byte {
+	keys := make(map[string][]byte, len(cfg.Keys))
+	for id, encoded := range cfg.Keys {
+		if key, err := base64.StdEncoding.DecodeString(encoded); err == nil && validAESKeyLength(len(key)) {
+			keys[id] = key
+		}
+	}
+	return keys
+}
+
+func (cfg *CookieEncryption) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if len(cfg.Keys) == 0 {
+		errs = append(errs, fmt.Errorf("host_cookie.encryption.keys must be set when host_cookie.encryption.enabled=true"))
+	}
+
+	ids := make([]string, 0, len(cfg.Keys))
+	for id := range cfg.Keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if len(id) == 0 || len(id) > MaxCookieEncryptionKeyIDLength {
+			errs = append(errs, fmt.Errorf("host_cookie.encryption.keys IDs must be 1 to %d characters long. Got %q", MaxCookieEncryptionKeyIDLength, id))
+		}
+		key, err := base64.StdEncoding.DecodeString(cfg.Keys[id])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("host_cookie.encryption.keys.%s must be base64 encoded: %v", id, err))
+		} else if !validAESKeyLength(len(key)) {
+			errs = append(errs, fmt.Errorf("host_cookie.encryption.keys.%s must be 16, 24 or 32 bytes long. Got %d", id, len(key)))
+		}
+	}
+
+	if _, ok := cfg.Keys[cfg.ActiveKeyID]; !ok {
+		errs = append(errs, fmt.Errorf("host_cookie.encryption.active_key_id must be one of the host_cookie.encryption.keys. Got %q", cfg.ActiveKeyID))
+	}
+	return errs
+}
+
+func validAESKeyLength(length int) bool {
+	return length == 16 || length == 24 || length == 32
+}
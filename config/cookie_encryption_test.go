@@ -0,0 +1,74 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookieEncryptionValidate(t *testing.T) {
+	testCases := []struct {
+		description  string
+		cfg          CookieEncryption
+		expectedErrs []error
+	}{
+		{
+			description: "valid",
+			cfg: CookieEncryption{
+				Enabled:     true,
+				Keys:        map[string]string{"2024": "MDEyMzQ1Njc4OWFiY2RlZg==", "2025": "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="},
+				ActiveKeyID: "2025",
+			},
+		},
+		{
+			description: "disabled-not-validated",
+			cfg:         CookieEncryption{Enabled: false, ActiveKeyID: "unknown"},
+		},
+		{
+			description: "no-keys",
+			cfg:         CookieEncryption{Enabled: true},
+			expectedErrs: []error{
+				errors.New("host_cookie.encryption.keys must be set when host_cookie.encryption.enabled=true"),
+				errors.New(`host_cookie.encryption.active_key_id must be one of the host_cookie.encryption.keys. Got ""`),
+			},
+		},
+		{
+			description: "invalid-keys",
+			cfg: CookieEncryption{
+				Enabled:     true,
+				Keys:        map[string]string{"a": "%%%", "b": "c2hvcnQ="},
+				ActiveKeyID: "a",
+			},
+			expectedErrs: []error{
+				errors.New("host_cookie.encryption.keys.a must be base64 encoded: illegal base64 data at input byte 0"),
+				errors.New("host_cookie.encryption.keys.b must be 16, 24 or 32 bytes long. Got 5"),
+			},
+		},
+		{
+			description: "unknown-active-key",
+			cfg: CookieEncryption{
+				Enabled:     true,
+				Keys:        map[string]string{"2025": "MDEyMzQ1Njc4OWFiY2RlZg=="},
+				ActiveKeyID: "2026",
+			},
+			expectedErrs: []error{
+				errors.New(`host_cookie.encryption.active_key_id must be one of the host_cookie.encryption.keys. Got "2026"`),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.cfg.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
+
+func TestCookieEncryptionDecodedKeys(t *testing.T) {
+	cfg := CookieEncryption{
+		Keys: map[string]string{"good": "MDEyMzQ1Njc4OWFiY2RlZg==", "short": "c2hvcnQ=", "bad": "%%%"},
+	}
+	assert.Equal(t, map[string][]byte{"good": []byte("0123456789abcdef")}, cfg.DecodedKeys())
+}
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ReloadReport lists the keys which changed in a reloaded configuration. Applied keys are in effect from the
+// next request on, while RestartRequired keys are ignored until Prebid Server is restarted.
+type ReloadReport struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required"`
+}
+
+// liveConfiguration is shared by the startup configuration and every configuration swapped in by a reload.
+type liveConfiguration struct {
+	lock    sync.Mutex
+	startup *Configuration
+	current atomic.Pointer[Configuration]
+}
+
+// reloadableKey is a key, along with everything below it, which can change without a restart because it's
+// only read while handling requests.
+type reloadableKey struct {
+	prefix string
+	// apply copies the changed key from next into reloaded. It returns false when the change needs a restart
+	// after all, given the configuration the server was started with.
+	apply func(key string, reloaded, next, startup *Configuration) bool
+}
+
+var reloadableKeys = []reloadableKey{
+	{
+		prefix: "account_defaults",
+		apply: func(key string, reloaded, next, startup *Configuration) bool {
+			reloaded.AccountDefaults = next.AccountDefaults
+			reloaded.accountDefaultsJSON = next.accountDefaultsJSON
+			return true
+		},
+	},
+	{
+		prefix: "auction_timeouts_ms",
+		apply: func(key string, reloaded, next, startup *Configuration) bool {
+			reloaded.AuctionTimeouts = next.AuctionTimeouts
+			return true
+		},
+	},
+	{
+		prefix: "blocked_apps",
+		apply: func(key string, reloaded, next, startup *Configuration) bool {
+			reloaded.BlockedApps = next.BlockedApps
+			reloaded.BlockedAppsLookup = next.BlockedAppsLookup
+			return true
+		},
+	},
+	{
+		prefix: "hooks.host_execution_plan",
+		apply: func(key string, reloaded, next, startup *Configuration) bool {
+			// Without hooks enabled at startup there is no plan builder to pick up the plan
+			if !startup.Hooks.Enabled {
+				return false
+			}
+			reloaded.Hooks.HostExecutionPlan = next.Hooks.HostExecutionPlan
+			return true
+		},
+	},
+	{
+		prefix: "hooks.default_account_execution_plan",
+		apply: func(key string, reloaded, next, startup *Configuration) bool {
+			if !startup.Hooks.Enabled {
+				return false
+			}
+			reloaded.Hooks.DefaultAccountExecutionPlan = next.Hooks.DefaultAccountExecutionPlan
+			return true
+		},
+	},
+	{
+		prefix: "user_sync.coop_sync",
+		apply: func(key string, reloaded, next, startup *Configuration) bool {
+			reloaded.UserSync.Cooperative = next.UserSync.Cooperative
+			return true
+		},
+	},
+	{
+		prefix: "user_sync.priority_groups",
+		apply: func(key string, reloaded, next, startup *Configuration) bool {
+			reloaded.UserSync.PriorityGroups = next.UserSync.PriorityGroups
+			return true
+		},
+	},
+	{
+		prefix: "adapters",
+		apply: func(key string, reloaded, next, startup *Configuration) bool {
+			// Only adapters.<bidder>.disabled can change, and only for bidders which were enabled at startup,
+			// since disabled bidders have no adapter built for them.
+			//
+			// Changes to adapters.<bidder>.userSync require a restart. The syncers, with their parsed url
+			// templates, are built once from them at startup and shared without locking by /cookie_sync,
+			// /setuid, /getuids and the exchange, which must all agree on each syncer's key. Swapping them
+			// mid-flight could let /setuid reject the callback of a sync started by /cookie_sync, and a
+			// changed key orphans the ids already stored under the old one in the users' uids cookies.
+			parts := strings.Split(key, ".")
+			if len(parts) != 3 || parts[2] != "disabled" {
+				return false
+			}
+			bidder := parts[1]
+			if info, ok := startup.BidderInfos[bidder]; !ok || info.Disabled {
+				return false
+			}
+			infos := make(BidderInfos, len(reloaded.BidderInfos))
+			for name, info := range reloaded.BidderInfos {
+				infos[name] = info
+			}
+			info := infos[bidder]
+			info.Disabled = next.BidderInfos[bidder].Disabled
+			infos[bidder] = info
+			reloaded.BidderInfos = infos
+			return true
+		},
+	},
+}
+
+// EnableReload lets the keys in reloadableKeys be replaced by Reload. It's called once at startup, before cfg
+// is handed to anything else.
+func (cfg *Configuration) EnableReload() {
+	cfg.live = &liveConfiguration{startup: cfg}
+	cfg.live.current.Store(cfg)
+}
+
+// Current returns the configuration in effect, which is cfg itself unless a reload swapped in another one.
+// Reloadable keys must be read through Current while handling requests to pick up reloads.
+func (cfg *Configuration) Current() *Configuration {
+	if cfg == nil || cfg.live == nil {
+		return cfg
+	}
+	return cfg.live.current.Load()
+}
+
+// Reload compares next, which must have passed validation, with the configuration in effect and atomically
+// swaps in the reloadable keys which changed. Any other change is reported as requiring a restart.
+func (cfg *Configuration) Reload(next *Configuration) (ReloadReport, error) {
+	if cfg.live == nil {
+		return ReloadReport{}, errors.New("configuration reload is not enabled")
+	}
+	live := cfg.live
+	live.lock.Lock()
+	defer live.lock.Unlock()
+
+	current := live.current.Load()
+	reloaded := *current
+	report := ReloadReport{Applied: []string{}, RestartRequired: []string{}}
+
+	for _, key := range changedKeys("", reflect.ValueOf(current).Elem(), reflect.ValueOf(next).Elem()) {
+		if applyKey(key, &reloaded, next, live.startup) {
+			report.Applied = append(report.Applied, key)
+		} else {
+			report.RestartRequired = append(report.RestartRequired, key)
+		}
+	}
+
+	if len(report.Applied) > 0 {
+		live.current.Store(&reloaded)
+	}
+	return report, nil
+}
+
+func applyKey(key string, reloaded, next, startup *Configuration) bool {
+	for _, reloadable := range reloadableKeys {
+		if key == reloadable.prefix || strings.HasPrefix(key, reloadable.prefix+".") {
+			return reloadable.apply(key, reloaded, next, startup)
+		}
+	}
+	return false
+}
+
+// changedKeys returns the keys, named after their mapstructure tags, whose values differ between a and b.
+// Structs and maps are compared field by field and key by key, anything else as a whole. Fields without a
+// tag are derived from the tagged ones, so they're skipped.
+func changedKeys(prefix string, a reflect.Value, b reflect.Value) []string {
+	if a.Kind() == reflect.Pointer && !a.IsNil() && !b.IsNil() {
+		return changedKeys(prefix, a.Elem(), b.Elem())
+	}
+
+	var keys []string
+	switch a.Kind() {
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if !field.IsExported() || name == "" || name == "-" {
+				continue
+			}
+			keys = append(keys, changedKeys(joinKey(prefix, name), a.Field(i), b.Field(i))...)
+		}
+	case reflect.Map:
+		mapKeys := make(map[string]reflect.Value)
+		for _, k := range append(a.MapKeys(), b.MapKeys()...) {
+			mapKeys[fmt.Sprintf("%v", k.Interface())] = k
+		}
+		names := make([]string, 0, len(mapKeys))
+		for name := range mapKeys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			aValue, bValue := a.MapIndex(mapKeys[name]), b.MapIndex(mapKeys[name])
+			if !aValue.IsValid() || !bValue.IsValid() {
+				keys = append(keys, joinKey(prefix, name))
+				continue
+			}
+			keys = append(keys, changedKeys(joinKey(prefix, name), aValue, bValue)...)
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			keys = append(keys, prefix)
+		}
+	}
+	return keys
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangedKeys(t *testing.T) {
+	a := &Configuration{
+		Host:        "localhost",
+		BlockedApps: []string{"app-1"},
+		HostCookie:  HostCookie{Domain: "a.com"},
+		BidderInfos: BidderInfos{"appnexus": {Endpoint: "https://a.com"}, "rubicon": {}},
+		Hooks:       Hooks{Modules: Modules{"vendor": {"module": map[string]interface{}{"enabled": true}}}},
+	}
+	b := &Configuration{
+		Host:              "localhost",
+		BlockedApps:       []string{"app-1", "app-2"},
+		BlockedAppsLookup: map[string]bool{"app-1": true, "app-2": true},
+		HostCookie:        HostCookie{Domain: "b.com"},
+		BidderInfos:       BidderInfos{"appnexus": {Endpoint: "https://b.com", Disabled: true}, "pubmatic": {}},
+		Hooks:             Hooks{Modules: Modules{"vendor": {"module": map[string]interface{}{"enabled": false}}}},
+	}
+
+	expected := []string{
+		"host_cookie.domain",
+		"blocked_apps",
+		"adapters.appnexus.disabled",
+		"adapters.appnexus.endpoint",
+		"adapters.pubmatic",
+		"adapters.rubicon",
+		"hooks.modules.vendor.module",
+	}
+	assert.Equal(t, expected, changedKeys("", reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()))
+	assert.Empty(t, changedKeys("", reflect.ValueOf(a).Elem(), reflect.ValueOf(a).Elem()))
+}
+
+func TestReload(t *testing.T) {
+	newStartupConfig := func() *Configuration {
+		cfg := &Configuration{
+			Port:            8000,
+			AuctionTimeouts: AuctionTimeouts{Default: 500, Max: 1000},
+			AccountDefaults: Account{DebugAllow: true},
+			BidderInfos:     BidderInfos{"appnexus": {}, "rubicon": {Disabled: true}},
+		}
+		assert.NoError(t, cfg.MarshalAccountDefaults())
+		return cfg
+	}
+
+	var auctionPlan HookExecutionPlan
+	assert.NoError(t, jsonutil.UnmarshalValid([]byte(`{"endpoints": {"/openrtb2/auction": {"stages": {}}}}`), &auctionPlan))
+
+	testCases := []struct {
+		description             string
+		hooksEnabled            bool
+		next                    func(*Configuration)
+		expectedReport          ReloadReport
+		expectedAuctionTimeouts AuctionTimeouts
+		expectedBidderInfos     BidderInfos
+	}{
+		{
+			description:    "unchanged",
+			next:           func(cfg *Configuration) {},
+			expectedReport: ReloadReport{Applied: []string{}, RestartRequired: []string{}},
+		},
+		{
+			description: "reloadable-and-restart-keys",
+			next: func(cfg *Configuration) {
+				cfg.Port = 9000
+				cfg.AuctionTimeouts.Max = 2000
+				cfg.BlockedApps = []string{"app"}
+				cfg.BlockedAppsLookup = map[string]bool{"app": true}
+				cfg.AccountDefaults.DebugAllow = false
+				assert.NoError(t, cfg.MarshalAccountDefaults())
+			},
+			expectedReport: ReloadReport{
+				Applied:         []string{"auction_timeouts_ms.max", "blocked_apps", "account_defaults.debug_allow"},
+				RestartRequired: []string{"port"},
+			},
+			expectedAuctionTimeouts: AuctionTimeouts{Default: 500, Max: 2000},
+		},
+		{
+			description: "bidders",
+			next: func(cfg *Configuration) {
+				cfg.BidderInfos = BidderInfos{"appnexus": {Disabled: true}, "rubicon": {}}
+			},
+			expectedReport: ReloadReport{
+				Applied:         []string{"adapters.appnexus.disabled"},
+				RestartRequired: []string{"adapters.rubicon.disabled"},
+			},
+			expectedBidderInfos: BidderInfos{"appnexus": {Disabled: true}, "rubicon": {Disabled: true}},
+		},
+		{
+			description: "bidder-syncers",
+			next: func(cfg *Configuration) {
+				cfg.BidderInfos = BidderInfos{"appnexus": {Syncer: &Syncer{Key: "adnxs"}}, "rubicon": {Disabled: true}}
+			},
+			expectedReport: ReloadReport{
+				Applied:         []string{},
+				RestartRequired: []string{"adapters.appnexus.userSync"},
+			},
+		},
+		{
+			description:  "hook-plans",
+			hooksEnabled: true,
+			next: func(cfg *Configuration) {
+				cfg.Hooks.HostExecutionPlan = auctionPlan
+			},
+			expectedReport: ReloadReport{
+				Applied:         []string{"hooks.host_execution_plan.endpoints./openrtb2/auction"},
+				RestartRequired: []string{},
+			},
+		},
+		{
+			description: "hook-plans-with-hooks-disabled",
+			next: func(cfg *Configuration) {
+				cfg.Hooks.HostExecutionPlan = auctionPlan
+			},
+			expectedReport: ReloadReport{
+				Applied:         []string{},
+				RestartRequired: []string{"hooks.host_execution_plan.endpoints./openrtb2/auction"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := newStartupConfig()
+			cfg.Hooks.Enabled = test.hooksEnabled
+			cfg.EnableReload()
+			assert.Same(t, cfg, cfg.Current())
+
+			next := newStartupConfig()
+			next.Hooks.Enabled = test.hooksEnabled
+			test.next(next)
+
+			report, err := cfg.Reload(next)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedReport, report)
+
+			current := cfg.Current()
+			if len(test.expectedReport.Applied) == 0 {
+				assert.Same(t, cfg, current)
+				return
+			}
+			assert.NotSame(t, cfg, current)
+			assert.Equal(t, 8000, current.Port, "restart required keys must not change")
+			assert.Equal(t, next.accountDefaultsJSON, current.AccountDefaultsJSON())
+			assert.Equal(t, next.BlockedAppsLookup, current.BlockedAppsLookup)
+			assert.Equal(t, next.Hooks.HostExecutionPlan, current.Hooks.HostExecutionPlan)
+			if test.expectedAuctionTimeouts != (AuctionTimeouts{}) {
+				assert.Equal(t, test.expectedAuctionTimeouts, current.AuctionTimeouts)
+			}
+			if test.expectedBidderInfos != nil {
+				assert.Equal(t, test.expectedBidderInfos, current.BidderInfos)
+				assert.False(t, cfg.BidderInfos["appnexus"].Disabled, "the startup bidder infos must not be modified")
+			}
+		})
+	}
+}
+
+func TestReloadNotEnabled(t *testing.T) {
+	cfg := &Configuration{}
+	assert.Same(t, cfg, cfg.Current())
+
+	_, err := cfg.Reload(&Configuration{Port: 1})
+	assert.Equal(t, errors.New("configuration reload is not enabled"), err)
+}
@@ -71,6 +71,71 @@ type StoredRequests struct {
 	// HTTPEvents configures an instance of stored_requests/events/http/http.go.
 	// If non-nil, the server will use those endpoints to populate and update the cache.
 	HTTPEvents HTTPEventsConfig `mapstructure:"http_events"`
+	// Redis configures an instance of stored_requests/backends/redis_fetcher/fetcher.go.
+	// If enabled, Stored data will be fetched from the Redis server described there, and
+	// stored_requests/events/redis/redis.go may be used to keep the caches up to date.
+	Redis RedisConfig `mapstructure:"redis"`
+}
+
+// RedisConfig configures stored_requests/backends/redis_fetcher/fetcher.go
+// and stored_requests/events/redis/redis.go
+type RedisConfig struct {
+	// Enabled should be true if Stored data should be loaded from Redis.
+	Enabled bool `mapstructure:"enabled"`
+	// Address is the host:port of the Redis server.
+	Address  string `mapstructure:"address"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// Database is the numeric Redis database which holds the stored data.
+	Database int `mapstructure:"db"`
+	// Timeout is the amount of time before a call to Redis is aborted.
+	Timeout int `mapstructure:"timeout_ms"`
+	// KeyPrefixes are prepended to each ID to build the Redis key which holds its data.
+	KeyPrefixes RedisKeyPrefixes `mapstructure:"key_prefixes"`
+	// Events configures an instance of stored_requests/events/redis/redis.go.
+	Events RedisEventsConfig `mapstructure:"events"`
+}
+
+func (cfg RedisConfig) TimeoutDuration() time.Duration {
+	return time.Duration(cfg.Timeout) * time.Millisecond
+}
+
+func (cfg *RedisConfig) validate(dataType DataType, errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+
+	section := dataType.Section()
+	if cfg.Address == "" {
+		errs = append(errs, fmt.Errorf("%s: redis.address must be set when redis.enabled=true", section))
+	}
+	if cfg.Database < 0 {
+		errs = append(errs, fmt.Errorf("%s: redis.db must be >= 0. Got %d", section, cfg.Database))
+	}
+	if cfg.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("%s: redis.timeout_ms must be > 0", section))
+	}
+	return errs
+}
+
+// RedisKeyPrefixes holds the key prefix used for each type of Stored data kept in Redis.
+// A Stored Request with ID "abc" is read from the key "{Requests}abc", for example.
+type RedisKeyPrefixes struct {
+	Requests    string `mapstructure:"requests"`
+	AmpRequests string `mapstructure:"amp_requests"`
+	Imps        string `mapstructure:"imps"`
+	Responses   string `mapstructure:"responses"`
+	Accounts    string `mapstructure:"accounts"`
+	Categories  string `mapstructure:"categories"`
+}
+
+// RedisEventsConfig configures stored_requests/events/redis/redis.go
+type RedisEventsConfig struct {
+	// Enabled should be true to subscribe to Redis keyspace notifications and use them
+	// to update the in-memory cache. The Redis server must be started with keyspace
+	// notifications enabled for generic and string commands, expired and evicted keys included
+	// (e.g. notify-keyspace-events "K$gxe").
+	Enabled bool `mapstructure:"enabled"`
 }
 
 // HTTPEventsConfig configures stored_requests/events/http/http.go
@@ -126,6 +191,9 @@ func resolvedStoredRequestsConfig(cfg *Configuration) {
 	amp.HTTP.Endpoint = sr.HTTP.AmpEndpoint
 	amp.CacheEvents.Endpoint = "/storedrequests/amp"
 	amp.HTTPEvents.Endpoint = sr.HTTPEvents.AmpEndpoint
+	amp.Redis.KeyPrefixes.Requests = sr.Redis.KeyPrefixes.AmpRequests
+	// The stored data admin API only writes through the stored_requests section
+	amp.Database.WriterQueries = DatabaseWriterQueries{}
 
 	// Set data types for each section
 	cfg.StoredRequests.dataType = RequestDataType
@@ -142,6 +210,7 @@ func (cfg *StoredRequests) validate(errs []error) []error {
 	} else {
 		errs = cfg.Database.validate(cfg.DataType(), errs)
 	}
+	errs = cfg.Redis.validate(cfg.DataType(), errs)
 
 	// Categories do not use cache so none of the following checks apply
 	if cfg.DataType() == CategoryDataType {
@@ -163,6 +232,9 @@ func (cfg *StoredRequests) validate(errs []error) []error {
 		if cfg.Database.CacheInitialization.Query != "" {
 			errs = append(errs, fmt.Errorf("%s: database.initialize_caches.query must be empty if in_memory_cache=none", cfg.Section()))
 		}
+		if cfg.Redis.Events.Enabled {
+			errs = append(errs, fmt.Errorf("%s: redis.events must be disabled if in_memory_cache=none", cfg.Section()))
+		}
 	}
 	errs = cfg.InMemoryCache.validate(cfg.DataType(), errs)
 	return errs
@@ -175,6 +247,7 @@ type DatabaseConfig struct {
 	FetcherQueries      DatabaseFetcherQueries   `mapstructure:"fetcher"`
 	CacheInitialization DatabaseCacheInitializer `mapstructure:"initialize_caches"`
 	PollUpdates         DatabaseUpdatePolling    `mapstructure:"poll_for_updates"`
+	WriterQueries       DatabaseWriterQueries    `mapstructure:"writer"`
 }
 
 func (cfg *DatabaseConfig) validate(dataType DataType, errs []error) []error {
@@ -184,6 +257,10 @@ func (cfg *DatabaseConfig) validate(dataType DataType, errs []error) []error {
 
 	errs = cfg.CacheInitialization.validate(dataType, errs)
 	errs = cfg.PollUpdates.validate(dataType, errs)
+	errs = cfg.WriterQueries.validate(dataType, errs)
+	if cfg.WriterQueries.Enabled() && cfg.FetcherQueries.QueryTemplate == "" {
+		errs = append(errs, fmt.Errorf("%s: database.writer requires database.fetcher.query to read the stored data", dataType.Section()))
+	}
 	return errs
 }
 
@@ -235,6 +312,60 @@ type DatabaseFetcherQueries struct {
 	AmpQueryTemplate string `mapstructure:"amp_query"`
 }
 
+// DatabaseWriterQueries are the queries the stored data admin API writes Stored Requests and Imps with.
+// Reads go through the fetcher query, so it must be set as well. Each query receives $DATA_TYPE, which is
+// either 'request' or 'imp'. For example:
+//
+//	list_query:   SELECT id FROM stored_data WHERE type = $DATA_TYPE ORDER BY id LIMIT $LIMIT OFFSET $OFFSET
+//	upsert_query: INSERT INTO stored_data (id, type, data) VALUES ($ID, $DATA_TYPE, $DATA)
+//	                ON CONFLICT (id, type) DO UPDATE SET data = EXCLUDED.data
+//	delete_query: DELETE FROM stored_data WHERE id = $ID AND type = $DATA_TYPE
+type DatabaseWriterQueries struct {
+	// ListQuery must return the ids of the stored data in ascending order, for the page given by $LIMIT and $OFFSET.
+	ListQuery string `mapstructure:"list_query"`
+	// UpsertQuery inserts or replaces the stored data $DATA with id $ID.
+	UpsertQuery string `mapstructure:"upsert_query"`
+	// DeleteQuery deletes the stored data with id $ID.
+	DeleteQuery string `mapstructure:"delete_query"`
+}
+
+// Enabled returns true if any of the writer queries are set.
+func (cfg *DatabaseWriterQueries) Enabled() bool {
+	return cfg.ListQuery != "" || cfg.UpsertQuery != "" || cfg.DeleteQuery != ""
+}
+
+func (cfg *DatabaseWriterQueries) validate(dataType DataType, errs []error) []error {
+	if !cfg.Enabled() {
+		return errs
+	}
+	section := dataType.Section()
+	if dataType != RequestDataType {
+		return append(errs, fmt.Errorf("%s: database.writer is only supported for stored_requests", section))
+	}
+
+	queries := []struct {
+		name   string
+		query  string
+		params []string
+	}{
+		{name: "list_query", query: cfg.ListQuery, params: []string{"$DATA_TYPE", "$LIMIT", "$OFFSET"}},
+		{name: "upsert_query", query: cfg.UpsertQuery, params: []string{"$ID", "$DATA_TYPE", "$DATA"}},
+		{name: "delete_query", query: cfg.DeleteQuery, params: []string{"$ID", "$DATA_TYPE"}},
+	}
+	for _, query := range queries {
+		if query.query == "" {
+			errs = append(errs, fmt.Errorf("%s: database.writer.%s must be set along with the other writer queries", section, query.name))
+			continue
+		}
+		for _, param := range query.params {
+			if !strings.Contains(query.query, param) {
+				errs = append(errs, fmt.Errorf("%s: database.writer.%s must contain %s parameter", section, query.name, param))
+			}
+		}
+	}
+	return errs
+}
+
 type DatabaseCacheInitializer struct {
 	Timeout int `mapstructure:"timeout_ms"`
 	// Query should be something like:
@@ -205,6 +205,107 @@ func TestDatabaseConfigValidation(t *testing.T) {
 	}
 }
 
+func TestDatabaseWriterQueriesValidation(t *testing.T) {
+	validQueries := DatabaseWriterQueries{
+		ListQuery:   "SELECT id FROM stored_data WHERE type = $DATA_TYPE ORDER BY id LIMIT $LIMIT OFFSET $OFFSET",
+		UpsertQuery: "INSERT INTO stored_data (id, type, data) VALUES ($ID, $DATA_TYPE, $DATA)",
+		DeleteQuery: "DELETE FROM stored_data WHERE id = $ID AND type = $DATA_TYPE",
+	}
+
+	tests := []struct {
+		description string
+		dataType    DataType
+		queries     DatabaseWriterQueries
+		wantErrors  []error
+	}{
+		{
+			description: "Not set",
+			dataType:    AccountDataType,
+		},
+		{
+			description: "Valid",
+			dataType:    RequestDataType,
+			queries:     validQueries,
+		},
+		{
+			description: "Set for accounts",
+			dataType:    AccountDataType,
+			queries:     validQueries,
+			wantErrors:  []error{errors.New("accounts: database.writer is only supported for stored_requests")},
+		},
+		{
+			description: "Missing query and parameter",
+			dataType:    RequestDataType,
+			queries: DatabaseWriterQueries{
+				ListQuery:   validQueries.ListQuery,
+				UpsertQuery: "INSERT INTO stored_data (id, type, data) VALUES ($ID, 'request', $DATA)",
+			},
+			wantErrors: []error{
+				errors.New("stored_requests: database.writer.upsert_query must contain $DATA_TYPE parameter"),
+				errors.New("stored_requests: database.writer.delete_query must be set along with the other writer queries"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		errs := tt.queries.validate(tt.dataType, nil)
+		assert.Equal(t, tt.wantErrors, errs, tt.description)
+	}
+}
+
+func TestDatabaseWriterRequiresFetcherQuery(t *testing.T) {
+	dbConfig := &DatabaseConfig{
+		ConnectionInfo: DatabaseConnection{Database: "some-connection-string"},
+		WriterQueries: DatabaseWriterQueries{
+			ListQuery:   "SELECT id FROM stored_data WHERE type = $DATA_TYPE LIMIT $LIMIT OFFSET $OFFSET",
+			UpsertQuery: "INSERT INTO stored_data (id, type, data) VALUES ($ID, $DATA_TYPE, $DATA)",
+			DeleteQuery: "DELETE FROM stored_data WHERE id = $ID AND type = $DATA_TYPE",
+		},
+	}
+	errs := dbConfig.validate(RequestDataType, nil)
+	assert.Equal(t, []error{errors.New("stored_requests: database.writer requires database.fetcher.query to read the stored data")}, errs)
+
+	dbConfig.FetcherQueries.QueryTemplate = "SELECT id, data, type FROM stored_data WHERE id in $REQUEST_ID_LIST"
+	assert.Empty(t, dbConfig.validate(RequestDataType, nil))
+}
+
+func TestRedisConfigValidation(t *testing.T) {
+	tests := []struct {
+		description    string
+		config         RedisConfig
+		wantErrorCount int
+	}{
+		{
+			description: "Disabled",
+			config:      RedisConfig{},
+		},
+		{
+			description: "Valid",
+			config:      RedisConfig{Enabled: true, Address: "localhost:6379", Timeout: 100},
+		},
+		{
+			description:    "Missing address",
+			config:         RedisConfig{Enabled: true, Timeout: 100},
+			wantErrorCount: 1,
+		},
+		{
+			description:    "Negative database",
+			config:         RedisConfig{Enabled: true, Address: "localhost:6379", Database: -1, Timeout: 100},
+			wantErrorCount: 1,
+		},
+		{
+			description:    "Zero timeout",
+			config:         RedisConfig{Enabled: true, Address: "localhost:6379"},
+			wantErrorCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		errs := tt.config.validate(RequestDataType, nil)
+		assert.Equal(t, tt.wantErrorCount, len(errs), tt.description)
+	}
+}
+
 func assertErrsExist(t *testing.T, err []error) {
 	t.Helper()
 	if len(err) == 0 {
@@ -266,6 +367,12 @@ func TestResolveConfig(t *testing.T) {
 			HTTPEvents: HTTPEventsConfig{
 				AmpEndpoint: "amp-http-events-endpoint",
 			},
+			Redis: RedisConfig{
+				KeyPrefixes: RedisKeyPrefixes{
+					Requests:    "auc-redis-prefix:",
+					AmpRequests: "amp-redis-prefix:",
+				},
+			},
 		},
 	}
 
@@ -289,4 +396,6 @@ func TestResolveConfig(t *testing.T) {
 	assertStringsEqual(t, amp.HTTP.Endpoint, cfg.StoredRequests.HTTP.AmpEndpoint)
 	assertStringsEqual(t, amp.HTTPEvents.Endpoint, cfg.StoredRequests.HTTPEvents.AmpEndpoint)
 	assertStringsEqual(t, amp.CacheEvents.Endpoint, "/storedrequests/amp")
+	assertStringsEqual(t, amp.Redis.KeyPrefixes.Requests, cfg.StoredRequests.Redis.KeyPrefixes.AmpRequests)
+	assertStringsEqual(t, auc.Redis.KeyPrefixes.Requests, "auc-redis-prefix:")
 }
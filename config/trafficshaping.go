@@ -0,0 +1,28 @@
+package config
+
+import "fmt"
+
+// TrafficShaping configures the model behind account level traffic shaping, which skips calls to bidders
+// that rarely bid on a given kind of traffic. Accounts opt in, and tune the thresholds, in their own
+// traffic_shaping settings.
+type TrafficShaping struct {
+	Enabled bool `mapstructure:"enabled"`
+	// WindowMinutes is the length of the rolling window the bid rates are computed over.
+	WindowMinutes int `mapstructure:"window_minutes"`
+	// MaxKeys caps the number of account, bidder, country, device type and size combinations tracked in memory.
+	// Once it's reached, traffic for combinations which aren't tracked yet is never throttled.
+	MaxKeys int `mapstructure:"max_keys"`
+}
+
+func (cfg *TrafficShaping) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.WindowMinutes <= 0 {
+		errs = append(errs, fmt.Errorf("traffic_shaping.window_minutes must be > 0. Got %d", cfg.WindowMinutes))
+	}
+	if cfg.MaxKeys <= 0 {
+		errs = append(errs, fmt.Errorf("traffic_shaping.max_keys must be > 0. Got %d", cfg.MaxKeys))
+	}
+	return errs
+}
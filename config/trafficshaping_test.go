@@ -0,0 +1,40 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrafficShapingValidate(t *testing.T) {
+	testCases := []struct {
+		description  string
+		cfg          TrafficShaping
+		expectedErrs []error
+	}{
+		{
+			description: "valid",
+			cfg:         TrafficShaping{Enabled: true, WindowMinutes: 60, MaxKeys: 100000},
+		},
+		{
+			description: "disabled-not-validated",
+			cfg:         TrafficShaping{Enabled: false},
+		},
+		{
+			description: "all-invalid",
+			cfg:         TrafficShaping{Enabled: true, WindowMinutes: -1},
+			expectedErrs: []error{
+				errors.New("traffic_shaping.window_minutes must be > 0. Got -1"),
+				errors.New("traffic_shaping.max_keys must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.cfg.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	UIDStoreTypeMemory = "memory"
+	UIDStoreTypeRedis  = "redis"
+)
+
+// UIDStore configures the server-side storage of the bidder UIDs, keyed by a first-party ID cookie on the
+// host domain. It keeps syncs working for the browsers which drop or cap the uids cookie.
+type UIDStore struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Type is the storage used, either "memory" or "redis".
+	Type string `mapstructure:"type"`
+	// IDCookieName is the name of the first-party ID cookie. It's set by /setuid and /cookie_sync when missing.
+	IDCookieName string `mapstructure:"id_cookie_name"`
+	// TTLSeconds is how long the UIDs of a user are kept after their last sync.
+	TTLSeconds int `mapstructure:"ttl_seconds"`
+	// MaxUsers caps the number of users kept by the memory store. The least recently synced are dropped first.
+	MaxUsers int           `mapstructure:"max_users"`
+	Redis    UIDStoreRedis `mapstructure:"redis"`
+}
+
+// UIDStoreRedis configures the Redis server, or compatible, used by a redis UIDStore.
+type UIDStoreRedis struct {
+	// Address is the host:port of the Redis server.
+	Address  string `mapstructure:"address"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	Database int    `mapstructure:"db"`
+	// Timeout is the amount of time before a call to Redis is aborted.
+	Timeout int `mapstructure:"timeout_ms"`
+	// KeyPrefix is prepended to the first-party ID to build the Redis key which holds the UIDs.
+	KeyPrefix string `mapstructure:"key_prefix"`
+}
+
+func (cfg *UIDStore) TTL() time.Duration {
+	return time.Duration(cfg.TTLSeconds) * time.Second
+}
+
+func (cfg UIDStoreRedis) TimeoutDuration() time.Duration {
+	return time.Duration(cfg.Timeout) * time.Millisecond
+}
+
+func (cfg *UIDStore) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.IDCookieName == "" {
+		errs = append(errs, fmt.Errorf("uid_store.id_cookie_name must be set when uid_store.enabled=true"))
+	}
+	if cfg.TTLSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("uid_store.ttl_seconds must be > 0. Got %d", cfg.TTLSeconds))
+	}
+	switch cfg.Type {
+	case UIDStoreTypeMemory:
+		if cfg.MaxUsers <= 0 {
+			errs = append(errs, fmt.Errorf("uid_store.max_users must be > 0. Got %d", cfg.MaxUsers))
+		}
+	case UIDStoreTypeRedis:
+		if cfg.Redis.Address == "" {
+			errs = append(errs, fmt.Errorf("uid_store.redis.address must be set when uid_store.type=redis"))
+		}
+		if cfg.Redis.Database < 0 {
+			errs = append(errs, fmt.Errorf("uid_store.redis.db must be >= 0. Got %d", cfg.Redis.Database))
+		}
+		if cfg.Redis.Timeout <= 0 {
+			errs = append(errs, fmt.Errorf("uid_store.redis.timeout_ms must be > 0. Got %d", cfg.Redis.Timeout))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("uid_store.type must be one of [%s, %s]. Got %q", UIDStoreTypeMemory, UIDStoreTypeRedis, cfg.Type))
+	}
+	return errs
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIDStoreValidate(t *testing.T) {
+	testCases := []struct {
+		description  string
+		cfg          UIDStore
+		expectedErrs []error
+	}{
+		{
+			description: "valid-memory",
+			cfg:         UIDStore{Enabled: true, Type: "memory", IDCookieName: "pbs_fpid", TTLSeconds: 60, MaxUsers: 10},
+		},
+		{
+			description: "valid-redis",
+			cfg:         UIDStore{Enabled: true, Type: "redis", IDCookieName: "pbs_fpid", TTLSeconds: 60, Redis: UIDStoreRedis{Address: "localhost:6379", Timeout: 50}},
+		},
+		{
+			description: "disabled-not-validated",
+			cfg:         UIDStore{Enabled: false, Type: "unknown"},
+		},
+		{
+			description: "invalid-memory",
+			cfg:         UIDStore{Enabled: true, Type: "memory"},
+			expectedErrs: []error{
+				errors.New("uid_store.id_cookie_name must be set when uid_store.enabled=true"),
+				errors.New("uid_store.ttl_seconds must be > 0. Got 0"),
+				errors.New("uid_store.max_users must be > 0. Got 0"),
+			},
+		},
+		{
+			description: "invalid-redis",
+			cfg:         UIDStore{Enabled: true, Type: "redis", IDCookieName: "pbs_fpid", TTLSeconds: 60, Redis: UIDStoreRedis{Database: -1}},
+			expectedErrs: []error{
+				errors.New("uid_store.redis.address must be set when uid_store.type=redis"),
+				errors.New("uid_store.redis.db must be >= 0. Got -1"),
+				errors.New("uid_store.redis.timeout_ms must be > 0. Got 0"),
+			},
+		},
+		{
+			description: "invalid-type",
+			cfg:         UIDStore{Enabled: true, Type: "sql", IDCookieName: "pbs_fpid", TTLSeconds: 60},
+			expectedErrs: []error{
+				errors.New(`uid_store.type must be one of [memory, redis]. Got "sql"`),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.cfg.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
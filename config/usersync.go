@@ -1,14 +1,67 @@
 package config
 
+import (
+	"fmt"
+	"time"
+)
+
 // UserSync specifies the static global user sync configuration.
 type UserSync struct {
-	Cooperative    UserSyncCooperative `mapstructure:"coop_sync"`
-	ExternalURL    string              `mapstructure:"external_url"`
-	RedirectURL    string              `mapstructure:"redirect_url"`
-	PriorityGroups [][]string          `mapstructure:"priority_groups"`
+	Cooperative    UserSyncCooperative  `mapstructure:"coop_sync"`
+	ExternalURL    string               `mapstructure:"external_url"`
+	RedirectURL    string               `mapstructure:"redirect_url"`
+	PriorityGroups [][]string           `mapstructure:"priority_groups"`
+	ValueRanking   UserSyncValueRanking `mapstructure:"value_ranking"`
 }
 
 // UserSyncCooperative specifies the static global default cooperative cookie sync
 type UserSyncCooperative struct {
 	EnabledByDefault bool `mapstructure:"default"`
 }
+
+// UserSyncValueRanking configures the ranking of the bidders to sync by the value a UID brings to them. The value
+// is learned per account from the site auctions, comparing the winning CPM of the bidders with and without a UID.
+// Bidders are ranked before the /cookie_sync limit applies, in place of the static priority groups. The bidders of
+// the request still come before the ones added by cooperative syncing.
+type UserSyncValueRanking struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MinAuctions is the number of auctions observed with and without a UID before the value of a bidder is trusted.
+	// Until then the bidder gets the average value of the bidders known to the account.
+	MinAuctions int `mapstructure:"min_auctions"`
+	// MinSyncs is the number of syncs offered to a bidder before its /setuid success rate is trusted.
+	MinSyncs int `mapstructure:"min_syncs"`
+	// Window is the number of observations after which the older ones weigh half, so the ranking follows changes.
+	Window int `mapstructure:"window"`
+	// MaxAccounts caps the accounts tracked. Auctions of further accounts aren't learned from.
+	MaxAccounts int `mapstructure:"max_accounts"`
+	// StaleUIDAgeHours makes a UID older than this eligible to sync again, ranked by age. 0 disables re-syncs.
+	StaleUIDAgeHours int `mapstructure:"stale_uid_age_hours"`
+	// Deterministic disables the shuffling of the bidders, ties are kept in the order requested. Meant for tests.
+	Deterministic bool `mapstructure:"deterministic"`
+}
+
+func (cfg *UserSyncValueRanking) StaleUIDAge() time.Duration {
+	return time.Duration(cfg.StaleUIDAgeHours) * time.Hour
+}
+
+func (cfg *UserSyncValueRanking) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.MinAuctions < 0 {
+		errs = append(errs, fmt.Errorf("user_sync.value_ranking.min_auctions must be >= 0. Got %d", cfg.MinAuctions))
+	}
+	if cfg.MinSyncs < 0 {
+		errs = append(errs, fmt.Errorf("user_sync.value_ranking.min_syncs must be >= 0. Got %d", cfg.MinSyncs))
+	}
+	if cfg.Window <= 0 {
+		errs = append(errs, fmt.Errorf("user_sync.value_ranking.window must be > 0. Got %d", cfg.Window))
+	}
+	if cfg.MaxAccounts <= 0 {
+		errs = append(errs, fmt.Errorf("user_sync.value_ranking.max_accounts must be > 0. Got %d", cfg.MaxAccounts))
+	}
+	if cfg.StaleUIDAgeHours < 0 {
+		errs = append(errs, fmt.Errorf("user_sync.value_ranking.stale_uid_age_hours must be >= 0. Got %d", cfg.StaleUIDAgeHours))
+	}
+	return errs
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSyncValueRankingValidate(t *testing.T) {
+	testCases := []struct {
+		description  string
+		cfg          UserSyncValueRanking
+		expectedErrs []error
+	}{
+		{
+			description: "valid",
+			cfg:         UserSyncValueRanking{Enabled: true, MinAuctions: 100, MinSyncs: 20, Window: 10000, MaxAccounts: 10, StaleUIDAgeHours: 72},
+		},
+		{
+			description: "disabled-not-validated",
+			cfg:         UserSyncValueRanking{Enabled: false, Window: -1},
+		},
+		{
+			description: "invalid",
+			cfg:         UserSyncValueRanking{Enabled: true, MinAuctions: -1, MinSyncs: -1, StaleUIDAgeHours: -1},
+			expectedErrs: []error{
+				errors.New("user_sync.value_ranking.min_auctions must be >= 0. Got -1"),
+				errors.New("user_sync.value_ranking.min_syncs must be >= 0. Got -1"),
+				errors.New("user_sync.value_ranking.window must be > 0. Got 0"),
+				errors.New("user_sync.value_ranking.max_accounts must be > 0. Got 0"),
+				errors.New("user_sync.value_ranking.stale_uid_age_hours must be >= 0. Got -1"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.cfg.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import "fmt"
+
+// VASTUnwrap configures the server-side resolution of the VAST wrapper chains in video bids. Accounts opt
+// in, and choose whether invalid creatives are rejected, in their own vast_unwrap settings.
+type VASTUnwrap struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxWrapperDepth is the number of wrappers followed before the bid is considered invalid.
+	MaxWrapperDepth int `mapstructure:"max_wrapper_depth"`
+	// TimeoutMs caps the time spent resolving the bids of an auction. It's also capped by what's left of tmax.
+	TimeoutMs int `mapstructure:"timeout_ms"`
+	// MaxResponseBytes caps the size of each VAST document fetched.
+	MaxResponseBytes int64      `mapstructure:"max_response_bytes"`
+	HttpClient       HTTPClient `mapstructure:"http_client"`
+}
+
+func (cfg *VASTUnwrap) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.MaxWrapperDepth <= 0 {
+		errs = append(errs, fmt.Errorf("vast_unwrap.max_wrapper_depth must be > 0. Got %d", cfg.MaxWrapperDepth))
+	}
+	if cfg.TimeoutMs <= 0 {
+		errs = append(errs, fmt.Errorf("vast_unwrap.timeout_ms must be > 0. Got %d", cfg.TimeoutMs))
+	}
+	if cfg.MaxResponseBytes <= 0 {
+		errs = append(errs, fmt.Errorf("vast_unwrap.max_response_bytes must be > 0. Got %d", cfg.MaxResponseBytes))
+	}
+	return errs
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVASTUnwrapValidate(t *testing.T) {
+	testCases := []struct {
+		description  string
+		cfg          VASTUnwrap
+		expectedErrs []error
+	}{
+		{
+			description: "valid",
+			cfg:         VASTUnwrap{Enabled: true, MaxWrapperDepth: 5, TimeoutMs: 200, MaxResponseBytes: 1048576},
+		},
+		{
+			description: "disabled-not-validated",
+			cfg:         VASTUnwrap{Enabled: false},
+		},
+		{
+			description: "all-invalid",
+			cfg:         VASTUnwrap{Enabled: true, MaxWrapperDepth: -1},
+			expectedErrs: []error{
+				errors.New("vast_unwrap.max_wrapper_depth must be > 0. Got -1"),
+				errors.New("vast_unwrap.timeout_ms must be > 0. Got 0"),
+				errors.New("vast_unwrap.max_response_bytes must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.cfg.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
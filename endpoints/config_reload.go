@@ -0,0 +1,39 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// NewConfigReloadEndpoint returns an admin endpoint which reloads the host configuration and responds
+// with the keys which were applied, and the ones which require a restart.
+func NewConfigReloadEndpoint(reload func() (config.ReloadReport, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		report, err := reload()
+		if err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprintf(w, "Configuration was not reloaded: %v", err)
+			return
+		}
+
+		jsonOutput, err := jsonutil.Marshal(report)
+		if err != nil {
+			glog.Errorf("/config/reload Critical error when trying to marshal the reload report: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
@@ -0,0 +1,65 @@
+package endpoints
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigReload(t *testing.T) {
+	var testCases = []struct {
+		description    string
+		method         string
+		report         config.ReloadReport
+		err            error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description:    "Reloaded",
+			method:         http.MethodPost,
+			report:         config.ReloadReport{Applied: []string{"blocked_apps"}, RestartRequired: []string{"port"}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"applied":["blocked_apps"],"restart_required":["port"]}`,
+		},
+		{
+			description:    "Invalid Config",
+			method:         http.MethodPost,
+			err:            errors.New("validation errors"),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "Configuration was not reloaded: validation errors",
+		},
+		{
+			description:    "Wrong Method",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			reloads := 0
+			handler := NewConfigReloadEndpoint(func() (config.ReloadReport, error) {
+				reloads++
+				return test.report, test.err
+			})
+
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(test.method, "/config/reload", nil))
+			result := w.Result()
+			defer result.Body.Close()
+			body, _ := io.ReadAll(result.Body)
+
+			assert.Equal(t, test.expectedStatus, result.StatusCode)
+			assert.Equal(t, test.expectedBody, string(body))
+			if test.method != http.MethodPost {
+				assert.Zero(t, reloads)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package endpoints
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/golang/glog"
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	accountService "github.com/prebid/prebid-server/v3/account"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/privacy/ccpa"
+	gppPrivacy "github.com/prebid/prebid-server/v3/privacy/gpp"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/stringutil"
+)
+
+var errConsentDiagnosticsChannelInvalid = errors.New(`"channel" must be one of web, amp, app, video or dooh`)
+
+// consentDiagnosticsActivities are the activities enforced for a bidder, in the order they are traced.
+var consentDiagnosticsActivities = []privacy.Activity{
+	privacy.ActivitySyncUser,
+	privacy.ActivityFetchBids,
+	privacy.ActivityTransmitUserFPD,
+	privacy.ActivityTransmitPreciseGeo,
+	privacy.ActivityTransmitUniqueRequestIDs,
+	privacy.ActivityTransmitTIDs,
+}
+
+// NewConsentDiagnosticsEndpoint explains why bidders are allowed or denied each privacy activity. It accepts the
+// consent signals of a request (TCF2, GPP and US Privacy), an account, a channel and the bidders, and returns the
+// TCF2 decision trace, the CCPA policy and the account activity rules which matched, along with the final
+// decision per activity per bidder.
+func NewConsentDiagnosticsEndpoint(cfg *config.Configuration, accountsFetcher stored_requests.AccountFetcher, me metrics.MetricsEngine, vendorIDs map[openrtb_ext.BidderName]uint16, vendorListFetcher gdpr.VendorListFetcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		request, err := parseConsentDiagnosticsRequest(w, r, cfg.MaxRequestSize)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		account, errs := accountService.GetAccount(r.Context(), cfg, accountsFetcher, request.Account, me)
+		if len(errs) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(combineErrors(errs).Error()))
+			return
+		}
+
+		response := diagnoseConsent(r.Context(), cfg, account, vendorIDs, vendorListFetcher, request)
+
+		jsonOutput, err := jsonutil.Marshal(response)
+		if err != nil {
+			glog.Errorf("/consent/diagnostics Critical error when trying to marshal the diagnostics: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
+
+type consentDiagnosticsRequest struct {
+	Account     string   `json:"account"`
+	Channel     string   `json:"channel"`
+	Bidders     []string `json:"bidders"`
+	GDPR        *int     `json:"gdpr"`
+	GDPRConsent string   `json:"gdpr_consent"`
+	USPrivacy   string   `json:"us_privacy"`
+	GPP         string   `json:"gpp"`
+	GPPSID      string   `json:"gpp_sid"`
+}
+
+type consentDiagnosticsResponse struct {
+	Account string                     `json:"account"`
+	Channel string                     `json:"channel"`
+	GPP     consentDiagnosticsGPP      `json:"gpp"`
+	GDPR    gdpr.Diagnostics           `json:"gdpr"`
+	CCPA    consentDiagnosticsCCPA     `json:"ccpa"`
+	Bidders []consentDiagnosticsBidder `json:"bidders"`
+}
+
+type consentDiagnosticsGPP struct {
+	SIDs     []int8 `json:"sids"`
+	Sections []int  `json:"sections"`
+	Error    string `json:"error,omitempty"`
+}
+
+type consentDiagnosticsCCPA struct {
+	Enabled    bool   `json:"enabled"`
+	Consent    string `json:"consent"`
+	OptOutSale bool   `json:"opt_out_sale"`
+	Error      string `json:"error,omitempty"`
+}
+
+type consentDiagnosticsBidder struct {
+	Bidder     string                       `json:"bidder"`
+	Activities []consentDiagnosticsActivity `json:"activities"`
+}
+
+type consentDiagnosticsActivity struct {
+	Activity string `json:"activity"`
+	// Rule is the index of the account rule which decided the activity, or -1 if the default was used.
+	Rule            int  `json:"rule"`
+	ActivityAllowed bool `json:"activity_allowed"`
+	GDPRAllowed     bool `json:"gdpr_allowed"`
+	CCPAAllowed     bool `json:"ccpa_allowed"`
+	Allowed         bool `json:"allowed"`
+}
+
+func parseConsentDiagnosticsRequest(w http.ResponseWriter, r *http.Request, maxSize int64) (consentDiagnosticsRequest, error) {
+	reader := io.Reader(r.Body)
+	if maxSize > 0 {
+		reader = http.MaxBytesReader(w, r.Body, maxSize)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return consentDiagnosticsRequest{}, err
+	}
+
+	request := consentDiagnosticsRequest{}
+	if err := jsonutil.UnmarshalValid(body, &request); err != nil {
+		return consentDiagnosticsRequest{}, err
+	}
+
+	if request.Account == "" {
+		request.Account = metrics.PublisherUnknown
+	}
+
+	switch config.ChannelType(request.Channel) {
+	case "":
+		request.Channel = string(config.ChannelWeb)
+	case config.ChannelWeb, config.ChannelAMP, config.ChannelApp, config.ChannelVideo, config.ChannelDOOH:
+	default:
+		return consentDiagnosticsRequest{}, errConsentDiagnosticsChannelInvalid
+	}
+
+	return request, nil
+}
+
+func diagnoseConsent(ctx context.Context, cfg *config.Configuration, account *config.Account, vendorIDs map[openrtb_ext.BidderName]uint16, vendorListFetcher gdpr.VendorListFetcher, request consentDiagnosticsRequest) consentDiagnosticsResponse {
+	channel := config.ChannelType(request.Channel)
+	response := consentDiagnosticsResponse{
+		Account: request.Account,
+		Channel: request.Channel,
+		GPP:     consentDiagnosticsGPP{Sections: []int{}},
+		Bidders: make([]consentDiagnosticsBidder, 0, len(request.Bidders)),
+	}
+
+	var gpp gpplib.GppContainer
+	gppSID, err := stringutil.StrToInt8Slice(request.GPPSID)
+	if err != nil {
+		response.GPP.Error = err.Error()
+	}
+	response.GPP.SIDs = gppSID
+	if len(request.GPP) > 0 {
+		var errs []error
+		if gpp, errs = gpplib.Parse(request.GPP); len(errs) > 0 {
+			response.GPP.Error = errs[0].Error()
+		}
+		for _, section := range gpp.SectionTypes {
+			response.GPP.Sections = append(response.GPP.Sections, int(section))
+		}
+	}
+
+	gdprSignal, _, err := extractGDPRSignal(request.GDPR, gppSID)
+	if err != nil {
+		gdprSignal = gdpr.SignalAmbiguous
+	}
+	gdprConsent := request.GDPRConsent
+	if i := gppPrivacy.IndexOfSID(gpp, gppConstants.SectionTCFEU2); i >= 0 && gdprConsent == "" {
+		gdprConsent = gpp.Sections[i].GetValue()
+	}
+
+	bidders := make([]openrtb_ext.BidderName, 0, len(request.Bidders))
+	for _, bidder := range request.Bidders {
+		bidders = append(bidders, openrtb_ext.NormalizeBidderNameOrUnchanged(bidder))
+	}
+
+	tcf2Cfg := gdpr.NewTCF2Config(cfg.GDPR.TCF2, account.GDPR)
+	gdprRequestInfo := gdpr.RequestInfo{Consent: gdprConsent, GDPRSignal: gdprSignal, PublisherID: account.ID}
+	response.GDPR = gdpr.Diagnose(ctx, cfg.GDPR, tcf2Cfg, vendorIDs, vendorListFetcher, gdprRequestInfo, channel, bidders)
+
+	response.CCPA.Enabled = cfg.CCPA.Enforce
+	if accountEnabled := account.CCPA.EnabledForChannelType(channel); accountEnabled != nil {
+		response.CCPA.Enabled = *accountEnabled
+	}
+	response.CCPA.Consent, err = ccpa.SelectCCPAConsent(request.USPrivacy, gpp, gppSID)
+	if err != nil {
+		response.CCPA.Error = err.Error()
+	}
+	ccpaParsedPolicy, err := ccpa.Policy{Consent: response.CCPA.Consent}.Parse(nil)
+	if err != nil {
+		response.CCPA.Error = err.Error()
+	}
+	response.CCPA.OptOutSale = ccpaParsedPolicy.CanEnforce() && ccpaParsedPolicy.ShouldEnforce("")
+
+	activityControl := privacy.NewActivityControl(&account.Privacy)
+	activityRequest := privacy.NewRequestFromPolicies(privacy.Policies{GPPSID: gppSID, GPP: gpp})
+
+	for i, bidder := range bidders {
+		gdprDiagnostics := response.GDPR.Bidders[i]
+		ccpaAllowed := !response.CCPA.Enabled || !ccpaParsedPolicy.CanEnforce() || !ccpaParsedPolicy.ShouldEnforce(bidder.String())
+		component := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidder.String()}
+
+		bidderDiagnostics := consentDiagnosticsBidder{Bidder: bidder.String()}
+		for _, activity := range consentDiagnosticsActivities {
+			decision := activityControl.Decide(activity, component, activityRequest)
+			activityDiagnostics := consentDiagnosticsActivity{
+				Activity:        activity.String(),
+				Rule:            decision.Rule,
+				ActivityAllowed: decision.Allowed,
+				GDPRAllowed:     true,
+				CCPAAllowed:     true,
+			}
+
+			switch activity {
+			case privacy.ActivitySyncUser:
+				activityDiagnostics.GDPRAllowed = gdprDiagnostics.SyncAllowed
+				activityDiagnostics.CCPAAllowed = ccpaAllowed
+			case privacy.ActivityFetchBids:
+				activityDiagnostics.GDPRAllowed = gdprDiagnostics.AllowBidRequest
+			case privacy.ActivityTransmitUserFPD:
+				activityDiagnostics.GDPRAllowed = gdprDiagnostics.PassID
+				activityDiagnostics.CCPAAllowed = ccpaAllowed
+			case privacy.ActivityTransmitPreciseGeo:
+				activityDiagnostics.GDPRAllowed = gdprDiagnostics.PassGeo
+				activityDiagnostics.CCPAAllowed = ccpaAllowed
+			}
+			activityDiagnostics.Allowed = activityDiagnostics.ActivityAllowed && activityDiagnostics.GDPRAllowed && activityDiagnostics.CCPAAllowed
+
+			bidderDiagnostics.Activities = append(bidderDiagnostics.Activities, activityDiagnostics)
+		}
+		response.Bidders = append(response.Bidders, bidderDiagnostics)
+	}
+
+	return response
+}
@@ -0,0 +1,155 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prebid/go-gdpr/vendorlist"
+	"github.com/prebid/prebid-server/v3/config"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsentDiagnosticsEndpoint(t *testing.T) {
+	accountsFetcher := FakeAccountsFetcher{AccountData: map[string]json.RawMessage{
+		"disabled-pub": json.RawMessage(`{"disabled":true}`),
+		"pub":          json.RawMessage(`{"privacy":{"allowactivities":{"fetchBids":{"rules":[{"condition":{"componentName":["appnexus"]},"allow":false}]}}}}`),
+	}}
+	vendorListFetcher := func(ctx context.Context, specVersion, listVersion uint16) (vendorlist.VendorList, error) {
+		return nil, errors.New("not found")
+	}
+
+	testCases := []struct {
+		description    string
+		method         string
+		body           string
+		maxRequestSize int64
+		expectedStatus int
+	}{
+		{
+			description:    "get-not-allowed",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			description:    "malformed-body",
+			method:         http.MethodPost,
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "body-too-large",
+			method:         http.MethodPost,
+			body:           `{"account":"pub","bidders":["appnexus"]}`,
+			maxRequestSize: 10,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "invalid-channel",
+			method:         http.MethodPost,
+			body:           `{"channel":"tv"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "account-disabled",
+			method:         http.MethodPost,
+			body:           `{"account":"disabled-pub"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "diagnostics",
+			method:         http.MethodPost,
+			body:           `{"account":"pub","bidders":["appnexus"]}`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := &config.Configuration{MaxRequestSize: test.maxRequestSize}
+			endpoint := NewConsentDiagnosticsEndpoint(cfg, accountsFetcher, &metricsConf.NilMetricsEngine{}, nil, vendorListFetcher)
+			w := httptest.NewRecorder()
+
+			endpoint(w, httptest.NewRequest(test.method, "/consent/diagnostics", strings.NewReader(test.body)))
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestDiagnoseConsent(t *testing.T) {
+	cfg := &config.Configuration{
+		CCPA: config.CCPA{Enforce: true},
+		GDPR: config.GDPR{Enabled: true, DefaultValue: "0", TCF2: config.TCF2{Enabled: true}},
+	}
+	account := &config.Account{
+		ID: "pub",
+		Privacy: config.AccountPrivacy{
+			AllowActivities: &config.AllowActivities{
+				FetchBids: config.Activity{
+					Rules: []config.ActivityRule{
+						{Condition: config.ActivityCondition{ComponentName: []string{"appnexus"}}, Allow: false},
+					},
+				},
+			},
+		},
+	}
+	vendorListFetcher := func(ctx context.Context, specVersion, listVersion uint16) (vendorlist.VendorList, error) {
+		return nil, errors.New("not found")
+	}
+
+	request := consentDiagnosticsRequest{
+		Account:   "pub",
+		Channel:   string(config.ChannelWeb),
+		Bidders:   []string{"AppNexus", "pubmatic"},
+		GDPR:      ptrutil.ToPtr(0),
+		USPrivacy: "1NYN",
+	}
+
+	response := diagnoseConsent(context.Background(), cfg, account, nil, vendorListFetcher, request)
+
+	assert.False(t, response.GDPR.InScope)
+	assert.Equal(t, consentDiagnosticsCCPA{Enabled: true, Consent: "1NYN", OptOutSale: true}, response.CCPA)
+	require.Len(t, response.Bidders, 2)
+
+	appnexus := response.Bidders[0]
+	assert.Equal(t, string(openrtb_ext.BidderAppnexus), appnexus.Bidder)
+	require.Len(t, appnexus.Activities, len(consentDiagnosticsActivities))
+	assert.Equal(t, consentDiagnosticsActivity{Activity: "syncUser", Rule: -1, ActivityAllowed: true, GDPRAllowed: true, CCPAAllowed: false, Allowed: false}, appnexus.Activities[0])
+	assert.Equal(t, consentDiagnosticsActivity{Activity: "fetchBids", Rule: 0, ActivityAllowed: false, GDPRAllowed: true, CCPAAllowed: true, Allowed: false}, appnexus.Activities[1])
+	assert.Equal(t, consentDiagnosticsActivity{Activity: "transmitTid", Rule: -1, ActivityAllowed: true, GDPRAllowed: true, CCPAAllowed: true, Allowed: true}, appnexus.Activities[5])
+
+	pubmatic := response.Bidders[1]
+	assert.Equal(t, consentDiagnosticsActivity{Activity: "fetchBids", Rule: -1, ActivityAllowed: true, GDPRAllowed: true, CCPAAllowed: true, Allowed: true}, pubmatic.Activities[1])
+}
+
+func TestDiagnoseConsentGPP(t *testing.T) {
+	cfg := &config.Configuration{GDPR: config.GDPR{Enabled: true, DefaultValue: "0"}}
+	vendorListFetcher := func(ctx context.Context, specVersion, listVersion uint16) (vendorlist.VendorList, error) {
+		return nil, errors.New("not found")
+	}
+
+	request := consentDiagnosticsRequest{
+		Channel: string(config.ChannelApp),
+		GPP:     "DBABMA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA",
+		GPPSID:  "2",
+	}
+
+	response := diagnoseConsent(context.Background(), cfg, &config.Account{}, nil, vendorListFetcher, request)
+
+	assert.Equal(t, consentDiagnosticsGPP{SIDs: []int8{2}, Sections: []int{2}}, response.GPP)
+	assert.Equal(t, "app", response.Channel)
+	assert.Equal(t, "not found", response.GDPR.VendorList.Error)
+	assert.NotNil(t, response.GDPR.Consent, "the TCF2 consent is read from the GPP string")
+}
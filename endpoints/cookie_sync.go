@@ -21,6 +21,9 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/macros"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
@@ -58,7 +61,10 @@ func NewCookieSyncEndpoint(
 	metrics metrics.MetricsEngine,
 	analyticsRunner analytics.Runner,
 	accountsFetcher stored_requests.AccountFetcher,
-	bidders map[string]openrtb_ext.BidderName) HTTPRouterHandler {
+	bidders map[string]openrtb_ext.BidderName,
+	uidStore usersync.UIDStore,
+	syncValueTracker *usersync.ValueTracker,
+	planBuilder hooks.ExecutionPlanBuilder) HTTPRouterHandler {
 
 	bidderHashSet := make(map[string]struct{}, len(bidders))
 	for _, bidder := range bidders {
@@ -66,7 +72,7 @@ func NewCookieSyncEndpoint(
 	}
 
 	return &cookieSyncEndpoint{
-		chooser: usersync.NewChooser(syncersByBidder, bidderHashSet, config.BidderInfos),
+		chooser: usersync.NewChooser(syncersByBidder, bidderHashSet, config.BidderInfos, syncValueTracker),
 		config:  config,
 		privacyConfig: usersyncPrivacyConfig{
 			gdprConfig:             config.GDPR,
@@ -79,6 +85,9 @@ func NewCookieSyncEndpoint(
 		pbsAnalytics:    analyticsRunner,
 		accountsFetcher: accountsFetcher,
 		time:            &timeutil.RealTime{},
+		uidStore:        uidStore,
+		uidsDecoder:     usersync.NewDecoder(&config.HostCookie),
+		planBuilder:     planBuilder,
 	}
 }
 
@@ -90,22 +99,45 @@ type cookieSyncEndpoint struct {
 	pbsAnalytics    analytics.Runner
 	accountsFetcher stored_requests.AccountFetcher
 	time            timeutil.Time
+	uidStore        usersync.UIDStore
+	uidsDecoder     usersync.Decoder
+	planBuilder     hooks.ExecutionPlanBuilder
 }
 
 func (c *cookieSyncEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	request, privacyMacros, account, err := c.parseRequest(r)
+	hookExecutor := hookexecution.NewHookExecutor(c.planBuilder, hookexecution.EndpointCookieSync, c.metrics)
+
+	request, privacyMacros, account, err := c.parseRequest(r, hookExecutor)
 	c.setCookieDeprecationHeader(w, r, account)
-	if err != nil {
+	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
+	if err != nil && !isRejectErr {
 		c.writeParseRequestErrorMetrics(err)
 		c.handleError(w, err, http.StatusBadRequest)
 		return
 	}
-	decoder := usersync.Base64Decoder{}
-
-	cookie := usersync.ReadCookie(r, decoder, &c.config.HostCookie)
+	cookie, err := usersync.ReadCookieWithError(r, c.uidsDecoder, &c.config.HostCookie)
+	if err != nil {
+		// A cookie which fails to decrypt is handled as a missing cookie, so the bidders sync again
+		glog.Warningf("/cookie_sync failed to read the uids cookie: %v", err)
+		c.metrics.RecordCookieSync(metrics.CookieSyncCookieDecryptFailed)
+	}
 	usersync.SyncHostCookie(r, cookie, &c.config.HostCookie)
+	if err := usersync.ReadStoredUIDs(r.Context(), r, cookie, c.uidStore, &c.config.UIDStore); err != nil {
+		glog.Warningf("/cookie_sync failed to read the stored UIDs: %v", err)
+	}
+
+	if isRejectErr {
+		glog.V(2).Infof("/cookie_sync rejected by module hook: %v", rejectErr)
+		c.metrics.RecordCookieSync(metrics.CookieSyncModuleRejected)
+		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, nil, nil, request.Debug, nil, hookExecutor.GetOutcomes())
+		return
+	}
 
 	result := c.chooser.Choose(request, cookie)
+	if c.uidStore != nil && result.Status == usersync.StatusOK {
+		// The syncs redirect to /setuid, which stores the UIDs under this first-party ID
+		usersync.EnsureFirstPartyID(w, r, &c.config.UIDStore, &c.config.HostCookie, siteCookieCheck(r.UserAgent()))
+	}
 
 	switch result.Status {
 	case usersync.StatusBlockedByUserOptOut:
@@ -113,15 +145,15 @@ func (c *cookieSyncEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ ht
 		c.handleError(w, errCookieSyncOptOut, http.StatusUnauthorized)
 	case usersync.StatusBlockedByPrivacy:
 		c.metrics.RecordCookieSync(metrics.CookieSyncGDPRHostCookieBlocked)
-		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, nil, result.BiddersEvaluated, request.Debug)
+		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, nil, result.BiddersEvaluated, request.Debug, nil, hookExecutor.GetOutcomes())
 	case usersync.StatusOK:
 		c.metrics.RecordCookieSync(metrics.CookieSyncOK)
 		c.writeSyncerMetrics(result.BiddersEvaluated)
-		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, result.SyncersChosen, result.BiddersEvaluated, request.Debug)
+		c.handleResponse(w, request.SyncTypeFilter, cookie, privacyMacros, result.SyncersChosen, result.BiddersEvaluated, request.Debug, result.Ranking, hookExecutor.GetOutcomes())
 	}
 }
 
-func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, macros.UserSyncPrivacy, *config.Account, error) {
+func (c *cookieSyncEndpoint) parseRequest(r *http.Request, hookExecutor hookexecution.HookStageExecutor) (usersync.Request, macros.UserSyncPrivacy, *config.Account, error) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -144,6 +176,15 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, ma
 	request = c.setLimit(request, account.CookieSync)
 	request = c.setCooperativeSync(request, account.CookieSync)
 
+	activityControl := privacy.NewActivityControl(&account.Privacy)
+
+	hookExecutor.SetAccount(account)
+	hookExecutor.SetActivityControl(activityControl)
+	request, rejectErr := executeCookieSyncRequestStage(r, request, hookExecutor)
+	if rejectErr != nil {
+		return usersync.Request{}, macros.UserSyncPrivacy{}, account, rejectErr
+	}
+
 	privacyMacros, gdprSignal, privacyPolicies, err := extractPrivacyPolicies(request, c.privacyConfig.gdprConfig.DefaultValue)
 	if err != nil {
 		return usersync.Request{}, macros.UserSyncPrivacy{}, account, err
@@ -160,8 +201,6 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, ma
 		}
 	}
 
-	activityControl := privacy.NewActivityControl(&account.Privacy)
-
 	syncTypeFilter, err := parseTypeFilter(request.FilterSettings)
 	if err != nil {
 		return usersync.Request{}, macros.UserSyncPrivacy{}, account, err
@@ -181,10 +220,11 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, ma
 	}
 
 	rx := usersync.Request{
+		Account: request.Account,
 		Bidders: request.Bidders,
 		Cooperative: usersync.Cooperative{
-			Enabled:        (request.CooperativeSync != nil && *request.CooperativeSync) || (request.CooperativeSync == nil && c.config.UserSync.Cooperative.EnabledByDefault),
-			PriorityGroups: c.config.UserSync.PriorityGroups,
+			Enabled:        (request.CooperativeSync != nil && *request.CooperativeSync) || (request.CooperativeSync == nil && c.config.Current().UserSync.Cooperative.EnabledByDefault),
+			PriorityGroups: c.config.Current().UserSync.PriorityGroups,
 		},
 		Debug: request.Debug,
 		Limit: limit,
@@ -201,6 +241,30 @@ func (c *cookieSyncEndpoint) parseRequest(r *http.Request) (usersync.Request, ma
 	return rx, privacyMacros, account, nil
 }
 
+// executeCookieSyncRequestStage runs the cookie_sync_request hooks, which may change the bidders
+// and the privacy fields of the request.
+func executeCookieSyncRequestStage(r *http.Request, request cookieSyncRequest, hookExecutor hookexecution.HookStageExecutor) (cookieSyncRequest, *hookexecution.RejectError) {
+	payload, rejectErr := hookExecutor.ExecuteCookieSyncRequestStage(hookstage.CookieSyncRequestPayload{
+		Request:     r,
+		Account:     request.Account,
+		Bidders:     request.Bidders,
+		GDPR:        request.GDPR,
+		GDPRConsent: request.GDPRConsent,
+		USPrivacy:   request.USPrivacy,
+		GPP:         request.GPP,
+		GPPSID:      request.GPPSID,
+	})
+
+	request.Bidders = payload.Bidders
+	request.GDPR = payload.GDPR
+	request.GDPRConsent = payload.GDPRConsent
+	request.USPrivacy = payload.USPrivacy
+	request.GPP = payload.GPP
+	request.GPPSID = payload.GPPSID
+
+	return request, rejectErr
+}
+
 func extractPrivacyPolicies(request cookieSyncRequest, usersyncDefaultGDPRValue string) (macros.UserSyncPrivacy, gdpr.Signal, privacy.Policies, error) {
 	// GDPR
 	gppSID, err := stringutil.StrToInt8Slice(request.GPPSID)
@@ -253,6 +317,7 @@ func extractPrivacyPolicies(request cookieSyncRequest, usersyncDefaultGDPRValue
 
 	privacyPolicies := privacy.Policies{
 		GPPSID: gppSID,
+		GPP:    gpp,
 	}
 
 	return privacyMacros, gdprSignal, privacyPolicies, nil
@@ -435,7 +500,7 @@ func (c *cookieSyncEndpoint) writeSyncerMetrics(biddersEvaluated []usersync.Bidd
 	}
 }
 
-func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.SyncTypeFilter, co *usersync.Cookie, m macros.UserSyncPrivacy, s []usersync.SyncerChoice, biddersEvaluated []usersync.BidderEvaluation, debug bool) {
+func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.SyncTypeFilter, co *usersync.Cookie, m macros.UserSyncPrivacy, s []usersync.SyncerChoice, biddersEvaluated []usersync.BidderEvaluation, debug bool, ranking []usersync.BidderRanking, hookOutcomes []hookexecution.StageOutcome) {
 	status := "no_cookie"
 	if co.HasAnyLiveSyncs() {
 		status = "ok"
@@ -481,11 +546,13 @@ func (c *cookieSyncEndpoint) handleResponse(w http.ResponseWriter, tf usersync.S
 			biddersSeen[bidderEval.Bidder] = struct{}{}
 		}
 		response.Debug = debugInfo
+		response.Ranking = mapRankingToDebug(ranking)
 	}
 
 	c.pbsAnalytics.LogCookieSyncObject(&analytics.CookieSyncObject{
-		Status:       http.StatusOK,
-		BidderStatus: mapBidderStatusToAnalytics(response.BidderStatus),
+		Status:               http.StatusOK,
+		BidderStatus:         mapBidderStatusToAnalytics(response.BidderStatus),
+		HookExecutionOutcome: hookOutcomes,
 	})
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -537,6 +604,26 @@ func mapBidderStatusToAnalytics(from []cookieSyncResponseBidder) []*analytics.Co
 	return to
 }
 
+// mapRankingToDebug explains the value ranking of the bidders, in their ranked order.
+func mapRankingToDebug(ranking []usersync.BidderRanking) []cookieSyncResponseRanking {
+	if len(ranking) == 0 {
+		return nil
+	}
+	debugRanking := make([]cookieSyncResponseRanking, len(ranking))
+	for i, r := range ranking {
+		debugRanking[i] = cookieSyncResponseRanking{
+			Bidder:          r.Bidder,
+			Score:           r.Score,
+			ValueWithUID:    r.ValueWithUID,
+			ValueWithoutUID: r.ValueWithoutUID,
+			ValueEstimated:  r.ValueEstimated,
+			SyncSuccessRate: r.SyncSuccessRate,
+			UIDAgeHours:     r.UIDAge.Hours(),
+		}
+	}
+	return debugRanking
+}
+
 func getDebugMessage(status usersync.Status) string {
 	switch status {
 	case usersync.StatusAlreadySynced:
@@ -584,9 +671,10 @@ type cookieSyncRequestFilter struct {
 }
 
 type cookieSyncResponse struct {
-	Status       string                     `json:"status"`
-	BidderStatus []cookieSyncResponseBidder `json:"bidder_status"`
-	Debug        []cookieSyncResponseDebug  `json:"debug,omitempty"`
+	Status       string                      `json:"status"`
+	BidderStatus []cookieSyncResponseBidder  `json:"bidder_status"`
+	Debug        []cookieSyncResponseDebug   `json:"debug,omitempty"`
+	Ranking      []cookieSyncResponseRanking `json:"ranking,omitempty"`
 }
 
 type cookieSyncResponseBidder struct {
@@ -606,6 +694,16 @@ type cookieSyncResponseDebug struct {
 	Error  string `json:"error,omitempty"`
 }
 
+type cookieSyncResponseRanking struct {
+	Bidder          string  `json:"bidder"`
+	Score           float64 `json:"score"`
+	ValueWithUID    float64 `json:"value_with_uid"`
+	ValueWithoutUID float64 `json:"value_without_uid"`
+	ValueEstimated  bool    `json:"value_estimated,omitempty"`
+	SyncSuccessRate float64 `json:"sync_success_rate"`
+	UIDAgeHours     float64 `json:"uid_age_hours,omitempty"`
+}
+
 type usersyncPrivacyConfig struct {
 	gdprConfig             config.GDPR
 	gdprPermissionsBuilder gdpr.PermissionsBuilder
@@ -15,10 +15,13 @@ import (
 	"testing/iotest"
 	"time"
 
+	gpplib "github.com/prebid/go-gpp"
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
 	"github.com/prebid/prebid-server/v3/macros"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
@@ -75,11 +78,14 @@ func TestNewCookieSyncEndpoint(t *testing.T) {
 		&analytics,
 		&fetcher,
 		bidders,
+		nil,
+		nil,
+		hooks.EmptyPlanBuilder{},
 	)
 	result := endpoint.(*cookieSyncEndpoint)
 
 	expected := &cookieSyncEndpoint{
-		chooser: usersync.NewChooser(syncersByBidder, biddersKnown, bidderInfo),
+		chooser: usersync.NewChooser(syncersByBidder, biddersKnown, bidderInfo, nil),
 		config: &config.Configuration{
 			UserSync:    configUserSync,
 			HostCookie:  configHostCookie,
@@ -97,6 +103,8 @@ func TestNewCookieSyncEndpoint(t *testing.T) {
 		metrics:         &metrics,
 		pbsAnalytics:    &analytics,
 		accountsFetcher: &fetcher,
+		uidsDecoder:     usersync.Base64Decoder{},
+		planBuilder:     hooks.EmptyPlanBuilder{},
 	}
 
 	assert.IsType(t, &cookieSyncEndpoint{}, endpoint)
@@ -106,6 +114,8 @@ func TestNewCookieSyncEndpoint(t *testing.T) {
 	assert.Equal(t, expected.metrics, result.metrics)
 	assert.Equal(t, expected.pbsAnalytics, result.pbsAnalytics)
 	assert.Equal(t, expected.accountsFetcher, result.accountsFetcher)
+	assert.Equal(t, expected.planBuilder, result.planBuilder)
+	assert.Equal(t, expected.uidsDecoder, result.uidsDecoder)
 
 	assert.Equal(t, expected.privacyConfig.gdprConfig, result.privacyConfig.gdprConfig)
 	assert.Equal(t, expected.privacyConfig.ccpaEnforce, result.privacyConfig.ccpaEnforce)
@@ -161,6 +171,7 @@ func TestCookieSyncHandle(t *testing.T) {
 							UsersyncInfo: &analytics.UsersyncInfo{URL: "aURL", Type: "redirect", SupportCORS: true},
 						},
 					},
+					HookExecutionOutcome: []hookexecution.StageOutcome{},
 				}
 				a.On("LogCookieSyncObject", &expected).Once()
 			},
@@ -193,6 +204,7 @@ func TestCookieSyncHandle(t *testing.T) {
 							UsersyncInfo: &analytics.UsersyncInfo{URL: "aURL", Type: "redirect", SupportCORS: true},
 						},
 					},
+					HookExecutionOutcome: []hookexecution.StageOutcome{},
 				}
 				a.On("LogCookieSyncObject", &expected).Once()
 			},
@@ -259,9 +271,10 @@ func TestCookieSyncHandle(t *testing.T) {
 			},
 			setAnalyticsExpectations: func(a *MockAnalyticsRunner) {
 				expected := analytics.CookieSyncObject{
-					Status:       200,
-					Errors:       nil,
-					BidderStatus: []*analytics.CookieSyncBidder{},
+					Status:               200,
+					Errors:               nil,
+					BidderStatus:         []*analytics.CookieSyncBidder{},
+					HookExecutionOutcome: []hookexecution.StageOutcome{},
 				}
 				a.On("LogCookieSyncObject", &expected).Once()
 			},
@@ -294,6 +307,7 @@ func TestCookieSyncHandle(t *testing.T) {
 							UsersyncInfo: &analytics.UsersyncInfo{URL: "aURL", Type: "redirect", SupportCORS: true},
 						},
 					},
+					HookExecutionOutcome: []hookexecution.StageOutcome{},
 				}
 				a.On("LogCookieSyncObject", &expected).Once()
 			},
@@ -330,6 +344,7 @@ func TestCookieSyncHandle(t *testing.T) {
 							UsersyncInfo: &analytics.UsersyncInfo{URL: "aURL", Type: "redirect", SupportCORS: true},
 						},
 					},
+					HookExecutionOutcome: []hookexecution.StageOutcome{},
 				}
 				a.On("LogCookieSyncObject", &expected).Once()
 			},
@@ -364,7 +379,8 @@ func TestCookieSyncHandle(t *testing.T) {
 		writer := httptest.NewRecorder()
 
 		endpoint := cookieSyncEndpoint{
-			chooser: FakeChooser{Result: test.givenChooserResult},
+			uidsDecoder: usersync.Base64Decoder{},
+			chooser:     FakeChooser{Result: test.givenChooserResult},
 			config: &config.Configuration{
 				AccountDefaults: config.Account{Disabled: false},
 			},
@@ -381,6 +397,7 @@ func TestCookieSyncHandle(t *testing.T) {
 			pbsAnalytics:    &mockAnalytics,
 			accountsFetcher: &fakeAccountFetcher,
 			time:            &fakeTime{time: time.Date(2024, 2, 22, 9, 42, 4, 13, time.UTC)},
+			planBuilder:     hooks.EmptyPlanBuilder{},
 		}
 		assert.NoError(t, endpoint.config.MarshalAccountDefaults())
 
@@ -403,6 +420,85 @@ func TestCookieSyncHandle(t *testing.T) {
 	}
 }
 
+func TestCookieSyncHandleUIDStore(t *testing.T) {
+	stored := usersync.NewCookie()
+	stored.Sync("aSyncer", "storedID")
+
+	testCases := []struct {
+		description        string
+		givenFirstPartyID  string
+		expectedStoredSync bool
+		expectedNewID      bool
+	}{
+		{
+			description:        "Known first-party ID merges the stored UIDs",
+			givenFirstPartyID:  "user1",
+			expectedStoredSync: true,
+			expectedNewID:      false,
+		},
+		{
+			description:        "Missing first-party ID sets a new one",
+			givenFirstPartyID:  "",
+			expectedStoredSync: false,
+			expectedNewID:      true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			uidStoreCfg := config.UIDStore{Enabled: true, IDCookieName: "pbs_fpid", TTLSeconds: 60, MaxUsers: 10}
+			store := usersync.NewUIDStore(uidStoreCfg)
+			assert.NoError(t, store.Save(context.Background(), "user1", stored))
+
+			mockMetrics := metrics.MetricsEngineMock{}
+			mockMetrics.On("RecordCookieSync", mock.Anything).Maybe()
+			mockAnalytics := MockAnalyticsRunner{}
+			mockAnalytics.On("LogCookieSyncObject", mock.Anything).Maybe()
+
+			chooser := &recordingChooser{result: usersync.Result{Status: usersync.StatusOK}}
+			endpoint := cookieSyncEndpoint{
+				uidsDecoder: usersync.Base64Decoder{},
+				chooser:     chooser,
+				config: &config.Configuration{
+					AccountDefaults: config.Account{Disabled: false},
+					UIDStore:        uidStoreCfg,
+				},
+				privacyConfig: usersyncPrivacyConfig{
+					gdprConfig: config.GDPR{
+						Enabled:      true,
+						DefaultValue: "0",
+					},
+					gdprPermissionsBuilder: fakePermissionsBuilder{permissions: &fakePermissions{}}.Builder,
+					tcf2ConfigBuilder: fakeTCF2ConfigBuilder{
+						cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+					}.Builder,
+				},
+				metrics:         &mockMetrics,
+				pbsAnalytics:    &mockAnalytics,
+				accountsFetcher: &FakeAccountsFetcher{},
+				time:            &fakeTime{time: time.Date(2024, 2, 22, 9, 42, 4, 13, time.UTC)},
+				uidStore:        store,
+				planBuilder:     hooks.EmptyPlanBuilder{},
+			}
+			assert.NoError(t, endpoint.config.MarshalAccountDefaults())
+
+			request := httptest.NewRequest("POST", "/cookiesync", strings.NewReader(`{}`))
+			if test.givenFirstPartyID != "" {
+				request.AddCookie(&http.Cookie{Name: "pbs_fpid", Value: test.givenFirstPartyID})
+			}
+			writer := httptest.NewRecorder()
+
+			endpoint.Handle(writer, request, nil)
+
+			assert.Equal(t, http.StatusOK, writer.Code)
+			if assert.NotNil(t, chooser.cookie) {
+				assert.Equal(t, test.expectedStoredSync, chooser.cookie.HasLiveSync("aSyncer"), "stored sync")
+			}
+			assert.Equal(t, test.expectedNewID, strings.Contains(writer.Header().Get("Set-Cookie"), "pbs_fpid="), "first-party id cookie")
+		})
+	}
+}
+
 func TestExtractGDPRSignal(t *testing.T) {
 	type testInput struct {
 		requestGDPR *int
@@ -490,6 +586,8 @@ func TestExtractGDPRSignal(t *testing.T) {
 }
 
 func TestExtractPrivacyPolicies(t *testing.T) {
+	gppTCF2USP, _ := gpplib.Parse("DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN")
+
 	type testInput struct {
 		request                  cookieSyncRequest
 		usersyncDefaultGDPRValue string
@@ -566,7 +664,7 @@ func TestExtractPrivacyPolicies(t *testing.T) {
 					GPPSID:      "6",
 				},
 				gdprSignal: gdpr.SignalNo,
-				policies:   privacy.Policies{GPPSID: []int8{6}},
+				policies:   privacy.Policies{GPPSID: []int8{6}, GPP: gppTCF2USP},
 				err:        nil,
 			},
 		},
@@ -584,6 +682,7 @@ func TestExtractPrivacyPolicies(t *testing.T) {
 func TestCookieSyncParseRequest(t *testing.T) {
 	expectedCCPAParsedPolicy, _ := ccpa.Policy{Consent: "1NYN"}.Parse(map[string]struct{}{})
 	emptyActivityPoliciesRequest := privacy.NewRequestFromPolicies(privacy.Policies{})
+	gppTCF2, _ := gpplib.Parse("DBABMA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA")
 
 	testCases := []struct {
 		description          string
@@ -626,6 +725,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				GPPSID:      "2",
 			},
 			expectedRequest: usersync.Request{
+				Account: "unknown",
 				Bidders: []string{"a", "b"},
 				Cooperative: usersync.Cooperative{
 					Enabled:        true,
@@ -635,7 +735,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				Privacy: usersyncPrivacy{
 					gdprPermissions:  &fakePermissions{},
 					ccpaParsedPolicy: expectedCCPAParsedPolicy,
-					activityRequest:  privacy.NewRequestFromPolicies(privacy.Policies{GPPSID: []int8{2}}),
+					activityRequest:  privacy.NewRequestFromPolicies(privacy.Policies{GPPSID: []int8{2}, GPP: gppTCF2}),
 					gdprSignal:       1,
 				},
 				SyncTypeFilter: usersync.SyncTypeFilter{
@@ -668,6 +768,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				USPrivacy:   "1NYN",
 			},
 			expectedRequest: usersync.Request{
+				Account: "unknown",
 				Bidders: []string{"a", "b"},
 				Cooperative: usersync.Cooperative{
 					Enabled:        false,
@@ -693,7 +794,8 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			givenCCPAEnabled: true,
 			expectedPrivacy:  macros.UserSyncPrivacy{},
 			expectedRequest: usersync.Request{
-				Limit: math.MaxInt,
+				Account: "unknown",
+				Limit:   math.MaxInt,
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
 					activityRequest: emptyActivityPoliciesRequest,
@@ -718,6 +820,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			},
 			expectedPrivacy: macros.UserSyncPrivacy{},
 			expectedRequest: usersync.Request{
+				Account: "unknown",
 				Cooperative: usersync.Cooperative{
 					Enabled:        true,
 					PriorityGroups: [][]string{{"a", "b", "c"}},
@@ -747,6 +850,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			},
 			expectedPrivacy: macros.UserSyncPrivacy{},
 			expectedRequest: usersync.Request{
+				Account: "unknown",
 				Cooperative: usersync.Cooperative{
 					Enabled:        false,
 					PriorityGroups: [][]string{{"a", "b", "c"}},
@@ -776,6 +880,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			},
 			expectedPrivacy: macros.UserSyncPrivacy{},
 			expectedRequest: usersync.Request{
+				Account: "unknown",
 				Cooperative: usersync.Cooperative{
 					Enabled:        false,
 					PriorityGroups: [][]string{{"a", "b", "c"}},
@@ -805,6 +910,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			},
 			expectedPrivacy: macros.UserSyncPrivacy{},
 			expectedRequest: usersync.Request{
+				Account: "unknown",
 				Cooperative: usersync.Cooperative{
 					Enabled:        false,
 					PriorityGroups: [][]string{{"a", "b", "c"}},
@@ -834,6 +940,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			},
 			expectedPrivacy: macros.UserSyncPrivacy{},
 			expectedRequest: usersync.Request{
+				Account: "unknown",
 				Cooperative: usersync.Cooperative{
 					Enabled:        true,
 					PriorityGroups: [][]string{{"a", "b", "c"}},
@@ -863,6 +970,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			},
 			expectedPrivacy: macros.UserSyncPrivacy{},
 			expectedRequest: usersync.Request{
+				Account: "unknown",
 				Cooperative: usersync.Cooperative{
 					Enabled:        true,
 					PriorityGroups: [][]string{{"a", "b", "c"}},
@@ -886,7 +994,8 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			givenCCPAEnabled: true,
 			expectedPrivacy:  macros.UserSyncPrivacy{},
 			expectedRequest: usersync.Request{
-				Limit: math.MaxInt,
+				Account: "unknown",
+				Limit:   math.MaxInt,
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
 					activityRequest: emptyActivityPoliciesRequest,
@@ -907,7 +1016,8 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				USPrivacy: "1NYN",
 			},
 			expectedRequest: usersync.Request{
-				Limit: math.MaxInt,
+				Account: "unknown",
+				Limit:   math.MaxInt,
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
 					activityRequest: emptyActivityPoliciesRequest,
@@ -949,7 +1059,8 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				GDPR: "0",
 			},
 			expectedRequest: usersync.Request{
-				Limit: math.MaxInt,
+				Account: "unknown",
+				Limit:   math.MaxInt,
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
 					activityRequest: emptyActivityPoliciesRequest,
@@ -977,7 +1088,8 @@ func TestCookieSyncParseRequest(t *testing.T) {
 				GDPR: "",
 			},
 			expectedRequest: usersync.Request{
-				Limit: math.MaxInt,
+				Account: "unknown",
+				Limit:   math.MaxInt,
 				Privacy: usersyncPrivacy{
 					gdprPermissions: &fakePermissions{},
 					activityRequest: emptyActivityPoliciesRequest,
@@ -1018,6 +1130,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			},
 			expectedPrivacy: macros.UserSyncPrivacy{},
 			expectedRequest: usersync.Request{
+				Account: "TestAccount",
 				Bidders: []string{"a", "b"},
 				Cooperative: usersync.Cooperative{
 					Enabled:        true,
@@ -1051,6 +1164,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			},
 			expectedPrivacy: macros.UserSyncPrivacy{},
 			expectedRequest: usersync.Request{
+				Account: "TestAccount",
 				Bidders: []string{"a", "b"},
 				Cooperative: usersync.Cooperative{
 					Enabled:        true,
@@ -1084,6 +1198,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			},
 			expectedPrivacy: macros.UserSyncPrivacy{},
 			expectedRequest: usersync.Request{
+				Account: "DisabledAccount",
 				Bidders: []string{"a", "b"},
 				Cooperative: usersync.Cooperative{
 					Enabled:        true,
@@ -1133,7 +1248,7 @@ func TestCookieSyncParseRequest(t *testing.T) {
 			}},
 		}
 		assert.NoError(t, endpoint.config.MarshalAccountDefaults())
-		request, privacyPolicies, _, err := endpoint.parseRequest(httpRequest)
+		request, privacyPolicies, _, err := endpoint.parseRequest(httpRequest, hookexecution.EmptyHookExecutor{})
 
 		if test.expectedError == "" {
 			assert.NoError(t, err, test.description+":err")
@@ -1812,6 +1927,7 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 		givenCookieHasSyncs bool
 		givenSyncersChosen  []usersync.SyncerChoice
 		givenDebug          bool
+		givenRanking        []usersync.BidderRanking
 		expectedJSON        string
 		expectedAnalytics   analytics.CookieSyncObject
 	}{
@@ -1897,6 +2013,27 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 			expectedJSON:        `{"status":"ok","bidder_status":[],"debug":[{"bidder":"Bidder1","error":"Already in sync"},{"bidder":"Bidder2","error":"Unsupported bidder"},{"bidder":"Bidder3","error":"No sync config"},{"bidder":"Bidder4","error":"Rejected by privacy"},{"bidder":"Bidder5","error":"Rejected by request filter"},{"bidder":"Bidder6","error":"Status blocked by user opt out"},{"bidder":"Bidder7","error":"Sync disabled by config"},{"bidder":"BidderA","error":"Duplicate bidder synced as syncerB"}]}` + "\n",
 			expectedAnalytics:   analytics.CookieSyncObject{Status: 200, BidderStatus: []*analytics.CookieSyncBidder{}},
 		},
+		{
+			description:         "Debug is true with value ranking, should see the ranking explained",
+			givenCookieHasSyncs: true,
+			givenDebug:          true,
+			givenSyncersChosen:  []usersync.SyncerChoice{},
+			givenRanking: []usersync.BidderRanking{
+				{Bidder: "a", Score: 1.5, ValueWithUID: 2, ValueWithoutUID: 0.5, SyncSuccessRate: 0.75, UIDAge: 36 * time.Hour},
+				{Bidder: "b", Score: 1, ValueEstimated: true, SyncSuccessRate: 1},
+			},
+			expectedJSON: `{"status":"ok","bidder_status":[],"debug":[{"bidder":"Bidder1","error":"Already in sync"},{"bidder":"Bidder2","error":"Unsupported bidder"},{"bidder":"Bidder3","error":"No sync config"},{"bidder":"Bidder4","error":"Rejected by privacy"},{"bidder":"Bidder5","error":"Rejected by request filter"},{"bidder":"Bidder6","error":"Status blocked by user opt out"},{"bidder":"Bidder7","error":"Sync disabled by config"},{"bidder":"BidderA","error":"Duplicate bidder synced as syncerB"}],` +
+				`"ranking":[{"bidder":"a","score":1.5,"value_with_uid":2,"value_without_uid":0.5,"sync_success_rate":0.75,"uid_age_hours":36},{"bidder":"b","score":1,"value_with_uid":0,"value_without_uid":0,"value_estimated":true,"sync_success_rate":1}]}` + "\n",
+			expectedAnalytics: analytics.CookieSyncObject{Status: 200, BidderStatus: []*analytics.CookieSyncBidder{}},
+		},
+		{
+			description:         "Debug is false with value ranking, should not see the ranking",
+			givenCookieHasSyncs: true,
+			givenSyncersChosen:  []usersync.SyncerChoice{},
+			givenRanking:        []usersync.BidderRanking{{Bidder: "a", Score: 1.5}},
+			expectedJSON:        `{"status":"ok","bidder_status":[]}` + "\n",
+			expectedAnalytics:   analytics.CookieSyncObject{Status: 200, BidderStatus: []*analytics.CookieSyncBidder{}},
+		},
 	}
 
 	for _, test := range testCases {
@@ -1919,7 +2056,7 @@ func TestCookieSyncHandleResponse(t *testing.T) {
 		} else {
 			bidderEval = []usersync.BidderEvaluation{}
 		}
-		endpoint.handleResponse(writer, syncTypeFilter, cookie, privacyMacros, test.givenSyncersChosen, bidderEval, test.givenDebug)
+		endpoint.handleResponse(writer, syncTypeFilter, cookie, privacyMacros, test.givenSyncersChosen, bidderEval, test.givenDebug, test.givenRanking, nil)
 
 		if assert.Equal(t, writer.Code, http.StatusOK, test.description+":http_status") {
 			assert.Equal(t, writer.Header().Get("Content-Type"), "application/json; charset=utf-8", test.description+":http_header")
@@ -2248,6 +2385,16 @@ func (c FakeChooser) Choose(request usersync.Request, cookie *usersync.Cookie) u
 	return c.Result
 }
 
+type recordingChooser struct {
+	result usersync.Result
+	cookie *usersync.Cookie
+}
+
+func (c *recordingChooser) Choose(request usersync.Request, cookie *usersync.Cookie) usersync.Result {
+	c.cookie = cookie
+	return c.result
+}
+
 type MockSyncer struct {
 	mock.Mock
 }
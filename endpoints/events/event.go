@@ -17,6 +17,9 @@ import (
 	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/privacy"
 	"github.com/prebid/prebid-server/v3/stored_requests"
@@ -47,15 +50,22 @@ type eventEndpoint struct {
 	Cfg           *config.Configuration
 	TrackingPixel *httputil.Pixel
 	MetricsEngine metrics.MetricsEngine
+	PlanBuilder   hooks.ExecutionPlanBuilder
 }
 
 func NewEventEndpoint(cfg *config.Configuration, accounts stored_requests.AccountFetcher, analytics analytics.Runner, me metrics.MetricsEngine) httprouter.Handle {
+	return NewEventEndpointWithHooks(cfg, accounts, analytics, me, hooks.EmptyPlanBuilder{})
+}
+
+// NewEventEndpointWithHooks builds the event endpoint, running the event_request hooks of the plan.
+func NewEventEndpointWithHooks(cfg *config.Configuration, accounts stored_requests.AccountFetcher, analytics analytics.Runner, me metrics.MetricsEngine, planBuilder hooks.ExecutionPlanBuilder) httprouter.Handle {
 	ee := &eventEndpoint{
 		Accounts:      accounts,
 		Analytics:     analytics,
 		Cfg:           cfg,
 		TrackingPixel: &httputil.Pixel1x1PNG,
 		MetricsEngine: me,
+		PlanBuilder:   planBuilder,
 	}
 
 	return ee.Handle
@@ -119,11 +129,18 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 
 	activities := privacy.NewActivityControl(&account.Privacy)
 
-	// handle notification event
-	e.Analytics.LogNotificationEventObject(&analytics.NotificationEvent{
-		Request: eventRequest,
-		Account: account,
-	}, activities)
+	hookExecutor := hookexecution.NewHookExecutor(e.PlanBuilder, hookexecution.EndpointEvent, e.MetricsEngine)
+	hookExecutor.SetAccount(account)
+	hookExecutor.SetActivityControl(activities)
+
+	// handle notification event, unless a module dropped it
+	if rejectErr := executeEventRequestStage(r, eventRequest, hookExecutor); rejectErr == nil {
+		e.Analytics.LogNotificationEventObject(&analytics.NotificationEvent{
+			Request:              eventRequest,
+			Account:              account,
+			HookExecutionOutcome: hookExecutor.GetOutcomes(),
+		}, activities)
+	}
 
 	// Add tracking pixel if format == image
 	if eventRequest.Format == analytics.Image {
@@ -137,6 +154,30 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// executeEventRequestStage runs the event_request hooks, which may change the event in place.
+func executeEventRequestStage(r *http.Request, event *analytics.EventRequest, hookExecutor hookexecution.HookStageExecutor) *hookexecution.RejectError {
+	payload, rejectErr := hookExecutor.ExecuteEventRequestStage(hookstage.EventRequestPayload{
+		Request:     r,
+		Type:        string(event.Type),
+		VType:       string(event.VType),
+		BidID:       event.BidID,
+		AccountID:   event.AccountID,
+		Bidder:      event.Bidder,
+		Timestamp:   event.Timestamp,
+		Integration: event.Integration,
+	})
+
+	event.Type = analytics.EventType(payload.Type)
+	event.VType = analytics.VastType(payload.VType)
+	event.BidID = payload.BidID
+	event.AccountID = payload.AccountID
+	event.Bidder = payload.Bidder
+	event.Timestamp = payload.Timestamp
+	event.Integration = payload.Integration
+
+	return rejectErr
+}
+
 // EventRequestToUrl converts an analytics.EventRequest to an URL
 func EventRequestToUrl(externalUrl string, request *analytics.EventRequest) string {
 	s := fmt.Sprintf(TemplateUrl, externalUrl, request.Type, request.BidID, request.AccountID)
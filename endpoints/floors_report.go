@@ -0,0 +1,31 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/floorsreport"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+type floorsReporter interface {
+	Report(accountID string) floorsreport.Report
+}
+
+// NewFloorsReportEndpoint returns the price floors outcomes aggregated per account and model version.
+// The optional "account" query parameter limits the report to a single account.
+func NewFloorsReportEndpoint(reporter floorsReporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := reporter.Report(r.URL.Query().Get("account"))
+
+		jsonOutput, err := jsonutil.Marshal(report)
+		if err != nil {
+			glog.Errorf("/floors/report Critical error when trying to marshal the floors report: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
@@ -0,0 +1,67 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/floorsreport"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockFloorsReporter struct {
+	accountID string
+}
+
+func (m *mockFloorsReporter) Report(accountID string) floorsreport.Report {
+	m.accountID = accountID
+	return floorsreport.Report{
+		Since:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Currency: "USD",
+		Models: []floorsreport.ModelReport{
+			{
+				Account:      "acct",
+				ModelVersion: "model_a",
+				Requests:     2,
+				WinningBids:  1,
+				Revenue:      0.002,
+				Rules:        []floorsreport.RuleReport{{Rule: "banner|*", WinningBids: 1, Revenue: 0.002}},
+			},
+		},
+	}
+}
+
+func TestFloorsReportEndpoint(t *testing.T) {
+	testCases := []struct {
+		description       string
+		url               string
+		expectedAccountID string
+	}{
+		{
+			description:       "all-accounts",
+			url:               "/floors/report",
+			expectedAccountID: "",
+		},
+		{
+			description:       "single-account",
+			url:               "/floors/report?account=acct",
+			expectedAccountID: "acct",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			reporter := &mockFloorsReporter{}
+			handler := NewFloorsReportEndpoint(reporter)
+
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(http.MethodGet, test.url, nil))
+
+			assert.Equal(t, test.expectedAccountID, reporter.accountID)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.JSONEq(t, `{"since":"2024-03-01T12:00:00Z","currency":"USD","models":[{"account":"acct","model_version":"model_a","requests":2,"skipped":0,"rejected_bids":0,"winning_bids":1,"revenue":0.002,"rules":[{"rule":"banner|*","rejected_bids":0,"winning_bids":1,"revenue":0.002}]}]}`, w.Body.String())
+		})
+	}
+}
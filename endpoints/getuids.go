@@ -3,6 +3,7 @@ package endpoints
 import (
 	"net/http"
 
+	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/usersync"
@@ -15,11 +16,15 @@ type userSyncs struct {
 }
 
 // NewGetUIDsEndpoint implements the /getuid endpoint which
-// returns all the existing syncs for the user
-func NewGetUIDsEndpoint(cfg config.HostCookie) httprouter.Handle {
+// returns all the existing syncs for the user, including the ones in the UID store
+func NewGetUIDsEndpoint(cfg config.HostCookie, uidStoreCfg config.UIDStore, uidStore usersync.UIDStore) httprouter.Handle {
+	decoder := usersync.NewDecoder(&cfg)
 	return httprouter.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-		cookie := usersync.ReadCookie(r, usersync.Base64Decoder{}, &cfg)
+		cookie := usersync.ReadCookie(r, decoder, &cfg)
 		usersync.SyncHostCookie(r, cookie, &cfg)
+		if err := usersync.ReadStoredUIDs(r.Context(), r, cookie, uidStore, &uidStoreCfg); err != nil {
+			glog.Warningf("/getuids failed to read the stored UIDs: %v", err)
+		}
 
 		userSyncs := new(userSyncs)
 		userSyncs.BuyerUIDs = cookie.GetUIDs()
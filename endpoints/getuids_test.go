@@ -1,17 +1,19 @@
 package endpoints
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetUIDs(t *testing.T) {
 	req := makeRequest("/getuids", map[string]string{"adnxs": "123", "audienceNetwork": "456"})
-	endpoint := NewGetUIDsEndpoint(config.HostCookie{})
+	endpoint := NewGetUIDsEndpoint(config.HostCookie{}, config.UIDStore{}, nil)
 	res := httptest.NewRecorder()
 	endpoint(res, req, nil)
 
@@ -22,7 +24,7 @@ func TestGetUIDs(t *testing.T) {
 
 func TestGetUIDsWithNoSyncs(t *testing.T) {
 	req := makeRequest("/getuids", map[string]string{})
-	endpoint := NewGetUIDsEndpoint(config.HostCookie{})
+	endpoint := NewGetUIDsEndpoint(config.HostCookie{}, config.UIDStore{}, nil)
 	res := httptest.NewRecorder()
 	endpoint(res, req, nil)
 
@@ -32,10 +34,28 @@ func TestGetUIDsWithNoSyncs(t *testing.T) {
 
 func TestGetUIDWIthNoCookie(t *testing.T) {
 	req := httptest.NewRequest("GET", "/getuids", nil)
-	endpoint := NewGetUIDsEndpoint(config.HostCookie{})
+	endpoint := NewGetUIDsEndpoint(config.HostCookie{}, config.UIDStore{}, nil)
 	res := httptest.NewRecorder()
 	endpoint(res, req, nil)
 
 	assert.Equal(t, http.StatusOK, res.Code)
 	assert.JSONEq(t, `{}`, res.Body.String(), "GetUIDs endpoint shouldn't return anything if there doesn't exist a PBS cookie")
 }
+
+func TestGetUIDsWithStoredUIDs(t *testing.T) {
+	uidStoreCfg := config.UIDStore{Enabled: true, IDCookieName: "pbs_fpid", TTLSeconds: 60, MaxUsers: 10}
+	store := usersync.NewUIDStore(uidStoreCfg)
+	stored := usersync.NewCookie()
+	stored.Sync("audienceNetwork", "456")
+	assert.NoError(t, store.Save(context.Background(), "user1", stored))
+
+	req := makeRequest("/getuids", map[string]string{"adnxs": "123"})
+	req.AddCookie(&http.Cookie{Name: "pbs_fpid", Value: "user1"})
+	endpoint := NewGetUIDsEndpoint(config.HostCookie{}, uidStoreCfg, store)
+	res := httptest.NewRecorder()
+	endpoint(res, req, nil)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"buyeruids": {"adnxs": "123", "audienceNetwork": "456"}}`,
+		res.Body.String(), "GetUIDs endpoint should merge the stored user IDs")
+}
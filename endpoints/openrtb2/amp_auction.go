@@ -71,6 +71,7 @@ func NewAmpEndpoint(
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	uidStore usersync.UIDStore,
 ) (httprouter.Handle, error) {
 
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
@@ -105,6 +106,8 @@ func NewAmpEndpoint(
 		hookExecutionPlanBuilder,
 		tmaxAdjustments,
 		openrtb_ext.NormalizeBidderName,
+		uidStore,
+		usersync.NewDecoder(&cfg.HostCookie),
 	}).AmpAuction), nil
 
 }
@@ -190,8 +193,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	defer cancel()
 
 	// Read UserSyncs/Cookie from Request
-	usersyncs := usersync.ReadCookie(r, usersync.Base64Decoder{}, &deps.cfg.HostCookie)
-	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
+	usersyncs := deps.readUserSyncs(ctx, r)
 	if usersyncs.HasAnyLiveSyncs() {
 		labels.CookieFlag = metrics.CookieFlagYes
 	} else {
@@ -404,7 +406,8 @@ func sendAmpResponse(
 	ao.AmpTargetingValues = targets
 
 	// Fixes #231
-	enc := json.NewEncoder(w) // nosemgrep: json-encoder-needs-type
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body) // nosemgrep: json-encoder-needs-type
 	enc.SetEscapeHTML(false)
 	// Explicitly set content type to text/plain, which had previously been
 	// the implied behavior from the time the project was launched.
@@ -412,10 +415,15 @@ func sendAmpResponse(
 	// nevertheless we will keep it as such for compatibility reasons.
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 
-	// If an error happens when encoding the response, there isn't much we can do.
+	// If an error happens when encoding or sending the response, there isn't much we can do.
 	// If we've sent _any_ bytes, then Go would have sent the 200 status code first.
 	// That status code can't be un-sent... so the best we can do is log the error.
-	if err := enc.Encode(ampResponse); err != nil {
+	err := enc.Encode(ampResponse)
+	if err == nil {
+		err = writeResponse(w, hookExecutor, body.Bytes())
+		ao.HookExecutionOutcome = hookExecutor.GetOutcomes()
+	}
+	if err != nil {
 		labels.RequestStatus = metrics.RequestStatusNetworkErr
 		ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/amp Failed to send response: %v", err))
 	}
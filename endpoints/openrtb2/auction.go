@@ -1,6 +1,7 @@
 package openrtb2
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
@@ -39,6 +40,7 @@ import (
 	"github.com/prebid/prebid-server/v3/exchange"
 	"github.com/prebid/prebid-server/v3/gdpr"
 	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/prebid_cache_client"
@@ -98,7 +100,32 @@ func NewEndpoint(
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	uidStore usersync.UIDStore,
 ) (httprouter.Handle, error) {
+	deps, err := newEndpointDeps(uuidGenerator, ex, requestValidator, requestsById, accounts, cfg, metricsEngine, analyticsRunner, disabledBidders, defReqJSON, bidderMap, storedRespFetcher, hookExecutionPlanBuilder, tmaxAdjustments, uidStore)
+	if err != nil {
+		return nil, err
+	}
+	return httprouter.Handle(deps.Auction), nil
+}
+
+func newEndpointDeps(
+	uuidGenerator uuidutil.UUIDGenerator,
+	ex exchange.Exchange,
+	requestValidator ortb.RequestValidator,
+	requestsById stored_requests.Fetcher,
+	accounts stored_requests.AccountFetcher,
+	cfg *config.Configuration,
+	metricsEngine metrics.MetricsEngine,
+	analyticsRunner analytics.Runner,
+	disabledBidders map[string]string,
+	defReqJSON []byte,
+	bidderMap map[string]openrtb_ext.BidderName,
+	storedRespFetcher stored_requests.Fetcher,
+	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
+	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	uidStore usersync.UIDStore,
+) (*endpointDeps, error) {
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
 		return nil, errors.New("NewEndpoint requires non-nil arguments.")
 	}
@@ -110,7 +137,7 @@ func NewEndpoint(
 		IPv6PrivateNetworks: cfg.RequestValidation.IPv6PrivateNetworksParsed,
 	}
 
-	return httprouter.Handle((&endpointDeps{
+	return &endpointDeps{
 		uuidGenerator,
 		ex,
 		requestValidator,
@@ -130,7 +157,9 @@ func NewEndpoint(
 		storedRespFetcher,
 		hookExecutionPlanBuilder,
 		tmaxAdjustments,
-		openrtb_ext.NormalizeBidderName}).Auction), nil
+		openrtb_ext.NormalizeBidderName,
+		uidStore,
+		usersync.NewDecoder(&cfg.HostCookie)}, nil
 }
 
 type endpointDeps struct {
@@ -154,9 +183,32 @@ type endpointDeps struct {
 	hookExecutionPlanBuilder  hooks.ExecutionPlanBuilder
 	tmaxAdjustments           *exchange.TmaxAdjustmentsPreprocessed
 	normalizeBidderName       openrtb_ext.BidderNameNormalizer
+	uidStore                  usersync.UIDStore
+	uidsDecoder               usersync.Decoder
 }
 
-func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+// readUserSyncs reads the uids cookie of the request, along with the UIDs stored server side for the user.
+func (deps *endpointDeps) readUserSyncs(ctx context.Context, r *http.Request) *usersync.Cookie {
+	usersyncs := usersync.ReadCookie(r, deps.uidsDecoder, &deps.cfg.HostCookie)
+	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
+	if err := usersync.ReadStoredUIDs(ctx, r, usersyncs, deps.uidStore, &deps.cfg.UIDStore); err != nil {
+		glog.Warningf("Failed to read the stored UIDs: %v", err)
+	}
+	return usersyncs
+}
+
+// auctionState tracks an auction from the moment its request is received, for the metrics and analytics
+// recorded once it is over. It is shared by the HTTP and gRPC endpoints.
+type auctionState struct {
+	start           time.Time
+	hookExecutor    hookexecution.HookStageExecutor
+	labels          metrics.Labels
+	ao              analytics.AuctionObject
+	activityControl privacy.ActivityControl
+	auctionRequest  *exchange.AuctionRequest
+}
+
+func (deps *endpointDeps) newAuctionState() *auctionState {
 	// Prebid Server interprets request.tmax to be the maximum amount of time that a caller is willing
 	// to wait for bids. However, tmax may be defined in the Stored Request data.
 	//
@@ -165,139 +217,175 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	// to compute the auction timeout.
 	start := time.Now()
 
-	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
-
-	ao := analytics.AuctionObject{
-		Status:    http.StatusOK,
-		Errors:    make([]error, 0),
-		StartTime: start,
+	return &auctionState{
+		start:        start,
+		hookExecutor: hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine),
+		labels: metrics.Labels{
+			Source:        metrics.DemandUnknown,
+			RType:         metrics.ReqTypeORTB2Web,
+			PubID:         metrics.PublisherUnknown,
+			CookieFlag:    metrics.CookieFlagUnknown,
+			RequestStatus: metrics.RequestStatusOK,
+		},
+		ao: analytics.AuctionObject{
+			Status:    http.StatusOK,
+			Errors:    make([]error, 0),
+			StartTime: start,
+		},
 	}
+}
 
-	labels := metrics.Labels{
-		Source:        metrics.DemandUnknown,
-		RType:         metrics.ReqTypeORTB2Web,
-		PubID:         metrics.PublisherUnknown,
-		CookieFlag:    metrics.CookieFlagUnknown,
-		RequestStatus: metrics.RequestStatusOK,
+// finishAuction records the metrics and logs the analytics object of the auction.
+func (deps *endpointDeps) finishAuction(a *auctionState) {
+	if a.auctionRequest != nil && !a.auctionRequest.BidderResponseStartTime.IsZero() {
+		deps.metricsEngine.RecordOverheadTime(metrics.MakeAuctionResponse, time.Since(a.auctionRequest.BidderResponseStartTime))
 	}
+	deps.metricsEngine.RecordRequest(a.labels)
+	deps.metricsEngine.RecordRequestTime(a.labels, time.Since(a.start))
+	deps.analytics.LogAuctionObject(&a.ao, a.activityControl)
+}
 
-	activityControl := privacy.ActivityControl{}
-	defer func() {
-		deps.metricsEngine.RecordRequest(labels)
-		deps.metricsEngine.RecordRequestTime(labels, time.Since(start))
-		deps.analytics.LogAuctionObject(&ao, activityControl)
-	}()
+func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	a := deps.newAuctionState()
+	defer deps.finishAuction(a)
 
 	w.Header().Set("X-Prebid", version.BuildXPrebidHeader(version.Ver))
 	setBrowsingTopicsHeader(w, r)
 
-	req, impExtInfoMap, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, account, errL := deps.parseRequest(r, &labels, hookExecutor)
-	if errortypes.ContainsFatalError(errL) && writeError(errL, w, &labels) {
+	parsed := newParsedRequest(deps.parseRequest(r, &a.labels, a.hookExecutor))
+	response, errL, err := deps.holdAuction(r, a, parsed)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "Critical error while running the auction: %v", err)
 		return
 	}
+	if writeError(errL, w, &a.labels) {
+		return
+	}
+	a.labels, a.ao = sendAuctionResponse(w, a.hookExecutor, response, parsed.req.BidRequest, parsed.account, a.labels, a.ao)
+}
+
+// parsedRequest holds the results of parseRequest.
+type parsedRequest struct {
+	req                    *openrtb_ext.RequestWrapper
+	impExtInfoMap          map[string]exchange.ImpExtInfo
+	storedAuctionResponses stored_responses.ImpsWithBidResponses
+	storedBidResponses     stored_responses.ImpBidderStoredResp
+	bidderImpReplaceImpId  stored_responses.BidderImpReplaceImpID
+	account                *config.Account
+	errs                   []error
+}
+
+func newParsedRequest(req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, errs []error) parsedRequest {
+	return parsedRequest{
+		req:                    req,
+		impExtInfoMap:          impExtInfoMap,
+		storedAuctionResponses: storedAuctionResponses,
+		storedBidResponses:     storedBidResponses,
+		bidderImpReplaceImpId:  bidderImpReplaceImpId,
+		account:                account,
+		errs:                   errs,
+	}
+}
+
+// holdAuction runs the auction for a parsed request. It returns either the response to send, which only
+// holds the no-bid reason if a hook rejected the request, the request errors to report as writeError does,
+// or the error which made the auction fail.
+func (deps *endpointDeps) holdAuction(r *http.Request, a *auctionState, parsed parsedRequest) (*openrtb2.BidResponse, []error, error) {
+	req, account, errL := parsed.req, parsed.account, parsed.errs
+	if errortypes.ContainsFatalError(errL) {
+		return nil, errL, nil
+	}
 
 	if rejectErr := hookexecution.FindFirstRejectOrNil(errL); rejectErr != nil {
-		ao.RequestWrapper = req
-		labels, ao = rejectAuctionRequest(*rejectErr, w, hookExecutor, req.BidRequest, account, labels, ao)
-		return
+		a.ao.RequestWrapper = req
+		return rejectAuctionRequest(*rejectErr, req.BidRequest, &a.ao), nil, nil
 	}
 
 	tcf2Config := gdpr.NewTCF2Config(deps.cfg.GDPR.TCF2, account.GDPR)
 
-	activityControl = privacy.NewActivityControl(&account.Privacy)
+	a.activityControl = privacy.NewActivityControl(&account.Privacy)
 
-	hookExecutor.SetActivityControl(activityControl)
-	hookExecutor.SetAccount(account)
+	a.hookExecutor.SetActivityControl(a.activityControl)
+	a.hookExecutor.SetAccount(account)
 
 	ctx := context.Background()
 
-	timeout := deps.cfg.AuctionTimeouts.LimitAuctionTimeout(time.Duration(req.TMax) * time.Millisecond)
+	timeout := deps.cfg.Current().AuctionTimeouts.LimitAuctionTimeout(time.Duration(req.TMax) * time.Millisecond)
 	if timeout > 0 {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithDeadline(ctx, start.Add(timeout))
+		ctx, cancel = context.WithDeadline(ctx, a.start.Add(timeout))
 		defer cancel()
 	}
 
 	// Read Usersyncs/Cookie
-	decoder := usersync.Base64Decoder{}
-	usersyncs := usersync.ReadCookie(r, decoder, &deps.cfg.HostCookie)
-	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
+	usersyncs := deps.readUserSyncs(ctx, r)
 
 	if req.Site != nil {
 		if usersyncs.HasAnyLiveSyncs() {
-			labels.CookieFlag = metrics.CookieFlagYes
+			a.labels.CookieFlag = metrics.CookieFlagYes
 		} else {
-			labels.CookieFlag = metrics.CookieFlagNo
+			a.labels.CookieFlag = metrics.CookieFlagNo
 		}
 	}
 
 	// Set Integration Information
 	err := deps.setIntegrationType(req, account)
 	if err != nil {
-		errL = append(errL, err)
-		writeError(errL, w, &labels)
-		return
+		return nil, append(errL, err), nil
 	}
 	secGPC := r.Header.Get("Sec-GPC")
 
 	warnings := errortypes.WarningOnly(errL)
 
-	auctionRequest := &exchange.AuctionRequest{
+	a.auctionRequest = &exchange.AuctionRequest{
 		BidRequestWrapper:          req,
 		Account:                    *account,
 		UserSyncs:                  usersyncs,
-		RequestType:                labels.RType,
-		StartTime:                  start,
-		LegacyLabels:               labels,
+		RequestType:                a.labels.RType,
+		StartTime:                  a.start,
+		LegacyLabels:               a.labels,
 		Warnings:                   warnings,
 		GlobalPrivacyControlHeader: secGPC,
-		ImpExtInfoMap:              impExtInfoMap,
-		StoredAuctionResponses:     storedAuctionResponses,
-		StoredBidResponses:         storedBidResponses,
-		BidderImpReplaceImpID:      bidderImpReplaceImp,
-		PubID:                      labels.PubID,
-		HookExecutor:               hookExecutor,
+		ImpExtInfoMap:              parsed.impExtInfoMap,
+		StoredAuctionResponses:     parsed.storedAuctionResponses,
+		StoredBidResponses:         parsed.storedBidResponses,
+		BidderImpReplaceImpID:      parsed.bidderImpReplaceImpId,
+		PubID:                      a.labels.PubID,
+		HookExecutor:               a.hookExecutor,
 		TCF2Config:                 tcf2Config,
-		Activities:                 activityControl,
+		Activities:                 a.activityControl,
 		TmaxAdjustments:            deps.tmaxAdjustments,
 	}
-	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, nil)
-	defer func() {
-		if !auctionRequest.BidderResponseStartTime.IsZero() {
-			deps.metricsEngine.RecordOverheadTime(metrics.MakeAuctionResponse, time.Since(auctionRequest.BidderResponseStartTime))
-		}
-	}()
-	ao.RequestWrapper = req
-	ao.Account = account
+	auctionResponse, err := deps.ex.HoldAuction(ctx, a.auctionRequest, nil)
+	a.ao.RequestWrapper = req
+	a.ao.Account = account
 	var response *openrtb2.BidResponse
 	if auctionResponse != nil {
 		response = auctionResponse.BidResponse
 	}
-	ao.Response = response
-	ao.SeatNonBid = auctionResponse.GetSeatNonBid()
+	a.ao.Response = response
+	a.ao.SeatNonBid = auctionResponse.GetSeatNonBid()
+	a.ao.Floors = auctionResponse.GetFloors()
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
 	if err != nil && !isRejectErr {
 		if errortypes.ReadCode(err) == errortypes.BadInputErrorCode {
-			writeError([]error{err}, w, &labels)
-			return
+			return nil, []error{err}, nil
 		}
-		labels.RequestStatus = metrics.RequestStatusErr
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprintf(w, "Critical error while running the auction: %v", err)
+		a.labels.RequestStatus = metrics.RequestStatusErr
 		glog.Errorf("/openrtb2/auction Critical error: %v", err)
-		ao.Status = http.StatusInternalServerError
-		ao.Errors = append(ao.Errors, err)
-		return
+		a.ao.Status = http.StatusInternalServerError
+		a.ao.Errors = append(a.ao.Errors, err)
+		return nil, nil, err
 	} else if isRejectErr {
-		labels, ao = rejectAuctionRequest(*rejectErr, w, hookExecutor, req.BidRequest, account, labels, ao)
-		return
+		return rejectAuctionRequest(*rejectErr, req.BidRequest, &a.ao), nil, nil
 	}
 
 	err = setSeatNonBidRaw(req, auctionResponse)
 	if err != nil {
 		glog.Errorf("Error setting seat non-bid: %v", err)
 	}
-	labels, ao = sendAuctionResponse(w, hookExecutor, response, req.BidRequest, account, labels, ao)
+	return response, nil, nil
 }
 
 // setSeatNonBidRaw is transitional function for setting SeatNonBid inside bidResponse.Ext
@@ -327,15 +415,8 @@ func setSeatNonBidRaw(request *openrtb_ext.RequestWrapper, auctionResponse *exch
 	return nil
 }
 
-func rejectAuctionRequest(
-	rejectErr hookexecution.RejectError,
-	w http.ResponseWriter,
-	hookExecutor hookexecution.HookStageExecutor,
-	request *openrtb2.BidRequest,
-	account *config.Account,
-	labels metrics.Labels,
-	ao analytics.AuctionObject,
-) (metrics.Labels, analytics.AuctionObject) {
+// rejectAuctionRequest returns the response to a request rejected by a hook.
+func rejectAuctionRequest(rejectErr hookexecution.RejectError, request *openrtb2.BidRequest, ao *analytics.AuctionObject) *openrtb2.BidResponse {
 	response := &openrtb2.BidResponse{NBR: openrtb3.NoBidReason(rejectErr.NBR).Ptr()}
 	if request != nil {
 		response.ID = request.ID
@@ -343,8 +424,7 @@ func rejectAuctionRequest(
 
 	ao.Response = response
 	ao.Errors = append(ao.Errors, rejectErr)
-
-	return sendAuctionResponse(w, hookExecutor, response, request, account, labels, ao)
+	return response
 }
 
 func sendAuctionResponse(
@@ -356,6 +436,39 @@ func sendAuctionResponse(
 	labels metrics.Labels,
 	ao analytics.AuctionObject,
 ) (metrics.Labels, analytics.AuctionObject) {
+	ao = finishAuctionResponse(hookExecutor, response, request, account, ao)
+
+	// Fixes #231
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	enc.SetEscapeHTML(false)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// If an error happens when encoding or sending the response, there isn't much we can do.
+	// If we've sent _any_ bytes, then Go would have sent the 200 status code first.
+	// That status code can't be un-sent... so the best we can do is log the error.
+	err := enc.Encode(response)
+	if err == nil {
+		err = writeResponse(w, hookExecutor, body.Bytes())
+		ao.HookExecutionOutcome = hookExecutor.GetOutcomes()
+	}
+	if err != nil {
+		labels.RequestStatus = metrics.RequestStatusNetworkErr
+		ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/auction Failed to send response: %v", err))
+	}
+
+	return labels, ao
+}
+
+// finishAuctionResponse runs the auction response stage and adds the outcomes of the hooks to the response.
+func finishAuctionResponse(
+	hookExecutor hookexecution.HookStageExecutor,
+	response *openrtb2.BidResponse,
+	request *openrtb2.BidRequest,
+	account *config.Account,
+	ao analytics.AuctionObject,
+) analytics.AuctionObject {
 	hookExecutor.ExecuteAuctionResponseStage(response)
 
 	if response != nil {
@@ -375,22 +488,35 @@ func sendAuctionResponse(
 			ao.Errors = append(ao.Errors, warns...)
 		}
 	}
+	return ao
+}
 
-	// Fixes #231
-	enc := json.NewEncoder(w)
-	enc.SetEscapeHTML(false)
+// writeResponse runs the exitpoint stage over the serialized response and writes
+// the status code, headers and body left by the module hooks to w.
+func writeResponse(w http.ResponseWriter, hookExecutor hookexecution.HookStageExecutor, body []byte) error {
+	response := hookExecutor.ExecuteExitpointStage(hookstage.ExitpointPayload{
+		StatusCode: http.StatusOK,
+		Headers:    w.Header().Clone(),
+		Body:       body,
+	})
 
-	w.Header().Set("Content-Type", "application/json")
+	headers := w.Header()
+	for key := range headers {
+		if _, ok := response.Headers[key]; !ok {
+			headers.Del(key)
+		}
+	}
+	for key, values := range response.Headers {
+		headers[key] = values
+	}
 
-	// If an error happens when encoding the response, there isn't much we can do.
-	// If we've sent _any_ bytes, then Go would have sent the 200 status code first.
-	// That status code can't be un-sent... so the best we can do is log the error.
-	if err := enc.Encode(response); err != nil {
-		labels.RequestStatus = metrics.RequestStatusNetworkErr
-		ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/auction Failed to send response: %v", err))
+	// WriteHeader panics on codes outside of the 1xx-9xx range, such codes are ignored
+	if response.StatusCode != http.StatusOK && response.StatusCode >= 100 && response.StatusCode <= 999 {
+		w.WriteHeader(response.StatusCode)
 	}
 
-	return labels, ao
+	_, err := w.Write(response.Body)
+	return err
 }
 
 // setBrowsingTopicsHeader always set the Observe-Browsing-Topics header to a value of ?1 if the Sec-Browsing-Topics is present in request
@@ -413,7 +539,6 @@ func setBrowsingTopicsHeader(w http.ResponseWriter, r *http.Request) {
 func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metrics.Labels, hookExecutor hookexecution.HookStageExecutor) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, errs []error) {
 	errs = nil
 	var err error
-	var errL []error
 	var r io.ReadCloser = httpRequest.Body
 	reqContentEncoding := httputil.ContentEncoding(httpRequest.Header.Get("Content-Encoding"))
 	if reqContentEncoding != "" {
@@ -452,6 +577,15 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 		}
 	}
 
+	return deps.parseRequestJSON(httpRequest, requestJson, labels, hookExecutor)
+}
+
+// parseRequestJSON is the part of parseRequest which follows the reading of the request body. It is shared
+// with the gRPC endpoint, for which httpRequest only holds the headers and the address of the client.
+func (deps *endpointDeps) parseRequestJSON(httpRequest *http.Request, requestJson []byte, labels *metrics.Labels, hookExecutor hookexecution.HookStageExecutor) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, errs []error) {
+	var err error
+	var errL []error
+
 	req = &openrtb_ext.RequestWrapper{}
 	req.BidRequest = &openrtb2.BidRequest{}
 
@@ -1212,7 +1346,7 @@ func (deps *endpointDeps) validateApp(req *openrtb_ext.RequestWrapper) error {
 	}
 
 	if req.App.ID != "" {
-		if _, found := deps.cfg.BlockedAppsLookup[req.App.ID]; found {
+		if _, found := deps.cfg.Current().BlockedAppsLookup[req.App.ID]; found {
 			return &errortypes.BlockedApp{Message: fmt.Sprintf("Prebid-server does not process requests from App ID: %s", req.App.ID)}
 		}
 	}
@@ -1866,20 +2000,7 @@ func setDoNotTrackImplicitly(httpReq *http.Request, r *openrtb_ext.RequestWrappe
 func writeError(errs []error, w http.ResponseWriter, labels *metrics.Labels) bool {
 	var rc bool = false
 	if len(errs) > 0 {
-		httpStatus := http.StatusBadRequest
-		metricsStatus := metrics.RequestStatusBadInput
-		for _, err := range errs {
-			erVal := errortypes.ReadCode(err)
-			if erVal == errortypes.BlockedAppErrorCode || erVal == errortypes.AccountDisabledErrorCode {
-				httpStatus = http.StatusServiceUnavailable
-				metricsStatus = metrics.RequestStatusBlockedApp
-				break
-			} else if erVal == errortypes.MalformedAcctErrorCode {
-				httpStatus = http.StatusInternalServerError
-				metricsStatus = metrics.RequestStatusAccountConfigErr
-				break
-			}
-		}
+		httpStatus, metricsStatus := errorStatus(errs)
 		w.WriteHeader(httpStatus)
 		labels.RequestStatus = metricsStatus
 		for _, err := range errs {
@@ -1890,6 +2011,19 @@ func writeError(errs []error, w http.ResponseWriter, labels *metrics.Labels) boo
 	return rc
 }
 
+// errorStatus returns the HTTP status and the metrics status of a request rejected because of errs.
+func errorStatus(errs []error) (int, metrics.RequestStatus) {
+	for _, err := range errs {
+		erVal := errortypes.ReadCode(err)
+		if erVal == errortypes.BlockedAppErrorCode || erVal == errortypes.AccountDisabledErrorCode {
+			return http.StatusServiceUnavailable, metrics.RequestStatusBlockedApp
+		} else if erVal == errortypes.MalformedAcctErrorCode {
+			return http.StatusInternalServerError, metrics.RequestStatusAccountConfigErr
+		}
+	}
+	return http.StatusBadRequest, metrics.RequestStatusBadInput
+}
+
 // Returns the account ID for the request
 func getAccountID(pub *openrtb2.Publisher) string {
 	if pub != nil {
@@ -0,0 +1,224 @@
+package openrtb2
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/endpoints/openrtb2/openrtbpb"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange"
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_responses"
+	"github.com/prebid/prebid-server/v3/usersync"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/prebid/prebid-server/v3/util/uuidutil"
+	"github.com/prebid/prebid-server/v3/version"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const grpcAuctionPath = "/openrtb2/auction"
+
+// GRPCAuctionServer serves auctions over gRPC, with the messages of the OpenRTB protobuf schema of the
+// openrtbpb package.
+//
+// The BidRequest message is mapped onto openrtb2.BidRequest and runs through the same pipeline as the requests
+// of /openrtb2/auction, from the parsing of the request to exchange.HoldAuction, with the same metrics labels
+// and analytics objects. The mapped request is serialized to JSON for the steps working on the raw request,
+// which are the entrypoint and raw auction hooks and the merge of the Stored Requests. The exitpoint stage
+// rewrites HTTP responses, so it doesn't run for gRPC auctions.
+type GRPCAuctionServer struct {
+	deps *endpointDeps
+}
+
+// NewGRPCAuctionServer takes the same arguments as NewEndpoint.
+func NewGRPCAuctionServer(
+	uuidGenerator uuidutil.UUIDGenerator,
+	ex exchange.Exchange,
+	requestValidator ortb.RequestValidator,
+	requestsById stored_requests.Fetcher,
+	accounts stored_requests.AccountFetcher,
+	cfg *config.Configuration,
+	metricsEngine metrics.MetricsEngine,
+	analyticsRunner analytics.Runner,
+	disabledBidders map[string]string,
+	defReqJSON []byte,
+	bidderMap map[string]openrtb_ext.BidderName,
+	storedRespFetcher stored_requests.Fetcher,
+	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
+	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	uidStore usersync.UIDStore,
+) (*GRPCAuctionServer, error) {
+	deps, err := newEndpointDeps(uuidGenerator, ex, requestValidator, requestsById, accounts, cfg, metricsEngine, analyticsRunner, disabledBidders, defReqJSON, bidderMap, storedRespFetcher, hookExecutionPlanBuilder, tmaxAdjustments, uidStore)
+	if err != nil {
+		return nil, err
+	}
+	return &GRPCAuctionServer{deps: deps}, nil
+}
+
+// RegisterGRPCAuctionServer registers the prebid.openrtb.Auction service served by auction.
+func RegisterGRPCAuctionServer(registrar grpc.ServiceRegistrar, auction *GRPCAuctionServer) {
+	registrar.RegisterService(&grpcAuctionServiceDesc, auction)
+}
+
+// grpcAuctionService is the handler type of the prebid.openrtb.Auction service.
+type grpcAuctionService interface {
+	Auction(ctx context.Context, request *dynamicpb.Message) (*dynamicpb.Message, error)
+}
+
+var grpcAuctionServiceDesc = grpc.ServiceDesc{
+	ServiceName: string(openrtbpb.AuctionService.FullName()),
+	HandlerType: (*grpcAuctionService)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Auction",
+			Handler:    grpcAuctionHandler,
+		},
+	},
+	Metadata: "prebid.proto",
+}
+
+func grpcAuctionHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	request := openrtbpb.NewBidRequest()
+	if err := dec(request); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(grpcAuctionService).Auction(ctx, request)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + string(openrtbpb.AuctionService.FullName()) + "/Auction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(grpcAuctionService).Auction(ctx, req.(*dynamicpb.Message))
+	}
+	return interceptor(ctx, request, info, handler)
+}
+
+func (s *GRPCAuctionServer) Auction(ctx context.Context, request *dynamicpb.Message) (*dynamicpb.Message, error) {
+	deps := s.deps
+	a := deps.newAuctionState()
+	defer deps.finishAuction(a)
+
+	httpRequest, err := newGRPCHTTPRequest(ctx)
+	if err != nil {
+		a.labels.RequestStatus = metrics.RequestStatusErr
+		return nil, status.Errorf(codes.Internal, "Failed to read the auction request: %v", err)
+	}
+
+	header := metadata.Pairs("X-Prebid", version.BuildXPrebidHeader(version.Ver))
+	if httpRequest.Header.Get(secBrowsingTopics) != "" {
+		header.Set(observeBrowsingTopics, observeBrowsingTopicsValue)
+	}
+	if err := grpc.SetHeader(ctx, header); err != nil {
+		glog.Errorf("Failed to set the gRPC auction response headers: %v", err)
+	}
+
+	parsed := newParsedRequest(deps.parseBidRequest(httpRequest, request, &a.labels, a.hookExecutor))
+	response, errL, err := deps.holdAuction(httpRequest, a, parsed)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Critical error while running the auction: %v", err)
+	}
+	if len(errL) > 0 {
+		httpStatus, metricsStatus := errorStatus(errL)
+		a.labels.RequestStatus = metricsStatus
+		return nil, status.Error(grpcCode(httpStatus), invalidRequestMessage(errL))
+	}
+
+	a.ao = finishAuctionResponse(a.hookExecutor, response, parsed.req.BidRequest, parsed.account, a.ao)
+	message, err := openrtbpb.FromBidResponse(response)
+	if err != nil {
+		a.labels.RequestStatus = metrics.RequestStatusErr
+		a.ao.Errors = append(a.ao.Errors, err)
+		return nil, status.Errorf(codes.Internal, "Failed to convert the auction response: %v", err)
+	}
+	return message, nil
+}
+
+// parseBidRequest is the parseRequest counterpart of the gRPC endpoint.
+func (deps *endpointDeps) parseBidRequest(httpRequest *http.Request, message *dynamicpb.Message, labels *metrics.Labels, hookExecutor hookexecution.HookStageExecutor) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, errs []error) {
+	bidRequest, err := openrtbpb.ToBidRequest(message)
+	if err != nil {
+		errs = []error{&errortypes.BadInput{Message: err.Error()}}
+		return
+	}
+
+	requestJson, err := jsonutil.Marshal(bidRequest)
+	if err != nil {
+		errs = []error{err}
+		return
+	}
+	return deps.parseRequestJSON(httpRequest, requestJson, labels, hookExecutor)
+}
+
+// newGRPCHTTPRequest returns the HTTP request the auction reads the headers and the address of the client from.
+// Its headers are the gRPC metadata, so that the user agent, cookies, Sec-GPC or X-Forwarded-For reach the
+// auction just like they would over HTTP.
+func newGRPCHTTPRequest(ctx context.Context) (*http.Request, error) {
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, grpcAuctionPath, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header = headersFromMetadata(ctx)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		httpRequest.RemoteAddr = p.Addr.String()
+	}
+	return httpRequest, nil
+}
+
+// headersFromMetadata copies the incoming gRPC metadata into HTTP headers, leaving out the pseudo headers and
+// the ones of the gRPC protocol.
+func headersFromMetadata(ctx context.Context) http.Header {
+	header := http.Header{}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return header
+	}
+	for key, values := range md {
+		if strings.HasPrefix(key, ":") || strings.HasPrefix(key, "grpc-") || key == "content-type" || key == "te" {
+			continue
+		}
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	return header
+}
+
+// invalidRequestMessage is the gRPC counterpart of the response body written by writeError.
+func invalidRequestMessage(errs []error) string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, "Invalid request: "+err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// grpcCode maps the HTTP status of a failed auction onto a gRPC status code.
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusServiceUnavailable:
+		// Blocked apps and disabled accounts. Retrying the same request won't help.
+		return codes.PermissionDenied
+	case http.StatusInternalServerError:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
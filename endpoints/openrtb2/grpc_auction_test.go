@@ -0,0 +1,168 @@
+package openrtb2
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	analyticsBuild "github.com/prebid/prebid-server/v3/analytics/build"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/endpoints/openrtb2/openrtbpb"
+	"github.com/prebid/prebid-server/v3/exchange"
+	"github.com/prebid/prebid-server/v3/hooks"
+	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestGRPCAuction(t *testing.T) {
+	ex := &mockExchange{}
+	client := newTestGRPCAuctionClient(t, ex, &config.Configuration{MaxRequestSize: maxSize})
+
+	request := &openrtb2.BidRequest{
+		ID: "some-request-id",
+		Imp: []openrtb2.Imp{{
+			ID:     "my-imp-id",
+			Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}},
+			Ext:    json.RawMessage(`{"appnexus":{"placementId":12883451}}`),
+		}},
+		Site: &openrtb2.Site{Page: "test.somepage.com"},
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "dnt", "1")
+	var header metadata.MD
+	response, err := client.auction(ctx, request, grpc.Header(&header))
+	require.NoError(t, err)
+
+	require.NotNil(t, ex.lastRequest, "The request never made it into the Exchange.")
+	assert.Equal(t, "some-request-id", ex.lastRequest.ID)
+	assert.Equal(t, "test.somepage.com", ex.lastRequest.Site.Page)
+	require.NotNil(t, ex.lastRequest.Device)
+	assert.Equal(t, ptrutil.ToPtr[int8](1), ex.lastRequest.Device.DNT, "The metadata should be read as request headers.")
+	require.Len(t, response.SeatBid, 1)
+	require.Len(t, response.SeatBid[0].Bid, 1)
+	assert.Equal(t, "<script></script>", response.SeatBid[0].Bid[0].AdM)
+	assert.NotEmpty(t, header.Get("x-prebid"))
+}
+
+func TestGRPCAuctionErrors(t *testing.T) {
+	testCases := []struct {
+		description     string
+		givenExchange   exchange.Exchange
+		givenRequest    *openrtb2.BidRequest
+		expectedCode    codes.Code
+		expectedMessage string
+	}{
+		{
+			description:     "Invalid request",
+			givenExchange:   &mockExchange{},
+			givenRequest:    &openrtb2.BidRequest{ID: "some-request-id", Site: &openrtb2.Site{Page: "test.somepage.com"}},
+			expectedCode:    codes.InvalidArgument,
+			expectedMessage: "Invalid request: request.imp must contain at least one element.",
+		},
+		{
+			description:   "Blocked app",
+			givenExchange: &mockExchange{},
+			givenRequest: &openrtb2.BidRequest{
+				ID:  "some-request-id",
+				Imp: []openrtb2.Imp{{ID: "my-imp-id", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}, Ext: json.RawMessage(`{"appnexus":{"placementId":12883451}}`)}},
+				App: &openrtb2.App{ID: "blocked-app"},
+			},
+			expectedCode:    codes.PermissionDenied,
+			expectedMessage: "Invalid request: Prebid-server does not process requests from App ID: blocked-app",
+		},
+		{
+			description:   "Critical error",
+			givenExchange: &brokenExchange{},
+			givenRequest: &openrtb2.BidRequest{
+				ID:   "some-request-id",
+				Imp:  []openrtb2.Imp{{ID: "my-imp-id", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}, Ext: json.RawMessage(`{"appnexus":{"placementId":12883451}}`)}},
+				Site: &openrtb2.Site{Page: "test.somepage.com"},
+			},
+			expectedCode:    codes.Internal,
+			expectedMessage: "Critical error while running the auction: Critical, unrecoverable error.",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := &config.Configuration{
+				MaxRequestSize:    maxSize,
+				BlockedApps:       []string{"blocked-app"},
+				BlockedAppsLookup: map[string]bool{"blocked-app": true},
+			}
+			client := newTestGRPCAuctionClient(t, test.givenExchange, cfg)
+
+			_, err := client.auction(context.Background(), test.givenRequest)
+			assert.Equal(t, test.expectedCode, status.Code(err))
+			assert.Equal(t, test.expectedMessage, status.Convert(err).Message())
+		})
+	}
+}
+
+type testGRPCAuctionClient struct {
+	conn *grpc.ClientConn
+}
+
+func (c testGRPCAuctionClient) auction(ctx context.Context, request *openrtb2.BidRequest, opts ...grpc.CallOption) (*openrtb2.BidResponse, error) {
+	message, err := openrtbpb.FromBidRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	response := dynamicpb.NewMessage(openrtbpb.BidResponse)
+	if err := c.conn.Invoke(ctx, "/prebid.openrtb.Auction/Auction", message, response, opts...); err != nil {
+		return nil, err
+	}
+	return openrtbpb.ToBidResponse(response)
+}
+
+func newTestGRPCAuctionClient(t *testing.T, ex exchange.Exchange, cfg *config.Configuration) testGRPCAuctionClient {
+	t.Helper()
+
+	auction, err := NewGRPCAuctionServer(
+		fakeUUIDGenerator{},
+		ex,
+		ortb.NewRequestValidator(openrtb_ext.BuildBidderMap(), map[string]string{}, mockBidderParamValidator{}),
+		empty_fetcher.EmptyFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		cfg,
+		&metricsConfig.NilMetricsEngine{},
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
+		map[string]string{},
+		[]byte{},
+		openrtb_ext.BuildBidderMap(),
+		empty_fetcher.EmptyFetcher{},
+		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	RegisterGRPCAuctionServer(server, auction)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return testGRPCAuctionClient{conn: conn}
+}
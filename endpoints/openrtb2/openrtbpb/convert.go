@@ -0,0 +1,292 @@
+package openrtbpb
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// messageMapping maps the fields of a message to the fields of an openrtb2 model with the same JSON name.
+// The ext extension of the message maps to the ext field of the model.
+type messageMapping struct {
+	fields   []fieldMapping
+	ext      protoreflect.ExtensionTypeDescriptor
+	extIndex int
+}
+
+type fieldMapping struct {
+	field protoreflect.FieldDescriptor
+	index int
+}
+
+var (
+	// extTypes holds the ext extension of every message, by message name.
+	extTypes = map[protoreflect.FullName]protoreflect.ExtensionType{}
+	// mappings holds the mapping of every message, by message name. It is only written by load.
+	mappings = map[protoreflect.FullName]*messageMapping{}
+)
+
+// NewBidRequest returns an empty BidRequest message to decode a request into.
+func NewBidRequest() *dynamicpb.Message {
+	return dynamicpb.NewMessage(BidRequest)
+}
+
+// ToBidRequest maps a BidRequest message to the openrtb2 model. The ext extensions are copied as is, they
+// are validated along with the rest of the request by the auction.
+func ToBidRequest(message protoreflect.Message) (*openrtb2.BidRequest, error) {
+	request := &openrtb2.BidRequest{}
+	if err := toRootModel(message, BidRequest, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// FromBidResponse maps a BidResponse to its message. Fields of the model missing from the schema are dropped.
+func FromBidResponse(response *openrtb2.BidResponse) (*dynamicpb.Message, error) {
+	return fromRootModel(BidResponse, response)
+}
+
+// FromBidRequest maps a BidRequest to its message, for the Go clients of the gRPC endpoint.
+func FromBidRequest(request *openrtb2.BidRequest) (*dynamicpb.Message, error) {
+	return fromRootModel(BidRequest, request)
+}
+
+// ToBidResponse maps a BidResponse message to the openrtb2 model, for the Go clients of the gRPC endpoint.
+func ToBidResponse(message protoreflect.Message) (*openrtb2.BidResponse, error) {
+	response := &openrtb2.BidResponse{}
+	if err := toRootModel(message, BidResponse, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func toRootModel(message protoreflect.Message, descriptor protoreflect.MessageDescriptor, model interface{}) error {
+	if message.Descriptor().FullName() != descriptor.FullName() {
+		return fmt.Errorf("unexpected message %s", message.Descriptor().FullName())
+	}
+	return toModel(message, reflect.ValueOf(model).Elem())
+}
+
+func fromRootModel(descriptor protoreflect.MessageDescriptor, model interface{}) (*dynamicpb.Message, error) {
+	message := dynamicpb.NewMessage(descriptor)
+	value := reflect.ValueOf(model)
+	if value.IsNil() {
+		return message, nil
+	}
+	if err := fromModel(value.Elem(), message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func addMapping(descriptor protoreflect.MessageDescriptor, model reflect.Type) error {
+	if _, ok := mappings[descriptor.FullName()]; ok {
+		return nil
+	}
+
+	indexes := make(map[string]int, model.NumField())
+	for i := 0; i < model.NumField(); i++ {
+		name, _, _ := strings.Cut(model.Field(i).Tag.Get("json"), ",")
+		indexes[name] = i
+	}
+
+	mapping := &messageMapping{}
+	mappings[descriptor.FullName()] = mapping
+
+	fields := descriptor.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		index, ok := indexes[string(field.Name())]
+		if !ok {
+			return fmt.Errorf("%s has no %s field", model, field.Name())
+		}
+		fieldType := model.Field(index).Type
+		if err := checkKind(field, fieldType); err != nil {
+			return fmt.Errorf("%s.%s: %v", model, model.Field(index).Name, err)
+		}
+		if field.Kind() == protoreflect.MessageKind {
+			if field.IsList() {
+				fieldType = fieldType.Elem()
+			}
+			if err := addMapping(field.Message(), elemType(fieldType)); err != nil {
+				return err
+			}
+		}
+		mapping.fields = append(mapping.fields, fieldMapping{field: field, index: index})
+	}
+
+	ext, ok := extTypes[descriptor.FullName()]
+	if !ok {
+		return fmt.Errorf("%s has no ext extension", descriptor.FullName())
+	}
+	if mapping.extIndex, ok = indexes["ext"]; !ok {
+		return fmt.Errorf("%s has no ext field", model)
+	}
+	mapping.ext = ext.TypeDescriptor()
+	return nil
+}
+
+// checkKind checks that values of the field can be stored in the model field, bools being stored as ints.
+func checkKind(field protoreflect.FieldDescriptor, fieldType reflect.Type) error {
+	if field.IsList() {
+		if fieldType.Kind() != reflect.Slice {
+			return fmt.Errorf("%s is not a slice", fieldType)
+		}
+		fieldType = fieldType.Elem()
+	}
+	fieldType = elemType(fieldType)
+
+	var ok bool
+	switch kind := fieldType.Kind(); field.Kind() {
+	case protoreflect.MessageKind:
+		ok = kind == reflect.Struct
+	case protoreflect.BoolKind, protoreflect.EnumKind, protoreflect.Int32Kind:
+		ok = kind >= reflect.Int && kind <= reflect.Int64
+	case protoreflect.DoubleKind:
+		ok = kind == reflect.Float64
+	case protoreflect.StringKind:
+		ok = kind == reflect.String
+	}
+	if !ok {
+		return fmt.Errorf("%s can't hold %s values", fieldType, field.Kind())
+	}
+	return nil
+}
+
+func elemType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+func toModel(message protoreflect.Message, model reflect.Value) error {
+	mapping := mappings[message.Descriptor().FullName()]
+	for _, f := range mapping.fields {
+		if !message.Has(f.field) {
+			continue
+		}
+		if err := toModelField(f.field, message.Get(f.field), model.Field(f.index)); err != nil {
+			return fmt.Errorf("%s: %w", f.field.Name(), err)
+		}
+	}
+	if message.Has(mapping.ext) {
+		model.Field(mapping.extIndex).SetBytes(bytes.Clone(message.Get(mapping.ext).Bytes()))
+	}
+	return nil
+}
+
+func toModelField(field protoreflect.FieldDescriptor, value protoreflect.Value, target reflect.Value) error {
+	if !field.IsList() {
+		return toModelValue(field, value, target)
+	}
+	list := value.List()
+	slice := reflect.MakeSlice(target.Type(), list.Len(), list.Len())
+	for i := 0; i < list.Len(); i++ {
+		if err := toModelValue(field, list.Get(i), slice.Index(i)); err != nil {
+			return err
+		}
+	}
+	target.Set(slice)
+	return nil
+}
+
+func toModelValue(field protoreflect.FieldDescriptor, value protoreflect.Value, target reflect.Value) error {
+	if target.Kind() == reflect.Pointer {
+		target.Set(reflect.New(target.Type().Elem()))
+		target = target.Elem()
+	}
+
+	switch field.Kind() {
+	case protoreflect.MessageKind:
+		return toModel(value.Message(), target)
+	case protoreflect.BoolKind:
+		if value.Bool() {
+			target.SetInt(1)
+		} else {
+			target.SetInt(0)
+		}
+	case protoreflect.EnumKind:
+		return setInt(target, int64(value.Enum()))
+	case protoreflect.Int32Kind:
+		return setInt(target, value.Int())
+	case protoreflect.DoubleKind:
+		target.SetFloat(value.Float())
+	case protoreflect.StringKind:
+		target.SetString(value.String())
+	}
+	return nil
+}
+
+func setInt(target reflect.Value, n int64) error {
+	if target.OverflowInt(n) {
+		return fmt.Errorf("%d is out of range", n)
+	}
+	target.SetInt(n)
+	return nil
+}
+
+func fromModel(model reflect.Value, message protoreflect.Message) error {
+	mapping := mappings[message.Descriptor().FullName()]
+	for _, f := range mapping.fields {
+		source := model.Field(f.index)
+		if source.IsZero() && f.field.Cardinality() != protoreflect.Required {
+			continue
+		}
+
+		if !f.field.IsList() {
+			value, err := fromModelValue(f.field, source)
+			if err != nil {
+				return fmt.Errorf("%s: %w", f.field.Name(), err)
+			}
+			message.Set(f.field, value)
+			continue
+		}
+
+		list := message.Mutable(f.field).List()
+		for i := 0; i < source.Len(); i++ {
+			value, err := fromModelValue(f.field, source.Index(i))
+			if err != nil {
+				return fmt.Errorf("%s: %w", f.field.Name(), err)
+			}
+			list.Append(value)
+		}
+	}
+	if ext := model.Field(mapping.extIndex).Bytes(); len(ext) > 0 {
+		message.Set(mapping.ext, protoreflect.ValueOfBytes(ext))
+	}
+	return nil
+}
+
+func fromModelValue(field protoreflect.FieldDescriptor, source reflect.Value) (protoreflect.Value, error) {
+	if source.Kind() == reflect.Pointer {
+		source = source.Elem()
+	}
+
+	switch field.Kind() {
+	case protoreflect.MessageKind:
+		message := dynamicpb.NewMessage(field.Message())
+		return protoreflect.ValueOfMessage(message), fromModel(source, message)
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(source.Int() != 0), nil
+	case protoreflect.EnumKind, protoreflect.Int32Kind:
+		n := source.Int()
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			return protoreflect.Value{}, fmt.Errorf("%d is out of range", n)
+		}
+		if field.Kind() == protoreflect.EnumKind {
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+		}
+		return protoreflect.ValueOfInt32(int32(n)), nil
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(source.Float()), nil
+	default:
+		return protoreflect.ValueOfString(source.String()), nil
+	}
+}
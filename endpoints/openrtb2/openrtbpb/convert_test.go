@@ -0,0 +1,218 @@
+package openrtbpb
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestToBidRequest(t *testing.T) {
+	imp := newMessage(t, BidRequest, "imp")
+	imp.Set(field(imp, "id"), protoreflect.ValueOfString("imp-1"))
+	imp.Set(field(imp, "secure"), protoreflect.ValueOfBool(false))
+	imp.Set(field(imp, "instl"), protoreflect.ValueOfBool(true))
+	imp.Set(extField(imp), protoreflect.ValueOfBytes([]byte(`{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}`)))
+
+	banner := newMessage(t, imp.Descriptor(), "banner")
+	banner.Set(field(banner, "pos"), protoreflect.ValueOfEnum(1))
+	banner.Mutable(field(banner, "api")).List().Append(protoreflect.ValueOfEnum(5))
+	format := newMessage(t, banner.Descriptor(), "format")
+	format.Set(field(format, "w"), protoreflect.ValueOfInt32(300))
+	format.Set(field(format, "h"), protoreflect.ValueOfInt32(250))
+	banner.Mutable(field(banner, "format")).List().Append(protoreflect.ValueOfMessage(format))
+	imp.Set(field(imp, "banner"), protoreflect.ValueOfMessage(banner))
+
+	regs := newMessage(t, BidRequest, "regs")
+	regs.Set(field(regs, "gdpr"), protoreflect.ValueOfBool(true))
+	regs.Mutable(field(regs, "gpp_sid")).List().Append(protoreflect.ValueOfInt32(7))
+
+	request := NewBidRequest()
+	request.Set(field(request, "id"), protoreflect.ValueOfString("req-1"))
+	request.Mutable(field(request, "imp")).List().Append(protoreflect.ValueOfMessage(imp))
+	request.Set(field(request, "regs"), protoreflect.ValueOfMessage(regs))
+	request.Set(field(request, "tmax"), protoreflect.ValueOfInt32(500))
+	request.Set(extField(request), protoreflect.ValueOfBytes([]byte(`{"prebid":{"debug":true}}`)))
+
+	// decode the encoded request, as the gRPC codec does, to check that the ext extensions are resolved
+	encoded, err := proto.Marshal(request)
+	require.NoError(t, err)
+	decoded := NewBidRequest()
+	require.NoError(t, proto.Unmarshal(encoded, decoded))
+
+	expected := &openrtb2.BidRequest{
+		ID: "req-1",
+		Imp: []openrtb2.Imp{{
+			ID: "imp-1",
+			Banner: &openrtb2.Banner{
+				Format: []openrtb2.Format{{W: 300, H: 250}},
+				Pos:    adcom1.PositionAboveFold.Ptr(),
+				API:    []adcom1.APIFramework{adcom1.APIMRAID20},
+			},
+			Instl:  1,
+			Secure: ptrutil.ToPtr[int8](0),
+			Ext:    json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}`),
+		}},
+		TMax: 500,
+		Regs: &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1), GPPSID: []int8{7}},
+		Ext:  json.RawMessage(`{"prebid":{"debug":true}}`),
+	}
+
+	actual, err := ToBidRequest(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestBidRequestRoundTrip(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID: "req-1",
+		Imp: []openrtb2.Imp{{
+			ID:     "imp-1",
+			Video:  &openrtb2.Video{MIMEs: []string{"video/mp4"}, W: ptrutil.ToPtr[int64](640), Plcmt: adcom1.VideoPlcmtInstream, Skip: ptrutil.ToPtr[int8](0)},
+			PMP:    &openrtb2.PMP{PrivateAuction: 1, Deals: []openrtb2.Deal{{ID: "deal-1", BidFloor: 2.5}}},
+			Secure: ptrutil.ToPtr[int8](1),
+			Ext:    json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}`),
+		}},
+		App: &openrtb2.App{
+			Bundle:    "com.example",
+			Publisher: &openrtb2.Publisher{ID: "pub-1", Ext: json.RawMessage(`{"prebid":{"parentAccount":"acc-1"}}`)},
+		},
+		Device: &openrtb2.Device{
+			UA:  "Mozilla/5.0",
+			Geo: &openrtb2.Geo{Lat: ptrutil.ToPtr(52.5), Country: "DEU"},
+			SUA: &openrtb2.UserAgent{Browsers: []openrtb2.BrandVersion{{Brand: "Chrome", Version: []string{"120"}}}, Mobile: ptrutil.ToPtr[int8](1)},
+			Lmt: ptrutil.ToPtr[int8](0),
+		},
+		User:   &openrtb2.User{EIDs: []openrtb2.EID{{Source: "example.com", UIDs: []openrtb2.UID{{ID: "uid-1", AType: 1}}}}},
+		Source: &openrtb2.Source{SChain: &openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{{ASI: "example.com", SID: "1", HP: ptrutil.ToPtr[int8](1)}}}},
+		Test:   1,
+		Cur:    []string{"USD"},
+	}
+
+	message, err := FromBidRequest(request)
+	require.NoError(t, err)
+	encoded, err := proto.Marshal(message)
+	require.NoError(t, err)
+	decoded := NewBidRequest()
+	require.NoError(t, proto.Unmarshal(encoded, decoded))
+
+	actual, err := ToBidRequest(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, request, actual)
+}
+
+func TestToBidRequestOutOfRange(t *testing.T) {
+	regs := newMessage(t, BidRequest, "regs")
+	regs.Mutable(field(regs, "gpp_sid")).List().Append(protoreflect.ValueOfInt32(1000))
+
+	request := NewBidRequest()
+	request.Set(field(request, "id"), protoreflect.ValueOfString("req-1"))
+	request.Set(field(request, "regs"), protoreflect.ValueOfMessage(regs))
+
+	_, err := ToBidRequest(request)
+	assert.EqualError(t, err, "regs: gpp_sid: 1000 is out of range")
+}
+
+func TestToBidRequestUnexpectedMessage(t *testing.T) {
+	_, err := ToBidRequest(dynamicpb.NewMessage(BidResponse))
+	assert.EqualError(t, err, "unexpected message com.google.openrtb.BidResponse")
+}
+
+func TestFromBidRequestOutOfRange(t *testing.T) {
+	_, err := FromBidRequest(&openrtb2.BidRequest{ID: "req-1", TMax: math.MaxInt32 + 1})
+	assert.EqualError(t, err, "tmax: 2147483648 is out of range")
+}
+
+func TestFromBidResponse(t *testing.T) {
+	response := &openrtb2.BidResponse{
+		ID:  "req-1",
+		Cur: "USD",
+		SeatBid: []openrtb2.SeatBid{{
+			Seat: "appnexus",
+			Bid: []openrtb2.Bid{
+				{ID: "bid-1", ImpID: "imp-1", Price: 1.5, W: 300, H: 250, MType: openrtb2.MarkupBanner, Ext: json.RawMessage(`{"prebid":{"type":"banner"}}`)},
+				{ID: "bid-2", ImpID: "imp-2"},
+			},
+		}},
+		NBR: openrtb3.NoBidProxy.Ptr(),
+		Ext: json.RawMessage(`{"responsetimemillis":{"appnexus":10}}`),
+	}
+
+	message, err := FromBidResponse(response)
+	require.NoError(t, err)
+
+	// required fields must be set even when they hold the zero value
+	encoded, err := proto.Marshal(message)
+	require.NoError(t, err)
+	decoded := dynamicpb.NewMessage(BidResponse)
+	require.NoError(t, proto.Unmarshal(encoded, decoded))
+
+	assert.Equal(t, "req-1", decoded.Get(field(decoded, "id")).String())
+	assert.Equal(t, "USD", decoded.Get(field(decoded, "cur")).String())
+	assert.Equal(t, int64(openrtb3.NoBidProxy), decoded.Get(field(decoded, "nbr")).Int())
+	assert.Equal(t, `{"responsetimemillis":{"appnexus":10}}`, string(decoded.Get(extField(decoded)).Bytes()))
+
+	seatBids := decoded.Get(field(decoded, "seatbid")).List()
+	require.Equal(t, 1, seatBids.Len())
+	seatBid := seatBids.Get(0).Message()
+	assert.Equal(t, "appnexus", seatBid.Get(field(seatBid, "seat")).String())
+
+	bids := seatBid.Get(field(seatBid, "bid")).List()
+	require.Equal(t, 2, bids.Len())
+	bid := bids.Get(0).Message()
+	assert.Equal(t, "bid-1", bid.Get(field(bid, "id")).String())
+	assert.Equal(t, 1.5, bid.Get(field(bid, "price")).Float())
+	assert.Equal(t, int64(300), bid.Get(field(bid, "w")).Int())
+	assert.Equal(t, protoreflect.EnumNumber(openrtb2.MarkupBanner), bid.Get(field(bid, "mtype")).Enum())
+	assert.Equal(t, `{"prebid":{"type":"banner"}}`, string(bid.Get(extField(bid)).Bytes()))
+	zeroPriceBid := bids.Get(1).Message()
+	assert.True(t, zeroPriceBid.Has(field(zeroPriceBid, "price")))
+	assert.False(t, zeroPriceBid.Has(field(zeroPriceBid, "w")))
+}
+
+func TestBidResponseRoundTrip(t *testing.T) {
+	response := &openrtb2.BidResponse{
+		ID: "req-1",
+		SeatBid: []openrtb2.SeatBid{{
+			Seat: "appnexus",
+			Bid:  []openrtb2.Bid{{ID: "bid-1", ImpID: "imp-1", Price: 1.5, AdM: "<VAST/>", Attr: []adcom1.CreativeAttribute{adcom1.AttrPop}, MType: openrtb2.MarkupVideo}},
+		}},
+		Ext: json.RawMessage(`{"prebid":{"auctiontimestamp":1}}`),
+	}
+
+	message, err := FromBidResponse(response)
+	require.NoError(t, err)
+
+	actual, err := ToBidResponse(message)
+	require.NoError(t, err)
+	assert.Equal(t, response, actual)
+}
+
+func TestFromBidResponseNil(t *testing.T) {
+	message, err := FromBidResponse(nil)
+	require.NoError(t, err)
+	assert.False(t, message.Has(field(message, "id")))
+}
+
+func newMessage(t *testing.T, parent protoreflect.MessageDescriptor, name protoreflect.Name) *dynamicpb.Message {
+	fd := parent.Fields().ByName(name)
+	require.NotNil(t, fd, "%s has no %s field", parent.FullName(), name)
+	return dynamicpb.NewMessage(fd.Message())
+}
+
+func field(message protoreflect.Message, name protoreflect.Name) protoreflect.FieldDescriptor {
+	return message.Descriptor().Fields().ByName(name)
+}
+
+func extField(message protoreflect.Message) protoreflect.FieldDescriptor {
+	return mappings[message.Descriptor().FullName()].ext
+}
@@ -0,0 +1,74 @@
+// Package openrtbpb holds the protobuf schema of the gRPC auction endpoint, along with the mapping between
+// its messages and the openrtb2 models.
+//
+// The schema is compiled from the embedded .proto files when the package is loaded and the messages are
+// handled with dynamicpb, so there is no generated code to keep in sync with the .proto files.
+package openrtbpb
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+//go:embed openrtb.proto prebid.proto
+var sources embed.FS
+
+var (
+	// BidRequest describes the com.google.openrtb.BidRequest message.
+	BidRequest protoreflect.MessageDescriptor
+	// BidResponse describes the com.google.openrtb.BidResponse message.
+	BidResponse protoreflect.MessageDescriptor
+	// AuctionService describes the prebid.openrtb.Auction service.
+	AuctionService protoreflect.ServiceDescriptor
+)
+
+func init() {
+	if err := load(); err != nil {
+		panic(fmt.Sprintf("Failed to load the OpenRTB protobuf schema: %v", err))
+	}
+}
+
+// load compiles the schema, registers the ext extensions so that the gRPC codec decodes them, and maps
+// the messages to the openrtb2 models.
+func load() error {
+	compiler := protocompile.Compiler{
+		Resolver: &protocompile.SourceResolver{
+			Accessor: func(path string) (io.ReadCloser, error) {
+				return sources.Open(path)
+			},
+		},
+	}
+	files, err := compiler.Compile(context.Background(), "prebid.proto")
+	if err != nil {
+		return err
+	}
+	prebid := files[0]
+	openrtb := prebid.FindImportByPath("openrtb.proto")
+
+	BidRequest = openrtb.Messages().ByName("BidRequest")
+	BidResponse = openrtb.Messages().ByName("BidResponse")
+	AuctionService = prebid.Services().ByName("Auction")
+
+	extensions := prebid.Extensions()
+	for i := 0; i < extensions.Len(); i++ {
+		extension := dynamicpb.NewExtensionType(extensions.Get(i))
+		if err := protoregistry.GlobalTypes.RegisterExtension(extension); err != nil {
+			return err
+		}
+		extTypes[extension.TypeDescriptor().ContainingMessage().FullName()] = extension
+	}
+
+	if err := addMapping(BidRequest, reflect.TypeOf(openrtb2.BidRequest{})); err != nil {
+		return err
+	}
+	return addMapping(BidResponse, reflect.TypeOf(openrtb2.BidResponse{}))
+}
@@ -57,7 +57,9 @@ func NewVideoEndpoint(
 	defReqJSON []byte,
 	bidderMap map[string]openrtb_ext.BidderName,
 	cache prebid_cache_client.Client,
+	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	uidStore usersync.UIDStore,
 ) (httprouter.Handle, error) {
 
 	if ex == nil || requestValidator == nil || requestsById == nil || accounts == nil || cfg == nil || met == nil {
@@ -91,9 +93,11 @@ func NewVideoEndpoint(
 		videoEndpointRegexp,
 		ipValidator,
 		empty_fetcher.EmptyFetcher{},
-		hooks.EmptyPlanBuilder{},
+		hookExecutionPlanBuilder,
 		tmaxAdjustments,
-		openrtb_ext.NormalizeBidderName}).VideoAuctionEndpoint), nil
+		openrtb_ext.NormalizeBidderName,
+		uidStore,
+		usersync.NewDecoder(&cfg.HostCookie)}).VideoAuctionEndpoint), nil
 }
 
 /*
@@ -122,6 +126,8 @@ func NewVideoEndpoint(
 func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	start := time.Now()
 
+	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointVideo, deps.metricsEngine)
+
 	vo := analytics.VideoObject{
 		Status:    http.StatusOK,
 		Errors:    make([]error, 0),
@@ -159,6 +165,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 				vo.Errors = append(vo.Errors, err)
 			}
 		}
+		vo.HookExecutionOutcome = hookExecutor.GetOutcomes()
 		deps.metricsEngine.RecordRequest(labels)
 		deps.metricsEngine.RecordRequestTime(labels, time.Since(start))
 		deps.analytics.LogVideoObject(&vo, activityControl)
@@ -177,6 +184,12 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	requestJson, rejectErr := hookExecutor.ExecuteEntrypointStage(r, requestJson)
+	if rejectErr != nil {
+		rejectVideoRequest(*rejectErr, w, hookExecutor, &vo)
+		return
+	}
+
 	resolvedRequest := requestJson
 	if debugLog.DebugEnabledOrOverridden {
 		debugLog.Data.Request = string(requestJson)
@@ -271,7 +284,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 
 	ctx := context.Background()
-	timeout := deps.cfg.AuctionTimeouts.LimitAuctionTimeout(time.Duration(bidReqWrapper.TMax) * time.Millisecond)
+	timeout := deps.cfg.Current().AuctionTimeouts.LimitAuctionTimeout(time.Duration(bidReqWrapper.TMax) * time.Millisecond)
 	if timeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithDeadline(ctx, start.Add(timeout))
@@ -279,9 +292,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 
 	// Read Usersyncs/Cookie
-	decoder := usersync.Base64Decoder{}
-	usersyncs := usersync.ReadCookie(r, decoder, &deps.cfg.HostCookie)
-	usersync.SyncHostCookie(r, usersyncs, &deps.cfg.HostCookie)
+	usersyncs := deps.readUserSyncs(ctx, r)
 
 	if bidReqWrapper.App != nil {
 		labels.Source = metrics.DemandApp
@@ -303,6 +314,17 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	hookExecutor.SetAccount(account)
+	bidReqWrapper, rejectErr, err = deps.executeVideoRawAuctionStage(hookExecutor, account, bidReqWrapper)
+	if rejectErr != nil {
+		rejectVideoRequest(*rejectErr, w, hookExecutor, &vo)
+		return
+	} else if err != nil {
+		handleError(&labels, w, []error{err}, &vo, &debugLog)
+		return
+	}
+	bidReq = bidReqWrapper.BidRequest
+
 	// Populate any "missing" OpenRTB fields with info from other sources, (e.g. HTTP request headers).
 	if errs := deps.setFieldsImplicitly(r, bidReqWrapper, account); len(errs) > 0 {
 		errL = append(errL, errs...)
@@ -317,6 +339,8 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 
 	activityControl = privacy.NewActivityControl(&account.Privacy)
 
+	hookExecutor.SetActivityControl(activityControl)
+
 	warnings := errortypes.WarningOnly(errL)
 
 	secGPC := r.Header.Get("Sec-GPC")
@@ -330,7 +354,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		Warnings:                   warnings,
 		GlobalPrivacyControlHeader: secGPC,
 		PubID:                      labels.PubID,
-		HookExecutor:               hookexecution.EmptyHookExecutor{},
+		HookExecutor:               hookExecutor,
 		TmaxAdjustments:            deps.tmaxAdjustments,
 		Activities:                 activityControl,
 	}
@@ -348,12 +372,17 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 	vo.Response = response
 	vo.SeatNonBid = auctionResponse.GetSeatNonBid()
-	if err != nil {
+	if rejectErr, isRejectErr := hookexecution.CastRejectErr(err); isRejectErr {
+		rejectVideoRequest(*rejectErr, w, hookExecutor, &vo)
+		return
+	} else if err != nil {
 		errL := []error{err}
 		handleError(&labels, w, errL, &vo, &debugLog)
 		return
 	}
 
+	hookExecutor.ExecuteAuctionResponseStage(response)
+
 	//build simplified response
 	bidResp, err := buildVideoResponse(response, podErrors)
 	if err != nil {
@@ -361,11 +390,21 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		handleError(&labels, w, errL, &vo, &debugLog)
 		return
 	}
+	addUnfilledSlots(bidResp, auctionResponse)
 	if bidReq.Test == 1 {
 		err = setSeatNonBidRaw(bidReqWrapper, auctionResponse)
 		if err != nil {
 			glog.Errorf("Error setting seat non-bid: %v", err)
 		}
+		ext, warns, err := hookexecution.EnrichExtBidResponse(response.Ext, hookExecutor.GetOutcomes(), bidReq, account)
+		if err != nil {
+			err = fmt.Errorf("Failed to enrich Bid Response with hook debug information: %s", err)
+			glog.Errorf(err.Error())
+			vo.Errors = append(vo.Errors, err)
+		} else {
+			response.Ext = ext
+		}
+		vo.Errors = append(vo.Errors, warns...)
 		bidResp.Ext = response.Ext
 	}
 
@@ -394,7 +433,51 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.Write(resp)
+	writeResponse(w, hookExecutor, resp)
+}
+
+// executeVideoRawAuctionStage runs the raw auction hooks on the OpenRTB request built from the video request, since
+// the video request itself isn't OpenRTB. The request is only rebuilt if a hook changed it.
+func (deps *endpointDeps) executeVideoRawAuctionStage(hookExecutor hookexecution.HookStageExecutor, account *config.Account, req *openrtb_ext.RequestWrapper) (*openrtb_ext.RequestWrapper, *hookexecution.RejectError, error) {
+	if len(deps.hookExecutionPlanBuilder.PlanForRawAuctionStage(hookexecution.EndpointVideo, account)) == 0 {
+		return req, nil, nil
+	}
+
+	if err := req.RebuildRequest(); err != nil {
+		return req, nil, err
+	}
+	requestJson, err := jsonutil.Marshal(req.BidRequest)
+	if err != nil {
+		return req, nil, err
+	}
+
+	requestJson, rejectErr := hookExecutor.ExecuteRawAuctionStage(requestJson)
+	if rejectErr != nil || !hasPayloadUpdatesAt(hooks.StageRawAuctionRequest.String(), hookExecutor.GetOutcomes()) {
+		return req, rejectErr, nil
+	}
+
+	bidReq := &openrtb2.BidRequest{}
+	if err := jsonutil.UnmarshalValid(requestJson, bidReq); err != nil {
+		return req, nil, err
+	}
+	return &openrtb_ext.RequestWrapper{BidRequest: bidReq}, nil, nil
+}
+
+// rejectVideoRequest sends an empty video response for a request rejected by a module hook.
+func rejectVideoRequest(rejectErr hookexecution.RejectError, w http.ResponseWriter, hookExecutor hookexecution.HookStageExecutor, vo *analytics.VideoObject) {
+	bidResp := &openrtb_ext.BidResponseVideo{AdPods: []*openrtb_ext.AdPod{}}
+	vo.VideoResponse = bidResp
+	vo.Errors = append(vo.Errors, &rejectErr)
+
+	resp, err := jsonutil.Marshal(bidResp)
+	if err != nil {
+		vo.Errors = append(vo.Errors, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeResponse(w, hookExecutor, resp)
 }
 
 func cleanupVideoBidRequest(videoReq *openrtb_ext.BidRequestVideo, podErrors []PodError) *openrtb_ext.BidRequestVideo {
@@ -486,6 +569,8 @@ func (deps *endpointDeps) createImpressions(videoReq *openrtb_ext.BidRequestVide
 			}
 
 			impsArray[impInd].ID = fmt.Sprintf("%d_%d", pod.PodId, impInd)
+			impsArray[impInd].Video.PodID = strconv.Itoa(pod.PodId)
+			impsArray[impInd].Video.PodDur = int64(pod.AdPodDurationSec)
 		}
 		finalImpsArray = append(finalImpsArray, impsArray...)
 
@@ -599,6 +684,28 @@ func buildVideoResponse(bidresponse *openrtb2.BidResponse, podErrors []PodError)
 	return &openrtb_ext.BidResponseVideo{AdPods: adPods}, nil
 }
 
+// addUnfilledSlots reports the slots the ad pod auction couldn't fill for each pod, including the pods which got no bids at all.
+func addUnfilledSlots(bidResp *openrtb_ext.BidResponseVideo, auctionResponse *exchange.AuctionResponse) {
+	if auctionResponse == nil || auctionResponse.ExtBidResponse == nil || auctionResponse.ExtBidResponse.Prebid == nil {
+		return
+	}
+	for _, adPodResult := range auctionResponse.ExtBidResponse.Prebid.AdPods {
+		podId, err := strconv.ParseInt(adPodResult.PodID, 10, 64)
+		if err != nil {
+			continue
+		}
+		adPod := findAdPod(podId, bidResp.AdPods)
+		if adPod == nil {
+			adPod = &openrtb_ext.AdPod{
+				PodId:     podId,
+				Targeting: make([]openrtb_ext.VideoTargeting, 0),
+			}
+			bidResp.AdPods = append(bidResp.AdPods, adPod)
+		}
+		adPod.UnfilledSlots = adPodResult.UnfilledSlots
+	}
+}
+
 func formatTargetingKey(key openrtb_ext.TargetingKey, bidderName string) string {
 	fullKey := fmt.Sprintf("%s_%s", string(key), bidderName)
 	if len(fullKey) > exchange.MaxKeyLength {
@@ -818,7 +925,7 @@ func (deps *endpointDeps) validateVideoRequest(req *openrtb_ext.BidRequestVideo)
 		errL = append(errL, err)
 	} else if req.App != nil {
 		if req.App.ID != "" {
-			if _, found := deps.cfg.BlockedAppsLookup[req.App.ID]; found {
+			if _, found := deps.cfg.Current().BlockedAppsLookup[req.App.ID]; found {
 				err := &errortypes.BlockedApp{Message: fmt.Sprintf("Prebid-server does not process requests from App ID: %s", req.App.ID)}
 				errL = append(errL, err)
 				return errL, podErrors
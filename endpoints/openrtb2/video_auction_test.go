@@ -17,6 +17,7 @@ import (
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/exchange"
 	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/metrics"
 	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
@@ -24,6 +25,7 @@ import (
 	"github.com/prebid/prebid-server/v3/prebid_cache_client"
 	"github.com/prebid/prebid-server/v3/privacy"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
+	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 
@@ -32,6 +34,7 @@ import (
 	gometrics "github.com/rcrowley/go-metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	jsonpatch "gopkg.in/evanphx/json-patch.v5"
 )
 
 func TestVideoEndpointImpressionsNumber(t *testing.T) {
@@ -68,6 +71,68 @@ func TestVideoEndpointImpressionsNumber(t *testing.T) {
 	assert.Equal(t, "ABC_123", resp.AdPods[0].Targeting[0].HbDeal, "If DealID exists in bid response, hb_deal targeting needs to be added to resp")
 }
 
+func TestVideoEndpointRawAuctionHooks(t *testing.T) {
+	testCases := []struct {
+		description       string
+		hook              hookstage.RawAuctionRequest
+		expectedAuction   bool
+		expectedSitePage  string
+		expectedAdPodsLen int
+	}{
+		{
+			description:       "rejected",
+			hook:              mockRejectionHook{nbr: 123},
+			expectedAuction:   false,
+			expectedAdPodsLen: 0,
+		},
+		{
+			description:       "updated",
+			hook:              mockVideoRawAuctionUpdateHook{},
+			expectedAuction:   true,
+			expectedSitePage:  "hooked.com",
+			expectedAdPodsLen: 5,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			ex := &mockExchangeVideo{}
+			reqBody := readVideoTestFile(t, "sample-requests/video/video_valid_sample.json")
+			req := httptest.NewRequest("POST", "/openrtb2/video", strings.NewReader(reqBody))
+			recorder := httptest.NewRecorder()
+
+			deps := mockDeps(t, ex)
+			deps.hookExecutionPlanBuilder = mockPlanBuilder{rawAuctionPlan: makePlan[hookstage.RawAuctionRequest](test.hook)}
+			deps.VideoAuctionEndpoint(recorder, req, nil)
+
+			resp := &openrtb_ext.BidResponseVideo{}
+			require.NoError(t, jsonutil.UnmarshalValid(recorder.Body.Bytes(), resp))
+			assert.Len(t, resp.AdPods, test.expectedAdPodsLen)
+
+			if !test.expectedAuction {
+				assert.Nil(t, ex.lastRequest, "a rejected request never makes it into the exchange")
+				return
+			}
+			require.NotNil(t, ex.lastRequest)
+			assert.Equal(t, test.expectedSitePage, ex.lastRequest.Site.Page)
+		})
+	}
+}
+
+type mockVideoRawAuctionUpdateHook struct{}
+
+func (m mockVideoRawAuctionUpdateHook) HandleRawAuctionHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.RawAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.RawAuctionRequestPayload]{}
+	c.AddMutation(func(payload hookstage.RawAuctionRequestPayload) (hookstage.RawAuctionRequestPayload, error) {
+		return jsonpatch.MergePatch(payload, []byte(`{"site":{"page":"hooked.com"}}`))
+	}, hookstage.MutationUpdate, "site", "page")
+	return hookstage.HookResult[hookstage.RawAuctionRequestPayload]{ChangeSet: c}, nil
+}
+
 func TestVideoEndpointImpressionsDuration(t *testing.T) {
 	ex := &mockExchangeVideo{}
 	reqBody := readVideoTestFile(t, "sample-requests/video/video_valid_sample_different_durations.json")
@@ -102,6 +167,9 @@ func TestVideoEndpointImpressionsDuration(t *testing.T) {
 	assert.Equal(t, "2_5", ex.lastRequest.Imp[17].ID, "Incorrect impression id in request")
 	assert.Equal(t, int64(30), ex.lastRequest.Imp[17].Video.MaxDuration, "Incorrect impression max duration in request")
 	assert.Equal(t, int64(30), ex.lastRequest.Imp[17].Video.MinDuration, "Incorrect impression min duration in request")
+
+	assert.Equal(t, "1", ex.lastRequest.Imp[0].Video.PodID, "Incorrect impression pod id in request")
+	assert.Equal(t, "2", ex.lastRequest.Imp[17].Video.PodID, "Incorrect impression pod id in request")
 }
 
 func TestCreateBidExtension(t *testing.T) {
@@ -749,6 +817,34 @@ func TestVideoBuildVideoResponseNoBids(t *testing.T) {
 	assert.Len(t, bidRespVideo.AdPods, 0, "AdPods length should be 0")
 }
 
+func TestAddUnfilledSlots(t *testing.T) {
+	bidRespVideo := &openrtb_ext.BidResponseVideo{
+		AdPods: []*openrtb_ext.AdPod{
+			{PodId: 1, Targeting: []openrtb_ext.VideoTargeting{{HbPb: "10.00"}}},
+		},
+	}
+	auctionResponse := &exchange.AuctionResponse{
+		ExtBidResponse: &openrtb_ext.ExtBidResponse{
+			Prebid: &openrtb_ext.ExtResponsePrebid{
+				AdPods: []openrtb_ext.ExtAdPod{
+					{PodID: "1", Slots: 3, FilledSlots: 1, UnfilledSlots: 2},
+					{PodID: "2", Slots: 2, UnfilledSlots: 2},
+				},
+			},
+		},
+	}
+
+	addUnfilledSlots(bidRespVideo, auctionResponse)
+
+	assert.Equal(t, []*openrtb_ext.AdPod{
+		{PodId: 1, Targeting: []openrtb_ext.VideoTargeting{{HbPb: "10.00"}}, UnfilledSlots: 2},
+		{PodId: 2, Targeting: []openrtb_ext.VideoTargeting{}, UnfilledSlots: 2},
+	}, bidRespVideo.AdPods)
+
+	addUnfilledSlots(bidRespVideo, nil)
+	assert.Len(t, bidRespVideo.AdPods, 2)
+}
+
 func TestMergeOpenRTBToVideoRequest(t *testing.T) {
 	var bidReq = &openrtb2.BidRequest{}
 	var videoReq = &openrtb_ext.BidRequestVideo{}
@@ -1252,6 +1348,8 @@ func mockDepsWithMetrics(t *testing.T, ex *mockExchangeVideo) (*endpointDeps, *m
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 	return deps, metrics, mockModule
 }
@@ -1291,7 +1389,7 @@ func mockDeps(t *testing.T, ex *mockExchangeVideo) *endpointDeps {
 		&mockAccountFetcher{data: mockVideoAccountData},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -1303,6 +1401,8 @@ func mockDeps(t *testing.T, ex *mockExchangeVideo) *endpointDeps {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 }
 
@@ -1316,7 +1416,7 @@ func mockDepsAppendBidderNames(t *testing.T, ex *mockExchangeAppendBidderNames)
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -1328,6 +1428,8 @@ func mockDepsAppendBidderNames(t *testing.T, ex *mockExchangeAppendBidderNames)
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	return deps
@@ -1343,7 +1445,7 @@ func mockDepsNoBids(t *testing.T, ex *mockExchangeVideoNoBids) *endpointDeps {
 		empty_fetcher.EmptyFetcher{},
 		&config.Configuration{MaxRequestSize: maxSize},
 		&metricsConfig.NilMetricsEngine{},
-		analyticsBuild.New(&config.Analytics{}),
+		analyticsBuild.New(&config.Analytics{}, &metricsConfig.NilMetricsEngine{}),
 		map[string]string{},
 		false,
 		[]byte{},
@@ -1355,6 +1457,8 @@ func mockDepsNoBids(t *testing.T, ex *mockExchangeVideoNoBids) *endpointDeps {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		usersync.Base64Decoder{},
 	}
 
 	return edep
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	gpplib "github.com/prebid/go-gpp"
 	gppConstants "github.com/prebid/go-gpp/constants"
@@ -17,6 +18,9 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/privacy"
@@ -37,9 +41,9 @@ const (
 
 const uidCookieName = "uids"
 
-func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, analyticsRunner analytics.Runner, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine) httprouter.Handle {
-	encoder := usersync.Base64Encoder{}
-	decoder := usersync.Base64Decoder{}
+func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, gdprPermsBuilder gdpr.PermissionsBuilder, tcf2CfgBuilder gdpr.TCF2ConfigBuilder, analyticsRunner analytics.Runner, accountsFetcher stored_requests.AccountFetcher, metricsEngine metrics.MetricsEngine, uidStore usersync.UIDStore, syncValueTracker *usersync.ValueTracker, planBuilder hooks.ExecutionPlanBuilder) httprouter.Handle {
+	encoder := usersync.NewEncoder(&cfg.HostCookie)
+	decoder := usersync.NewDecoder(&cfg.HostCookie)
 
 	return httprouter.Handle(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 		so := analytics.SetUIDObject{
@@ -47,9 +51,24 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 			Errors: make([]error, 0),
 		}
 
-		defer analyticsRunner.LogSetUIDObject(&so)
+		hookExecutor := hookexecution.NewHookExecutor(planBuilder, hookexecution.EndpointSetUID, metricsEngine)
 
-		cookie := usersync.ReadCookie(r, decoder, &cfg.HostCookie)
+		defer func() {
+			if outcomes := hookExecutor.GetOutcomes(); len(outcomes) > 0 {
+				so.HookExecutionOutcome = outcomes
+			}
+			analyticsRunner.LogSetUIDObject(&so)
+		}()
+
+		cookie, err := usersync.ReadCookieWithError(r, decoder, &cfg.HostCookie)
+		if err != nil {
+			// A cookie which fails to decrypt is replaced, the sync goes on
+			glog.Warningf("/setuid failed to read the uids cookie: %v", err)
+			metricsEngine.RecordSetUid(metrics.SetUidCookieDecryptFailed)
+		}
+		if err := usersync.ReadStoredUIDs(r.Context(), r, cookie, uidStore, &cfg.UIDStore); err != nil {
+			glog.Warningf("/setuid failed to read the stored UIDs: %v", err)
+		}
 		if !cookie.AllowSyncs() {
 			handleBadStatus(w, http.StatusUnauthorized, metrics.SetUidOptOut, nil, metricsEngine, &so)
 			return
@@ -109,6 +128,12 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 		policies := privacy.Policies{
 			GPPSID: gppSID,
 		}
+		if gppQueryValue := query.Get("gpp"); len(gppQueryValue) > 0 {
+			// a malformed GPP string is rejected when the GDPR consent is read from it
+			if gpp, errs := gpplib.Parse(gppQueryValue); len(errs) == 0 {
+				policies.GPP = gpp
+			}
+		}
 
 		userSyncActivityAllowed := activityControl.Allow(privacy.ActivitySyncUser,
 			privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName},
@@ -142,7 +167,21 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 			return
 		}
 
-		uid := query.Get("uid")
+		hookExecutor.SetAccount(account)
+		hookExecutor.SetActivityControl(activityControl)
+		payload, rejectErr := hookExecutor.ExecuteSetUIDRequestStage(hookstage.SetUIDRequestPayload{
+			Request: r,
+			Account: accountID,
+			Bidder:  bidderName,
+			UID:     query.Get("uid"),
+		})
+		if rejectErr != nil {
+			// Like a sync of a non priority bidder, the request succeeds but the cookie isn't updated
+			handleBadStatus(w, http.StatusOK, metrics.SetUidModuleRejected, rejectErr, metricsEngine, &so)
+			return
+		}
+
+		uid := payload.UID
 		so.UID = uid
 
 		if uid == "" {
@@ -153,14 +192,21 @@ func NewSetUIDEndpoint(cfg *config.Configuration, syncersByBidder map[string]use
 		} else if err = cookie.Sync(syncer.Key(), uid); err == nil {
 			metricsEngine.RecordSetUid(metrics.SetUidOK)
 			metricsEngine.RecordSyncerSet(syncer.Key(), metrics.SyncerSetUidOK)
+			syncValueTracker.RecordSyncCompleted(syncer.Key())
 			so.Success = true
 		}
 
 		setSiteCookie := siteCookieCheck(r.UserAgent())
 
+		// The store keeps all the UIDs, including those ejected from the cookie below for its size
+		if err := usersync.WriteStoredUIDs(r.Context(), w, r, cookie, uidStore, &cfg.UIDStore, &cfg.HostCookie, setSiteCookie); err != nil {
+			glog.Warningf("/setuid failed to save the stored UIDs: %v", err)
+		}
+
 		// Priority Ejector Set Up
-		priorityEjector := &usersync.PriorityBidderEjector{PriorityGroups: cfg.UserSync.PriorityGroups, TieEjector: &usersync.OldestEjector{}, SyncersByBidder: syncersByBidder}
-		priorityEjector.IsSyncerPriority = isSyncerPriority(bidderName, cfg.UserSync.PriorityGroups)
+		priorityGroups := cfg.Current().UserSync.PriorityGroups
+		priorityEjector := &usersync.PriorityBidderEjector{PriorityGroups: priorityGroups, TieEjector: &usersync.OldestEjector{}, SyncersByBidder: syncersByBidder}
+		priorityEjector.IsSyncerPriority = isSyncerPriority(bidderName, priorityGroups)
 
 		// Write Cookie
 		encodedCookie, err := cookie.PrepareCookieForWrite(&cfg.HostCookie, encoder, priorityEjector)
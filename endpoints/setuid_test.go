@@ -17,11 +17,14 @@ import (
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
 	"github.com/prebid/prebid-server/v3/macros"
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
 )
@@ -360,7 +363,7 @@ func TestSetUIDEndpoint(t *testing.T) {
 		},
 	}
 
-	analytics := analyticsBuild.New(&config.Analytics{})
+	analytics := analyticsBuild.New(&config.Analytics{}, &metricsConf.NilMetricsEngine{})
 	metrics := &metricsConf.NilMetricsEngine{}
 
 	for _, test := range testCases {
@@ -394,7 +397,7 @@ func TestSetUIDEndpoint(t *testing.T) {
 
 func TestSetUIDPriorityEjection(t *testing.T) {
 	decoder := usersync.Base64Decoder{}
-	analytics := analyticsBuild.New(&config.Analytics{})
+	analytics := analyticsBuild.New(&config.Analytics{}, &metricsConf.NilMetricsEngine{})
 	syncersByBidder := map[string]string{
 		"pubmatic":             "pubmatic",
 		"syncer1":              "syncer1",
@@ -1363,13 +1366,177 @@ func TestSetUIDEndpointMetrics(t *testing.T) {
 	}
 }
 
+func TestSetUIDEndpointUIDStore(t *testing.T) {
+	cfg := config.Configuration{
+		UIDStore: config.UIDStore{
+			Enabled:      true,
+			IDCookieName: "pbs_fpid",
+			TTLSeconds:   60,
+			MaxUsers:     10,
+		},
+	}
+	cfg.UserSync.PriorityGroups = [][]string{{"pubmatic", "appnexus"}}
+	cfg.MarshalAccountDefaults()
+
+	store := usersync.NewUIDStore(cfg.UIDStore)
+	stored := usersync.NewCookie()
+	stored.Sync("adnxs", "456")
+	assert.NoError(t, store.Save(context.Background(), "user1", stored))
+
+	syncersByBidder := map[string]usersync.Syncer{
+		"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame},
+		"appnexus": fakeSyncer{key: "adnxs", defaultSyncType: usersync.SyncTypeIFrame},
+	}
+	gdprPermsBuilder := fakePermissionsBuilder{
+		permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+	analytics := analyticsBuild.New(&config.Analytics{}, &metricsConf.NilMetricsEngine{})
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, FakeAccountsFetcher{}, &metricsConf.NilMetricsEngine{}, store, nil, hooks.EmptyPlanBuilder{})
+
+	request := httptest.NewRequest("GET", "/setuid?bidder=pubmatic&uid=123", nil)
+	request.AddCookie(&http.Cookie{Name: "pbs_fpid", Value: "user1"})
+	response := httptest.NewRecorder()
+	endpoint(response, request, nil)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	cookie := parseCookieString(t, response)
+	assert.True(t, cookie.HasLiveSync("pubmatic"), "cookie should hold the new UID")
+	assert.True(t, cookie.HasLiveSync("adnxs"), "cookie should hold the stored UID")
+
+	saved, err := store.Get(context.Background(), "user1")
+	assert.NoError(t, err)
+	if assert.NotNil(t, saved) {
+		uid, _, _ := saved.GetUID("pubmatic")
+		assert.Equal(t, "123", uid, "store should hold the new UID")
+		uid, _, _ = saved.GetUID("adnxs")
+		assert.Equal(t, "456", uid, "store should keep the existing UID")
+	}
+}
+
+func TestSetUIDEndpointEncryptedCookie(t *testing.T) {
+	cfg := config.Configuration{
+		HostCookie: config.HostCookie{
+			Encryption: config.CookieEncryption{
+				Enabled:     true,
+				Keys:        map[string]string{"2025": "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="},
+				ActiveKeyID: "2025",
+			},
+		},
+	}
+	cfg.UserSync.PriorityGroups = [][]string{{"pubmatic"}}
+	cfg.MarshalAccountDefaults()
+
+	syncersByBidder := map[string]usersync.Syncer{
+		"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame},
+	}
+	gdprPermsBuilder := fakePermissionsBuilder{
+		permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true},
+	}.Builder
+	tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+		cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+	}.Builder
+	analytics := analyticsBuild.New(&config.Analytics{}, &metricsConf.NilMetricsEngine{})
+
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordSetUid", metrics.SetUidCookieDecryptFailed).Once()
+	metricsEngine.On("RecordSetUid", metrics.SetUidOK).Once()
+	metricsEngine.On("RecordSyncerSet", "pubmatic", metrics.SyncerSetUidOK).Once()
+
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, FakeAccountsFetcher{}, metricsEngine, nil, nil, hooks.EmptyPlanBuilder{})
+
+	// A forged plain cookie isn't accepted once the legacy format is disallowed
+	request := makeRequest("/setuid?bidder=pubmatic&uid=123", map[string]string{"adnxs": "forged"})
+	response := httptest.NewRecorder()
+	endpoint(response, request, nil)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	metricsEngine.AssertExpectations(t)
+
+	parser := regexp.MustCompile("uids=(.*?);")
+	res := parser.FindStringSubmatch(response.Header().Get("Set-Cookie"))
+	if assert.Equal(t, 2, len(res)) {
+		assert.Empty(t, usersync.Base64Decoder{}.Decode(res[1]).GetUIDs(), "cookie should not be readable as plain base64")
+
+		cookie := usersync.NewDecoder(&cfg.HostCookie).Decode(res[1])
+		assert.True(t, cookie.HasLiveSync("pubmatic"))
+		assert.False(t, cookie.HasLiveSync("adnxs"))
+	}
+}
+
+func TestSetUIDEndpointHooks(t *testing.T) {
+	testCases := []struct {
+		description    string
+		hook           hookstage.SetUIDRequest
+		expectedMetric metrics.SetUidStatus
+		expectedUID    string
+	}{
+		{
+			description:    "Hook mutation replaces the uid",
+			hook:           fakeSetUIDRequestHook{uid: "456"},
+			expectedMetric: metrics.SetUidOK,
+			expectedUID:    "456",
+		},
+		{
+			description:    "Hook rejection leaves the cookie unchanged",
+			hook:           fakeSetUIDRequestHook{reject: true},
+			expectedMetric: metrics.SetUidModuleRejected,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := config.Configuration{}
+			cfg.UserSync.PriorityGroups = [][]string{{"pubmatic"}}
+			cfg.MarshalAccountDefaults()
+
+			syncersByBidder := map[string]usersync.Syncer{
+				"pubmatic": fakeSyncer{key: "pubmatic", defaultSyncType: usersync.SyncTypeIFrame},
+			}
+			gdprPermsBuilder := fakePermissionsBuilder{
+				permissions: &fakePermsSetUID{allowHost: true, personalInfoAllowed: true},
+			}.Builder
+			tcf2ConfigBuilder := fakeTCF2ConfigBuilder{
+				cfg: gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+			}.Builder
+			analytics := analyticsBuild.New(&config.Analytics{}, &metricsConf.NilMetricsEngine{})
+
+			metricsEngine := &metrics.MetricsEngineMock{}
+			metricsEngine.On("RecordSetUid", test.expectedMetric).Once()
+			metricsEngine.On("RecordSyncerSet", "pubmatic", metrics.SyncerSetUidOK).Maybe()
+			metricsEngine.On("RecordModuleCalled", mock.Anything, mock.Anything).Maybe()
+			metricsEngine.On("RecordModuleSuccessUpdated", mock.Anything, mock.Anything).Maybe()
+			metricsEngine.On("RecordModuleSuccessRejected", mock.Anything, mock.Anything).Maybe()
+
+			planBuilder := fakeSetUIDPlanBuilder{hook: test.hook}
+			endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, FakeAccountsFetcher{}, metricsEngine, nil, nil, planBuilder)
+
+			response := httptest.NewRecorder()
+			endpoint(response, makeRequest("/setuid?bidder=pubmatic&uid=123", nil), nil)
+
+			assert.Equal(t, http.StatusOK, response.Code)
+			metricsEngine.AssertExpectations(t)
+
+			if test.expectedUID == "" {
+				assert.Empty(t, response.Header().Get("Set-Cookie"), "cookie should not be written")
+				return
+			}
+			uid, _, _ := parseCookieString(t, response).GetUID("pubmatic")
+			assert.Equal(t, test.expectedUID, uid)
+		})
+	}
+}
+
 func TestOptedOut(t *testing.T) {
 	request := httptest.NewRequest("GET", "/setuid?bidder=pubmatic&uid=123", nil)
 	cookie := usersync.NewCookie()
 	cookie.SetOptOut(true)
 	addCookie(request, cookie)
 	syncersBidderNameToKey := map[string]string{"pubmatic": "pubmatic"}
-	analytics := analyticsBuild.New(&config.Analytics{})
+	analytics := analyticsBuild.New(&config.Analytics{}, &metricsConf.NilMetricsEngine{})
 	metrics := &metricsConf.NilMetricsEngine{}
 	response := doRequest(request, analytics, metrics, syncersBidderNameToKey, true, false, false, false, 0, nil, "")
 
@@ -1639,7 +1806,7 @@ func doRequest(req *http.Request, analytics analytics.Runner, metrics metrics.Me
 		"valid_acct_with_invalid_activities":                 json.RawMessage(`{"privacy":{"allowactivities":{"syncUser":{"rules":[{"condition":{"componentName": ["bidderA.bidderB.bidderC"]}}]}}}}`),
 	}}
 
-	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, fakeAccountsFetcher, metrics)
+	endpoint := NewSetUIDEndpoint(&cfg, syncersByBidder, gdprPermsBuilder, tcf2ConfigBuilder, analytics, fakeAccountsFetcher, metrics, nil, nil, hooks.EmptyPlanBuilder{})
 	response := httptest.NewRecorder()
 	endpoint(response, req, nil)
 	return response
@@ -1766,3 +1933,34 @@ func getUIDFromHeader(setCookieHeader string) string {
 	}
 	return ""
 }
+
+type fakeSetUIDRequestHook struct {
+	uid    string
+	reject bool
+}
+
+func (h fakeSetUIDRequestHook) HandleSetUIDRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.SetUIDRequestPayload) (hookstage.HookResult[hookstage.SetUIDRequestPayload], error) {
+	if h.reject {
+		return hookstage.HookResult[hookstage.SetUIDRequestPayload]{Reject: true}, nil
+	}
+	c := hookstage.ChangeSet[hookstage.SetUIDRequestPayload]{}
+	c.AddMutation(func(payload hookstage.SetUIDRequestPayload) (hookstage.SetUIDRequestPayload, error) {
+		payload.UID = h.uid
+		return payload, nil
+	}, hookstage.MutationUpdate, "uid")
+	return hookstage.HookResult[hookstage.SetUIDRequestPayload]{ChangeSet: c}, nil
+}
+
+type fakeSetUIDPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook hookstage.SetUIDRequest
+}
+
+func (b fakeSetUIDPlanBuilder) PlanForSetUIDRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.SetUIDRequest] {
+	return hooks.Plan[hookstage.SetUIDRequest]{
+		hooks.Group[hookstage.SetUIDRequest]{
+			Timeout: 100 * time.Millisecond,
+			Hooks:   []hooks.HookWrapper[hookstage.SetUIDRequest]{{Module: "foobar", Code: "foo", Hook: b.hook}},
+		},
+	}
+}
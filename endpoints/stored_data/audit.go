@@ -0,0 +1,80 @@
+package stored_data
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// AuditEntry records one change made through the stored data admin API.
+type AuditEntry struct {
+	Time time.Time `json:"time"`
+	// UnverifiedUser is the admin the caller claims to be, from the X-Prebid-Admin-User header or the basic
+	// auth user name. Prebid Server doesn't authenticate the callers of the admin port, so it's only as
+	// trustworthy as whatever guards access to that port.
+	UnverifiedUser string `json:"unverified_user,omitempty"`
+	// RemoteAddr is the address the change was made from.
+	RemoteAddr string `json:"remote_addr"`
+	Action     string `json:"action"`
+	Type       string `json:"type"`
+	ID         string `json:"id"`
+	// Diff is the JSON merge patch (RFC 7386) which turns the previous data into the saved data.
+	Diff json.RawMessage `json:"diff,omitempty"`
+	// Previous is the data which was deleted.
+	Previous json.RawMessage `json:"previous,omitempty"`
+}
+
+// Audit log actions
+const (
+	ActionSave   = "save"
+	ActionDelete = "delete"
+)
+
+// AuditLog records who changed what through the stored data admin API.
+type AuditLog interface {
+	Record(entry AuditEntry)
+}
+
+// NewAuditLog returns an AuditLog which appends JSON lines to the given file, or which logs with glog if
+// the file is empty.
+func NewAuditLog(file string) (AuditLog, error) {
+	if file == "" {
+		return glogAuditLog{}, nil
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the stored data audit log: %v", err)
+	}
+	return &fileAuditLog{file: f}, nil
+}
+
+type glogAuditLog struct{}
+
+func (glogAuditLog) Record(entry AuditEntry) {
+	line, _ := jsonutil.Marshal(entry)
+	glog.Infof("Stored data changed: %s", line)
+}
+
+type fileAuditLog struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+func (l *fileAuditLog) Record(entry AuditEntry) {
+	line, err := jsonutil.Marshal(entry)
+	if err != nil {
+		glog.Errorf("Failed to write the stored data audit log for %s %s: %v", entry.Type, entry.ID, err)
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		glog.Errorf("Failed to write the stored data audit log for %s %s: %v", entry.Type, entry.ID, err)
+	}
+}
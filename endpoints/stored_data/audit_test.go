@@ -0,0 +1,39 @@
+package stored_data
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileAuditLog(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := NewAuditLog(file)
+	require.NoError(t, err)
+
+	entryTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	auditLog.Record(AuditEntry{Time: entryTime, UnverifiedUser: "admin", RemoteAddr: "10.0.0.1:1234", Action: ActionSave, Type: "accounts", ID: "pub", Diff: json.RawMessage(`{"debug_allow":true}`)})
+	auditLog.Record(AuditEntry{Time: entryTime, UnverifiedUser: "admin", RemoteAddr: "10.0.0.1:1234", Action: ActionDelete, Type: "imps", ID: "imp", Previous: json.RawMessage(`{"id":"imp"}`)})
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"time":"2024-01-02T03:04:05Z","unverified_user":"admin","remote_addr":"10.0.0.1:1234","action":"save","type":"accounts","id":"pub","diff":{"debug_allow":true}}`, lines[0])
+	assert.JSONEq(t, `{"time":"2024-01-02T03:04:05Z","unverified_user":"admin","remote_addr":"10.0.0.1:1234","action":"delete","type":"imps","id":"imp","previous":{"id":"imp"}}`, lines[1])
+}
+
+func TestNewAuditLog(t *testing.T) {
+	auditLog, err := NewAuditLog("")
+	assert.NoError(t, err)
+	assert.IsType(t, glogAuditLog{}, auditLog)
+
+	_, err = NewAuditLog(filepath.Join(t.TempDir(), "missing", "audit.log"))
+	assert.Error(t, err)
+}
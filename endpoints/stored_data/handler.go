@@ -0,0 +1,288 @@
+// Package stored_data serves the admin API which manages Stored Requests, Stored Imps and Accounts.
+package stored_data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	jsonpatch "gopkg.in/evanphx/json-patch.v5"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+
+	// userHeader names the admin making a change, for the audit log. The basic auth user name is used if
+	// it's missing. Neither is verified.
+	userHeader = "X-Prebid-Admin-User"
+)
+
+// dataTypes maps the {type} path segment to the stored data it manages.
+var dataTypes = map[string]stored_requests.DataType{
+	"requests": stored_requests.RequestData,
+	"imps":     stored_requests.ImpData,
+	"accounts": stored_requests.AccountData,
+}
+
+// NewHandler returns the stored data admin API:
+//
+//	GET    /stored_data/{type}?offset=0&limit=100  lists the IDs in ascending order
+//	GET    /stored_data/{type}/{id}                returns the stored data
+//	PUT    /stored_data/{type}/{id}                validates and saves the body. With ?dry_run=true, it only
+//	                                               validates it and returns the diff.
+//	DELETE /stored_data/{type}/{id}                deletes the stored data
+//
+// where {type} is "requests", "imps" or "accounts". Every save and delete is recorded in the auditLog.
+func NewHandler(writer stored_requests.Writer, requestValidator ortb.RequestValidator, cfg *config.Configuration, auditLog AuditLog) http.Handler {
+	h := &handler{
+		writer:           writer,
+		requestValidator: requestValidator,
+		cfg:              cfg,
+		auditLog:         auditLog,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stored_data/{type}", h.list)
+	mux.HandleFunc("GET /stored_data/{type}/{id}", h.get)
+	mux.HandleFunc("PUT /stored_data/{type}/{id}", h.save)
+	mux.HandleFunc("DELETE /stored_data/{type}/{id}", h.delete)
+	return mux
+}
+
+type handler struct {
+	writer           stored_requests.Writer
+	requestValidator ortb.RequestValidator
+	cfg              *config.Configuration
+	auditLog         AuditLog
+}
+
+type listResponse struct {
+	IDs     []string `json:"ids"`
+	Offset  int      `json:"offset"`
+	Limit   int      `json:"limit"`
+	HasMore bool     `json:"has_more"`
+}
+
+// saveResponse describes the outcome of a PUT. Errors reject the data, warnings don't.
+type saveResponse struct {
+	Valid    bool            `json:"valid"`
+	Saved    bool            `json:"saved"`
+	Errors   []string        `json:"errors"`
+	Warnings []string        `json:"warnings"`
+	Diff     json.RawMessage `json:"diff,omitempty"`
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	dataType, ok := parseDataType(w, r)
+	if !ok {
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		http.Error(w, "offset must be a non-negative integer", http.StatusBadRequest)
+		return
+	}
+	limit, err := queryInt(r, "limit", defaultListLimit)
+	if err != nil || limit < 1 || limit > maxListLimit {
+		http.Error(w, fmt.Sprintf("limit must be an integer between 1 and %d", maxListLimit), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := h.context(r)
+	defer cancel()
+	// One more ID than asked for tells whether there's another page
+	ids, err := h.writer.List(ctx, dataType, offset, limit+1)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := listResponse{IDs: ids, Offset: offset, Limit: limit}
+	if len(ids) > limit {
+		response.IDs = ids[:limit]
+		response.HasMore = true
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	dataType, ok := parseDataType(w, r)
+	if !ok {
+		return
+	}
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	data, err := h.writer.Get(ctx, dataType, r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (h *handler) save(w http.ResponseWriter, r *http.Request) {
+	dataType, ok := parseDataType(w, r)
+	if !ok {
+		return
+	}
+	id := r.PathValue("id")
+	if err := stored_requests.ValidateID(id); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	reader := io.Reader(r.Body)
+	if maxSize := h.cfg.MaxRequestSize; maxSize > 0 {
+		reader = io.LimitReader(r.Body, maxSize)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read the request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var response saveResponse
+	response.Errors, response.Warnings = splitErrors(h.validate(dataType, id, data))
+	response.Valid = len(response.Errors) == 0
+	if !response.Valid {
+		writeJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	previous, err := h.writer.Get(ctx, dataType, id)
+	if err != nil && !isNotFound(err) {
+		writeError(w, err)
+		return
+	}
+	if previous == nil {
+		previous = json.RawMessage(`{}`)
+	}
+	if response.Diff, err = jsonpatch.CreateMergePatch(previous, data); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to compare with the saved data: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if !dryRun {
+		if err := h.writer.Save(ctx, dataType, id, data); err != nil {
+			writeError(w, err)
+			return
+		}
+		response.Saved = true
+		h.auditLog.Record(AuditEntry{
+			Time:           time.Now().UTC(),
+			UnverifiedUser: unverifiedUser(r),
+			RemoteAddr:     r.RemoteAddr,
+			Action:         ActionSave,
+			Type:           r.PathValue("type"),
+			ID:             id,
+			Diff:           response.Diff,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *handler) delete(w http.ResponseWriter, r *http.Request) {
+	dataType, ok := parseDataType(w, r)
+	if !ok {
+		return
+	}
+	id := r.PathValue("id")
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	previous, err := h.writer.Get(ctx, dataType, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := h.writer.Delete(ctx, dataType, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	h.auditLog.Record(AuditEntry{
+		Time:           time.Now().UTC(),
+		UnverifiedUser: unverifiedUser(r),
+		RemoteAddr:     r.RemoteAddr,
+		Action:         ActionDelete,
+		Type:           r.PathValue("type"),
+		ID:             id,
+		Previous:       previous,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) context(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(r.Context(), time.Duration(h.cfg.StoredRequestsTimeout)*time.Millisecond)
+}
+
+func parseDataType(w http.ResponseWriter, r *http.Request) (stored_requests.DataType, bool) {
+	dataType, ok := dataTypes[r.PathValue("type")]
+	if !ok {
+		http.Error(w, fmt.Sprintf(`Unknown stored data type "%s". Use "requests", "imps" or "accounts".`, r.PathValue("type")), http.StatusNotFound)
+	}
+	return dataType, ok
+}
+
+func queryInt(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// unverifiedUser returns who the caller claims to be, for the audit log.
+func unverifiedUser(r *http.Request) string {
+	if name := r.Header.Get(userHeader); name != "" {
+		return name
+	}
+	if name, _, ok := r.BasicAuth(); ok {
+		return name
+	}
+	return ""
+}
+
+func isNotFound(err error) bool {
+	var notFound stored_requests.NotFoundError
+	return errors.As(err, &notFound)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case isNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, stored_requests.ErrWriteUnsupported):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	default:
+		glog.Errorf("Stored data admin API error: %v", err)
+		http.Error(w, fmt.Sprintf("Stored data backend error: %v", err), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, response interface{}) {
+	body, err := jsonutil.Marshal(response)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to marshal the response: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
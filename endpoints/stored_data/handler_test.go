@@ -0,0 +1,285 @@
+package stored_data
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/file_fetcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validImp = `{"id":"imp-1","banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":12883451}}}}}`
+
+func TestHandler(t *testing.T) {
+	testCases := []struct {
+		description    string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+		expectedAudit  []AuditEntry
+	}{
+		{
+			description:    "list",
+			method:         http.MethodGet,
+			path:           "/stored_data/requests?limit=1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"ids":["a"],"offset":0,"limit":1,"has_more":true}`,
+		},
+		{
+			description:    "list-last-page",
+			method:         http.MethodGet,
+			path:           "/stored_data/requests?offset=1&limit=5",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"ids":["b"],"offset":1,"limit":5,"has_more":false}`,
+		},
+		{
+			description:    "list-invalid-limit",
+			method:         http.MethodGet,
+			path:           "/stored_data/requests?limit=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "limit must be an integer between 1 and 1000\n",
+		},
+		{
+			description:    "unknown-type",
+			method:         http.MethodGet,
+			path:           "/stored_data/responses",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `Unknown stored data type "responses". Use "requests", "imps" or "accounts".` + "\n",
+		},
+		{
+			description:    "get",
+			method:         http.MethodGet,
+			path:           "/stored_data/requests/a",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":"a","tmax":500}`,
+		},
+		{
+			description:    "get-not-found",
+			method:         http.MethodGet,
+			path:           "/stored_data/imps/missing",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `Stored imp with ID="missing" not found.` + "\n",
+		},
+		{
+			description:    "dry-run",
+			method:         http.MethodPut,
+			path:           "/stored_data/requests/a?dry_run=true",
+			body:           `{"id":"a","tmax":800}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"valid":true,"saved":false,"errors":[],"warnings":[],"diff":{"tmax":800}}`,
+		},
+		{
+			description:    "save-request",
+			method:         http.MethodPut,
+			path:           "/stored_data/requests/a",
+			body:           `{"id":"a","imp":[` + validImp + `]}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"valid":true,"saved":true,"errors":[],"warnings":[],"diff":{"imp":[` + validImp + `],"tmax":null}}`,
+			expectedAudit: []AuditEntry{{UnverifiedUser: "admin", Action: ActionSave, Type: "requests", ID: "a",
+				Diff: json.RawMessage(`{"imp":[` + validImp + `],"tmax":null}`)}},
+		},
+		{
+			description:    "save-invalid-imp",
+			method:         http.MethodPut,
+			path:           "/stored_data/imps/new",
+			body:           `{"banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"bidder":{"appnexus":{}}}}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "save-imp-without-id",
+			method:         http.MethodPut,
+			path:           "/stored_data/imps/new",
+			body:           `{"banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"valid":true,"saved":true,"errors":[],"warnings":[],"diff":{"banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}}}`,
+			expectedAudit: []AuditEntry{{UnverifiedUser: "admin", Action: ActionSave, Type: "imps", ID: "new",
+				Diff: json.RawMessage(`{"banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}}`)}},
+		},
+		{
+			description:    "save-not-an-object",
+			method:         http.MethodPut,
+			path:           "/stored_data/requests/a",
+			body:           `[]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"valid":false,"saved":false,"errors":["stored data must be a JSON object"],"warnings":[]}`,
+		},
+		{
+			description:    "save-invalid-account",
+			method:         http.MethodPut,
+			path:           "/stored_data/accounts/pub",
+			body:           `{"traffic_shaping":{"exploration_rate":2}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"valid":false,"saved":false,"errors":["account_defaults.traffic_shaping.exploration_rate should be between 0 and 1"],"warnings":[]}`,
+		},
+		{
+			description:    "save-account-with-other-id",
+			method:         http.MethodPut,
+			path:           "/stored_data/accounts/pub",
+			body:           `{"id":"other"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"valid":false,"saved":false,"errors":["account id \"other\" doesn't match the id \"pub\" it's saved with"],"warnings":[]}`,
+		},
+		{
+			description:    "save-account",
+			method:         http.MethodPut,
+			path:           "/stored_data/accounts/pub",
+			body:           `{"debug_allow":true}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"valid":true,"saved":true,"errors":[],"warnings":[],"diff":{"debug_allow":true}}`,
+			expectedAudit: []AuditEntry{{UnverifiedUser: "admin", Action: ActionSave, Type: "accounts", ID: "pub",
+				Diff: json.RawMessage(`{"debug_allow":true}`)}},
+		},
+		{
+			description:    "save-invalid-id",
+			method:         http.MethodPut,
+			path:           "/stored_data/requests/a%5Cb",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `invalid stored data ID "a\b"` + "\n",
+		},
+		{
+			description:    "delete",
+			method:         http.MethodDelete,
+			path:           "/stored_data/requests/b",
+			expectedStatus: http.StatusNoContent,
+			expectedAudit: []AuditEntry{{UnverifiedUser: "admin", Action: ActionDelete, Type: "requests", ID: "b",
+				Previous: json.RawMessage(`{"id":"b"}`)}},
+		},
+		{
+			description:    "delete-not-found",
+			method:         http.MethodDelete,
+			path:           "/stored_data/requests/missing",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `Stored request with ID="missing" not found.` + "\n",
+		},
+		{
+			description:    "wrong-method",
+			method:         http.MethodPost,
+			path:           "/stored_data/requests/a",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			writer := newTestWriter(t)
+			auditLog := &recordingAuditLog{}
+			handler := NewHandler(writer, newTestRequestValidator(t), newTestConfig(t), auditLog)
+
+			request := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			request.Header.Set(userHeader, "admin")
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			if strings.HasPrefix(test.expectedBody, "{") {
+				assert.JSONEq(t, test.expectedBody, recorder.Body.String())
+			} else if test.expectedBody != "" {
+				assert.Equal(t, test.expectedBody, recorder.Body.String())
+			}
+
+			require.Len(t, auditLog.entries, len(test.expectedAudit))
+			for i, entry := range auditLog.entries {
+				expected := test.expectedAudit[i]
+				assert.False(t, entry.Time.IsZero())
+				assert.Equal(t, expected.UnverifiedUser, entry.UnverifiedUser)
+				assert.Equal(t, request.RemoteAddr, entry.RemoteAddr)
+				assert.Equal(t, expected.Action, entry.Action)
+				assert.Equal(t, expected.Type, entry.Type)
+				assert.Equal(t, expected.ID, entry.ID)
+				assertOptionalJSON(t, expected.Diff, entry.Diff)
+				assertOptionalJSON(t, expected.Previous, entry.Previous)
+			}
+		})
+	}
+}
+
+func TestHandlerSavesToWriter(t *testing.T) {
+	writer := newTestWriter(t)
+	handler := NewHandler(writer, newTestRequestValidator(t), newTestConfig(t), &recordingAuditLog{})
+
+	request := httptest.NewRequest(http.MethodPut, "/stored_data/requests/c", strings.NewReader(`{"id":"c"}`))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	data, err := writer.Get(context.Background(), stored_requests.RequestData, "c")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"c"}`, string(data))
+
+	request = httptest.NewRequest(http.MethodPut, "/stored_data/requests/d?dry_run=1", strings.NewReader(`{"id":"d"}`))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	_, err = writer.Get(context.Background(), stored_requests.RequestData, "d")
+	assert.Equal(t, stored_requests.NotFoundError{ID: "d", DataType: "request"}, err, "dry runs must not save")
+}
+
+func TestHandlerWriteUnsupported(t *testing.T) {
+	handler := NewHandler(stored_requests.WritersByDataType{}, newTestRequestValidator(t), newTestConfig(t), &recordingAuditLog{})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/stored_data/accounts", nil))
+	assert.Equal(t, http.StatusNotImplemented, recorder.Code)
+}
+
+func TestUnverifiedUser(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Equal(t, "", unverifiedUser(request))
+
+	request.SetBasicAuth("basic-user", "secret")
+	assert.Equal(t, "basic-user", unverifiedUser(request))
+
+	request.Header.Set(userHeader, "header-user")
+	assert.Equal(t, "header-user", unverifiedUser(request))
+}
+
+func newTestWriter(t *testing.T) stored_requests.Writer {
+	fetcher, err := file_fetcher.NewFileFetcher(t.TempDir())
+	require.NoError(t, err)
+	writer := fetcher.(stored_requests.Writer)
+	require.NoError(t, writer.Save(context.Background(), stored_requests.RequestData, "a", json.RawMessage(`{"id":"a","tmax":500}`)))
+	require.NoError(t, writer.Save(context.Background(), stored_requests.RequestData, "b", json.RawMessage(`{"id":"b"}`)))
+	return writer
+}
+
+func newTestRequestValidator(t *testing.T) ortb.RequestValidator {
+	paramsValidator, err := openrtb_ext.NewBidderParamsValidator("../../static/bidder-params")
+	require.NoError(t, err)
+	return ortb.NewRequestValidator(map[string]openrtb_ext.BidderName{"appnexus": openrtb_ext.BidderAppnexus}, nil, paramsValidator)
+}
+
+func newTestConfig(t *testing.T) *config.Configuration {
+	cfg := &config.Configuration{
+		StoredRequestsTimeout: 1000,
+		AccountDefaults: config.Account{
+			PriceFloors: config.AccountPriceFloors{
+				Fetcher: config.AccountFloorFetch{Timeout: 3000, MaxAge: 86400, Period: 3600},
+			},
+		},
+	}
+	require.NoError(t, cfg.MarshalAccountDefaults())
+	return cfg
+}
+
+func assertOptionalJSON(t *testing.T, expected, actual json.RawMessage) {
+	if expected == nil {
+		assert.Nil(t, actual)
+		return
+	}
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+type recordingAuditLog struct {
+	entries []AuditEntry
+}
+
+func (l *recordingAuditLog) Record(entry AuditEntry) {
+	l.entries = append(l.entries, entry)
+}
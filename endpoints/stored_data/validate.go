@@ -0,0 +1,103 @@
+package stored_data
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/ortb"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	jsonpatch "gopkg.in/evanphx/json-patch.v5"
+)
+
+// placeholderImpID stands in for the ID of stored imps which leave it to the incoming request.
+const placeholderImpID = "stored-imp"
+
+// validate checks the stored data the way the auction endpoints would once it's merged into a request.
+// Stored Requests are partial, so only their imps are validated. Stored Imps are expected to be complete,
+// apart from their ID. Accounts are merged with the account defaults first.
+func (h *handler) validate(dataType stored_requests.DataType, id string, data json.RawMessage) []error {
+	var object map[string]json.RawMessage
+	if err := jsonutil.UnmarshalValid(data, &object); err != nil || object == nil {
+		return []error{fmt.Errorf("stored data must be a JSON object")}
+	}
+
+	switch dataType {
+	case stored_requests.RequestData:
+		return h.validateRequest(data)
+	case stored_requests.ImpData:
+		var imp openrtb2.Imp
+		if err := jsonutil.UnmarshalValid(data, &imp); err != nil {
+			return []error{err}
+		}
+		return h.validateImp(&imp, 0, nil)
+	case stored_requests.AccountData:
+		return h.validateAccount(id, data)
+	}
+	return nil
+}
+
+func (h *handler) validateRequest(data json.RawMessage) []error {
+	var request openrtb2.BidRequest
+	if err := jsonutil.UnmarshalValid(data, &request); err != nil {
+		return []error{err}
+	}
+
+	requestWrapper := &openrtb_ext.RequestWrapper{BidRequest: &request}
+	requestExt, err := requestWrapper.GetRequestExt()
+	if err != nil {
+		return []error{err}
+	}
+	var aliases map[string]string
+	if prebid := requestExt.GetPrebid(); prebid != nil {
+		aliases = prebid.Aliases
+	}
+
+	var errs []error
+	for i := range request.Imp {
+		errs = append(errs, h.validateImp(&request.Imp[i], i, aliases)...)
+	}
+	return errs
+}
+
+func (h *handler) validateImp(imp *openrtb2.Imp, index int, aliases map[string]string) []error {
+	if imp.ID == "" {
+		imp.ID = placeholderImpID
+	}
+	return h.requestValidator.ValidateImp(&openrtb_ext.ImpWrapper{Imp: imp}, ortb.ValidationConfig{}, index, aliases, false, nil)
+}
+
+func (h *handler) validateAccount(id string, data json.RawMessage) []error {
+	completeJSON, err := jsonpatch.MergePatch(h.cfg.Current().AccountDefaultsJSON(), data)
+	if err != nil {
+		return []error{err}
+	}
+
+	var account config.Account
+	if err := jsonutil.UnmarshalValid(completeJSON, &account); err != nil {
+		return []error{err}
+	}
+	if err := config.UnpackDSADefault(account.Privacy.DSA); err != nil {
+		return []error{err}
+	}
+	if account.ID != "" && account.ID != id {
+		return []error{fmt.Errorf(`account id "%s" doesn't match the id "%s" it's saved with`, account.ID, id)}
+	}
+	return account.Validate(nil)
+}
+
+// splitErrors separates the errors which would reject the stored data from the warnings.
+func splitErrors(errs []error) (fatal []string, warnings []string) {
+	fatal, warnings = []string{}, []string{}
+	for _, err := range errortypes.FatalOnly(errs) {
+		fatal = append(fatal, err.Error())
+	}
+	for _, err := range errortypes.WarningOnly(errs) {
+		warnings = append(warnings, err.Error())
+	}
+	return
+}
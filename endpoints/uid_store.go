@@ -0,0 +1,38 @@
+package endpoints
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/golang/glog"
+)
+
+type uidStoreDeleter interface {
+	Delete(ctx context.Context, id string) error
+}
+
+// NewUIDStoreDeleteEndpoint deletes the UIDs and opt out stored for a user, to fulfill GDPR and CCPA deletion
+// requests. The user's first-party ID is given by the required "id" query parameter.
+func NewUIDStoreDeleteEndpoint(store uidStoreDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+			w.Header().Set("Allow", "POST, DELETE")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`the "id" query parameter is required`))
+			return
+		}
+
+		if err := store.Delete(r.Context(), id); err != nil {
+			glog.Errorf("/uid_store/delete failed to delete the stored UIDs: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,79 @@
+package endpoints
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockUIDStoreDeleter struct {
+	deletedID string
+	err       error
+}
+
+func (m *mockUIDStoreDeleter) Delete(_ context.Context, id string) error {
+	m.deletedID = id
+	return m.err
+}
+
+func TestUIDStoreDeleteEndpoint(t *testing.T) {
+	testCases := []struct {
+		description       string
+		method            string
+		url               string
+		storeErr          error
+		expectedStatus    int
+		expectedDeletedID string
+	}{
+		{
+			description:       "delete",
+			method:            http.MethodDelete,
+			url:               "/uid_store/delete?id=user1",
+			expectedStatus:    http.StatusNoContent,
+			expectedDeletedID: "user1",
+		},
+		{
+			description:       "post",
+			method:            http.MethodPost,
+			url:               "/uid_store/delete?id=user1",
+			expectedStatus:    http.StatusNoContent,
+			expectedDeletedID: "user1",
+		},
+		{
+			description:    "get-not-allowed",
+			method:         http.MethodGet,
+			url:            "/uid_store/delete?id=user1",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			description:    "missing-id",
+			method:         http.MethodDelete,
+			url:            "/uid_store/delete",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:       "store-error",
+			method:            http.MethodDelete,
+			url:               "/uid_store/delete?id=user1",
+			storeErr:          errors.New("unavailable"),
+			expectedStatus:    http.StatusInternalServerError,
+			expectedDeletedID: "user1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			store := &mockUIDStoreDeleter{err: test.storeErr}
+			w := httptest.NewRecorder()
+
+			NewUIDStoreDeleteEndpoint(store)(w, httptest.NewRequest(test.method, test.url, nil))
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			assert.Equal(t, test.expectedDeletedID, store.deletedID)
+		})
+	}
+}
@@ -18,6 +18,7 @@ const (
 	FailedToMarshalErrorCode
 	FailedToUnmarshalErrorCode
 	InvalidImpFirstPartyDataErrorCode
+	CircuitBreakerOpenErrorCode
 )
 
 // Defines numeric codes for well-known warnings.
@@ -35,6 +36,8 @@ const (
 	InvalidBidResponseDSAWarningCode
 	SecCookieDeprecationLenWarningCode
 	SecBrowsingTopicsWarningCode
+	FloorCurrencyConversionWarningCode
+	VASTUnwrapWarningCode
 )
 
 // Coder provides an error or warning code with severity.
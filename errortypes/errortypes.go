@@ -39,6 +39,23 @@ func (err *TmaxTimeout) Severity() Severity {
 	return SeverityFatal
 }
 
+// CircuitBreakerOpen should be used when a request to a bidder is skipped because its circuit breaker is open
+type CircuitBreakerOpen struct {
+	Message string
+}
+
+func (err *CircuitBreakerOpen) Error() string {
+	return err.Message
+}
+
+func (err *CircuitBreakerOpen) Code() int {
+	return CircuitBreakerOpenErrorCode
+}
+
+func (err *CircuitBreakerOpen) Severity() Severity {
+	return SeverityFatal
+}
+
 // BadInput should be used when returning errors which are caused by bad input.
 // It should _not_ be used if the error is a server-side issue (e.g. failed to send the external request).
 //
@@ -0,0 +1,352 @@
+package exchange
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// maxAdPodSearchSteps bounds the search for the best combination of bids in a pod. Once it's reached,
+// the best combination found so far wins, which is never worse than filling the pod greedily by price.
+const maxAdPodSearchSteps = 100000
+
+// adPod is a group of imps which are filled together. The imps of an OpenRTB 2.6 pod share imp.video.podid.
+type adPod struct {
+	id string
+	// impIDs holds the pod imps in request order
+	impIDs []string
+	// capacity is the number of bids each imp can hold. 0 means unlimited.
+	capacity map[string]int
+	// duration is the total duration of the pod in seconds. 0 means unlimited.
+	duration int
+}
+
+// slots returns the number of bids the pod can hold, or 0 if it's unlimited.
+func (p *adPod) slots() int {
+	slots := 0
+	for _, impID := range p.impIDs {
+		if p.capacity[impID] == 0 {
+			return 0
+		}
+		slots += p.capacity[impID]
+	}
+	return slots
+}
+
+// hasAdPods returns whether any imp of the request belongs to an ad pod.
+func hasAdPods(req *openrtb2.BidRequest) bool {
+	for _, imp := range req.Imp {
+		if imp.Video != nil && imp.Video.PodID != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// buildAdPods groups the imps of the request into ad pods, in the order the pods first appear.
+func buildAdPods(req *openrtb2.BidRequest) []*adPod {
+	var pods []*adPod
+	podsByID := make(map[string]*adPod)
+	for _, imp := range req.Imp {
+		if imp.Video == nil || imp.Video.PodID == "" {
+			continue
+		}
+		pod, ok := podsByID[imp.Video.PodID]
+		if !ok {
+			pod = &adPod{id: imp.Video.PodID, capacity: make(map[string]int)}
+			podsByID[imp.Video.PodID] = pod
+			pods = append(pods, pod)
+		}
+		pod.impIDs = append(pod.impIDs, imp.ID)
+		pod.capacity[imp.ID] = 1
+		if imp.Video.MaxSeq > 0 {
+			pod.capacity[imp.ID] = int(imp.Video.MaxSeq)
+		}
+		if int(imp.Video.PodDur) > pod.duration {
+			pod.duration = int(imp.Video.PodDur)
+		}
+	}
+
+	// A single imp with a pod duration but no maximum number of ads is a dynamic pod, which is only
+	// limited by its duration
+	for _, imp := range req.Imp {
+		if imp.Video == nil || imp.Video.PodID == "" {
+			continue
+		}
+		if pod := podsByID[imp.Video.PodID]; len(pod.impIDs) == 1 && imp.Video.PodDur > 0 && imp.Video.MaxSeq == 0 {
+			pod.capacity[imp.ID] = 0
+		}
+	}
+	return pods
+}
+
+type adPodCandidate struct {
+	bidderName openrtb_ext.BidderName
+	bid        *entities.PbsOrtbBid
+	duration   int
+	// exclusions are the advertiser domains, categories and creatives of the bid.
+	// Two bids sharing any of them can't both win the same pod.
+	exclusions []string
+}
+
+// newAdPodCandidate returns the candidate of a bid. category is the category the bid was mapped to for
+// hb_pb_cat_dur, which is only set when ext.prebid.targeting.includebrandcategory.withcategory is true.
+func newAdPodCandidate(bidderName openrtb_ext.BidderName, bid *entities.PbsOrtbBid, category string) adPodCandidate {
+	candidate := adPodCandidate{
+		bidderName: bidderName,
+		bid:        bid,
+		duration:   int(bid.Bid.Dur),
+	}
+	if bid.BidVideo != nil && bid.BidVideo.Duration > 0 {
+		candidate.duration = bid.BidVideo.Duration
+	}
+	if category != "" {
+		candidate.exclusions = append(candidate.exclusions, "cat:"+category)
+	}
+	for _, domain := range bid.Bid.ADomain {
+		candidate.exclusions = append(candidate.exclusions, "adomain:"+strings.ToLower(domain))
+	}
+	if bid.Bid.AdM != "" {
+		candidate.exclusions = append(candidate.exclusions, "adm:"+bid.Bid.AdM)
+	}
+	if bid.Bid.CrID != "" {
+		candidate.exclusions = append(candidate.exclusions, fmt.Sprintf("crid:%s:%s", bidderName, bid.Bid.CrID))
+	}
+	return candidate
+}
+
+// adPodAuction picks the bids filling an ad pod.
+type adPodAuction struct {
+	pod        *adPod
+	candidates []adPodCandidate
+
+	steps int
+	// slots is the number of bids the pod can hold, or 0 if it's unlimited
+	slots int
+	// prices holds the sum of the prices of the candidates before each index
+	prices     []float64
+	selected   []int
+	sum        float64
+	duration   int
+	impUsage   map[string]int
+	exclusions map[string]bool
+
+	best    []int
+	bestSum float64
+}
+
+// run returns the indexes of the candidates which maximize the revenue of the pod without going over its
+// slots or duration, and without two winners sharing an advertiser domain, a category or a creative.
+// The candidates must be sorted by price, highest first.
+func (a *adPodAuction) run() []int {
+	a.slots = a.pod.slots()
+	a.prices = make([]float64, len(a.candidates)+1)
+	for i, candidate := range a.candidates {
+		a.prices[i+1] = a.prices[i] + candidate.bid.Bid.Price
+	}
+	a.impUsage = make(map[string]int)
+	a.exclusions = make(map[string]bool)
+	a.bestSum = -1
+	a.search(0)
+	return a.best
+}
+
+func (a *adPodAuction) search(i int) {
+	if a.steps >= maxAdPodSearchSteps {
+		return
+	}
+	a.steps++
+
+	if a.sum > a.bestSum {
+		a.bestSum = a.sum
+		a.best = append(a.best[:0], a.selected...)
+	}
+	if i == len(a.candidates) || a.bound(i) <= a.bestSum {
+		return
+	}
+
+	if a.fits(i) {
+		a.add(i)
+		a.search(i + 1)
+		a.remove(i)
+	}
+	a.search(i + 1)
+}
+
+// bound is the highest revenue the pod could reach by adding candidates from i onwards, which is the sum of
+// the prices of the next candidates for as many slots as are left.
+func (a *adPodAuction) bound(i int) float64 {
+	end := len(a.candidates)
+	if a.slots > 0 && i+a.slots-len(a.selected) < end {
+		end = i + a.slots - len(a.selected)
+	}
+	return a.sum + a.prices[end] - a.prices[i]
+}
+
+func (a *adPodAuction) fits(i int) bool {
+	candidate := a.candidates[i]
+	impID := candidate.bid.Bid.ImpID
+	if capacity := a.pod.capacity[impID]; capacity > 0 && a.impUsage[impID] >= capacity {
+		return false
+	}
+	if a.pod.duration > 0 && a.duration+candidate.duration > a.pod.duration {
+		return false
+	}
+	for _, exclusion := range candidate.exclusions {
+		if a.exclusions[exclusion] {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *adPodAuction) add(i int) {
+	candidate := a.candidates[i]
+	a.selected = append(a.selected, i)
+	a.sum += candidate.bid.Bid.Price
+	a.duration += candidate.duration
+	a.impUsage[candidate.bid.Bid.ImpID]++
+	for _, exclusion := range candidate.exclusions {
+		a.exclusions[exclusion] = true
+	}
+}
+
+func (a *adPodAuction) remove(i int) {
+	candidate := a.candidates[i]
+	a.selected = a.selected[:len(a.selected)-1]
+	a.sum -= candidate.bid.Bid.Price
+	a.duration -= candidate.duration
+	a.impUsage[candidate.bid.Bid.ImpID]--
+	for _, exclusion := range candidate.exclusions {
+		delete(a.exclusions, exclusion)
+	}
+}
+
+// applyAdPodAuction fills the ad pods of the request, and removes every pod bid which didn't win from seatBids.
+// Bids stay on the imp they were made for, so the slot position requested through imp.video.slotinpod is kept.
+// Bids with the same price are shuffled, so that no bidder is favored when breaking ties. categories holds the
+// category of each bid set by applyCategoryMapping, bids of the same category being exclusive.
+func applyAdPodAuction(req *openrtb2.BidRequest, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, bidCategory map[string]string, categories map[string]string, shuffle func(n int, swap func(i, j int)), seatNonBidBuilder *SeatNonBidBuilder) ([]string, []openrtb_ext.ExtAdPod) {
+	pods := buildAdPods(req)
+	if len(pods) == 0 {
+		return nil, nil
+	}
+
+	podsByImpID := make(map[string]*adPod)
+	for _, pod := range pods {
+		for _, impID := range pod.impIDs {
+			podsByImpID[impID] = pod
+		}
+	}
+
+	candidatesByPod := make(map[*adPod][]adPodCandidate)
+	bidderNames := make([]openrtb_ext.BidderName, 0, len(seatBids))
+	for bidderName := range seatBids {
+		bidderNames = append(bidderNames, bidderName)
+	}
+	sort.Slice(bidderNames, func(i, j int) bool { return bidderNames[i] < bidderNames[j] })
+	for _, bidderName := range bidderNames {
+		seatBid := seatBids[bidderName]
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			if pod, ok := podsByImpID[bid.Bid.ImpID]; ok {
+				candidatesByPod[pod] = append(candidatesByPod[pod], newAdPodCandidate(bidderName, bid, categories[bid.Bid.ID]))
+			}
+		}
+	}
+
+	var rejections []string
+	var results []openrtb_ext.ExtAdPod
+	for _, pod := range pods {
+		candidates := candidatesByPod[pod]
+		shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].bid.Bid.Price > candidates[j].bid.Bid.Price })
+
+		auction := &adPodAuction{pod: pod, candidates: candidates}
+		winners := make(map[int]bool)
+		for _, i := range auction.run() {
+			winners[i] = true
+		}
+
+		filledDuration := 0
+		filledImps := make(map[string]bool)
+		winnerExclusions := make(map[string]bool)
+		for i := range winners {
+			filledDuration += candidates[i].duration
+			filledImps[candidates[i].bid.Bid.ImpID] = true
+			for _, exclusion := range candidates[i].exclusions {
+				winnerExclusions[exclusion] = true
+			}
+		}
+
+		for i, candidate := range candidates {
+			if winners[i] {
+				continue
+			}
+			reason := "Bid was not selected for the ad pod"
+			if pod.duration > 0 && candidate.duration > pod.duration {
+				reason = fmt.Sprintf("Bid duration %ds exceeds the ad pod duration %ds", candidate.duration, pod.duration)
+			} else if candidate.excludedBy(winnerExclusions) {
+				reason = "Bid was excluded by competitive separation in the ad pod"
+			}
+			rejections = updateRejections(rejections, candidate.bid.Bid.ID, reason)
+			seatNonBidBuilder.rejectBid(candidate.bid, int(ResponseRejectedAdPod), candidate.bidderName.String())
+			removeBidById(seatBids[candidate.bidderName], candidate.bid.Bid.ID)
+			delete(bidCategory, candidate.bid.Bid.ID)
+		}
+
+		result := openrtb_ext.ExtAdPod{
+			PodID:          pod.id,
+			Slots:          pod.slots(),
+			FilledSlots:    len(winners),
+			Duration:       pod.duration,
+			FilledDuration: filledDuration,
+		}
+		if result.Slots > 0 {
+			result.UnfilledSlots = result.Slots - result.FilledSlots
+		}
+		for _, impID := range pod.impIDs {
+			if !filledImps[impID] {
+				result.UnfilledImpIDs = append(result.UnfilledImpIDs, impID)
+			}
+		}
+		results = append(results, result)
+	}
+
+	for _, seatBid := range seatBids {
+		if seatBid != nil && len(seatBid.Bids) == 0 {
+			seatBid.Bids = nil
+		}
+	}
+	return rejections, results
+}
+
+func (c adPodCandidate) excludedBy(exclusions map[string]bool) bool {
+	for _, exclusion := range c.exclusions {
+		if exclusions[exclusion] {
+			return true
+		}
+	}
+	return false
+}
+
+// unfilledAdPods reports the ad pods of a request which got no bids at all.
+func unfilledAdPods(req *openrtb2.BidRequest) []openrtb_ext.ExtAdPod {
+	var results []openrtb_ext.ExtAdPod
+	for _, pod := range buildAdPods(req) {
+		results = append(results, openrtb_ext.ExtAdPod{
+			PodID:          pod.id,
+			Slots:          pod.slots(),
+			UnfilledSlots:  pod.slots(),
+			Duration:       pod.duration,
+			UnfilledImpIDs: pod.impIDs,
+		})
+	}
+	return results
+}
@@ -0,0 +1,258 @@
+package exchange
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func noShuffle(n int, swap func(i, j int)) {}
+
+func reverseShuffle(n int, swap func(i, j int)) {
+	for i := 0; i < n/2; i++ {
+		swap(i, n-1-i)
+	}
+}
+
+func podImp(id, podID string, podDur, maxSeq int64) openrtb2.Imp {
+	return openrtb2.Imp{ID: id, Video: &openrtb2.Video{PodID: podID, PodDur: podDur, MaxSeq: maxSeq}}
+}
+
+func podBid(id, impID string, price float64, duration int, adomain, cat string) *entities.PbsOrtbBid {
+	bid := &openrtb2.Bid{ID: id, ImpID: impID, Price: price}
+	if adomain != "" {
+		bid.ADomain = []string{adomain}
+	}
+	if cat != "" {
+		bid.Cat = []string{cat}
+	}
+	return &entities.PbsOrtbBid{Bid: bid, BidType: openrtb_ext.BidTypeVideo, BidVideo: &openrtb_ext.ExtBidPrebidVideo{Duration: duration}}
+}
+
+func bidIDs(seatBid *entities.PbsOrtbSeatBid) []string {
+	var ids []string
+	for _, bid := range seatBid.Bids {
+		ids = append(ids, bid.Bid.ID)
+	}
+	return ids
+}
+
+func TestBuildAdPods(t *testing.T) {
+	testCases := []struct {
+		name     string
+		imps     []openrtb2.Imp
+		expected []*adPod
+	}{
+		{
+			name:     "no-pods",
+			imps:     []openrtb2.Imp{{ID: "imp1", Video: &openrtb2.Video{}}, {ID: "imp2"}},
+			expected: nil,
+		},
+		{
+			name: "structured-and-dynamic-pods",
+			imps: []openrtb2.Imp{
+				podImp("imp1", "pod1", 60, 0),
+				{ID: "imp2"},
+				podImp("imp3", "pod1", 60, 0),
+				podImp("imp4", "pod2", 90, 0),
+				podImp("imp5", "pod3", 0, 3),
+			},
+			expected: []*adPod{
+				{id: "pod1", impIDs: []string{"imp1", "imp3"}, capacity: map[string]int{"imp1": 1, "imp3": 1}, duration: 60},
+				{id: "pod2", impIDs: []string{"imp4"}, capacity: map[string]int{"imp4": 0}, duration: 90},
+				{id: "pod3", impIDs: []string{"imp5"}, capacity: map[string]int{"imp5": 3}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			req := &openrtb2.BidRequest{Imp: test.imps}
+			assert.Equal(t, test.expected, buildAdPods(req))
+			assert.Equal(t, len(test.expected) > 0, hasAdPods(req))
+		})
+	}
+}
+
+func TestApplyAdPodAuction(t *testing.T) {
+	testCases := []struct {
+		name               string
+		imps               []openrtb2.Imp
+		seatBids           map[openrtb_ext.BidderName][]*entities.PbsOrtbBid
+		withoutCategories  bool
+		expectedBids       map[openrtb_ext.BidderName][]string
+		expectedRejections []string
+		expectedAdPods     []openrtb_ext.ExtAdPod
+	}{
+		{
+			name: "optimizes-revenue-under-duration",
+			imps: []openrtb2.Imp{podImp("imp1", "pod1", 60, 0)},
+			seatBids: map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"appnexus": {podBid("a1", "imp1", 10, 45, "a.com", "")},
+				"rubicon":  {podBid("r1", "imp1", 7, 30, "b.com", ""), podBid("r2", "imp1", 6, 30, "c.com", "")},
+			},
+			// Greedily taking the 45s bid would leave no room for anything else, 7+6 beats 10
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"appnexus": nil,
+				"rubicon":  {"r1", "r2"},
+			},
+			expectedRejections: []string{
+				"bid rejected [bid ID: a1] reason: Bid was not selected for the ad pod",
+			},
+			expectedAdPods: []openrtb_ext.ExtAdPod{
+				{PodID: "pod1", Duration: 60, FilledSlots: 2, FilledDuration: 60},
+			},
+		},
+		{
+			name: "competitive-separation",
+			imps: []openrtb2.Imp{podImp("imp1", "pod1", 0, 0), podImp("imp2", "pod1", 0, 0), podImp("imp3", "pod1", 0, 0)},
+			seatBids: map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"appnexus": {podBid("a1", "imp1", 10, 30, "a.com", "IAB1"), podBid("a2", "imp2", 9, 30, "A.com", "IAB2")},
+				"rubicon":  {podBid("r1", "imp2", 8, 30, "b.com", "IAB1"), podBid("r2", "imp3", 5, 30, "c.com", "IAB3")},
+			},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"appnexus": {"a1"},
+				"rubicon":  {"r2"},
+			},
+			expectedRejections: []string{
+				"bid rejected [bid ID: a2] reason: Bid was excluded by competitive separation in the ad pod",
+				"bid rejected [bid ID: r1] reason: Bid was excluded by competitive separation in the ad pod",
+			},
+			expectedAdPods: []openrtb_ext.ExtAdPod{
+				{PodID: "pod1", Slots: 3, FilledSlots: 2, UnfilledSlots: 1, FilledDuration: 60, UnfilledImpIDs: []string{"imp2"}},
+			},
+		},
+		{
+			name: "categories-not-mapped",
+			imps: []openrtb2.Imp{podImp("imp1", "pod1", 0, 2)},
+			seatBids: map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"appnexus": {podBid("a1", "imp1", 10, 30, "a.com", "IAB1")},
+				"rubicon":  {podBid("r1", "imp1", 8, 30, "b.com", "IAB1")},
+			},
+			// Without includebrandcategory.withcategory the IAB categories of the bids aren't exclusive
+			withoutCategories: true,
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"appnexus": {"a1"},
+				"rubicon":  {"r1"},
+			},
+			expectedAdPods: []openrtb_ext.ExtAdPod{
+				{PodID: "pod1", Slots: 2, FilledSlots: 2, FilledDuration: 60},
+			},
+		},
+		{
+			name: "duplicate-creatives",
+			imps: []openrtb2.Imp{podImp("imp1", "pod1", 0, 2)},
+			seatBids: map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"appnexus": {
+					{Bid: &openrtb2.Bid{ID: "a1", ImpID: "imp1", Price: 5, AdM: "<VAST/>"}},
+					{Bid: &openrtb2.Bid{ID: "a2", ImpID: "imp1", Price: 4, AdM: "<VAST/>"}},
+					{Bid: &openrtb2.Bid{ID: "a3", ImpID: "imp1", Price: 3, CrID: "creative"}},
+					{Bid: &openrtb2.Bid{ID: "a4", ImpID: "imp1", Price: 2, CrID: "creative"}},
+				},
+			},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"appnexus": {"a1", "a3"},
+			},
+			expectedRejections: []string{
+				"bid rejected [bid ID: a2] reason: Bid was excluded by competitive separation in the ad pod",
+				"bid rejected [bid ID: a4] reason: Bid was excluded by competitive separation in the ad pod",
+			},
+			expectedAdPods: []openrtb_ext.ExtAdPod{
+				{PodID: "pod1", Slots: 2, FilledSlots: 2},
+			},
+		},
+		{
+			name: "bid-longer-than-pod",
+			imps: []openrtb2.Imp{podImp("imp1", "pod1", 30, 0), {ID: "imp2"}},
+			seatBids: map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"appnexus": {podBid("a1", "imp1", 10, 45, "", ""), podBid("a2", "imp2", 10, 45, "", "")},
+			},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"appnexus": {"a2"},
+			},
+			expectedRejections: []string{
+				"bid rejected [bid ID: a1] reason: Bid duration 45s exceeds the ad pod duration 30s",
+			},
+			expectedAdPods: []openrtb_ext.ExtAdPod{
+				{PodID: "pod1", Duration: 30, UnfilledImpIDs: []string{"imp1"}},
+			},
+		},
+		{
+			name: "no-pods",
+			imps: []openrtb2.Imp{{ID: "imp1"}},
+			seatBids: map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"appnexus": {podBid("a1", "imp1", 10, 30, "a.com", ""), podBid("a2", "imp1", 9, 30, "a.com", "")},
+			},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"appnexus": {"a1", "a2"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			seatBids := make(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
+			bidCategory := make(map[string]string)
+			categories := make(map[string]string)
+			for bidderName, bids := range test.seatBids {
+				seatBids[bidderName] = &entities.PbsOrtbSeatBid{Bids: bids, Currency: "USD"}
+				for _, bid := range bids {
+					bidCategory[bid.Bid.ID] = "cat_dur"
+					if len(bid.Bid.Cat) > 0 && !test.withoutCategories {
+						categories[bid.Bid.ID] = bid.Bid.Cat[0]
+					}
+				}
+			}
+			seatNonBidBuilder := SeatNonBidBuilder{}
+
+			rejections, adPods := applyAdPodAuction(&openrtb2.BidRequest{Imp: test.imps}, seatBids, bidCategory, categories, noShuffle, &seatNonBidBuilder)
+
+			assert.Equal(t, test.expectedRejections, rejections)
+			assert.Equal(t, test.expectedAdPods, adPods)
+			expectedCategory := make(map[string]string)
+			for bidderName, expectedIDs := range test.expectedBids {
+				assert.Equal(t, expectedIDs, bidIDs(seatBids[bidderName]), string(bidderName))
+				for _, id := range expectedIDs {
+					expectedCategory[id] = "cat_dur"
+				}
+			}
+			assert.Equal(t, expectedCategory, bidCategory)
+
+			rejectedCount := 0
+			for _, nonBids := range seatNonBidBuilder {
+				for _, nonBid := range nonBids {
+					assert.Equal(t, int(ResponseRejectedAdPod), nonBid.StatusCode)
+					rejectedCount++
+				}
+			}
+			assert.Equal(t, len(test.expectedRejections), rejectedCount)
+		})
+	}
+}
+
+func TestAdPodAuctionManyCandidates(t *testing.T) {
+	pod := &adPod{id: "pod1", impIDs: []string{"imp1"}, capacity: map[string]int{"imp1": 10}}
+	var candidates []adPodCandidate
+	for i := 0; i < 500; i++ {
+		bid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: fmt.Sprintf("bid%d", i), ImpID: "imp1", Price: float64(500 - i)}}
+		candidates = append(candidates, adPodCandidate{bidderName: "appnexus", bid: bid, duration: 30})
+	}
+
+	auction := &adPodAuction{pod: pod, candidates: candidates}
+
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, auction.run())
+	assert.Less(t, auction.steps, 100, "the bound should stop the search once the pod is full")
+}
+
+func TestUnfilledAdPods(t *testing.T) {
+	req := &openrtb2.BidRequest{Imp: []openrtb2.Imp{podImp("imp1", "pod1", 60, 0), podImp("imp2", "pod1", 60, 0), {ID: "imp3"}}}
+
+	assert.Equal(t, []openrtb_ext.ExtAdPod{
+		{PodID: "pod1", Slots: 2, UnfilledSlots: 2, Duration: 60, UnfilledImpIDs: []string{"imp1", "imp2"}},
+	}, unfilledAdPods(req))
+	assert.Nil(t, unfilledAdPods(&openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}}))
+}
@@ -264,7 +264,7 @@ func (a *auction) doCache(ctx context.Context, cache prebid_cache_client.Client,
 					}
 				}
 				if vast && topBid.BidType == openrtb_ext.BidTypeVideo {
-					vastXML := makeVAST(topBid.Bid)
+					vastXML := cachedVAST(topBid)
 					if jsonBytes, err := jsonutil.Marshal(vastXML); err == nil {
 						if useCustomCacheKey {
 							toCache = append(toCache, prebid_cache_client.Cacheable{
@@ -331,6 +331,15 @@ func (a *auction) doCache(ctx context.Context, cache prebid_cache_client.Client,
 	return errs
 }
 
+// cachedVAST returns the VAST XML to cache for the given bid, which is the inline VAST the bid resolved to
+// if VAST unwrapping inlined it.
+func cachedVAST(bid *entities.PbsOrtbBid) string {
+	if bid.InlineVAST != "" {
+		return bid.InlineVAST
+	}
+	return makeVAST(bid.Bid)
+}
+
 // makeVAST returns some VAST XML for the given bid. If AdM is defined,
 // it takes precedence. Otherwise the Nurl will be wrapped in a redirect tag.
 func makeVAST(bid *openrtb2.Bid) string {
@@ -0,0 +1,59 @@
+package exchange
+
+import (
+	"context"
+
+	gpplib "github.com/prebid/go-gpp"
+	"github.com/prebid/prebid-server/v3/auctioncapture"
+	"github.com/prebid/prebid-server/v3/gdpr"
+)
+
+// startAuctionCapture samples the auction for capture. The request is captured once the processed auction
+// hooks have run, as that's what a replay through HoldAuction starts from.
+func (e *exchange) startAuctionCapture(ctx context.Context, r *AuctionRequest) *auctioncapture.Session {
+	if e.auctionCapturer == nil || !r.Account.AuctionCapture.Enabled || !e.auctionCaptureAllowedByGDPR(ctx, r) {
+		return nil
+	}
+	var rates map[string]map[string]float64
+	if e.currencyConverter != nil {
+		if conversions := e.currencyConverter.Rates(); conversions != nil {
+			if pbsRates := conversions.GetRates(); pbsRates != nil {
+				rates = *pbsRates
+			}
+		}
+	}
+	return e.auctionCapturer.Start(&r.Account, r.BidRequestWrapper, r.Activities, r.FirstPartyData, rates)
+}
+
+// auctionCaptureAllowedByGDPR tells whether the host may keep a capture of the auction. Where GDPR applies,
+// that takes a consent string in which the host vendor is allowed to store information, like for the host
+// cookie. A host without a vendor id never has that consent.
+func (e *exchange) auctionCaptureAllowedByGDPR(ctx context.Context, r *AuctionRequest) bool {
+	eeaCountries := selectEEACountries(e.privacyConfig.GDPR.EEACountries, r.Account.GDPR.EEACountries)
+	gdprDefaultValue := e.parseGDPRDefaultValue(r.BidRequestWrapper, eeaCountries)
+	gdprSignal, err := getGDPR(r.BidRequestWrapper)
+	if err != nil {
+		return false
+	}
+	channelEnabled := r.TCF2Config.ChannelEnabled(channelTypeMap[r.LegacyLabels.RType])
+	if !enforceGDPR(gdprSignal, gdprDefaultValue, channelEnabled) {
+		return true
+	}
+
+	var gpp gpplib.GppContainer
+	if r.BidRequestWrapper.Regs != nil && len(r.BidRequestWrapper.Regs.GPP) > 0 {
+		gpp, _ = gpplib.Parse(r.BidRequestWrapper.Regs.GPP)
+	}
+	consent, err := getConsent(r.BidRequestWrapper, gpp)
+	if err != nil || consent == "" || e.privacyConfig.GDPR.HostVendorID == 0 {
+		return false
+	}
+
+	permissions := e.gdprPermsBuilder(r.TCF2Config, gdpr.RequestInfo{
+		Consent:     consent,
+		GDPRSignal:  gdprSignal,
+		PublisherID: r.LegacyLabels.PubID,
+	})
+	allowed, err := permissions.HostCookiesAllowed(ctx)
+	return err == nil && allowed
+}
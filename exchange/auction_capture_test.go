@@ -0,0 +1,167 @@
+package exchange
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/auctioncapture"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/privacy"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartAuctionCapture(t *testing.T) {
+	request := func() *AuctionRequest {
+		return &AuctionRequest{
+			BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req"}},
+			Account:           config.Account{ID: "acct", AuctionCapture: config.AccountAuctionCapture{Enabled: true, SampleRate: 1}},
+			TCF2Config:        gdpr.NewTCF2Config(config.TCF2{Enabled: true}, config.AccountGDPR{}),
+		}
+	}
+
+	e := &exchange{gdprDefaultValue: gdpr.SignalNo}
+	assert.Nil(t, e.startAuctionCapture(context.Background(), request()), "capture disabled for the host")
+
+	e.auctionCapturer = auctioncapture.NewCapturer(config.AuctionCapture{Enabled: true, Directory: t.TempDir(), MaxPerMinute: 10, RetentionHours: 1})
+	assert.NotNil(t, e.startAuctionCapture(context.Background(), request()))
+
+	notOptedIn := request()
+	notOptedIn.Account.AuctionCapture.Enabled = false
+	assert.Nil(t, e.startAuctionCapture(context.Background(), notOptedIn), "account not opted in")
+
+	gdprApplies := request()
+	gdprApplies.BidRequestWrapper.Regs = &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)}
+	assert.Nil(t, e.startAuctionCapture(context.Background(), gdprApplies), "GDPR applies without consent")
+}
+
+type hostCookiesPermissions struct {
+	permissionsMock
+	allowed bool
+}
+
+func (p *hostCookiesPermissions) HostCookiesAllowed(ctx context.Context) (bool, error) {
+	return p.allowed, nil
+}
+
+func TestAuctionCaptureAllowedByGDPR(t *testing.T) {
+	testCases := []struct {
+		description     string
+		givenRegs       *openrtb2.Regs
+		givenUser       *openrtb2.User
+		givenTCF2       config.TCF2
+		givenVendorID   int
+		givenHostAllows bool
+		expected        bool
+	}{
+		{
+			description: "gdpr-does-not-apply",
+			givenRegs:   &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](0)},
+			givenTCF2:   config.TCF2{Enabled: true},
+			expected:    true,
+		},
+		{
+			description: "tcf2-disabled",
+			givenRegs:   &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)},
+			givenTCF2:   config.TCF2{Enabled: false},
+			expected:    true,
+		},
+		{
+			description:     "no-consent",
+			givenRegs:       &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)},
+			givenTCF2:       config.TCF2{Enabled: true},
+			givenVendorID:   1,
+			givenHostAllows: true,
+			expected:        false,
+		},
+		{
+			description:     "no-host-vendor-id",
+			givenRegs:       &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)},
+			givenUser:       &openrtb2.User{Consent: "consent"},
+			givenTCF2:       config.TCF2{Enabled: true},
+			givenHostAllows: true,
+			expected:        false,
+		},
+		{
+			description:     "host-not-allowed",
+			givenRegs:       &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)},
+			givenUser:       &openrtb2.User{Consent: "consent"},
+			givenTCF2:       config.TCF2{Enabled: true},
+			givenVendorID:   1,
+			givenHostAllows: false,
+			expected:        false,
+		},
+		{
+			description:     "host-allowed",
+			givenRegs:       &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)},
+			givenUser:       &openrtb2.User{Consent: "consent"},
+			givenTCF2:       config.TCF2{Enabled: true},
+			givenVendorID:   1,
+			givenHostAllows: true,
+			expected:        true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			e := &exchange{
+				gdprDefaultValue: gdpr.SignalNo,
+				privacyConfig:    config.Privacy{GDPR: config.GDPR{HostVendorID: test.givenVendorID}},
+				gdprPermsBuilder: fakePermissionsBuilder{permissions: &hostCookiesPermissions{allowed: test.givenHostAllows}}.Builder,
+			}
+			r := &AuctionRequest{
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req", Regs: test.givenRegs, User: test.givenUser}},
+				TCF2Config:        gdpr.NewTCF2Config(test.givenTCF2, config.AccountGDPR{}),
+			}
+			assert.Equal(t, test.expected, e.auctionCaptureAllowedByGDPR(context.Background(), r))
+		})
+	}
+}
+
+func TestDoRequestRecordsCapturedCall(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", `{"seatbid":[]}`))
+	defer server.Close()
+	bidder := &BidderAdapter{
+		Bidder:     &mixedMultiBidder{},
+		Client:     server.Client(),
+		BidderName: openrtb_ext.BidderAppnexus,
+		me:         &metricsConfig.NilMetricsEngine{},
+	}
+
+	dir := t.TempDir()
+	capturer := auctioncapture.NewCapturer(config.AuctionCapture{Enabled: true, Directory: dir, MaxPerMinute: 10, RetentionHours: 1})
+	account := &config.Account{ID: "acct", AuctionCapture: config.AccountAuctionCapture{Enabled: true, SampleRate: 1}}
+	session := capturer.Start(account, &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req"}}, privacy.ActivityControl{}, nil, nil)
+	require.NotNil(t, session)
+
+	ctx := auctioncapture.WithSession(context.Background(), session)
+	bidder.doRequest(ctx, &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte(`{"id":"req"}`)}, time.Now(), &TmaxAdjustmentsPreprocessed{})
+	session.Finish(&openrtb2.BidResponse{ID: "req"})
+
+	var files []os.DirEntry
+	require.Eventually(t, func() bool {
+		files, _ = os.ReadDir(dir)
+		return len(files) == 1 && filepath.Ext(files[0].Name()) == ".json"
+	}, time.Second, 10*time.Millisecond)
+
+	capture, err := auctioncapture.Load(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	require.Len(t, capture.BidderCalls, 1)
+	call := capture.BidderCalls[0]
+	assert.Equal(t, "appnexus", call.Bidder)
+	assert.Equal(t, server.URL, call.Request.URI)
+	assert.Equal(t, `{"id":"req"}`, call.Request.Body)
+	require.NotNil(t, call.Response)
+	assert.Equal(t, 200, call.Response.StatusCode)
+	assert.Equal(t, `{"seatbid":[]}`, call.Response.Body)
+}
@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 )
 
@@ -9,6 +10,7 @@ import (
 type AuctionResponse struct {
 	*openrtb2.BidResponse
 	ExtBidResponse *openrtb_ext.ExtBidResponse
+	Floors         *analytics.FloorsObject
 }
 
 // GetSeatNonBid returns array of seat non-bid if present. nil otherwise
@@ -18,3 +20,11 @@ func (ar *AuctionResponse) GetSeatNonBid() []openrtb_ext.SeatNonBid {
 	}
 	return nil
 }
+
+// GetFloors returns the price floors details of the auction if present. nil otherwise
+func (ar *AuctionResponse) GetFloors() *analytics.FloorsObject {
+	if ar != nil {
+		return ar.Floors
+	}
+	return nil
+}
@@ -45,6 +45,14 @@ func TestMakeVASTNurl(t *testing.T) {
 	assert.Equal(t, expect, vast)
 }
 
+func TestCachedVAST(t *testing.T) {
+	const adm = `<VAST version="3.0"><Ad><Wrapper></Wrapper></Ad></VAST>`
+	const inline = `<VAST version="3.0"><Ad><InLine></InLine></Ad></VAST>`
+
+	assert.Equal(t, adm, cachedVAST(&entities.PbsOrtbBid{Bid: &openrtb2.Bid{AdM: adm}}))
+	assert.Equal(t, inline, cachedVAST(&entities.PbsOrtbBid{Bid: &openrtb2.Bid{AdM: adm}, InlineVAST: inline}))
+}
+
 func TestBuildCacheString(t *testing.T) {
 	testCases := []struct {
 		description      string
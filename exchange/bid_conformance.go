@@ -0,0 +1,119 @@
+package exchange
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// validateBidConformance checks that the bid matches what its imp asked for and what the bidder declared
+// in its capabilities for the request's channel. Violations are reported in the response ext and in the
+// metrics. It returns false, with the seat non-bid reason, if the bid doesn't conform.
+func (e exchange) validateBidConformance(bid *entities.PbsOrtbBid, imp *openrtb2.Imp, bidRequest *openrtb_ext.RequestWrapper, bidResponseExt *openrtb_ext.ExtBidResponse, adapter openrtb_ext.BidderName, pubID string, validationType string) (NonBidReason, bool) {
+	reason, err := bidConformanceViolation(bid, imp, e.bidderPlatform(bid.AdapterCode, bidRequest))
+	if err == nil {
+		return 0, true
+	}
+
+	if validationType == config.ValidationEnforce {
+		bidResponseExt.Errors[adapter] = append(bidResponseExt.Errors[adapter], openrtb_ext.ExtBidderMessage{
+			Code:    errortypes.BadServerResponseErrorCode,
+			Message: fmt.Sprintf("bidResponse rejected: %s", err.Error()),
+		})
+		e.me.RecordBidValidationConformanceError(adapter, pubID)
+	} else {
+		bidResponseExt.Warnings[adapter] = append(bidResponseExt.Warnings[adapter], openrtb_ext.ExtBidderMessage{
+			Code:    errortypes.BadServerResponseErrorCode,
+			Message: fmt.Sprintf("bidResponse conformance warning: %s", err.Error()),
+		})
+		e.me.RecordBidValidationConformanceWarn(adapter, pubID)
+	}
+	return reason, false
+}
+
+// bidderPlatform returns the media types the bidder declares for the request's channel, or nil if they're unknown.
+func (e exchange) bidderPlatform(bidder openrtb_ext.BidderName, bidRequest *openrtb_ext.RequestWrapper) *config.PlatformInfo {
+	info, ok := e.bidderInfo[bidder.String()]
+	if !ok {
+		return nil
+	}
+	switch {
+	case bidRequest.Site != nil:
+		return info.Capabilities.Site
+	case bidRequest.App != nil:
+		return info.Capabilities.App
+	case bidRequest.DOOH != nil:
+		return info.Capabilities.DOOH
+	}
+	return nil
+}
+
+// bidConformanceViolation returns an error describing how the bid doesn't match its imp or the bidder's
+// platform capabilities, if it doesn't. Imps which don't constrain a property, like banners without sizes
+// or imps without deals, accept any value of it.
+func bidConformanceViolation(bid *entities.PbsOrtbBid, imp *openrtb2.Imp, platform *config.PlatformInfo) (NonBidReason, error) {
+	if imp == nil {
+		return ResponseRejectedNonConforming, fmt.Errorf("bid %s is for imp %s which isn't in the request", bid.Bid.ID, bid.Bid.ImpID)
+	}
+
+	if bid.BidType != "" {
+		if !impHasMediaType(imp, bid.BidType) {
+			return ResponseRejectedNonConforming, fmt.Errorf("bid %s is a %s bid on imp %s which has no %s", bid.Bid.ID, bid.BidType, imp.ID, bid.BidType)
+		}
+		if platform != nil && !slices.Contains(platform.MediaTypes, bid.BidType) {
+			return ResponseRejectedNonConforming, fmt.Errorf("bid %s is a %s bid which the bidder doesn't declare in its capabilities", bid.Bid.ID, bid.BidType)
+		}
+	}
+
+	if bid.BidType == openrtb_ext.BidTypeBanner && !bannerAllowsSize(imp, bid.Bid.W, bid.Bid.H) {
+		return ResponseRejectedCreativeSizeNotAllowed, fmt.Errorf("bid %s size %dx%d isn't in imp.banner.format of imp %s", bid.Bid.ID, bid.Bid.W, bid.Bid.H, imp.ID)
+	}
+
+	if bid.Bid.DealID != "" && imp.PMP != nil && len(imp.PMP.Deals) > 0 {
+		dealMatches := slices.ContainsFunc(imp.PMP.Deals, func(deal openrtb2.Deal) bool { return deal.ID == bid.Bid.DealID })
+		if !dealMatches {
+			return ResponseRejectedNonConforming, fmt.Errorf("bid %s dealid %s isn't in imp.pmp.deals of imp %s", bid.Bid.ID, bid.Bid.DealID, imp.ID)
+		}
+	}
+
+	return 0, nil
+}
+
+func impHasMediaType(imp *openrtb2.Imp, bidType openrtb_ext.BidType) bool {
+	switch bidType {
+	case openrtb_ext.BidTypeBanner:
+		return imp.Banner != nil
+	case openrtb_ext.BidTypeVideo:
+		return imp.Video != nil
+	case openrtb_ext.BidTypeAudio:
+		return imp.Audio != nil
+	case openrtb_ext.BidTypeNative:
+		return imp.Native != nil
+	}
+	return true
+}
+
+// bannerAllowsSize returns false if the banner lists fixed sizes and w x h isn't one of them. Interstitials
+// and flexible formats may be filled by other sizes.
+func bannerAllowsSize(imp *openrtb2.Imp, w, h int64) bool {
+	if imp.Banner == nil || imp.Instl == 1 || (w == 0 && h == 0) {
+		return true
+	}
+
+	var sizes [][2]int64
+	if imp.Banner.W != nil && imp.Banner.H != nil {
+		sizes = append(sizes, [2]int64{*imp.Banner.W, *imp.Banner.H})
+	}
+	for _, format := range imp.Banner.Format {
+		if format.W == 0 || format.H == 0 {
+			return true
+		}
+		sizes = append(sizes, [2]int64{format.W, format.H})
+	}
+	return len(sizes) == 0 || slices.Contains(sizes, [2]int64{w, h})
+}
@@ -0,0 +1,259 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/gdpr"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/metrics"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBidConformanceViolation(t *testing.T) {
+	bannerImp := &openrtb2.Imp{
+		ID:     "imp-1",
+		Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 728, H: 90}}},
+		PMP:    &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "deal-1"}}},
+	}
+	bannerOnly := &config.PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner}}
+
+	testCases := []struct {
+		description    string
+		bid            *entities.PbsOrtbBid
+		imp            *openrtb2.Imp
+		platform       *config.PlatformInfo
+		expectedReason NonBidReason
+		expectedErr    error
+	}{
+		{
+			description: "conforming",
+			bid:         &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid", W: 728, H: 90, DealID: "deal-1"}, BidType: openrtb_ext.BidTypeBanner},
+			imp:         bannerImp,
+			platform:    bannerOnly,
+		},
+		{
+			description:    "unknown-imp",
+			bid:            &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid", ImpID: "imp-2"}, BidType: openrtb_ext.BidTypeBanner},
+			expectedReason: ResponseRejectedNonConforming,
+			expectedErr:    errors.New("bid bid is for imp imp-2 which isn't in the request"),
+		},
+		{
+			description:    "media-type-not-in-imp",
+			bid:            &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid"}, BidType: openrtb_ext.BidTypeVideo},
+			imp:            bannerImp,
+			expectedReason: ResponseRejectedNonConforming,
+			expectedErr:    errors.New("bid bid is a video bid on imp imp-1 which has no video"),
+		},
+		{
+			description:    "media-type-not-in-capabilities",
+			bid:            &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid"}, BidType: openrtb_ext.BidTypeVideo},
+			imp:            &openrtb2.Imp{ID: "imp-1", Video: &openrtb2.Video{}},
+			platform:       bannerOnly,
+			expectedReason: ResponseRejectedNonConforming,
+			expectedErr:    errors.New("bid bid is a video bid which the bidder doesn't declare in its capabilities"),
+		},
+		{
+			description:    "size-not-in-format",
+			bid:            &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid", W: 320, H: 50}, BidType: openrtb_ext.BidTypeBanner},
+			imp:            bannerImp,
+			expectedReason: ResponseRejectedCreativeSizeNotAllowed,
+			expectedErr:    errors.New("bid bid size 320x50 isn't in imp.banner.format of imp imp-1"),
+		},
+		{
+			description: "size-matches-banner-w-h",
+			bid:         &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid", W: 320, H: 50}, BidType: openrtb_ext.BidTypeBanner},
+			imp:         &openrtb2.Imp{ID: "imp-1", Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](320), H: ptrutil.ToPtr[int64](50)}},
+		},
+		{
+			description: "size-on-interstitial",
+			bid:         &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid", W: 320, H: 480}, BidType: openrtb_ext.BidTypeBanner},
+			imp:         &openrtb2.Imp{ID: "imp-1", Instl: 1, Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}},
+		},
+		{
+			description: "size-with-flexible-format",
+			bid:         &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid", W: 320, H: 480}, BidType: openrtb_ext.BidTypeBanner},
+			imp:         &openrtb2.Imp{ID: "imp-1", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{WRatio: 2, HRatio: 3, WMin: 100}}}},
+		},
+		{
+			description: "size-without-formats",
+			bid:         &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid", W: 320, H: 480}, BidType: openrtb_ext.BidTypeBanner},
+			imp:         &openrtb2.Imp{ID: "imp-1", Banner: &openrtb2.Banner{}},
+		},
+		{
+			description:    "deal-not-in-pmp",
+			bid:            &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid", W: 300, H: 250, DealID: "deal-2"}, BidType: openrtb_ext.BidTypeBanner},
+			imp:            bannerImp,
+			expectedReason: ResponseRejectedNonConforming,
+			expectedErr:    errors.New("bid bid dealid deal-2 isn't in imp.pmp.deals of imp imp-1"),
+		},
+		{
+			description: "deal-on-imp-without-deals",
+			bid:         &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid", DealID: "deal-2"}, BidType: openrtb_ext.BidTypeBanner},
+			imp:         &openrtb2.Imp{ID: "imp-1", Banner: &openrtb2.Banner{}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			reason, err := bidConformanceViolation(test.bid, test.imp, test.platform)
+			assert.Equal(t, test.expectedReason, reason)
+			assert.Equal(t, test.expectedErr, err)
+		})
+	}
+}
+
+func TestMakeBidWithConformance(t *testing.T) {
+	bids := []*entities.PbsOrtbBid{
+		{Bid: &openrtb2.Bid{ID: "valid", ImpID: "imp-1", W: 300, H: 250}, BidType: openrtb_ext.BidTypeBanner, AdapterCode: openrtb_ext.BidderAppnexus},
+		{Bid: &openrtb2.Bid{ID: "video", ImpID: "imp-1"}, BidType: openrtb_ext.BidTypeVideo, AdapterCode: openrtb_ext.BidderAppnexus},
+	}
+	bidRequest := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		Site: &openrtb2.Site{},
+		Imp:  []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}}},
+	}}
+	bidderInfo := config.BidderInfos{"appnexus": {Capabilities: &config.CapabilitiesInfo{
+		Site: &config.PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeVideo}},
+	}}}
+
+	testCases := []struct {
+		description       string
+		bidConformance    string
+		expectedBidIDs    []string
+		expectedNonBids   int
+		expectedErrors    int
+		expectedWarnings  int
+		expectedMetricSet string
+	}{
+		{
+			description:       "enforce",
+			bidConformance:    config.ValidationEnforce,
+			expectedBidIDs:    []string{"valid"},
+			expectedNonBids:   1,
+			expectedErrors:    1,
+			expectedMetricSet: "RecordBidValidationConformanceError",
+		},
+		{
+			description:       "warn",
+			bidConformance:    config.ValidationWarn,
+			expectedBidIDs:    []string{"valid", "video"},
+			expectedWarnings:  1,
+			expectedMetricSet: "RecordBidValidationConformanceWarn",
+		},
+		{
+			description:    "skip",
+			bidConformance: config.ValidationSkip,
+			expectedBidIDs: []string{"valid", "video"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsEngine := &metrics.MetricsEngineMock{}
+			if test.expectedMetricSet != "" {
+				metricsEngine.On(test.expectedMetricSet, openrtb_ext.BidderName("appnexus"), "pub").Return()
+			}
+			e := &exchange{
+				bidderInfo: bidderInfo,
+				me:         metricsEngine,
+			}
+			bidResponseExt := &openrtb_ext.ExtBidResponse{
+				Errors:   make(map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderMessage),
+				Warnings: make(map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderMessage),
+			}
+			nonBids := SeatNonBidBuilder{}
+
+			result, errs := e.makeBid(bids, &auction{}, true, nil, bidRequest, bidResponseExt, "appnexus", "pub", test.bidConformance, &nonBids)
+
+			assert.Empty(t, errs)
+			bidIDs := make([]string, 0, len(result))
+			for _, bid := range result {
+				bidIDs = append(bidIDs, bid.ID)
+			}
+			assert.Equal(t, test.expectedBidIDs, bidIDs)
+			assert.Len(t, nonBids["appnexus"], test.expectedNonBids)
+			if test.expectedNonBids > 0 {
+				assert.Equal(t, int(ResponseRejectedNonConforming), nonBids["appnexus"][0].StatusCode)
+			}
+			assert.Len(t, bidResponseExt.Errors["appnexus"], test.expectedErrors)
+			assert.Len(t, bidResponseExt.Warnings["appnexus"], test.expectedWarnings)
+			metricsEngine.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBidConformanceMode(t *testing.T) {
+	host := config.Validations{BidConformance: config.ValidationWarn}
+	assert.Equal(t, config.ValidationWarn, host.BidConformanceMode(config.Validations{}), "accounts without the setting keep the host's")
+	assert.Equal(t, config.ValidationEnforce, host.BidConformanceMode(config.Validations{BidConformance: config.ValidationEnforce}))
+	assert.Equal(t, config.ValidationWarn, host.BidConformance, "the host mode is left unchanged")
+}
+
+func TestHoldAuctionBidConformancePerAccount(t *testing.T) {
+	bidder := &mockAdaptedBidder{bidResponse: []*entities.PbsOrtbSeatBid{{
+		Bids:     []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "video", ImpID: "imp-1", Price: 1}, BidType: openrtb_ext.BidTypeVideo}},
+		Currency: "USD",
+	}}}
+	e := &exchange{
+		adapterMap: map[openrtb_ext.BidderName]AdaptedBidder{openrtb_ext.BidderAppnexus: bidder},
+		me:         &metricsConf.NilMetricsEngine{},
+		cache:      &wellBehavedCache{},
+		gdprPermsBuilder: fakePermissionsBuilder{
+			permissions: &permissionsMock{allowAllBidders: true},
+		}.Builder,
+		currencyConverter: currency.NewRateConverter(&http.Client{}, "", 24*time.Hour),
+		categoriesFetcher: nilCategoryFetcher{},
+		bidIDGenerator:    &fakeBidIDGenerator{},
+	}
+	e.requestSplitter = requestSplitter{
+		me:               e.me,
+		gdprPermsBuilder: e.gdprPermsBuilder,
+	}
+
+	holdAuction := func(account config.Account) []string {
+		request := &openrtb2.BidRequest{
+			ID: "request-id",
+			Imp: []openrtb2.Imp{{
+				ID:     "imp-1",
+				Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}},
+				Ext:    json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}`),
+			}},
+			Site: &openrtb2.Site{Page: "prebid.org"},
+		}
+		response, err := e.HoldAuction(context.Background(), &AuctionRequest{
+			BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: request},
+			Account:           account,
+			UserSyncs:         &emptyUsersync{},
+			HookExecutor:      &hookexecution.EmptyHookExecutor{},
+			TCF2Config:        gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+		}, &DebugLog{})
+		require.NoError(t, err)
+
+		var bidIDs []string
+		for _, seatBid := range response.BidResponse.SeatBid {
+			for _, bid := range seatBid.Bid {
+				bidIDs = append(bidIDs, bid.ID)
+			}
+		}
+		return bidIDs
+	}
+
+	bidIDs := holdAuction(config.Account{ID: "enforced", Validations: config.Validations{BidConformance: config.ValidationEnforce}})
+	assert.Empty(t, bidIDs, "the video bid for a banner imp is rejected")
+
+	bidIDs = holdAuction(config.Account{ID: "default"})
+	assert.Equal(t, []string{"video"}, bidIDs, "the next account isn't enforced")
+	assert.Empty(t, e.bidValidationEnforcement.BidConformance, "the exchange keeps the host mode")
+}
@@ -30,6 +30,7 @@ import (
 	nativeResponse "github.com/prebid/openrtb/v20/native1/response"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/auctioncapture"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/errortypes"
 	"github.com/prebid/prebid-server/v3/metrics"
@@ -109,6 +110,7 @@ func AdaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Config
 			DebugInfo:           config.DebugInfo{Allow: parseDebugInfo(debugInfo)},
 			EndpointCompression: endpointCompression,
 		},
+		circuitBreaker: newBidderCircuitBreaker(name, cfg.CircuitBreaker, me),
 	}
 }
 
@@ -125,6 +127,8 @@ type BidderAdapter struct {
 	Client     *http.Client
 	me         metrics.MetricsEngine
 	config     bidderAdapterConfig
+
+	circuitBreaker *bidderCircuitBreaker
 }
 
 type bidderAdapterConfig struct {
@@ -155,7 +159,12 @@ func (bidder *BidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 
 	//check if real request exists for this bidder or it only has stored responses
 	dataLen := 0
-	if len(bidderRequest.BidRequest.Imp) > 0 {
+	if len(bidderRequest.BidRequest.Imp) > 0 && bidder.circuitBreaker.isOpen() {
+		// The bidder has been failing, so don't waste the connection and tmax on it.
+		bidder.me.RecordAdapterCircuitBreakerRejected(bidder.BidderName)
+		errs = append(errs, &errortypes.CircuitBreakerOpen{Message: fmt.Sprintf("Skipped %s, its circuit breaker is open", bidder.BidderName)})
+		seatNonBidBuilder.rejectImps(openrtb_ext.GetImpIDs(bidderRequest.BidRequest.Imp), RequestBlockedCircuitBreakerOpen, string(bidderRequest.BidderName))
+	} else if len(bidderRequest.BidRequest.Imp) > 0 {
 		// Reducing the amount of time bidders have to compensate for the processing time used by PBS to fetch a stored request (if needed), validate the OpenRTB request and split it into multiple requests sanitized for each bidder
 		// As well as for the time needed by PBS to prepare the auction response
 		if bidRequestOptions.tmaxAdjustments != nil && bidRequestOptions.tmaxAdjustments.IsEnforced {
@@ -527,7 +536,9 @@ func makeExt(httpInfo *httpCallInfo) *openrtb_ext.ExtHttpCall {
 // doRequest makes a request, handles the response, and returns the data needed by the
 // Bidder interface.
 func (bidder *BidderAdapter) doRequest(ctx context.Context, req *adapters.RequestData, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
-	return bidder.doRequestImpl(ctx, req, glog.Warningf, bidderRequestStartTime, tmaxAdjustments)
+	httpInfo := bidder.doRequestImpl(ctx, req, glog.Warningf, bidderRequestStartTime, tmaxAdjustments)
+	auctioncapture.FromContext(ctx).RecordBidderCall(string(bidder.BidderName), httpInfo.request, httpInfo.response, httpInfo.err)
+	return httpInfo
 }
 
 func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.RequestData, logger util.LogMsg, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
@@ -564,9 +575,19 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 		}
 	}
 
+	circuitBreakerCall, allowed := bidder.circuitBreaker.allow(httpReq.URL.Host)
+	if !allowed {
+		bidder.me.RecordAdapterCircuitBreakerRejected(bidder.BidderName)
+		return &httpCallInfo{
+			request: req,
+			err:     &errortypes.CircuitBreakerOpen{Message: fmt.Sprintf("Skipped request to %s, its circuit breaker is open", httpReq.URL.Host)},
+		}
+	}
+
 	httpCallStart := time.Now()
 	httpResp, err := ctxhttp.Do(ctx, bidder.Client, httpReq)
 	if err != nil {
+		bidder.circuitBreaker.done(circuitBreakerCall, httpCallOutcome(err, 0))
 		if err == context.DeadlineExceeded {
 			err = &errortypes.Timeout{Message: err.Error()}
 			var corebidder adapters.Bidder = bidder.Bidder
@@ -591,6 +612,7 @@ func (bidder *BidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 	}
 
 	respBody, err := io.ReadAll(httpResp.Body)
+	bidder.circuitBreaker.done(circuitBreakerCall, httpCallOutcome(err, httpResp.StatusCode))
 	if err != nil {
 		return &httpCallInfo{
 			request: req,
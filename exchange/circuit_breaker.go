@@ -0,0 +1,270 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// callOutcome is what a bidder call tells the circuit breaker about the health of the bidder.
+type callOutcome int
+
+const (
+	callSucceeded callOutcome = iota
+	callFailed
+	// callIgnored is used for calls which say nothing about the bidder, like ones canceled by PBS itself.
+	callIgnored
+)
+
+// httpCallOutcome classifies the result of a bidder call. Timeouts, transport errors and 5xx responses
+// count against the bidder. Anything else means that the bidder is up, even if it didn't like the request.
+func httpCallOutcome(err error, statusCode int) callOutcome {
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return callIgnored
+		}
+		return callFailed
+	}
+	if statusCode >= 500 {
+		return callFailed
+	}
+	return callSucceeded
+}
+
+// bidderCircuitBreaker holds the circuit breakers for a single bidder: either one for the whole bidder,
+// or one per endpoint host. A nil *bidderCircuitBreaker is valid, and never blocks anything.
+type bidderCircuitBreaker struct {
+	bidder        openrtb_ext.BidderName
+	cfg           config.CircuitBreaker
+	me            metrics.MetricsEngine
+	now           func() time.Time
+	lock          sync.Mutex
+	hosts         map[string]*circuitBreaker
+	reportedState metrics.CircuitBreakerState
+}
+
+func newBidderCircuitBreaker(bidder openrtb_ext.BidderName, cfg config.CircuitBreaker, me metrics.MetricsEngine) *bidderCircuitBreaker {
+	if !cfg.Enabled {
+		return nil
+	}
+	me.RecordAdapterCircuitBreakerState(bidder, metrics.CircuitBreakerClosed)
+	return &bidderCircuitBreaker{
+		bidder: bidder,
+		cfg:    cfg,
+		me:     me,
+		now:    time.Now,
+		hosts:  make(map[string]*circuitBreaker),
+	}
+}
+
+// circuitBreakerCall is a call which the breaker let through. Its outcome must be reported with done.
+type circuitBreakerCall struct {
+	breaker *circuitBreaker
+	// probe is the half open period the call was a probe of, or 0 if it wasn't a probe.
+	probe int
+}
+
+// isOpen reports whether the whole bidder is blocked, which lets requestBid skip MakeRequests.
+// Per host breakers can only decide once the request URIs are known, so they never block the whole bidder.
+func (cb *bidderCircuitBreaker) isOpen() bool {
+	if cb == nil || cb.cfg.PerHost {
+		return false
+	}
+	return cb.get("").isOpen(cb.now())
+}
+
+// allow decides whether a call to the given host may be made.
+func (cb *bidderCircuitBreaker) allow(host string) (circuitBreakerCall, bool) {
+	if cb == nil {
+		return circuitBreakerCall{}, true
+	}
+	breaker := cb.get(host)
+	probe, allowed, changed := breaker.allow(cb.now())
+	if changed {
+		cb.reportState()
+	}
+	return circuitBreakerCall{breaker: breaker, probe: probe}, allowed
+}
+
+// done reports the outcome of a call which was let through by allow.
+func (cb *bidderCircuitBreaker) done(call circuitBreakerCall, outcome callOutcome) {
+	if cb == nil || call.breaker == nil {
+		return
+	}
+	if call.breaker.done(cb.now(), call.probe, outcome) {
+		cb.reportState()
+	}
+}
+
+func (cb *bidderCircuitBreaker) get(host string) *circuitBreaker {
+	if !cb.cfg.PerHost {
+		host = ""
+	}
+
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+	breaker, ok := cb.hosts[host]
+	if !ok {
+		breaker = newCircuitBreaker(cb.cfg)
+		cb.hosts[host] = breaker
+	}
+	return breaker
+}
+
+// reportState records the worst state across all of the bidder's breakers.
+func (cb *bidderCircuitBreaker) reportState() {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	worst := metrics.CircuitBreakerClosed
+	for _, breaker := range cb.hosts {
+		if state := breaker.currentState(); state > worst {
+			worst = state
+		}
+	}
+	if worst != cb.reportedState {
+		cb.reportedState = worst
+		cb.me.RecordAdapterCircuitBreakerState(cb.bidder, worst)
+	}
+}
+
+// circuitBreaker tracks the calls made to one bidder endpoint over a sliding window of one second buckets.
+//
+// Closed: calls are let through, and the breaker opens once there have been at least MinRequests calls
+// in the window and ErrorRatio of them failed.
+// Open: calls are rejected until OpenDuration has passed, then the breaker becomes half open.
+// Half open: up to HalfOpenProbes calls are let through. If any of them fails the breaker opens again,
+// and once all of them succeed it closes with an empty window.
+type circuitBreaker struct {
+	lock           sync.Mutex
+	state          metrics.CircuitBreakerState
+	buckets        []breakerBucket
+	minRequests    int
+	errorRatio     float64
+	openDuration   time.Duration
+	halfOpenProbes int
+	openedAt       time.Time
+	halfOpenPeriod int
+	probesInFlight int
+	probeSuccesses int
+}
+
+type breakerBucket struct {
+	second   int64
+	calls    int
+	failures int
+}
+
+func newCircuitBreaker(cfg config.CircuitBreaker) *circuitBreaker {
+	return &circuitBreaker{
+		state:          metrics.CircuitBreakerClosed,
+		buckets:        make([]breakerBucket, cfg.WindowSeconds),
+		minRequests:    cfg.MinRequests,
+		errorRatio:     cfg.ErrorRatio,
+		openDuration:   cfg.OpenDurationTime(),
+		halfOpenProbes: cfg.HalfOpenProbes,
+	}
+}
+
+func (b *circuitBreaker) currentState() metrics.CircuitBreakerState {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.state
+}
+
+func (b *circuitBreaker) isOpen(now time.Time) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.state == metrics.CircuitBreakerOpen && now.Sub(b.openedAt) < b.openDuration
+}
+
+// allow returns the half open period the call is a probe of (0 if it isn't one), whether the call may be
+// made, and whether the state changed.
+func (b *circuitBreaker) allow(now time.Time) (probe int, allowed bool, changed bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.state == metrics.CircuitBreakerOpen {
+		if now.Sub(b.openedAt) < b.openDuration {
+			return 0, false, false
+		}
+		b.state = metrics.CircuitBreakerHalfOpen
+		b.halfOpenPeriod++
+		b.probesInFlight = 0
+		b.probeSuccesses = 0
+		changed = true
+	}
+
+	if b.state == metrics.CircuitBreakerHalfOpen {
+		if b.probesInFlight+b.probeSuccesses >= b.halfOpenProbes {
+			return 0, false, changed
+		}
+		b.probesInFlight++
+		return b.halfOpenPeriod, true, changed
+	}
+
+	return 0, true, changed
+}
+
+// done records the outcome of a call, and returns whether the state changed. Outcomes of calls which were
+// let through in a previous state are dropped, since they say nothing about the current one.
+func (b *circuitBreaker) done(now time.Time, probe int, outcome callOutcome) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch {
+	case probe != 0 && probe == b.halfOpenPeriod && b.state == metrics.CircuitBreakerHalfOpen:
+		b.probesInFlight--
+		switch outcome {
+		case callFailed:
+			b.open(now)
+			return true
+		case callSucceeded:
+			b.probeSuccesses++
+			if b.probeSuccesses >= b.halfOpenProbes {
+				b.state = metrics.CircuitBreakerClosed
+				b.buckets = make([]breakerBucket, len(b.buckets))
+				return true
+			}
+		}
+	case probe == 0 && b.state == metrics.CircuitBreakerClosed && outcome != callIgnored:
+		bucket := &b.buckets[now.Unix()%int64(len(b.buckets))]
+		if bucket.second != now.Unix() {
+			*bucket = breakerBucket{second: now.Unix()}
+		}
+		bucket.calls++
+		if outcome == callFailed {
+			bucket.failures++
+		}
+
+		calls, failures := b.window(now)
+		if calls >= b.minRequests && float64(failures) >= b.errorRatio*float64(calls) {
+			b.open(now)
+			return true
+		}
+	}
+	return false
+}
+
+func (b *circuitBreaker) window(now time.Time) (calls int, failures int) {
+	oldest := now.Unix() - int64(len(b.buckets))
+	for _, bucket := range b.buckets {
+		if bucket.second > oldest {
+			calls += bucket.calls
+			failures += bucket.failures
+		}
+	}
+	return calls, failures
+}
+
+func (b *circuitBreaker) open(now time.Time) {
+	b.state = metrics.CircuitBreakerOpen
+	b.openedAt = now
+	b.probesInFlight = 0
+	b.probeSuccesses = 0
+}
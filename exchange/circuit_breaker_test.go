@@ -0,0 +1,237 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testCircuitBreakerConfig = config.CircuitBreaker{
+	Enabled:        true,
+	WindowSeconds:  10,
+	MinRequests:    4,
+	ErrorRatio:     0.5,
+	OpenDuration:   1000,
+	HalfOpenProbes: 2,
+}
+
+func TestHTTPCallOutcome(t *testing.T) {
+	testCases := []struct {
+		description string
+		err         error
+		statusCode  int
+		expected    callOutcome
+	}{
+		{description: "ok", statusCode: http.StatusOK, expected: callSucceeded},
+		{description: "no-content", statusCode: http.StatusNoContent, expected: callSucceeded},
+		{description: "bad-request", statusCode: http.StatusBadRequest, expected: callSucceeded},
+		{description: "server-error", statusCode: http.StatusServiceUnavailable, expected: callFailed},
+		{description: "timeout", err: &errortypes.Timeout{Message: "context deadline exceeded"}, expected: callFailed},
+		{description: "transport-error", err: &url.Error{Op: "Post", Err: errors.New("connection reset by peer")}, expected: callFailed},
+		{description: "canceled", err: &url.Error{Op: "Post", Err: context.Canceled}, expected: callIgnored},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, httpCallOutcome(test.err, test.statusCode))
+		})
+	}
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterCircuitBreakerState", openrtb_ext.BidderAppnexus, mock.Anything).Return()
+
+	now := time.Unix(1700000000, 0)
+	cb := newBidderCircuitBreaker(openrtb_ext.BidderAppnexus, testCircuitBreakerConfig, me)
+	cb.now = func() time.Time { return now }
+
+	makeCall := func(outcome callOutcome) bool {
+		call, allowed := cb.allow("")
+		if allowed {
+			cb.done(call, outcome)
+		}
+		return allowed
+	}
+
+	// Below min_requests the breaker stays closed, however bad the calls are
+	for i := 0; i < 3; i++ {
+		assert.True(t, makeCall(callFailed))
+	}
+	assert.False(t, cb.isOpen())
+
+	// Ignored calls don't count
+	assert.True(t, makeCall(callIgnored))
+	assert.False(t, cb.isOpen())
+
+	// Calls older than the window are forgotten
+	now = now.Add(11 * time.Second)
+	assert.True(t, makeCall(callSucceeded))
+	assert.True(t, makeCall(callFailed))
+	assert.True(t, makeCall(callSucceeded))
+	assert.False(t, cb.isOpen())
+
+	// 2 failures out of 4 reaches the ratio
+	assert.True(t, makeCall(callFailed))
+	assert.True(t, cb.isOpen())
+	assert.False(t, makeCall(callSucceeded), "calls must be rejected while open")
+
+	// After open_duration_ms, only half_open_probes calls are let through
+	now = now.Add(time.Second)
+	assert.False(t, cb.isOpen())
+	probe1, allowed1 := cb.allow("")
+	probe2, allowed2 := cb.allow("")
+	_, allowed3 := cb.allow("")
+	assert.True(t, allowed1)
+	assert.True(t, allowed2)
+	assert.False(t, allowed3, "only half_open_probes calls may be in flight")
+
+	// A failed probe opens the breaker again, and late probes are dropped
+	cb.done(probe1, callFailed)
+	assert.True(t, cb.isOpen())
+	cb.done(probe2, callSucceeded)
+	assert.True(t, cb.isOpen())
+
+	// Successful probes close the breaker with an empty window
+	now = now.Add(time.Second)
+	assert.True(t, makeCall(callSucceeded))
+	assert.True(t, makeCall(callSucceeded))
+	assert.False(t, cb.isOpen())
+	for i := 0; i < 3; i++ {
+		assert.True(t, makeCall(callFailed))
+	}
+	assert.False(t, cb.isOpen())
+
+	me.AssertCalled(t, "RecordAdapterCircuitBreakerState", openrtb_ext.BidderAppnexus, metrics.CircuitBreakerOpen)
+	me.AssertCalled(t, "RecordAdapterCircuitBreakerState", openrtb_ext.BidderAppnexus, metrics.CircuitBreakerHalfOpen)
+	me.AssertNumberOfCalls(t, "RecordAdapterCircuitBreakerState", 6)
+}
+
+func TestCircuitBreakerPerHost(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterCircuitBreakerState", openrtb_ext.BidderAppnexus, mock.Anything).Return()
+
+	cfg := testCircuitBreakerConfig
+	cfg.PerHost = true
+	cb := newBidderCircuitBreaker(openrtb_ext.BidderAppnexus, cfg, me)
+
+	for i := 0; i < 4; i++ {
+		call, allowed := cb.allow("us.bidder.com")
+		assert.True(t, allowed)
+		cb.done(call, callFailed)
+	}
+
+	_, allowed := cb.allow("us.bidder.com")
+	assert.False(t, allowed)
+	_, allowed = cb.allow("eu.bidder.com")
+	assert.True(t, allowed)
+	assert.False(t, cb.isOpen(), "per host breakers never block the whole bidder")
+	me.AssertCalled(t, "RecordAdapterCircuitBreakerState", openrtb_ext.BidderAppnexus, metrics.CircuitBreakerOpen)
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	cb := newBidderCircuitBreaker(openrtb_ext.BidderAppnexus, config.CircuitBreaker{}, &metrics.MetricsEngineMock{})
+	assert.Nil(t, cb)
+
+	call, allowed := cb.allow("bidder.com")
+	assert.True(t, allowed)
+	assert.False(t, cb.isOpen())
+	cb.done(call, callFailed)
+}
+
+func TestRequestBidCircuitBreaker(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer healthy.Close()
+
+	testCases := []struct {
+		description         string
+		perHost             bool
+		requests            []*adapters.RequestData
+		expectedMakeRequest int
+		expectedNonBids     []openrtb_ext.NonBid
+		expectedErrors      []error
+	}{
+		{
+			description: "bidder",
+			requests:    []*adapters.RequestData{{Method: "POST", Uri: failing.URL, ImpIDs: []string{"imp-1"}}},
+			// The last auction never gets to MakeRequests
+			expectedMakeRequest: 4,
+			expectedNonBids:     []openrtb_ext.NonBid{{ImpId: "imp-1", StatusCode: int(RequestBlockedCircuitBreakerOpen)}},
+			expectedErrors:      []error{&errortypes.CircuitBreakerOpen{Message: "Skipped appnexus, its circuit breaker is open"}},
+		},
+		{
+			description: "per-host",
+			perHost:     true,
+			requests: []*adapters.RequestData{
+				{Method: "POST", Uri: failing.URL, ImpIDs: []string{"imp-1"}},
+				{Method: "POST", Uri: healthy.URL, ImpIDs: []string{"imp-2"}},
+			},
+			expectedMakeRequest: 5,
+			expectedNonBids:     []openrtb_ext.NonBid{{ImpId: "imp-1", StatusCode: int(RequestBlockedCircuitBreakerOpen)}},
+			expectedErrors:      []error{&errortypes.CircuitBreakerOpen{Message: "Skipped request to " + failing.Listener.Addr().String() + ", its circuit breaker is open"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidder := &mockBidder{}
+			bidder.On("MakeRequests", mock.Anything, mock.Anything).Return(test.requests, []error(nil))
+			bidder.On("MakeBids", mock.Anything, mock.Anything, mock.Anything).Return((*adapters.BidderResponse)(nil), []error(nil))
+
+			me := &metrics.MetricsEngineMock{}
+			me.On("RecordOverheadTime", mock.Anything, mock.Anything).Return()
+			me.On("RecordBidderServerResponseTime", mock.Anything).Return()
+			me.On("RecordAdapterCircuitBreakerState", openrtb_ext.BidderAppnexus, mock.Anything).Return()
+			me.On("RecordAdapterCircuitBreakerRejected", openrtb_ext.BidderAppnexus).Return()
+
+			cfg := &config.Configuration{CircuitBreaker: testCircuitBreakerConfig}
+			cfg.CircuitBreaker.PerHost = test.perHost
+			cfg.Metrics.Disabled.AdapterConnectionMetrics = true
+			adaptedBidder := AdaptBidder(bidder, failing.Client(), cfg, me, openrtb_ext.BidderAppnexus, nil, "")
+
+			bidderRequest := BidderRequest{
+				BidRequest: &openrtb2.BidRequest{ID: "request-id", Imp: []openrtb2.Imp{{ID: "imp-1"}, {ID: "imp-2"}}},
+				BidderName: openrtb_ext.BidderAppnexus,
+			}
+			if !test.perHost {
+				bidderRequest.BidRequest.Imp = bidderRequest.BidRequest.Imp[:1]
+			}
+			requestBid := func() (extraBidderRespInfo, []error) {
+				_, extraInfo, errs := adaptedBidder.requestBid(context.Background(), bidderRequest, nil, &adapters.ExtraRequestInfo{}, &MockSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, hookexecution.EmptyHookExecutor{}, nil)
+				return extraInfo, errs
+			}
+
+			for i := 0; i < 4; i++ {
+				extraInfo, _ := requestBid()
+				assert.Equal(t, []openrtb_ext.NonBid{{ImpId: "imp-1", StatusCode: int(ErrorGeneral)}}, extraInfo.seatNonBidBuilder["appnexus"])
+			}
+
+			extraInfo, errs := requestBid()
+			assert.Equal(t, test.expectedNonBids, extraInfo.seatNonBidBuilder["appnexus"])
+			assert.Equal(t, test.expectedErrors, errs)
+			bidder.AssertNumberOfCalls(t, "MakeRequests", test.expectedMakeRequest)
+			me.AssertCalled(t, "RecordAdapterCircuitBreakerState", openrtb_ext.BidderAppnexus, metrics.CircuitBreakerOpen)
+			me.AssertNumberOfCalls(t, "RecordAdapterCircuitBreakerRejected", 1)
+		})
+	}
+}
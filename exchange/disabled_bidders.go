@@ -0,0 +1,34 @@
+package exchange
+
+import (
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/errortypes"
+)
+
+// removeDisabledBidders drops the requests to bidders which were enabled at startup but have been disabled
+// since by a config reload. Bidders disabled at startup have no adapter, and are already removed, with the
+// same warning, by the request validator.
+func (e *exchange) removeDisabledBidders(bidderRequests []BidderRequest) ([]BidderRequest, []error) {
+	if e.hostConfig == nil {
+		return bidderRequests, nil
+	}
+	currentInfos := e.hostConfig.Current().BidderInfos
+
+	var errs []error
+	enabledRequests := bidderRequests[:0]
+	for _, bidderRequest := range bidderRequests {
+		name := bidderRequest.BidderName.String()
+		if _, ok := e.bidderInfo[name]; !ok {
+			name = bidderRequest.BidderCoreName.String()
+		}
+		if startupInfo, ok := e.bidderInfo[name]; ok && !startupInfo.Disabled && currentInfos[name].Disabled {
+			errs = append(errs, &errortypes.BidderTemporarilyDisabled{
+				Message: fmt.Sprintf(`Bidder "%s" has been disabled on this instance of Prebid Server. Please work with the PBS host to enable this bidder again.`, name),
+			})
+			continue
+		}
+		enabledRequests = append(enabledRequests, bidderRequest)
+	}
+	return enabledRequests, errs
+}
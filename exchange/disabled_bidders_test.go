@@ -0,0 +1,68 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveDisabledBidders(t *testing.T) {
+	startupInfos := config.BidderInfos{"appnexus": {}, "rubicon": {}, "pubmatic": {Disabled: true}}
+	bidderRequests := func() []BidderRequest {
+		return []BidderRequest{
+			{BidderName: "appnexus", BidderCoreName: "appnexus"},
+			{BidderName: "rubicon", BidderCoreName: "rubicon"},
+			{BidderName: "rubiconAlias", BidderCoreName: "rubicon", IsRequestAlias: true},
+		}
+	}
+
+	testCases := []struct {
+		description      string
+		hostConfig       *config.Configuration
+		reloadedInfos    config.BidderInfos
+		expectedBidders  []openrtb_ext.BidderName
+		expectedWarnings []error
+	}{
+		{
+			description:     "no-host-config",
+			expectedBidders: []openrtb_ext.BidderName{"appnexus", "rubicon", "rubiconAlias"},
+		},
+		{
+			description:     "not-reloaded",
+			hostConfig:      &config.Configuration{BidderInfos: startupInfos},
+			expectedBidders: []openrtb_ext.BidderName{"appnexus", "rubicon", "rubiconAlias"},
+		},
+		{
+			description:     "disabled-by-reload",
+			hostConfig:      &config.Configuration{BidderInfos: startupInfos},
+			reloadedInfos:   config.BidderInfos{"appnexus": {}, "rubicon": {Disabled: true}, "pubmatic": {Disabled: true}},
+			expectedBidders: []openrtb_ext.BidderName{"appnexus"},
+			expectedWarnings: []error{
+				&errortypes.BidderTemporarilyDisabled{Message: `Bidder "rubicon" has been disabled on this instance of Prebid Server. Please work with the PBS host to enable this bidder again.`},
+				&errortypes.BidderTemporarilyDisabled{Message: `Bidder "rubicon" has been disabled on this instance of Prebid Server. Please work with the PBS host to enable this bidder again.`},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			if test.reloadedInfos != nil {
+				test.hostConfig.EnableReload()
+				_, err := test.hostConfig.Reload(&config.Configuration{BidderInfos: test.reloadedInfos})
+				assert.NoError(t, err)
+			}
+			e := &exchange{bidderInfo: startupInfos, hostConfig: test.hostConfig}
+
+			remaining, warnings := e.removeDisabledBidders(bidderRequests())
+			var bidders []openrtb_ext.BidderName
+			for _, bidderRequest := range remaining {
+				bidders = append(bidders, bidderRequest.BidderName)
+			}
+			assert.Equal(t, test.expectedBidders, bidders)
+			assert.Equal(t, test.expectedWarnings, warnings)
+		})
+	}
+}
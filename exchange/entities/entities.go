@@ -36,6 +36,7 @@ type PbsOrtbSeatBid struct {
 // PbsOrtbBid.DealPriority is optionally provided by adapters and used internally by the exchange to support deal targeted campaigns.
 // PbsOrtbBid.DealTierSatisfied is set to true by exchange.updateHbPbCatDur if deal tier satisfied otherwise it will be set to false
 // PbsOrtbBid.GeneratedBidID is unique Bid id generated by prebid server if generate Bid id option is enabled in config
+// PbsOrtbBid.InlineVAST is set by exchange to the inline VAST the wrapper chain of the bid resolved to, if the account asked for it. It's cached in place of the adm.
 type PbsOrtbBid struct {
 	Bid               *openrtb2.Bid
 	BidMeta           *openrtb_ext.ExtBidPrebidMeta
@@ -51,4 +52,5 @@ type PbsOrtbBid struct {
 	OriginalBidCur    string
 	TargetBidderCode  string
 	AdapterCode       openrtb_ext.BidderName
+	InlineVAST        string
 }
@@ -77,6 +77,11 @@ func (ev *eventTracking) modifyBidVAST(pbsBid *entities.PbsOrtbBid, bidderName o
 	if newVastXML, ok := events.ModifyVastXmlString(ev.externalURL, vastXML, bidID, bidderName.String(), ev.accountID, ev.auctionTimestampMs, ev.integrationType); ok {
 		bid.AdM = newVastXML
 	}
+	if pbsBid.InlineVAST != "" {
+		if newVastXML, ok := events.ModifyVastXmlString(ev.externalURL, pbsBid.InlineVAST, bidID, bidderName.String(), ev.accountID, ev.auctionTimestampMs, ev.integrationType); ok {
+			pbsBid.InlineVAST = newVastXML
+		}
+	}
 }
 
 // modifyBidJSON injects "wurl" (win) event url if needed, otherwise returns original json
@@ -19,6 +19,7 @@ import (
 
 	"github.com/prebid/prebid-server/v3/adapters"
 	"github.com/prebid/prebid-server/v3/adservertargeting"
+	"github.com/prebid/prebid-server/v3/auctioncapture"
 	"github.com/prebid/prebid-server/v3/bidadjustment"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
@@ -28,6 +29,7 @@ import (
 	"github.com/prebid/prebid-server/v3/experiment/adscert"
 	"github.com/prebid/prebid-server/v3/firstpartydata"
 	"github.com/prebid/prebid-server/v3/floors"
+	"github.com/prebid/prebid-server/v3/floorsreport"
 	"github.com/prebid/prebid-server/v3/gdpr"
 	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
 	"github.com/prebid/prebid-server/v3/macros"
@@ -36,9 +38,11 @@ import (
 	"github.com/prebid/prebid-server/v3/prebid_cache_client"
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/stored_responses"
+	"github.com/prebid/prebid-server/v3/trafficshaping"
 	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 	"github.com/prebid/prebid-server/v3/util/maputil"
+	"github.com/prebid/prebid-server/v3/vastunwrap"
 
 	"github.com/buger/jsonparser"
 	"github.com/gofrs/uuid"
@@ -85,6 +89,13 @@ type exchange struct {
 	macroReplacer            macros.Replacer
 	priceFloorEnabled        bool
 	priceFloorFetcher        floors.FloorFetcher
+	trafficShaper            *trafficshaping.Shaper
+	auctionCapturer          *auctioncapture.Capturer
+	floorsReporter           *floorsreport.Reporter
+	vastUnwrapper            *vastunwrap.Unwrapper
+	syncValueRecorder        syncValueRecorder
+	// hostConfig is only read through Current(), for the values which may change with a config reload
+	hostConfig *config.Configuration
 }
 
 // Container to pass out response ext data from the GetAllBids goroutines back into the main thread
@@ -136,7 +147,7 @@ func (randomDeduplicateBidBooleanGenerator) Generate() bool {
 	return rand.Intn(100) < 50
 }
 
-func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, requestValidator ortb.RequestValidator, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, macroReplacer macros.Replacer, priceFloorFetcher floors.FloorFetcher) Exchange {
+func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, requestValidator ortb.RequestValidator, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, macroReplacer macros.Replacer, priceFloorFetcher floors.FloorFetcher, floorsReporter *floorsreport.Reporter) Exchange {
 	bidderToSyncerKey := map[string]string{}
 	for bidder, syncer := range syncersByBidder {
 		bidderToSyncerKey[bidder] = syncer.Key()
@@ -184,6 +195,12 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 		macroReplacer:            macroReplacer,
 		priceFloorEnabled:        cfg.PriceFloors.Enabled,
 		priceFloorFetcher:        priceFloorFetcher,
+		trafficShaper:            trafficshaping.NewShaper(cfg.TrafficShaping),
+		auctionCapturer:          auctioncapture.NewCapturer(cfg.AuctionCapture),
+		floorsReporter:           floorsReporter,
+		vastUnwrapper:            vastunwrap.NewUnwrapper(cfg.VASTUnwrap),
+		syncValueRecorder:        newSyncValueRecorder(usersync.NewValueTracker(cfg.UserSync.ValueRanking)),
+		hostConfig:               cfg,
 	}
 }
 
@@ -246,6 +263,9 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		return nil, err
 	}
 
+	captureSession := e.startAuctionCapture(ctx, r)
+	ctx = auctioncapture.WithSession(ctx, captureSession)
+
 	requestExt, err := r.BidRequestWrapper.GetRequestExt()
 	if err != nil {
 		return nil, err
@@ -275,9 +295,12 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	// Get currency rates conversions for the auction
 	conversions := currency.GetAuctionCurrencyRates(e.currencyConverter, requestExtPrebid.CurrencyConversions)
 
+	// The floors are resolved and enforced at the same time, so that hourOfDay and dayOfWeek rules match the same way
+	floorsTime := time.Now()
 	var floorErrs []error
 	if e.priceFloorEnabled {
-		floorErrs = floors.EnrichWithPriceFloors(r.BidRequestWrapper, r.Account, conversions, e.priceFloorFetcher)
+		floorErrs = floors.EnrichWithPriceFloors(r.BidRequestWrapper, r.Account, conversions, e.priceFloorFetcher, floorsTime)
+		e.recordFloorsConversionErrors(floorErrs, metrics.FloorsConversionRule)
 	}
 
 	responseDebugAllow, accountDebugAllow, debugLog := getDebugInfo(r.BidRequestWrapper.Test, requestExtPrebid, r.Account.DebugAllow, debugLog)
@@ -353,6 +376,10 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	}
 	errs = append(errs, floorErrs...)
 
+	bidderRequests, disabledErrs := e.removeDisabledBidders(bidderRequests)
+	errs = append(errs, disabledErrs...)
+	errs = append(errs, e.convertFloorsToBidderCurrency(bidderRequests, conversions)...)
+
 	mergedBidAdj, err := bidadjustment.Merge(r.BidRequestWrapper, r.Account.BidAdjustments)
 	if err != nil {
 		if errortypes.ContainsFatalError([]error{err}) {
@@ -381,6 +408,8 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		// List of bidders we have requests for.
 		liveAdapters      []openrtb_ext.BidderName
 		seatNonBidBuilder SeatNonBidBuilder = SeatNonBidBuilder{}
+		// Imps which traffic shaping didn't send to the bidders, for ext.debug.trafficshaping.
+		trafficShapingDebug map[openrtb_ext.BidderName][]openrtb_ext.ExtTrafficShapingImp
 	)
 
 	if len(r.StoredAuctionResponses) > 0 {
@@ -391,6 +420,9 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		anyBidsReturned = true
 
 	} else {
+		var trafficShapingNonBids SeatNonBidBuilder
+		bidderRequests, trafficShapingNonBids, trafficShapingDebug = e.shapeTraffic(&r.Account, bidderRequests)
+
 		// List of bidders we have requests for.
 		liveAdapters = listBiddersWithRequests(bidderRequests)
 
@@ -409,22 +441,25 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		if extraRespInfo.seatNonBidBuilder != nil {
 			seatNonBidBuilder = extraRespInfo.seatNonBidBuilder
 		}
+		e.recordTrafficShaping(&r.Account, bidderRequests, adapterBids, seatNonBidBuilder)
+		seatNonBidBuilder.append(trafficShapingNonBids)
 	}
 
 	var (
-		auc            *auction
-		cacheErrs      []error
-		bidResponseExt *openrtb_ext.ExtBidResponse
+		auc                *auction
+		cacheErrs          []error
+		bidResponseExt     *openrtb_ext.ExtBidResponse
+		floorsRejectedBids []*entities.PbsOrtbSeatBid
+		adPods             []openrtb_ext.ExtAdPod
 	)
 
 	if anyBidsReturned {
 		if e.priceFloorEnabled {
-			var rejectedBids []*entities.PbsOrtbSeatBid
 			var enforceErrs []error
 
-			adapterBids, enforceErrs, rejectedBids = floors.Enforce(r.BidRequestWrapper, adapterBids, r.Account, conversions)
+			adapterBids, enforceErrs, floorsRejectedBids = floors.Enforce(r.BidRequestWrapper, adapterBids, r.Account, conversions, floorsTime)
 			errs = append(errs, enforceErrs...)
-			for _, rejectedBid := range rejectedBids {
+			for _, rejectedBid := range floorsRejectedBids {
 				errs = append(errs, &errortypes.Warning{
 					Message:     fmt.Sprintf("%s bid id %s rejected - bid price %.4f %s is less than bid floor %.4f %s for imp %s", rejectedBid.Seat, rejectedBid.Bids[0].Bid.ID, rejectedBid.Bids[0].Bid.Price, rejectedBid.Currency, rejectedBid.Bids[0].BidFloors.FloorValue, rejectedBid.Bids[0].BidFloors.FloorCurrency, rejectedBid.Bids[0].Bid.ImpID),
 					WarningCode: errortypes.FloorBidRejectionWarningCode})
@@ -436,11 +471,18 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 			}
 		}
 
+		vastErrs := e.unwrapVAST(auctionCtx, r.Account.VASTUnwrap, r.BidRequestWrapper.BidRequest, adapterBids, &seatNonBidBuilder)
+		errs = append(errs, vastErrs...)
+
 		var bidCategory map[string]string
+		var podCategories map[string]string
 		//If includebrandcategory is present in ext then CE feature is on.
 		if requestExtPrebid.Targeting != nil && requestExtPrebid.Targeting.IncludeBrandCategory != nil {
+			if hasAdPods(r.BidRequestWrapper.BidRequest) {
+				podCategories = make(map[string]string)
+			}
 			var rejections []string
-			bidCategory, adapterBids, rejections, err = applyCategoryMapping(ctx, *requestExtPrebid.Targeting, adapterBids, e.categoriesFetcher, targData, &randomDeduplicateBidBooleanGenerator{}, &seatNonBidBuilder)
+			bidCategory, adapterBids, rejections, err = applyCategoryMapping(ctx, *requestExtPrebid.Targeting, adapterBids, e.categoriesFetcher, targData, &randomDeduplicateBidBooleanGenerator{}, podCategories, &seatNonBidBuilder)
 			if err != nil {
 				return nil, fmt.Errorf("Error in category mapping : %s", err.Error())
 			}
@@ -449,6 +491,12 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 			}
 		}
 
+		var adPodRejections []string
+		adPodRejections, adPods = applyAdPodAuction(r.BidRequestWrapper.BidRequest, adapterBids, bidCategory, podCategories, rand.Shuffle, &seatNonBidBuilder)
+		for _, message := range adPodRejections {
+			errs = append(errs, errors.New(message))
+		}
+
 		if e.bidIDGenerator.Enabled() {
 			for bidder, seatBid := range adapterBids {
 				for i := range seatBid.Bids {
@@ -500,6 +548,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		}
 		bidResponseExt = e.makeExtBidResponse(adapterBids, adapterExtra, *r, responseDebugAllow, requestExtPrebid.Passthrough, fledge, errs)
 	} else {
+		adPods = unfilledAdPods(r.BidRequestWrapper.BidRequest)
 		bidResponseExt = e.makeExtBidResponse(adapterBids, adapterExtra, *r, responseDebugAllow, requestExtPrebid.Passthrough, fledge, errs)
 
 		if debugLog.DebugEnabledOrOverridden {
@@ -513,6 +562,23 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		}
 	}
 
+	if len(adPods) > 0 {
+		if bidResponseExt.Prebid == nil {
+			bidResponseExt.Prebid = &openrtb_ext.ExtResponsePrebid{}
+		}
+		bidResponseExt.Prebid.AdPods = adPods
+	}
+
+	if bidResponseExt.Debug != nil && len(trafficShapingDebug) > 0 {
+		bidResponseExt.Debug.TrafficShaping = trafficShapingDebug
+	}
+
+	if bidResponseExt.Debug != nil {
+		if usnatDecisions := r.Activities.USNatDecisions(); len(usnatDecisions) > 0 {
+			bidResponseExt.Debug.USNat = usnatDecisions
+		}
+	}
+
 	if !accountDebugAllow && !debugLog.DebugOverride {
 		accountDebugDisabledWarning := openrtb_ext.ExtBidderMessage{
 			Code:    errortypes.AccountLevelDebugDisabledWarningCode,
@@ -533,9 +599,10 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	}
 
 	e.bidValidationEnforcement.SetBannerCreativeMaxSize(r.Account.Validations)
+	bidConformance := e.bidValidationEnforcement.BidConformanceMode(r.Account.Validations)
 
 	// Build the response
-	bidResponse := e.buildBidResponse(ctx, liveAdapters, adapterBids, r.BidRequestWrapper, adapterExtra, auc, bidResponseExt, cacheInstructions.returnCreative, r.ImpExtInfoMap, r.PubID, errs, &seatNonBidBuilder)
+	bidResponse := e.buildBidResponse(ctx, liveAdapters, adapterBids, r.BidRequestWrapper, adapterExtra, auc, bidResponseExt, cacheInstructions.returnCreative, r.ImpExtInfoMap, r.PubID, bidConformance, errs, &seatNonBidBuilder)
 	bidResponse = adservertargeting.Apply(r.BidRequestWrapper, r.ResolvedBidRequest, bidResponse, r.QueryParams, bidResponseExt, r.Account.TruncateTargetAttribute)
 
 	bidResponse.Ext, err = encodeBidResponseExt(bidResponseExt)
@@ -543,10 +610,13 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		return nil, err
 	}
 	bidResponseExt = setSeatNonBid(bidResponseExt, seatNonBidBuilder)
+	captureSession.Finish(bidResponse)
+	e.recordSyncValue(r, liveAdapters, adapterBids)
 
 	return &AuctionResponse{
 		BidResponse:    bidResponse,
 		ExtBidResponse: bidResponseExt,
+		Floors:         e.recordFloorsReport(r, adapterBids, floorsRejectedBids, auc, bidResponse, conversions),
 	}, nil
 }
 
@@ -902,6 +972,8 @@ func errorsToMetric(errs []error) map[metrics.AdapterError]struct{} {
 			ret[metrics.AdapterErrorValidation] = s
 		case errortypes.TmaxTimeoutErrorCode:
 			ret[metrics.AdapterErrorTmaxTimeout] = s
+		case errortypes.CircuitBreakerOpenErrorCode:
+			// Counted by RecordAdapterCircuitBreakerRejected instead
 		default:
 			ret[metrics.AdapterErrorUnknown] = s
 		}
@@ -909,6 +981,30 @@ func errorsToMetric(errs []error) map[metrics.AdapterError]struct{} {
 	return ret
 }
 
+// convertFloorsToBidderCurrency converts the floors of each bidder request to the currency the bidder expects
+// them in, if its bidder info sets one.
+func (e *exchange) convertFloorsToBidderCurrency(bidderRequests []BidderRequest, conversions currency.Conversions) []error {
+	var errs []error
+	for _, bidderRequest := range bidderRequests {
+		info, ok := e.bidderInfo[string(bidderRequest.BidderName)]
+		if !ok {
+			info = e.bidderInfo[string(bidderRequest.BidderCoreName)]
+		}
+		errs = append(errs, floors.ConvertToBidderCurrency(bidderRequest.BidRequest, info.FloorCurrency, conversions)...)
+	}
+	e.recordFloorsConversionErrors(errs, metrics.FloorsConversionBidder)
+	return errs
+}
+
+// recordFloorsConversionErrors counts the floors which were dropped or left unconverted because of a missing rate
+func (e *exchange) recordFloorsConversionErrors(errs []error, source metrics.FloorsConversionSource) {
+	for _, err := range errs {
+		if errortypes.ReadCode(err) == errortypes.FloorCurrencyConversionWarningCode {
+			e.me.RecordFloorsCurrencyConversionError(source)
+		}
+	}
+}
+
 func errsToBidderErrors(errs []error) []openrtb_ext.ExtBidderMessage {
 	sErr := make([]openrtb_ext.ExtBidderMessage, 0)
 	for _, err := range errortypes.FatalOnly(errs) {
@@ -935,7 +1031,7 @@ func errsToBidderWarnings(errs []error) []openrtb_ext.ExtBidderMessage {
 }
 
 // This piece takes all the bids supplied by the adapters and crafts an openRTB response to send back to the requester
-func (e *exchange) buildBidResponse(ctx context.Context, liveAdapters []openrtb_ext.BidderName, adapterSeatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, bidRequest *openrtb_ext.RequestWrapper, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, auc *auction, bidResponseExt *openrtb_ext.ExtBidResponse, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, pubID string, errList []error, seatNonBidBuilder *SeatNonBidBuilder) *openrtb2.BidResponse {
+func (e *exchange) buildBidResponse(ctx context.Context, liveAdapters []openrtb_ext.BidderName, adapterSeatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, bidRequest *openrtb_ext.RequestWrapper, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, auc *auction, bidResponseExt *openrtb_ext.ExtBidResponse, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, pubID string, bidConformance string, errList []error, seatNonBidBuilder *SeatNonBidBuilder) *openrtb2.BidResponse {
 	bidResponse := new(openrtb2.BidResponse)
 
 	bidResponse.ID = bidRequest.ID
@@ -950,7 +1046,7 @@ func (e *exchange) buildBidResponse(ctx context.Context, liveAdapters []openrtb_
 	for a, adapterSeatBids := range adapterSeatBids {
 		//while processing every single bib, do we need to handle categories here?
 		if adapterSeatBids != nil && len(adapterSeatBids.Bids) > 0 {
-			sb := e.makeSeatBid(adapterSeatBids, a, adapterExtra, auc, returnCreative, impExtInfoMap, bidRequest, bidResponseExt, pubID, seatNonBidBuilder)
+			sb := e.makeSeatBid(adapterSeatBids, a, adapterExtra, auc, returnCreative, impExtInfoMap, bidRequest, bidResponseExt, pubID, bidConformance, seatNonBidBuilder)
 			seatBids = append(seatBids, *sb)
 			bidResponse.Cur = adapterSeatBids.Currency
 		}
@@ -970,7 +1066,7 @@ func encodeBidResponseExt(bidResponseExt *openrtb_ext.ExtBidResponse) ([]byte, e
 	return buffer.Bytes(), err
 }
 
-func applyCategoryMapping(ctx context.Context, targeting openrtb_ext.ExtRequestTargeting, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, categoriesFetcher stored_requests.CategoryFetcher, targData *targetData, booleanGenerator deduplicateChanceGenerator, seatNonBidBuilder *SeatNonBidBuilder) (map[string]string, map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, []string, error) {
+func applyCategoryMapping(ctx context.Context, targeting openrtb_ext.ExtRequestTargeting, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, categoriesFetcher stored_requests.CategoryFetcher, targData *targetData, booleanGenerator deduplicateChanceGenerator, categories map[string]string, seatNonBidBuilder *SeatNonBidBuilder) (map[string]string, map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, []string, error) {
 	res := make(map[string]string)
 
 	type bidDedupe struct {
@@ -1077,6 +1173,15 @@ func applyCategoryMapping(ctx context.Context, targeting openrtb_ext.ExtRequestT
 				categoryDuration = fmt.Sprintf("%s_%s", categoryDuration, bidderName.String())
 			}
 
+			// Bids of requests with ad pods aren't deduplicated, the ad pod auction separates them by category instead
+			if categories != nil {
+				if brandCatExt.WithCategory {
+					categories[bidID] = category
+				}
+				res[bidID] = categoryDuration
+				continue
+			}
+
 			if dupe, ok := dedupe[dupeKey]; ok {
 
 				dupeBidPrice, err := strconv.ParseFloat(dupe.bidPrice, 64)
@@ -1269,14 +1374,14 @@ func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*en
 
 // Return an openrtb seatBid for a bidder
 // buildBidResponse is responsible for ensuring nil bid seatbids are not included
-func (e *exchange) makeSeatBid(adapterBid *entities.PbsOrtbSeatBid, adapter openrtb_ext.BidderName, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, auc *auction, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, bidRequest *openrtb_ext.RequestWrapper, bidResponseExt *openrtb_ext.ExtBidResponse, pubID string, seatNonBidBuilder *SeatNonBidBuilder) *openrtb2.SeatBid {
+func (e *exchange) makeSeatBid(adapterBid *entities.PbsOrtbSeatBid, adapter openrtb_ext.BidderName, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, auc *auction, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, bidRequest *openrtb_ext.RequestWrapper, bidResponseExt *openrtb_ext.ExtBidResponse, pubID string, bidConformance string, seatNonBidBuilder *SeatNonBidBuilder) *openrtb2.SeatBid {
 	seatBid := &openrtb2.SeatBid{
 		Seat:  adapter.String(),
 		Group: 0, // Prebid cannot support roadblocking
 	}
 
 	var errList []error
-	seatBid.Bid, errList = e.makeBid(adapterBid.Bids, auc, returnCreative, impExtInfoMap, bidRequest, bidResponseExt, adapter, pubID, seatNonBidBuilder)
+	seatBid.Bid, errList = e.makeBid(adapterBid.Bids, auc, returnCreative, impExtInfoMap, bidRequest, bidResponseExt, adapter, pubID, bidConformance, seatNonBidBuilder)
 	if len(errList) > 0 {
 		adapterExtra[adapter].Errors = append(adapterExtra[adapter].Errors, errsToBidderErrors(errList)...)
 	}
@@ -1284,10 +1389,19 @@ func (e *exchange) makeSeatBid(adapterBid *entities.PbsOrtbSeatBid, adapter open
 	return seatBid
 }
 
-func (e *exchange) makeBid(bids []*entities.PbsOrtbBid, auc *auction, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, bidRequest *openrtb_ext.RequestWrapper, bidResponseExt *openrtb_ext.ExtBidResponse, adapter openrtb_ext.BidderName, pubID string, seatNonBidBuilder *SeatNonBidBuilder) ([]openrtb2.Bid, []error) {
+func (e *exchange) makeBid(bids []*entities.PbsOrtbBid, auc *auction, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, bidRequest *openrtb_ext.RequestWrapper, bidResponseExt *openrtb_ext.ExtBidResponse, adapter openrtb_ext.BidderName, pubID string, bidConformance string, seatNonBidBuilder *SeatNonBidBuilder) ([]openrtb2.Bid, []error) {
 	result := make([]openrtb2.Bid, 0, len(bids))
 	errs := make([]error, 0, 1)
 
+	checkConformance := bidConformance == config.ValidationEnforce || bidConformance == config.ValidationWarn
+	var impsByID map[string]*openrtb2.Imp
+	if checkConformance {
+		impsByID = make(map[string]*openrtb2.Imp, len(bidRequest.Imp))
+		for i := range bidRequest.Imp {
+			impsByID[bidRequest.Imp[i].ID] = &bidRequest.Imp[i]
+		}
+	}
+
 	for _, bid := range bids {
 		if err := dsa.Validate(bidRequest, bid); err != nil {
 			dsaMessage := openrtb_ext.ExtBidderMessage{
@@ -1318,6 +1432,12 @@ func (e *exchange) makeBid(bids []*entities.PbsOrtbBid, auc *auction, returnCrea
 			}
 
 		}
+		if checkConformance {
+			if reason, ok := e.validateBidConformance(bid, impsByID[bid.Bid.ImpID], bidRequest, bidResponseExt, adapter, pubID, bidConformance); !ok && bidConformance == config.ValidationEnforce {
+				seatNonBidBuilder.rejectBid(bid, int(reason), adapter.String())
+				continue // Don't add bid to result
+			}
+		}
 		bidExtPrebid := &openrtb_ext.ExtBidPrebid{
 			DealPriority:      bid.DealPriority,
 			DealTierSatisfied: bid.DealTierSatisfied,
@@ -0,0 +1,128 @@
+package exchange
+
+import (
+	"sort"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// recordFloorsReport describes how price floors were applied to the auction, and adds it to the floors report.
+// It returns nil if price floors aren't enabled or weren't resolved for the request.
+func (e *exchange) recordFloorsReport(r *AuctionRequest, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, rejectedBids []*entities.PbsOrtbSeatBid, auc *auction, bidResponse *openrtb2.BidResponse, conversions currency.Conversions) *analytics.FloorsObject {
+	if !e.priceFloorEnabled {
+		return nil
+	}
+	floorsObject := buildFloorsObject(r.BidRequestWrapper, adapterBids, rejectedBids, auc, bidResponse)
+	e.floorsReporter.Record(r.Account.ID, floorsObject, conversions)
+	return floorsObject
+}
+
+func buildFloorsObject(req *openrtb_ext.RequestWrapper, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, rejectedBids []*entities.PbsOrtbSeatBid, auc *auction, bidResponse *openrtb2.BidResponse) *analytics.FloorsObject {
+	requestExt, err := req.GetRequestExt()
+	if err != nil {
+		return nil
+	}
+	prebidExt := requestExt.GetPrebid()
+	if prebidExt == nil || prebidExt.Floors == nil {
+		return nil
+	}
+	floors := prebidExt.Floors
+
+	floorsObject := &analytics.FloorsObject{
+		Location:    floors.PriceFloorLocation,
+		FetchStatus: floors.FetchStatus,
+		Skipped:     floors.Skipped != nil && *floors.Skipped,
+		Enforced:    floors.GetEnforcePBS(),
+	}
+	if floors.Data != nil && len(floors.Data.ModelGroups) > 0 {
+		floorsObject.ModelVersion = floors.Data.ModelGroups[0].ModelVersion
+	}
+
+	for _, seatBid := range rejectedBids {
+		for _, bid := range seatBid.Bids {
+			floorsObject.RejectedBids = append(floorsObject.RejectedBids, newFloorsBid(seatBid.Seat, seatBid.Currency, bid))
+		}
+	}
+	floorsObject.WinningBids = floorsWinningBids(auc, adapterBids, bidResponse)
+	return floorsObject
+}
+
+// floorsWinningBids returns the winning bid of each imp of the response, ordered by imp id. Only the bids of the
+// final response can win. The winner of an imp is the one picked by the auction when targeting ran it, so that
+// deals are preferred the same way, and the highest priced bid otherwise.
+func floorsWinningBids(auc *auction, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, bidResponse *openrtb2.BidResponse) []analytics.FloorsBid {
+	if bidResponse == nil {
+		return nil
+	}
+	returned := make(map[string]map[string]bool, len(bidResponse.SeatBid))
+	for _, seatBid := range bidResponse.SeatBid {
+		bidIDs := make(map[string]bool, len(seatBid.Bid))
+		for _, bid := range seatBid.Bid {
+			bidIDs[bid.ID] = true
+		}
+		returned[seatBid.Seat] = bidIDs
+	}
+
+	winners := make(map[string]analytics.FloorsBid)
+	auctionWinners := make(map[string]bool)
+	for bidderName, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		seat := seatBid.Seat
+		if seat == "" {
+			seat = bidderName.String()
+		}
+		for _, bid := range seatBid.Bids {
+			if bid == nil || bid.Bid == nil || !returned[bidderName.String()][bid.Bid.ID] {
+				continue
+			}
+			impID := bid.Bid.ImpID
+			if auc != nil && auc.winningBids[impID] == bid {
+				winners[impID] = newFloorsBid(seat, seatBid.Currency, bid)
+				auctionWinners[impID] = true
+				continue
+			}
+			if auctionWinners[impID] {
+				continue
+			}
+			winner, ok := winners[impID]
+			if !ok || bid.Bid.Price > winner.Price || (bid.Bid.Price == winner.Price && seat < winner.Bidder) {
+				winners[impID] = newFloorsBid(seat, seatBid.Currency, bid)
+			}
+		}
+	}
+	if len(winners) == 0 {
+		return nil
+	}
+	impIDs := make([]string, 0, len(winners))
+	for impID := range winners {
+		impIDs = append(impIDs, impID)
+	}
+	sort.Strings(impIDs)
+	winningBids := make([]analytics.FloorsBid, 0, len(impIDs))
+	for _, impID := range impIDs {
+		winningBids = append(winningBids, winners[impID])
+	}
+	return winningBids
+}
+
+func newFloorsBid(seat, bidCurrency string, bid *entities.PbsOrtbBid) analytics.FloorsBid {
+	floorsBid := analytics.FloorsBid{
+		Bidder:   seat,
+		ImpID:    bid.Bid.ImpID,
+		BidID:    bid.Bid.ID,
+		Price:    bid.Bid.Price,
+		Currency: bidCurrency,
+	}
+	if bid.BidFloors != nil {
+		floorsBid.Rule = bid.BidFloors.FloorRule
+		floorsBid.Floor = bid.BidFloors.FloorValue
+		floorsBid.FloorCur = bid.BidFloors.FloorCurrency
+	}
+	return floorsBid
+}
@@ -0,0 +1,149 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/floorsreport"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildFloorsObject(t *testing.T) {
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {
+			Currency: "USD",
+			Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "an1", ImpID: "imp1", Price: 2}, BidFloors: &openrtb_ext.ExtBidPrebidFloors{FloorRule: "banner|*", FloorValue: 1, FloorCurrency: "USD"}},
+				{Bid: &openrtb2.Bid{ID: "an2", ImpID: "imp2", Price: 1}},
+			},
+		},
+		"rubicon": {
+			Seat:     "rubicon-seat",
+			Currency: "USD",
+			Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "rb1", ImpID: "imp1", Price: 1.5}},
+				{Bid: &openrtb2.Bid{ID: "rb2", ImpID: "imp2", Price: 3}},
+			},
+		},
+	}
+	rejectedBids := []*entities.PbsOrtbSeatBid{
+		{
+			Seat:     "pubmatic",
+			Currency: "USD",
+			Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "pm1", ImpID: "imp1", Price: 0.5}, BidFloors: &openrtb_ext.ExtBidPrebidFloors{FloorRule: "banner|*", FloorValue: 1, FloorCurrency: "USD"}},
+			},
+		},
+	}
+
+	// rb2 was dropped while building the response
+	bidResponse := &openrtb2.BidResponse{SeatBid: []openrtb2.SeatBid{
+		{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "an1"}, {ID: "an2"}}},
+		{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "rb1"}}},
+	}}
+
+	testCases := []struct {
+		name     string
+		reqExt   string
+		expected *analytics.FloorsObject
+	}{
+		{
+			name:     "no-floors",
+			reqExt:   `{"prebid":{}}`,
+			expected: nil,
+		},
+		{
+			name:   "floors-applied",
+			reqExt: `{"prebid":{"floors":{"location":"fetch","fetchstatus":"success","skipped":false,"enforcement":{"enforcepbs":true},"data":{"modelgroups":[{"modelversion":"model_a","values":{"banner|*":1}}]}}}}`,
+			expected: &analytics.FloorsObject{
+				ModelVersion: "model_a",
+				Location:     "fetch",
+				FetchStatus:  "success",
+				Enforced:     true,
+				RejectedBids: []analytics.FloorsBid{
+					{Bidder: "pubmatic", ImpID: "imp1", BidID: "pm1", Rule: "banner|*", Floor: 1, FloorCur: "USD", Price: 0.5, Currency: "USD"},
+				},
+				WinningBids: []analytics.FloorsBid{
+					{Bidder: "appnexus", ImpID: "imp1", BidID: "an1", Rule: "banner|*", Floor: 1, FloorCur: "USD", Price: 2, Currency: "USD"},
+					{Bidder: "appnexus", ImpID: "imp2", BidID: "an2", Price: 1, Currency: "USD"},
+				},
+			},
+		},
+		{
+			name:   "floors-skipped",
+			reqExt: `{"prebid":{"floors":{"location":"request","skipped":true}}}`,
+			expected: &analytics.FloorsObject{
+				Location: "request",
+				Skipped:  true,
+				Enforced: true,
+				RejectedBids: []analytics.FloorsBid{
+					{Bidder: "pubmatic", ImpID: "imp1", BidID: "pm1", Rule: "banner|*", Floor: 1, FloorCur: "USD", Price: 0.5, Currency: "USD"},
+				},
+				WinningBids: []analytics.FloorsBid{
+					{Bidder: "appnexus", ImpID: "imp1", BidID: "an1", Rule: "banner|*", Floor: 1, FloorCur: "USD", Price: 2, Currency: "USD"},
+					{Bidder: "appnexus", ImpID: "imp2", BidID: "an2", Price: 1, Currency: "USD"},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Ext: json.RawMessage(test.reqExt)}}
+			assert.Equal(t, test.expected, buildFloorsObject(req, adapterBids, rejectedBids, nil, bidResponse))
+		})
+	}
+}
+
+func TestFloorsWinningBids(t *testing.T) {
+	dealBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "rb1", ImpID: "imp1", Price: 1, DealID: "deal"}}
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"rubicon":  {Bids: []*entities.PbsOrtbBid{dealBid}},
+		"appnexus": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "an1", ImpID: "imp1", Price: 1}}}},
+		"pubmatic": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "pm1", ImpID: "imp1", Price: 2}}}},
+	}
+	bidResponse := &openrtb2.BidResponse{SeatBid: []openrtb2.SeatBid{
+		{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "rb1"}}},
+		{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "an1"}}},
+	}}
+
+	winners := floorsWinningBids(nil, adapterBids, bidResponse)
+	if assert.Len(t, winners, 1) {
+		assert.Equal(t, "appnexus", winners[0].Bidder, "ties go to the first seat and bids missing from the response can't win")
+	}
+
+	auc := &auction{winningBids: map[string]*entities.PbsOrtbBid{"imp1": dealBid}}
+	winners = floorsWinningBids(auc, adapterBids, bidResponse)
+	if assert.Len(t, winners, 1) {
+		assert.Equal(t, "rubicon", winners[0].Bidder, "the winner of the auction")
+	}
+
+	assert.Nil(t, floorsWinningBids(nil, adapterBids, nil))
+	assert.Nil(t, floorsWinningBids(auc, nil, bidResponse))
+}
+
+func TestRecordFloorsReport(t *testing.T) {
+	reporter := floorsreport.NewReporter(config.PriceFloorsReporting{Enabled: true, MaxModels: 10}, nil)
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Ext: json.RawMessage(`{"prebid":{"floors":{"data":{"modelgroups":[{"modelversion":"model_a"}]}}}}`)}}
+	r := &AuctionRequest{BidRequestWrapper: req, Account: config.Account{ID: "acct"}}
+
+	disabled := &exchange{priceFloorEnabled: false, floorsReporter: reporter}
+	assert.Nil(t, disabled.recordFloorsReport(r, nil, nil, nil, nil, currency.NewConstantRates()))
+	assert.Empty(t, reporter.Report("").Models)
+
+	enabled := &exchange{priceFloorEnabled: true, floorsReporter: reporter}
+	floorsObject := enabled.recordFloorsReport(r, nil, nil, nil, nil, currency.NewConstantRates())
+	assert.Equal(t, &analytics.FloorsObject{ModelVersion: "model_a", Enforced: true}, floorsObject)
+
+	report := reporter.Report("acct")
+	if assert.Len(t, report.Models, 1) {
+		assert.Equal(t, "model_a", report.Models[0].ModelVersion)
+		assert.Equal(t, int64(1), report.Models[0].Requests)
+	}
+}
@@ -21,14 +21,21 @@ const (
 	ResponseRejectedBelowFloor             NonBidReason = 301 // Response Rejected - Below Floor
 	ResponseRejectedCategoryMappingInvalid NonBidReason = 303 // Response Rejected - Category Mapping Invalid
 	ResponseRejectedBelowDealFloor         NonBidReason = 304 // Response Rejected - Bid was Below Deal Floor
+	ResponseRejectedInvalidCreative        NonBidReason = 350 // Response Rejected - Invalid Creative
 	ResponseRejectedCreativeSizeNotAllowed NonBidReason = 351 // Response Rejected - Invalid Creative (Size Not Allowed)
 	ResponseRejectedCreativeNotSecure      NonBidReason = 352 // Response Rejected - Invalid Creative (Not Secure)
+	RequestBlockedCircuitBreakerOpen       NonBidReason = 500 // Exchange specific - Bidder request skipped while its circuit breaker is open
+	RequestBlockedTrafficShaping           NonBidReason = 501 // Exchange specific - Bidder request skipped by traffic shaping
+	ResponseRejectedNonConforming          NonBidReason = 502 // Exchange specific - Bid doesn't match its imp or the bidder's declared capabilities
+	ResponseRejectedAdPod                  NonBidReason = 503 // Exchange specific - Bid lost the ad pod auction or was excluded by competitive separation
 )
 
 func errorToNonBidReason(err error) NonBidReason {
 	switch errortypes.ReadCode(err) {
 	case errortypes.TimeoutErrorCode:
 		return ErrorTimeout
+	case errortypes.CircuitBreakerOpenErrorCode:
+		return RequestBlockedCircuitBreakerOpen
 	default:
 		return ErrorGeneral
 	}
@@ -28,6 +28,15 @@ func Test_httpInfoToNonBidReason(t *testing.T) {
 			},
 			want: ErrorTimeout,
 		},
+		{
+			name: "error-circuitBreakerOpen",
+			args: args{
+				httpInfo: &httpCallInfo{
+					err: &errortypes.CircuitBreakerOpen{},
+				},
+			},
+			want: RequestBlockedCircuitBreakerOpen,
+		},
 		{
 			name: "error-general",
 			args: args{
@@ -0,0 +1,85 @@
+package exchange
+
+import (
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/usersync"
+)
+
+// syncValueRecorder learns what the bidders win with and without a UID, implemented by usersync.ValueTracker.
+type syncValueRecorder interface {
+	RecordAuction(account, bidder string, hasUID bool, cpm float64)
+}
+
+// newSyncValueRecorder returns nil if the value tracker is, so the auctions skip the recording altogether.
+func newSyncValueRecorder(tracker *usersync.ValueTracker) syncValueRecorder {
+	if tracker == nil {
+		return nil
+	}
+	return tracker
+}
+
+// SyncValueTracker returns the tracker the exchange teaches the value of syncing each bidder, which ranks the
+// bidders of /cookie_sync. It's nil if the value ranking is disabled.
+func SyncValueTracker(ex Exchange) *usersync.ValueTracker {
+	e, ok := ex.(*exchange)
+	if !ok {
+		return nil
+	}
+	tracker, _ := e.syncValueRecorder.(*usersync.ValueTracker)
+	return tracker
+}
+
+// recordSyncValue teaches the user sync value tracker what each bidder of the auction won, and whether the user
+// had a UID for it. It does nothing if the value ranking isn't enabled or the bids are stored responses. Only the
+// site requests are recorded, since the UIDs of the other channels don't come from the syncs.
+func (e *exchange) recordSyncValue(r *AuctionRequest, liveAdapters []openrtb_ext.BidderName, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+	if e.syncValueRecorder == nil || len(r.StoredAuctionResponses) > 0 {
+		return
+	}
+	if r.BidRequestWrapper == nil || r.BidRequestWrapper.Site == nil {
+		return
+	}
+
+	wonCPM := winningCPMByBidder(adapterBids)
+	for _, bidder := range liveAdapters {
+		syncerKey, ok := e.bidderToSyncerKey[bidder.String()]
+		if !ok {
+			continue
+		}
+		hasUID := false
+		if r.UserSyncs != nil {
+			_, _, hasUID = r.UserSyncs.GetUID(syncerKey)
+		}
+		e.syncValueRecorder.RecordAuction(r.Account.ID, bidder.String(), hasUID, wonCPM[bidder])
+	}
+}
+
+// winningCPMByBidder sums the price of the highest bid of each imp, by the bidder which made it.
+func winningCPMByBidder(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) map[openrtb_ext.BidderName]float64 {
+	type winner struct {
+		bidder openrtb_ext.BidderName
+		price  float64
+	}
+	winners := make(map[string]winner)
+	for bidder, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			if bid == nil || bid.Bid == nil {
+				continue
+			}
+			current, ok := winners[bid.Bid.ImpID]
+			if !ok || bid.Bid.Price > current.price || (bid.Bid.Price == current.price && bidder < current.bidder) {
+				winners[bid.Bid.ImpID] = winner{bidder: bidder, price: bid.Bid.Price}
+			}
+		}
+	}
+
+	wonCPM := make(map[openrtb_ext.BidderName]float64, len(winners))
+	for _, w := range winners {
+		wonCPM[w.bidder] += w.price
+	}
+	return wonCPM
+}
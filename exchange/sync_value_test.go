@@ -0,0 +1,125 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/stored_responses"
+	"github.com/prebid/prebid-server/v3/usersync"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSyncValueRecorder(t *testing.T) {
+	assert.Nil(t, newSyncValueRecorder(nil))
+	assert.NotNil(t, newSyncValueRecorder(usersync.NewValueTracker(config.UserSyncValueRanking{Enabled: true})))
+}
+
+func TestSyncValueTracker(t *testing.T) {
+	tracker := usersync.NewValueTracker(config.UserSyncValueRanking{Enabled: true})
+	assert.Same(t, tracker, SyncValueTracker(&exchange{syncValueRecorder: newSyncValueRecorder(tracker)}))
+	assert.Nil(t, SyncValueTracker(&exchange{}))
+}
+
+func TestWinningCPMByBidder(t *testing.T) {
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Bids: []*entities.PbsOrtbBid{
+			{Bid: &openrtb2.Bid{ImpID: "imp1", Price: 2}},
+			{Bid: &openrtb2.Bid{ImpID: "imp2", Price: 1}},
+		}},
+		"rubicon": {Bids: []*entities.PbsOrtbBid{
+			{Bid: &openrtb2.Bid{ImpID: "imp1", Price: 1.5}},
+			{Bid: &openrtb2.Bid{ImpID: "imp2", Price: 3}},
+			{Bid: &openrtb2.Bid{ImpID: "imp3", Price: 0.5}},
+		}},
+		"pubmatic": {Bids: []*entities.PbsOrtbBid{
+			{Bid: &openrtb2.Bid{ImpID: "imp3", Price: 0.5}},
+		}},
+		"empty": nil,
+	}
+
+	assert.Equal(t, map[openrtb_ext.BidderName]float64{"appnexus": 2, "rubicon": 3, "pubmatic": 0.5}, winningCPMByBidder(adapterBids),
+		"ties go to the first bidder by name")
+}
+
+func TestRecordSyncValue(t *testing.T) {
+	withUID := usersync.NewCookie()
+	withUID.Sync("adnxs", "uid")
+
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ImpID: "imp1", Price: 2}}}},
+	}
+	liveAdapters := []openrtb_ext.BidderName{"appnexus", "rubicon", "unsynced"}
+	site := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Site: &openrtb2.Site{}}}
+
+	testCases := []struct {
+		description      string
+		givenRequest     *AuctionRequest
+		expectedAuctions []recordedAuction
+	}{
+		{
+			description:  "with-uid",
+			givenRequest: &AuctionRequest{BidRequestWrapper: site, Account: config.Account{ID: "acct"}, UserSyncs: withUID},
+			expectedAuctions: []recordedAuction{
+				{account: "acct", bidder: "appnexus", hasUID: true, cpm: 2},
+				{account: "acct", bidder: "rubicon", hasUID: false, cpm: 0},
+			},
+		},
+		{
+			description:  "without-cookie",
+			givenRequest: &AuctionRequest{BidRequestWrapper: site, Account: config.Account{ID: "acct"}},
+			expectedAuctions: []recordedAuction{
+				{account: "acct", bidder: "appnexus", hasUID: false, cpm: 2},
+				{account: "acct", bidder: "rubicon", hasUID: false, cpm: 0},
+			},
+		},
+		{
+			description: "stored-responses",
+			givenRequest: &AuctionRequest{
+				BidRequestWrapper:      site,
+				Account:                config.Account{ID: "acct"},
+				UserSyncs:              withUID,
+				StoredAuctionResponses: stored_responses.ImpsWithBidResponses{"imp1": nil},
+			},
+			expectedAuctions: nil,
+		},
+		{
+			description: "app",
+			givenRequest: &AuctionRequest{
+				BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{App: &openrtb2.App{}}},
+				Account:           config.Account{ID: "acct"},
+				UserSyncs:         withUID,
+			},
+			expectedAuctions: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := &fakeSyncValueRecorder{}
+			e := &exchange{
+				syncValueRecorder: recorder,
+				bidderToSyncerKey: map[string]string{"appnexus": "adnxs", "rubicon": "rubicon"},
+			}
+			e.recordSyncValue(test.givenRequest, liveAdapters, adapterBids)
+			assert.Equal(t, test.expectedAuctions, recorder.auctions)
+		})
+	}
+}
+
+type recordedAuction struct {
+	account string
+	bidder  string
+	hasUID  bool
+	cpm     float64
+}
+
+type fakeSyncValueRecorder struct {
+	auctions []recordedAuction
+}
+
+func (r *fakeSyncValueRecorder) RecordAuction(account, bidder string, hasUID bool, cpm float64) {
+	r.auctions = append(r.auctions, recordedAuction{account: account, bidder: bidder, hasUID: hasUID, cpm: cpm})
+}
@@ -0,0 +1,112 @@
+package exchange
+
+import (
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/trafficshaping"
+)
+
+// shapeTraffic removes the imps which traffic shaping decided not to send from the bidder requests, and drops
+// the bidders left without imps. The skipped imps are returned as seat non bids, and along with the bid rates
+// behind each decision for ext.debug.trafficshaping.
+func (e *exchange) shapeTraffic(account *config.Account, bidderRequests []BidderRequest) ([]BidderRequest, SeatNonBidBuilder, map[openrtb_ext.BidderName][]openrtb_ext.ExtTrafficShapingImp) {
+	if e.trafficShaper == nil || !account.TrafficShaping.Enabled {
+		return bidderRequests, nil, nil
+	}
+
+	seatNonBidBuilder := SeatNonBidBuilder{}
+	throttled := make(map[openrtb_ext.BidderName][]openrtb_ext.ExtTrafficShapingImp)
+	shapedRequests := make([]BidderRequest, 0, len(bidderRequests))
+
+	for _, bidderRequest := range bidderRequests {
+		var keptImps []openrtb2.Imp
+		var throttledImpIDs []string
+		for i, imp := range bidderRequest.BidRequest.Imp {
+			if _, stored := bidderRequest.BidderStoredResponses[imp.ID]; stored {
+				keptImps = append(keptImps, imp)
+				continue
+			}
+			key := trafficshaping.KeyFor(account.ID, bidderRequest.BidderName, bidderRequest.BidRequest, &bidderRequest.BidRequest.Imp[i])
+			decision := e.trafficShaper.Decide(account.TrafficShaping, key)
+			if !decision.Throttled {
+				keptImps = append(keptImps, imp)
+				continue
+			}
+			throttledImpIDs = append(throttledImpIDs, imp.ID)
+			throttled[bidderRequest.BidderName] = append(throttled[bidderRequest.BidderName], openrtb_ext.ExtTrafficShapingImp{
+				ImpID:    imp.ID,
+				BidRate:  decision.BidRate,
+				Requests: decision.Requests,
+			})
+		}
+
+		if len(throttledImpIDs) == 0 {
+			shapedRequests = append(shapedRequests, bidderRequest)
+			continue
+		}
+		seatNonBidBuilder.rejectImps(throttledImpIDs, RequestBlockedTrafficShaping, bidderRequest.BidderName.String())
+		if len(keptImps) == 0 {
+			continue
+		}
+
+		shapedRequest := *bidderRequest.BidRequest
+		shapedRequest.Imp = keptImps
+		bidderRequest.BidRequest = &shapedRequest
+		shapedRequests = append(shapedRequests, bidderRequest)
+	}
+
+	return shapedRequests, seatNonBidBuilder, throttled
+}
+
+// recordTrafficShaping feeds the outcome of every imp sent to a bidder back into the traffic shaping model.
+// Imps which ended in a timeout or an error rather than a bid or no bid are left out, since they say nothing
+// about the bidder's interest in the traffic. Bids rejected by the exchange still count as bids.
+func (e *exchange) recordTrafficShaping(account *config.Account, bidderRequests []BidderRequest, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, seatNonBidBuilder SeatNonBidBuilder) {
+	if e.trafficShaper == nil || !account.TrafficShaping.Enabled {
+		return
+	}
+
+	for _, bidderRequest := range bidderRequests {
+		failedImps := make(map[string]struct{})
+		bidImps := make(map[string]struct{})
+		for _, nonBid := range seatNonBidBuilder[bidderRequest.BidderName.String()] {
+			switch reason := NonBidReason(nonBid.StatusCode); {
+			case isErrorNonBidReason(reason):
+				failedImps[nonBid.ImpId] = struct{}{}
+			case isResponseRejectedNonBidReason(reason):
+				bidImps[nonBid.ImpId] = struct{}{}
+			}
+		}
+		if seatBid, ok := adapterBids[bidderRequest.BidderName]; ok && seatBid != nil {
+			for _, bid := range seatBid.Bids {
+				if bid != nil && bid.Bid != nil {
+					bidImps[bid.Bid.ImpID] = struct{}{}
+				}
+			}
+		}
+
+		for i, imp := range bidderRequest.BidRequest.Imp {
+			if _, stored := bidderRequest.BidderStoredResponses[imp.ID]; stored {
+				continue
+			}
+			if _, failed := failedImps[imp.ID]; failed {
+				continue
+			}
+			_, bid := bidImps[imp.ID]
+			key := trafficshaping.KeyFor(account.ID, bidderRequest.BidderName, bidderRequest.BidRequest, &bidderRequest.BidRequest.Imp[i])
+			e.trafficShaper.Record(key, bid)
+		}
+	}
+}
+
+// isErrorNonBidReason tells whether the request for the imp failed or never reached the bidder.
+func isErrorNonBidReason(reason NonBidReason) bool {
+	return (reason >= ErrorGeneral && reason < 200) || reason == RequestBlockedCircuitBreakerOpen
+}
+
+// isResponseRejectedNonBidReason tells whether the bidder bid on the imp, but the bid was rejected.
+func isResponseRejectedNonBidReason(reason NonBidReason) bool {
+	return (reason >= ResponseRejectedGeneral && reason < 400) || reason == ResponseRejectedNonConforming || reason == ResponseRejectedAdPod
+}
@@ -0,0 +1,115 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/trafficshaping"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShapeTraffic(t *testing.T) {
+	account := &config.Account{
+		ID: "acct",
+		TrafficShaping: config.AccountTrafficShaping{
+			Enabled:          true,
+			MinRequests:      2,
+			BidRateThreshold: 0.5,
+			ExplorationRate:  0,
+		},
+	}
+	bidRequest := &openrtb2.BidRequest{
+		ID: "request-id",
+		Imp: []openrtb2.Imp{
+			{ID: "imp-1", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}},
+			{ID: "imp-2", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 728, H: 90}}}},
+		},
+	}
+	bidderRequests := []BidderRequest{
+		{BidderName: openrtb_ext.BidderAppnexus, BidRequest: bidRequest},
+		{BidderName: openrtb_ext.BidderRubicon, BidRequest: &openrtb2.BidRequest{ID: "request-id", Imp: bidRequest.Imp[:1]}},
+		{
+			BidderName:            openrtb_ext.BidderOpenx,
+			BidRequest:            &openrtb2.BidRequest{ID: "request-id", Imp: bidRequest.Imp[:1]},
+			BidderStoredResponses: map[string]json.RawMessage{"imp-1": json.RawMessage(`{}`)},
+		},
+	}
+
+	e := &exchange{trafficShaper: trafficshaping.NewShaper(config.TrafficShaping{Enabled: true, WindowMinutes: 60, MaxKeys: 100})}
+
+	// Nothing is throttled until there's a history to go by
+	shaped, nonBids, debug := e.shapeTraffic(account, bidderRequests)
+	assert.Equal(t, bidderRequests, shaped)
+	assert.Empty(t, nonBids)
+	assert.Empty(t, debug)
+
+	// Appnexus bids on 728x90 only, rubicon never bids
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		openrtb_ext.BidderAppnexus: {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ImpID: "imp-2"}}}},
+	}
+	for i := 0; i < 2; i++ {
+		e.recordTrafficShaping(account, bidderRequests, adapterBids, SeatNonBidBuilder{})
+	}
+	// Errors aren't counted as no bids
+	e.recordTrafficShaping(account, bidderRequests, adapterBids, SeatNonBidBuilder{"appnexus": {{ImpId: "imp-2", StatusCode: int(ErrorTimeout)}}})
+
+	shaped, nonBids, debug = e.shapeTraffic(account, bidderRequests)
+	assert.Equal(t, []BidderRequest{
+		{BidderName: openrtb_ext.BidderAppnexus, BidRequest: &openrtb2.BidRequest{ID: "request-id", Imp: bidRequest.Imp[1:]}},
+		bidderRequests[2],
+	}, shaped)
+	assert.Equal(t, SeatNonBidBuilder{
+		"appnexus": {{ImpId: "imp-1", StatusCode: int(RequestBlockedTrafficShaping)}},
+		"rubicon":  {{ImpId: "imp-1", StatusCode: int(RequestBlockedTrafficShaping)}},
+	}, nonBids)
+	assert.Equal(t, map[openrtb_ext.BidderName][]openrtb_ext.ExtTrafficShapingImp{
+		openrtb_ext.BidderAppnexus: {{ImpID: "imp-1", BidRate: 0, Requests: 3}},
+		openrtb_ext.BidderRubicon:  {{ImpID: "imp-1", BidRate: 0, Requests: 3}},
+	}, debug)
+	assert.Len(t, bidRequest.Imp, 2, "the original request must not be modified")
+
+	// Accounts which haven't opted in are never throttled
+	account.TrafficShaping.Enabled = false
+	shaped, nonBids, debug = e.shapeTraffic(account, bidderRequests)
+	assert.Equal(t, bidderRequests, shaped)
+	assert.Empty(t, nonBids)
+	assert.Empty(t, debug)
+}
+
+func TestRecordTrafficShapingNonBids(t *testing.T) {
+	account := &config.Account{ID: "acct", TrafficShaping: config.AccountTrafficShaping{Enabled: true, MinRequests: 100}}
+	imp := openrtb2.Imp{ID: "imp-1", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}}
+	bidderRequests := []BidderRequest{{BidderName: openrtb_ext.BidderAppnexus, BidRequest: &openrtb2.BidRequest{ID: "request-id", Imp: []openrtb2.Imp{imp}}}}
+	key := trafficshaping.KeyFor(account.ID, openrtb_ext.BidderAppnexus, bidderRequests[0].BidRequest, &imp)
+
+	testCases := []struct {
+		description      string
+		givenReason      NonBidReason
+		expectedBidRate  float64
+		expectedRequests int
+	}{
+		{description: "timeout", givenReason: ErrorTimeout, expectedBidRate: 0, expectedRequests: 0},
+		{description: "error", givenReason: ErrorBidderUnreachable, expectedBidRate: 0, expectedRequests: 0},
+		{description: "circuit-breaker-open", givenReason: RequestBlockedCircuitBreakerOpen, expectedBidRate: 0, expectedRequests: 0},
+		{description: "rejected-below-floor", givenReason: ResponseRejectedBelowFloor, expectedBidRate: 1, expectedRequests: 1},
+		{description: "rejected-ad-pod", givenReason: ResponseRejectedAdPod, expectedBidRate: 1, expectedRequests: 1},
+		{description: "other", givenReason: RequestBlockedTrafficShaping, expectedBidRate: 0, expectedRequests: 1},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			e := &exchange{trafficShaper: trafficshaping.NewShaper(config.TrafficShaping{Enabled: true, WindowMinutes: 60, MaxKeys: 100})}
+			seatNonBidBuilder := SeatNonBidBuilder{"appnexus": {{ImpId: "imp-1", StatusCode: int(test.givenReason)}}}
+
+			e.recordTrafficShaping(account, bidderRequests, nil, seatNonBidBuilder)
+
+			decision := e.trafficShaper.Decide(account.TrafficShaping, key)
+			assert.Equal(t, test.expectedBidRate, decision.BidRate)
+			assert.Equal(t, test.expectedRequests, decision.Requests)
+		})
+	}
+}
@@ -107,11 +107,16 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 	}
 
 	var gpp gpplib.GppContainer
+	// activitiesGPP is parsed once for the activities of every bidder. A malformed GPP string carries no choices
+	// for them to enforce.
+	var activitiesGPP gpplib.GppContainer
 	if req.BidRequest.Regs != nil && len(req.BidRequest.Regs.GPP) > 0 {
 		var gppErrs []error
 		gpp, gppErrs = gpplib.Parse(req.BidRequest.Regs.GPP)
 		if len(gppErrs) > 0 {
 			errs = append(errs, gppErrs[0])
+		} else {
+			activitiesGPP = gpp
 		}
 	}
 
@@ -194,7 +199,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		auctionPermissions := gdprPerms.AuctionActivitiesAllowed(ctx, coreBidder, openrtb_ext.BidderName(bidder))
 
 		// privacy blocking
-		if rs.isBidderBlockedByPrivacy(reqWrapperCopy, auctionReq.Activities, auctionPermissions, coreBidder, openrtb_ext.BidderName(bidder)) {
+		if rs.isBidderBlockedByPrivacy(reqWrapperCopy, activitiesGPP, auctionReq.Activities, auctionPermissions, coreBidder, openrtb_ext.BidderName(bidder)) {
 			continue
 		}
 
@@ -202,7 +207,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		applyFPD(auctionReq.FirstPartyData, coreBidder, openrtb_ext.BidderName(bidder), isRequestAlias, reqWrapperCopy, fpdUserEIDsPresent)
 
 		// privacy scrubbing
-		if err := rs.applyPrivacy(reqWrapperCopy, coreBidder, bidder, auctionReq, auctionPermissions, ccpaEnforcer, lmt, coppa); err != nil {
+		if err := rs.applyPrivacy(reqWrapperCopy, activitiesGPP, coreBidder, bidder, auctionReq, auctionPermissions, ccpaEnforcer, lmt, coppa); err != nil {
 			errs = append(errs, err)
 			continue
 		}
@@ -347,10 +352,10 @@ func PreloadExts(req *openrtb_ext.RequestWrapper) error {
 	return nil
 }
 
-func (rs *requestSplitter) isBidderBlockedByPrivacy(r *openrtb_ext.RequestWrapper, activities privacy.ActivityControl, auctionPermissions gdpr.AuctionPermissions, coreBidder, bidderName openrtb_ext.BidderName) bool {
+func (rs *requestSplitter) isBidderBlockedByPrivacy(r *openrtb_ext.RequestWrapper, gpp gpplib.GppContainer, activities privacy.ActivityControl, auctionPermissions gdpr.AuctionPermissions, coreBidder, bidderName openrtb_ext.BidderName) bool {
 	// activities control
 	scope := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName.String()}
-	fetchBidsActivityAllowed := activities.Allow(privacy.ActivityFetchBids, scope, privacy.NewRequestFromBidRequest(*r))
+	fetchBidsActivityAllowed := activities.Allow(privacy.ActivityFetchBids, scope, privacy.NewRequestFromBidRequest(*r).WithGPP(gpp))
 	if !fetchBidsActivityAllowed {
 		return true
 	}
@@ -364,14 +369,14 @@ func (rs *requestSplitter) isBidderBlockedByPrivacy(r *openrtb_ext.RequestWrappe
 	return false
 }
 
-func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper, coreBidderName openrtb_ext.BidderName, bidderName string, auctionReq AuctionRequest, auctionPermissions gdpr.AuctionPermissions, ccpaEnforcer privacy.PolicyEnforcer, lmt bool, coppa bool) error {
+func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper, gpp gpplib.GppContainer, coreBidderName openrtb_ext.BidderName, bidderName string, auctionReq AuctionRequest, auctionPermissions gdpr.AuctionPermissions, ccpaEnforcer privacy.PolicyEnforcer, lmt bool, coppa bool) error {
 	scope := privacy.Component{Type: privacy.ComponentTypeBidder, Name: bidderName}
 	ipConf := privacy.IPConf{IPV6: auctionReq.Account.Privacy.IPv6Config, IPV4: auctionReq.Account.Privacy.IPv4Config}
 
 	bidRequest := ortb.CloneBidRequestPartial(reqWrapper.BidRequest)
 	reqWrapper.BidRequest = bidRequest
 
-	passIDActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitUserFPD, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	passIDActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitUserFPD, scope, privacy.NewRequestFromBidRequest(*reqWrapper).WithGPP(gpp))
 	buyerUIDSet := reqWrapper.User != nil && reqWrapper.User.BuyerUID != ""
 	buyerUIDRemoved := false
 	if !passIDActivityAllowed {
@@ -392,7 +397,7 @@ func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper,
 		rs.me.RecordAdapterBuyerUIDScrubbed(coreBidderName)
 	}
 
-	passGeoActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitPreciseGeo, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	passGeoActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitPreciseGeo, scope, privacy.NewRequestFromBidRequest(*reqWrapper).WithGPP(gpp))
 	if !passGeoActivityAllowed {
 		privacy.ScrubGeoAndDeviceIP(reqWrapper, ipConf)
 	} else {
@@ -408,7 +413,7 @@ func (rs *requestSplitter) applyPrivacy(reqWrapper *openrtb_ext.RequestWrapper,
 		privacy.ScrubDeviceIDsIPsUserDemoExt(reqWrapper, ipConf, "eids", coppa)
 	}
 
-	passTIDAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitTIDs, scope, privacy.NewRequestFromBidRequest(*reqWrapper))
+	passTIDAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitTIDs, scope, privacy.NewRequestFromBidRequest(*reqWrapper).WithGPP(gpp))
 	if !passTIDAllowed {
 		privacy.ScrubTID(reqWrapper)
 	}
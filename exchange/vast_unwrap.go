@@ -0,0 +1,146 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/vastunwrap"
+)
+
+type vastUnwrapJob struct {
+	bidderName openrtb_ext.BidderName
+	bid        *entities.PbsOrtbBid
+	video      *openrtb2.Video
+	result     *vastunwrap.Result
+	err        error
+}
+
+// unwrapVAST resolves the VAST of the video bids, if the account opted in, and validates it against the imps.
+// The bids are resolved in parallel, within the host timeout and what's left of the auction's time. Bids
+// whose VAST is invalid get a warning, and are rejected if the account enforces it. Bids which couldn't be
+// resolved in time are kept as they are.
+func (e *exchange) unwrapVAST(ctx context.Context, account config.AccountVASTUnwrap, req *openrtb2.BidRequest, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, seatNonBidBuilder *SeatNonBidBuilder) []error {
+	if e.vastUnwrapper == nil || !account.Enabled {
+		return nil
+	}
+
+	jobs := vastUnwrapJobs(req, seatBids)
+	if len(jobs) == 0 {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return []error{&errortypes.Warning{
+			Message:     "VAST unwrapping skipped, the auction has no time left",
+			WarningCode: errortypes.VASTUnwrapWarningCode,
+		}}
+	}
+
+	unwrapCtx, cancel := context.WithTimeout(ctx, e.vastUnwrapper.Timeout())
+	defer cancel()
+	var wg sync.WaitGroup
+	for i := range jobs {
+		wg.Add(1)
+		go func(job *vastUnwrapJob) {
+			defer wg.Done()
+			job.result, job.err = e.vastUnwrapper.Unwrap(unwrapCtx, job.bid.Bid.AdM)
+			if job.err == nil {
+				job.err = vastunwrap.Validate(job.result, job.video)
+			}
+		}(&jobs[i])
+	}
+	wg.Wait()
+
+	var errs []error
+	rejected := make(map[*entities.PbsOrtbBid]struct{})
+	for _, job := range jobs {
+		if job.err == nil {
+			applyUnwrappedVAST(job.bid, job.result, account.Inline)
+			continue
+		}
+
+		if !errors.Is(job.err, vastunwrap.ErrInvalidVAST) {
+			errs = append(errs, &errortypes.Warning{
+				Message:     fmt.Sprintf("%s bid id %s VAST wasn't resolved: %v", job.bidderName, job.bid.Bid.ID, job.err),
+				WarningCode: errortypes.VASTUnwrapWarningCode,
+			})
+			continue
+		}
+		if !account.Enforce {
+			errs = append(errs, &errortypes.Warning{
+				Message:     fmt.Sprintf("%s bid id %s has %v", job.bidderName, job.bid.Bid.ID, job.err),
+				WarningCode: errortypes.VASTUnwrapWarningCode,
+			})
+			continue
+		}
+		errs = append(errs, &errortypes.Warning{
+			Message:     fmt.Sprintf("%s bid id %s rejected - %v", job.bidderName, job.bid.Bid.ID, job.err),
+			WarningCode: errortypes.VASTUnwrapWarningCode,
+		})
+		seatNonBidBuilder.rejectBid(job.bid, int(ResponseRejectedInvalidCreative), job.bidderName.String())
+		rejected[job.bid] = struct{}{}
+	}
+
+	if len(rejected) > 0 {
+		for _, seatBid := range seatBids {
+			kept := seatBid.Bids[:0]
+			for _, bid := range seatBid.Bids {
+				if _, ok := rejected[bid]; !ok {
+					kept = append(kept, bid)
+				}
+			}
+			seatBid.Bids = kept
+		}
+	}
+	return errs
+}
+
+// vastUnwrapJobs lists the video bids, ordered by bidder, along with the video of their imp.
+func vastUnwrapJobs(req *openrtb2.BidRequest, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) []vastUnwrapJob {
+	videos := make(map[string]*openrtb2.Video, len(req.Imp))
+	for i := range req.Imp {
+		videos[req.Imp[i].ID] = req.Imp[i].Video
+	}
+
+	bidderNames := make([]openrtb_ext.BidderName, 0, len(seatBids))
+	for bidderName := range seatBids {
+		bidderNames = append(bidderNames, bidderName)
+	}
+	sort.Slice(bidderNames, func(i, j int) bool { return bidderNames[i] < bidderNames[j] })
+
+	var jobs []vastUnwrapJob
+	for _, bidderName := range bidderNames {
+		for _, bid := range seatBids[bidderName].Bids {
+			if bid.BidType != openrtb_ext.BidTypeVideo {
+				continue
+			}
+			jobs = append(jobs, vastUnwrapJob{bidderName: bidderName, bid: bid, video: videos[bid.Bid.ImpID]})
+		}
+	}
+	return jobs
+}
+
+// applyUnwrappedVAST fills in the duration of the bid from its VAST if the bidder didn't set it, and sets the
+// inline VAST to cache in place of its adm if the account asked for it. The adm is returned as is, so that
+// the wrapper trackers the bidder relies on still fire when the response markup is rendered.
+func applyUnwrappedVAST(bid *entities.PbsOrtbBid, result *vastunwrap.Result, inline bool) {
+	if result.Duration > 0 {
+		if bid.BidVideo == nil {
+			bid.BidVideo = &openrtb_ext.ExtBidPrebidVideo{}
+		}
+		if bid.BidVideo.Duration == 0 {
+			bid.BidVideo.Duration = int(math.Round(result.Duration.Seconds()))
+		}
+	}
+	if inline && result.Wrappers > 0 {
+		bid.InlineVAST = result.VAST
+	}
+}
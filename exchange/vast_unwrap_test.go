@@ -0,0 +1,167 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/prebid/prebid-server/v3/exchange/entities"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/vastunwrap"
+	"github.com/stretchr/testify/assert"
+)
+
+const testInlineVAST = `<VAST version="3.0"><Ad><InLine><Creatives><Creative><Linear>` +
+	`<Duration>00:00:30</Duration>` +
+	`<MediaFiles><MediaFile type="video/mp4"><![CDATA[https://cdn.example.com/ad.mp4]]></MediaFile></MediaFiles>` +
+	`</Linear></Creative></Creatives></InLine></Ad></VAST>`
+
+func testWrapperVAST(tagURI string) string {
+	return `<VAST version="3.0"><Ad><Wrapper><VASTAdTagURI><![CDATA[` + tagURI + `]]></VASTAdTagURI></Wrapper></Ad></VAST>`
+}
+
+func TestUnwrapVAST(t *testing.T) {
+	var winNotices atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/win":
+			winNotices.Add(1)
+			w.Write([]byte(testInlineVAST))
+		case "/inline":
+			w.Write([]byte(testInlineVAST))
+		case "/broken":
+			w.Write([]byte("<div/>"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	e := &exchange{vastUnwrapper: vastunwrap.NewUnwrapperWithClient(config.VASTUnwrap{Enabled: true, MaxWrapperDepth: 3, TimeoutMs: 1000, MaxResponseBytes: 1024}, server.Client())}
+	req := &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+		{ID: "imp1", Video: &openrtb2.Video{MIMEs: []string{"video/mp4"}, MaxDuration: 30}},
+		{ID: "imp2", Video: &openrtb2.Video{MaxDuration: 15}},
+	}}
+
+	newSeatBids := func() map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid {
+		return map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			"appnexus": {Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "wrapped", ImpID: "imp1", AdM: testWrapperVAST(server.URL + "/inline")}, BidType: openrtb_ext.BidTypeVideo},
+				{Bid: &openrtb2.Bid{ID: "banner", ImpID: "imp1", AdM: "<div/>"}, BidType: openrtb_ext.BidTypeBanner},
+			}},
+			"rubicon": {Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "too-long", ImpID: "imp2", AdM: testInlineVAST}, BidType: openrtb_ext.BidTypeVideo},
+				{Bid: &openrtb2.Bid{ID: "missing", ImpID: "imp1", AdM: testWrapperVAST(server.URL + "/missing")}, BidType: openrtb_ext.BidTypeVideo},
+				{Bid: &openrtb2.Bid{ID: "broken", ImpID: "imp1", AdM: testWrapperVAST(server.URL + "/broken")}, BidType: openrtb_ext.BidTypeVideo},
+				{Bid: &openrtb2.Bid{ID: "nurl", ImpID: "imp1", NURL: server.URL + "/win"}, BidType: openrtb_ext.BidTypeVideo},
+			}},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		account          config.AccountVASTUnwrap
+		expectedBids     map[openrtb_ext.BidderName][]string
+		expectedWarnings []string
+		expectedNonBids  int
+		expectedInline   string
+	}{
+		{
+			name:    "account-disabled",
+			account: config.AccountVASTUnwrap{Enabled: false, Enforce: true},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"appnexus": {"wrapped", "banner"},
+				"rubicon":  {"too-long", "missing", "broken", "nurl"},
+			},
+		},
+		{
+			name:    "warn",
+			account: config.AccountVASTUnwrap{Enabled: true},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"appnexus": {"wrapped", "banner"},
+				"rubicon":  {"too-long", "missing", "broken", "nurl"},
+			},
+			expectedWarnings: []string{
+				"rubicon bid id too-long has invalid VAST: VAST duration 30s is longer than the imp maxduration 15s",
+				"rubicon bid id missing VAST wasn't resolved: GET " + server.URL + "/missing returned status 404",
+				"rubicon bid id broken has invalid VAST: VAST isn't valid XML: expected element type <VAST> but have <div>",
+				"rubicon bid id nurl VAST wasn't resolved: bid has no adm",
+			},
+		},
+		{
+			name:    "enforce-and-inline",
+			account: config.AccountVASTUnwrap{Enabled: true, Enforce: true, Inline: true},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"appnexus": {"wrapped", "banner"},
+				"rubicon":  {"missing", "nurl"},
+			},
+			expectedWarnings: []string{
+				"rubicon bid id too-long rejected - invalid VAST: VAST duration 30s is longer than the imp maxduration 15s",
+				"rubicon bid id missing VAST wasn't resolved: GET " + server.URL + "/missing returned status 404",
+				"rubicon bid id broken rejected - invalid VAST: VAST isn't valid XML: expected element type <VAST> but have <div>",
+				"rubicon bid id nurl VAST wasn't resolved: bid has no adm",
+			},
+			expectedNonBids: 2,
+			expectedInline:  testInlineVAST,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			seatBids := newSeatBids()
+			seatNonBidBuilder := SeatNonBidBuilder{}
+
+			errs := e.unwrapVAST(context.Background(), test.account, req, seatBids, &seatNonBidBuilder)
+
+			var warnings []string
+			for _, err := range errs {
+				assert.Equal(t, errortypes.VASTUnwrapWarningCode, errortypes.ReadCode(err))
+				warnings = append(warnings, err.Error())
+			}
+			assert.Equal(t, test.expectedWarnings, warnings)
+			for bidderName, expectedIDs := range test.expectedBids {
+				ids := []string{}
+				for _, bid := range seatBids[bidderName].Bids {
+					ids = append(ids, bid.Bid.ID)
+				}
+				assert.Equal(t, expectedIDs, ids, string(bidderName))
+			}
+			nonBids := 0
+			for _, seatNonBids := range seatNonBidBuilder {
+				for _, nonBid := range seatNonBids {
+					assert.Equal(t, int(ResponseRejectedInvalidCreative), nonBid.StatusCode)
+					nonBids++
+				}
+			}
+			assert.Equal(t, test.expectedNonBids, nonBids)
+
+			wrapped := seatBids["appnexus"].Bids[0]
+			assert.Equal(t, testWrapperVAST(server.URL+"/inline"), wrapped.Bid.AdM, "the adm is returned as is")
+			assert.Equal(t, test.expectedInline, wrapped.InlineVAST)
+			if test.account.Enabled {
+				assert.Equal(t, &openrtb_ext.ExtBidPrebidVideo{Duration: 30}, wrapped.BidVideo, "duration is filled in from the VAST")
+			}
+		})
+	}
+	assert.Zero(t, winNotices.Load(), "the nurl is the win notice, it must not be fetched")
+}
+
+func TestUnwrapVASTNoTimeLeft(t *testing.T) {
+	e := &exchange{vastUnwrapper: vastunwrap.NewUnwrapper(config.VASTUnwrap{Enabled: true, MaxWrapperDepth: 3, TimeoutMs: 1000, MaxResponseBytes: 1024})}
+	seatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", AdM: testInlineVAST}, BidType: openrtb_ext.BidTypeVideo}}},
+	}
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	errs := e.unwrapVAST(ctx, config.AccountVASTUnwrap{Enabled: true, Enforce: true}, &openrtb2.BidRequest{}, seatBids, &SeatNonBidBuilder{})
+
+	assert.Equal(t, []error{&errortypes.Warning{Message: "VAST unwrapping skipped, the auction has no time left", WarningCode: errortypes.VASTUnwrapWarningCode}}, errs)
+	assert.Len(t, seatBids["appnexus"].Bids, 1)
+}
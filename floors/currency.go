@@ -0,0 +1,59 @@
+package floors
+
+import (
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/errortypes"
+)
+
+// convertRuleFloor converts the value of a rule which has its own currency to the currency of the model group
+func convertRuleFloor(floorVal float64, ruleCur, floorCur string, conversions currency.Conversions) (float64, error) {
+	if ruleCur == "" || ruleCur == floorCur {
+		return floorVal, nil
+	}
+	rate, err := conversions.GetRate(ruleCur, floorCur)
+	if err != nil {
+		return 0, &errortypes.Warning{
+			Message:     fmt.Sprintf("Error in converting floor rule value from %s to %s : '%v'", ruleCur, floorCur, err.Error()),
+			WarningCode: errortypes.FloorCurrencyConversionWarningCode,
+		}
+	}
+	return roundToFourDecimals(rate * floorVal), nil
+}
+
+// ConvertToBidderCurrency converts imp.bidfloor and imp.bidfloorcur of a bidder's request to the currency the
+// bidder expects floors in. Floors which can't be converted are left unchanged, with a warning.
+func ConvertToBidderCurrency(request *openrtb2.BidRequest, bidderCurrency string, conversions currency.Conversions) []error {
+	if request == nil || bidderCurrency == "" {
+		return nil
+	}
+
+	var errs []error
+	for i := range request.Imp {
+		imp := &request.Imp[i]
+		if imp.BidFloor <= 0 {
+			continue
+		}
+		floorCur := imp.BidFloorCur
+		if floorCur == "" {
+			floorCur = defaultCurrency
+		}
+		if floorCur == bidderCurrency {
+			continue
+		}
+
+		rate, err := conversions.GetRate(floorCur, bidderCurrency)
+		if err != nil {
+			errs = append(errs, &errortypes.Warning{
+				Message:     fmt.Sprintf("Error in converting bid floor of impression id %s from %s to %s : '%v'", imp.ID, floorCur, bidderCurrency, err.Error()),
+				WarningCode: errortypes.FloorCurrencyConversionWarningCode,
+			})
+			continue
+		}
+		imp.BidFloor = roundToFourDecimals(rate * imp.BidFloor)
+		imp.BidFloorCur = bidderCurrency
+	}
+	return errs
+}
@@ -0,0 +1,119 @@
+package floors
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/errortypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertRuleFloor(t *testing.T) {
+	conversions := getCurrencyRates(map[string]map[string]float64{
+		"USD": {
+			"EUR": 0.8,
+		},
+	})
+
+	testCases := []struct {
+		name        string
+		floorVal    float64
+		ruleCur     string
+		floorCur    string
+		expFloorVal float64
+		expErr      bool
+	}{
+		{
+			name:        "No rule currency",
+			floorVal:    1.5,
+			floorCur:    "USD",
+			expFloorVal: 1.5,
+		},
+		{
+			name:        "Rule currency same as floor currency",
+			floorVal:    1.5,
+			ruleCur:     "USD",
+			floorCur:    "USD",
+			expFloorVal: 1.5,
+		},
+		{
+			name:        "Rule currency converted",
+			floorVal:    2,
+			ruleCur:     "EUR",
+			floorCur:    "USD",
+			expFloorVal: 2.5,
+		},
+		{
+			name:     "Rate not found",
+			floorVal: 2,
+			ruleCur:  "JPY",
+			floorCur: "USD",
+			expErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			floorVal, err := convertRuleFloor(tc.floorVal, tc.ruleCur, tc.floorCur, conversions)
+			if tc.expErr {
+				assert.Equal(t, errortypes.FloorCurrencyConversionWarningCode, errortypes.ReadCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expFloorVal, floorVal)
+		})
+	}
+}
+
+func TestConvertToBidderCurrency(t *testing.T) {
+	conversions := getCurrencyRates(map[string]map[string]float64{
+		"USD": {
+			"EUR": 0.8,
+		},
+	})
+
+	testCases := []struct {
+		name           string
+		bidderCurrency string
+		imps           []openrtb2.Imp
+		expImps        []openrtb2.Imp
+		expErrs        []error
+	}{
+		{
+			name:    "No bidder currency",
+			imps:    []openrtb2.Imp{{ID: "1", BidFloor: 1, BidFloorCur: "USD"}},
+			expImps: []openrtb2.Imp{{ID: "1", BidFloor: 1, BidFloorCur: "USD"}},
+		},
+		{
+			name:           "Floors converted",
+			bidderCurrency: "EUR",
+			imps:           []openrtb2.Imp{{ID: "1", BidFloor: 1, BidFloorCur: "USD"}, {ID: "2", BidFloor: 2}},
+			expImps:        []openrtb2.Imp{{ID: "1", BidFloor: 0.8, BidFloorCur: "EUR"}, {ID: "2", BidFloor: 1.6, BidFloorCur: "EUR"}},
+		},
+		{
+			name:           "Floors already in bidder currency or zero unchanged",
+			bidderCurrency: "EUR",
+			imps:           []openrtb2.Imp{{ID: "1", BidFloor: 1, BidFloorCur: "EUR"}, {ID: "2"}},
+			expImps:        []openrtb2.Imp{{ID: "1", BidFloor: 1, BidFloorCur: "EUR"}, {ID: "2"}},
+		},
+		{
+			name:           "Rate not found",
+			bidderCurrency: "EUR",
+			imps:           []openrtb2.Imp{{ID: "1", BidFloor: 100, BidFloorCur: "JPY"}, {ID: "2", BidFloor: 1, BidFloorCur: "USD"}},
+			expImps:        []openrtb2.Imp{{ID: "1", BidFloor: 100, BidFloorCur: "JPY"}, {ID: "2", BidFloor: 0.8, BidFloorCur: "EUR"}},
+			expErrs: []error{&errortypes.Warning{
+				Message:     "Error in converting bid floor of impression id 1 from JPY to EUR : 'Currency conversion rate not found: 'JPY' => 'EUR''",
+				WarningCode: errortypes.FloorCurrencyConversionWarningCode,
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{Imp: tc.imps}
+			errs := ConvertToBidderCurrency(request, tc.bidderCurrency, conversions)
+			assert.Equal(t, tc.expErrs, errs)
+			assert.Equal(t, tc.expImps, request.Imp)
+		})
+	}
+}
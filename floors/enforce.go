@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"slices"
+	"time"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/config"
@@ -12,8 +14,9 @@ import (
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 )
 
-// Enforce does floors enforcement for bids from all bidders based on floors provided in request, account level floors config
-func Enforce(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, account config.Account, conversions currency.Conversions) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, []error, []*entities.PbsOrtbSeatBid) {
+// Enforce does floors enforcement for bids from all bidders based on floors provided in request, account level floors config.
+// requestTime must be the time the floors were resolved at by EnrichWithPriceFloors.
+func Enforce(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, account config.Account, conversions currency.Conversions, requestTime time.Time) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, []error, []*entities.PbsOrtbSeatBid) {
 	rejectionErrs := []error{}
 
 	rejectedBids := []*entities.PbsOrtbSeatBid{}
@@ -27,10 +30,15 @@ func Enforce(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb
 		return seatBids, nil, rejectedBids
 	}
 
-	if isSignalingSkipped(requestExt) || !isValidImpBidFloorPresent(bidRequestWrapper.BidRequest.Imp) {
+	if isSignalingSkipped(requestExt) {
 		return seatBids, nil, rejectedBids
 	}
 
+	bidderFloors, bidderFloorErrs := resolveBidderFloors(bidRequestWrapper, requestExt, seatBids, conversions, requestTime)
+	if !isValidImpBidFloorPresent(bidRequestWrapper.BidRequest.Imp) && len(bidderFloors) == 0 {
+		return seatBids, bidderFloorErrs, rejectedBids
+	}
+
 	enforceFloors := isSatisfiedByEnforceRate(requestExt, account.PriceFloors.EnforceFloorsRate, rand.Intn)
 	if updateEnforcePBS(enforceFloors, requestExt) {
 		err := bidRequestWrapper.RebuildRequest()
@@ -38,12 +46,12 @@ func Enforce(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb
 			return seatBids, []error{err}, rejectedBids
 		}
 	}
-	updateBidExt(bidRequestWrapper, seatBids)
+	updateBidExt(bidRequestWrapper, seatBids, bidderFloors)
 	if enforceFloors {
 		enforceDealFloors := account.PriceFloors.EnforceDealFloors && getEnforceDealsFlag(requestExt)
-		seatBids, rejectionErrs, rejectedBids = enforceFloorToBids(bidRequestWrapper, seatBids, conversions, enforceDealFloors)
+		seatBids, rejectionErrs, rejectedBids = enforceFloorToBids(bidRequestWrapper, seatBids, conversions, enforceDealFloors, bidderFloors)
 	}
-	return seatBids, rejectionErrs, rejectedBids
+	return seatBids, append(rejectionErrs, bidderFloorErrs...), rejectedBids
 }
 
 // updateEnforcePBS updates prebid extension in request if enforcePBS needs to be updated
@@ -81,15 +89,89 @@ func updateEnforcePBS(enforceFloors bool, requestExt *openrtb_ext.RequestExt) bo
 	return updateReqExt
 }
 
+// bidderFloor is the floor of an impression for a single seat, matched by a rule keyed on the bidder
+type bidderFloor struct {
+	rule      string
+	ruleValue float64
+	value     float64
+	currency  string
+}
+
+// resolveBidderFloors matches the rules of the selected model group again for each seat when its schema has
+// the bidder field, as that field is a wildcard when imp.bidfloor is set. Impressions without a matching rule
+// for the seat keep their imp.bidfloor.
+func resolveBidderFloors(bidRequestWrapper *openrtb_ext.RequestWrapper, requestExt *openrtb_ext.RequestExt, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, conversions currency.Conversions, requestTime time.Time) (map[openrtb_ext.BidderName]map[string]bidderFloor, []error) {
+	floorsExt := getFloorsExt(requestExt)
+	if floorsExt == nil || floorsExt.Data == nil || len(floorsExt.Data.ModelGroups) == 0 {
+		return nil, nil
+	}
+	modelGroup := floorsExt.Data.ModelGroups[0]
+	if !slices.Contains(modelGroup.Schema.Fields, Bidder) || len(modelGroup.Values) == 0 {
+		return nil, nil
+	}
+	delimiter := modelGroup.Schema.Delimiter
+	if delimiter == "" {
+		delimiter = defaultDelimiter
+	}
+
+	var errs []error
+	bidderFloors := make(map[openrtb_ext.BidderName]map[string]bidderFloor)
+	for bidderName := range seatBids {
+		for _, imp := range bidRequestWrapper.GetImp() {
+			desiredRuleKey := createRuleKey(modelGroup.Schema, bidRequestWrapper, imp, requestTime, string(bidderName))
+			matchedRule, isRuleMatched := findRule(modelGroup.Values, delimiter, desiredRuleKey)
+			if !isRuleMatched {
+				continue
+			}
+
+			floorMinVal, floorCur, err := getMinFloorValue(floorsExt, imp, conversions)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			ruleVal, err := convertRuleFloor(modelGroup.Values[matchedRule], modelGroup.Currencies[matchedRule], floorCur, conversions)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			ruleVal = roundToFourDecimals(ruleVal)
+			floorVal := ruleVal
+			if floorMinVal > 0.0 && floorVal < floorMinVal {
+				floorVal = floorMinVal
+			}
+
+			if bidderFloors[bidderName] == nil {
+				bidderFloors[bidderName] = make(map[string]bidderFloor)
+			}
+			bidderFloors[bidderName][imp.ID] = bidderFloor{
+				rule:      matchedRule,
+				ruleValue: ruleVal,
+				value:     floorVal,
+				currency:  floorCur,
+			}
+		}
+	}
+	return bidderFloors, errs
+}
+
 // updateBidExt updates bid extension for floors related details
-func updateBidExt(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+func updateBidExt(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, bidderFloors map[openrtb_ext.BidderName]map[string]bidderFloor) {
 	impMap := make(map[string]*openrtb_ext.ImpWrapper, bidRequestWrapper.LenImp())
 	for _, imp := range bidRequestWrapper.GetImp() {
 		impMap[imp.ID] = imp
 	}
 
-	for _, seatBid := range seatBids {
+	for bidderName, seatBid := range seatBids {
 		for _, bid := range seatBid.Bids {
+			if floor, ok := bidderFloors[bidderName][bid.Bid.ImpID]; ok {
+				bid.BidFloors = &openrtb_ext.ExtBidPrebidFloors{
+					FloorRule:      floor.rule,
+					FloorRuleValue: floor.ruleValue,
+					FloorValue:     floor.value,
+					FloorCurrency:  floor.currency,
+				}
+				continue
+			}
 			reqImp, ok := impMap[bid.Bid.ImpID]
 			if ok {
 				updateBidExtWithFloors(reqImp, bid, reqImp.BidFloorCur)
@@ -100,7 +182,7 @@ func updateBidExt(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[op
 
 // enforceFloorToBids function does floors enforcement for each bid,
 // The bids returned by each partner below bid floor price are rejected and remaining eligible bids are considered for further processing
-func enforceFloorToBids(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, conversions currency.Conversions, enforceDealFloors bool) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, []error, []*entities.PbsOrtbSeatBid) {
+func enforceFloorToBids(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, conversions currency.Conversions, enforceDealFloors bool, bidderFloors map[openrtb_ext.BidderName]map[string]bidderFloor) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, []error, []*entities.PbsOrtbSeatBid) {
 	errs := []error{}
 	rejectedBids := []*entities.PbsOrtbSeatBid{}
 	impMap := make(map[string]*openrtb_ext.ImpWrapper, bidRequestWrapper.LenImp())
@@ -130,14 +212,23 @@ func enforceFloorToBids(bidRequestWrapper *openrtb_ext.RequestWrapper, seatBids
 					continue
 				}
 
-				rate, err := getCurrencyConversionRate(seatBid.Currency, reqImp.BidFloorCur, conversions)
+				floorVal, floorCur := reqImp.BidFloor, reqImp.BidFloorCur
+				if floor, ok := bidderFloors[bidderName][reqImp.ID]; ok {
+					floorVal, floorCur = floor.value, floor.currency
+				} else if floorVal <= 0 && len(bidderFloors) > 0 {
+					// only other seats have a floor for this impression
+					eligibleBids = append(eligibleBids, bid)
+					continue
+				}
+
+				rate, err := getCurrencyConversionRate(seatBid.Currency, floorCur, conversions)
 				if err != nil {
-					errs = append(errs, fmt.Errorf("error in rate conversion from = %s to %s with bidder %s for impression id %s and bid id %s error = %v", seatBid.Currency, reqImp.BidFloorCur, bidderName, bid.Bid.ImpID, bid.Bid.ID, err.Error()))
+					errs = append(errs, fmt.Errorf("error in rate conversion from = %s to %s with bidder %s for impression id %s and bid id %s error = %v", seatBid.Currency, floorCur, bidderName, bid.Bid.ImpID, bid.Bid.ID, err.Error()))
 					continue
 				}
 
 				bidPrice := rate * bid.Bid.Price
-				if (bidPrice + floorPrecision) < reqImp.BidFloor {
+				if (bidPrice + floorPrecision) < floorVal {
 					rejectedBid := &entities.PbsOrtbSeatBid{
 						Currency: seatBid.Currency,
 						Seat:     seatBid.Seat,
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/config"
@@ -13,6 +14,7 @@ import (
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/ptrutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type convert struct {
@@ -399,7 +401,7 @@ func TestEnforceFloorToBids(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		seatbids, errs, rejBids := enforceFloorToBids(tt.args.bidRequestWrapper, tt.args.seatBids, tt.args.conversions, tt.args.enforceDealFloors)
+		seatbids, errs, rejBids := enforceFloorToBids(tt.args.bidRequestWrapper, tt.args.seatBids, tt.args.conversions, tt.args.enforceDealFloors, nil)
 		assert.Equal(t, tt.expEligibleBids, seatbids, tt.name)
 		assert.Equal(t, tt.expErrs, errs, tt.name)
 		assert.Equal(t, tt.expRejectedBids, rejBids, tt.name)
@@ -722,7 +724,7 @@ func TestEnforce(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		actEligibleBids, actErrs, actRejecteBids := Enforce(tt.args.bidRequestWrapper, tt.args.seatBids, config.Account{PriceFloors: tt.args.priceFloorsCfg}, tt.args.conversions)
+		actEligibleBids, actErrs, actRejecteBids := Enforce(tt.args.bidRequestWrapper, tt.args.seatBids, config.Account{PriceFloors: tt.args.priceFloorsCfg}, tt.args.conversions, time.Now())
 		assert.Equal(t, tt.expErrs, actErrs, tt.name)
 		assert.ElementsMatch(t, tt.expRejectedBids, actRejecteBids, tt.name)
 
@@ -1222,3 +1224,69 @@ func TestUpdateEnforcePBS(t *testing.T) {
 		})
 	}
 }
+
+func TestEnforceBidderFloors(t *testing.T) {
+	seatBids := func() map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid {
+		return map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			"appnexus": {
+				Bids:     []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "appnexus-bid", Price: 3, ImpID: "imp-1"}}},
+				Seat:     "appnexus",
+				Currency: "USD",
+			},
+			"pubmatic": {
+				Bids:     []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "pubmatic-bid", Price: 3, ImpID: "imp-1"}}},
+				Seat:     "pubmatic",
+				Currency: "USD",
+			},
+		}
+	}
+	account := config.Account{PriceFloors: config.AccountPriceFloors{Enabled: true, EnforceFloorsRate: 100}}
+
+	t.Run("floor_per_seat", func(t *testing.T) {
+		request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, BidFloor: 2, BidFloorCur: "USD"}},
+			Ext: json.RawMessage(`{"prebid":{"floors":{"data":{"currency":"USD","modelgroups":[{"schema":{"fields":["mediaType","bidder"]},"values":{"banner|*":2,"banner|pubmatic":4}}]},"enforcement":{"enforcepbs":true}}}}`),
+		}}
+
+		eligible, errs, rejected := Enforce(request, seatBids(), account, convert{}, time.Now())
+
+		assert.Empty(t, errs)
+		require.Len(t, eligible["appnexus"].Bids, 1)
+		assert.Equal(t, &openrtb_ext.ExtBidPrebidFloors{FloorRule: "banner|*", FloorRuleValue: 2, FloorValue: 2, FloorCurrency: "USD"}, eligible["appnexus"].Bids[0].BidFloors)
+		assert.Empty(t, eligible["pubmatic"].Bids)
+		require.Len(t, rejected, 1)
+		assert.Equal(t, "pubmatic-bid", rejected[0].Bids[0].Bid.ID)
+		assert.Equal(t, &openrtb_ext.ExtBidPrebidFloors{FloorRule: "banner|pubmatic", FloorRuleValue: 4, FloorValue: 4, FloorCurrency: "USD"}, rejected[0].Bids[0].BidFloors)
+	})
+
+	t.Run("seat_floor_without_imp_floor", func(t *testing.T) {
+		request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}},
+			Ext: json.RawMessage(`{"prebid":{"floors":{"floormin":3.5,"data":{"currency":"USD","modelgroups":[{"schema":{"fields":["bidder"]},"values":{"appnexus":1}}]},"enforcement":{"enforcepbs":true}}}}`),
+		}}
+
+		eligible, errs, rejected := Enforce(request, seatBids(), account, convert{}, time.Now())
+
+		assert.Empty(t, errs)
+		assert.Empty(t, eligible["appnexus"].Bids, "floormin applies to the seat floor")
+		assert.Len(t, eligible["pubmatic"].Bids, 1)
+		require.Len(t, rejected, 1)
+		assert.Equal(t, "appnexus-bid", rejected[0].Bids[0].Bid.ID)
+	})
+
+	t.Run("seat_floor_at_request_time", func(t *testing.T) {
+		request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+			Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}, BidFloor: 2, BidFloorCur: "USD"}},
+			Ext: json.RawMessage(`{"prebid":{"floors":{"data":{"currency":"USD","modelgroups":[{"schema":{"fields":["dayOfWeek","bidder"]},"values":{"*|*":2,"monday|pubmatic":4}}]},"enforcement":{"enforcepbs":true}}}}`),
+		}}
+		monday := time.Date(2024, time.January, 1, 23, 59, 59, 0, time.UTC)
+
+		eligible, errs, rejected := Enforce(request, seatBids(), account, convert{}, monday)
+
+		assert.Empty(t, errs)
+		assert.Len(t, eligible["appnexus"].Bids, 1)
+		assert.Empty(t, eligible["pubmatic"].Bids, "the rules are matched at the request time, not at the time bids are enforced")
+		require.Len(t, rejected, 1)
+		assert.Equal(t, "pubmatic-bid", rejected[0].Bids[0].Bid.ID)
+	})
+}
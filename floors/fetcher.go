@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"math"
 	"net/http"
@@ -19,6 +20,7 @@ import (
 	"github.com/prebid/prebid-server/v3/metrics"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/util/timeutil"
+	"golang.org/x/text/currency"
 )
 
 var refetchCheckInterval = 300
@@ -329,6 +331,16 @@ func validateRules(config config.AccountFloorFetch, priceFloors *openrtb_ext.Pri
 		if modelGroup.Default < 0 {
 			return errors.New("modelGroup.Default should be greater than 0")
 		}
+
+		if _, err := time.LoadLocation(modelGroup.Schema.Timezone); err != nil {
+			return fmt.Errorf("modelGroup.schema.timezone %s is not a valid time zone", modelGroup.Schema.Timezone)
+		}
+
+		for rule, ruleCurrency := range modelGroup.Currencies {
+			if _, err := currency.ParseISO(ruleCurrency); err != nil {
+				return fmt.Errorf("modelGroup.currencies has an invalid currency %s for rule %s", ruleCurrency, rule)
+			}
+		}
 	}
 
 	return nil
@@ -423,6 +423,90 @@ func TestValidatePriceFloorRules(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Valid rule currencies",
+			args: args{
+				configs: config.AccountFloorFetch{
+					Enabled:       true,
+					URL:           testURL,
+					Timeout:       5,
+					MaxFileSizeKB: 20,
+					MaxRules:      2,
+					MaxAge:        20,
+					Period:        10,
+				},
+				priceFloors: &openrtb_ext.PriceFloorRules{
+					Data: &openrtb_ext.PriceFloorData{
+						ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+							Currency: "USD",
+							Values: map[string]float64{
+								"*|*|www.website.com": 15.01,
+								"*|*|*":               12.01,
+							},
+							Currencies: map[string]string{
+								"*|*|www.website.com": "EUR",
+							},
+						}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid rule currency",
+			args: args{
+				configs: config.AccountFloorFetch{
+					Enabled:       true,
+					URL:           testURL,
+					Timeout:       5,
+					MaxFileSizeKB: 20,
+					MaxRules:      1,
+					MaxAge:        20,
+					Period:        10,
+				},
+				priceFloors: &openrtb_ext.PriceFloorRules{
+					Data: &openrtb_ext.PriceFloorData{
+						ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+							Values: map[string]float64{
+								"*|*|www.website.com": 15.01,
+							},
+							Currencies: map[string]string{
+								"*|*|www.website.com": "EURO",
+							},
+						}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid schema timezone",
+			args: args{
+				configs: config.AccountFloorFetch{
+					Enabled:       true,
+					URL:           testURL,
+					Timeout:       5,
+					MaxFileSizeKB: 20,
+					MaxRules:      1,
+					MaxAge:        20,
+					Period:        10,
+				},
+				priceFloors: &openrtb_ext.PriceFloorRules{
+					Data: &openrtb_ext.PriceFloorData{
+						ModelGroups: []openrtb_ext.PriceFloorModelGroup{{
+							Schema: openrtb_ext.PriceFloorSchema{
+								Fields:   []string{"hourOfDay"},
+								Timezone: "Mars/Olympus_Mons",
+							},
+							Values: map[string]float64{
+								"22": 15.01,
+							},
+						}},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -5,6 +5,7 @@ import (
 	"math"
 	"math/rand"
 	"strings"
+	"time"
 
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
@@ -33,8 +34,9 @@ const (
 )
 
 // EnrichWithPriceFloors checks for floors enabled in account and request and selects floors data from dynamic fetched if present
-// else selects floors data from req.ext.prebid.floors and update request with selected floors details
-func EnrichWithPriceFloors(bidRequestWrapper *openrtb_ext.RequestWrapper, account config.Account, conversions currency.Conversions, priceFloorFetcher FloorFetcher) []error {
+// else selects floors data from req.ext.prebid.floors and update request with selected floors details.
+// The hourOfDay and dayOfWeek fields are evaluated at requestTime, which must be the one passed to Enforce.
+func EnrichWithPriceFloors(bidRequestWrapper *openrtb_ext.RequestWrapper, account config.Account, conversions currency.Conversions, priceFloorFetcher FloorFetcher, requestTime time.Time) []error {
 	if bidRequestWrapper == nil || bidRequestWrapper.BidRequest == nil {
 		return []error{errors.New("Empty bidrequest")}
 	}
@@ -45,13 +47,13 @@ func EnrichWithPriceFloors(bidRequestWrapper *openrtb_ext.RequestWrapper, accoun
 
 	floors, err := resolveFloors(account, bidRequestWrapper, conversions, priceFloorFetcher)
 
-	updateReqErrs := updateBidRequestWithFloors(floors, bidRequestWrapper, conversions)
+	updateReqErrs := updateBidRequestWithFloors(floors, bidRequestWrapper, conversions, requestTime)
 	updateFloorsInRequest(bidRequestWrapper, floors)
 	return append(err, updateReqErrs...)
 }
 
 // updateBidRequestWithFloors will update imp.bidfloor and imp.bidfloorcur based on rules matching
-func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, request *openrtb_ext.RequestWrapper, conversions currency.Conversions) []error {
+func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, request *openrtb_ext.RequestWrapper, conversions currency.Conversions, requestTime time.Time) []error {
 	var (
 		floorErrList []error
 		floorVal     float64
@@ -73,9 +75,10 @@ func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, requ
 	}
 
 	floorErrList = validateFloorRulesAndLowerValidRuleKey(modelGroup.Schema, modelGroup.Schema.Delimiter, modelGroup.Values)
+	floorErrList = append(floorErrList, validateRuleCurrenciesAndLowerRuleKey(modelGroup.Currencies)...)
 	if len(modelGroup.Values) > 0 {
 		for _, imp := range request.GetImp() {
-			desiredRuleKey := createRuleKey(modelGroup.Schema, request, imp)
+			desiredRuleKey := createRuleKey(modelGroup.Schema, request, imp, requestTime, "")
 			matchedRule, isRuleMatched := findRule(modelGroup.Values, modelGroup.Schema.Delimiter, desiredRuleKey)
 			floorVal = modelGroup.Default
 			if isRuleMatched {
@@ -88,6 +91,9 @@ func updateBidRequestWithFloors(extFloorRules *openrtb_ext.PriceFloorRules, requ
 			}
 
 			floorMinVal, floorCur, err := getMinFloorValue(extFloorRules, imp, conversions)
+			if err == nil && isRuleMatched {
+				floorVal, err = convertRuleFloor(floorVal, modelGroup.Currencies[matchedRule], floorCur, conversions)
+			}
 			if err == nil {
 				floorVal = roundToFourDecimals(floorVal)
 				bidFloor := floorVal
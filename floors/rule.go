@@ -5,7 +5,10 @@ import (
 	"math/bits"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/prebid/openrtb/v20/openrtb2"
@@ -26,6 +29,14 @@ const (
 	AdUnitCode          string = "adUnitCode"
 	Country             string = "country"
 	DeviceType          string = "deviceType"
+	Region              string = "region"
+	Metro               string = "metro"
+	OS                  string = "os"
+	Browser             string = "browser"
+	HourOfDay           string = "hourOfDay"
+	DayOfWeek           string = "dayOfWeek"
+	Deal                string = "deal"
+	Bidder              string = "bidder"
 	Tablet              string = "tablet"
 	Desktop             string = "desktop"
 	Phone               string = "phone"
@@ -191,8 +202,10 @@ func findRule(ruleValues map[string]float64, delimiter string, desiredRuleKey []
 	return "", false
 }
 
-// createRuleKey prepares rule keys based on schema dimension and values present in request
-func createRuleKey(floorSchema openrtb_ext.PriceFloorSchema, request *openrtb_ext.RequestWrapper, imp *openrtb_ext.ImpWrapper) []string {
+// createRuleKey prepares rule keys based on schema dimension and values present in request. The hourOfDay and
+// dayOfWeek fields are evaluated at requestTime, and the bidder field is only known once bids are enforced, so
+// it's a wildcard when bidder is empty
+func createRuleKey(floorSchema openrtb_ext.PriceFloorSchema, request *openrtb_ext.RequestWrapper, imp *openrtb_ext.ImpWrapper, requestTime time.Time, bidder string) []string {
 	var ruleKeys []string
 
 	for _, field := range floorSchema.Fields {
@@ -220,6 +233,24 @@ func createRuleKey(floorSchema openrtb_ext.PriceFloorSchema, request *openrtb_ex
 			value = getGptSlot(imp)
 		case AdUnitCode:
 			value = getAdUnitCode(imp)
+		case Region:
+			value = getDeviceRegion(request)
+		case Metro:
+			value = getDeviceMetro(request)
+		case OS:
+			value = getDeviceOS(request)
+		case Browser:
+			value = getBrowser(request)
+		case HourOfDay:
+			value = getHourOfDay(requestTime, floorSchema.Timezone)
+		case DayOfWeek:
+			value = getDayOfWeek(requestTime, floorSchema.Timezone)
+		case Deal:
+			value = getDealPresence(imp.Imp)
+		case Bidder:
+			if bidder != "" {
+				value = bidder
+			}
 		}
 		ruleKeys = append(ruleKeys, value)
 	}
@@ -251,6 +282,111 @@ func getDeviceCountry(request *openrtb_ext.RequestWrapper) string {
 	return value
 }
 
+// getDeviceRegion returns device region provided into request
+func getDeviceRegion(request *openrtb_ext.RequestWrapper) string {
+	if request.Device != nil && request.Device.Geo != nil && request.Device.Geo.Region != "" {
+		return request.Device.Geo.Region
+	}
+	return catchAll
+}
+
+// getDeviceMetro returns device metro code provided into request
+func getDeviceMetro(request *openrtb_ext.RequestWrapper) string {
+	if request.Device != nil && request.Device.Geo != nil && request.Device.Geo.Metro != "" {
+		return request.Device.Geo.Metro
+	}
+	return catchAll
+}
+
+// getDeviceOS returns device.os, or the platform from the structured user agent if it's not provided
+func getDeviceOS(request *openrtb_ext.RequestWrapper) string {
+	if request.Device == nil {
+		return catchAll
+	}
+	if request.Device.OS != "" {
+		return request.Device.OS
+	}
+	if request.Device.SUA != nil && request.Device.SUA.Platform != nil && request.Device.SUA.Platform.Brand != "" {
+		return request.Device.SUA.Platform.Brand
+	}
+	return catchAll
+}
+
+// browserFamilies maps the brands found in device.sua.browsers to browser families
+var browserFamilies = map[string]string{
+	"google chrome":    "chrome",
+	"chrome":           "chrome",
+	"microsoft edge":   "edge",
+	"edge":             "edge",
+	"opera":            "opera",
+	"samsung internet": "samsung",
+	"brave":            "brave",
+	"yandex":           "yandex",
+	"firefox":          "firefox",
+	"safari":           "safari",
+}
+
+// getBrowser returns the browser family from the structured user agent. The made up brands browsers add to
+// discourage sniffing are ignored, and Chromium is only used when no other brand is present.
+func getBrowser(request *openrtb_ext.RequestWrapper) string {
+	if request.Device == nil || request.Device.SUA == nil {
+		return catchAll
+	}
+	value := catchAll
+	for _, browser := range request.Device.SUA.Browsers {
+		brand := strings.ToLower(strings.TrimSpace(browser.Brand))
+		if brand == "" || (strings.Contains(brand, "not") && strings.Contains(brand, "brand")) {
+			continue
+		}
+		if brand == "chromium" {
+			value = brand
+			continue
+		}
+		if family, ok := browserFamilies[brand]; ok {
+			return family
+		}
+		return brand
+	}
+	return value
+}
+
+// timezones caches the locations of the schema time zones, as loading one reads the time zone database
+var timezones sync.Map
+
+// getLocation returns the location of the time zone, or UTC if it's empty or unknown
+func getLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+	if location, ok := timezones.Load(timezone); ok {
+		return location.(*time.Location)
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	timezones.Store(timezone, location)
+	return location
+}
+
+// getHourOfDay returns the hour, from 0 to 23, of the request time in the schema time zone
+func getHourOfDay(requestTime time.Time, timezone string) string {
+	return strconv.Itoa(requestTime.In(getLocation(timezone)).Hour())
+}
+
+// getDayOfWeek returns the lower case english name of the day of the request time in the schema time zone
+func getDayOfWeek(requestTime time.Time, timezone string) string {
+	return strings.ToLower(requestTime.In(getLocation(timezone)).Weekday().String())
+}
+
+// getDealPresence returns whether deals are offered for the impression
+func getDealPresence(imp *openrtb2.Imp) string {
+	if imp.PMP != nil && len(imp.PMP.Deals) > 0 {
+		return "true"
+	}
+	return "false"
+}
+
 // getMediaType returns media type for give impression
 func getMediaType(imp *openrtb2.Imp) string {
 	value := catchAll
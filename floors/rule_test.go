@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/currency"
@@ -507,7 +508,7 @@ func TestCreateRuleKeys(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			out := createRuleKey(tc.floorSchema, &openrtb_ext.RequestWrapper{BidRequest: tc.request}, &openrtb_ext.ImpWrapper{Imp: &tc.request.Imp[0]})
+			out := createRuleKey(tc.floorSchema, &openrtb_ext.RequestWrapper{BidRequest: tc.request}, &openrtb_ext.ImpWrapper{Imp: &tc.request.Imp[0]}, time.Now(), "")
 			assert.Equal(t, out, tc.out, tc.name)
 		})
 	}
@@ -1213,3 +1214,138 @@ func getIntPtr(v int) *int {
 func getInt64Ptr(v int64) *int64 {
 	return &v
 }
+
+func TestCreateRuleKeysExtendedDimensions(t *testing.T) {
+	// Monday 2024-01-01 03:30 UTC, which is Sunday 22:30 in New York
+	requestTime := time.Date(2024, 1, 1, 3, 30, 0, 0, time.UTC)
+	request := &openrtb2.BidRequest{
+		Device: &openrtb2.Device{
+			OS:  "iOS",
+			Geo: &openrtb2.Geo{Country: "USA", Region: "NY", Metro: "501"},
+			SUA: &openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{{Brand: "Not_A Brand"}, {Brand: "Chromium"}, {Brand: "Google Chrome"}},
+			},
+		},
+		Imp: []openrtb2.Imp{{ID: "1234", Banner: &openrtb2.Banner{}, PMP: &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "deal"}}}}},
+	}
+
+	testCases := []struct {
+		name        string
+		floorSchema openrtb_ext.PriceFloorSchema
+		bidder      string
+		out         []string
+	}{
+		{
+			name:        "geo_os_browser_deal",
+			floorSchema: openrtb_ext.PriceFloorSchema{Fields: []string{"region", "metro", "os", "browser", "deal"}},
+			out:         []string{"NY", "501", "iOS", "chrome", "true"},
+		},
+		{
+			name:        "time_in_utc",
+			floorSchema: openrtb_ext.PriceFloorSchema{Fields: []string{"hourOfDay", "dayOfWeek"}},
+			out:         []string{"3", "monday"},
+		},
+		{
+			name:        "time_in_publisher_timezone",
+			floorSchema: openrtb_ext.PriceFloorSchema{Fields: []string{"hourOfDay", "dayOfWeek"}, Timezone: "America/New_York"},
+			out:         []string{"22", "sunday"},
+		},
+		{
+			name:        "bidder_unknown_when_signaling",
+			floorSchema: openrtb_ext.PriceFloorSchema{Fields: []string{"mediaType", "bidder"}},
+			out:         []string{"banner", "*"},
+		},
+		{
+			name:        "bidder_when_enforcing",
+			floorSchema: openrtb_ext.PriceFloorSchema{Fields: []string{"mediaType", "bidder"}},
+			bidder:      "appnexus",
+			out:         []string{"banner", "appnexus"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := createRuleKey(tc.floorSchema, &openrtb_ext.RequestWrapper{BidRequest: request}, &openrtb_ext.ImpWrapper{Imp: &request.Imp[0]}, requestTime, tc.bidder)
+			assert.Equal(t, tc.out, out)
+		})
+	}
+}
+
+func TestGetDeviceOS(t *testing.T) {
+	tests := []struct {
+		name   string
+		device *openrtb2.Device
+		want   string
+	}{
+		{
+			name: "no_device",
+			want: "*",
+		},
+		{
+			name:   "device_os",
+			device: &openrtb2.Device{OS: "Android", SUA: &openrtb2.UserAgent{Platform: &openrtb2.BrandVersion{Brand: "Linux"}}},
+			want:   "Android",
+		},
+		{
+			name:   "sua_platform",
+			device: &openrtb2.Device{SUA: &openrtb2.UserAgent{Platform: &openrtb2.BrandVersion{Brand: "macOS"}}},
+			want:   "macOS",
+		},
+		{
+			name:   "unknown",
+			device: &openrtb2.Device{},
+			want:   "*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getDeviceOS(&openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: tt.device}})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetBrowser(t *testing.T) {
+	tests := []struct {
+		name     string
+		browsers []openrtb2.BrandVersion
+		want     string
+	}{
+		{
+			name: "no_browsers",
+			want: "*",
+		},
+		{
+			name:     "known_family",
+			browsers: []openrtb2.BrandVersion{{Brand: "Microsoft Edge"}, {Brand: "Chromium"}},
+			want:     "edge",
+		},
+		{
+			name:     "grease_and_chromium_skipped",
+			browsers: []openrtb2.BrandVersion{{Brand: "Not/A)Brand"}, {Brand: "Chromium"}, {Brand: "Opera"}},
+			want:     "opera",
+		},
+		{
+			name:     "chromium_only",
+			browsers: []openrtb2.BrandVersion{{Brand: "Chromium"}, {Brand: " Not A;Brand"}},
+			want:     "chromium",
+		},
+		{
+			name:     "unknown_brand_lower_cased",
+			browsers: []openrtb2.BrandVersion{{Brand: "Vivaldi"}},
+			want:     "vivaldi",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &openrtb2.BidRequest{Device: &openrtb2.Device{SUA: &openrtb2.UserAgent{Browsers: tt.browsers}}}
+			got := getBrowser(&openrtb_ext.RequestWrapper{BidRequest: request})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetDealPresence(t *testing.T) {
+	assert.Equal(t, "false", getDealPresence(&openrtb2.Imp{}))
+	assert.Equal(t, "false", getDealPresence(&openrtb2.Imp{PMP: &openrtb2.PMP{}}))
+	assert.Equal(t, "true", getDealPresence(&openrtb2.Imp{PMP: &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "deal"}}}}))
+}
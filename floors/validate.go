@@ -3,9 +3,11 @@ package floors
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"golang.org/x/text/currency"
 )
 
 var validSchemaDimensions = map[string]struct{}{
@@ -20,6 +22,14 @@ var validSchemaDimensions = map[string]struct{}{
 	AdUnitCode: {},
 	Country:    {},
 	DeviceType: {},
+	Region:     {},
+	Metro:      {},
+	OS:         {},
+	Browser:    {},
+	HourOfDay:  {},
+	DayOfWeek:  {},
+	Deal:       {},
+	Bidder:     {},
 }
 
 // validateSchemaDimensions validates schema dimesions given in floors JSON
@@ -32,6 +42,14 @@ func validateSchemaDimensions(fields []string) error {
 	return nil
 }
 
+// validateSchemaTimezone validates the time zone given in floors JSON, which must be an IANA time zone name
+func validateSchemaTimezone(timezone string) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("Invalid schema timezone provided = '%s'", timezone)
+	}
+	return nil
+}
+
 // validateFloorRulesAndLowerValidRuleKey validates rule keys for number of schema dimension fields and drops invalid rules.
 // It also lower case of rule if any charactor in a rule is upper
 func validateFloorRulesAndLowerValidRuleKey(schema openrtb_ext.PriceFloorSchema, delimiter string, ruleValues map[string]float64) []error {
@@ -53,6 +71,23 @@ func validateFloorRulesAndLowerValidRuleKey(schema openrtb_ext.PriceFloorSchema,
 	return errs
 }
 
+// validateRuleCurrenciesAndLowerRuleKey drops rule currencies which aren't ISO 4217 codes and lower cases their
+// rule keys, so they match the keys from validateFloorRulesAndLowerValidRuleKey
+func validateRuleCurrenciesAndLowerRuleKey(ruleCurrencies map[string]string) []error {
+	var errs []error
+	for key, cur := range ruleCurrencies {
+		unit, err := currency.ParseISO(cur)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Invalid Floor Rule Currency = '%s' for Floor Rule = '%s'", cur, key))
+			delete(ruleCurrencies, key)
+			continue
+		}
+		delete(ruleCurrencies, key)
+		ruleCurrencies[strings.ToLower(key)] = unit.String()
+	}
+	return errs
+}
+
 // validateFloorParams validates SchemaVersion, SkipRate and FloorMin
 func validateFloorParams(extFloorRules *openrtb_ext.PriceFloorRules) error {
 	if extFloorRules.Data != nil && extFloorRules.Data.FloorsSchemaVersion != 0 && extFloorRules.Data.FloorsSchemaVersion != 2 {
@@ -88,6 +123,11 @@ func selectValidFloorModelGroups(modelGroups []openrtb_ext.PriceFloorModelGroup,
 			continue
 		}
 
+		if err := validateSchemaTimezone(modelGroup.Schema.Timezone); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
 		if account.PriceFloors.MaxSchemaDims > 0 && len(modelGroup.Schema.Fields) > account.PriceFloors.MaxSchemaDims {
 			errs = append(errs, fmt.Errorf("Invalid Floor Model = '%v' due to number of schema fields = '%v' are greater than limit %v", modelGroup.ModelVersion, len(modelGroup.Schema.Fields), account.PriceFloors.MaxSchemaDims))
 			continue
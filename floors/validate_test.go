@@ -405,6 +405,51 @@ func TestValidateFloorRulesAndLowerValidRuleKey(t *testing.T) {
 	}
 }
 
+func TestValidateRuleCurrenciesAndLowerRuleKey(t *testing.T) {
+	tt := []struct {
+		name               string
+		ruleCurrencies     map[string]string
+		Err                []error
+		expectedCurrencies map[string]string
+	}{
+		{
+			name:               "No rule currencies",
+			ruleCurrencies:     nil,
+			expectedCurrencies: nil,
+		},
+		{
+			name: "Rule keys lowered and currencies normalized",
+			ruleCurrencies: map[string]string{
+				"BANNER|300x250|WWW.WEBSITE.COM": "eur",
+				"*|*|*":                          "JPY",
+			},
+			expectedCurrencies: map[string]string{
+				"banner|300x250|www.website.com": "EUR",
+				"*|*|*":                          "JPY",
+			},
+		},
+		{
+			name: "Invalid currency dropped",
+			ruleCurrencies: map[string]string{
+				"banner|*|*": "EURO",
+				"*|*|*":      "JPY",
+			},
+			Err: []error{fmt.Errorf("Invalid Floor Rule Currency = 'EURO' for Floor Rule = 'banner|*|*'")},
+			expectedCurrencies: map[string]string{
+				"*|*|*": "JPY",
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ErrList := validateRuleCurrenciesAndLowerRuleKey(tc.ruleCurrencies)
+			assert.Equal(t, tc.Err, ErrList, tc.name)
+			assert.Equal(t, tc.expectedCurrencies, tc.ruleCurrencies, tc.name)
+		})
+	}
+}
+
 func TestValidateSchemaDimensions(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -415,6 +460,10 @@ func TestValidateSchemaDimensions(t *testing.T) {
 			name:   "valid_fields",
 			fields: []string{"deviceType", "size"},
 		},
+		{
+			name:   "valid_extended_fields",
+			fields: []string{"region", "metro", "os", "browser", "hourOfDay", "dayOfWeek", "deal", "bidder"},
+		},
 		{
 			name:   "invalid_fields",
 			fields: []string{"deviceType", "dealType"},
@@ -428,3 +477,30 @@ func TestValidateSchemaDimensions(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSchemaTimezone(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		err      error
+	}{
+		{
+			name: "empty_timezone_is_utc",
+		},
+		{
+			name:     "valid_timezone",
+			timezone: "America/New_York",
+		},
+		{
+			name:     "invalid_timezone",
+			timezone: "Mars/Olympus_Mons",
+			err:      fmt.Errorf("Invalid schema timezone provided = 'Mars/Olympus_Mons'"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSchemaTimezone(tt.timezone)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
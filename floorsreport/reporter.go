@@ -0,0 +1,232 @@
+package floorsreport
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/metrics"
+)
+
+// ReportCurrency is the currency revenue is reported in.
+const ReportCurrency = "USD"
+
+// UntrackedModelVersion is the model version the metrics of untracked account and model version pairs are
+// recorded under, since model versions come from the request and would otherwise grow the metric labels unbounded.
+const UntrackedModelVersion = "other"
+
+// Reporter aggregates price floors outcomes per account and model version, so that floors models
+// picked by modelweight can be compared with each other.
+// A nil *Reporter is valid, and never records anything.
+type Reporter struct {
+	metricsEngine metrics.MetricsEngine
+	maxModels     int
+	now           func() time.Time
+
+	mu     sync.Mutex
+	since  time.Time
+	models map[modelKey]*modelStats
+}
+
+type modelKey struct {
+	account      string
+	modelVersion string
+}
+
+type modelStats struct {
+	requests     int64
+	skipped      int64
+	rejectedBids int64
+	winningBids  int64
+	revenue      float64
+	rules        map[string]*ruleStats
+}
+
+type ruleStats struct {
+	rejectedBids int64
+	winningBids  int64
+	revenue      float64
+}
+
+// NewReporter creates a reporter for the host config, or returns nil if floors reporting is disabled.
+func NewReporter(cfg config.PriceFloorsReporting, metricsEngine metrics.MetricsEngine) *Reporter {
+	if !cfg.Enabled {
+		return nil
+	}
+	return newReporter(cfg.MaxModels, metricsEngine, time.Now)
+}
+
+func newReporter(maxModels int, metricsEngine metrics.MetricsEngine, now func() time.Time) *Reporter {
+	return &Reporter{
+		metricsEngine: metricsEngine,
+		maxModels:     maxModels,
+		now:           now,
+		since:         now().UTC(),
+		models:        make(map[modelKey]*modelStats),
+	}
+}
+
+// Record adds the price floors outcome of one auction to the report. Winning bid prices are converted to
+// ReportCurrency using the auction's currency conversions, and bids which can't be converted add no revenue.
+// Once the report holds maxModels account and model version pairs, new pairs are no longer tracked and their
+// auctions are only counted in the metrics, under UntrackedModelVersion.
+func (r *Reporter) Record(accountID string, floors *analytics.FloorsObject, conversions currency.Conversions) {
+	if r == nil || floors == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := modelKey{account: accountID, modelVersion: floors.ModelVersion}
+	stats, ok := r.models[key]
+	if !ok {
+		if len(r.models) >= r.maxModels {
+			r.recordMetrics(UntrackedModelVersion, floors)
+			return
+		}
+		stats = &modelStats{rules: make(map[string]*ruleStats)}
+		r.models[key] = stats
+	}
+	r.recordMetrics(floors.ModelVersion, floors)
+
+	stats.requests++
+	if floors.Skipped {
+		stats.skipped++
+	}
+
+	for _, bid := range floors.RejectedBids {
+		stats.rejectedBids++
+		stats.rule(bid.Rule).rejectedBids++
+	}
+
+	for _, bid := range floors.WinningBids {
+		revenue := revenueOf(bid, conversions)
+		stats.winningBids++
+		stats.revenue += revenue
+		rule := stats.rule(bid.Rule)
+		rule.winningBids++
+		rule.revenue += revenue
+	}
+}
+
+func (r *Reporter) recordMetrics(modelVersion string, floors *analytics.FloorsObject) {
+	r.recordMetric(modelVersion, metrics.FloorsModelRequested, 1)
+	if floors.Skipped {
+		r.recordMetric(modelVersion, metrics.FloorsModelSkipped, 1)
+	}
+	r.recordMetric(modelVersion, metrics.FloorsModelRejected, len(floors.RejectedBids))
+	r.recordMetric(modelVersion, metrics.FloorsModelWon, len(floors.WinningBids))
+}
+
+func (r *Reporter) recordMetric(modelVersion string, outcome metrics.FloorsModelOutcome, count int) {
+	if r.metricsEngine != nil && count > 0 {
+		r.metricsEngine.RecordFloorsModelOutcome(modelVersion, outcome, count)
+	}
+}
+
+func (s *modelStats) rule(name string) *ruleStats {
+	if name == "" {
+		name = "*"
+	}
+	rule, ok := s.rules[name]
+	if !ok {
+		rule = &ruleStats{}
+		s.rules[name] = rule
+	}
+	return rule
+}
+
+// revenueOf is the amount paid for the impression won by the bid, since bid prices are CPMs.
+func revenueOf(bid analytics.FloorsBid, conversions currency.Conversions) float64 {
+	price := bid.Price
+	if bid.Currency != "" && bid.Currency != ReportCurrency {
+		if conversions == nil {
+			return 0
+		}
+		rate, err := conversions.GetRate(bid.Currency, ReportCurrency)
+		if err != nil {
+			return 0
+		}
+		price *= rate
+	}
+	return price / 1000
+}
+
+// Report returns the aggregated outcomes for the account, or for every account if accountID is empty.
+func (r *Reporter) Report(accountID string) Report {
+	if r == nil {
+		return Report{Models: []ModelReport{}}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := Report{
+		Since:    r.since,
+		Currency: ReportCurrency,
+		Models:   make([]ModelReport, 0, len(r.models)),
+	}
+	for key, stats := range r.models {
+		if accountID != "" && key.account != accountID {
+			continue
+		}
+		model := ModelReport{
+			Account:      key.account,
+			ModelVersion: key.modelVersion,
+			Requests:     stats.requests,
+			Skipped:      stats.skipped,
+			RejectedBids: stats.rejectedBids,
+			WinningBids:  stats.winningBids,
+			Revenue:      stats.revenue,
+			Rules:        make([]RuleReport, 0, len(stats.rules)),
+		}
+		for name, rule := range stats.rules {
+			model.Rules = append(model.Rules, RuleReport{
+				Rule:         name,
+				RejectedBids: rule.rejectedBids,
+				WinningBids:  rule.winningBids,
+				Revenue:      rule.revenue,
+			})
+		}
+		sort.Slice(model.Rules, func(i, j int) bool { return model.Rules[i].Rule < model.Rules[j].Rule })
+		report.Models = append(report.Models, model)
+	}
+	sort.Slice(report.Models, func(i, j int) bool {
+		if report.Models[i].Account != report.Models[j].Account {
+			return report.Models[i].Account < report.Models[j].Account
+		}
+		return report.Models[i].ModelVersion < report.Models[j].ModelVersion
+	})
+	return report
+}
+
+// Report is the JSON representation of the aggregated price floors outcomes.
+type Report struct {
+	Since    time.Time     `json:"since,omitempty"`
+	Currency string        `json:"currency,omitempty"`
+	Models   []ModelReport `json:"models"`
+}
+
+// ModelReport holds the outcomes of one floors model version within an account.
+type ModelReport struct {
+	Account      string       `json:"account"`
+	ModelVersion string       `json:"model_version"`
+	Requests     int64        `json:"requests"`
+	Skipped      int64        `json:"skipped"`
+	RejectedBids int64        `json:"rejected_bids"`
+	WinningBids  int64        `json:"winning_bids"`
+	Revenue      float64      `json:"revenue"`
+	Rules        []RuleReport `json:"rules"`
+}
+
+// RuleReport holds the bid outcomes of one floor rule of a model version.
+type RuleReport struct {
+	Rule         string  `json:"rule"`
+	RejectedBids int64   `json:"rejected_bids"`
+	WinningBids  int64   `json:"winning_bids"`
+	Revenue      float64 `json:"revenue"`
+}
@@ -0,0 +1,131 @@
+package floorsreport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/analytics"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/currency"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func testNow() time.Time {
+	return testTime
+}
+
+func TestNewReporter(t *testing.T) {
+	assert.Nil(t, NewReporter(config.PriceFloorsReporting{Enabled: false, MaxModels: 10}, &metrics.MetricsEngineMock{}))
+	assert.NotNil(t, NewReporter(config.PriceFloorsReporting{Enabled: true, MaxModels: 10}, &metrics.MetricsEngineMock{}))
+}
+
+func TestNilReporter(t *testing.T) {
+	var r *Reporter
+	r.Record("acct", &analytics.FloorsObject{ModelVersion: "model_a"}, nil)
+	assert.Equal(t, Report{Models: []ModelReport{}}, r.Report(""))
+}
+
+func TestRecord(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordFloorsModelOutcome", mock.Anything, mock.Anything, mock.Anything).Return()
+	r := newReporter(10, me, testNow)
+	conversions := currency.NewRates(map[string]map[string]float64{
+		"EUR": {"USD": 2},
+	})
+
+	r.Record("acct", &analytics.FloorsObject{
+		ModelVersion: "model_a",
+		Enforced:     true,
+		RejectedBids: []analytics.FloorsBid{
+			{Bidder: "appnexus", ImpID: "imp1", Rule: "banner|300x250", Floor: 1, FloorCur: "USD", Price: 0.5, Currency: "USD"},
+		},
+		WinningBids: []analytics.FloorsBid{
+			{Bidder: "rubicon", ImpID: "imp1", Rule: "banner|300x250", Floor: 1, FloorCur: "USD", Price: 2, Currency: "USD"},
+			{Bidder: "rubicon", ImpID: "imp2", Rule: "banner|*", Floor: 1, FloorCur: "USD", Price: 3, Currency: "EUR"},
+		},
+	}, conversions)
+	r.Record("acct", &analytics.FloorsObject{ModelVersion: "model_a", Skipped: true}, conversions)
+	r.Record("acct", &analytics.FloorsObject{
+		ModelVersion: "model_b",
+		WinningBids: []analytics.FloorsBid{
+			{Bidder: "rubicon", ImpID: "imp1", Price: 1, Currency: "JPY"},
+		},
+	}, conversions)
+	r.Record("other", nil, conversions)
+
+	expected := Report{
+		Since:    testTime,
+		Currency: "USD",
+		Models: []ModelReport{
+			{
+				Account:      "acct",
+				ModelVersion: "model_a",
+				Requests:     2,
+				Skipped:      1,
+				RejectedBids: 1,
+				WinningBids:  2,
+				Revenue:      0.008,
+				Rules: []RuleReport{
+					{Rule: "banner|*", WinningBids: 1, Revenue: 0.006},
+					{Rule: "banner|300x250", RejectedBids: 1, WinningBids: 1, Revenue: 0.002},
+				},
+			},
+			{
+				Account:      "acct",
+				ModelVersion: "model_b",
+				Requests:     1,
+				WinningBids:  1,
+				Rules: []RuleReport{
+					{Rule: "*", WinningBids: 1},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, r.Report(""))
+
+	me.AssertCalled(t, "RecordFloorsModelOutcome", "model_a", metrics.FloorsModelRequested, 1)
+	me.AssertCalled(t, "RecordFloorsModelOutcome", "model_a", metrics.FloorsModelSkipped, 1)
+	me.AssertCalled(t, "RecordFloorsModelOutcome", "model_a", metrics.FloorsModelRejected, 1)
+	me.AssertCalled(t, "RecordFloorsModelOutcome", "model_a", metrics.FloorsModelWon, 2)
+	me.AssertCalled(t, "RecordFloorsModelOutcome", "model_b", metrics.FloorsModelWon, 1)
+	me.AssertNotCalled(t, "RecordFloorsModelOutcome", "model_b", metrics.FloorsModelRejected, mock.Anything)
+}
+
+func TestRecordMaxModels(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordFloorsModelOutcome", mock.Anything, mock.Anything, mock.Anything).Return()
+	r := newReporter(1, me, testNow)
+
+	r.Record("acct", &analytics.FloorsObject{ModelVersion: "model_a"}, nil)
+	r.Record("acct", &analytics.FloorsObject{ModelVersion: "model_b", Skipped: true, RejectedBids: []analytics.FloorsBid{{Price: 1}}}, nil)
+	r.Record("acct", &analytics.FloorsObject{ModelVersion: "model_a"}, nil)
+
+	report := r.Report("")
+	if assert.Len(t, report.Models, 1) {
+		assert.Equal(t, "model_a", report.Models[0].ModelVersion)
+		assert.Equal(t, int64(2), report.Models[0].Requests)
+	}
+
+	me.AssertCalled(t, "RecordFloorsModelOutcome", "model_a", metrics.FloorsModelRequested, 1)
+	me.AssertCalled(t, "RecordFloorsModelOutcome", UntrackedModelVersion, metrics.FloorsModelRequested, 1)
+	me.AssertCalled(t, "RecordFloorsModelOutcome", UntrackedModelVersion, metrics.FloorsModelSkipped, 1)
+	me.AssertCalled(t, "RecordFloorsModelOutcome", UntrackedModelVersion, metrics.FloorsModelRejected, 1)
+	me.AssertNotCalled(t, "RecordFloorsModelOutcome", "model_b", mock.Anything, mock.Anything)
+}
+
+func TestReportForAccount(t *testing.T) {
+	r := newReporter(10, nil, testNow)
+
+	r.Record("acct1", &analytics.FloorsObject{ModelVersion: "model_a"}, nil)
+	r.Record("acct2", &analytics.FloorsObject{ModelVersion: "model_a"}, nil)
+
+	report := r.Report("acct2")
+	if assert.Len(t, report.Models, 1) {
+		assert.Equal(t, "acct2", report.Models[0].Account)
+	}
+	assert.Empty(t, r.Report("acct3").Models)
+}
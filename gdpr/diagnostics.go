@@ -0,0 +1,208 @@
+package gdpr
+
+import (
+	"context"
+
+	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/go-gdpr/vendorlist"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+const (
+	diagnosticsMaxPurpose        = 10
+	diagnosticsMaxSpecialFeature = 2
+)
+
+// Diagnostics traces how the TCF2 consent of a request is enforced for a set of bidders, to explain why a
+// bidder was blocked without reverse engineering the Permissions.
+type Diagnostics struct {
+	// InScope is true if GDPR applies to the request and is enabled for the channel.
+	InScope      bool                  `json:"in_scope"`
+	Signal       Signal                `json:"signal"`
+	Consent      *ConsentDiagnostics   `json:"consent,omitempty"`
+	ConsentError string                `json:"consent_error,omitempty"`
+	VendorList   VendorListDiagnostics `json:"vendor_list"`
+	Bidders      []BidderDiagnostics   `json:"bidders"`
+}
+
+// ConsentDiagnostics holds the fields of a parsed TCF2 consent string.
+type ConsentDiagnostics struct {
+	Version               uint8  `json:"version"`
+	PolicyVersion         uint8  `json:"policy_version"`
+	CMPID                 uint16 `json:"cmp_id"`
+	CMPVersion            uint16 `json:"cmp_version"`
+	VendorListVersion     uint16 `json:"vendor_list_version"`
+	PurposeOneTreatment   bool   `json:"purpose_one_treatment"`
+	PurposeConsents       []int  `json:"purpose_consents"`
+	PurposeLITransparency []int  `json:"purpose_li_transparency"`
+	SpecialFeatureOptIns  []int  `json:"special_feature_opt_ins"`
+}
+
+// VendorListDiagnostics reports the global vendor list used to compute legal basis.
+type VendorListDiagnostics struct {
+	SpecVersion uint16 `json:"spec_version"`
+	ListVersion uint16 `json:"list_version"`
+	Loaded      bool   `json:"loaded"`
+	Error       string `json:"error,omitempty"`
+}
+
+// BidderDiagnostics traces the TCF2 enforcement for a bidder.
+type BidderDiagnostics struct {
+	Bidder              string               `json:"bidder"`
+	VendorID            uint16               `json:"vendor_id"`
+	VendorIDKnown       bool                 `json:"vendor_id_known"`
+	InVendorList        bool                 `json:"in_vendor_list"`
+	VendorConsent       bool                 `json:"vendor_consent"`
+	VendorLegitInterest bool                 `json:"vendor_legit_interest"`
+	FeatureOneException bool                 `json:"feature_one_exception"`
+	Purposes            []PurposeDiagnostics `json:"purposes"`
+	SyncAllowed         bool                 `json:"sync_allowed"`
+	SyncError           string               `json:"sync_error,omitempty"`
+	AllowBidRequest     bool                 `json:"allow_bid_request"`
+	PassGeo             bool                 `json:"pass_geo"`
+	PassID              bool                 `json:"pass_id"`
+}
+
+// PurposeDiagnostics traces the enforcement of a purpose for a bidder.
+type PurposeDiagnostics struct {
+	Purpose int `json:"purpose"`
+	// Enforcer is the PurposeEnforcer which ran, either "full" or "basic".
+	Enforcer string `json:"enforcer"`
+	// Downgraded is true if full enforcement was downgraded to basic for a basic enforcement vendor.
+	Downgraded      bool `json:"downgraded"`
+	EnforcePurpose  bool `json:"enforce_purpose"`
+	EnforceVendors  bool `json:"enforce_vendors"`
+	VendorException bool `json:"vendor_exception"`
+	LegalBasis      bool `json:"legal_basis"`
+}
+
+// Diagnose traces the enforcement of the TCF2 consent of the request for each bidder. The final decisions are
+// made by the same Permissions which enforce auctions and syncs, so the trace never disagrees with them.
+func Diagnose(ctx context.Context, cfg config.GDPR, tcf2Cfg TCF2ConfigReader, vendorIDs map[openrtb_ext.BidderName]uint16, fetcher VendorListFetcher, requestInfo RequestInfo, channel config.ChannelType, bidders []openrtb_ext.BidderName) Diagnostics {
+	diagnostics := Diagnostics{
+		Signal:  SignalNormalize(requestInfo.GDPRSignal, cfg.DefaultValue),
+		Bidders: make([]BidderDiagnostics, 0, len(bidders)),
+	}
+	diagnostics.InScope = cfg.Enabled && diagnostics.Signal == SignalYes && tcf2Cfg.ChannelEnabled(channel)
+
+	var permissions Permissions = &AlwaysAllow{}
+	if diagnostics.InScope {
+		permissions = NewPermissions(cfg, tcf2Cfg, vendorIDs, fetcher, NewPurposeEnforcerBuilder(tcf2Cfg), requestInfo)
+	}
+
+	var pc *parsedConsent
+	if requestInfo.Consent != "" {
+		var err error
+		if pc, err = parseConsent(requestInfo.Consent); err != nil {
+			diagnostics.ConsentError = err.Error()
+		} else {
+			diagnostics.Consent = newConsentDiagnostics(pc)
+		}
+	}
+
+	var vendorList vendorlist.VendorList
+	if pc != nil {
+		diagnostics.VendorList.SpecVersion = pc.specVersion
+		diagnostics.VendorList.ListVersion = pc.listVersion
+		if list, err := fetcher(ctx, pc.specVersion, pc.listVersion); err != nil {
+			diagnostics.VendorList.Error = err.Error()
+		} else {
+			diagnostics.VendorList.Loaded = true
+			vendorList = list
+		}
+	}
+
+	purposeEnforcerBuilder := NewPurposeEnforcerBuilder(tcf2Cfg)
+	for _, bidder := range bidders {
+		bidderDiagnostics := BidderDiagnostics{Bidder: bidder.String()}
+		bidderDiagnostics.VendorID, bidderDiagnostics.VendorIDKnown = vendorIDs[bidder]
+		bidderDiagnostics.FeatureOneException = tcf2Cfg.FeatureOneVendorException(bidder)
+
+		if pc != nil {
+			vendorInfo := VendorInfo{vendorID: bidderDiagnostics.VendorID}
+			if vendorList != nil {
+				vendorInfo.vendor = vendorList.Vendor(bidderDiagnostics.VendorID)
+			}
+			bidderDiagnostics.InVendorList = vendorInfo.vendor != nil
+			bidderDiagnostics.VendorConsent = pc.consentMeta.VendorConsent(bidderDiagnostics.VendorID)
+			bidderDiagnostics.VendorLegitInterest = pc.consentMeta.VendorLegitInterest(bidderDiagnostics.VendorID)
+			bidderDiagnostics.Purposes = diagnosePurposes(tcf2Cfg, purposeEnforcerBuilder, bidder, vendorInfo, pc)
+		}
+
+		syncAllowed, err := permissions.BidderSyncAllowed(ctx, bidder)
+		bidderDiagnostics.SyncAllowed = syncAllowed
+		if err != nil {
+			bidderDiagnostics.SyncError = err.Error()
+		}
+
+		auctionPermissions := permissions.AuctionActivitiesAllowed(ctx, bidder, bidder)
+		bidderDiagnostics.AllowBidRequest = auctionPermissions.AllowBidRequest
+		bidderDiagnostics.PassGeo = auctionPermissions.PassGeo
+		bidderDiagnostics.PassID = auctionPermissions.PassID
+
+		diagnostics.Bidders = append(diagnostics.Bidders, bidderDiagnostics)
+	}
+
+	return diagnostics
+}
+
+func newConsentDiagnostics(pc *parsedConsent) *ConsentDiagnostics {
+	consent := &ConsentDiagnostics{
+		Version:               pc.encodingVersion,
+		PolicyVersion:         pc.consentMeta.TCFPolicyVersion(),
+		CMPID:                 pc.consentMeta.CmpID(),
+		CMPVersion:            pc.consentMeta.CmpVersion(),
+		VendorListVersion:     pc.listVersion,
+		PurposeOneTreatment:   pc.consentMeta.PurposeOneTreatment(),
+		PurposeConsents:       []int{},
+		PurposeLITransparency: []int{},
+		SpecialFeatureOptIns:  []int{},
+	}
+	for i := 1; i <= diagnosticsMaxPurpose; i++ {
+		if pc.consentMeta.PurposeAllowed(consentconstants.Purpose(i)) {
+			consent.PurposeConsents = append(consent.PurposeConsents, i)
+		}
+		if pc.consentMeta.PurposeLITransparency(consentconstants.Purpose(i)) {
+			consent.PurposeLITransparency = append(consent.PurposeLITransparency, i)
+		}
+	}
+	for i := 1; i <= diagnosticsMaxSpecialFeature; i++ {
+		if pc.consentMeta.SpecialFeatureOptIn(uint16(i)) {
+			consent.SpecialFeatureOptIns = append(consent.SpecialFeatureOptIns, i)
+		}
+	}
+	return consent
+}
+
+// diagnosePurposes computes the legal basis of each purpose for the bidder, with the enforcer and overrides
+// the auction uses to decide whether to send the bidder a request.
+func diagnosePurposes(tcf2Cfg TCF2ConfigReader, purposeEnforcerBuilder PurposeEnforcerBuilder, bidder openrtb_ext.BidderName, vendorInfo VendorInfo, pc *parsedConsent) []PurposeDiagnostics {
+	_, basicEnforcementVendor := tcf2Cfg.BasicEnforcementVendors()[bidder.String()]
+
+	purposes := make([]PurposeDiagnostics, 0, diagnosticsMaxPurpose)
+	for i := 1; i <= diagnosticsMaxPurpose; i++ {
+		purpose := consentconstants.Purpose(i)
+		enforcer := purposeEnforcerBuilder(purpose, bidder.String())
+		_, vendorException := tcf2Cfg.PurposeVendorExceptions(purpose)[bidder.String()]
+
+		purposeDiagnostics := PurposeDiagnostics{
+			Purpose:         i,
+			Enforcer:        config.TCF2EnforceAlgoFull,
+			Downgraded:      purpose != consentconstants.Purpose(1) && isDowngraded(tcf2Cfg.PurposeEnforcementAlgo(purpose), basicEnforcementVendor),
+			EnforcePurpose:  tcf2Cfg.PurposeEnforced(purpose),
+			EnforceVendors:  tcf2Cfg.PurposeEnforcingVendors(purpose),
+			VendorException: vendorException,
+		}
+
+		overrides := Overrides{}
+		if _, ok := enforcer.(*BasicEnforcement); ok {
+			purposeDiagnostics.Enforcer = config.TCF2EnforceAlgoBasic
+			overrides.allowLITransparency = purpose == consentconstants.Purpose(2)
+		}
+		purposeDiagnostics.LegalBasis = enforcer.LegalBasis(vendorInfo, bidder.String(), pc.consentMeta, overrides)
+
+		purposes = append(purposes, purposeDiagnostics)
+	}
+	return purposes
+}
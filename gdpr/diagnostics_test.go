@@ -0,0 +1,113 @@
+package gdpr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/go-gdpr/vendorlist"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnose(t *testing.T) {
+	// full consents to purposes and vendors 2, 6, 8 and special feature 1 opt-in
+	const consent = "COzTVhaOzTVhaGvAAAENAiCIAP_AAH_AAAAAAEEUACCKAAA"
+
+	vendorIDs := map[openrtb_ext.BidderName]uint16{
+		openrtb_ext.BidderAppnexus: 2,
+		openrtb_ext.BidderPubmatic: 6,
+		openrtb_ext.BidderRubicon:  8,
+	}
+	fetcher := listFetcher(map[uint16]map[uint16]vendorlist.VendorList{
+		2: {34: parseVendorListDataV2(t, MarshalVendorList(buildVendorList34()))},
+	})
+	gdprCfg := config.GDPR{Enabled: true, DefaultValue: "0"}
+	bidders := []openrtb_ext.BidderName{openrtb_ext.BidderPubmatic, openrtb_ext.BidderRubicon}
+
+	t.Run("in-scope", func(t *testing.T) {
+		tcf2Cfg := allPurposesEnabledTCF2Config()
+		tcf2Cfg.AccountConfig.BasicEnforcementVendorsMap = map[string]struct{}{string(openrtb_ext.BidderRubicon): {}}
+
+		diagnostics := Diagnose(context.Background(), gdprCfg, &tcf2Cfg, vendorIDs, fetcher, RequestInfo{Consent: consent, GDPRSignal: SignalYes}, config.ChannelWeb, bidders)
+
+		assert.True(t, diagnostics.InScope)
+		require.NotNil(t, diagnostics.Consent)
+		assert.Equal(t, uint8(2), diagnostics.Consent.Version)
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, diagnostics.Consent.PurposeConsents)
+		assert.Equal(t, []int{1}, diagnostics.Consent.SpecialFeatureOptIns)
+		assert.Equal(t, VendorListDiagnostics{SpecVersion: 2, ListVersion: 34, Loaded: true}, diagnostics.VendorList)
+		require.Len(t, diagnostics.Bidders, 2)
+
+		pubmatic := diagnostics.Bidders[0]
+		assert.Equal(t, "pubmatic", pubmatic.Bidder)
+		assert.Equal(t, uint16(6), pubmatic.VendorID)
+		assert.True(t, pubmatic.InVendorList)
+		assert.True(t, pubmatic.VendorConsent)
+		assert.True(t, pubmatic.SyncAllowed)
+		assert.True(t, pubmatic.AllowBidRequest)
+		assert.True(t, pubmatic.PassGeo)
+		assert.True(t, pubmatic.PassID)
+		require.Len(t, pubmatic.Purposes, 10)
+		assert.Equal(t, PurposeDiagnostics{Purpose: 2, Enforcer: "full", EnforcePurpose: true, EnforceVendors: true, LegalBasis: true}, pubmatic.Purposes[1])
+		assert.Equal(t, PurposeDiagnostics{Purpose: 3, Enforcer: "full", EnforcePurpose: true, EnforceVendors: true, LegalBasis: false}, pubmatic.Purposes[2])
+
+		rubicon := diagnostics.Bidders[1]
+		assert.Equal(t, "rubicon", rubicon.Bidder)
+		assert.Equal(t, PurposeDiagnostics{Purpose: 1, Enforcer: "full", EnforcePurpose: true, EnforceVendors: true, LegalBasis: true}, rubicon.Purposes[0])
+		assert.Equal(t, PurposeDiagnostics{Purpose: 3, Enforcer: "basic", Downgraded: true, EnforcePurpose: true, EnforceVendors: true, LegalBasis: true}, rubicon.Purposes[2])
+		assert.True(t, rubicon.PassGeo, "basic enforcement vendors don't need to claim special feature 1")
+	})
+
+	t.Run("vendor-exception", func(t *testing.T) {
+		tcf2Cfg := allPurposesEnabledTCF2Config()
+		tcf2Cfg.HostConfig.PurposeConfigs[consentconstants.Purpose(3)].VendorExceptionMap = map[string]struct{}{string(openrtb_ext.BidderPubmatic): {}}
+
+		diagnostics := Diagnose(context.Background(), gdprCfg, &tcf2Cfg, vendorIDs, fetcher, RequestInfo{Consent: consent, GDPRSignal: SignalYes}, config.ChannelWeb, bidders[:1])
+
+		require.Len(t, diagnostics.Bidders, 1)
+		assert.Equal(t, PurposeDiagnostics{Purpose: 3, Enforcer: "full", EnforcePurpose: true, EnforceVendors: true, VendorException: true, LegalBasis: true}, diagnostics.Bidders[0].Purposes[2])
+	})
+
+	t.Run("malformed-consent", func(t *testing.T) {
+		tcf2Cfg := allPurposesEnabledTCF2Config()
+
+		diagnostics := Diagnose(context.Background(), gdprCfg, &tcf2Cfg, vendorIDs, fetcher, RequestInfo{Consent: "malformed", GDPRSignal: SignalYes}, config.ChannelWeb, bidders[:1])
+
+		assert.True(t, diagnostics.InScope)
+		assert.Nil(t, diagnostics.Consent)
+		assert.NotEmpty(t, diagnostics.ConsentError)
+		require.Len(t, diagnostics.Bidders, 1)
+		assert.Nil(t, diagnostics.Bidders[0].Purposes)
+		assert.False(t, diagnostics.Bidders[0].SyncAllowed)
+		assert.NotEmpty(t, diagnostics.Bidders[0].SyncError)
+		assert.False(t, diagnostics.Bidders[0].AllowBidRequest)
+	})
+
+	t.Run("vendor-list-unavailable", func(t *testing.T) {
+		tcf2Cfg := allPurposesEnabledTCF2Config()
+
+		diagnostics := Diagnose(context.Background(), gdprCfg, &tcf2Cfg, vendorIDs, failedListFetcher, RequestInfo{Consent: consent, GDPRSignal: SignalYes}, config.ChannelWeb, bidders[:1])
+
+		assert.False(t, diagnostics.VendorList.Loaded)
+		assert.Equal(t, "vendor list can't be fetched", diagnostics.VendorList.Error)
+		require.Len(t, diagnostics.Bidders, 1)
+		assert.False(t, diagnostics.Bidders[0].InVendorList)
+		assert.False(t, diagnostics.Bidders[0].AllowBidRequest)
+	})
+
+	t.Run("out-of-scope", func(t *testing.T) {
+		tcf2Cfg := allPurposesEnabledTCF2Config()
+
+		diagnostics := Diagnose(context.Background(), gdprCfg, &tcf2Cfg, vendorIDs, fetcher, RequestInfo{GDPRSignal: SignalNo}, config.ChannelWeb, bidders[:1])
+
+		assert.False(t, diagnostics.InScope)
+		require.Len(t, diagnostics.Bidders, 1)
+		assert.True(t, diagnostics.Bidders[0].SyncAllowed)
+		assert.True(t, diagnostics.Bidders[0].AllowBidRequest)
+		assert.True(t, diagnostics.Bidders[0].PassGeo)
+		assert.True(t, diagnostics.Bidders[0].PassID)
+	})
+}
@@ -50,6 +50,7 @@ func NewPermissions(cfg config.GDPR, tcf2Config TCF2ConfigReader, vendorIDs map[
 
 	permissionsImpl := &permissionsImpl{
 		fetchVendorList:        fetcher,
+		failClosed:             cfg.VendorLists.FailClosed,
 		gdprDefaultValue:       cfg.DefaultValue,
 		hostVendorID:           cfg.HostVendorID,
 		nonStandardPublishers:  cfg.NonStandardPublisherMap,
@@ -17,6 +17,7 @@ const noBidder openrtb_ext.BidderName = ""
 type permissionsImpl struct {
 	// global
 	fetchVendorList        VendorListFetcher
+	failClosed             bool
 	gdprDefaultValue       string
 	hostVendorID           int
 	nonStandardPublishers  map[string]struct{}
@@ -77,6 +78,10 @@ func (p *permissionsImpl) AuctionActivitiesAllowed(ctx context.Context, bidderCo
 	vendorID, _ := p.resolveVendorID(bidderCoreName, bidder)
 	vendor, err := p.getVendor(ctx, vendorID, *pc)
 	if err != nil {
+		// the vendor list of the consent string isn't loaded
+		if p.failClosed {
+			return AuctionPermissions{}
+		}
 		return p.defaultPermissions()
 	}
 
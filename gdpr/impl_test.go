@@ -1320,6 +1320,46 @@ func TestDefaultPermissions(t *testing.T) {
 	}
 }
 
+func TestAllowActivitiesVendorListMissing(t *testing.T) {
+	vendor2AndPurpose2Consent := "CPGWbY_PGWbY_GYAAAENABCAAEAAAAAAAAAAACEAAAAA"
+
+	tests := []struct {
+		description     string
+		failClosed      bool
+		wantPermissions AuctionPermissions
+	}{
+		{
+			description:     "Default permissions",
+			wantPermissions: AuctionPermissions{AllowBidRequest: true, PassGeo: true, PassID: false},
+		},
+		{
+			description:     "Fail closed",
+			failClosed:      true,
+			wantPermissions: AuctionPermissions{},
+		},
+	}
+
+	for _, tt := range tests {
+		tcf2AggConfig := allPurposesEnabledTCF2Config()
+		tcf2AggConfig.HostConfig.Purpose2.EnforcePurpose = false
+		tcf2AggConfig.HostConfig.SpecialFeature1.Enforce = false
+		tcf2AggConfig.HostConfig.PurposeConfigs[consentconstants.Purpose(2)] = &tcf2AggConfig.HostConfig.Purpose2
+
+		perms := permissionsImpl{
+			cfg:             &tcf2AggConfig,
+			fetchVendorList: failedListFetcher,
+			failClosed:      tt.failClosed,
+			vendorIDs:       map[openrtb_ext.BidderName]uint16{openrtb_ext.BidderAppnexus: 2},
+			gdprSignal:      SignalYes,
+			consent:         vendor2AndPurpose2Consent,
+		}
+
+		result := perms.AuctionActivitiesAllowed(context.Background(), openrtb_ext.BidderAppnexus, openrtb_ext.BidderAppnexus)
+
+		assert.Equal(t, tt.wantPermissions, result, tt.description)
+	}
+}
+
 func TestVendorListSelection(t *testing.T) {
 	policyVersion3WithVendor2AndPurpose1Consent := "CPGWbY_PGWbY_GYAAAENABDAAIAAAAAAAAAAACEAAAAA"
 	policyVersion4WithVendor2AndPurpose1Consent := "CPGWbY_PGWbY_GYAAAENABEAAIAAAAAAAAAAACEAAAAA"
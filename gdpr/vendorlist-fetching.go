@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -15,10 +16,12 @@ import (
 	"github.com/prebid/go-gdpr/vendorlist"
 	"github.com/prebid/go-gdpr/vendorlist2"
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/metrics"
 	"golang.org/x/net/context/ctxhttp"
 )
 
-type saveVendors func(uint16, uint16, api.VendorList)
+// saveVendors saves a parsed vendor list along with the JSON it was parsed from.
+type saveVendors func(specVersion uint16, listVersion uint16, list api.VendorList, data []byte)
 type VendorListFetcher func(ctx context.Context, specVersion uint16, listVersion uint16) (vendorlist.VendorList, error)
 
 // This file provides the vendorlist-fetching function for Prebid Server.
@@ -27,12 +30,37 @@ type VendorListFetcher func(ctx context.Context, specVersion uint16, listVersion
 //
 // Nothing in this file is exported. Public APIs can be found in gdpr.go
 
-func NewVendorListFetcher(initCtx context.Context, cfg config.GDPR, client *http.Client, urlMaker func(uint16, uint16) string) VendorListFetcher {
+func NewVendorListFetcher(initCtx context.Context, cfg config.GDPR, client *http.Client, urlMaker func(uint16, uint16) string, me metrics.MetricsEngine) VendorListFetcher {
 	cacheSave, cacheLoad := newVendorListCache()
+	store := vendorListStore{dir: cfg.VendorLists.Dir}
+	versions := newVendorListVersions(me)
+
+	save := func(specVersion, listVersion uint16, list api.VendorList, data []byte) {
+		cacheSave(specVersion, listVersion, list)
+		store.save(specVersion, listVersion, data)
+		versions.record(specVersion, listVersion, data)
+	}
+	isLoaded := func(specVersion, listVersion uint16) bool {
+		return cacheLoad(specVersion, listVersion) != nil
+	}
+
+	// The lists persisted by a previous run don't need to be fetched again
+	if cfg.VendorLists.Dir != "" {
+		loadVendorLists(os.DirFS(cfg.VendorLists.Dir), save)
+	}
 
 	preloadContext, cancel := context.WithTimeout(initCtx, cfg.Timeouts.InitTimeout())
 	defer cancel()
-	preloadCache(preloadContext, client, urlMaker, cacheSave)
+	preloadCache(preloadContext, client, urlMaker, save, isLoaded)
+
+	// The snapshot bundled in the binary is a fallback for the lists which couldn't be loaded otherwise
+	if cfg.VendorLists.Snapshot {
+		loadVendorLists(vendorListSnapshot, func(specVersion, listVersion uint16, list api.VendorList, data []byte) {
+			if !isLoaded(specVersion, listVersion) {
+				save(specVersion, listVersion, list, data)
+			}
+		})
+	}
 
 	saveOneRateLimited := newOccasionalSaver(cfg.Timeouts.ActiveTimeout())
 	return func(ctx context.Context, specVersion, listVersion uint16) (vendorlist.VendorList, error) {
@@ -43,7 +71,7 @@ func NewVendorListFetcher(initCtx context.Context, cfg config.GDPR, client *http
 
 		// Attempt To Download
 		// - May not add to cache immediately.
-		saveOneRateLimited(ctx, client, urlMaker(specVersion, listVersion), cacheSave)
+		saveOneRateLimited(ctx, client, urlMaker(specVersion, listVersion), save)
 
 		// Attempt To Load From Cache Again
 		// - May have been added by the call to saveOneRateLimited.
@@ -60,8 +88,9 @@ func makeVendorListNotFoundError(specVersion, listVersion uint16) error {
 	return fmt.Errorf("gdpr vendor list spec version %d list version %d does not exist, or has not been loaded yet. Try again in a few minutes", specVersion, listVersion)
 }
 
-// preloadCache saves all the known versions of the vendor list for future use.
-func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16, uint16) string, saver saveVendors) {
+// preloadCache saves all the known versions of the vendor list for future use. The latest version is always
+// fetched to learn about the new versions, but the older versions which are already loaded aren't.
+func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16, uint16) string, saver saveVendors, isLoaded func(uint16, uint16) bool) {
 	versions := [2]struct {
 		specVersion      uint16
 		firstListVersion uint16
@@ -79,6 +108,9 @@ func preloadCache(ctx context.Context, client *http.Client, urlMaker func(uint16
 		latestVersion := saveOne(ctx, client, urlMaker(v.specVersion, 0), saver)
 
 		for i := v.firstListVersion; i < latestVersion; i++ {
+			if isLoaded(v.specVersion, i) {
+				continue
+			}
 			saveOne(ctx, client, urlMaker(v.specVersion, i), saver)
 		}
 	}
@@ -145,7 +177,7 @@ func saveOne(ctx context.Context, client *http.Client, url string, saver saveVen
 		return 0
 	}
 
-	saver(newList.SpecVersion(), newList.Version(), newList)
+	saver(newList.SpecVersion(), newList.Version(), newList, respBody)
 	return newList.Version()
 }
 
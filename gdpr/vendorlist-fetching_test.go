@@ -12,6 +12,7 @@ import (
 	"github.com/prebid/go-gdpr/api"
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/prebid-server/v3/config"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
 )
 
@@ -95,7 +96,7 @@ func TestFetcherThrottling(t *testing.T) {
 	})))
 	defer server.Close()
 
-	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), testURLMaker(server))
+	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), testURLMaker(server), &metricsConf.NilMetricsEngine{})
 
 	// Dynamically Load List 2 Successfully
 	_, errList1 := fetcher(context.Background(), 3, 2)
@@ -118,7 +119,7 @@ func TestMalformedVendorlist(t *testing.T) {
 	})))
 	defer server.Close()
 
-	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), testURLMaker(server))
+	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), testURLMaker(server), &metricsConf.NilMetricsEngine{})
 	_, err := fetcher(context.Background(), 3, 1)
 
 	// Fetching should fail since vendor list could not be unmarshalled.
@@ -131,7 +132,7 @@ func TestServerUrlInvalid(t *testing.T) {
 
 	invalidURLGenerator := func(uint16, uint16) string { return " http://invalid-url-has-leading-whitespace" }
 
-	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), invalidURLGenerator)
+	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), invalidURLGenerator, &metricsConf.NilMetricsEngine{})
 	_, err := fetcher(context.Background(), 3, 1)
 
 	assert.EqualError(t, err, "gdpr vendor list spec version 3 list version 1 does not exist, or has not been loaded yet. Try again in a few minutes")
@@ -141,7 +142,7 @@ func TestServerUnavailable(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
 	server.Close()
 
-	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), testURLMaker(server))
+	fetcher := NewVendorListFetcher(context.Background(), testConfig(), server.Client(), testURLMaker(server), &metricsConf.NilMetricsEngine{})
 	_, err := fetcher(context.Background(), 3, 1)
 
 	assert.EqualError(t, err, "gdpr vendor list spec version 3 list version 1 does not exist, or has not been loaded yet. Try again in a few minutes")
@@ -192,7 +193,7 @@ type versionInfo struct {
 }
 type saver []versionInfo
 
-func (s *saver) saveVendorLists(specVersion uint16, listVersion uint16, gvl api.VendorList) {
+func (s *saver) saveVendorLists(specVersion uint16, listVersion uint16, gvl api.VendorList, data []byte) {
 	vi := versionInfo{
 		specVersion: specVersion,
 		listVersion: listVersion,
@@ -253,7 +254,7 @@ func TestPreloadCache(t *testing.T) {
 	defer server.Close()
 
 	s := make(saver, 0, 5)
-	preloadCache(context.Background(), server.Client(), testURLMaker(server), s.saveVendorLists)
+	preloadCache(context.Background(), server.Client(), testURLMaker(server), s.saveVendorLists, func(uint16, uint16) bool { return false })
 
 	expectedLoadedVersions := []versionInfo{
 		{specVersion: 2, listVersion: 2},
@@ -380,7 +381,7 @@ type testExpected struct {
 
 func runTest(t *testing.T, test test, server *httptest.Server) {
 	config := testConfig()
-	fetcher := NewVendorListFetcher(context.Background(), config, server.Client(), testURLMaker(server))
+	fetcher := NewVendorListFetcher(context.Background(), config, server.Client(), testURLMaker(server), &metricsConf.NilMetricsEngine{})
 	vendorList, err := fetcher(context.Background(), test.setup.specVersion, test.setup.listVersion)
 
 	if test.expected.errorMessage != "" {
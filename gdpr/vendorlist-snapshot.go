@@ -0,0 +1,13 @@
+package gdpr
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed vendorlists
+var vendorListSnapshotFiles embed.FS
+
+// vendorListSnapshot is the snapshot of vendor lists bundled in the binary, laid out like vendorListPattern.
+// It's empty unless the lists were added to gdpr/vendorlists before the build, see scripts/vendorlist-snapshot.sh.
+var vendorListSnapshot, _ = fs.Sub(vendorListSnapshotFiles, "vendorlists")
@@ -0,0 +1,124 @@
+package gdpr
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/go-gdpr/vendorlist2"
+	"github.com/prebid/prebid-server/v3/metrics"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// The vendor lists are laid out as v{specVersion}/vendor-list-v{listVersion}.json, like the archives of the
+// vendor list host, both in the directory they are persisted to and in the snapshot bundled in the binary.
+const vendorListPattern = "v*/vendor-list-v*.json"
+
+func vendorListPath(specVersion, listVersion uint16) string {
+	return path.Join(fmt.Sprintf("v%d", specVersion), fmt.Sprintf("vendor-list-v%d.json", listVersion))
+}
+
+// loadVendorLists saves the vendor lists found in fsys. The files which can't be read or parsed are skipped.
+func loadVendorLists(fsys fs.FS, saver saveVendors) {
+	paths, err := fs.Glob(fsys, vendorListPattern)
+	if err != nil {
+		glog.Errorf("Failed to list the GDPR vendor lists: %v", err)
+		return
+	}
+
+	for _, p := range paths {
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			glog.Errorf("Failed to read the GDPR vendor list %s: %v", p, err)
+			continue
+		}
+		list, err := vendorlist2.ParseEagerly(data)
+		if err != nil {
+			glog.Errorf("GDPR vendor list %s is malformed: %v", p, err)
+			continue
+		}
+		saver(list.SpecVersion(), list.Version(), list, data)
+	}
+}
+
+// vendorListStore persists the vendor lists to a directory, so a restart doesn't depend on the vendor list
+// host. A store without a directory doesn't persist anything.
+type vendorListStore struct {
+	dir string
+}
+
+func (s vendorListStore) save(specVersion, listVersion uint16, data []byte) {
+	if s.dir == "" {
+		return
+	}
+
+	// A version of the vendor list never changes once published
+	file := filepath.Join(s.dir, filepath.FromSlash(vendorListPath(specVersion, listVersion)))
+	if _, err := os.Stat(file); err == nil {
+		return
+	}
+
+	if err := writeFileAtomically(file, data); err != nil {
+		glog.Errorf("Failed to persist GDPR vendor list spec version %d list version %d: %v", specVersion, listVersion, err)
+	}
+}
+
+// writeFileAtomically writes the file through a temporary file, so a crash never leaves a partial list behind.
+func writeFileAtomically(file string, data []byte) error {
+	dir := filepath.Dir(file)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".vendor-list-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// vendorListVersions records the latest vendor list loaded per spec version in the metrics.
+type vendorListVersions struct {
+	me     metrics.MetricsEngine
+	mu     sync.Mutex
+	latest map[uint16]uint16
+}
+
+func newVendorListVersions(me metrics.MetricsEngine) *vendorListVersions {
+	return &vendorListVersions{
+		me:     me,
+		latest: make(map[uint16]uint16),
+	}
+}
+
+func (v *vendorListVersions) record(specVersion, listVersion uint16, data []byte) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if listVersion <= v.latest[specVersion] {
+		return
+	}
+	v.latest[specVersion] = listVersion
+
+	// The publication time is only informative, so a list without one is still recorded
+	var published struct {
+		LastUpdated time.Time `json:"lastUpdated"`
+	}
+	jsonutil.Unmarshal(data, &published)
+
+	v.me.RecordVendorList(specVersion, listVersion, published.LastUpdated)
+}
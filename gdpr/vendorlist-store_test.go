@@ -0,0 +1,200 @@
+package gdpr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/prebid-server/v3/metrics"
+	metricsConf "github.com/prebid/prebid-server/v3/metrics/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetcherPersistsVendorLists(t *testing.T) {
+	dir := t.TempDir()
+	cfg := testConfig()
+	cfg.VendorLists.Dir = dir
+
+	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
+		vendorListLatestVersion: 2,
+		vendorLists: map[int]map[int]string{
+			3: {
+				1: vendorList1,
+				2: vendorList2,
+			},
+		},
+	})))
+	NewVendorListFetcher(context.Background(), cfg, server.Client(), testURLMaker(server), &metricsConf.NilMetricsEngine{})
+	server.Close()
+
+	persisted, err := os.ReadFile(filepath.Join(dir, "v3", "vendor-list-v1.json"))
+	require.NoError(t, err)
+	assert.Equal(t, vendorList1, string(persisted))
+	assert.FileExists(t, filepath.Join(dir, "v3", "vendor-list-v2.json"))
+
+	// A restart loads the persisted lists while the vendor list host is unreachable
+	fetcher := NewVendorListFetcher(context.Background(), cfg, server.Client(), testURLMaker(server), &metricsConf.NilMetricsEngine{})
+
+	list, err := fetcher(context.Background(), 3, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(2), list.Version())
+	assert.True(t, list.Vendor(12).Purpose(consentconstants.Purpose(3)))
+}
+
+func TestFetcherSnapshot(t *testing.T) {
+	snapshotVendorList1 := MarshalVendorList(vendorList{
+		GVLSpecificationVersion: 3,
+		VendorListVersion:       1,
+		Vendors:                 map[string]*vendor{"12": {ID: 12, Purposes: []int{1}}},
+	})
+
+	defaultSnapshot := vendorListSnapshot
+	defer func() { vendorListSnapshot = defaultSnapshot }()
+	vendorListSnapshot = fstest.MapFS{
+		"README.md":              {Data: []byte("snapshot")},
+		"v3/vendor-list-v1.json": {Data: []byte(snapshotVendorList1)},
+	}
+
+	tests := []struct {
+		description      string
+		snapshot         bool
+		serverUp         bool
+		expectedError    bool
+		expectedPurpose1 bool
+		expectedPurpose2 bool
+	}{
+		{
+			description:      "Snapshot used when the host is unreachable",
+			snapshot:         true,
+			expectedPurpose1: true,
+		},
+		{
+			description:   "Snapshot disabled",
+			snapshot:      false,
+			expectedError: true,
+		},
+		{
+			description:      "Fetched list takes precedence over the snapshot",
+			snapshot:         true,
+			serverUp:         true,
+			expectedPurpose2: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
+				vendorListLatestVersion: 1,
+				vendorLists:             map[int]map[int]string{3: {1: vendorList1}},
+			})))
+			defer server.Close()
+			if !tt.serverUp {
+				server.Close()
+			}
+
+			cfg := testConfig()
+			cfg.VendorLists.Snapshot = tt.snapshot
+			fetcher := NewVendorListFetcher(context.Background(), cfg, server.Client(), testURLMaker(server), &metricsConf.NilMetricsEngine{})
+
+			list, err := fetcher(context.Background(), 3, 1)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPurpose1, list.Vendor(12).Purpose(consentconstants.Purpose(1)))
+			assert.Equal(t, tt.expectedPurpose2, list.Vendor(12).Purpose(consentconstants.Purpose(2)))
+		})
+	}
+}
+
+func TestFetcherRecordsVendorListMetrics(t *testing.T) {
+	latestVendorList := `{"gvlSpecificationVersion":3,"vendorListVersion":2,"lastUpdated":"2024-05-16T16:05:32Z","vendors":{}}`
+
+	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
+		vendorListLatestVersion: 2,
+		vendorLists: map[int]map[int]string{
+			3: {
+				1: vendorList1,
+				2: latestVendorList,
+			},
+		},
+	})))
+	defer server.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordVendorList", uint16(3), uint16(2), time.Date(2024, 5, 16, 16, 5, 32, 0, time.UTC)).Return()
+
+	NewVendorListFetcher(context.Background(), testConfig(), server.Client(), testURLMaker(server), me)
+
+	me.AssertExpectations(t)
+	me.AssertNumberOfCalls(t, "RecordVendorList", 1)
+}
+
+func TestPreloadCacheSkipsLoadedVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(mockServer(serverSettings{
+		vendorListLatestVersion: 3,
+		vendorLists: map[int]map[int]string{
+			3: {
+				1: MarshalVendorList(vendorList{GVLSpecificationVersion: 3, VendorListVersion: 1}),
+				2: MarshalVendorList(vendorList{GVLSpecificationVersion: 3, VendorListVersion: 2}),
+				3: MarshalVendorList(vendorList{GVLSpecificationVersion: 3, VendorListVersion: 3}),
+			},
+		},
+	})))
+	defer server.Close()
+
+	s := make(saver, 0, 2)
+	isLoaded := func(specVersion, listVersion uint16) bool {
+		return specVersion == 3 && listVersion == 1
+	}
+	preloadCache(context.Background(), server.Client(), testURLMaker(server), s.saveVendorLists, isLoaded)
+
+	expectedLoadedVersions := []versionInfo{
+		{specVersion: 3, listVersion: 2},
+		{specVersion: 3, listVersion: 3},
+	}
+	assert.ElementsMatch(t, expectedLoadedVersions, s)
+}
+
+func TestLoadVendorLists(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md":              {Data: []byte("not a vendor list")},
+		"v3/vendor-list-v1.json": {Data: []byte(vendorList1)},
+		"v3/vendor-list-v2.json": {Data: []byte("malformed")},
+		"v3/other.json":          {Data: []byte(vendorList2)},
+	}
+
+	s := make(saver, 0, 1)
+	loadVendorLists(fsys, s.saveVendorLists)
+
+	assert.Equal(t, saver{{specVersion: 3, listVersion: 1}}, s)
+}
+
+func TestVendorListStoreSave(t *testing.T) {
+	dir := t.TempDir()
+	store := vendorListStore{dir: dir}
+	file := filepath.Join(dir, "v3", "vendor-list-v1.json")
+
+	store.save(3, 1, []byte(vendorList1))
+	store.save(3, 1, []byte(vendorList2))
+
+	persisted, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, vendorList1, string(persisted), "a published version never changes, so it isn't overwritten")
+
+	entries, err := os.ReadDir(filepath.Join(dir, "v3"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file is left behind")
+}
+
+func TestVendorListPath(t *testing.T) {
+	assert.Equal(t, "v3/vendor-list-v42.json", vendorListPath(3, 42))
+}
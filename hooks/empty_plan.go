@@ -36,3 +36,19 @@ func (e EmptyPlanBuilder) PlanForAllProcessedBidResponsesStage(endpoint string,
 func (e EmptyPlanBuilder) PlanForAuctionResponseStage(endpoint string, account *config.Account) Plan[hookstage.AuctionResponse] {
 	return nil
 }
+
+func (e EmptyPlanBuilder) PlanForCookieSyncRequestStage(endpoint string, account *config.Account) Plan[hookstage.CookieSyncRequest] {
+	return nil
+}
+
+func (e EmptyPlanBuilder) PlanForSetUIDRequestStage(endpoint string, account *config.Account) Plan[hookstage.SetUIDRequest] {
+	return nil
+}
+
+func (e EmptyPlanBuilder) PlanForEventRequestStage(endpoint string, account *config.Account) Plan[hookstage.EventRequest] {
+	return nil
+}
+
+func (e EmptyPlanBuilder) PlanForExitpointStage(endpoint string, account *config.Account) Plan[hookstage.Exitpoint] {
+	return nil
+}
@@ -18,4 +18,8 @@ func TestEmptyPlanBuilder(t *testing.T) {
 	assert.Len(t, planBuilder.PlanForRawBidderResponseStage(endpoint, nil), 0, message, StageRawBidderResponse)
 	assert.Len(t, planBuilder.PlanForAllProcessedBidResponsesStage(endpoint, nil), 0, message, StageAllProcessedBidResponses)
 	assert.Len(t, planBuilder.PlanForAuctionResponseStage(endpoint, nil), 0, message, StageAuctionResponse)
+	assert.Len(t, planBuilder.PlanForCookieSyncRequestStage(endpoint, nil), 0, message, StageCookieSyncRequest)
+	assert.Len(t, planBuilder.PlanForSetUIDRequestStage(endpoint, nil), 0, message, StageSetUIDRequest)
+	assert.Len(t, planBuilder.PlanForEventRequestStage(endpoint, nil), 0, message, StageEventRequest)
+	assert.Len(t, planBuilder.PlanForExitpointStage(endpoint, nil), 0, message, StageExitpoint)
 }
@@ -1,11 +1,16 @@
 package hookexecution
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/hooks"
 	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	metricsConfig "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 	"github.com/prebid/prebid-server/v3/privacy"
 	"github.com/stretchr/testify/assert"
@@ -233,3 +238,36 @@ func TestHandleModuleActivitiesNoBidderRequestPayload(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteStagePassesPreciseGeoActivity(t *testing.T) {
+	for _, allowed := range []bool{true, false} {
+		t.Run(fmt.Sprintf("allowed=%t", allowed), func(t *testing.T) {
+			plan := hooks.Plan[hookstage.ProcessedAuctionRequest]{
+				hooks.Group[hookstage.ProcessedAuctionRequest]{
+					Timeout: 10 * time.Millisecond,
+					Hooks:   []hooks.HookWrapper[hookstage.ProcessedAuctionRequest]{{Module: "foobar", Code: "foo", Hook: mockUpdateBidRequestHook{}}},
+				},
+			}
+			executionCtx := executionContext{
+				stage:           hooks.StageProcessedAuctionRequest.String(),
+				activityControl: privacy.NewActivityControl(getTransmitPreciseGeoActivityConfig("foo", allowed)),
+			}
+
+			var preciseGeoAllowed bool
+			handler := func(
+				_ context.Context,
+				moduleCtx hookstage.ModuleInvocationContext,
+				_ hookstage.ProcessedAuctionRequest,
+				_ hookstage.ProcessedAuctionRequestPayload,
+			) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+				preciseGeoAllowed = moduleCtx.PreciseGeoAllowed
+				return hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}, nil
+			}
+
+			payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}}
+			executeStage(executionCtx, plan, payload, handler, &metricsConfig.NilMetricsEngine{})
+
+			assert.Equal(t, allowed, preciseGeoAllowed)
+		})
+	}
+}
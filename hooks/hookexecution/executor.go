@@ -17,8 +17,12 @@ import (
 )
 
 const (
-	EndpointAuction = "/openrtb2/auction"
-	EndpointAmp     = "/openrtb2/amp"
+	EndpointAuction    = "/openrtb2/auction"
+	EndpointAmp        = "/openrtb2/amp"
+	EndpointVideo      = "/openrtb2/video"
+	EndpointCookieSync = "/cookie_sync"
+	EndpointSetUID     = "/setuid"
+	EndpointEvent      = "/event"
 )
 
 // An entity specifies the type of object that was processed during the execution of the stage.
@@ -29,6 +33,10 @@ const (
 	entityAuctionRequest           entity = "auction-request"
 	entityAuctionResponse          entity = "auction_response"
 	entityAllProcessedBidResponses entity = "all_processed_bid_responses"
+	entityCookieSyncRequest        entity = "cookie-sync-request"
+	entitySetUIDRequest            entity = "setuid-request"
+	entityEventRequest             entity = "event-request"
+	entityHttpResponse             entity = "http-response"
 )
 
 type StageExecutor interface {
@@ -39,6 +47,10 @@ type StageExecutor interface {
 	ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) *RejectError
 	ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
 	ExecuteAuctionResponseStage(response *openrtb2.BidResponse)
+	ExecuteCookieSyncRequestStage(request hookstage.CookieSyncRequestPayload) (hookstage.CookieSyncRequestPayload, *RejectError)
+	ExecuteSetUIDRequestStage(request hookstage.SetUIDRequestPayload) (hookstage.SetUIDRequestPayload, *RejectError)
+	ExecuteEventRequestStage(request hookstage.EventRequestPayload) (hookstage.EventRequestPayload, *RejectError)
+	ExecuteExitpointStage(response hookstage.ExitpointPayload) hookstage.ExitpointPayload
 }
 
 type HookStageExecutor interface {
@@ -295,6 +307,121 @@ func (e *hookExecutor) ExecuteAuctionResponseStage(response *openrtb2.BidRespons
 	e.pushStageOutcome(outcome)
 }
 
+func (e *hookExecutor) ExecuteCookieSyncRequestStage(request hookstage.CookieSyncRequestPayload) (hookstage.CookieSyncRequestPayload, *RejectError) {
+	plan := e.planBuilder.PlanForCookieSyncRequestStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return request, nil
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.CookieSyncRequest,
+		payload hookstage.CookieSyncRequestPayload,
+	) (hookstage.HookResult[hookstage.CookieSyncRequestPayload], error) {
+		return hook.HandleCookieSyncRequestHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageCookieSyncRequest.String()
+	executionCtx := e.newContext(stageName)
+
+	outcome, payload, contexts, reject := executeStage(executionCtx, plan, request, handler, e.metricEngine)
+	outcome.Entity = entityCookieSyncRequest
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	return payload, reject
+}
+
+func (e *hookExecutor) ExecuteSetUIDRequestStage(request hookstage.SetUIDRequestPayload) (hookstage.SetUIDRequestPayload, *RejectError) {
+	plan := e.planBuilder.PlanForSetUIDRequestStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return request, nil
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.SetUIDRequest,
+		payload hookstage.SetUIDRequestPayload,
+	) (hookstage.HookResult[hookstage.SetUIDRequestPayload], error) {
+		return hook.HandleSetUIDRequestHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageSetUIDRequest.String()
+	executionCtx := e.newContext(stageName)
+
+	outcome, payload, contexts, reject := executeStage(executionCtx, plan, request, handler, e.metricEngine)
+	outcome.Entity = entitySetUIDRequest
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	return payload, reject
+}
+
+func (e *hookExecutor) ExecuteEventRequestStage(request hookstage.EventRequestPayload) (hookstage.EventRequestPayload, *RejectError) {
+	plan := e.planBuilder.PlanForEventRequestStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return request, nil
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.EventRequest,
+		payload hookstage.EventRequestPayload,
+	) (hookstage.HookResult[hookstage.EventRequestPayload], error) {
+		return hook.HandleEventRequestHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageEventRequest.String()
+	executionCtx := e.newContext(stageName)
+
+	outcome, payload, contexts, reject := executeStage(executionCtx, plan, request, handler, e.metricEngine)
+	outcome.Entity = entityEventRequest
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	return payload, reject
+}
+
+func (e *hookExecutor) ExecuteExitpointStage(response hookstage.ExitpointPayload) hookstage.ExitpointPayload {
+	response.Endpoint = e.endpoint
+
+	plan := e.planBuilder.PlanForExitpointStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return response
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.Exitpoint,
+		payload hookstage.ExitpointPayload,
+	) (hookstage.HookResult[hookstage.ExitpointPayload], error) {
+		return hook.HandleExitpointHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageExitpoint.String()
+	executionCtx := e.newContext(stageName)
+
+	outcome, payload, contexts, _ := executeStage(executionCtx, plan, response, handler, e.metricEngine)
+	outcome.Entity = entityHttpResponse
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	payload.Endpoint = e.endpoint
+	return payload
+}
+
 func (e *hookExecutor) newContext(stage string) executionContext {
 	return executionContext{
 		account:         e.account,
@@ -354,3 +481,18 @@ func (executor EmptyHookExecutor) ExecuteAllProcessedBidResponsesStage(_ map[ope
 }
 
 func (executor EmptyHookExecutor) ExecuteAuctionResponseStage(_ *openrtb2.BidResponse) {}
+
+func (executor EmptyHookExecutor) ExecuteCookieSyncRequestStage(request hookstage.CookieSyncRequestPayload) (hookstage.CookieSyncRequestPayload, *RejectError) {
+	return request, nil
+}
+
+func (executor EmptyHookExecutor) ExecuteSetUIDRequestStage(request hookstage.SetUIDRequestPayload) (hookstage.SetUIDRequestPayload, *RejectError) {
+	return request, nil
+}
+
+func (executor EmptyHookExecutor) ExecuteEventRequestStage(request hookstage.EventRequestPayload) (hookstage.EventRequestPayload, *RejectError) {
+	return request, nil
+}
+func (executor EmptyHookExecutor) ExecuteExitpointStage(response hookstage.ExitpointPayload) hookstage.ExitpointPayload {
+	return response
+}
@@ -40,6 +40,10 @@ func TestEmptyHookExecutor(t *testing.T) {
 	processedAuctionRejectErr := executor.ExecuteProcessedAuctionStage(&openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}})
 	bidderRequestRejectErr := executor.ExecuteBidderRequestStage(&openrtb_ext.RequestWrapper{BidRequest: bidderRequest}, "bidder-name")
 	executor.ExecuteAuctionResponseStage(&openrtb2.BidResponse{})
+	cookieSyncRequest, cookieSyncRejectErr := executor.ExecuteCookieSyncRequestStage(hookstage.CookieSyncRequestPayload{Bidders: []string{"bidder-name"}})
+	setUIDRequest, setUIDRejectErr := executor.ExecuteSetUIDRequestStage(hookstage.SetUIDRequestPayload{UID: "some-uid"})
+	eventRequest, eventRejectErr := executor.ExecuteEventRequestStage(hookstage.EventRequestPayload{BidID: "some-bid"})
+	exitpointResponse := executor.ExecuteExitpointStage(hookstage.ExitpointPayload{StatusCode: http.StatusOK, Body: []byte("body")})
 
 	outcomes := executor.GetOutcomes()
 	assert.Equal(t, EmptyHookExecutor{}, executor, "EmptyHookExecutor shouldn't be changed.")
@@ -54,6 +58,14 @@ func TestEmptyHookExecutor(t *testing.T) {
 	assert.Nil(t, processedAuctionRejectErr, "EmptyHookExecutor shouldn't return reject error at processed-auction stage.")
 	assert.Nil(t, bidderRequestRejectErr, "EmptyHookExecutor shouldn't return reject error at bidder-request stage.")
 	assert.Equal(t, expectedBidderRequest, bidderRequest, "EmptyHookExecutor shouldn't change payload at bidder-request stage.")
+
+	assert.Nil(t, cookieSyncRejectErr, "EmptyHookExecutor shouldn't return reject error at cookie-sync-request stage.")
+	assert.Equal(t, hookstage.CookieSyncRequestPayload{Bidders: []string{"bidder-name"}}, cookieSyncRequest, "EmptyHookExecutor shouldn't change payload at cookie-sync-request stage.")
+	assert.Nil(t, setUIDRejectErr, "EmptyHookExecutor shouldn't return reject error at setuid-request stage.")
+	assert.Equal(t, hookstage.SetUIDRequestPayload{UID: "some-uid"}, setUIDRequest, "EmptyHookExecutor shouldn't change payload at setuid-request stage.")
+	assert.Nil(t, eventRejectErr, "EmptyHookExecutor shouldn't return reject error at event-request stage.")
+	assert.Equal(t, hookstage.EventRequestPayload{BidID: "some-bid"}, eventRequest, "EmptyHookExecutor shouldn't change payload at event-request stage.")
+	assert.Equal(t, hookstage.ExitpointPayload{StatusCode: http.StatusOK, Body: []byte("body")}, exitpointResponse, "EmptyHookExecutor shouldn't change payload at exitpoint stage.")
 }
 
 func TestExecuteEntrypointStage(t *testing.T) {
@@ -1952,6 +1964,251 @@ func TestExecuteAuctionResponseStage(t *testing.T) {
 	}
 }
 
+func TestExecuteCookieSyncRequestStage(t *testing.T) {
+	request := hookstage.CookieSyncRequestPayload{Account: "some-account", Bidders: []string{"bidderA", "bidderB"}}
+
+	testCases := []struct {
+		description           string
+		givenPlanBuilder      hooks.ExecutionPlanBuilder
+		expectedRequest       hookstage.CookieSyncRequestPayload
+		expectedReject        *RejectError
+		expectedStageOutcomes []StageOutcome
+	}{
+		{
+			description:           "Payload not changed if hook execution plan empty",
+			givenPlanBuilder:      hooks.EmptyPlanBuilder{},
+			expectedRequest:       request,
+			expectedStageOutcomes: []StageOutcome{},
+		},
+		{
+			description:      "Payload changed if hooks return mutations",
+			givenPlanBuilder: TestRequestStagesPlanBuilder{hook: mockUpdateUserSyncHook{}},
+			expectedRequest:  hookstage.CookieSyncRequestPayload{Account: "some-account", Bidders: []string{"bidderA"}},
+			expectedStageOutcomes: []StageOutcome{
+				newRequestStageOutcome(entityCookieSyncRequest, hooks.StageCookieSyncRequest, StatusSuccess, ActionUpdate,
+					[]string{fmt.Sprintf("Hook mutation successfully applied, affected key: bidders, mutation type: %s", hookstage.MutationUpdate)}, nil),
+			},
+		},
+		{
+			description:      "Stage execution can be rejected",
+			givenPlanBuilder: TestRequestStagesPlanBuilder{hook: mockRejectHook{}},
+			expectedRequest:  request,
+			expectedReject:   &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageCookieSyncRequest.String()},
+			expectedStageOutcomes: []StageOutcome{
+				newRequestStageOutcome(entityCookieSyncRequest, hooks.StageCookieSyncRequest, StatusSuccess, ActionReject, nil,
+					[]string{fmt.Sprintf("Module foobar (hook: foo) rejected request with code 0 at %s stage", hooks.StageCookieSyncRequest)}),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointCookieSync, &metricsConfig.NilMetricsEngine{})
+
+			payload, reject := exec.ExecuteCookieSyncRequestStage(request)
+
+			assert.Equal(t, test.expectedReject, reject, "Unexpected stage reject.")
+			assert.Equal(t, test.expectedRequest, payload, "Incorrect request update.")
+			assertRequestStageOutcomes(t, test.expectedStageOutcomes, exec.GetOutcomes())
+		})
+	}
+}
+
+func TestExecuteSetUIDRequestStage(t *testing.T) {
+	request := hookstage.SetUIDRequestPayload{Account: "some-account", Bidder: "bidderA", UID: "some-uid"}
+
+	testCases := []struct {
+		description           string
+		givenPlanBuilder      hooks.ExecutionPlanBuilder
+		expectedRequest       hookstage.SetUIDRequestPayload
+		expectedReject        *RejectError
+		expectedStageOutcomes []StageOutcome
+	}{
+		{
+			description:           "Payload not changed if hook execution plan empty",
+			givenPlanBuilder:      hooks.EmptyPlanBuilder{},
+			expectedRequest:       request,
+			expectedStageOutcomes: []StageOutcome{},
+		},
+		{
+			description:      "Payload changed if hooks return mutations",
+			givenPlanBuilder: TestRequestStagesPlanBuilder{hook: mockUpdateUserSyncHook{}},
+			expectedRequest:  hookstage.SetUIDRequestPayload{Account: "some-account", Bidder: "bidderA", UID: "new-uid"},
+			expectedStageOutcomes: []StageOutcome{
+				newRequestStageOutcome(entitySetUIDRequest, hooks.StageSetUIDRequest, StatusSuccess, ActionUpdate,
+					[]string{fmt.Sprintf("Hook mutation successfully applied, affected key: uid, mutation type: %s", hookstage.MutationUpdate)}, nil),
+			},
+		},
+		{
+			description:      "Stage execution can be rejected",
+			givenPlanBuilder: TestRequestStagesPlanBuilder{hook: mockRejectHook{}},
+			expectedRequest:  request,
+			expectedReject:   &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageSetUIDRequest.String()},
+			expectedStageOutcomes: []StageOutcome{
+				newRequestStageOutcome(entitySetUIDRequest, hooks.StageSetUIDRequest, StatusSuccess, ActionReject, nil,
+					[]string{fmt.Sprintf("Module foobar (hook: foo) rejected request with code 0 at %s stage", hooks.StageSetUIDRequest)}),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointSetUID, &metricsConfig.NilMetricsEngine{})
+
+			payload, reject := exec.ExecuteSetUIDRequestStage(request)
+
+			assert.Equal(t, test.expectedReject, reject, "Unexpected stage reject.")
+			assert.Equal(t, test.expectedRequest, payload, "Incorrect request update.")
+			assertRequestStageOutcomes(t, test.expectedStageOutcomes, exec.GetOutcomes())
+		})
+	}
+}
+
+func TestExecuteEventRequestStage(t *testing.T) {
+	request := hookstage.EventRequestPayload{Type: "win", BidID: "some-bid", AccountID: "some-account", Integration: "some-integration"}
+
+	testCases := []struct {
+		description           string
+		givenPlanBuilder      hooks.ExecutionPlanBuilder
+		expectedRequest       hookstage.EventRequestPayload
+		expectedReject        *RejectError
+		expectedStageOutcomes []StageOutcome
+	}{
+		{
+			description:           "Payload not changed if hook execution plan empty",
+			givenPlanBuilder:      hooks.EmptyPlanBuilder{},
+			expectedRequest:       request,
+			expectedStageOutcomes: []StageOutcome{},
+		},
+		{
+			description:      "Payload changed if hooks return mutations",
+			givenPlanBuilder: TestRequestStagesPlanBuilder{hook: mockUpdateUserSyncHook{}},
+			expectedRequest:  hookstage.EventRequestPayload{Type: "win", BidID: "some-bid", AccountID: "some-account", Integration: "new-integration"},
+			expectedStageOutcomes: []StageOutcome{
+				newRequestStageOutcome(entityEventRequest, hooks.StageEventRequest, StatusSuccess, ActionUpdate,
+					[]string{fmt.Sprintf("Hook mutation successfully applied, affected key: integration, mutation type: %s", hookstage.MutationUpdate)}, nil),
+			},
+		},
+		{
+			description:      "Stage execution can be rejected",
+			givenPlanBuilder: TestRequestStagesPlanBuilder{hook: mockRejectHook{}},
+			expectedRequest:  request,
+			expectedReject:   &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageEventRequest.String()},
+			expectedStageOutcomes: []StageOutcome{
+				newRequestStageOutcome(entityEventRequest, hooks.StageEventRequest, StatusSuccess, ActionReject, nil,
+					[]string{fmt.Sprintf("Module foobar (hook: foo) rejected request with code 0 at %s stage", hooks.StageEventRequest)}),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointEvent, &metricsConfig.NilMetricsEngine{})
+
+			payload, reject := exec.ExecuteEventRequestStage(request)
+
+			assert.Equal(t, test.expectedReject, reject, "Unexpected stage reject.")
+			assert.Equal(t, test.expectedRequest, payload, "Incorrect request update.")
+			assertRequestStageOutcomes(t, test.expectedStageOutcomes, exec.GetOutcomes())
+		})
+	}
+}
+
+func TestExecuteExitpointStage(t *testing.T) {
+	newResponse := func() hookstage.ExitpointPayload {
+		return hookstage.ExitpointPayload{
+			StatusCode: http.StatusOK,
+			Headers:    http.Header{"Content-Type": []string{"application/json"}},
+			Body:       []byte(`{"id":"some-id"}`),
+		}
+	}
+	expectedResponse := newResponse()
+	expectedResponse.Endpoint = EndpointAuction
+
+	testCases := []struct {
+		description           string
+		givenPlanBuilder      hooks.ExecutionPlanBuilder
+		expectedResponse      hookstage.ExitpointPayload
+		expectedStageOutcomes []StageOutcome
+	}{
+		{
+			description:           "Payload not changed if hook execution plan empty",
+			givenPlanBuilder:      hooks.EmptyPlanBuilder{},
+			expectedResponse:      expectedResponse,
+			expectedStageOutcomes: []StageOutcome{},
+		},
+		{
+			description:      "Payload changed if hooks return mutations, endpoint can't be changed",
+			givenPlanBuilder: TestExitpointPlanBuilder{hook: mockUpdateExitpointHook{}},
+			expectedResponse: hookstage.ExitpointPayload{
+				Endpoint:   EndpointAuction,
+				StatusCode: http.StatusOK,
+				Headers:    http.Header{"Content-Type": []string{"application/xml"}},
+				Body:       []byte("<vast/>"),
+			},
+			expectedStageOutcomes: []StageOutcome{
+				newExitpointStageOutcome(StatusSuccess, ActionUpdate,
+					[]string{fmt.Sprintf("Hook mutation successfully applied, affected key: body.headers, mutation type: %s", hookstage.MutationUpdate)}, nil),
+			},
+		},
+		{
+			description:      "Stage execution can't be rejected - stage doesn't support rejection",
+			givenPlanBuilder: TestExitpointPlanBuilder{hook: mockRejectHook{}},
+			expectedResponse: expectedResponse,
+			expectedStageOutcomes: []StageOutcome{
+				newExitpointStageOutcome(StatusExecutionFailure, "", nil,
+					[]string{fmt.Sprintf("Module (name: foobar, hook code: foo) tried to reject request on the %s stage that does not support rejection", hooks.StageExitpoint)}),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+			response := exec.ExecuteExitpointStage(newResponse())
+
+			assert.Equal(t, test.expectedResponse, response, "Incorrect response update.")
+			assertRequestStageOutcomes(t, test.expectedStageOutcomes, exec.GetOutcomes())
+		})
+	}
+}
+
+func newExitpointStageOutcome(status Status, action Action, debugMessages, errors []string) StageOutcome {
+	return newRequestStageOutcome(entityHttpResponse, hooks.StageExitpoint, status, action, debugMessages, errors)
+}
+
+func newRequestStageOutcome(entity entity, stage hooks.Stage, status Status, action Action, debugMessages, errors []string) StageOutcome {
+	return StageOutcome{
+		Entity: entity,
+		Stage:  stage.String(),
+		Groups: []GroupOutcome{
+			{
+				InvocationResults: []HookOutcome{
+					{
+						AnalyticsTags: hookanalytics.Analytics{},
+						HookID:        HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+						Status:        status,
+						Action:        action,
+						DebugMessages: debugMessages,
+						Errors:        errors,
+					},
+				},
+			},
+		},
+	}
+}
+
+func assertRequestStageOutcomes(t *testing.T, expected, actual []StageOutcome) {
+	if len(expected) == 0 {
+		assert.Empty(t, actual, "Incorrect stage outcomes.")
+		return
+	}
+	if assert.Len(t, actual, len(expected), "Incorrect stage outcomes.") {
+		assertEqualStageOutcomes(t, expected[0], actual[0])
+	}
+}
+
 func TestInterStageContextCommunication(t *testing.T) {
 	body := []byte(`{"foo": "bar"}`)
 	reader := bytes.NewReader(body)
@@ -2169,6 +2426,57 @@ func (e TestApplyHookMutationsBuilder) PlanForAuctionResponseStage(_ string, _ *
 	}
 }
 
+// TestRequestStagesPlanBuilder runs a single hook at the cookie sync, setuid and event stages.
+type TestRequestStagesPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook interface {
+		hookstage.CookieSyncRequest
+		hookstage.SetUIDRequest
+		hookstage.EventRequest
+	}
+}
+
+func (e TestRequestStagesPlanBuilder) PlanForCookieSyncRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.CookieSyncRequest] {
+	return hooks.Plan[hookstage.CookieSyncRequest]{
+		hooks.Group[hookstage.CookieSyncRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks:   []hooks.HookWrapper[hookstage.CookieSyncRequest]{{Module: "foobar", Code: "foo", Hook: e.hook}},
+		},
+	}
+}
+
+func (e TestRequestStagesPlanBuilder) PlanForSetUIDRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.SetUIDRequest] {
+	return hooks.Plan[hookstage.SetUIDRequest]{
+		hooks.Group[hookstage.SetUIDRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks:   []hooks.HookWrapper[hookstage.SetUIDRequest]{{Module: "foobar", Code: "foo", Hook: e.hook}},
+		},
+	}
+}
+
+func (e TestRequestStagesPlanBuilder) PlanForEventRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.EventRequest] {
+	return hooks.Plan[hookstage.EventRequest]{
+		hooks.Group[hookstage.EventRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks:   []hooks.HookWrapper[hookstage.EventRequest]{{Module: "foobar", Code: "foo", Hook: e.hook}},
+		},
+	}
+}
+
+type TestExitpointPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook hookstage.Exitpoint
+}
+
+func (e TestExitpointPlanBuilder) PlanForExitpointStage(_ string, _ *config.Account) hooks.Plan[hookstage.Exitpoint] {
+	return hooks.Plan[hookstage.Exitpoint]{
+		hooks.Group[hookstage.Exitpoint]{
+			Timeout: 10 * time.Millisecond,
+			Hooks:   []hooks.HookWrapper[hookstage.Exitpoint]{{Module: "foobar", Code: "foo", Hook: e.hook}},
+		},
+	}
+}
+
 type TestRejectPlanBuilder struct {
 	hooks.EmptyPlanBuilder
 }
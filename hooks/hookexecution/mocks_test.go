@@ -100,6 +100,22 @@ func (e mockRejectHook) HandleAuctionResponseHook(_ context.Context, _ hookstage
 	return hookstage.HookResult[hookstage.AuctionResponsePayload]{Reject: true}, nil
 }
 
+func (e mockRejectHook) HandleCookieSyncRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.CookieSyncRequestPayload) (hookstage.HookResult[hookstage.CookieSyncRequestPayload], error) {
+	return hookstage.HookResult[hookstage.CookieSyncRequestPayload]{Reject: true}, nil
+}
+
+func (e mockRejectHook) HandleSetUIDRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.SetUIDRequestPayload) (hookstage.HookResult[hookstage.SetUIDRequestPayload], error) {
+	return hookstage.HookResult[hookstage.SetUIDRequestPayload]{Reject: true}, nil
+}
+
+func (e mockRejectHook) HandleExitpointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.ExitpointPayload) (hookstage.HookResult[hookstage.ExitpointPayload], error) {
+	return hookstage.HookResult[hookstage.ExitpointPayload]{Reject: true}, nil
+}
+
+func (e mockRejectHook) HandleEventRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EventRequestPayload) (hookstage.HookResult[hookstage.EventRequestPayload], error) {
+	return hookstage.HookResult[hookstage.EventRequestPayload]{Reject: true}, nil
+}
+
 type mockTimeoutHook struct{}
 
 func (e mockTimeoutHook) HandleEntrypointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
@@ -385,3 +401,49 @@ func (e mockUpdateBidResponseHook) HandleAuctionResponseHook(_ context.Context,
 
 	return hookstage.HookResult[hookstage.AuctionResponsePayload]{ChangeSet: c}, nil
 }
+
+type mockUpdateUserSyncHook struct{}
+
+func (e mockUpdateUserSyncHook) HandleCookieSyncRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.CookieSyncRequestPayload) (hookstage.HookResult[hookstage.CookieSyncRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.CookieSyncRequestPayload]{}
+	c.AddMutation(func(payload hookstage.CookieSyncRequestPayload) (hookstage.CookieSyncRequestPayload, error) {
+		payload.Bidders = []string{"bidderA"}
+		return payload, nil
+	}, hookstage.MutationUpdate, "bidders")
+
+	return hookstage.HookResult[hookstage.CookieSyncRequestPayload]{ChangeSet: c}, nil
+}
+
+func (e mockUpdateUserSyncHook) HandleSetUIDRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.SetUIDRequestPayload) (hookstage.HookResult[hookstage.SetUIDRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.SetUIDRequestPayload]{}
+	c.AddMutation(func(payload hookstage.SetUIDRequestPayload) (hookstage.SetUIDRequestPayload, error) {
+		payload.UID = "new-uid"
+		return payload, nil
+	}, hookstage.MutationUpdate, "uid")
+
+	return hookstage.HookResult[hookstage.SetUIDRequestPayload]{ChangeSet: c}, nil
+}
+
+func (e mockUpdateUserSyncHook) HandleEventRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EventRequestPayload) (hookstage.HookResult[hookstage.EventRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.EventRequestPayload]{}
+	c.AddMutation(func(payload hookstage.EventRequestPayload) (hookstage.EventRequestPayload, error) {
+		payload.Integration = "new-integration"
+		return payload, nil
+	}, hookstage.MutationUpdate, "integration")
+
+	return hookstage.HookResult[hookstage.EventRequestPayload]{ChangeSet: c}, nil
+}
+
+type mockUpdateExitpointHook struct{}
+
+func (e mockUpdateExitpointHook) HandleExitpointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.ExitpointPayload) (hookstage.HookResult[hookstage.ExitpointPayload], error) {
+	c := hookstage.ChangeSet[hookstage.ExitpointPayload]{}
+	c.AddMutation(func(payload hookstage.ExitpointPayload) (hookstage.ExitpointPayload, error) {
+		payload.Body = []byte("<vast/>")
+		payload.Headers.Set("Content-Type", "application/xml")
+		payload.Endpoint = "/changed"
+		return payload, nil
+	}, hookstage.MutationUpdate, "body", "headers")
+
+	return hookstage.HookResult[hookstage.ExitpointPayload]{ChangeSet: c}, nil
+}
@@ -0,0 +1,36 @@
+package hookstage
+
+import (
+	"context"
+	"net/http"
+)
+
+// CookieSyncRequest hooks are invoked only for "/cookie_sync" endpoint
+// after retrieving the account config, but before the privacy policies
+// are read and the syncers are chosen.
+//
+// At this stage, account config is available,
+// so it can be configured at the account-level execution plan,
+// the account-level module config is passed to hooks.
+//
+// Rejection results in sending a response without any user syncs.
+type CookieSyncRequest interface {
+	HandleCookieSyncRequestHook(
+		context.Context,
+		ModuleInvocationContext,
+		CookieSyncRequestPayload,
+	) (HookResult[CookieSyncRequestPayload], error)
+}
+
+// CookieSyncRequestPayload consists of an HTTP request and the fields of the cookie sync request
+// used to choose the syncers. Hooks are allowed to modify the bidders and the privacy fields using mutations.
+type CookieSyncRequestPayload struct {
+	Request     *http.Request
+	Account     string
+	Bidders     []string
+	GDPR        *int
+	GDPRConsent string
+	USPrivacy   string
+	GPP         string
+	GPPSID      string
+}
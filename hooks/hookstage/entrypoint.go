@@ -12,7 +12,8 @@ import (
 // the account-level module config is not available.
 //
 // Rejection results in sending an empty BidResponse
-// with the NBR code indicating the rejection reason.
+// with the NBR code indicating the rejection reason,
+// for "/openrtb2/video" endpoint an empty video response is sent.
 type Entrypoint interface {
 	HandleEntrypointHook(
 		context.Context,
@@ -22,7 +23,8 @@ type Entrypoint interface {
 }
 
 // EntrypointPayload consists of an HTTP request and a raw body of the openrtb2.BidRequest.
-// For "/openrtb2/amp" endpoint the body is nil.
+// For "/openrtb2/amp" endpoint the body is nil,
+// for "/openrtb2/video" endpoint it holds the video request.
 // Hooks are allowed to modify this data using mutations.
 type EntrypointPayload struct {
 	Request *http.Request
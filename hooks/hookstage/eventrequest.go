@@ -0,0 +1,37 @@
+package hookstage
+
+import (
+	"context"
+	"net/http"
+)
+
+// EventRequest hooks are invoked only for "/event" endpoint
+// after retrieving the account config,
+// but before the notification event is passed to the analytics modules.
+//
+// At this stage, account config is available,
+// so it can be configured at the account-level execution plan,
+// the account-level module config is passed to hooks.
+//
+// Rejection results in dropping the event,
+// the response is sent as if the event was logged.
+type EventRequest interface {
+	HandleEventRequestHook(
+		context.Context,
+		ModuleInvocationContext,
+		EventRequestPayload,
+	) (HookResult[EventRequestPayload], error)
+}
+
+// EventRequestPayload consists of an HTTP request and the fields of the notification event.
+// Hooks are allowed to modify the event fields using mutations.
+type EventRequestPayload struct {
+	Request     *http.Request
+	Type        string
+	VType       string
+	BidID       string
+	AccountID   string
+	Bidder      string
+	Timestamp   int64
+	Integration string
+}
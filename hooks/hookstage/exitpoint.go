@@ -0,0 +1,35 @@
+package hookstage
+
+import (
+	"context"
+	"net/http"
+)
+
+// Exitpoint hooks are invoked right before the response is written
+// to the client, after the auction_response stage is done
+// and the response is serialized.
+// The hooks are invoked even if the request was rejected at earlier stages.
+//
+// At this stage, account config is available,
+// so it can be configured at the account-level execution plan,
+// the account-level module config is passed to hooks.
+//
+// Rejection has no effect and is completely ignored at this stage.
+type Exitpoint interface {
+	HandleExitpointHook(
+		context.Context,
+		ModuleInvocationContext,
+		ExitpointPayload,
+	) (HookResult[ExitpointPayload], error)
+}
+
+// ExitpointPayload consists of the final HTTP response that will be sent back to the requester.
+// Hooks are allowed to replace the body, change the headers and the status code,
+// for example to convert the response to a different format or to sign it.
+// The Endpoint is provided for reference and changes to it are ignored.
+type ExitpointPayload struct {
+	Endpoint   string
+	StatusCode int
+	Headers    http.Header
+	Body       []byte
+}
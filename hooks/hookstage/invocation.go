@@ -27,6 +27,10 @@ type ModuleInvocationContext struct {
 	Endpoint string
 	// ModuleContext holds values that the module passes to itself from the previous stages.
 	ModuleContext ModuleContext
+	// PreciseGeoAllowed reports if the transmitPreciseGeo activity allows the hook to handle precise geolocation.
+	// When it's not allowed, the hook receives the request with the device IP masked and the geo rounded,
+	// and hooks adding geolocation data to the request should add only coarse data.
+	PreciseGeoAllowed bool
 }
 
 // ModuleContext holds arbitrary data passed between module hooks at different stages.
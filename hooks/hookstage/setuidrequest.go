@@ -0,0 +1,32 @@
+package hookstage
+
+import (
+	"context"
+	"net/http"
+)
+
+// SetUIDRequest hooks are invoked only for "/setuid" endpoint
+// after retrieving the account config and enforcing the privacy policies,
+// but before the UID is written to the cookie.
+//
+// At this stage, account config is available,
+// so it can be configured at the account-level execution plan,
+// the account-level module config is passed to hooks.
+//
+// Rejection results in leaving the cookie unchanged.
+type SetUIDRequest interface {
+	HandleSetUIDRequestHook(
+		context.Context,
+		ModuleInvocationContext,
+		SetUIDRequestPayload,
+	) (HookResult[SetUIDRequestPayload], error)
+}
+
+// SetUIDRequestPayload consists of an HTTP request and the UID about to be set for the bidder.
+// An empty UID clears the bidder's UID. Hooks are allowed to modify the UID using mutations.
+type SetUIDRequestPayload struct {
+	Request *http.Request
+	Account string
+	Bidder  string
+	UID     string
+}
@@ -19,6 +19,10 @@ const (
 	StageRawBidderResponse        Stage = "raw_bidder_response"
 	StageAllProcessedBidResponses Stage = "all_processed_bid_responses"
 	StageAuctionResponse          Stage = "auction_response"
+	StageCookieSyncRequest        Stage = "cookie_sync_request"
+	StageSetUIDRequest            Stage = "setuid_request"
+	StageEventRequest             Stage = "event_request"
+	StageExitpoint                Stage = "exitpoint"
 )
 
 func (s Stage) String() string {
@@ -27,7 +31,8 @@ func (s Stage) String() string {
 
 func (s Stage) IsRejectable() bool {
 	return s != StageAllProcessedBidResponses &&
-		s != StageAuctionResponse
+		s != StageAuctionResponse &&
+		s != StageExitpoint
 }
 
 // ExecutionPlanBuilder is the interface that provides methods
@@ -41,6 +46,10 @@ type ExecutionPlanBuilder interface {
 	PlanForRawBidderResponseStage(endpoint string, account *config.Account) Plan[hookstage.RawBidderResponse]
 	PlanForAllProcessedBidResponsesStage(endpoint string, account *config.Account) Plan[hookstage.AllProcessedBidResponses]
 	PlanForAuctionResponseStage(endpoint string, account *config.Account) Plan[hookstage.AuctionResponse]
+	PlanForCookieSyncRequestStage(endpoint string, account *config.Account) Plan[hookstage.CookieSyncRequest]
+	PlanForSetUIDRequestStage(endpoint string, account *config.Account) Plan[hookstage.SetUIDRequest]
+	PlanForEventRequestStage(endpoint string, account *config.Account) Plan[hookstage.EventRequest]
+	PlanForExitpointStage(endpoint string, account *config.Account) Plan[hookstage.Exitpoint]
 }
 
 // Plan represents a slice of groups of hooks of a specific type grouped in the established order.
@@ -156,6 +165,113 @@ func (p PlanBuilder) PlanForAuctionResponseStage(endpoint string, account *confi
 	)
 }
 
+func (p PlanBuilder) PlanForCookieSyncRequestStage(endpoint string, account *config.Account) Plan[hookstage.CookieSyncRequest] {
+	return getMergedPlan(
+		p.hooks,
+		account,
+		endpoint,
+		StageCookieSyncRequest,
+		p.repo.GetCookieSyncRequestHook,
+	)
+}
+
+func (p PlanBuilder) PlanForSetUIDRequestStage(endpoint string, account *config.Account) Plan[hookstage.SetUIDRequest] {
+	return getMergedPlan(
+		p.hooks,
+		account,
+		endpoint,
+		StageSetUIDRequest,
+		p.repo.GetSetUIDRequestHook,
+	)
+}
+
+func (p PlanBuilder) PlanForEventRequestStage(endpoint string, account *config.Account) Plan[hookstage.EventRequest] {
+	return getMergedPlan(
+		p.hooks,
+		account,
+		endpoint,
+		StageEventRequest,
+		p.repo.GetEventRequestHook,
+	)
+}
+
+func (p PlanBuilder) PlanForExitpointStage(endpoint string, account *config.Account) Plan[hookstage.Exitpoint] {
+	return getMergedPlan(
+		p.hooks,
+		account,
+		endpoint,
+		StageExitpoint,
+		p.repo.GetExitpointHook,
+	)
+}
+
+// NewReloadableExecutionPlanBuilder works like NewExecutionPlanBuilder, except that the returned builder
+// always uses the execution plans of cfg.Current(), so that plans swapped in by a config reload are used
+// from the next request on. Whether hooks are enabled at all is decided once, from cfg.
+func NewReloadableExecutionPlanBuilder(cfg *config.Configuration, repo HookRepository) ExecutionPlanBuilder {
+	if cfg.Hooks.Enabled {
+		return ReloadablePlanBuilder{
+			cfg:  cfg,
+			repo: repo,
+		}
+	}
+	return EmptyPlanBuilder{}
+}
+
+// ReloadablePlanBuilder is an ExecutionPlanBuilder which follows config reloads.
+type ReloadablePlanBuilder struct {
+	cfg  *config.Configuration
+	repo HookRepository
+}
+
+func (p ReloadablePlanBuilder) current() PlanBuilder {
+	return PlanBuilder{hooks: p.cfg.Current().Hooks, repo: p.repo}
+}
+
+func (p ReloadablePlanBuilder) PlanForEntrypointStage(endpoint string) Plan[hookstage.Entrypoint] {
+	return p.current().PlanForEntrypointStage(endpoint)
+}
+
+func (p ReloadablePlanBuilder) PlanForRawAuctionStage(endpoint string, account *config.Account) Plan[hookstage.RawAuctionRequest] {
+	return p.current().PlanForRawAuctionStage(endpoint, account)
+}
+
+func (p ReloadablePlanBuilder) PlanForProcessedAuctionStage(endpoint string, account *config.Account) Plan[hookstage.ProcessedAuctionRequest] {
+	return p.current().PlanForProcessedAuctionStage(endpoint, account)
+}
+
+func (p ReloadablePlanBuilder) PlanForBidderRequestStage(endpoint string, account *config.Account) Plan[hookstage.BidderRequest] {
+	return p.current().PlanForBidderRequestStage(endpoint, account)
+}
+
+func (p ReloadablePlanBuilder) PlanForRawBidderResponseStage(endpoint string, account *config.Account) Plan[hookstage.RawBidderResponse] {
+	return p.current().PlanForRawBidderResponseStage(endpoint, account)
+}
+
+func (p ReloadablePlanBuilder) PlanForAllProcessedBidResponsesStage(endpoint string, account *config.Account) Plan[hookstage.AllProcessedBidResponses] {
+	return p.current().PlanForAllProcessedBidResponsesStage(endpoint, account)
+}
+
+func (p ReloadablePlanBuilder) PlanForAuctionResponseStage(endpoint string, account *config.Account) Plan[hookstage.AuctionResponse] {
+	return p.current().PlanForAuctionResponseStage(endpoint, account)
+}
+
+func (p ReloadablePlanBuilder) PlanForCookieSyncRequestStage(endpoint string, account *config.Account) Plan[hookstage.CookieSyncRequest] {
+	return p.current().PlanForCookieSyncRequestStage(endpoint, account)
+}
+
+func (p ReloadablePlanBuilder) PlanForSetUIDRequestStage(endpoint string, account *config.Account) Plan[hookstage.SetUIDRequest] {
+	return p.current().PlanForSetUIDRequestStage(endpoint, account)
+}
+
+func (p ReloadablePlanBuilder) PlanForEventRequestStage(endpoint string, account *config.Account) Plan[hookstage.EventRequest] {
+	return p.current().PlanForEventRequestStage(endpoint, account)
+}
+
+func (p ReloadablePlanBuilder) PlanForExitpointStage(endpoint string, account *config.Account) Plan[hookstage.Exitpoint] {
+	return p.current().PlanForExitpointStage(endpoint, account)
+}
+
 type hookFn[T any] func(moduleName string) (T, bool)
 
 func getMergedPlan[T any](
@@ -35,6 +35,40 @@ func TestNewExecutionPlanBuilder(t *testing.T) {
 	}
 }
 
+func TestReloadablePlanBuilder(t *testing.T) {
+	const fooPlan string = `{"endpoints": {"/openrtb2/auction": {"stages": {"entrypoint": {"groups": [{"timeout": 5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}]}}}}}`
+	const barPlan string = `{"endpoints": {"/openrtb2/auction": {"stages": {"entrypoint": {"groups": [{"timeout": 5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "bar"}]}]}}}}}`
+
+	assert.Equal(t, EmptyPlanBuilder{}, NewReloadableExecutionPlanBuilder(&config.Configuration{}, nil))
+
+	repo, err := NewHookRepository(map[string]interface{}{"foobar": fakeEntrypointHook{}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	cfg := &config.Configuration{Hooks: config.Hooks{Enabled: true}}
+	next := &config.Configuration{Hooks: config.Hooks{Enabled: true}}
+	if !assert.NoError(t, jsonutil.UnmarshalValid([]byte(fooPlan), &cfg.Hooks.HostExecutionPlan)) ||
+		!assert.NoError(t, jsonutil.UnmarshalValid([]byte(barPlan), &next.Hooks.HostExecutionPlan)) {
+		return
+	}
+	cfg.EnableReload()
+	planBuilder := NewReloadableExecutionPlanBuilder(cfg, repo)
+
+	planHook := func() string {
+		plan := planBuilder.PlanForEntrypointStage("/openrtb2/auction")
+		if assert.Len(t, plan, 1) && assert.Len(t, plan[0].Hooks, 1) {
+			return plan[0].Hooks[0].Code
+		}
+		return ""
+	}
+
+	assert.Equal(t, "foo", planHook())
+	_, err = cfg.Reload(next)
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", planHook(), "the reloaded host plan should be used")
+}
+
 func TestPlanForEntrypointStage(t *testing.T) {
 	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
 	const group2 string = `{"timeout": 10, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "bar"}, {"module_code": "ortb2blocking", "hook_impl_code": "block_request"}]}`
@@ -770,6 +804,357 @@ func TestPlanForAuctionResponseStage(t *testing.T) {
 	}
 }
 
+func TestPlanForCookieSyncRequestStage(t *testing.T) {
+	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
+	const group2 string = `{"timeout": 10, "hook_sequence": [{"module_code": "prebid", "hook_impl_code": "bar"}]}`
+	const hostPlanData string = `{"endpoints": {"/cookie_sync": {"stages": {"cookie_sync_request": {"groups": [` + group1 + `]}}}}}`
+	const defaultAccountPlanData string = `{"endpoints": {"/cookie_sync": {"stages": {"cookie_sync_request": {"groups": [` + group1 + `]}}}, "/openrtb2/auction": {"stages": {"cookie_sync_request": {"groups": [` + group2 + `]}}}}}`
+	const accountPlanData string = `{"execution_plan": {"endpoints": {"/cookie_sync": {"stages": {"cookie_sync_request": {"groups": [` + group2 + `]}}}}}}`
+
+	hooks := map[string]interface{}{
+		"foobar": fakeCookieSyncRequestHook{},
+		"prebid": fakeCookieSyncRequestHook{},
+	}
+
+	testCases := map[string]struct {
+		givenEndpoint               string
+		givenHostPlanData           []byte
+		givenDefaultAccountPlanData []byte
+		giveAccountPlanData         []byte
+		givenHooks                  map[string]interface{}
+		expectedPlan                Plan[hookstage.CookieSyncRequest]
+	}{
+		"Account-specific execution plan rewrites default-account execution plan": {
+			givenEndpoint:               "/cookie_sync",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(defaultAccountPlanData),
+			giveAccountPlanData:         []byte(accountPlanData),
+			givenHooks:                  hooks,
+			expectedPlan: Plan[hookstage.CookieSyncRequest]{
+				Group[hookstage.CookieSyncRequest]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.CookieSyncRequest]{
+						{Module: "foobar", Code: "foo", Hook: fakeCookieSyncRequestHook{}},
+					},
+				},
+				Group[hookstage.CookieSyncRequest]{
+					Timeout: 10 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.CookieSyncRequest]{
+						{Module: "prebid", Code: "bar", Hook: fakeCookieSyncRequestHook{}},
+					},
+				},
+			},
+		},
+		"Works with empty account-specific execution plan": {
+			givenEndpoint:               "/cookie_sync",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(defaultAccountPlanData),
+			giveAccountPlanData:         []byte(`{}`),
+			givenHooks:                  hooks,
+			expectedPlan: Plan[hookstage.CookieSyncRequest]{
+				Group[hookstage.CookieSyncRequest]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.CookieSyncRequest]{
+						{Module: "foobar", Code: "foo", Hook: fakeCookieSyncRequestHook{}},
+					},
+				},
+				Group[hookstage.CookieSyncRequest]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.CookieSyncRequest]{
+						{Module: "foobar", Code: "foo", Hook: fakeCookieSyncRequestHook{}},
+					},
+				},
+			},
+		},
+		"Plan is empty if hooks not registered for stage": {
+			givenEndpoint:               "/cookie_sync",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(`{}`),
+			giveAccountPlanData:         []byte(`{}`),
+			givenHooks:                  map[string]interface{}{"foobar": fakeEntrypointHook{}},
+			expectedPlan:                Plan[hookstage.CookieSyncRequest]{},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			account := new(config.Account)
+			if err := jsonutil.UnmarshalValid(test.giveAccountPlanData, &account.Hooks); err != nil {
+				t.Fatal(err)
+			}
+
+			planBuilder, err := getPlanBuilder(test.givenHooks, test.givenHostPlanData, test.givenDefaultAccountPlanData)
+			if assert.NoError(t, err, "Failed to init hook execution plan builder") {
+				plan := planBuilder.PlanForCookieSyncRequestStage(test.givenEndpoint, account)
+				assert.Equal(t, test.expectedPlan, plan)
+			}
+		})
+	}
+}
+
+func TestPlanForSetUIDRequestStage(t *testing.T) {
+	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
+	const group2 string = `{"timeout": 10, "hook_sequence": [{"module_code": "prebid", "hook_impl_code": "bar"}]}`
+	const hostPlanData string = `{"endpoints": {"/setuid": {"stages": {"setuid_request": {"groups": [` + group1 + `]}}}}}`
+	const defaultAccountPlanData string = `{"endpoints": {"/setuid": {"stages": {"setuid_request": {"groups": [` + group1 + `]}}}, "/openrtb2/auction": {"stages": {"setuid_request": {"groups": [` + group2 + `]}}}}}`
+	const accountPlanData string = `{"execution_plan": {"endpoints": {"/setuid": {"stages": {"setuid_request": {"groups": [` + group2 + `]}}}}}}`
+
+	hooks := map[string]interface{}{
+		"foobar": fakeSetUIDRequestHook{},
+		"prebid": fakeSetUIDRequestHook{},
+	}
+
+	testCases := map[string]struct {
+		givenEndpoint               string
+		givenHostPlanData           []byte
+		givenDefaultAccountPlanData []byte
+		giveAccountPlanData         []byte
+		givenHooks                  map[string]interface{}
+		expectedPlan                Plan[hookstage.SetUIDRequest]
+	}{
+		"Account-specific execution plan rewrites default-account execution plan": {
+			givenEndpoint:               "/setuid",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(defaultAccountPlanData),
+			giveAccountPlanData:         []byte(accountPlanData),
+			givenHooks:                  hooks,
+			expectedPlan: Plan[hookstage.SetUIDRequest]{
+				Group[hookstage.SetUIDRequest]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.SetUIDRequest]{
+						{Module: "foobar", Code: "foo", Hook: fakeSetUIDRequestHook{}},
+					},
+				},
+				Group[hookstage.SetUIDRequest]{
+					Timeout: 10 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.SetUIDRequest]{
+						{Module: "prebid", Code: "bar", Hook: fakeSetUIDRequestHook{}},
+					},
+				},
+			},
+		},
+		"Works with empty account-specific execution plan": {
+			givenEndpoint:               "/setuid",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(defaultAccountPlanData),
+			giveAccountPlanData:         []byte(`{}`),
+			givenHooks:                  hooks,
+			expectedPlan: Plan[hookstage.SetUIDRequest]{
+				Group[hookstage.SetUIDRequest]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.SetUIDRequest]{
+						{Module: "foobar", Code: "foo", Hook: fakeSetUIDRequestHook{}},
+					},
+				},
+				Group[hookstage.SetUIDRequest]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.SetUIDRequest]{
+						{Module: "foobar", Code: "foo", Hook: fakeSetUIDRequestHook{}},
+					},
+				},
+			},
+		},
+		"Plan is empty if hooks not registered for stage": {
+			givenEndpoint:               "/setuid",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(`{}`),
+			giveAccountPlanData:         []byte(`{}`),
+			givenHooks:                  map[string]interface{}{"foobar": fakeEntrypointHook{}},
+			expectedPlan:                Plan[hookstage.SetUIDRequest]{},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			account := new(config.Account)
+			if err := jsonutil.UnmarshalValid(test.giveAccountPlanData, &account.Hooks); err != nil {
+				t.Fatal(err)
+			}
+
+			planBuilder, err := getPlanBuilder(test.givenHooks, test.givenHostPlanData, test.givenDefaultAccountPlanData)
+			if assert.NoError(t, err, "Failed to init hook execution plan builder") {
+				plan := planBuilder.PlanForSetUIDRequestStage(test.givenEndpoint, account)
+				assert.Equal(t, test.expectedPlan, plan)
+			}
+		})
+	}
+}
+
+func TestPlanForEventRequestStage(t *testing.T) {
+	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
+	const group2 string = `{"timeout": 10, "hook_sequence": [{"module_code": "prebid", "hook_impl_code": "bar"}]}`
+	const hostPlanData string = `{"endpoints": {"/event": {"stages": {"event_request": {"groups": [` + group1 + `]}}}}}`
+	const defaultAccountPlanData string = `{"endpoints": {"/event": {"stages": {"event_request": {"groups": [` + group1 + `]}}}, "/openrtb2/auction": {"stages": {"event_request": {"groups": [` + group2 + `]}}}}}`
+	const accountPlanData string = `{"execution_plan": {"endpoints": {"/event": {"stages": {"event_request": {"groups": [` + group2 + `]}}}}}}`
+
+	hooks := map[string]interface{}{
+		"foobar": fakeEventRequestHook{},
+		"prebid": fakeEventRequestHook{},
+	}
+
+	testCases := map[string]struct {
+		givenEndpoint               string
+		givenHostPlanData           []byte
+		givenDefaultAccountPlanData []byte
+		giveAccountPlanData         []byte
+		givenHooks                  map[string]interface{}
+		expectedPlan                Plan[hookstage.EventRequest]
+	}{
+		"Account-specific execution plan rewrites default-account execution plan": {
+			givenEndpoint:               "/event",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(defaultAccountPlanData),
+			giveAccountPlanData:         []byte(accountPlanData),
+			givenHooks:                  hooks,
+			expectedPlan: Plan[hookstage.EventRequest]{
+				Group[hookstage.EventRequest]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.EventRequest]{
+						{Module: "foobar", Code: "foo", Hook: fakeEventRequestHook{}},
+					},
+				},
+				Group[hookstage.EventRequest]{
+					Timeout: 10 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.EventRequest]{
+						{Module: "prebid", Code: "bar", Hook: fakeEventRequestHook{}},
+					},
+				},
+			},
+		},
+		"Works with empty account-specific execution plan": {
+			givenEndpoint:               "/event",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(defaultAccountPlanData),
+			giveAccountPlanData:         []byte(`{}`),
+			givenHooks:                  hooks,
+			expectedPlan: Plan[hookstage.EventRequest]{
+				Group[hookstage.EventRequest]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.EventRequest]{
+						{Module: "foobar", Code: "foo", Hook: fakeEventRequestHook{}},
+					},
+				},
+				Group[hookstage.EventRequest]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.EventRequest]{
+						{Module: "foobar", Code: "foo", Hook: fakeEventRequestHook{}},
+					},
+				},
+			},
+		},
+		"Plan is empty if hooks not registered for stage": {
+			givenEndpoint:               "/event",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(`{}`),
+			giveAccountPlanData:         []byte(`{}`),
+			givenHooks:                  map[string]interface{}{"foobar": fakeEntrypointHook{}},
+			expectedPlan:                Plan[hookstage.EventRequest]{},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			account := new(config.Account)
+			if err := jsonutil.UnmarshalValid(test.giveAccountPlanData, &account.Hooks); err != nil {
+				t.Fatal(err)
+			}
+
+			planBuilder, err := getPlanBuilder(test.givenHooks, test.givenHostPlanData, test.givenDefaultAccountPlanData)
+			if assert.NoError(t, err, "Failed to init hook execution plan builder") {
+				plan := planBuilder.PlanForEventRequestStage(test.givenEndpoint, account)
+				assert.Equal(t, test.expectedPlan, plan)
+			}
+		})
+	}
+}
+func TestPlanForExitpointStage(t *testing.T) {
+	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
+	const group2 string = `{"timeout": 10, "hook_sequence": [{"module_code": "prebid", "hook_impl_code": "bar"}]}`
+	const hostPlanData string = `{"endpoints": {"/openrtb2/auction": {"stages": {"exitpoint": {"groups": [` + group1 + `]}}}}}`
+	const defaultAccountPlanData string = `{"endpoints": {"/openrtb2/auction": {"stages": {"exitpoint": {"groups": [` + group1 + `]}}}, "/openrtb2/amp": {"stages": {"exitpoint": {"groups": [` + group2 + `]}}}}}`
+	const accountPlanData string = `{"execution_plan": {"endpoints": {"/openrtb2/auction": {"stages": {"exitpoint": {"groups": [` + group2 + `]}}}}}}`
+
+	hooks := map[string]interface{}{
+		"foobar": fakeExitpointHook{},
+		"prebid": fakeExitpointHook{},
+	}
+
+	testCases := map[string]struct {
+		givenEndpoint               string
+		givenHostPlanData           []byte
+		givenDefaultAccountPlanData []byte
+		giveAccountPlanData         []byte
+		givenHooks                  map[string]interface{}
+		expectedPlan                Plan[hookstage.Exitpoint]
+	}{
+		"Account-specific execution plan rewrites default-account execution plan": {
+			givenEndpoint:               "/openrtb2/auction",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(defaultAccountPlanData),
+			giveAccountPlanData:         []byte(accountPlanData),
+			givenHooks:                  hooks,
+			expectedPlan: Plan[hookstage.Exitpoint]{
+				Group[hookstage.Exitpoint]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.Exitpoint]{
+						{Module: "foobar", Code: "foo", Hook: fakeExitpointHook{}},
+					},
+				},
+				Group[hookstage.Exitpoint]{
+					Timeout: 10 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.Exitpoint]{
+						{Module: "prebid", Code: "bar", Hook: fakeExitpointHook{}},
+					},
+				},
+			},
+		},
+		"Works with empty account-specific execution plan": {
+			givenEndpoint:               "/openrtb2/auction",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(defaultAccountPlanData),
+			giveAccountPlanData:         []byte(`{}`),
+			givenHooks:                  hooks,
+			expectedPlan: Plan[hookstage.Exitpoint]{
+				Group[hookstage.Exitpoint]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.Exitpoint]{
+						{Module: "foobar", Code: "foo", Hook: fakeExitpointHook{}},
+					},
+				},
+				Group[hookstage.Exitpoint]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.Exitpoint]{
+						{Module: "foobar", Code: "foo", Hook: fakeExitpointHook{}},
+					},
+				},
+			},
+		},
+		"Plan is empty if hooks not registered for stage": {
+			givenEndpoint:               "/openrtb2/auction",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(`{}`),
+			giveAccountPlanData:         []byte(`{}`),
+			givenHooks:                  map[string]interface{}{"foobar": fakeEntrypointHook{}},
+			expectedPlan:                Plan[hookstage.Exitpoint]{},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			account := new(config.Account)
+			if err := jsonutil.UnmarshalValid(test.giveAccountPlanData, &account.Hooks); err != nil {
+				t.Fatal(err)
+			}
+
+			planBuilder, err := getPlanBuilder(test.givenHooks, test.givenHostPlanData, test.givenDefaultAccountPlanData)
+			if assert.NoError(t, err, "Failed to init hook execution plan builder") {
+				plan := planBuilder.PlanForExitpointStage(test.givenEndpoint, account)
+				assert.Equal(t, test.expectedPlan, plan)
+			}
+		})
+	}
+}
+
 func getPlanBuilder(
 	moduleHooks map[string]interface{},
 	hostPlanData, accountPlanData []byte,
@@ -870,3 +1255,43 @@ func (f fakeAuctionResponseHook) HandleAuctionResponseHook(
 ) (hookstage.HookResult[hookstage.AuctionResponsePayload], error) {
 	return hookstage.HookResult[hookstage.AuctionResponsePayload]{}, nil
 }
+
+type fakeCookieSyncRequestHook struct{}
+
+func (f fakeCookieSyncRequestHook) HandleCookieSyncRequestHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.CookieSyncRequestPayload,
+) (hookstage.HookResult[hookstage.CookieSyncRequestPayload], error) {
+	return hookstage.HookResult[hookstage.CookieSyncRequestPayload]{}, nil
+}
+
+type fakeSetUIDRequestHook struct{}
+
+func (f fakeSetUIDRequestHook) HandleSetUIDRequestHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.SetUIDRequestPayload,
+) (hookstage.HookResult[hookstage.SetUIDRequestPayload], error) {
+	return hookstage.HookResult[hookstage.SetUIDRequestPayload]{}, nil
+}
+
+type fakeEventRequestHook struct{}
+
+func (f fakeEventRequestHook) HandleEventRequestHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.EventRequestPayload,
+) (hookstage.HookResult[hookstage.EventRequestPayload], error) {
+	return hookstage.HookResult[hookstage.EventRequestPayload]{}, nil
+}
+
+type fakeExitpointHook struct{}
+
+func (f fakeExitpointHook) HandleExitpointHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.ExitpointPayload,
+) (hookstage.HookResult[hookstage.ExitpointPayload], error) {
+	return hookstage.HookResult[hookstage.ExitpointPayload]{}, nil
+}
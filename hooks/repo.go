@@ -21,6 +21,10 @@ type HookRepository interface {
 	GetRawBidderResponseHook(id string) (hookstage.RawBidderResponse, bool)
 	GetAllProcessedBidResponsesHook(id string) (hookstage.AllProcessedBidResponses, bool)
 	GetAuctionResponseHook(id string) (hookstage.AuctionResponse, bool)
+	GetCookieSyncRequestHook(id string) (hookstage.CookieSyncRequest, bool)
+	GetSetUIDRequestHook(id string) (hookstage.SetUIDRequest, bool)
+	GetEventRequestHook(id string) (hookstage.EventRequest, bool)
+	GetExitpointHook(id string) (hookstage.Exitpoint, bool)
 }
 
 // NewHookRepository returns a new instance of the HookRepository interface.
@@ -49,6 +53,10 @@ type hookRepository struct {
 	rawBidderResponseHooks       map[string]hookstage.RawBidderResponse
 	allProcessedBidResponseHooks map[string]hookstage.AllProcessedBidResponses
 	auctionResponseHooks         map[string]hookstage.AuctionResponse
+	cookieSyncRequestHooks       map[string]hookstage.CookieSyncRequest
+	setUIDRequestHooks           map[string]hookstage.SetUIDRequest
+	eventRequestHooks            map[string]hookstage.EventRequest
+	exitpointHooks               map[string]hookstage.Exitpoint
 }
 
 func (r *hookRepository) GetEntrypointHook(id string) (hookstage.Entrypoint, bool) {
@@ -79,6 +87,22 @@ func (r *hookRepository) GetAuctionResponseHook(id string) (hookstage.AuctionRes
 	return getHook(r.auctionResponseHooks, id)
 }
 
+func (r *hookRepository) GetCookieSyncRequestHook(id string) (hookstage.CookieSyncRequest, bool) {
+	return getHook(r.cookieSyncRequestHooks, id)
+}
+
+func (r *hookRepository) GetSetUIDRequestHook(id string) (hookstage.SetUIDRequest, bool) {
+	return getHook(r.setUIDRequestHooks, id)
+}
+
+func (r *hookRepository) GetEventRequestHook(id string) (hookstage.EventRequest, bool) {
+	return getHook(r.eventRequestHooks, id)
+}
+
+func (r *hookRepository) GetExitpointHook(id string) (hookstage.Exitpoint, bool) {
+	return getHook(r.exitpointHooks, id)
+}
+
 func (r *hookRepository) add(id string, hook interface{}) error {
 	var hasAnyHooks bool
 	var err error
@@ -132,6 +156,34 @@ func (r *hookRepository) add(id string, hook interface{}) error {
 		}
 	}
 
+	if h, ok := hook.(hookstage.CookieSyncRequest); ok {
+		hasAnyHooks = true
+		if r.cookieSyncRequestHooks, err = addHook(r.cookieSyncRequestHooks, h, id); err != nil {
+			return err
+		}
+	}
+
+	if h, ok := hook.(hookstage.SetUIDRequest); ok {
+		hasAnyHooks = true
+		if r.setUIDRequestHooks, err = addHook(r.setUIDRequestHooks, h, id); err != nil {
+			return err
+		}
+	}
+
+	if h, ok := hook.(hookstage.EventRequest); ok {
+		hasAnyHooks = true
+		if r.eventRequestHooks, err = addHook(r.eventRequestHooks, h, id); err != nil {
+			return err
+		}
+	}
+
+	if h, ok := hook.(hookstage.Exitpoint); ok {
+		hasAnyHooks = true
+		if r.exitpointHooks, err = addHook(r.exitpointHooks, h, id); err != nil {
+			return err
+		}
+	}
+
 	if !hasAnyHooks {
 		return fmt.Errorf(`hook "%s" does not implement any supported hook interface`, id)
 	}
@@ -28,6 +28,42 @@ func TestNewHookRepository(t *testing.T) {
 				return repo.GetEntrypointHook(id)
 			},
 		},
+		"Added cookie sync request hook returns": {
+			isFound:      true,
+			providedHook: userSyncHook{},
+			expectedHook: userSyncHook{},
+			expectedErr:  nil,
+			getHookFn: func(repo HookRepository) (interface{}, bool) {
+				return repo.GetCookieSyncRequestHook(id)
+			},
+		},
+		"Added setuid request hook returns": {
+			isFound:      true,
+			providedHook: userSyncHook{},
+			expectedHook: userSyncHook{},
+			expectedErr:  nil,
+			getHookFn: func(repo HookRepository) (interface{}, bool) {
+				return repo.GetSetUIDRequestHook(id)
+			},
+		},
+		"Added event request hook returns": {
+			isFound:      true,
+			providedHook: userSyncHook{},
+			expectedHook: userSyncHook{},
+			expectedErr:  nil,
+			getHookFn: func(repo HookRepository) (interface{}, bool) {
+				return repo.GetEventRequestHook(id)
+			},
+		},
+		"Added exitpoint hook returns": {
+			isFound:      true,
+			providedHook: exitpointHook{},
+			expectedHook: exitpointHook{},
+			expectedErr:  nil,
+			getHookFn: func(repo HookRepository) (interface{}, bool) {
+				return repo.GetExitpointHook(id)
+			},
+		},
 		"Not found hook": {
 			isFound:      false,
 			providedHook: hook{},
@@ -74,3 +110,23 @@ type hook struct{}
 func (h hook) HandleEntrypointHook(ctx context.Context, context hookstage.ModuleInvocationContext, payload hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
 	return hookstage.HookResult[hookstage.EntrypointPayload]{}, nil
 }
+
+type userSyncHook struct{}
+
+func (h userSyncHook) HandleCookieSyncRequestHook(ctx context.Context, context hookstage.ModuleInvocationContext, payload hookstage.CookieSyncRequestPayload) (hookstage.HookResult[hookstage.CookieSyncRequestPayload], error) {
+	return hookstage.HookResult[hookstage.CookieSyncRequestPayload]{}, nil
+}
+
+func (h userSyncHook) HandleSetUIDRequestHook(ctx context.Context, context hookstage.ModuleInvocationContext, payload hookstage.SetUIDRequestPayload) (hookstage.HookResult[hookstage.SetUIDRequestPayload], error) {
+	return hookstage.HookResult[hookstage.SetUIDRequestPayload]{}, nil
+}
+
+func (h userSyncHook) HandleEventRequestHook(ctx context.Context, context hookstage.ModuleInvocationContext, payload hookstage.EventRequestPayload) (hookstage.HookResult[hookstage.EventRequestPayload], error) {
+	return hookstage.HookResult[hookstage.EventRequestPayload]{}, nil
+}
+
+type exitpointHook struct{}
+
+func (h exitpointHook) HandleExitpointHook(ctx context.Context, context hookstage.ModuleInvocationContext, payload hookstage.ExitpointPayload) (hookstage.HookResult[hookstage.ExitpointPayload], error) {
+	return hookstage.HookResult[hookstage.ExitpointPayload]{}, nil
+}
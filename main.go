@@ -3,8 +3,11 @@ package main
 import (
 	"flag"
 	"net/http"
+	"os"
+	"os/signal"
 	"path/filepath"
 	"runtime"
+	"syscall"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
@@ -49,7 +52,11 @@ func main() {
 	garbageCollectionThreshold := make([]byte, cfg.GarbageCollectorThreshold)
 	defer runtime.KeepAlive(garbageCollectionThreshold)
 
-	err = serve(cfg)
+	cfg.EnableReload()
+	reload := configReloader(cfg, bidderInfos)
+	reloadOnSignal(reload)
+
+	err = serve(cfg, reload)
 	if err != nil {
 		glog.Exitf("prebid-server failed: %v", err)
 	}
@@ -64,7 +71,37 @@ func loadConfig(bidderInfos config.BidderInfos) (*config.Configuration, error) {
 	return config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
 }
 
-func serve(cfg *config.Configuration) error {
+// configReloader returns a function which loads and validates the config again, and swaps the keys which
+// may change without a restart into cfg.
+func configReloader(cfg *config.Configuration, bidderInfos config.BidderInfos) func() (config.ReloadReport, error) {
+	return func() (config.ReloadReport, error) {
+		next, err := loadConfig(bidderInfos)
+		if err != nil {
+			glog.Errorf("Configuration could not be reloaded or did not pass validation: %v", err)
+			return config.ReloadReport{}, err
+		}
+		report, err := cfg.Reload(next)
+		if err != nil {
+			glog.Errorf("Configuration could not be reloaded: %v", err)
+			return config.ReloadReport{}, err
+		}
+		glog.Infof("Configuration reloaded. Applied: %v. Ignored until restart: %v", report.Applied, report.RestartRequired)
+		return report, nil
+	}
+}
+
+// reloadOnSignal reloads the config each time the process receives SIGHUP.
+func reloadOnSignal(reload func() (config.ReloadReport, error)) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			reload()
+		}
+	}()
+}
+
+func serve(cfg *config.Configuration, reload func() (config.ReloadReport, error)) error {
 	fetchingInterval := time.Duration(cfg.CurrencyConverter.FetchIntervalSeconds) * time.Second
 	staleRatesThreshold := time.Duration(cfg.CurrencyConverter.StaleRatesSeconds) * time.Second
 	currencyConverter := currency.NewRateConverter(&http.Client{}, cfg.CurrencyConverter.FetchURL, staleRatesThreshold)
@@ -78,7 +115,7 @@ func serve(cfg *config.Configuration) error {
 	}
 
 	corsRouter := router.SupportCORS(r)
-	if err := server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(currencyConverter, fetchingInterval), r.MetricsEngine); err != nil {
+	if err := server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(currencyConverter, fetchingInterval, reload, r.StoredDataAdmin, r.FloorsReporter, r.UIDStore, r.ConsentDiagnostics), r.GRPCAuction, r.MetricsEngine); err != nil {
 		glog.Fatalf("prebid-server returned an error: %v", err)
 	}
 
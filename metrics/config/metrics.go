@@ -280,6 +280,20 @@ func (me *MultiMetricsEngine) RecordAdapterGDPRRequestBlocked(adapter openrtb_ex
 	}
 }
 
+// RecordAdapterCircuitBreakerState across all engines
+func (me *MultiMetricsEngine) RecordAdapterCircuitBreakerState(adapter openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterCircuitBreakerState(adapter, state)
+	}
+}
+
+// RecordAdapterCircuitBreakerRejected across all engines
+func (me *MultiMetricsEngine) RecordAdapterCircuitBreakerRejected(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterCircuitBreakerRejected(adapter)
+	}
+}
+
 // RecordDebugRequest across all engines
 func (me *MultiMetricsEngine) RecordDebugRequest(debugEnabled bool, pubId string) {
 	for _, thisME := range *me {
@@ -329,6 +343,18 @@ func (me *MultiMetricsEngine) RecordBidValidationSecureMarkupWarn(adapter openrt
 	}
 }
 
+func (me *MultiMetricsEngine) RecordBidValidationConformanceError(adapter openrtb_ext.BidderName, account string) {
+	for _, thisME := range *me {
+		thisME.RecordBidValidationConformanceError(adapter, account)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordBidValidationConformanceWarn(adapter openrtb_ext.BidderName, account string) {
+	for _, thisME := range *me {
+		thisME.RecordBidValidationConformanceWarn(adapter, account)
+	}
+}
+
 func (me *MultiMetricsEngine) RecordModuleCalled(labels metrics.ModuleLabels, duration time.Duration) {
 	for _, thisME := range *me {
 		thisME.RecordModuleCalled(labels, duration)
@@ -371,6 +397,41 @@ func (me *MultiMetricsEngine) RecordModuleTimeout(labels metrics.ModuleLabels) {
 	}
 }
 
+// RecordFloorsCurrencyConversionError across all engines
+func (me *MultiMetricsEngine) RecordFloorsCurrencyConversionError(source metrics.FloorsConversionSource) {
+	for _, thisME := range *me {
+		thisME.RecordFloorsCurrencyConversionError(source)
+	}
+}
+
+// RecordFloorsModelOutcome across all engines
+func (me *MultiMetricsEngine) RecordFloorsModelOutcome(modelVersion string, outcome metrics.FloorsModelOutcome, count int) {
+	for _, thisME := range *me {
+		thisME.RecordFloorsModelOutcome(modelVersion, outcome, count)
+	}
+}
+
+// RecordAnalyticsEventDropped across all engines
+func (me *MultiMetricsEngine) RecordAnalyticsEventDropped(module string) {
+	for _, thisME := range *me {
+		thisME.RecordAnalyticsEventDropped(module)
+	}
+}
+
+// RecordAnalyticsBufferedEvents across all engines
+func (me *MultiMetricsEngine) RecordAnalyticsBufferedEvents(module string, count int) {
+	for _, thisME := range *me {
+		thisME.RecordAnalyticsBufferedEvents(module, count)
+	}
+}
+
+// RecordVendorList across all engines
+func (me *MultiMetricsEngine) RecordVendorList(specVersion uint16, listVersion uint16, lastUpdated time.Time) {
+	for _, thisME := range *me {
+		thisME.RecordVendorList(specVersion, listVersion, lastUpdated)
+	}
+}
+
 // NilMetricsEngine implements the MetricsEngine interface where no metrics are actually captured. This is
 // used if no metric backend is configured and also for tests.
 type NilMetricsEngine struct{}
@@ -499,6 +560,14 @@ func (me *NilMetricsEngine) RecordAdapterBuyerUIDScrubbed(adapter openrtb_ext.Bi
 func (me *NilMetricsEngine) RecordAdapterGDPRRequestBlocked(adapter openrtb_ext.BidderName) {
 }
 
+// RecordAdapterCircuitBreakerState as a noop
+func (me *NilMetricsEngine) RecordAdapterCircuitBreakerState(adapter openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+}
+
+// RecordAdapterCircuitBreakerRejected as a noop
+func (me *NilMetricsEngine) RecordAdapterCircuitBreakerRejected(adapter openrtb_ext.BidderName) {
+}
+
 // RecordDebugRequest as a noop
 func (me *NilMetricsEngine) RecordDebugRequest(debugEnabled bool, pubId string) {
 }
@@ -526,6 +595,12 @@ func (me *NilMetricsEngine) RecordBidValidationSecureMarkupError(adapter openrtb
 func (me *NilMetricsEngine) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.BidderName, account string) {
 }
 
+func (me *NilMetricsEngine) RecordBidValidationConformanceError(adapter openrtb_ext.BidderName, account string) {
+}
+
+func (me *NilMetricsEngine) RecordBidValidationConformanceWarn(adapter openrtb_ext.BidderName, account string) {
+}
+
 func (me *NilMetricsEngine) RecordModuleCalled(labels metrics.ModuleLabels, duration time.Duration) {
 }
 
@@ -546,3 +621,23 @@ func (me *NilMetricsEngine) RecordModuleExecutionError(labels metrics.ModuleLabe
 
 func (me *NilMetricsEngine) RecordModuleTimeout(labels metrics.ModuleLabels) {
 }
+
+// RecordFloorsCurrencyConversionError as a noop
+func (me *NilMetricsEngine) RecordFloorsCurrencyConversionError(source metrics.FloorsConversionSource) {
+}
+
+// RecordFloorsModelOutcome as a noop
+func (me *NilMetricsEngine) RecordFloorsModelOutcome(modelVersion string, outcome metrics.FloorsModelOutcome, count int) {
+}
+
+// RecordAnalyticsEventDropped as a noop
+func (me *NilMetricsEngine) RecordAnalyticsEventDropped(module string) {
+}
+
+// RecordAnalyticsBufferedEvents as a noop
+func (me *NilMetricsEngine) RecordAnalyticsBufferedEvents(module string, count int) {
+}
+
+// RecordVendorList as a noop
+func (me *NilMetricsEngine) RecordVendorList(specVersion uint16, listVersion uint16, lastUpdated time.Time) {
+}
@@ -142,6 +142,9 @@ func TestMultiMetricsEngine(t *testing.T) {
 
 	metricsEngine.RecordAdapterBuyerUIDScrubbed(openrtb_ext.BidderAppnexus)
 	metricsEngine.RecordAdapterGDPRRequestBlocked(openrtb_ext.BidderAppnexus)
+	metricsEngine.RecordAdapterCircuitBreakerState(openrtb_ext.BidderAppnexus, metrics.CircuitBreakerOpen)
+	metricsEngine.RecordAdapterCircuitBreakerRejected(openrtb_ext.BidderAppnexus)
+	metricsEngine.RecordAnalyticsEventDropped("eventlog")
 
 	metricsEngine.RecordRequestQueueTime(false, metrics.ReqTypeVideo, time.Duration(1))
 
@@ -188,6 +191,9 @@ func TestMultiMetricsEngine(t *testing.T) {
 
 	VerifyMetrics(t, "AdapterMetrics.appNexus.BuyerUIDScrubbed", goEngine.AdapterMetrics[strings.ToLower(string(openrtb_ext.BidderAppnexus))].BuyerUIDScrubbed.Count(), 1)
 	VerifyMetrics(t, "AdapterMetrics.appNexus.GDPRRequestBlocked", goEngine.AdapterMetrics[strings.ToLower(string(openrtb_ext.BidderAppnexus))].GDPRRequestBlocked.Count(), 1)
+	VerifyMetrics(t, "AdapterMetrics.appNexus.CircuitBreakerState", goEngine.AdapterMetrics[strings.ToLower(string(openrtb_ext.BidderAppnexus))].CircuitBreakerState.Value(), 2)
+	VerifyMetrics(t, "AdapterMetrics.appNexus.CircuitBreakerRejected", goEngine.AdapterMetrics[strings.ToLower(string(openrtb_ext.BidderAppnexus))].CircuitBreakerRejectedMeter.Count(), 1)
+	VerifyMetrics(t, "Analytics.eventlog.EventsDropped", goEngine.MetricsRegistry.Get("analytics.eventlog.events_dropped").(gometrics.Meter).Count(), 1)
 
 	// verify that each module has its own metric recorded
 	for module, stages := range modulesStages {
@@ -46,6 +46,9 @@ type Metrics struct {
 	SetUidStatusMeter     map[SetUidStatus]metrics.Meter
 	SyncerSetsMeter       map[string]map[SyncerSetUidStatus]metrics.Meter
 
+	// Price floors currency conversion failures
+	FloorsCurrencyConversionErrorMeter map[FloorsConversionSource]metrics.Meter
+
 	// Media types found in the "imp" JSON object
 	ImpsTypeBanner metrics.Meter
 	ImpsTypeVideo  metrics.Meter
@@ -102,11 +105,17 @@ type AdapterMetrics struct {
 	BuyerUIDScrubbed   metrics.Meter
 	GDPRRequestBlocked metrics.Meter
 
+	CircuitBreakerState         metrics.Gauge
+	CircuitBreakerRejectedMeter metrics.Meter
+
 	BidValidationCreativeSizeErrorMeter metrics.Meter
 	BidValidationCreativeSizeWarnMeter  metrics.Meter
 
 	BidValidationSecureMarkupErrorMeter metrics.Meter
 	BidValidationSecureMarkupWarnMeter  metrics.Meter
+
+	BidValidationConformanceErrorMeter metrics.Meter
+	BidValidationConformanceWarnMeter  metrics.Meter
 }
 
 type MarkupDeliveryMetrics struct {
@@ -128,6 +137,8 @@ type accountMetrics struct {
 	bidValidationCreativeSizeWarnMeter metrics.Meter
 	bidValidationSecureMarkupMeter     metrics.Meter
 	bidValidationSecureMarkupWarnMeter metrics.Meter
+	bidValidationConformanceMeter      metrics.Meter
+	bidValidationConformanceWarnMeter  metrics.Meter
 }
 
 type ModuleMetrics struct {
@@ -182,6 +193,8 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		SyncerSetsMeter:                make(map[string]map[SyncerSetUidStatus]metrics.Meter),
 		StoredResponsesMeter:           blankMeter,
 
+		FloorsCurrencyConversionErrorMeter: make(map[FloorsConversionSource]metrics.Meter),
+
 		ImpsTypeBanner: blankMeter,
 		ImpsTypeVideo:  blankMeter,
 		ImpsTypeAudio:  blankMeter,
@@ -238,6 +251,10 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		newMetrics.PrivacyTCFRequestVersion[v] = blankMeter
 	}
 
+	for _, s := range FloorsConversionSources() {
+		newMetrics.FloorsCurrencyConversionErrorMeter[s] = blankMeter
+	}
+
 	for _, dt := range StoredDataTypes() {
 		newMetrics.StoredDataFetchTimer[dt] = make(map[StoredDataFetchType]metrics.Timer)
 		newMetrics.StoredDataErrorMeter[dt] = make(map[StoredDataError]metrics.Meter)
@@ -326,6 +343,10 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 		newMetrics.SetUidStatusMeter[s] = metrics.GetOrRegisterMeter(fmt.Sprintf("setuid_requests.%s", s), registry)
 	}
 
+	for _, s := range FloorsConversionSources() {
+		newMetrics.FloorsCurrencyConversionErrorMeter[s] = metrics.GetOrRegisterMeter(fmt.Sprintf("floors.currency_conversion_err.%s", s), registry)
+	}
+
 	for _, syncerKey := range syncerKeys {
 		newMetrics.SyncerRequestsMeter[syncerKey] = make(map[SyncerCookieSyncStatus]metrics.Meter)
 		for _, status := range SyncerRequestStatuses() {
@@ -401,6 +422,9 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 		BidsReceivedMeter: blankMeter,
 		PanicMeter:        blankMeter,
 		MarkupMetrics:     makeBlankBidMarkupMetrics(),
+
+		CircuitBreakerState:         metrics.NilGauge{},
+		CircuitBreakerRejectedMeter: blankMeter,
 	}
 	if !disabledMetrics.AdapterConnectionMetrics {
 		newAdapter.ConnCreated = metrics.NilCounter{}
@@ -486,6 +510,9 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 	}
 	if adapterOrAccount != "adapter" {
 		am.BidsReceivedMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.bids_received", adapterOrAccount, exchange), registry)
+	} else {
+		am.CircuitBreakerState = metrics.GetOrRegisterGauge(fmt.Sprintf("%[1]s.%[2]s.circuit_breaker.state", adapterOrAccount, exchange), registry)
+		am.CircuitBreakerRejectedMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.circuit_breaker.rejected", adapterOrAccount, exchange), registry)
 	}
 	am.PanicMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.requests.panic", adapterOrAccount, exchange), registry)
 	am.BuyerUIDScrubbed = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.buyeruid_scrubbed", adapterOrAccount, exchange), registry)
@@ -496,6 +523,9 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 
 	am.BidValidationSecureMarkupErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.secure.err", adapterOrAccount, exchange), registry)
 	am.BidValidationSecureMarkupWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.secure.warn", adapterOrAccount, exchange), registry)
+
+	am.BidValidationConformanceErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.conformance.err", adapterOrAccount, exchange), registry)
+	am.BidValidationConformanceWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.conformance.warn", adapterOrAccount, exchange), registry)
 }
 
 func registerModuleMetrics(registry metrics.Registry, module string, stages []string, mm map[string]*ModuleMetrics) {
@@ -572,6 +602,9 @@ func (me *Metrics) getAccountMetrics(id string) *accountMetrics {
 	am.bidValidationSecureMarkupMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("account.%s.response.validation.secure.err", id), me.MetricsRegistry)
 	am.bidValidationSecureMarkupWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("account.%s.response.validation.secure.warn", id), me.MetricsRegistry)
 
+	am.bidValidationConformanceMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("account.%s.response.validation.conformance.err", id), me.MetricsRegistry)
+	am.bidValidationConformanceWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("account.%s.response.validation.conformance.warn", id), me.MetricsRegistry)
+
 	if !me.MetricsDisabled.AccountModulesMetrics {
 		for _, mod := range me.modules {
 			am.moduleMetrics[mod] = makeBlankModuleMetrics()
@@ -961,6 +994,30 @@ func (me *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.Bidde
 	am.GDPRRequestBlocked.Mark(1)
 }
 
+// RecordAdapterCircuitBreakerState implements a part of the MetricsEngine interface
+func (me *Metrics) RecordAdapterCircuitBreakerState(adapterName openrtb_ext.BidderName, state CircuitBreakerState) {
+	adapterStr := string(adapterName)
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		glog.Errorf("Trying to log adapter circuit breaker state metric for %s: adapter not found", adapterStr)
+		return
+	}
+
+	am.CircuitBreakerState.Update(int64(state))
+}
+
+// RecordAdapterCircuitBreakerRejected implements a part of the MetricsEngine interface
+func (me *Metrics) RecordAdapterCircuitBreakerRejected(adapterName openrtb_ext.BidderName) {
+	adapterStr := string(adapterName)
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		glog.Errorf("Trying to log adapter circuit breaker rejected metric for %s: adapter not found", adapterStr)
+		return
+	}
+
+	am.CircuitBreakerRejectedMeter.Mark(1)
+}
+
 func (me *Metrics) RecordAdsCertReq(success bool) {
 	if success {
 		me.AdsCertRequestsSuccess.Mark(1)
@@ -1033,6 +1090,43 @@ func (me *Metrics) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.Bidde
 	}
 }
 
+func (me *Metrics) RecordBidValidationConformanceError(adapter openrtb_ext.BidderName, pubID string) {
+	adapterStr := string(adapter)
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
+		return
+	}
+	am.BidValidationConformanceErrorMeter.Mark(1)
+
+	aam := me.getAccountMetrics(pubID)
+	if !me.MetricsDisabled.AccountAdapterDetails {
+		aam.bidValidationConformanceMeter.Mark(1)
+	}
+}
+
+func (me *Metrics) RecordBidValidationConformanceWarn(adapter openrtb_ext.BidderName, pubID string) {
+	adapterStr := string(adapter)
+	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
+		return
+	}
+	am.BidValidationConformanceWarnMeter.Mark(1)
+
+	aam := me.getAccountMetrics(pubID)
+	if !me.MetricsDisabled.AccountAdapterDetails {
+		aam.bidValidationConformanceWarnMeter.Mark(1)
+	}
+}
+
+// RecordFloorsCurrencyConversionError implements a part of the MetricsEngine interface
+func (me *Metrics) RecordFloorsCurrencyConversionError(source FloorsConversionSource) {
+	if meter, exists := me.FloorsCurrencyConversionErrorMeter[source]; exists {
+		meter.Mark(1)
+	}
+}
+
 func (me *Metrics) RecordModuleCalled(labels ModuleLabels, duration time.Duration) {
 	mm, err := me.getModuleMetric(labels)
 	if err != nil {
@@ -1154,6 +1248,32 @@ func (me *Metrics) RecordModuleTimeout(labels ModuleLabels) {
 	}
 }
 
+// RecordFloorsModelOutcome implements a part of the MetricsEngine interface
+func (me *Metrics) RecordFloorsModelOutcome(modelVersion string, outcome FloorsModelOutcome, count int) {
+	if modelVersion == "" {
+		modelVersion = "unknown"
+	}
+	metrics.GetOrRegisterMeter(fmt.Sprintf("floors.model.%s.%s", modelVersion, outcome), me.MetricsRegistry).Mark(int64(count))
+}
+
+// RecordAnalyticsEventDropped implements a part of the MetricsEngine interface
+func (me *Metrics) RecordAnalyticsEventDropped(module string) {
+	metrics.GetOrRegisterMeter(fmt.Sprintf("analytics.%s.events_dropped", module), me.MetricsRegistry).Mark(1)
+}
+
+// RecordAnalyticsBufferedEvents implements a part of the MetricsEngine interface
+func (me *Metrics) RecordAnalyticsBufferedEvents(module string, count int) {
+	metrics.GetOrRegisterGauge(fmt.Sprintf("analytics.%s.events_buffered", module), me.MetricsRegistry).Update(int64(count))
+}
+
+// RecordVendorList implements a part of the MetricsEngine interface
+func (me *Metrics) RecordVendorList(specVersion uint16, listVersion uint16, lastUpdated time.Time) {
+	metrics.GetOrRegisterGauge(fmt.Sprintf("gdpr.vendor_list.v%d.version", specVersion), me.MetricsRegistry).Update(int64(listVersion))
+	if !lastUpdated.IsZero() {
+		metrics.GetOrRegisterGauge(fmt.Sprintf("gdpr.vendor_list.v%d.last_updated", specVersion), me.MetricsRegistry).Update(lastUpdated.Unix())
+	}
+}
+
 func (me *Metrics) getModuleMetric(labels ModuleLabels) (*ModuleMetrics, error) {
 	mm, ok := me.ModuleMetrics[labels.Module][labels.Stage]
 	if !ok {
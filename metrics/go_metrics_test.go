@@ -29,12 +29,16 @@ func TestNewMetrics(t *testing.T) {
 	ensureContains(t, registry, "cookie_sync_requests.bad_request", m.CookieSyncStatusMeter[CookieSyncBadRequest])
 	ensureContains(t, registry, "cookie_sync_requests.opt_out", m.CookieSyncStatusMeter[CookieSyncOptOut])
 	ensureContains(t, registry, "cookie_sync_requests.gdpr_blocked_host_cookie", m.CookieSyncStatusMeter[CookieSyncGDPRHostCookieBlocked])
+	ensureContains(t, registry, "cookie_sync_requests.cookie_decrypt_failed", m.CookieSyncStatusMeter[CookieSyncCookieDecryptFailed])
+	ensureContains(t, registry, "cookie_sync_requests.module_rejected", m.CookieSyncStatusMeter[CookieSyncModuleRejected])
 	ensureContains(t, registry, "setuid_requests", m.SetUidMeter)
 	ensureContains(t, registry, "setuid_requests.ok", m.SetUidStatusMeter[SetUidOK])
 	ensureContains(t, registry, "setuid_requests.bad_request", m.SetUidStatusMeter[SetUidBadRequest])
 	ensureContains(t, registry, "setuid_requests.opt_out", m.SetUidStatusMeter[SetUidOptOut])
 	ensureContains(t, registry, "setuid_requests.gdpr_blocked_host_cookie", m.SetUidStatusMeter[SetUidGDPRHostCookieBlocked])
 	ensureContains(t, registry, "setuid_requests.syncer_unknown", m.SetUidStatusMeter[SetUidSyncerUnknown])
+	ensureContains(t, registry, "setuid_requests.cookie_decrypt_failed", m.SetUidStatusMeter[SetUidCookieDecryptFailed])
+	ensureContains(t, registry, "setuid_requests.module_rejected", m.SetUidStatusMeter[SetUidModuleRejected])
 	ensureContains(t, registry, "stored_responses", m.StoredResponsesMeter)
 
 	ensureContains(t, registry, "prebid_cache_request_time.ok", m.PrebidCacheRequestTimerSuccess)
@@ -377,6 +381,50 @@ func TestRecordBidValidationSecureMarkup(t *testing.T) {
 	}
 }
 
+func TestRecordBidValidationConformance(t *testing.T) {
+	testCases := []struct {
+		description          string
+		givenDisabledMetrics config.DisabledMetrics
+		givenPubID           string
+		expectedAccountCount int64
+		expectedAdapterCount int64
+	}{
+		{
+			description: "Account Metric isn't disabled, so both metrics should be incremented",
+			givenDisabledMetrics: config.DisabledMetrics{
+				AccountAdapterDetails: false,
+			},
+			givenPubID:           "acct-id",
+			expectedAdapterCount: 1,
+			expectedAccountCount: 1,
+		},
+		{
+			description: "Account Metric is disabled, so only the adapter metric should increment",
+			givenDisabledMetrics: config.DisabledMetrics{
+				AccountAdapterDetails: true,
+			},
+			givenPubID:           "acct-id",
+			expectedAdapterCount: 1,
+			expectedAccountCount: 0,
+		},
+	}
+	adapter := "AnyName"
+	lowerCaseAdapter := "anyname"
+	for _, test := range testCases {
+		registry := metrics.NewRegistry()
+		m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName(adapter)}, test.givenDisabledMetrics, nil, nil)
+
+		m.RecordBidValidationConformanceError(openrtb_ext.BidderName(adapter), test.givenPubID)
+		m.RecordBidValidationConformanceWarn(openrtb_ext.BidderName(adapter), test.givenPubID)
+		am := m.getAccountMetrics(test.givenPubID)
+
+		assert.Equal(t, test.expectedAdapterCount, m.AdapterMetrics[lowerCaseAdapter].BidValidationConformanceErrorMeter.Count())
+		assert.Equal(t, test.expectedAdapterCount, m.AdapterMetrics[lowerCaseAdapter].BidValidationConformanceWarnMeter.Count())
+		assert.Equal(t, test.expectedAccountCount, am.bidValidationConformanceMeter.Count())
+		assert.Equal(t, test.expectedAccountCount, am.bidValidationConformanceWarnMeter.Count())
+	}
+}
+
 func TestRecordDNSTime(t *testing.T) {
 	testCases := []struct {
 		description         string
@@ -874,6 +922,81 @@ func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 	}
 }
 
+func TestRecordAdapterCircuitBreakerState(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("AnyName")}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterCircuitBreakerState(openrtb_ext.BidderName("AnyName"), CircuitBreakerOpen)
+	assert.Equal(t, int64(2), m.AdapterMetrics["anyname"].CircuitBreakerState.Value())
+
+	m.RecordAdapterCircuitBreakerState(openrtb_ext.BidderName("AnyName"), CircuitBreakerClosed)
+	assert.Equal(t, int64(0), m.AdapterMetrics["anyname"].CircuitBreakerState.Value())
+
+	// Unknown adapters are ignored
+	m.RecordAdapterCircuitBreakerState(openrtb_ext.BidderName("fooAdvertising"), CircuitBreakerOpen)
+	assert.Equal(t, int64(0), m.AdapterMetrics["anyname"].CircuitBreakerState.Value())
+}
+
+func TestRecordAdapterCircuitBreakerRejected(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("AnyName")}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterCircuitBreakerRejected(openrtb_ext.BidderName("AnyName"))
+	m.RecordAdapterCircuitBreakerRejected(openrtb_ext.BidderName("fooAdvertising"))
+
+	assert.Equal(t, int64(1), m.AdapterMetrics["anyname"].CircuitBreakerRejectedMeter.Count())
+}
+
+func TestRecordFloorsCurrencyConversionError(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("AnyName")}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordFloorsCurrencyConversionError(FloorsConversionBidder)
+	m.RecordFloorsCurrencyConversionError(FloorsConversionBidder)
+	m.RecordFloorsCurrencyConversionError(FloorsConversionSource("unknown"))
+
+	assert.Equal(t, int64(0), registry.Get("floors.currency_conversion_err.rule").(metrics.Meter).Count())
+	assert.Equal(t, int64(2), registry.Get("floors.currency_conversion_err.bidder").(metrics.Meter).Count())
+}
+
+func TestRecordFloorsModelOutcome(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("AnyName")}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordFloorsModelOutcome("model_a", FloorsModelRequested, 1)
+	m.RecordFloorsModelOutcome("model_a", FloorsModelWon, 2)
+	m.RecordFloorsModelOutcome("", FloorsModelRejected, 1)
+
+	assert.Equal(t, int64(1), registry.Get("floors.model.model_a.requested").(metrics.Meter).Count())
+	assert.Equal(t, int64(2), registry.Get("floors.model.model_a.won").(metrics.Meter).Count())
+	assert.Equal(t, int64(1), registry.Get("floors.model.unknown.rejected").(metrics.Meter).Count())
+}
+
+func TestRecordAnalyticsEvents(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("AnyName")}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAnalyticsEventDropped("eventlog")
+	m.RecordAnalyticsEventDropped("eventlog")
+	m.RecordAnalyticsBufferedEvents("eventlog", 42)
+
+	assert.Equal(t, int64(2), registry.Get("analytics.eventlog.events_dropped").(metrics.Meter).Count())
+	assert.Equal(t, int64(42), registry.Get("analytics.eventlog.events_buffered").(metrics.Gauge).Value())
+}
+
+func TestRecordVendorList(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("AnyName")}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordVendorList(3, 42, time.Unix(1700000000, 0))
+	m.RecordVendorList(2, 7, time.Time{})
+
+	assert.Equal(t, int64(42), registry.Get("gdpr.vendor_list.v3.version").(metrics.Gauge).Value())
+	assert.Equal(t, int64(1700000000), registry.Get("gdpr.vendor_list.v3.last_updated").(metrics.Gauge).Value())
+	assert.Equal(t, int64(7), registry.Get("gdpr.vendor_list.v2.version").(metrics.Gauge).Value())
+	assert.Nil(t, registry.Get("gdpr.vendor_list.v2.last_updated"), "an unknown publication time isn't recorded")
+}
+
 func TestRecordCookieSync(t *testing.T) {
 	registry := metrics.NewRegistry()
 	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderName("Foo"), openrtb_ext.BidderName("Bar")}, config.DisabledMetrics{}, nil, nil)
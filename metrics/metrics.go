@@ -339,6 +339,8 @@ const (
 	CookieSyncAccountBlocked         CookieSyncStatus = "acct_blocked"
 	CookieSyncAccountConfigMalformed CookieSyncStatus = "acct_config_malformed"
 	CookieSyncAccountInvalid         CookieSyncStatus = "acct_invalid"
+	CookieSyncCookieDecryptFailed    CookieSyncStatus = "cookie_decrypt_failed"
+	CookieSyncModuleRejected         CookieSyncStatus = "module_rejected"
 )
 
 // CookieSyncStatuses returns possible cookie sync statuses.
@@ -351,6 +353,8 @@ func CookieSyncStatuses() []CookieSyncStatus {
 		CookieSyncAccountBlocked,
 		CookieSyncAccountConfigMalformed,
 		CookieSyncAccountInvalid,
+		CookieSyncCookieDecryptFailed,
+		CookieSyncModuleRejected,
 	}
 }
 
@@ -387,6 +391,8 @@ const (
 	SetUidAccountConfigMalformed SetUidStatus = "acct_config_malformed"
 	SetUidAccountInvalid         SetUidStatus = "acct_invalid"
 	SetUidSyncerUnknown          SetUidStatus = "syncer_unknown"
+	SetUidCookieDecryptFailed    SetUidStatus = "cookie_decrypt_failed"
+	SetUidModuleRejected         SetUidStatus = "module_rejected"
 )
 
 // SetUidStatuses returns possible setuid statuses.
@@ -400,6 +406,8 @@ func SetUidStatuses() []SetUidStatus {
 		SetUidAccountConfigMalformed,
 		SetUidAccountInvalid,
 		SetUidSyncerUnknown,
+		SetUidCookieDecryptFailed,
+		SetUidModuleRejected,
 	}
 }
 
@@ -419,6 +427,77 @@ func SyncerSetUidStatuses() []SyncerSetUidStatus {
 	}
 }
 
+// CircuitBreakerState is the state of a bidder's circuit breaker. The values are ordered by severity, so
+// they can be reported as a gauge.
+type CircuitBreakerState int
+
+const (
+	CircuitBreakerClosed CircuitBreakerState = iota
+	CircuitBreakerHalfOpen
+	CircuitBreakerOpen
+)
+
+// CircuitBreakerStates returns the possible circuit breaker states.
+func CircuitBreakerStates() []CircuitBreakerState {
+	return []CircuitBreakerState{
+		CircuitBreakerClosed,
+		CircuitBreakerHalfOpen,
+		CircuitBreakerOpen,
+	}
+}
+
+func (s CircuitBreakerState) String() string {
+	switch s {
+	case CircuitBreakerClosed:
+		return "closed"
+	case CircuitBreakerHalfOpen:
+		return "half_open"
+	case CircuitBreakerOpen:
+		return "open"
+	}
+	return "unknown"
+}
+
+// FloorsConversionSource is where a price floor had to be converted to another currency.
+type FloorsConversionSource string
+
+const (
+	// FloorsConversionRule is a floor rule in a different currency than the rest of its model group
+	FloorsConversionRule FloorsConversionSource = "rule"
+	// FloorsConversionBidder is a floor converted to the bidder's preferred currency
+	FloorsConversionBidder FloorsConversionSource = "bidder"
+)
+
+func FloorsConversionSources() []FloorsConversionSource {
+	return []FloorsConversionSource{
+		FloorsConversionRule,
+		FloorsConversionBidder,
+	}
+}
+
+// FloorsModelOutcome is what happened to a request or bid priced by a floors model version.
+type FloorsModelOutcome string
+
+const (
+	// FloorsModelRequested is a request that selected the model version
+	FloorsModelRequested FloorsModelOutcome = "requested"
+	// FloorsModelSkipped is a request that selected the model version but skipped floors
+	FloorsModelSkipped FloorsModelOutcome = "skipped"
+	// FloorsModelRejected is a bid rejected for being below a floor of the model version
+	FloorsModelRejected FloorsModelOutcome = "rejected"
+	// FloorsModelWon is a winning bid priced by the model version
+	FloorsModelWon FloorsModelOutcome = "won"
+)
+
+func FloorsModelOutcomes() []FloorsModelOutcome {
+	return []FloorsModelOutcome{
+		FloorsModelRequested,
+		FloorsModelSkipped,
+		FloorsModelRejected,
+		FloorsModelWon,
+	}
+}
+
 // MetricsEngine is a generic interface to record PBS metrics into the desired backend
 // The first three metrics function fire off once per incoming request, so total metrics
 // will equal the total number of incoming requests. The remaining 5 fire off per outgoing
@@ -457,6 +536,8 @@ type MetricsEngine interface {
 	RecordRequestPrivacy(privacy PrivacyLabels)
 	RecordAdapterBuyerUIDScrubbed(adapterName openrtb_ext.BidderName)
 	RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName)
+	RecordAdapterCircuitBreakerState(adapterName openrtb_ext.BidderName, state CircuitBreakerState)
+	RecordAdapterCircuitBreakerRejected(adapterName openrtb_ext.BidderName)
 	RecordDebugRequest(debugEnabled bool, pubId string)
 	RecordStoredResponse(pubId string)
 	RecordAdsCertReq(success bool)
@@ -465,6 +546,10 @@ type MetricsEngine interface {
 	RecordBidValidationCreativeSizeWarn(adapter openrtb_ext.BidderName, account string)
 	RecordBidValidationSecureMarkupError(adapter openrtb_ext.BidderName, account string)
 	RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.BidderName, account string)
+	RecordBidValidationConformanceError(adapter openrtb_ext.BidderName, account string)
+	RecordBidValidationConformanceWarn(adapter openrtb_ext.BidderName, account string)
+	RecordFloorsCurrencyConversionError(source FloorsConversionSource)
+	RecordFloorsModelOutcome(modelVersion string, outcome FloorsModelOutcome, count int)
 	RecordModuleCalled(labels ModuleLabels, duration time.Duration)
 	RecordModuleFailed(labels ModuleLabels)
 	RecordModuleSuccessNooped(labels ModuleLabels)
@@ -472,4 +557,9 @@ type MetricsEngine interface {
 	RecordModuleSuccessRejected(labels ModuleLabels)
 	RecordModuleExecutionError(labels ModuleLabels)
 	RecordModuleTimeout(labels ModuleLabels)
+	RecordAnalyticsEventDropped(module string)
+	RecordAnalyticsBufferedEvents(module string, count int)
+	// RecordVendorList records the latest GDPR vendor list loaded for a spec version, along with when it was
+	// published, so the age of the list can be monitored.
+	RecordVendorList(specVersion uint16, listVersion uint16, lastUpdated time.Time)
 }
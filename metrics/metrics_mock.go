@@ -166,6 +166,16 @@ func (me *MetricsEngineMock) RecordAdapterGDPRRequestBlocked(adapterName openrtb
 	me.Called(adapterName)
 }
 
+// RecordAdapterCircuitBreakerState mock
+func (me *MetricsEngineMock) RecordAdapterCircuitBreakerState(adapterName openrtb_ext.BidderName, state CircuitBreakerState) {
+	me.Called(adapterName, state)
+}
+
+// RecordAdapterCircuitBreakerRejected mock
+func (me *MetricsEngineMock) RecordAdapterCircuitBreakerRejected(adapterName openrtb_ext.BidderName) {
+	me.Called(adapterName)
+}
+
 // RecordDebugRequest mock
 func (me *MetricsEngineMock) RecordDebugRequest(debugEnabled bool, pubId string) {
 	me.Called(debugEnabled, pubId)
@@ -199,6 +209,14 @@ func (me *MetricsEngineMock) RecordBidValidationSecureMarkupWarn(adapter openrtb
 	me.Called(adapter, account)
 }
 
+func (me *MetricsEngineMock) RecordBidValidationConformanceError(adapter openrtb_ext.BidderName, account string) {
+	me.Called(adapter, account)
+}
+
+func (me *MetricsEngineMock) RecordBidValidationConformanceWarn(adapter openrtb_ext.BidderName, account string) {
+	me.Called(adapter, account)
+}
+
 func (me *MetricsEngineMock) RecordModuleCalled(labels ModuleLabels, duration time.Duration) {
 	me.Called(labels, duration)
 }
@@ -226,3 +244,28 @@ func (me *MetricsEngineMock) RecordModuleExecutionError(labels ModuleLabels) {
 func (me *MetricsEngineMock) RecordModuleTimeout(labels ModuleLabels) {
 	me.Called(labels)
 }
+
+// RecordFloorsCurrencyConversionError mock
+func (me *MetricsEngineMock) RecordFloorsCurrencyConversionError(source FloorsConversionSource) {
+	me.Called(source)
+}
+
+// RecordFloorsModelOutcome mock
+func (me *MetricsEngineMock) RecordFloorsModelOutcome(modelVersion string, outcome FloorsModelOutcome, count int) {
+	me.Called(modelVersion, outcome, count)
+}
+
+// RecordAnalyticsEventDropped mock
+func (me *MetricsEngineMock) RecordAnalyticsEventDropped(module string) {
+	me.Called(module)
+}
+
+// RecordAnalyticsBufferedEvents mock
+func (me *MetricsEngineMock) RecordAnalyticsBufferedEvents(module string, count int) {
+	me.Called(module, count)
+}
+
+// RecordVendorList mock
+func (me *MetricsEngineMock) RecordVendorList(specVersion uint16, listVersion uint16, lastUpdated time.Time) {
+	me.Called(specVersion, listVersion, lastUpdated)
+}
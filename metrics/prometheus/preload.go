@@ -16,6 +16,7 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		connectionErrorValues     = []string{connectionAcceptError, connectionCloseError}
 		cookieSyncStatusValues    = enumAsString(metrics.CookieSyncStatuses())
 		cookieValues              = enumAsString(metrics.CookieTypes())
+		floorsConversionValues    = enumAsString(metrics.FloorsConversionSources())
 		overheadTypes             = enumAsString(metrics.OverheadTypes())
 		requestStatusValues       = enumAsString(metrics.RequestStatuses())
 		requestTypeValues         = enumAsString(metrics.RequestTypes())
@@ -40,6 +41,10 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 		statusLabel: setUidStatusValues,
 	})
 
+	preloadLabelValuesForCounter(m.floorsCurrencyConversionErrors, map[string][]string{
+		sourceLabel: floorsConversionValues,
+	})
+
 	preloadLabelValuesForCounter(m.impressions, map[string][]string{
 		isBannerLabel: boolValues,
 		isVideoLabel:  boolValues,
@@ -72,10 +72,14 @@ type Metrics struct {
 	adapterConnectionWaitTime             *prometheus.HistogramVec
 	adapterScrubbedBuyerUIDs              *prometheus.CounterVec
 	adapterGDPRBlockedRequests            *prometheus.CounterVec
+	adapterCircuitBreakerState            *prometheus.GaugeVec
+	adapterCircuitBreakerRejected         *prometheus.CounterVec
 	adapterBidResponseValidationSizeError *prometheus.CounterVec
 	adapterBidResponseValidationSizeWarn  *prometheus.CounterVec
 	adapterBidResponseSecureMarkupError   *prometheus.CounterVec
 	adapterBidResponseSecureMarkupWarn    *prometheus.CounterVec
+	adapterBidResponseConformanceError    *prometheus.CounterVec
+	adapterBidResponseConformanceWarn     *prometheus.CounterVec
 
 	// Syncer Metrics
 	syncerRequests *prometheus.CounterVec
@@ -89,6 +93,8 @@ type Metrics struct {
 	accountBidResponseValidationSizeWarn  *prometheus.CounterVec
 	accountBidResponseSecureMarkupError   *prometheus.CounterVec
 	accountBidResponseSecureMarkupWarn    *prometheus.CounterVec
+	accountBidResponseConformanceError    *prometheus.CounterVec
+	accountBidResponseConformanceWarn     *prometheus.CounterVec
 
 	// Module Metrics as a map where the key is the module name
 	moduleDuration        map[string]*prometheus.HistogramVec
@@ -100,6 +106,18 @@ type Metrics struct {
 	moduleExecutionErrors map[string]*prometheus.CounterVec
 	moduleTimeouts        map[string]*prometheus.CounterVec
 
+	// Price Floors Metrics
+	floorsCurrencyConversionErrors *prometheus.CounterVec
+	floorsModelOutcomes            *prometheus.CounterVec
+
+	// Analytics Metrics
+	analyticsEventsDropped  *prometheus.CounterVec
+	analyticsEventsBuffered *prometheus.GaugeVec
+
+	// GDPR Vendor List Metrics
+	vendorListVersion     *prometheus.GaugeVec
+	vendorListLastUpdated *prometheus.GaugeVec
+
 	metricsDisabled config.DisabledMetrics
 }
 
@@ -118,11 +136,15 @@ const (
 	isNativeLabel        = "native"
 	isVideoLabel         = "video"
 	markupDeliveryLabel  = "delivery"
+	moduleLabel          = "module"
+	modelVersionLabel    = "model_version"
 	optOutLabel          = "opt_out"
+	outcomeLabel         = "outcome"
 	overheadTypeLabel    = "overhead_type"
 	privacyBlockedLabel  = "privacy_blocked"
 	requestStatusLabel   = "request_status"
 	requestTypeLabel     = "request_type"
+	specVersionLabel     = "spec_version"
 	stageLabel           = "stage"
 	statusLabel          = "status"
 	successLabel         = "success"
@@ -348,6 +370,46 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 			[]string{adapterLabel})
 	}
 
+	metrics.adapterCircuitBreakerState = newGauge(cfg, reg,
+		"adapter_circuit_breaker_state",
+		"State of the adapter circuit breaker: 0 closed, 1 half open, 2 open. With per host breakers, the worst state across hosts.",
+		[]string{adapterLabel})
+
+	metrics.adapterCircuitBreakerRejected = newCounter(cfg, reg,
+		"adapter_circuit_breaker_rejected",
+		"Count of bidder requests skipped because the adapter circuit breaker was open",
+		[]string{adapterLabel})
+
+	metrics.floorsCurrencyConversionErrors = newCounter(cfg, reg,
+		"floors_currency_conversion_errors",
+		"Count of price floors dropped because they couldn't be converted to the required currency",
+		[]string{sourceLabel})
+
+	metrics.floorsModelOutcomes = newCounter(cfg, reg,
+		"floors_model_outcomes",
+		"Count of requests, skips, floor rejections and wins per price floors model version",
+		[]string{modelVersionLabel, outcomeLabel})
+
+	metrics.analyticsEventsDropped = newCounter(cfg, reg,
+		"analytics_events_dropped",
+		"Count of analytics events dropped because the analytics module's buffer was full",
+		[]string{moduleLabel})
+
+	metrics.analyticsEventsBuffered = newGauge(cfg, reg,
+		"analytics_events_buffered",
+		"Number of analytics events waiting in the analytics module's buffer",
+		[]string{moduleLabel})
+
+	metrics.vendorListVersion = newGauge(cfg, reg,
+		"gdpr_vendor_list_version",
+		"Latest GDPR vendor list version loaded per spec version",
+		[]string{specVersionLabel})
+
+	metrics.vendorListLastUpdated = newGauge(cfg, reg,
+		"gdpr_vendor_list_last_updated",
+		"Unix time the latest GDPR vendor list loaded per spec version was published. Its age is time() minus this value",
+		[]string{specVersionLabel})
+
 	metrics.storedResponsesFetchTimer = newHistogramVec(cfg, reg,
 		"stored_response_fetch_time_seconds",
 		"Seconds to fetch stored responses labeled by fetch type",
@@ -427,6 +489,16 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count that tracks number of bids removed from bid response that had a invalid bidAdm (warn)",
 		[]string{adapterLabel, successLabel})
 
+	metrics.adapterBidResponseConformanceError = newCounter(cfg, reg,
+		"adapter_response_validation_conformance_err",
+		"Count that tracks number of bids removed from bid response that didn't match the imp or the bidder capabilities",
+		[]string{adapterLabel, successLabel})
+
+	metrics.adapterBidResponseConformanceWarn = newCounter(cfg, reg,
+		"adapter_response_validation_conformance_warn",
+		"Count that tracks number of bids removed from bid response that didn't match the imp or the bidder capabilities (warn)",
+		[]string{adapterLabel, successLabel})
+
 	metrics.overheadTimer = newHistogramVec(cfg, reg,
 		"overhead_time_seconds",
 		"Seconds to prepare adapter request or resolve adapter response",
@@ -484,6 +556,16 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count that tracks number of bids removed from bid response that had a invalid bidAdm labeled by account (warn)",
 		[]string{accountLabel, successLabel})
 
+	metrics.accountBidResponseConformanceError = newCounter(cfg, reg,
+		"account_response_validation_conformance_err",
+		"Count that tracks number of bids removed from bid response that didn't match the imp or the bidder capabilities labeled by account (enforce) ",
+		[]string{accountLabel, successLabel})
+
+	metrics.accountBidResponseConformanceWarn = newCounter(cfg, reg,
+		"account_response_validation_conformance_warn",
+		"Count that tracks number of bids removed from bid response that didn't match the imp or the bidder capabilities labeled by account (warn)",
+		[]string{accountLabel, successLabel})
+
 	metrics.requestsQueueTimer = newHistogramVec(cfg, reg,
 		"request_queue_time",
 		"Seconds request was waiting in queue",
@@ -605,6 +687,18 @@ func newCounterWithoutLabels(cfg config.PrometheusMetrics, registry *prometheus.
 	return counter
 }
 
+func newGauge(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string) *prometheus.GaugeVec {
+	opts := prometheus.GaugeOpts{
+		Namespace: cfg.Namespace,
+		Subsystem: cfg.Subsystem,
+		Name:      name,
+		Help:      help,
+	}
+	gauge := prometheus.NewGaugeVec(opts, labels)
+	registry.MustRegister(gauge)
+	return gauge
+}
+
 func newHistogramVec(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string, buckets []float64) *prometheus.HistogramVec {
 	opts := prometheus.HistogramOpts{
 		Namespace: cfg.Namespace,
@@ -979,6 +1073,18 @@ func (m *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.Bidder
 	}).Inc()
 }
 
+func (m *Metrics) RecordAdapterCircuitBreakerState(adapterName openrtb_ext.BidderName, state metrics.CircuitBreakerState) {
+	m.adapterCircuitBreakerState.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+	}).Set(float64(state))
+}
+
+func (m *Metrics) RecordAdapterCircuitBreakerRejected(adapterName openrtb_ext.BidderName) {
+	m.adapterCircuitBreakerRejected.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+	}).Inc()
+}
+
 func (m *Metrics) RecordAdsCertReq(success bool) {
 	if success {
 		m.adsCertRequests.With(prometheus.Labels{
@@ -1044,6 +1150,30 @@ func (m *Metrics) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.Bidder
 	}
 }
 
+func (m *Metrics) RecordBidValidationConformanceError(adapter openrtb_ext.BidderName, account string) {
+	m.adapterBidResponseConformanceError.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapter)), successLabel: successLabel,
+	}).Inc()
+
+	if !m.metricsDisabled.AccountAdapterDetails && account != metrics.PublisherUnknown {
+		m.accountBidResponseConformanceError.With(prometheus.Labels{
+			accountLabel: account, successLabel: successLabel,
+		}).Inc()
+	}
+}
+
+func (m *Metrics) RecordBidValidationConformanceWarn(adapter openrtb_ext.BidderName, account string) {
+	m.adapterBidResponseConformanceWarn.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapter)), successLabel: successLabel,
+	}).Inc()
+
+	if !m.metricsDisabled.AccountAdapterDetails && account != metrics.PublisherUnknown {
+		m.accountBidResponseConformanceWarn.With(prometheus.Labels{
+			accountLabel: account, successLabel: successLabel,
+		}).Inc()
+	}
+}
+
 func (m *Metrics) RecordModuleCalled(labels metrics.ModuleLabels, duration time.Duration) {
 	m.moduleCalls[labels.Module].With(prometheus.Labels{
 		stageLabel: labels.Stage,
@@ -1089,3 +1219,38 @@ func (m *Metrics) RecordModuleTimeout(labels metrics.ModuleLabels) {
 		stageLabel: labels.Stage,
 	}).Inc()
 }
+
+func (m *Metrics) RecordFloorsCurrencyConversionError(source metrics.FloorsConversionSource) {
+	m.floorsCurrencyConversionErrors.With(prometheus.Labels{
+		sourceLabel: string(source),
+	}).Inc()
+}
+
+func (m *Metrics) RecordFloorsModelOutcome(modelVersion string, outcome metrics.FloorsModelOutcome, count int) {
+	m.floorsModelOutcomes.With(prometheus.Labels{
+		modelVersionLabel: modelVersion,
+		outcomeLabel:      string(outcome),
+	}).Add(float64(count))
+}
+
+func (m *Metrics) RecordAnalyticsEventDropped(module string) {
+	m.analyticsEventsDropped.With(prometheus.Labels{
+		moduleLabel: module,
+	}).Inc()
+}
+
+func (m *Metrics) RecordAnalyticsBufferedEvents(module string, count int) {
+	m.analyticsEventsBuffered.With(prometheus.Labels{
+		moduleLabel: module,
+	}).Set(float64(count))
+}
+
+func (m *Metrics) RecordVendorList(specVersion uint16, listVersion uint16, lastUpdated time.Time) {
+	labels := prometheus.Labels{
+		specVersionLabel: strconv.Itoa(int(specVersion)),
+	}
+	m.vendorListVersion.With(labels).Set(float64(listVersion))
+	if !lastUpdated.IsZero() {
+		m.vendorListLastUpdated.With(labels).Set(float64(lastUpdated.Unix()))
+	}
+}
@@ -280,6 +280,44 @@ func TestBidValidationSecureMarkupMetric(t *testing.T) {
 	}
 }
 
+func TestBidValidationConformanceMetric(t *testing.T) {
+	testCases := []struct {
+		description                        string
+		givenDebugEnabledFlag              bool
+		givenAccountAdapterMetricsDisabled bool
+		expectedAdapterCount               float64
+		expectedAccountCount               float64
+	}{
+		{
+			description:                        "Account Metric isn't disabled, so both metrics should be incremented",
+			givenAccountAdapterMetricsDisabled: false,
+			expectedAdapterCount:               1,
+			expectedAccountCount:               1,
+		},
+		{
+			description:                        "Account Metric is disabled, so only adapter metric should be incremented",
+			givenAccountAdapterMetricsDisabled: true,
+			expectedAdapterCount:               1,
+			expectedAccountCount:               0,
+		},
+	}
+
+	adapterName := openrtb_ext.BidderName("AnyName")
+	lowerCasedAdapterName := "anyname"
+	for _, test := range testCases {
+		m := createMetricsForTesting()
+		m.metricsDisabled.AccountAdapterDetails = test.givenAccountAdapterMetricsDisabled
+		m.RecordBidValidationConformanceError(adapterName, "acct-id")
+		m.RecordBidValidationConformanceWarn(adapterName, "acct-id")
+
+		assertCounterVecValue(t, "", "Account Conformance Error", m.accountBidResponseConformanceError, test.expectedAccountCount, prometheus.Labels{accountLabel: "acct-id", successLabel: successLabel})
+		assertCounterVecValue(t, "", "Adapter Conformance Error", m.adapterBidResponseConformanceError, test.expectedAdapterCount, prometheus.Labels{adapterLabel: lowerCasedAdapterName, successLabel: successLabel})
+
+		assertCounterVecValue(t, "", "Account Conformance Warn", m.accountBidResponseConformanceWarn, test.expectedAccountCount, prometheus.Labels{accountLabel: "acct-id", successLabel: successLabel})
+		assertCounterVecValue(t, "", "Adapter Conformance Warn", m.adapterBidResponseConformanceWarn, test.expectedAdapterCount, prometheus.Labels{adapterLabel: lowerCasedAdapterName, successLabel: successLabel})
+	}
+}
+
 func TestRequestMetricWithoutCookie(t *testing.T) {
 	requestType := metrics.ReqTypeORTB2Web
 	performTest := func(m *Metrics, cookieFlag metrics.CookieFlag) {
@@ -1740,6 +1778,14 @@ func assertCounterVecValue(t *testing.T, description, name string, counterVec *p
 	assertCounterValue(t, description, name, counter, expected)
 }
 
+func assertGaugeVecValue(t *testing.T, description string, gaugeVec *prometheus.GaugeVec, expected float64, labels prometheus.Labels) {
+	m := dto.Metric{}
+	gaugeVec.With(labels).Write(&m)
+	actual := *m.GetGauge().Value
+
+	assert.Equal(t, expected, actual, description)
+}
+
 func getHistogramFromHistogramVec(histogram *prometheus.HistogramVec, labelKey, labelValue string) dto.Histogram {
 	var result dto.Histogram
 	processMetrics(histogram, func(m dto.Metric) {
@@ -1846,6 +1892,90 @@ func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 		})
 }
 
+func TestRecordAdapterCircuitBreakerState(t *testing.T) {
+	m := createMetricsForTesting()
+	adapterName := openrtb_ext.BidderName("AnyName")
+
+	m.RecordAdapterCircuitBreakerState(adapterName, metrics.CircuitBreakerOpen)
+	assertGaugeVecValue(t, "Set adapter circuit breaker state to open", m.adapterCircuitBreakerState, 2, prometheus.Labels{adapterLabel: "anyname"})
+
+	m.RecordAdapterCircuitBreakerState(adapterName, metrics.CircuitBreakerHalfOpen)
+	assertGaugeVecValue(t, "Set adapter circuit breaker state to half open", m.adapterCircuitBreakerState, 1, prometheus.Labels{adapterLabel: "anyname"})
+}
+
+func TestRecordAdapterCircuitBreakerRejected(t *testing.T) {
+	m := createMetricsForTesting()
+	adapterName := openrtb_ext.BidderName("AnyName")
+	lowerCasedAdapterName := "anyname"
+	m.RecordAdapterCircuitBreakerRejected(adapterName)
+
+	assertCounterVecValue(t,
+		"Increment adapter circuit breaker rejected counter",
+		"adapter_circuit_breaker_rejected",
+		m.adapterCircuitBreakerRejected,
+		1,
+		prometheus.Labels{
+			adapterLabel: lowerCasedAdapterName,
+		})
+}
+
+func TestRecordFloorsCurrencyConversionError(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordFloorsCurrencyConversionError(metrics.FloorsConversionRule)
+
+	assertCounterVecValue(t,
+		"Increment floors currency conversion errors counter",
+		"floors_currency_conversion_errors",
+		m.floorsCurrencyConversionErrors,
+		1,
+		prometheus.Labels{
+			sourceLabel: string(metrics.FloorsConversionRule),
+		})
+}
+
+func TestRecordFloorsModelOutcome(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordFloorsModelOutcome("model_a", metrics.FloorsModelWon, 3)
+
+	assertCounterVecValue(t,
+		"Increment floors model outcomes counter",
+		"floors_model_outcomes",
+		m.floorsModelOutcomes,
+		3,
+		prometheus.Labels{
+			modelVersionLabel: "model_a",
+			outcomeLabel:      string(metrics.FloorsModelWon),
+		})
+}
+
+func TestRecordAnalyticsEvents(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAnalyticsEventDropped("eventlog")
+	m.RecordAnalyticsBufferedEvents("eventlog", 42)
+
+	assertCounterVecValue(t,
+		"Increment analytics events dropped counter",
+		"analytics_events_dropped",
+		m.analyticsEventsDropped,
+		1,
+		prometheus.Labels{
+			moduleLabel: "eventlog",
+		})
+	assertGaugeVecValue(t, "Set analytics events buffered", m.analyticsEventsBuffered, 42, prometheus.Labels{moduleLabel: "eventlog"})
+}
+
+func TestRecordVendorList(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordVendorList(3, 42, time.Unix(1700000000, 0))
+
+	assertGaugeVecValue(t, "Set vendor list version", m.vendorListVersion, 42, prometheus.Labels{specVersionLabel: "3"})
+	assertGaugeVecValue(t, "Set vendor list last updated", m.vendorListLastUpdated, 1700000000, prometheus.Labels{specVersionLabel: "3"})
+}
+
 func TestStoredResponsesMetric(t *testing.T) {
 	testCases := []struct {
 		description                           string
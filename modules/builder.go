@@ -2,7 +2,10 @@ package modules
 
 import (
 	fiftyonedegreesDevicedetection "github.com/prebid/prebid-server/v3/modules/fiftyonedegrees/devicedetection"
+	prebidDevicedetection "github.com/prebid/prebid-server/v3/modules/prebid/devicedetection"
+	prebidGeolocation "github.com/prebid/prebid-server/v3/modules/prebid/geolocation"
 	prebidOrtb2blocking "github.com/prebid/prebid-server/v3/modules/prebid/ortb2blocking"
+	prebidRulesengine "github.com/prebid/prebid-server/v3/modules/prebid/rulesengine"
 )
 
 // builders returns mapping between module name and its builder
@@ -13,7 +16,10 @@ func builders() ModuleBuilders {
 			"devicedetection": fiftyonedegreesDevicedetection.Builder,
 		},
 		"prebid": {
-			"ortb2blocking": prebidOrtb2blocking.Builder,
+			"devicedetection": prebidDevicedetection.Builder,
+			"geolocation":     prebidGeolocation.Builder,
+			"ortb2blocking":   prebidOrtb2blocking.Builder,
+			"rulesengine":     prebidRulesengine.Builder,
 		},
 	}
 }
@@ -11,4 +11,6 @@ import (
 type ModuleDeps struct {
 	HTTPClient    *http.Client
 	RateConvertor *currency.RateConverter
+	// DataCenter is the data center the host is running in, as set by the datacenter config.
+	DataCenter string
 }
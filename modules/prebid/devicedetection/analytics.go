@@ -0,0 +1,63 @@
+package devicedetection
+
+import (
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+)
+
+const detectionActivity = "device-detection"
+
+const (
+	rulesVersionAnalyticKey = "rules_version"
+	botAnalyticKey          = "bot"
+	botNameAnalyticKey      = "bot_name"
+	deviceTypeAnalyticKey   = "device_type"
+	osAnalyticKey           = "os"
+	browserAnalyticKey      = "browser"
+	fieldsAnalyticKey       = "fields"
+)
+
+// newDetectionTags reports the detected device. The result is a block if the auction was rejected
+// and a modification if any of the device fields were updated.
+func newDetectionTags(rulesVersion string, d detection, fields []string, rejected bool) hookanalytics.Analytics {
+	values := map[string]interface{}{
+		rulesVersionAnalyticKey: rulesVersion,
+		botAnalyticKey:          d.bot != "",
+	}
+	if d.bot != "" {
+		values[botNameAnalyticKey] = d.bot
+	}
+	if d.deviceType != 0 {
+		values[deviceTypeAnalyticKey] = int(d.deviceType)
+	}
+	if d.os != "" {
+		values[osAnalyticKey] = d.os
+	}
+	if d.browser != "" {
+		values[browserAnalyticKey] = d.browser
+	}
+
+	status := hookanalytics.ResultStatusAllow
+	switch {
+	case rejected:
+		status = hookanalytics.ResultStatusBlock
+	case len(fields) > 0:
+		status = hookanalytics.ResultStatusModify
+		values[fieldsAnalyticKey] = fields
+	}
+
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{
+			{
+				Name:   detectionActivity,
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{
+					{
+						Status:    status,
+						Values:    values,
+						AppliedTo: hookanalytics.AppliedTo{Request: true},
+					},
+				},
+			},
+		},
+	}
+}
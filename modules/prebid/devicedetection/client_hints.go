@@ -0,0 +1,128 @@
+package devicedetection
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+)
+
+const (
+	userAgentHeader              = "User-Agent"
+	secCHUAHeader                = "Sec-CH-UA"
+	secCHUAFullVersionListHeader = "Sec-CH-UA-Full-Version-List"
+	secCHUAMobileHeader          = "Sec-CH-UA-Mobile"
+	secCHUAPlatformHeader        = "Sec-CH-UA-Platform"
+	secCHUAPlatformVersionHeader = "Sec-CH-UA-Platform-Version"
+	secCHUAModelHeader           = "Sec-CH-UA-Model"
+	secCHUAArchHeader            = "Sec-CH-UA-Arch"
+	secCHUABitnessHeader         = "Sec-CH-UA-Bitness"
+)
+
+// suaFromHeaders builds the structured user agent from the User-Agent Client Hints headers.
+// It returns nil if the browser didn't send any of the low entropy hints.
+func suaFromHeaders(header http.Header) *openrtb2.UserAgent {
+	sua := &openrtb2.UserAgent{Source: adcom1.UASourceLowEntropy}
+
+	if browsers := header.Get(secCHUAFullVersionListHeader); browsers != "" {
+		sua.Browsers = parseBrandList(browsers)
+		sua.Source = adcom1.UASourceHighEntropy
+	} else if browsers := header.Get(secCHUAHeader); browsers != "" {
+		sua.Browsers = parseBrandList(browsers)
+	}
+
+	if platform := parseString(header.Get(secCHUAPlatformHeader)); platform != "" {
+		sua.Platform = &openrtb2.BrandVersion{Brand: platform}
+		if version := header.Get(secCHUAPlatformVersionHeader); version != "" {
+			sua.Platform.Version = splitVersion(parseString(version))
+			sua.Source = adcom1.UASourceHighEntropy
+		}
+	}
+
+	switch header.Get(secCHUAMobileHeader) {
+	case "?1":
+		sua.Mobile = ptrutil.ToPtr[int8](1)
+	case "?0":
+		sua.Mobile = ptrutil.ToPtr[int8](0)
+	}
+
+	// high entropy hints are sent as an empty string when the value is unknown, e.g. the model on desktops
+	for _, hint := range []struct {
+		header string
+		value  *string
+	}{
+		{secCHUAModelHeader, &sua.Model},
+		{secCHUAArchHeader, &sua.Architecture},
+		{secCHUABitnessHeader, &sua.Bitness},
+	} {
+		if value := header.Get(hint.header); value != "" {
+			*hint.value = parseString(value)
+			sua.Source = adcom1.UASourceHighEntropy
+		}
+	}
+
+	if len(sua.Browsers) == 0 && sua.Platform == nil && sua.Mobile == nil {
+		return nil
+	}
+	return sua
+}
+
+// parseBrandList parses a structured header list such as `"Chromium";v="120.0.6099.71", "Not_A Brand";v="8"`.
+func parseBrandList(value string) []openrtb2.BrandVersion {
+	var brands []openrtb2.BrandVersion
+	for _, item := range splitOutsideQuotes(value, ',') {
+		params := splitOutsideQuotes(item, ';')
+		brand := openrtb2.BrandVersion{Brand: parseString(params[0])}
+		if brand.Brand == "" {
+			continue
+		}
+
+		for _, param := range params[1:] {
+			if key, version, ok := strings.Cut(strings.TrimSpace(param), "="); ok && key == "v" {
+				brand.Version = splitVersion(parseString(version))
+			}
+		}
+		brands = append(brands, brand)
+	}
+	return brands
+}
+
+// parseString returns the value of a structured header string, unquoted values are returned as is.
+func parseString(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return value
+	}
+	value = value[1 : len(value)-1]
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(value)
+}
+
+func splitOutsideQuotes(value string, separator byte) []string {
+	var parts []string
+	quoted, escaped := false, false
+	start := 0
+
+	for i := 0; i < len(value); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && value[i] == '\\':
+			escaped = true
+		case value[i] == '"':
+			quoted = !quoted
+		case !quoted && value[i] == separator:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+func splitVersion(version string) []string {
+	if version == "" {
+		return nil
+	}
+	return strings.Split(version, ".")
+}
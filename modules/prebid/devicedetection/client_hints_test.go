@@ -0,0 +1,86 @@
+package devicedetection
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuaFromHeaders(t *testing.T) {
+	testCases := []struct {
+		description  string
+		givenHeaders map[string]string
+		expectedSUA  *openrtb2.UserAgent
+	}{
+		{
+			description:  "No client hints",
+			givenHeaders: map[string]string{userAgentHeader: "Mozilla/5.0"},
+		},
+		{
+			description: "Low entropy hints",
+			givenHeaders: map[string]string{
+				secCHUAHeader:         `"Not_A Brand";v="8", "Chromium";v="120", "Google Chrome";v="120"`,
+				secCHUAMobileHeader:   "?0",
+				secCHUAPlatformHeader: `"Windows"`,
+			},
+			expectedSUA: &openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{
+					{Brand: "Not_A Brand", Version: []string{"8"}},
+					{Brand: "Chromium", Version: []string{"120"}},
+					{Brand: "Google Chrome", Version: []string{"120"}},
+				},
+				Platform: &openrtb2.BrandVersion{Brand: "Windows"},
+				Mobile:   ptrutil.ToPtr[int8](0),
+				Source:   adcom1.UASourceLowEntropy,
+			},
+		},
+		{
+			description: "High entropy hints",
+			givenHeaders: map[string]string{
+				secCHUAHeader:                `"Chromium";v="120"`,
+				secCHUAFullVersionListHeader: `"Not)A;Brand";v="24.0.0.0", "Chromium";v="120.0.6099.71"`,
+				secCHUAMobileHeader:          "?1",
+				secCHUAPlatformHeader:        `"Android"`,
+				secCHUAPlatformVersionHeader: `"14.0.0"`,
+				secCHUAModelHeader:           `"Pixel 8"`,
+				secCHUAArchHeader:            `""`,
+				secCHUABitnessHeader:         `"64"`,
+			},
+			expectedSUA: &openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{
+					{Brand: "Not)A;Brand", Version: []string{"24", "0", "0", "0"}},
+					{Brand: "Chromium", Version: []string{"120", "0", "6099", "71"}},
+				},
+				Platform: &openrtb2.BrandVersion{Brand: "Android", Version: []string{"14", "0", "0"}},
+				Mobile:   ptrutil.ToPtr[int8](1),
+				Model:    "Pixel 8",
+				Bitness:  "64",
+				Source:   adcom1.UASourceHighEntropy,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			header := http.Header{}
+			for name, value := range test.givenHeaders {
+				header.Set(name, value)
+			}
+
+			assert.Equal(t, test.expectedSUA, suaFromHeaders(header))
+		})
+	}
+}
+
+func TestParseBrandList(t *testing.T) {
+	assert.Equal(t, []openrtb2.BrandVersion{
+		{Brand: `Quoted "Brand"`, Version: []string{"1", "2"}},
+		{Brand: "No Version"},
+	}, parseBrandList(`"Quoted \"Brand\"";v="1.2", "No Version", ""`))
+
+	assert.Nil(t, parseBrandList(""))
+}
@@ -0,0 +1,66 @@
+package devicedetection
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const defaultReloadIntervalSeconds = 60 * 60
+
+type config struct {
+	Rules      rulesConfig `json:"rules"`
+	Overwrite  bool        `json:"overwrite"`
+	RejectBots bool        `json:"reject_bots"`
+}
+
+type rulesConfig struct {
+	// Path to a rules database replacing the bundled one.
+	Path string `json:"path"`
+	// ReloadIntervalSeconds is how often the file is checked for changes, 0 uses the default
+	// and a negative value disables reloading.
+	ReloadIntervalSeconds int `json:"reload_interval_seconds"`
+}
+
+// accountConfig holds the settings an account may override.
+type accountConfig struct {
+	Enabled    *bool `json:"enabled"`
+	Overwrite  *bool `json:"overwrite"`
+	RejectBots *bool `json:"reject_bots"`
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	var cfg config
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+
+	if cfg.Rules.ReloadIntervalSeconds == 0 {
+		cfg.Rules.ReloadIntervalSeconds = defaultReloadIntervalSeconds
+	}
+
+	return cfg, nil
+}
+
+// merge applies the account config on top of the host config.
+func (c config) merge(data json.RawMessage) (config, bool, error) {
+	if len(data) == 0 {
+		return c, true, nil
+	}
+
+	var account accountConfig
+	if err := jsonutil.UnmarshalValid(data, &account); err != nil {
+		return c, false, fmt.Errorf("failed to parse account config: %s", err)
+	}
+
+	if account.Overwrite != nil {
+		c.Overwrite = *account.Overwrite
+	}
+	if account.RejectBots != nil {
+		c.RejectBots = *account.RejectBots
+	}
+	enabled := account.Enabled == nil || *account.Enabled
+
+	return c, enabled, nil
+}
@@ -0,0 +1,191 @@
+package devicedetection
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+)
+
+// reducedUAModel is the model Chrome sends in the reduced User-Agent string instead of the real one.
+const reducedUAModel = "K"
+
+var platformNames = map[string]string{
+	"Chromium OS": "Chrome OS",
+	"Unknown":     "",
+}
+
+var browserNames = map[string]string{
+	"Google Chrome":  "Chrome",
+	"Microsoft Edge": "Edge",
+}
+
+var desktopPlatforms = map[string]bool{
+	"Windows":   true,
+	"macOS":     true,
+	"Linux":     true,
+	"Chrome OS": true,
+}
+
+// detection is the device described by the User-Agent string and the structured user agent.
+type detection struct {
+	bot            string
+	deviceType     adcom1.DeviceType
+	make           string
+	model          string
+	os             string
+	osv            string
+	browser        string
+	browserVersion string
+	// sua is the structured user agent the detection is based on,
+	// or the one parsed from the User-Agent string if none was given.
+	sua *openrtb2.UserAgent
+}
+
+// detect matches the User-Agent string against the rules. The structured user agent,
+// taken from device.sua or the client hints headers, takes precedence over the values
+// parsed from the User-Agent string since browsers freeze most of them.
+func (r *rules) detect(ua string, sua *openrtb2.UserAgent) detection {
+	var d detection
+
+	if ua != "" {
+		if bot, _ := r.bots.match(ua); bot != nil {
+			d.bot = bot.Name
+		}
+		if os, match := r.os.match(ua); os != nil {
+			d.os = os.Name
+			d.osv = expandVersion(os.Version, match)
+		}
+		if browser, match := r.browsers.match(ua); browser != nil {
+			d.browser = browser.Name
+			d.browserVersion = expandVersion(browser.Version, match)
+		}
+		if device, match := r.devices.match(ua); device != nil {
+			d.deviceType = deviceTypes[device.Type]
+			d.make = device.Make
+			d.model = expand(device.Model, match)
+		}
+		if d.model == reducedUAModel {
+			d.model = ""
+		}
+	}
+
+	if sua != nil {
+		d.applyUserAgent(sua)
+		d.sua = sua
+	} else {
+		d.sua = d.parsedUserAgent()
+	}
+
+	if d.make == "" && d.model != "" {
+		if make, _ := r.makes.match(d.model); make != nil {
+			d.make = make.Make
+		}
+	}
+
+	return d
+}
+
+func (d *detection) applyUserAgent(sua *openrtb2.UserAgent) {
+	if sua.Platform != nil {
+		os, known := platformNames[sua.Platform.Brand]
+		if !known {
+			os = sua.Platform.Brand
+		}
+		// the low entropy hints have no version, keep the one parsed for the same platform
+		if os != "" && (os != d.os || len(sua.Platform.Version) > 0) {
+			d.os = os
+			d.osv = platformVersion(os, sua.Platform.Version)
+		}
+	}
+
+	if sua.Model != "" {
+		d.model = sua.Model
+		d.make = ""
+	}
+
+	if brand := mainBrand(sua.Browsers); brand != nil {
+		d.browser = brand.Brand
+		if name, ok := browserNames[brand.Brand]; ok {
+			d.browser = name
+		}
+		d.browserVersion = strings.Join(brand.Version, ".")
+	}
+
+	if d.deviceType == 0 && sua.Mobile != nil {
+		if *sua.Mobile == 1 {
+			d.deviceType = adcom1.DeviceMobile
+		} else if desktopPlatforms[d.os] {
+			d.deviceType = adcom1.DevicePC
+		}
+	}
+}
+
+// parsedUserAgent returns the structured user agent built from the values parsed from the User-Agent string.
+func (d detection) parsedUserAgent() *openrtb2.UserAgent {
+	if d.browser == "" && d.os == "" {
+		return nil
+	}
+
+	sua := &openrtb2.UserAgent{Model: d.model, Source: adcom1.UASourceParsed}
+	if d.browser != "" {
+		sua.Browsers = []openrtb2.BrandVersion{{Brand: d.browser, Version: splitVersion(d.browserVersion)}}
+	}
+	if d.os != "" {
+		sua.Platform = &openrtb2.BrandVersion{Brand: d.os, Version: splitVersion(d.osv)}
+	}
+
+	switch d.deviceType {
+	case 0:
+	case adcom1.DeviceMobile, adcom1.DevicePhone:
+		sua.Mobile = ptrutil.ToPtr[int8](1)
+	default:
+		sua.Mobile = ptrutil.ToPtr[int8](0)
+	}
+
+	return sua
+}
+
+// mainBrand returns the browser brand, skipping the GREASE brands and Chromium if a more specific brand is listed.
+func mainBrand(brands []openrtb2.BrandVersion) *openrtb2.BrandVersion {
+	var chromium *openrtb2.BrandVersion
+	for i := range brands {
+		switch {
+		case strings.HasPrefix(brands[i].Brand, "Not") && strings.Contains(brands[i].Brand, "Brand"):
+		case brands[i].Brand == "Chromium":
+			chromium = &brands[i]
+		default:
+			return &brands[i]
+		}
+	}
+	return chromium
+}
+
+// platformVersion formats the platform version. The Windows platform version isn't the product version,
+// versions 13 and above are Windows 11 and versions 1 to 10 are Windows 10.
+func platformVersion(platform string, version []string) string {
+	if platform != "Windows" || len(version) == 0 {
+		return strings.Join(version, ".")
+	}
+
+	major, err := strconv.Atoi(version[0])
+	if err != nil {
+		return strings.Join(version, ".")
+	}
+
+	switch {
+	case major >= 13:
+		return "11"
+	case major > 0:
+		return "10"
+	case len(version) > 1 && version[1] == "1":
+		return "7"
+	case len(version) > 1 && version[1] == "2":
+		return "8"
+	case len(version) > 1 && version[1] == "3":
+		return "8.1"
+	}
+	return strings.Join(version, ".")
+}
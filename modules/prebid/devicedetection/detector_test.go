@@ -0,0 +1,222 @@
+package devicedetection
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	r, err := newRules(bundledRules)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description string
+		givenUA     string
+		givenSUA    *openrtb2.UserAgent
+		expected    detection
+	}{
+		{
+			description: "Chrome on Windows",
+			givenUA:     "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected: detection{
+				deviceType: adcom1.DevicePC, os: "Windows", osv: "10", browser: "Chrome", browserVersion: "120.0.0.0",
+			},
+		},
+		{
+			description: "Safari on macOS",
+			givenUA:     "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			expected: detection{
+				deviceType: adcom1.DevicePC, os: "macOS", osv: "10.15.7", browser: "Safari", browserVersion: "17.1",
+			},
+		},
+		{
+			description: "Safari on iPhone",
+			givenUA:     "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1.2 Mobile/15E148 Safari/604.1",
+			expected: detection{
+				deviceType: adcom1.DevicePhone, make: "Apple", model: "iPhone", os: "iOS", osv: "17.1.2", browser: "Safari", browserVersion: "17.1.2",
+			},
+		},
+		{
+			description: "Chrome on iPad",
+			givenUA:     "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/119.0.6045.169 Mobile/15E148 Safari/604.1",
+			expected: detection{
+				deviceType: adcom1.DeviceTablet, make: "Apple", model: "iPad", os: "iOS", osv: "16.6", browser: "Chrome", browserVersion: "119.0.6045.169",
+			},
+		},
+		{
+			description: "Samsung Internet on Galaxy phone",
+			givenUA:     "Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36",
+			expected: detection{
+				deviceType: adcom1.DevicePhone, make: "Samsung", model: "SM-S918B", os: "Android", osv: "13", browser: "Samsung Internet", browserVersion: "23.0",
+			},
+		},
+		{
+			description: "WebView on Android phone",
+			givenUA:     "Mozilla/5.0 (Linux; Android 12; Pixel 6 Build/SQ3A.220705.003; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/119.0.6045.163 Mobile Safari/537.36",
+			expected: detection{
+				deviceType: adcom1.DevicePhone, make: "Google", model: "Pixel 6", os: "Android", osv: "12", browser: "Chrome WebView", browserVersion: "119.0.6045.163",
+			},
+		},
+		{
+			description: "Reduced User-Agent on Android tablet",
+			givenUA:     "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected: detection{
+				deviceType: adcom1.DeviceTablet, os: "Android", osv: "10", browser: "Chrome", browserVersion: "120.0.0.0",
+			},
+		},
+		{
+			description: "Firefox on Android phone",
+			givenUA:     "Mozilla/5.0 (Android 14; Mobile; rv:120.0) Gecko/120.0 Firefox/120.0",
+			expected: detection{
+				deviceType: adcom1.DevicePhone, os: "Android", osv: "14", browser: "Firefox", browserVersion: "120.0",
+			},
+		},
+		{
+			description: "Samsung smart TV",
+			givenUA:     "Mozilla/5.0 (SMART-TV; LINUX; Tizen 6.0) AppleWebKit/537.36 (KHTML, like Gecko) 76.0.3809.146/6.0 TV Safari/537.36",
+			expected: detection{
+				deviceType: adcom1.DeviceTV, make: "Samsung", os: "Tizen", osv: "6.0",
+			},
+		},
+		{
+			description: "Fire TV",
+			givenUA:     "Mozilla/5.0 (Linux; Android 9; AFTMM Build/PS7233; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/98.0.4758.101 Mobile Safari/537.36",
+			expected: detection{
+				deviceType: adcom1.DeviceSetTopBox, make: "Amazon", model: "Fire TV", os: "Fire OS", browser: "Chrome WebView", browserVersion: "98.0.4758.101",
+			},
+		},
+		{
+			description: "Googlebot",
+			givenUA:     "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.6045.199 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			expected: detection{
+				bot: "Googlebot", deviceType: adcom1.DevicePhone, make: "Google", model: "Nexus 5X", os: "Android", osv: "6.0.1", browser: "Chrome", browserVersion: "119.0.6045.199",
+			},
+		},
+		{
+			description: "Unknown crawler",
+			givenUA:     "Mozilla/5.0 (compatible; ExampleCrawler/1.0; +https://crawler.example.com)",
+			expected:    detection{bot: "Crawler"},
+		},
+		{
+			description: "Client hints take precedence over the reduced User-Agent",
+			givenUA:     "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			givenSUA: &openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{
+					{Brand: "Not_A Brand", Version: []string{"8"}},
+					{Brand: "Chromium", Version: []string{"120", "0", "6099", "71"}},
+					{Brand: "Google Chrome", Version: []string{"120", "0", "6099", "71"}},
+				},
+				Platform: &openrtb2.BrandVersion{Brand: "Android", Version: []string{"14", "0", "0"}},
+				Mobile:   ptrutil.ToPtr[int8](1),
+				Model:    "Pixel 8",
+			},
+			expected: detection{
+				deviceType: adcom1.DevicePhone, make: "Google", model: "Pixel 8", os: "Android", osv: "14.0.0", browser: "Chrome", browserVersion: "120.0.6099.71",
+			},
+		},
+		{
+			description: "Windows 11 from client hints",
+			givenUA:     "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			givenSUA: &openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{{Brand: "Microsoft Edge", Version: []string{"120"}}},
+				Platform: &openrtb2.BrandVersion{Brand: "Windows", Version: []string{"15", "0", "0"}},
+			},
+			expected: detection{
+				deviceType: adcom1.DevicePC, os: "Windows", osv: "11", browser: "Edge", browserVersion: "120",
+			},
+		},
+		{
+			description: "Client hints without User-Agent",
+			givenSUA: &openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{{Brand: "Chromium", Version: []string{"120"}}},
+				Platform: &openrtb2.BrandVersion{Brand: "Linux"},
+				Mobile:   ptrutil.ToPtr[int8](0),
+			},
+			expected: detection{
+				deviceType: adcom1.DevicePC, os: "Linux", browser: "Chromium", browserVersion: "120",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			d := r.detect(test.givenUA, test.givenSUA)
+
+			if test.givenSUA != nil {
+				assert.Same(t, test.givenSUA, d.sua)
+			}
+			d.sua = nil
+			assert.Equal(t, test.expected, d)
+		})
+	}
+}
+
+func TestDetectParsedUserAgent(t *testing.T) {
+	r, err := newRules(bundledRules)
+	require.NoError(t, err)
+
+	d := r.detect("Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/115.0.0.0 Mobile Safari/537.36", nil)
+	assert.Equal(t, &openrtb2.UserAgent{
+		Browsers: []openrtb2.BrandVersion{{Brand: "Chrome", Version: []string{"115", "0", "0", "0"}}},
+		Platform: &openrtb2.BrandVersion{Brand: "Android", Version: []string{"13"}},
+		Mobile:   ptrutil.ToPtr[int8](1),
+		Model:    "SM-S918B",
+		Source:   adcom1.UASourceParsed,
+	}, d.sua)
+
+	d = r.detect("some-unknown-client", nil)
+	assert.Nil(t, d.sua)
+
+	d = detection{deviceType: adcom1.DeviceMobile, os: "Android"}
+	assert.Equal(t, ptrutil.ToPtr[int8](1), d.parsedUserAgent().Mobile, "mobile/tablet devices are mobile")
+}
+
+func TestNewRules(t *testing.T) {
+	r, err := newRules(bundledRules)
+	require.NoError(t, err)
+	assert.NotEmpty(t, r.version)
+
+	testCases := []struct {
+		description   string
+		givenRules    string
+		expectedError string
+	}{
+		{
+			description:   "Invalid regex",
+			givenRules:    `{"os": [{"name": "Android", "regex": "Android"}, {"name": "iOS", "regex": "iPhone("}]}`,
+			expectedError: "os[1]: invalid regex: error parsing regexp: missing closing ): `iPhone(`",
+		},
+		{
+			description:   "Unknown device type",
+			givenRules:    `{"devices": [{"regex": "Watch", "type": "watch"}]}`,
+			expectedError: `devices[0]: unknown device type "watch"`,
+		},
+		{
+			description:   "Malformed file",
+			givenRules:    `{"bots": {}}`,
+			expectedError: "failed to parse rules",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := newRules([]byte(test.givenRules))
+			assert.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	match := []string{"Android 13; SM-S918B)", "13", "SM-S918B"}
+
+	assert.Equal(t, "SM-S918B", expand("$2", match))
+	assert.Equal(t, "Galaxy 13", expand("Galaxy $1", match))
+	assert.Equal(t, "Apple TV", expand("Apple TV", match))
+	assert.Equal(t, "", expand("$5", match))
+	assert.Equal(t, "10.15.7", expandVersion("$1", []string{"", "10_15_7"}))
+}
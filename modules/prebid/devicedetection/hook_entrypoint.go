@@ -0,0 +1,25 @@
+package devicedetection
+
+import (
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+)
+
+// Module context keys holding the headers evidence for the raw_auction_request stage
+const (
+	userAgentCtxKey   = "user_agent"
+	clientHintsCtxKey = "client_hints"
+)
+
+// handleEntrypointHook keeps the User-Agent and the client hints headers,
+// they are used if the request doesn't carry device.ua or device.sua.
+func handleEntrypointHook(payload hookstage.EntrypointPayload) (result hookstage.HookResult[hookstage.EntrypointPayload], err error) {
+	if payload.Request == nil {
+		return result, nil
+	}
+
+	result.ModuleContext = hookstage.ModuleContext{
+		userAgentCtxKey:   payload.Request.Header.Get(userAgentHeader),
+		clientHintsCtxKey: suaFromHeaders(payload.Request.Header),
+	}
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package devicedetection
+
+import (
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+func handleRawAuctionHook(
+	cfg config,
+	r *rules,
+	moduleCtx hookstage.ModuleContext,
+	payload hookstage.RawAuctionRequestPayload,
+) (result hookstage.HookResult[hookstage.RawAuctionRequestPayload], err error) {
+	ua := gjson.GetBytes(payload, "device.ua").String()
+	if ua == "" {
+		ua, _ = moduleCtx[userAgentCtxKey].(string)
+	}
+
+	var sua *openrtb2.UserAgent
+	if raw := gjson.GetBytes(payload, "device.sua"); raw.IsObject() {
+		sua = &openrtb2.UserAgent{}
+		if err := jsonutil.Unmarshal([]byte(raw.Raw), sua); err != nil {
+			return result, hookexecution.NewFailure("failed to parse device.sua: %s", err)
+		}
+	} else {
+		sua, _ = moduleCtx[clientHintsCtxKey].(*openrtb2.UserAgent)
+	}
+
+	if ua == "" && sua == nil {
+		return result, nil
+	}
+
+	d := r.detect(ua, sua)
+	if d.bot != "" && cfg.RejectBots {
+		result.Reject = true
+		result.NbrCode = int(openrtb3.NoBidNonHuman)
+		result.AnalyticsTags = newDetectionTags(r.version, d, nil, true)
+		return result, nil
+	}
+
+	_, fields, err := fillDevice(payload, d, cfg.Overwrite)
+	if err != nil {
+		return result, hookexecution.NewFailure("failed to update device: %s", err)
+	}
+
+	result.AnalyticsTags = newDetectionTags(r.version, d, fields, false)
+	if len(fields) == 0 {
+		return result, nil
+	}
+
+	result.ChangeSet.AddMutation(func(payload hookstage.RawAuctionRequestPayload) (hookstage.RawAuctionRequestPayload, error) {
+		payload, _, err := fillDevice(payload, d, cfg.Overwrite)
+		return payload, err
+	}, hookstage.MutationUpdate, "device")
+
+	return result, nil
+}
+
+// fillDevice sets the detected values on the device object and returns the names of the updated fields.
+// Values sent by the caller are kept unless overwrite is set, device.sua is only added if missing.
+func fillDevice(payload []byte, d detection, overwrite bool) ([]byte, []string, error) {
+	device := gjson.GetBytes(payload, "device")
+
+	fields := []struct {
+		name  string
+		value interface{}
+		set   bool
+	}{
+		{"devicetype", int(d.deviceType), d.deviceType != 0},
+		{"make", d.make, d.make != ""},
+		{"model", d.model, d.model != ""},
+		{"os", d.os, d.os != ""},
+		{"osv", d.osv, d.osv != ""},
+		{"js", 1, d.browser != ""},
+	}
+
+	var updated []string
+	var err error
+	for _, field := range fields {
+		if !field.set {
+			continue
+		}
+		current := device.Get(field.name)
+		if current.Exists() && (!overwrite || current.String() == fmt.Sprint(field.value)) {
+			continue
+		}
+
+		if payload, err = sjson.SetBytes(payload, "device."+field.name, field.value); err != nil {
+			return nil, nil, err
+		}
+		updated = append(updated, field.name)
+	}
+
+	if d.sua != nil && !device.Get("sua").Exists() {
+		sua, err := jsonutil.Marshal(d.sua)
+		if err != nil {
+			return nil, nil, err
+		}
+		if payload, err = sjson.SetRawBytes(payload, "device.sua", sua); err != nil {
+			return nil, nil, err
+		}
+		updated = append(updated, "sua")
+	}
+
+	return payload, updated, nil
+}
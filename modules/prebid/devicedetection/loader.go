@@ -0,0 +1,77 @@
+package devicedetection
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// rulesLoader holds the current rules and replaces them when the rules file changes on disk.
+// The bundled rules are used if no file is configured.
+type rulesLoader struct {
+	path    string
+	current atomic.Pointer[rules]
+
+	mu      sync.Mutex
+	modTime time.Time
+}
+
+func newRulesLoader(path string) (*rulesLoader, error) {
+	loader := &rulesLoader{path: path}
+	if path == "" {
+		bundled, err := newRules(bundledRules)
+		if err != nil {
+			return nil, err
+		}
+		loader.current.Store(bundled)
+		return loader, nil
+	}
+
+	if err := loader.reload(); err != nil {
+		return nil, err
+	}
+	return loader, nil
+}
+
+func (l *rulesLoader) rules() *rules {
+	return l.current.Load()
+}
+
+// reload reads the file again if it was modified since the last load.
+// The current rules are kept if the new file can't be read.
+func (l *rulesLoader) reload() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return err
+	}
+	if l.current.Load() != nil && !info.ModTime().After(l.modTime) {
+		return nil
+	}
+
+	r, err := openRules(l.path)
+	if err != nil {
+		return err
+	}
+
+	l.current.Store(r)
+	l.modTime = info.ModTime()
+	return nil
+}
+
+// run checks the file for changes every interval for the lifetime of the process.
+func (l *rulesLoader) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := l.reload(); err != nil {
+			glog.Errorf("Failed to reload device detection rules %s: %v", l.path, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package devicedetection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+)
+
+func Builder(rawConfig json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	loader, err := newRulesLoader(cfg.Rules.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rules: %w", err)
+	}
+
+	if cfg.Rules.Path != "" && cfg.Rules.ReloadIntervalSeconds > 0 {
+		go loader.run(time.Duration(cfg.Rules.ReloadIntervalSeconds) * time.Second)
+	}
+
+	return Module{config: cfg, loader: loader}, nil
+}
+
+type Module struct {
+	config config
+	loader *rulesLoader
+}
+
+// HandleEntrypointHook keeps the User-Agent and client hints headers for the raw_auction_request stage.
+func (m Module) HandleEntrypointHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	payload hookstage.EntrypointPayload,
+) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	return handleEntrypointHook(payload)
+}
+
+// HandleRawAuctionHook fills the device object with the detected device type, make, model, os, osv, sua and js.
+// Traffic from bots and crawlers is rejected if the module is configured to do so.
+func (m Module) HandleRawAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.RawAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.RawAuctionRequestPayload]{}
+
+	cfg, enabled, err := m.config.merge(miCtx.AccountConfig)
+	if err != nil {
+		return result, err
+	}
+	if !enabled {
+		return result, nil
+	}
+
+	return handleRawAuctionHook(cfg, m.loader.rules(), miCtx.ModuleContext, payload)
+}
@@ -0,0 +1,234 @@
+package devicedetection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	iPhoneUA    = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1.2 Mobile/15E148 Safari/604.1"
+	googlebotUA = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+)
+
+func TestHandleRawAuctionHook(t *testing.T) {
+	r, err := newRules(bundledRules)
+	require.NoError(t, err)
+
+	iPhoneSUA := `{"browsers":[{"brand":"Safari","version":["17","1","2"]}],"platform":{"brand":"iOS","version":["17","1","2"]},"mobile":1,"model":"iPhone","source":3}`
+
+	testCases := []struct {
+		description       string
+		givenConfig       config
+		givenModuleCtx    hookstage.ModuleContext
+		givenPayload      string
+		expectedPayload   string
+		expectedReject    bool
+		expectedTagStatus hookanalytics.ResultStatus
+		expectedFields    []string
+	}{
+		{
+			description:       "Device filled from device.ua",
+			givenPayload:      `{"id":"req","device":{"ua":"` + iPhoneUA + `"}}`,
+			expectedPayload:   `{"id":"req","device":{"ua":"` + iPhoneUA + `","devicetype":4,"make":"Apple","model":"iPhone","os":"iOS","osv":"17.1.2","js":1,"sua":` + iPhoneSUA + `}}`,
+			expectedTagStatus: hookanalytics.ResultStatusModify,
+			expectedFields:    []string{"devicetype", "make", "model", "os", "osv", "js", "sua"},
+		},
+		{
+			description:       "Caller data kept",
+			givenPayload:      `{"device":{"ua":"` + iPhoneUA + `","devicetype":1,"make":"Apple","os":"iOS","osv":"17.1","js":0,"sua":{"source":1}}}`,
+			expectedPayload:   `{"device":{"ua":"` + iPhoneUA + `","devicetype":1,"make":"Apple","os":"iOS","osv":"17.1","js":0,"sua":{"source":1},"model":"iPhone"}}`,
+			expectedTagStatus: hookanalytics.ResultStatusModify,
+			expectedFields:    []string{"model"},
+		},
+		{
+			description:       "Caller data overwritten if configured",
+			givenConfig:       config{Overwrite: true},
+			givenPayload:      `{"device":{"ua":"` + iPhoneUA + `","devicetype":1,"make":"Apple","os":"iOS","osv":"17.1","js":0,"sua":{"source":1}}}`,
+			expectedPayload:   `{"device":{"ua":"` + iPhoneUA + `","devicetype":4,"make":"Apple","os":"iOS","osv":"17.1.2","js":1,"sua":{"source":1},"model":"iPhone"}}`,
+			expectedTagStatus: hookanalytics.ResultStatusModify,
+			expectedFields:    []string{"devicetype", "model", "osv", "js"},
+		},
+		{
+			description: "Headers used if the request has no device",
+			givenModuleCtx: hookstage.ModuleContext{
+				userAgentCtxKey: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+				clientHintsCtxKey: &openrtb2.UserAgent{
+					Platform: &openrtb2.BrandVersion{Brand: "Windows", Version: []string{"15", "0", "0"}},
+					Source:   adcom1.UASourceHighEntropy,
+				},
+			},
+			givenPayload:      `{"id":"req"}`,
+			expectedPayload:   `{"id":"req","device":{"devicetype":2,"os":"Windows","osv":"11","js":1,"sua":{"platform":{"brand":"Windows","version":["15","0","0"]},"source":2}}}`,
+			expectedTagStatus: hookanalytics.ResultStatusModify,
+			expectedFields:    []string{"devicetype", "os", "osv", "js", "sua"},
+		},
+		{
+			description:       "Bot allowed by default",
+			givenPayload:      `{"device":{"ua":"` + googlebotUA + `","js":1}}`,
+			expectedPayload:   `{"device":{"ua":"` + googlebotUA + `","js":1}}`,
+			expectedTagStatus: hookanalytics.ResultStatusAllow,
+		},
+		{
+			description:       "Bot rejected if configured",
+			givenConfig:       config{RejectBots: true},
+			givenPayload:      `{"device":{"ua":"` + googlebotUA + `"}}`,
+			expectedPayload:   `{"device":{"ua":"` + googlebotUA + `"}}`,
+			expectedReject:    true,
+			expectedTagStatus: hookanalytics.ResultStatusBlock,
+		},
+		{
+			description:     "Request without evidence not changed",
+			givenPayload:    `{"device":{"ip":"1.2.3.4"}}`,
+			expectedPayload: `{"device":{"ip":"1.2.3.4"}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			payload := hookstage.RawAuctionRequestPayload(test.givenPayload)
+
+			result, err := handleRawAuctionHook(test.givenConfig, r, test.givenModuleCtx, payload)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedReject, result.Reject)
+			if test.expectedReject {
+				assert.Equal(t, int(openrtb3.NoBidNonHuman), result.NbrCode)
+			}
+
+			if test.expectedTagStatus == "" {
+				assert.Empty(t, result.AnalyticsTags.Activities)
+			} else {
+				require.Len(t, result.AnalyticsTags.Activities, 1)
+				tagResult := result.AnalyticsTags.Activities[0].Results[0]
+				assert.Equal(t, test.expectedTagStatus, tagResult.Status)
+				assert.Equal(t, r.version, tagResult.Values[rulesVersionAnalyticKey])
+				if len(test.expectedFields) > 0 {
+					assert.Equal(t, test.expectedFields, tagResult.Values[fieldsAnalyticKey])
+				}
+			}
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				require.NoError(t, err)
+			}
+			assert.JSONEq(t, test.expectedPayload, string(payload))
+		})
+	}
+}
+
+func TestHandleRawAuctionHookInvalidSUA(t *testing.T) {
+	r, err := newRules(bundledRules)
+	require.NoError(t, err)
+
+	_, err = handleRawAuctionHook(config{}, r, nil, []byte(`{"device":{"sua":{"mobile":"yes"}}}`))
+	assert.ErrorContains(t, err, "failed to parse device.sua")
+}
+
+func TestHandleEntrypointHook(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	require.NoError(t, err)
+	request.Header.Set(userAgentHeader, iPhoneUA)
+	request.Header.Set(secCHUAPlatformHeader, `"Android"`)
+
+	result, err := handleEntrypointHook(hookstage.EntrypointPayload{Request: request})
+	require.NoError(t, err)
+	assert.Equal(t, hookstage.ModuleContext{
+		userAgentCtxKey: iPhoneUA,
+		clientHintsCtxKey: &openrtb2.UserAgent{
+			Platform: &openrtb2.BrandVersion{Brand: "Android"},
+			Source:   adcom1.UASourceLowEntropy,
+		},
+	}, result.ModuleContext)
+}
+
+func TestModuleHandleRawAuctionHook(t *testing.T) {
+	module, err := Builder(json.RawMessage(`{"enabled": true, "reject_bots": true}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description        string
+		givenAccountConfig json.RawMessage
+		expectedReject     bool
+		expectedError      bool
+	}{
+		{
+			description:    "Host config applied without account config",
+			expectedReject: true,
+		},
+		{
+			description:        "Account allows bots",
+			givenAccountConfig: json.RawMessage(`{"reject_bots": false}`),
+		},
+		{
+			description:        "Disabled for account",
+			givenAccountConfig: json.RawMessage(`{"enabled": false}`),
+		},
+		{
+			description:        "Invalid account config",
+			givenAccountConfig: json.RawMessage(`{"reject_bots": "yes"}`),
+			expectedError:      true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			miCtx := hookstage.ModuleInvocationContext{AccountConfig: test.givenAccountConfig}
+			payload := hookstage.RawAuctionRequestPayload(`{"device":{"ua":"` + googlebotUA + `"}}`)
+
+			result, err := module.(Module).HandleRawAuctionHook(context.Background(), miCtx, payload)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedReject, result.Reject)
+		})
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	_, err := Builder(json.RawMessage(`{"enabled": true, "rules": {"path": "does-not-exist.json"}}`), moduledeps.ModuleDeps{})
+	assert.ErrorContains(t, err, "failed to load rules")
+
+	_, err = Builder(json.RawMessage(`{"enabled": true, "overwrite": "yes"}`), moduledeps.ModuleDeps{})
+	assert.ErrorContains(t, err, "failed to parse config")
+}
+
+func TestRulesLoaderReload(t *testing.T) {
+	rulesPath := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(rulesPath, []byte(`{"version": "1"}`), 0644))
+
+	module, err := Builder(json.RawMessage(fmt.Sprintf(`{"enabled": true, "rules": {"path": %q, "reload_interval_seconds": -1}}`, rulesPath)), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	loader := module.(Module).loader
+	first := loader.rules()
+	assert.Equal(t, "1", first.version)
+
+	assert.NoError(t, loader.reload())
+	assert.Same(t, first, loader.rules(), "unchanged file must not be read again")
+
+	require.NoError(t, os.WriteFile(rulesPath, []byte(`{"os": [{"regex": "("}]}`), 0644))
+	require.NoError(t, os.Chtimes(rulesPath, time.Now(), time.Now().Add(time.Minute)))
+	assert.Error(t, loader.reload())
+	assert.Same(t, first, loader.rules(), "rules must be kept if the new file is invalid")
+
+	require.NoError(t, os.WriteFile(rulesPath, []byte(`{"version": "2"}`), 0644))
+	require.NoError(t, os.Chtimes(rulesPath, time.Now(), time.Now().Add(2*time.Minute)))
+	assert.NoError(t, loader.reload())
+	assert.Equal(t, "2", loader.rules().version)
+}
@@ -0,0 +1,144 @@
+package devicedetection
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// bundledRules is the rules database shipped with the module,
+// it is used unless the host points the module to a different file.
+//
+//go:embed rules.json
+var bundledRules []byte
+
+var deviceTypes = map[string]adcom1.DeviceType{
+	"mobile":    adcom1.DeviceMobile,
+	"pc":        adcom1.DevicePC,
+	"tv":        adcom1.DeviceTV,
+	"phone":     adcom1.DevicePhone,
+	"tablet":    adcom1.DeviceTablet,
+	"connected": adcom1.DeviceConnected,
+	"settopbox": adcom1.DeviceSetTopBox,
+}
+
+var templateGroup = regexp.MustCompile(`\$(\d)`)
+
+// rulesFile is the format of the rules database. Each list is matched in order and the first matching entry wins.
+// The bots, os, browsers and devices lists are matched against the User-Agent string,
+// the makes list is matched against the device model.
+type rulesFile struct {
+	Version  string      `json:"version"`
+	Bots     []ruleEntry `json:"bots"`
+	OS       []ruleEntry `json:"os"`
+	Browsers []ruleEntry `json:"browsers"`
+	Devices  []ruleEntry `json:"devices"`
+	Makes    []ruleEntry `json:"makes"`
+}
+
+// ruleEntry is a single rule of the database. The Version and Model templates may refer
+// to the groups captured by the regex with $1 to $9.
+type ruleEntry struct {
+	Regex   string `json:"regex"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type"`
+	Make    string `json:"make"`
+	Model   string `json:"model"`
+}
+
+type rule struct {
+	ruleEntry
+	regex *regexp.Regexp
+}
+
+type ruleList []rule
+
+// match returns the first rule matching the value with the groups it captured.
+func (l ruleList) match(value string) (*rule, []string) {
+	for i := range l {
+		if match := l[i].regex.FindStringSubmatch(value); match != nil {
+			return &l[i], match
+		}
+	}
+	return nil, nil
+}
+
+type rules struct {
+	version  string
+	bots     ruleList
+	os       ruleList
+	browsers ruleList
+	devices  ruleList
+	makes    ruleList
+}
+
+func openRules(path string) (*rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newRules(data)
+}
+
+func newRules(data []byte) (*rules, error) {
+	var file rulesFile
+	if err := jsonutil.UnmarshalValid(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %s", err)
+	}
+
+	r := &rules{version: file.Version}
+	lists := []struct {
+		name    string
+		entries []ruleEntry
+		rules   *ruleList
+	}{
+		{"bots", file.Bots, &r.bots},
+		{"os", file.OS, &r.os},
+		{"browsers", file.Browsers, &r.browsers},
+		{"devices", file.Devices, &r.devices},
+		{"makes", file.Makes, &r.makes},
+	}
+
+	for _, list := range lists {
+		for i, entry := range list.entries {
+			regex, err := regexp.Compile(entry.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("%s[%d]: invalid regex: %s", list.name, i, err)
+			}
+			if _, ok := deviceTypes[entry.Type]; entry.Type != "" && !ok {
+				return nil, fmt.Errorf("%s[%d]: unknown device type %q", list.name, i, entry.Type)
+			}
+			*list.rules = append(*list.rules, rule{ruleEntry: entry, regex: regex})
+		}
+	}
+
+	return r, nil
+}
+
+// expand replaces the $N references of the template with the groups captured by the rule.
+func expand(template string, match []string) string {
+	if !strings.Contains(template, "$") {
+		return template
+	}
+
+	expanded := templateGroup.ReplaceAllStringFunc(template, func(ref string) string {
+		group, _ := strconv.Atoi(ref[1:])
+		if group < len(match) {
+			return match[group]
+		}
+		return ""
+	})
+	return strings.TrimSpace(expanded)
+}
+
+// expandVersion expands a version template, versions captured as 10_15_7 are written as 10.15.7.
+func expandVersion(template string, match []string) string {
+	return strings.ReplaceAll(expand(template, match), "_", ".")
+}
@@ -0,0 +1,67 @@
+package geolocation
+
+import (
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+)
+
+const geolocationActivity = "device-geolocation"
+
+const (
+	ipVersionAnalyticKey = "ip_version"
+	foundAnalyticKey     = "found"
+	countryAnalyticKey   = "country"
+	fieldsAnalyticKey    = "fields"
+)
+
+// newLookupTags reports a database lookup,
+// the result is a modification if any of the device.geo fields were updated.
+func newLookupTags(ipVersion string, loc *location, fields []string) hookanalytics.Analytics {
+	values := map[string]interface{}{
+		ipVersionAnalyticKey: ipVersion,
+		foundAnalyticKey:     loc != nil,
+	}
+	if loc != nil && loc.country != "" {
+		values[countryAnalyticKey] = loc.country
+	}
+
+	status := hookanalytics.ResultStatusAllow
+	if len(fields) > 0 {
+		status = hookanalytics.ResultStatusModify
+		values[fieldsAnalyticKey] = fields
+	}
+
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{
+			{
+				Name:   geolocationActivity,
+				Status: hookanalytics.ActivityStatusSuccess,
+				Results: []hookanalytics.Result{
+					{
+						Status:    status,
+						Values:    values,
+						AppliedTo: hookanalytics.AppliedTo{Request: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newLookupErrorTags reports a lookup that failed.
+func newLookupErrorTags(ipVersion string) hookanalytics.Analytics {
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{
+			{
+				Name:   geolocationActivity,
+				Status: hookanalytics.ActivityStatusError,
+				Results: []hookanalytics.Result{
+					{
+						Status:    hookanalytics.ResultStatusError,
+						Values:    map[string]interface{}{ipVersionAnalyticKey: ipVersion},
+						AppliedTo: hookanalytics.AppliedTo{Request: true},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package geolocation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+const defaultReloadIntervalSeconds = 24 * 60 * 60
+
+type config struct {
+	Database  databaseConfig `json:"database"`
+	Overwrite bool           `json:"overwrite"`
+}
+
+type databaseConfig struct {
+	// Path to the MMDB file, for example a GeoIP2/GeoLite2 City or a DB-IP City database.
+	Path string `json:"path"`
+	// ReloadIntervalSeconds is how often the file is checked for changes, 0 uses the default
+	// and a negative value disables reloading.
+	ReloadIntervalSeconds int `json:"reload_interval_seconds"`
+}
+
+// accountConfig holds the settings an account may override.
+type accountConfig struct {
+	Enabled   *bool `json:"enabled"`
+	Overwrite *bool `json:"overwrite"`
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	cfg := config{Database: databaseConfig{ReloadIntervalSeconds: defaultReloadIntervalSeconds}}
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+
+	if cfg.Database.Path == "" {
+		return cfg, errors.New("database.path is required")
+	}
+	if cfg.Database.ReloadIntervalSeconds == 0 {
+		cfg.Database.ReloadIntervalSeconds = defaultReloadIntervalSeconds
+	}
+
+	return cfg, nil
+}
+
+// merge applies the account config on top of the host config.
+func (c config) merge(data json.RawMessage) (config, bool, error) {
+	if len(data) == 0 {
+		return c, true, nil
+	}
+
+	var account accountConfig
+	if err := jsonutil.UnmarshalValid(data, &account); err != nil {
+		return c, false, fmt.Errorf("failed to parse account config: %s", err)
+	}
+
+	if account.Overwrite != nil {
+		c.Overwrite = *account.Overwrite
+	}
+	enabled := account.Enabled == nil || *account.Enabled
+
+	return c, enabled, nil
+}
@@ -0,0 +1,90 @@
+package geolocation
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+)
+
+const (
+	ipVersion4 = "ipv4"
+	ipVersion6 = "ipv6"
+)
+
+func handleProcessedAuctionHook(
+	cfg config,
+	db *database,
+	preciseGeo bool,
+	payload hookstage.ProcessedAuctionRequestPayload,
+	now time.Time,
+) (result hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], err error) {
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return result, hookexecution.NewFailure("payload contains a nil bid request")
+	}
+
+	ip, ipVersion := deviceIP(payload.Request.Device)
+	if ip == nil {
+		return result, nil
+	}
+
+	record, err := db.lookup(ip)
+	if err != nil {
+		result.AnalyticsTags = newLookupErrorTags(ipVersion)
+		result.Errors = append(result.Errors, fmt.Sprintf("failed to look up %s address: %s", ipVersion, err))
+		return result, nil
+	}
+	if record == nil {
+		result.AnalyticsTags = newLookupTags(ipVersion, nil, nil)
+		return result, nil
+	}
+
+	loc := newLocation(record)
+	fields := loc.fill(deviceGeo(payload.Request.Device), cfg.Overwrite, preciseGeo, now)
+	result.AnalyticsTags = newLookupTags(ipVersion, &loc, fields)
+	if len(fields) == 0 {
+		return result, nil
+	}
+
+	// The hook may receive a copy of the request with the geo data scrubbed,
+	// so the values are filled again on the request the mutation is applied to.
+	result.ChangeSet.AddMutation(func(payload hookstage.ProcessedAuctionRequestPayload) (hookstage.ProcessedAuctionRequestPayload, error) {
+		if payload.Request == nil || payload.Request.Device == nil {
+			return payload, nil
+		}
+
+		device := *payload.Request.Device
+		device.Geo = deviceGeo(&device)
+		loc.fill(device.Geo, cfg.Overwrite, preciseGeo, now)
+		payload.Request.Device = &device
+		return payload, nil
+	}, hookstage.MutationUpdate, "device", "geo")
+
+	return result, nil
+}
+
+// deviceIP returns the IPv4 address of the device, or the IPv6 address if the IPv4 one is not set.
+func deviceIP(device *openrtb2.Device) (net.IP, string) {
+	if device == nil {
+		return nil, ""
+	}
+	if ip := net.ParseIP(device.IP); ip != nil && ip.To4() != nil {
+		return ip, ipVersion4
+	}
+	if ip := net.ParseIP(device.IPv6); ip != nil {
+		return ip, ipVersion6
+	}
+	return nil, ""
+}
+
+// deviceGeo returns a copy of device.geo which can be updated without affecting the request.
+func deviceGeo(device *openrtb2.Device) *openrtb2.Geo {
+	geo := &openrtb2.Geo{}
+	if device.Geo != nil {
+		*geo = *device.Geo
+	}
+	return geo
+}
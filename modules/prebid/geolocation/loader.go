@@ -0,0 +1,67 @@
+package geolocation
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// databaseLoader holds the current database and replaces it when the file changes on disk.
+type databaseLoader struct {
+	path    string
+	current atomic.Pointer[database]
+
+	mu      sync.Mutex
+	modTime time.Time
+}
+
+func newDatabaseLoader(path string) (*databaseLoader, error) {
+	loader := &databaseLoader{path: path}
+	if err := loader.reload(); err != nil {
+		return nil, err
+	}
+	return loader, nil
+}
+
+func (l *databaseLoader) database() *database {
+	return l.current.Load()
+}
+
+// reload reads the file again if it was modified since the last load.
+// The current database is kept if the new file can't be read.
+func (l *databaseLoader) reload() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return err
+	}
+	if l.current.Load() != nil && !info.ModTime().After(l.modTime) {
+		return nil
+	}
+
+	db, err := openDatabase(l.path)
+	if err != nil {
+		return err
+	}
+
+	l.current.Store(db)
+	l.modTime = info.ModTime()
+	return nil
+}
+
+// run checks the file for changes every interval for the lifetime of the process.
+func (l *databaseLoader) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := l.reload(); err != nil {
+			glog.Errorf("Failed to reload geolocation database %s: %v", l.path, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package geolocation
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// Names of the device.geo fields reported in the analytics tags.
+const (
+	fieldCountry   = "country"
+	fieldRegion    = "region"
+	fieldMetro     = "metro"
+	fieldCity      = "city"
+	fieldZip       = "zip"
+	fieldUTCOffset = "utcoffset"
+	fieldType      = "type"
+)
+
+// location holds the device.geo values resolved from a database record.
+type location struct {
+	country  string
+	region   string
+	metro    string
+	city     string
+	zip      string
+	timeZone string
+}
+
+// newLocation reads the location from a record in the GeoIP2 City layout which is shared by DB-IP.
+func newLocation(record map[string]interface{}) location {
+	loc := location{
+		country:  countryAlpha3[stringValue(path(record, "country", "iso_code"))],
+		city:     stringValue(path(record, "city", "names", "en")),
+		zip:      stringValue(path(record, "postal", "code")),
+		timeZone: stringValue(path(record, "location", "time_zone")),
+	}
+
+	if subdivisions, ok := record["subdivisions"].([]interface{}); ok && len(subdivisions) > 0 {
+		if subdivision, ok := subdivisions[0].(map[string]interface{}); ok {
+			loc.region = stringValue(subdivision["iso_code"])
+		}
+	}
+
+	if metro, ok := path(record, "location", "metro_code").(uint64); ok && metro > 0 {
+		loc.metro = strconv.FormatUint(metro, 10)
+	}
+
+	return loc
+}
+
+// fill sets the resolved values on the geo object and returns the names of the updated fields.
+// Values sent by the caller are kept unless overwrite is set. The precise values,
+// metro, city and zip, are only set if preciseGeo is allowed.
+func (loc location) fill(geo *openrtb2.Geo, overwrite, preciseGeo bool, now time.Time) []string {
+	var fields []string
+	setString := func(field string, target *string, value string) {
+		if value != "" && (*target == "" || overwrite) && *target != value {
+			*target = value
+			fields = append(fields, field)
+		}
+	}
+
+	setString(fieldCountry, &geo.Country, loc.country)
+	setString(fieldRegion, &geo.Region, loc.region)
+	if preciseGeo {
+		setString(fieldMetro, &geo.Metro, loc.metro)
+		setString(fieldCity, &geo.City, loc.city)
+		setString(fieldZip, &geo.ZIP, loc.zip)
+	}
+
+	if offset, ok := utcOffset(loc.timeZone, now); ok && (geo.UTCOffset == 0 || overwrite) && geo.UTCOffset != offset {
+		geo.UTCOffset = offset
+		fields = append(fields, fieldUTCOffset)
+	}
+
+	if len(fields) > 0 && (geo.Type == 0 || overwrite) && geo.Type != adcom1.LocationIP {
+		geo.Type = adcom1.LocationIP
+		fields = append(fields, fieldType)
+	}
+
+	return fields
+}
+
+var timeZones sync.Map
+
+// utcOffset returns the current offset of the time zone from UTC in minutes.
+func utcOffset(timeZone string, now time.Time) (int64, bool) {
+	if timeZone == "" {
+		return 0, false
+	}
+
+	var tz *time.Location
+	if cached, ok := timeZones.Load(timeZone); ok {
+		tz = cached.(*time.Location)
+	} else {
+		loaded, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return 0, false
+		}
+		timeZones.Store(timeZone, loaded)
+		tz = loaded
+	}
+
+	_, offset := now.In(tz).Zone()
+	return int64(offset / 60), true
+}
+
+// path returns the value found by walking the nested maps of the record.
+func path(record map[string]interface{}, keys ...string) interface{} {
+	var value interface{} = record
+	for _, key := range keys {
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = values[key]
+	}
+	return value
+}
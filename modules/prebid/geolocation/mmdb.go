@@ -0,0 +1,377 @@
+package geolocation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"os"
+)
+
+// metadataStartMarker precedes the metadata section at the end of the file.
+var metadataStartMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparatorSize is the number of zero bytes between the search tree and the data section.
+const dataSectionSeparatorSize = 16
+
+// maxDataDepth caps the nesting of maps and arrays, so that a corrupt file whose pointers form a cycle fails
+// to decode instead of overflowing the stack.
+const maxDataDepth = 512
+
+// Data types of the MaxMind DB data section.
+const (
+	typeExtended  = 0
+	typePointer   = 1
+	typeString    = 2
+	typeDouble    = 3
+	typeBytes     = 4
+	typeUint16    = 5
+	typeUint32    = 6
+	typeMap       = 7
+	typeInt32     = 8
+	typeUint64    = 9
+	typeUint128   = 10
+	typeArray     = 11
+	typeContainer = 12
+	typeEndMarker = 13
+	typeBool      = 14
+	typeFloat     = 15
+)
+
+// database is a reader of the MaxMind DB file format, the format used by
+// the MaxMind GeoIP2/GeoLite2 and the DB-IP databases.
+// See https://maxmind.github.io/MaxMind-DB/ for the format specification.
+type database struct {
+	tree         []byte
+	data         decoder
+	nodeCount    uint
+	recordSize   uint
+	ipVersion    uint
+	ipv4Start    uint
+	databaseType string
+	buildEpoch   uint64
+}
+
+func openDatabase(path string) (*database, error) {
+	buffer, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newDatabase(buffer)
+}
+
+func newDatabase(buffer []byte) (*database, error) {
+	markerStart := bytes.LastIndex(buffer, metadataStartMarker)
+	if markerStart == -1 {
+		return nil, errors.New("invalid MaxMind DB file: metadata section not found")
+	}
+
+	metadata, _, err := decoder{buffer: buffer[markerStart+len(metadataStartMarker):]}.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MaxMind DB metadata: %w", err)
+	}
+	fields, ok := metadata.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid MaxMind DB metadata: not a map")
+	}
+
+	db := &database{
+		nodeCount:    uint(uintValue(fields["node_count"])),
+		recordSize:   uint(uintValue(fields["record_size"])),
+		ipVersion:    uint(uintValue(fields["ip_version"])),
+		buildEpoch:   uintValue(fields["build_epoch"]),
+		databaseType: stringValue(fields["database_type"]),
+	}
+
+	if db.recordSize != 24 && db.recordSize != 28 && db.recordSize != 32 {
+		return nil, fmt.Errorf("unsupported MaxMind DB record size %d", db.recordSize)
+	}
+	if db.ipVersion != 4 && db.ipVersion != 6 {
+		return nil, fmt.Errorf("unsupported MaxMind DB ip version %d", db.ipVersion)
+	}
+
+	treeSize := db.nodeCount * db.recordSize / 4
+	dataStart := treeSize + dataSectionSeparatorSize
+	if dataStart > uint(markerStart) {
+		return nil, errors.New("invalid MaxMind DB file: search tree exceeds the file size")
+	}
+	db.tree = buffer[:treeSize]
+	db.data = decoder{buffer: buffer[dataStart:markerStart]}
+
+	// IPv4 addresses are stored in the ::/96 subtree of IPv6 databases
+	if db.ipVersion == 6 {
+		for i := 0; i < 96 && db.ipv4Start < db.nodeCount; i++ {
+			db.ipv4Start = db.readRecord(db.ipv4Start, 0)
+		}
+	}
+
+	return db, nil
+}
+
+// lookup returns the record stored for the IP address or nil if the database has no data for it.
+func (db *database) lookup(ip net.IP) (map[string]interface{}, error) {
+	node := uint(0)
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip = ipv4
+		if db.ipVersion == 6 {
+			node = db.ipv4Start
+		}
+	} else if db.ipVersion == 4 {
+		return nil, errors.New("IPv6 address lookup in IPv4 only database")
+	}
+
+	for i := 0; i < len(ip)*8 && node < db.nodeCount; i++ {
+		bit := uint(ip[i>>3]>>(7-uint(i&7))) & 1
+		node = db.readRecord(node, bit)
+	}
+
+	if node == db.nodeCount {
+		return nil, nil
+	}
+	if node < db.nodeCount {
+		return nil, errors.New("invalid MaxMind DB search tree: address bits exhausted")
+	}
+
+	value, _, err := db.data.decode(node - db.nodeCount - dataSectionSeparatorSize)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid MaxMind DB record: not a map")
+	}
+	return record, nil
+}
+
+// readRecord returns the left (index 0) or right (index 1) record of the search tree node.
+func (db *database) readRecord(node uint, index uint) uint {
+	b := db.tree
+	switch db.recordSize {
+	case 24:
+		offset := node*6 + index*3
+		return uint(b[offset])<<16 | uint(b[offset+1])<<8 | uint(b[offset+2])
+	case 28:
+		offset := node * 7
+		if index == 0 {
+			return uint(b[offset+3]&0xF0)<<20 | uint(b[offset])<<16 | uint(b[offset+1])<<8 | uint(b[offset+2])
+		}
+		return uint(b[offset+3]&0x0F)<<24 | uint(b[offset+4])<<16 | uint(b[offset+5])<<8 | uint(b[offset+6])
+	default:
+		offset := node*8 + index*4
+		return uint(binary.BigEndian.Uint32(b[offset : offset+4]))
+	}
+}
+
+// decoder decodes values of the data section.
+// Pointers are resolved relative to the start of the buffer.
+type decoder struct {
+	buffer []byte
+}
+
+// decode returns the value starting at the offset and the offset of the next value.
+func (d decoder) decode(offset uint) (interface{}, uint, error) {
+	return d.decodeAt(offset, 0)
+}
+
+func (d decoder) decodeAt(offset, depth uint) (interface{}, uint, error) {
+	if depth > maxDataDepth {
+		return nil, 0, fmt.Errorf("data nested deeper than %d levels", maxDataDepth)
+	}
+	if offset >= uint(len(d.buffer)) {
+		return nil, 0, errors.New("unexpected end of data")
+	}
+	ctrl := d.buffer[offset]
+	offset++
+
+	kind := uint(ctrl >> 5)
+	if kind == typePointer {
+		pointer, next, err := d.decodePointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		if pointer < uint(len(d.buffer)) && uint(d.buffer[pointer]>>5) == typePointer {
+			return nil, 0, errors.New("invalid pointer to a pointer")
+		}
+		value, _, err := d.decodeAt(pointer, depth)
+		return value, next, err
+	}
+
+	if kind == typeExtended {
+		if offset >= uint(len(d.buffer)) {
+			return nil, 0, errors.New("unexpected end of data")
+		}
+		kind = 7 + uint(d.buffer[offset])
+		offset++
+	}
+
+	size, offset, err := d.decodeSize(ctrl, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return d.decodeValue(kind, size, offset, depth)
+}
+
+func (d decoder) decodePointer(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint((ctrl>>3)&0x3) + 1
+	if offset+size > uint(len(d.buffer)) {
+		return 0, 0, errors.New("unexpected end of data")
+	}
+
+	prefix := uint(ctrl & 0x7)
+	b := d.buffer[offset : offset+size]
+	var pointer uint
+	switch size {
+	case 1:
+		pointer = prefix<<8 | uint(b[0])
+	case 2:
+		pointer = (prefix<<16 | uint(b[0])<<8 | uint(b[1])) + 2048
+	case 3:
+		pointer = (prefix<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])) + 526336
+	default:
+		pointer = uint(binary.BigEndian.Uint32(b))
+	}
+	return pointer, offset + size, nil
+}
+
+func (d decoder) decodeSize(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl & 0x1f)
+	if size < 29 {
+		return size, offset, nil
+	}
+
+	bytesToRead := size - 28
+	if offset+bytesToRead > uint(len(d.buffer)) {
+		return 0, 0, errors.New("unexpected end of data")
+	}
+	b := d.buffer[offset : offset+bytesToRead]
+	switch size {
+	case 29:
+		size = 29 + uint(b[0])
+	case 30:
+		size = 285 + (uint(b[0])<<8 | uint(b[1]))
+	default:
+		size = 65821 + (uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]))
+	}
+	return size, offset + bytesToRead, nil
+}
+
+func (d decoder) decodeValue(kind, size, offset, depth uint) (interface{}, uint, error) {
+	switch kind {
+	case typeMap:
+		return d.decodeMap(size, offset, depth+1)
+	case typeArray:
+		return d.decodeArray(size, offset, depth+1)
+	case typeBool:
+		return size != 0, offset, nil
+	case typeContainer, typeEndMarker:
+		return nil, 0, fmt.Errorf("unsupported data type %d", kind)
+	}
+
+	if offset+size > uint(len(d.buffer)) {
+		return nil, 0, errors.New("unexpected end of data")
+	}
+	b := d.buffer[offset : offset+size]
+	next := offset + size
+
+	switch kind {
+	case typeString:
+		return string(b), next, nil
+	case typeBytes:
+		return append([]byte(nil), b...), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %d", size)
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("invalid unsigned integer size %d", size)
+		}
+		var value uint64
+		for _, c := range b {
+			value = value<<8 | uint64(c)
+		}
+		return value, next, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("invalid int32 size %d", size)
+		}
+		var value uint32
+		for _, c := range b {
+			value = value<<8 | uint32(c)
+		}
+		return int64(int32(value)), next, nil
+	case typeUint128:
+		return new(big.Int).SetBytes(b), next, nil
+	}
+
+	return nil, 0, fmt.Errorf("unknown data type %d", kind)
+}
+
+func (d decoder) decodeMap(size, offset, depth uint) (interface{}, uint, error) {
+	// Every key and value takes at least one byte
+	values := make(map[string]interface{}, min(size, d.remaining(offset)/2))
+	for i := uint(0); i < size; i++ {
+		key, next, err := d.decodeAt(offset, depth)
+		if err != nil {
+			return nil, 0, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			return nil, 0, errors.New("invalid map key: not a string")
+		}
+
+		value, next, err := d.decodeAt(next, depth)
+		if err != nil {
+			return nil, 0, err
+		}
+		values[name] = value
+		offset = next
+	}
+	return values, offset, nil
+}
+
+func (d decoder) decodeArray(size, offset, depth uint) (interface{}, uint, error) {
+	// Every value takes at least one byte
+	values := make([]interface{}, 0, min(size, d.remaining(offset)))
+	for i := uint(0); i < size; i++ {
+		value, next, err := d.decodeAt(offset, depth)
+		if err != nil {
+			return nil, 0, err
+		}
+		values = append(values, value)
+		offset = next
+	}
+	return values, offset, nil
+}
+
+// remaining is the number of bytes of the buffer from the offset on.
+func (d decoder) remaining(offset uint) uint {
+	if offset >= uint(len(d.buffer)) {
+		return 0
+	}
+	return uint(len(d.buffer)) - offset
+}
+
+func uintValue(value interface{}) uint64 {
+	if v, ok := value.(uint64); ok {
+		return v
+	}
+	return 0
+}
+
+func stringValue(value interface{}) string {
+	if v, ok := value.(string); ok {
+		return v
+	}
+	return ""
+}
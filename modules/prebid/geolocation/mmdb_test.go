@@ -0,0 +1,346 @@
+package geolocation
+
+import (
+	"encoding/binary"
+	"math"
+	"net"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseLookup(t *testing.T) {
+	networks := []testNetwork{
+		{cidr: "1.2.3.0/24", record: map[string]interface{}{"country": map[string]interface{}{"iso_code": "FR"}}},
+		{cidr: "8.0.0.0/8", record: map[string]interface{}{"country": map[string]interface{}{"iso_code": "US"}}},
+		{cidr: "2001:db8::/32", record: map[string]interface{}{"country": map[string]interface{}{"iso_code": "DE"}}},
+	}
+
+	testCases := []struct {
+		description     string
+		givenIPVersion  int
+		givenRecordSize int
+		givenIP         string
+		expectedCountry string
+		expectedError   bool
+	}{
+		{description: "IPv4 in IPv4 database", givenIPVersion: 4, givenRecordSize: 24, givenIP: "1.2.3.4", expectedCountry: "FR"},
+		{description: "IPv4 in IPv6 database", givenIPVersion: 6, givenRecordSize: 24, givenIP: "8.8.8.8", expectedCountry: "US"},
+		{description: "IPv6 in IPv6 database", givenIPVersion: 6, givenRecordSize: 24, givenIP: "2001:db8::1", expectedCountry: "DE"},
+		{description: "28 bit records", givenIPVersion: 6, givenRecordSize: 28, givenIP: "1.2.3.255", expectedCountry: "FR"},
+		{description: "32 bit records", givenIPVersion: 6, givenRecordSize: 32, givenIP: "2001:db8:ffff::1", expectedCountry: "DE"},
+		{description: "Address not found", givenIPVersion: 6, givenRecordSize: 24, givenIP: "1.2.4.1"},
+		{description: "IPv6 in IPv4 database", givenIPVersion: 4, givenRecordSize: 24, givenIP: "2001:db8::1", expectedError: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			givenNetworks := networks
+			if test.givenIPVersion == 4 {
+				givenNetworks = networks[:2]
+			}
+
+			db, err := newDatabase(buildTestDatabase(t, test.givenIPVersion, test.givenRecordSize, givenNetworks))
+			require.NoError(t, err)
+			assert.Equal(t, "Test-City", db.databaseType)
+
+			record, err := db.lookup(net.ParseIP(test.givenIP))
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			if test.expectedCountry == "" {
+				assert.Nil(t, record)
+			} else {
+				assert.Equal(t, test.expectedCountry, path(record, "country", "iso_code"))
+			}
+		})
+	}
+}
+
+func TestNewDatabaseInvalidFile(t *testing.T) {
+	_, err := newDatabase([]byte("not a database"))
+	assert.EqualError(t, err, "invalid MaxMind DB file: metadata section not found")
+
+	metadata := append([]byte(nil), metadataStartMarker...)
+	metadata = append(metadata, encodeTestValue(map[string]interface{}{
+		"node_count":  uint32(1),
+		"record_size": uint16(20),
+		"ip_version":  uint16(4),
+	})...)
+	_, err = newDatabase(metadata)
+	assert.EqualError(t, err, "unsupported MaxMind DB record size 20")
+}
+
+func TestDecoder(t *testing.T) {
+	// "hi" followed by a pointer to it
+	d := decoder{buffer: []byte{0x42, 'h', 'i', 0x20, 0x00}}
+
+	value, next, err := d.decode(3)
+	assert.NoError(t, err)
+	assert.Equal(t, "hi", value)
+	assert.Equal(t, uint(5), next)
+
+	values := map[string]interface{}{
+		"double": 1.5,
+		"uint16": uint16(7),
+		"uint64": uint64(1) << 40,
+		"int32":  int32(-3),
+		"bool":   true,
+		"array":  []interface{}{"a", "b"},
+		"long":   string(make([]byte, 300)),
+	}
+	value, _, err = decoder{buffer: encodeTestValue(values)}.decode(0)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"double": 1.5,
+		"uint16": uint64(7),
+		"uint64": uint64(1) << 40,
+		"int32":  int64(-3),
+		"bool":   true,
+		"array":  []interface{}{"a", "b"},
+		"long":   string(make([]byte, 300)),
+	}, value)
+
+	_, _, err = decoder{buffer: []byte{0x45, 'h'}}.decode(0)
+	assert.EqualError(t, err, "unexpected end of data")
+}
+
+func TestDecoderMalformed(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenBuffer   []byte
+		givenOffset   uint
+		expectedError string
+	}{
+		{
+			description:   "Pointer to a pointer",
+			givenBuffer:   []byte{0x42, 'h', 'i', 0x20, 0x00, 0x20, 0x03},
+			givenOffset:   5,
+			expectedError: "invalid pointer to a pointer",
+		},
+		{
+			description:   "Pointer to itself",
+			givenBuffer:   []byte{0x20, 0x00},
+			expectedError: "invalid pointer to a pointer",
+		},
+		{
+			description:   "Map containing a pointer to itself",
+			givenBuffer:   []byte{0xE1, 0x41, 'a', 0x20, 0x00},
+			expectedError: "data nested deeper than 512 levels",
+		},
+		{
+			description:   "Array containing a pointer to itself",
+			givenBuffer:   []byte{0x01, 0x04, 0x20, 0x00},
+			expectedError: "data nested deeper than 512 levels",
+		},
+		{
+			description:   "Map size larger than the data",
+			givenBuffer:   []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x41, 'a'},
+			expectedError: "unexpected end of data",
+		},
+		{
+			description:   "Array size larger than the data",
+			givenBuffer:   []byte{0x1F, 0x04, 0xFF, 0xFF, 0xFF, 0x41, 'a'},
+			expectedError: "unexpected end of data",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			_, _, err := decoder{buffer: test.givenBuffer}.decode(test.givenOffset)
+			runtime.ReadMemStats(&after)
+
+			assert.EqualError(t, err, test.expectedError)
+			assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20), "allocations are bound by the data size")
+		})
+	}
+}
+
+func TestNewDatabaseCyclicMetadata(t *testing.T) {
+	metadata := append([]byte(nil), metadataStartMarker...)
+	metadata = append(metadata, 0xE1, 0x41, 'a', 0x20, 0x00)
+
+	_, err := newDatabase(metadata)
+	assert.EqualError(t, err, "invalid MaxMind DB metadata: data nested deeper than 512 levels")
+}
+
+type testNetwork struct {
+	cidr   string
+	record map[string]interface{}
+}
+
+type testNode struct {
+	children [2]*testNode
+	data     [2]int
+}
+
+// buildTestDatabase writes a MaxMind DB file holding the records of the networks.
+func buildTestDatabase(t *testing.T, ipVersion, recordSize int, networks []testNetwork) []byte {
+	var data []byte
+	root := &testNode{data: [2]int{-1, -1}}
+
+	for _, network := range networks {
+		_, ipNet, err := net.ParseCIDR(network.cidr)
+		require.NoError(t, err)
+
+		ip := []byte(ipNet.IP)
+		prefixLen, _ := ipNet.Mask.Size()
+		if ipv4 := ipNet.IP.To4(); ipv4 != nil && ipVersion == 6 {
+			ip = append(make([]byte, 12), ipv4...)
+			prefixLen += 96
+		}
+
+		node := root
+		for i := 0; i < prefixLen; i++ {
+			bit := (ip[i/8] >> (7 - i%8)) & 1
+			if i == prefixLen-1 {
+				node.data[bit] = len(data)
+				break
+			}
+			if node.children[bit] == nil {
+				node.children[bit] = &testNode{data: [2]int{-1, -1}}
+			}
+			node = node.children[bit]
+		}
+		data = append(data, encodeTestValue(network.record)...)
+	}
+
+	var nodes []*testNode
+	index := map[*testNode]int{}
+	for queue := []*testNode{root}; len(queue) > 0; queue = queue[1:] {
+		index[queue[0]] = len(nodes)
+		nodes = append(nodes, queue[0])
+		for _, child := range queue[0].children {
+			if child != nil {
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	nodeCount := len(nodes)
+	var tree []byte
+	for _, node := range nodes {
+		var records [2]uint32
+		for i := range records {
+			switch {
+			case node.children[i] != nil:
+				records[i] = uint32(index[node.children[i]])
+			case node.data[i] >= 0:
+				records[i] = uint32(nodeCount + dataSectionSeparatorSize + node.data[i])
+			default:
+				records[i] = uint32(nodeCount)
+			}
+		}
+		tree = append(tree, encodeTestNode(records, recordSize)...)
+	}
+
+	file := append(tree, make([]byte, dataSectionSeparatorSize)...)
+	file = append(file, data...)
+	file = append(file, metadataStartMarker...)
+	file = append(file, encodeTestValue(map[string]interface{}{
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(recordSize),
+		"ip_version":                  uint16(ipVersion),
+		"database_type":               "Test-City",
+		"build_epoch":                 uint64(1700000000),
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+	})...)
+	return file
+}
+
+func encodeTestNode(records [2]uint32, recordSize int) []byte {
+	left, right := records[0], records[1]
+	switch recordSize {
+	case 24:
+		return []byte{byte(left >> 16), byte(left >> 8), byte(left), byte(right >> 16), byte(right >> 8), byte(right)}
+	case 28:
+		return []byte{byte(left >> 16), byte(left >> 8), byte(left), byte((left>>24)<<4 | (right>>24)&0x0F), byte(right >> 16), byte(right >> 8), byte(right)}
+	default:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint32(b, left)
+		binary.BigEndian.PutUint32(b[4:], right)
+		return b
+	}
+}
+
+func encodeTestValue(value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return append(encodeTestControl(typeString, len(v)), v...)
+	case float64:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, math.Float64bits(v))
+		return append(encodeTestControl(typeDouble, 8), b...)
+	case uint16:
+		return encodeTestUint(typeUint16, uint64(v))
+	case uint32:
+		return encodeTestUint(typeUint32, uint64(v))
+	case uint64:
+		return encodeTestUint(typeUint64, v)
+	case int32:
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, uint32(v))
+		return append(encodeTestControl(typeInt32, 4), b...)
+	case bool:
+		size := 0
+		if v {
+			size = 1
+		}
+		return encodeTestControl(typeBool, size)
+	case []interface{}:
+		b := encodeTestControl(typeArray, len(v))
+		for _, item := range v {
+			b = append(b, encodeTestValue(item)...)
+		}
+		return b
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		b := encodeTestControl(typeMap, len(v))
+		for _, key := range keys {
+			b = append(b, encodeTestValue(key)...)
+			b = append(b, encodeTestValue(v[key])...)
+		}
+		return b
+	}
+	panic("unsupported test value")
+}
+
+func encodeTestUint(kind int, value uint64) []byte {
+	var b []byte
+	for ; value > 0; value >>= 8 {
+		b = append([]byte{byte(value)}, b...)
+	}
+	return append(encodeTestControl(kind, len(b)), b...)
+}
+
+func encodeTestControl(kind, size int) []byte {
+	var sizeBits byte
+	var sizeBytes []byte
+	switch {
+	case size < 29:
+		sizeBits = byte(size)
+	case size < 285:
+		sizeBits, sizeBytes = 29, []byte{byte(size - 29)}
+	default:
+		sizeBits, sizeBytes = 30, []byte{byte((size - 285) >> 8), byte(size - 285)}
+	}
+
+	if kind <= typeMap {
+		return append([]byte{byte(kind)<<5 | sizeBits}, sizeBytes...)
+	}
+	return append([]byte{sizeBits, byte(kind - 7)}, sizeBytes...)
+}
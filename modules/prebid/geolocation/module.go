@@ -0,0 +1,57 @@
+package geolocation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	// Time zones of the database records are resolved without relying on the host zoneinfo files
+	_ "time/tzdata"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+)
+
+func Builder(rawConfig json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	loader, err := newDatabaseLoader(cfg.Database.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load database: %w", err)
+	}
+
+	if cfg.Database.ReloadIntervalSeconds > 0 {
+		go loader.run(time.Duration(cfg.Database.ReloadIntervalSeconds) * time.Second)
+	}
+
+	return Module{config: cfg, loader: loader}, nil
+}
+
+type Module struct {
+	config config
+	loader *databaseLoader
+}
+
+// HandleProcessedAuctionHook fills device.geo with the location of device.ip or device.ipv6.
+// Values sent by the caller are kept unless the module is configured to overwrite them.
+func (m Module) HandleProcessedAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}
+
+	cfg, enabled, err := m.config.merge(miCtx.AccountConfig)
+	if err != nil {
+		return result, err
+	}
+	if !enabled {
+		return result, nil
+	}
+
+	return handleProcessedAuctionHook(cfg, m.loader.database(), miCtx.PreciseGeoAllowed, payload, time.Now())
+}
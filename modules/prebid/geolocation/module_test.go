@@ -0,0 +1,238 @@
+package geolocation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNetworks = []testNetwork{
+	{
+		cidr: "81.2.69.0/24",
+		record: map[string]interface{}{
+			"country":      map[string]interface{}{"iso_code": "FR"},
+			"subdivisions": []interface{}{map[string]interface{}{"iso_code": "IDF"}},
+			"city":         map[string]interface{}{"names": map[string]interface{}{"en": "Paris"}},
+			"postal":       map[string]interface{}{"code": "75001"},
+			"location":     map[string]interface{}{"time_zone": "Europe/Paris"},
+		},
+	},
+	{
+		cidr: "2001:db8::/32",
+		record: map[string]interface{}{
+			"country":      map[string]interface{}{"iso_code": "US"},
+			"subdivisions": []interface{}{map[string]interface{}{"iso_code": "NY"}},
+			"city":         map[string]interface{}{"names": map[string]interface{}{"en": "New York"}},
+			"postal":       map[string]interface{}{"code": "10001"},
+			"location":     map[string]interface{}{"metro_code": uint16(501), "time_zone": "America/New_York"},
+		},
+	},
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	db, err := newDatabase(buildTestDatabase(t, 6, 24, testNetworks))
+	require.NoError(t, err)
+
+	now := time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		description     string
+		givenDevice     *openrtb2.Device
+		givenOverwrite  bool
+		givenPreciseGeo bool
+		expectedGeo     *openrtb2.Geo
+		expectedTags    hookanalytics.Analytics
+	}{
+		{
+			description:     "Geo filled from the IPv4 address",
+			givenDevice:     &openrtb2.Device{IP: "81.2.69.160"},
+			givenPreciseGeo: true,
+			expectedGeo:     &openrtb2.Geo{Country: "FRA", Region: "IDF", City: "Paris", ZIP: "75001", UTCOffset: 60, Type: adcom1.LocationIP},
+			expectedTags:    getLookupTags(ipVersion4, "FRA", []string{"country", "region", "city", "zip", "utcoffset", "type"}),
+		},
+		{
+			description:     "Geo filled from the IPv6 address",
+			givenDevice:     &openrtb2.Device{IPv6: "2001:db8::1"},
+			givenPreciseGeo: true,
+			expectedGeo:     &openrtb2.Geo{Country: "USA", Region: "NY", Metro: "501", City: "New York", ZIP: "10001", UTCOffset: -300, Type: adcom1.LocationIP},
+			expectedTags:    getLookupTags(ipVersion6, "USA", []string{"country", "region", "metro", "city", "zip", "utcoffset", "type"}),
+		},
+		{
+			description:     "Caller data kept",
+			givenDevice:     &openrtb2.Device{IP: "81.2.69.160", Geo: &openrtb2.Geo{Country: "BEL", City: "Brussels", Type: adcom1.LocationGPS}},
+			givenPreciseGeo: true,
+			expectedGeo:     &openrtb2.Geo{Country: "BEL", Region: "IDF", City: "Brussels", ZIP: "75001", UTCOffset: 60, Type: adcom1.LocationGPS},
+			expectedTags:    getLookupTags(ipVersion4, "FRA", []string{"region", "zip", "utcoffset"}),
+		},
+		{
+			description:     "Caller data overwritten if configured",
+			givenDevice:     &openrtb2.Device{IP: "81.2.69.160", Geo: &openrtb2.Geo{Country: "BEL", City: "Brussels", Type: adcom1.LocationGPS}},
+			givenOverwrite:  true,
+			givenPreciseGeo: true,
+			expectedGeo:     &openrtb2.Geo{Country: "FRA", Region: "IDF", City: "Paris", ZIP: "75001", UTCOffset: 60, Type: adcom1.LocationIP},
+			expectedTags:    getLookupTags(ipVersion4, "FRA", []string{"country", "region", "city", "zip", "utcoffset", "type"}),
+		},
+		{
+			description:  "Only coarse geo filled if precise geo is not allowed",
+			givenDevice:  &openrtb2.Device{IP: "81.2.69.160"},
+			expectedGeo:  &openrtb2.Geo{Country: "FRA", Region: "IDF", UTCOffset: 60, Type: adcom1.LocationIP},
+			expectedTags: getLookupTags(ipVersion4, "FRA", []string{"country", "region", "utcoffset", "type"}),
+		},
+		{
+			description:     "Request not changed if the address is not found",
+			givenDevice:     &openrtb2.Device{IP: "10.0.0.1"},
+			givenPreciseGeo: true,
+			expectedTags:    newLookupTags(ipVersion4, nil, nil),
+		},
+		{
+			description:     "Request not changed if there is no address",
+			givenDevice:     &openrtb2.Device{UA: "some-ua"},
+			givenPreciseGeo: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			payload := hookstage.ProcessedAuctionRequestPayload{
+				Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: test.givenDevice}},
+			}
+			originalGeo := test.givenDevice.Geo
+
+			result, err := handleProcessedAuctionHook(config{Overwrite: test.givenOverwrite}, db, test.givenPreciseGeo, payload, now)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedTags, result.AnalyticsTags)
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				require.NoError(t, err)
+			}
+
+			if test.expectedGeo == nil {
+				assert.Empty(t, result.ChangeSet.Mutations())
+				assert.Equal(t, originalGeo, payload.Request.Device.Geo)
+				return
+			}
+			assert.Equal(t, test.expectedGeo, payload.Request.Device.Geo)
+			assert.Equal(t, originalGeo, test.givenDevice.Geo, "the original device must not be changed")
+		})
+	}
+}
+
+func TestModuleHandleProcessedAuctionHook(t *testing.T) {
+	dbPath := writeTestDatabase(t, testNetworks)
+
+	module, err := Builder(json.RawMessage(fmt.Sprintf(`{"enabled": true, "database": {"path": %q, "reload_interval_seconds": -1}}`, dbPath)), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description         string
+		givenAccountConfig  json.RawMessage
+		expectedCountry     string
+		expectedCity        string
+		expectedMutationLen int
+		expectedError       bool
+	}{
+		{
+			description:         "Enabled without account config",
+			expectedCountry:     "FRA",
+			expectedCity:        "Brussels",
+			expectedMutationLen: 1,
+		},
+		{
+			description:         "Account overwrites caller data",
+			givenAccountConfig:  json.RawMessage(`{"overwrite": true}`),
+			expectedCountry:     "FRA",
+			expectedCity:        "Paris",
+			expectedMutationLen: 1,
+		},
+		{
+			description:        "Disabled for account",
+			givenAccountConfig: json.RawMessage(`{"enabled": false}`),
+		},
+		{
+			description:        "Invalid account config",
+			givenAccountConfig: json.RawMessage(`{"overwrite": "yes"}`),
+			expectedError:      true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			payload := hookstage.ProcessedAuctionRequestPayload{
+				Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+					Device: &openrtb2.Device{IP: "81.2.69.160", Geo: &openrtb2.Geo{City: "Brussels"}},
+				}},
+			}
+			miCtx := hookstage.ModuleInvocationContext{AccountConfig: test.givenAccountConfig, PreciseGeoAllowed: true}
+
+			result, err := module.(Module).HandleProcessedAuctionHook(context.Background(), miCtx, payload)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result.ChangeSet.Mutations(), test.expectedMutationLen)
+			if test.expectedMutationLen == 0 {
+				return
+			}
+
+			payload, err = result.ChangeSet.Mutations()[0].Apply(payload)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedCountry, payload.Request.Device.Geo.Country)
+			assert.Equal(t, test.expectedCity, payload.Request.Device.Geo.City)
+		})
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	_, err := Builder(json.RawMessage(`{"enabled": true}`), moduledeps.ModuleDeps{})
+	assert.EqualError(t, err, "database.path is required")
+
+	_, err = Builder(json.RawMessage(`{"enabled": true, "database": {"path": "does-not-exist.mmdb"}}`), moduledeps.ModuleDeps{})
+	assert.ErrorContains(t, err, "failed to load database")
+}
+
+func TestDatabaseLoaderReload(t *testing.T) {
+	dbPath := writeTestDatabase(t, testNetworks)
+
+	loader, err := newDatabaseLoader(dbPath)
+	require.NoError(t, err)
+	first := loader.database()
+
+	assert.NoError(t, loader.reload())
+	assert.Same(t, first, loader.database(), "unchanged file must not be read again")
+
+	require.NoError(t, os.WriteFile(dbPath, []byte("corrupted"), 0644))
+	require.NoError(t, os.Chtimes(dbPath, time.Now(), time.Now().Add(time.Minute)))
+	assert.Error(t, loader.reload())
+	assert.Same(t, first, loader.database(), "database must be kept if the new file is invalid")
+
+	require.NoError(t, os.WriteFile(dbPath, buildTestDatabase(t, 4, 24, testNetworks[:1]), 0644))
+	require.NoError(t, os.Chtimes(dbPath, time.Now(), time.Now().Add(2*time.Minute)))
+	assert.NoError(t, loader.reload())
+	assert.NotSame(t, first, loader.database())
+	assert.Equal(t, uint(4), loader.database().ipVersion)
+}
+
+func writeTestDatabase(t *testing.T, networks []testNetwork) string {
+	dbPath := filepath.Join(t.TempDir(), "city.mmdb")
+	require.NoError(t, os.WriteFile(dbPath, buildTestDatabase(t, 6, 24, networks), 0644))
+	return dbPath
+}
+
+func getLookupTags(ipVersion, country string, fields []string) hookanalytics.Analytics {
+	return newLookupTags(ipVersion, &location{country: country}, fields)
+}
@@ -0,0 +1,29 @@
+package rulesengine
+
+import (
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+)
+
+const rulesEngineActivity = "rules-engine"
+
+const (
+	ruleAnalyticKey    = "rule"
+	actionsAnalyticKey = "actions"
+)
+
+// newRulesTags reports the matched rules, one result per rule.
+func newRulesTags(results []hookanalytics.Result) hookanalytics.Analytics {
+	if len(results) == 0 {
+		return hookanalytics.Analytics{}
+	}
+
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{
+			{
+				Name:    rulesEngineActivity,
+				Status:  hookanalytics.ActivityStatusSuccess,
+				Results: results,
+			},
+		},
+	}
+}
@@ -0,0 +1,113 @@
+package rulesengine
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+const (
+	channelWeb  = "web"
+	channelApp  = "app"
+	channelDOOH = "dooh"
+)
+
+// traceVerbose is the ext.prebid.trace level returning the debug messages of the hooks.
+const traceVerbose = "verbose"
+
+// attributes are the values of the request the rule conditions are matched against.
+type attributes struct {
+	channel    string
+	domain     string
+	bundle     string
+	country    string
+	deviceType int
+	gppSID     []int8
+	mediaTypes []string
+	bidder     string
+	dataCenter string
+	// debug enables the trace of the rules evaluation.
+	debug bool
+}
+
+func newAttributes(request *openrtb_ext.RequestWrapper, bidder, dataCenter string) attributes {
+	attrs := attributes{bidder: bidder, dataCenter: dataCenter}
+
+	switch {
+	case request.Site != nil:
+		attrs.channel = channelWeb
+		attrs.domain = request.Site.Domain
+	case request.App != nil:
+		attrs.channel = channelApp
+		attrs.bundle = request.App.Bundle
+	case request.DOOH != nil:
+		attrs.channel = channelDOOH
+	}
+
+	if request.Device != nil {
+		attrs.deviceType = int(request.Device.DeviceType)
+		if request.Device.Geo != nil {
+			attrs.country = request.Device.Geo.Country
+		}
+	}
+
+	if request.Regs != nil {
+		attrs.gppSID = request.Regs.GPPSID
+	}
+
+	for _, imp := range request.Imp {
+		present := []bool{imp.Banner != nil, imp.Video != nil, imp.Audio != nil, imp.Native != nil}
+		for i, mediaType := range supportedMediaTypes {
+			if present[i] && !slices.Contains(attrs.mediaTypes, mediaType) {
+				attrs.mediaTypes = append(attrs.mediaTypes, mediaType)
+			}
+		}
+	}
+
+	attrs.debug = request.Test == 1
+	if requestExt, err := request.GetRequestExt(); err == nil {
+		if prebid := requestExt.GetPrebid(); prebid != nil {
+			attrs.debug = attrs.debug || prebid.Debug || prebid.Trace == traceVerbose
+		}
+	}
+
+	return attrs
+}
+
+// match reports if the attributes match every condition, or returns the first condition not matched.
+func (c conditionsConfig) match(attrs attributes) (bool, string) {
+	switch {
+	case len(c.Channel) > 0 && !slices.Contains(c.Channel, attrs.channel):
+		return false, "channel"
+	case len(c.Domain) > 0 && !containsFold(c.Domain, attrs.domain):
+		return false, "domain"
+	case len(c.Bundle) > 0 && !containsFold(c.Bundle, attrs.bundle):
+		return false, "bundle"
+	case len(c.Country) > 0 && !containsFold(c.Country, attrs.country):
+		return false, "country"
+	case len(c.DeviceType) > 0 && !slices.Contains(c.DeviceType, attrs.deviceType):
+		return false, "device_type"
+	case len(c.GPPSID) > 0 && !containsAny(c.GPPSID, attrs.gppSID):
+		return false, "gpp_sid"
+	case len(c.Bidder) > 0 && !containsFold(c.Bidder, attrs.bidder):
+		return false, "bidder"
+	case len(c.MediaType) > 0 && !containsAny(c.MediaType, attrs.mediaTypes):
+		return false, "media_type"
+	case len(c.DataCenter) > 0 && !slices.Contains(c.DataCenter, attrs.dataCenter):
+		return false, "datacenter"
+	}
+	return true, ""
+}
+
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
+
+func containsAny[T comparable](values, candidates []T) bool {
+	return slices.ContainsFunc(candidates, func(candidate T) bool {
+		return slices.Contains(values, candidate)
+	})
+}
@@ -0,0 +1,76 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// config is the host config of the module, the host rules apply to every account.
+type config struct {
+	Rules []ruleConfig `json:"rules"`
+}
+
+// accountConfig is the account config of the module, the account rules apply after the host rules.
+type accountConfig struct {
+	Enabled *bool        `json:"enabled"`
+	Rules   []ruleConfig `json:"rules"`
+}
+
+type ruleConfig struct {
+	// Name identifies the rule in the analytics tags and the trace.
+	Name string `json:"name"`
+	// Stage is the stage the rule is executed at, processed_auction_request if not set.
+	Stage      string           `json:"stage"`
+	Conditions conditionsConfig `json:"conditions"`
+	Actions    []actionConfig   `json:"actions"`
+}
+
+// conditionsConfig lists the values a rule applies to. A rule matches if every condition set
+// matches, a condition matches if the value of the request is one of the listed values.
+type conditionsConfig struct {
+	Channel    []string `json:"channel"`
+	Domain     []string `json:"domain"`
+	Bundle     []string `json:"bundle"`
+	Country    []string `json:"country"`
+	DeviceType []int    `json:"device_type"`
+	GPPSID     []int8   `json:"gpp_sid"`
+	Bidder     []string `json:"bidder"`
+	MediaType  []string `json:"media_type"`
+	DataCenter []string `json:"datacenter"`
+}
+
+type actionConfig struct {
+	Type string `json:"type"`
+	// Path is the field updated by the set and remove actions.
+	Path string `json:"path"`
+	// Value is the value written by the set action.
+	Value json.RawMessage `json:"value"`
+	// From is the field copied by the set action.
+	From string `json:"from"`
+	// Where limits the remove action to the array elements having one of the listed values.
+	Where map[string][]string `json:"where"`
+	// Bidders are the bidders removed by the exclude_bidders action.
+	Bidders []string `json:"bidders"`
+	// NBR is the no bid reason of the reject action.
+	NBR int `json:"nbr"`
+	// Values are the values the analytics_tag action adds to the analytics tags.
+	Values map[string]interface{} `json:"values"`
+}
+
+func newConfig(data json.RawMessage) (config, error) {
+	var cfg config
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+	return cfg, nil
+}
+
+func newAccountConfig(data json.RawMessage) (accountConfig, error) {
+	var cfg accountConfig
+	if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse account config: %s", err)
+	}
+	return cfg, nil
+}
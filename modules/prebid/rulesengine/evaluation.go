@@ -0,0 +1,73 @@
+package rulesengine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+)
+
+// evaluation is the outcome of matching the rules of a stage.
+type evaluation struct {
+	// reject is the first reject action of the matched rules.
+	reject *action
+	// updates are the set and remove actions of the matched rules, in order.
+	updates []action
+	// excludedBidders are the bidders of the exclude_bidders actions of the matched rules.
+	excludedBidders []string
+	results         []hookanalytics.Result
+	trace           []string
+}
+
+// evaluate matches the rules against the attributes and collects the actions of the matched rules.
+// The evaluation stops at the first matched rule rejecting the payload.
+func evaluate(rules []rule, attrs attributes, subject string, appliedTo hookanalytics.AppliedTo) evaluation {
+	var e evaluation
+	for _, r := range rules {
+		matched, condition := r.conditions.match(attrs)
+		if !matched {
+			if attrs.debug {
+				e.trace = append(e.trace, fmt.Sprintf("Rule %s not matched for %s: %s condition", r.name, subject, condition))
+			}
+			continue
+		}
+
+		values := map[string]interface{}{ruleAnalyticKey: r.name}
+		status := hookanalytics.ResultStatusAllow
+		var applied []string
+		for i := range r.actions {
+			a := &r.actions[i]
+			applied = append(applied, a.String())
+
+			switch a.kind {
+			case actionSet, actionRemove:
+				e.updates = append(e.updates, *a)
+				status = hookanalytics.ResultStatusModify
+			case actionExcludeBidders:
+				e.excludedBidders = append(e.excludedBidders, a.bidders...)
+				status = hookanalytics.ResultStatusModify
+			case actionReject:
+				e.reject = a
+				status = hookanalytics.ResultStatusBlock
+			case actionAnalyticsTag:
+				for key, value := range a.values {
+					values[key] = value
+				}
+			}
+			if e.reject != nil {
+				break
+			}
+		}
+
+		values[actionsAnalyticKey] = applied
+		e.results = append(e.results, hookanalytics.Result{Status: status, Values: values, AppliedTo: appliedTo})
+		if attrs.debug {
+			e.trace = append(e.trace, fmt.Sprintf("Rule %s matched for %s: %s", r.name, subject, strings.Join(applied, ", ")))
+		}
+
+		if e.reject != nil {
+			break
+		}
+	}
+	return e
+}
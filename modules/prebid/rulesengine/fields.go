@@ -0,0 +1,181 @@
+package rulesengine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// wildcard is the path segment matching every element of an array.
+const wildcard = "*"
+
+var pathEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `|`, `\|`, `#`, `\#`, `@`, `\@`, `!`, `\!`)
+
+// fieldPath is a JSON field path such as user.eids or imp.*.ext.gpid.
+type fieldPath []string
+
+func newFieldPath(path string) (fieldPath, error) {
+	if path == "" {
+		return nil, errors.New("path is required")
+	}
+
+	segments := strings.Split(path, ".")
+	if slices.Contains(segments, "") {
+		return nil, fmt.Errorf("invalid path %q", path)
+	}
+	return segments, nil
+}
+
+func (p fieldPath) wildcards() int {
+	count := 0
+	for _, segment := range p {
+		if segment == wildcard {
+			count++
+		}
+	}
+	return count
+}
+
+// resolvedPath is a field path with the wildcards replaced by array indices.
+type resolvedPath struct {
+	path    string
+	indices []int
+}
+
+func (r resolvedPath) child(segment string, index int) resolvedPath {
+	child := resolvedPath{path: segment, indices: r.indices}
+	if r.path != "" {
+		child.path = r.path + "." + segment
+	}
+	if index >= 0 {
+		child.indices = append(slices.Clone(r.indices), index)
+	}
+	return child
+}
+
+// resolve returns the paths of the document matching the field path, a wildcard matches
+// every element of the array present in the document.
+func (p fieldPath) resolve(doc []byte) []resolvedPath {
+	resolved := []resolvedPath{{}}
+	for _, segment := range p {
+		var next []resolvedPath
+		for _, r := range resolved {
+			if segment != wildcard {
+				next = append(next, r.child(pathEscaper.Replace(segment), -1))
+				continue
+			}
+
+			length := int(gjson.GetBytes(doc, r.child("#", -1).path).Int())
+			for i := 0; i < length; i++ {
+				next = append(next, r.child(strconv.Itoa(i), i))
+			}
+		}
+		resolved = next
+	}
+	return resolved
+}
+
+// bind replaces the wildcards of the field path with the indices, in order.
+func (p fieldPath) bind(indices []int) string {
+	segments := make([]string, len(p))
+	next := 0
+	for i, segment := range p {
+		if segment == wildcard && next < len(indices) {
+			segments[i] = strconv.Itoa(indices[next])
+			next++
+		} else {
+			segments[i] = pathEscaper.Replace(segment)
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+// applyFieldActions applies the set and remove actions to the JSON document, in order.
+func applyFieldActions(doc []byte, actions []action) ([]byte, error) {
+	var err error
+	for _, a := range actions {
+		switch a.kind {
+		case actionSet:
+			doc, err = setField(doc, a)
+		case actionRemove:
+			doc, err = removeField(doc, a)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to %s: %s", a, err)
+		}
+	}
+	return doc, nil
+}
+
+func setField(doc []byte, a action) ([]byte, error) {
+	var err error
+	for _, target := range a.path.resolve(doc) {
+		value := a.value
+		if a.from != nil {
+			source := gjson.GetBytes(doc, a.from.bind(target.indices))
+			if !source.Exists() {
+				continue
+			}
+			value = []byte(source.Raw)
+		}
+
+		if doc, err = sjson.SetRawBytes(doc, target.path, value); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+func removeField(doc []byte, a action) ([]byte, error) {
+	var err error
+	for _, target := range a.path.resolve(doc) {
+		if len(a.where) == 0 {
+			doc, err = sjson.DeleteBytes(doc, target.path)
+		} else {
+			doc, err = removeElements(doc, target.path, a.where)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// removeElements removes the elements of the array having one of the values of every where field.
+// The field is removed if no element is left.
+func removeElements(doc []byte, path string, where map[string][]string) ([]byte, error) {
+	array := gjson.GetBytes(doc, path)
+	if !array.IsArray() {
+		return doc, nil
+	}
+
+	elements := array.Array()
+	kept := make([]string, 0, len(elements))
+	for _, element := range elements {
+		if !matchesWhere(element, where) {
+			kept = append(kept, element.Raw)
+		}
+	}
+
+	switch len(kept) {
+	case len(elements):
+		return doc, nil
+	case 0:
+		return sjson.DeleteBytes(doc, path)
+	}
+	return sjson.SetRawBytes(doc, path, []byte("["+strings.Join(kept, ",")+"]"))
+}
+
+func matchesWhere(element gjson.Result, where map[string][]string) bool {
+	for field, values := range where {
+		if !slices.Contains(values, element.Get(field).String()) {
+			return false
+		}
+	}
+	return true
+}
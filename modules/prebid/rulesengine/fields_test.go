@@ -0,0 +1,87 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyFieldActions(t *testing.T) {
+	testCases := []struct {
+		description  string
+		givenDoc     string
+		givenActions string
+		expectedDoc  string
+	}{
+		{
+			description:  "Set value",
+			givenDoc:     `{"tmax":1000}`,
+			givenActions: `[{"type": "set", "path": "tmax", "value": 500}, {"type": "set", "path": "ext.prebid.debug", "value": true}]`,
+			expectedDoc:  `{"tmax":500,"ext":{"prebid":{"debug":true}}}`,
+		},
+		{
+			description:  "Set from the field of the same array element",
+			givenDoc:     `{"imp":[{"id":"1","tagid":"slot-1"},{"id":"2"},{"id":"3","tagid":"slot-3","ext":{"gpid":"old"}}]}`,
+			givenActions: `[{"type": "set", "path": "imp.*.ext.gpid", "from": "imp.*.tagid"}]`,
+			expectedDoc:  `{"imp":[{"id":"1","tagid":"slot-1","ext":{"gpid":"slot-1"}},{"id":"2"},{"id":"3","tagid":"slot-3","ext":{"gpid":"slot-3"}}]}`,
+		},
+		{
+			description:  "Set from a field outside of the array",
+			givenDoc:     `{"site":{"page":"https://example.com"},"imp":[{"id":"1"},{"id":"2"}]}`,
+			givenActions: `[{"type": "set", "path": "imp.*.ext.data.page", "from": "site.page"}]`,
+			expectedDoc:  `{"site":{"page":"https://example.com"},"imp":[{"id":"1","ext":{"data":{"page":"https://example.com"}}},{"id":"2","ext":{"data":{"page":"https://example.com"}}}]}`,
+		},
+		{
+			description:  "Remove field",
+			givenDoc:     `{"user":{"id":"u","eids":[{"source":"a.com"}]},"imp":[{"id":"1","ext":{"tid":"t"}},{"id":"2"}]}`,
+			givenActions: `[{"type": "remove", "path": "user.eids"}, {"type": "remove", "path": "imp.*.ext.tid"}]`,
+			expectedDoc:  `{"user":{"id":"u"},"imp":[{"id":"1","ext":{}},{"id":"2"}]}`,
+		},
+		{
+			description:  "Remove array elements",
+			givenDoc:     `{"user":{"eids":[{"source":"a.com","uids":[]},{"source":"b.com"},{"source":"c.com"}]}}`,
+			givenActions: `[{"type": "remove", "path": "user.eids", "where": {"source": ["a.com", "c.com"]}}]`,
+			expectedDoc:  `{"user":{"eids":[{"source":"b.com"}]}}`,
+		},
+		{
+			description:  "Remove all array elements",
+			givenDoc:     `{"user":{"id":"u","eids":[{"source":"a.com"}]}}`,
+			givenActions: `[{"type": "remove", "path": "user.eids", "where": {"source": ["a.com"]}}]`,
+			expectedDoc:  `{"user":{"id":"u"}}`,
+		},
+		{
+			description:  "Missing fields ignored",
+			givenDoc:     `{"id":"req"}`,
+			givenActions: `[{"type": "remove", "path": "user.eids", "where": {"source": ["a.com"]}}, {"type": "set", "path": "imp.*.ext.gpid", "from": "imp.*.tagid"}]`,
+			expectedDoc:  `{"id":"req"}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var actionConfigs []actionConfig
+			require.NoError(t, json.Unmarshal([]byte(test.givenActions), &actionConfigs))
+
+			var actions []action
+			for _, cfg := range actionConfigs {
+				a, err := newAction("processed_auction_request", cfg)
+				require.NoError(t, err)
+				actions = append(actions, a)
+			}
+
+			doc, err := applyFieldActions([]byte(test.givenDoc), actions)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expectedDoc, string(doc))
+		})
+	}
+}
+
+func TestFieldPathBind(t *testing.T) {
+	path, err := newFieldPath("imp.*.ext.prebid.bidder.a*b")
+	require.NoError(t, err)
+
+	assert.Equal(t, `imp.3.ext.prebid.bidder.a\*b`, path.bind([]int{3}))
+	assert.Equal(t, 1, path.wildcards())
+}
@@ -0,0 +1,49 @@
+package rulesengine
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+)
+
+func handleBidderRequestHook(
+	rules []rule,
+	dataCenter string,
+	payload hookstage.BidderRequestPayload,
+) (result hookstage.HookResult[hookstage.BidderRequestPayload], err error) {
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return result, hookexecution.NewFailure("payload contains a nil bid request")
+	}
+
+	attrs := newAttributes(payload.Request, payload.Bidder, dataCenter)
+	e := evaluate(rules, attrs, "bidder "+payload.Bidder, hookanalytics.AppliedTo{Bidder: payload.Bidder, Request: true})
+	result.AnalyticsTags = newRulesTags(e.results)
+	result.DebugMessages = e.trace
+
+	if e.reject != nil {
+		result.Reject = true
+		result.NbrCode = e.reject.nbr
+		return result, nil
+	}
+
+	if slices.Contains(e.excludedBidders, payload.Bidder) {
+		result.Reject = true
+		if attrs.debug {
+			result.DebugMessages = append(result.DebugMessages, fmt.Sprintf("Bidder %s excluded", payload.Bidder))
+		}
+		return result, nil
+	}
+
+	if len(e.updates) == 0 {
+		return result, nil
+	}
+
+	result.ChangeSet.AddMutation(func(payload hookstage.BidderRequestPayload) (hookstage.BidderRequestPayload, error) {
+		return payload, updateRequest(payload.Request, e.updates)
+	}, hookstage.MutationUpdate, "bidrequest")
+
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package rulesengine
+
+import (
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// attributesCtxKey holds the request attributes for the raw_bidder_response stage
+const attributesCtxKey = "attributes"
+
+func handleProcessedAuctionHook(
+	rules []rule,
+	dataCenter string,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (result hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], err error) {
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return result, hookexecution.NewFailure("payload contains a nil bid request")
+	}
+
+	attrs := newAttributes(payload.Request, "", dataCenter)
+	result.ModuleContext = hookstage.ModuleContext{attributesCtxKey: attrs}
+
+	e := evaluate(rules, attrs, "request", hookanalytics.AppliedTo{Request: true})
+	result.AnalyticsTags = newRulesTags(e.results)
+	result.DebugMessages = e.trace
+
+	if e.reject != nil {
+		result.Reject = true
+		result.NbrCode = e.reject.nbr
+		return result, nil
+	}
+
+	updates := e.updates
+	for _, bidder := range e.excludedBidders {
+		updates = append(updates, action{kind: actionRemove, path: fieldPath{"imp", wildcard, "ext", "prebid", "bidder", bidder}})
+	}
+	if len(updates) == 0 {
+		return result, nil
+	}
+
+	result.ChangeSet.AddMutation(func(payload hookstage.ProcessedAuctionRequestPayload) (hookstage.ProcessedAuctionRequestPayload, error) {
+		return payload, updateRequest(payload.Request, updates)
+	}, hookstage.MutationUpdate, "bidrequest")
+
+	return result, nil
+}
+
+// updateRequest applies the set and remove actions to the request in its JSON form.
+func updateRequest(request *openrtb_ext.RequestWrapper, updates []action) error {
+	if err := request.RebuildRequest(); err != nil {
+		return err
+	}
+
+	doc, err := jsonutil.Marshal(request.BidRequest)
+	if err != nil {
+		return err
+	}
+	if doc, err = applyFieldActions(doc, updates); err != nil {
+		return err
+	}
+
+	var updated openrtb2.BidRequest
+	if err := jsonutil.UnmarshalValid(doc, &updated); err != nil {
+		return fmt.Errorf("updated request is invalid: %s", err)
+	}
+
+	// a new wrapper drops the extensions parsed from the previous request
+	*request.BidRequest = updated
+	*request = openrtb_ext.RequestWrapper{BidRequest: request.BidRequest}
+	return nil
+}
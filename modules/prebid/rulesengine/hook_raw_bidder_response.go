@@ -0,0 +1,89 @@
+package rulesengine
+
+import (
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// handleRawBidderResponseHook matches the rules against every bid, the request attributes are taken
+// from the processed_auction_request stage and the media type is the type of the bid.
+// The matched bids are dropped by the reject action and updated by the set and remove actions.
+func handleRawBidderResponseHook(
+	rules []rule,
+	dataCenter string,
+	moduleCtx hookstage.ModuleContext,
+	payload hookstage.RawBidderResponsePayload,
+) (result hookstage.HookResult[hookstage.RawBidderResponsePayload], err error) {
+	if payload.BidderResponse == nil {
+		return result, nil
+	}
+
+	attrs, ok := moduleCtx[attributesCtxKey].(attributes)
+	if !ok {
+		attrs = attributes{dataCenter: dataCenter}
+	}
+	attrs.bidder = payload.Bidder
+
+	var results []hookanalytics.Result
+	bids := make([]*adapters.TypedBid, 0, len(payload.BidderResponse.Bids))
+	changed := false
+
+	for _, bid := range payload.BidderResponse.Bids {
+		if bid == nil || bid.Bid == nil {
+			bids = append(bids, bid)
+			continue
+		}
+
+		attrs.mediaTypes = []string{string(bid.BidType)}
+		e := evaluate(rules, attrs, "bid "+bid.Bid.ID, hookanalytics.AppliedTo{Bidder: payload.Bidder, BidIds: []string{bid.Bid.ID}})
+		results = append(results, e.results...)
+		result.DebugMessages = append(result.DebugMessages, e.trace...)
+
+		if e.reject != nil {
+			changed = true
+			continue
+		}
+
+		if len(e.updates) > 0 {
+			updated, err := updateBid(bid.Bid, e.updates)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("failed to update bid %s: %s", bid.Bid.ID, err))
+			} else {
+				typedBid := *bid
+				typedBid.Bid = updated
+				bid = &typedBid
+				changed = true
+			}
+		}
+		bids = append(bids, bid)
+	}
+
+	result.AnalyticsTags = newRulesTags(results)
+	if changed {
+		result.ChangeSet.RawBidderResponse().Bids().UpdateBids(bids)
+	}
+
+	return result, nil
+}
+
+// updateBid applies the set and remove actions to a copy of the bid in its JSON form.
+func updateBid(bid *openrtb2.Bid, updates []action) (*openrtb2.Bid, error) {
+	doc, err := jsonutil.Marshal(bid)
+	if err != nil {
+		return nil, err
+	}
+	if doc, err = applyFieldActions(doc, updates); err != nil {
+		return nil, err
+	}
+
+	updated := &openrtb2.Bid{}
+	if err := jsonutil.UnmarshalValid(doc, updated); err != nil {
+		return nil, fmt.Errorf("updated bid is invalid: %s", err)
+	}
+	return updated, nil
+}
@@ -0,0 +1,152 @@
+package rulesengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/hooks"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+)
+
+// maxCachedRuleSets bounds the number of account configs kept compiled,
+// the cache is cleared when the limit is reached.
+const maxCachedRuleSets = 1000
+
+func Builder(rawConfig json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	hostRules, err := newRuleSet(cfg.Rules, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules: %s", err)
+	}
+
+	return Module{
+		config:     cfg,
+		hostRules:  hostRules,
+		dataCenter: deps.DataCenter,
+		accounts:   &ruleSetCache{entries: map[string]cachedRuleSet{}},
+	}, nil
+}
+
+type Module struct {
+	config     config
+	hostRules  ruleSet
+	dataCenter string
+	accounts   *ruleSetCache
+}
+
+// HandleProcessedAuctionHook applies the rules of the processed_auction_request stage to the auction request.
+func (m Module) HandleProcessedAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}
+
+	rules, err := m.rules(miCtx.AccountConfig)
+	if err != nil || rules == nil {
+		return result, err
+	}
+
+	return handleProcessedAuctionHook(rules[hooks.StageProcessedAuctionRequest.String()], m.dataCenter, payload)
+}
+
+// HandleBidderRequestHook applies the rules of the bidder_request stage to the request of each bidder.
+func (m Module) HandleBidderRequestHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.BidderRequestPayload,
+) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.BidderRequestPayload]{}
+
+	rules, err := m.rules(miCtx.AccountConfig)
+	if err != nil || rules == nil {
+		return result, err
+	}
+
+	return handleBidderRequestHook(rules[hooks.StageBidderRequest.String()], m.dataCenter, payload)
+}
+
+// HandleRawBidderResponseHook applies the rules of the raw_bidder_response stage to the bids of each bidder.
+func (m Module) HandleRawBidderResponseHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.RawBidderResponsePayload,
+) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	result := hookstage.HookResult[hookstage.RawBidderResponsePayload]{}
+
+	rules, err := m.rules(miCtx.AccountConfig)
+	if err != nil || rules == nil {
+		return result, err
+	}
+
+	return handleRawBidderResponseHook(rules[hooks.StageRawBidderResponse.String()], m.dataCenter, miCtx.ModuleContext, payload)
+}
+
+// rules returns the host rules followed by the rules of the account config,
+// or nil if the module is disabled for the account. The account rules are validated
+// here, on the first request of the account, as modules aren't called when accounts are fetched.
+func (m Module) rules(accountConfig json.RawMessage) (ruleSet, error) {
+	if len(accountConfig) == 0 {
+		return m.hostRules, nil
+	}
+	return m.accounts.get(accountConfig, func() (ruleSet, error) {
+		account, err := newAccountConfig(accountConfig)
+		if err != nil {
+			return nil, err
+		}
+		if account.Enabled != nil && !*account.Enabled {
+			return nil, nil
+		}
+
+		rules, err := newRuleSet(m.config.Rules, account.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rules: %s", err)
+		}
+		return rules, nil
+	})
+}
+
+type cachedRuleSet struct {
+	rules ruleSet
+	err   error
+}
+
+// ruleSetCache keeps the rules compiled from the account configs, so each account config is validated
+// once when it is first seen and not on every request.
+type ruleSetCache struct {
+	mu      sync.RWMutex
+	entries map[string]cachedRuleSet
+}
+
+func (c *ruleSetCache) get(accountConfig json.RawMessage, compile func() (ruleSet, error)) (ruleSet, error) {
+	key := string(accountConfig)
+
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok {
+		return entry.rules, entry.err
+	}
+
+	entry.rules, entry.err = compile()
+	if entry.err != nil {
+		glog.Errorf("Rules engine account config rejected: %v", entry.err)
+	}
+
+	c.mu.Lock()
+	if len(c.entries) >= maxCachedRuleSets {
+		c.entries = map[string]cachedRuleSet{}
+	}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	return entry.rules, entry.err
+}
@@ -0,0 +1,254 @@
+package rulesengine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/adapters"
+	"github.com/prebid/prebid-server/v3/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v3/hooks/hookstage"
+	"github.com/prebid/prebid-server/v3/modules/moduledeps"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testHostConfig = `{"rules": [
+	{"name": "drop-bidder-de", "conditions": {"country": ["DEU"]}, "actions": [{"type": "exclude_bidders", "bidders": ["appnexus"]}]},
+	{"name": "gpid", "actions": [{"type": "set", "path": "imp.*.ext.gpid", "from": "imp.*.tagid"}]},
+	{"name": "cap-tmax", "conditions": {"channel": ["app"]}, "actions": [{"type": "set", "path": "tmax", "value": 500}]},
+	{"name": "reject-dooh", "conditions": {"channel": ["dooh"]}, "actions": [{"type": "reject", "nbr": 2}]}
+]}`
+
+func TestBuilder(t *testing.T) {
+	_, err := Builder(json.RawMessage(testHostConfig), moduledeps.ModuleDeps{})
+	assert.NoError(t, err)
+
+	_, err = Builder(json.RawMessage(`{"rules": [{"name": "r", "actions": [{"type": "drop"}]}]}`), moduledeps.ModuleDeps{})
+	assert.EqualError(t, err, `invalid rules: host rules[0]: rule r: actions[0]: unsupported action type "drop"`)
+
+	_, err = Builder(json.RawMessage(`{"rules": {}}`), moduledeps.ModuleDeps{})
+	assert.ErrorContains(t, err, "failed to parse config")
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	testCases := []struct {
+		description          string
+		givenAccountConfig   json.RawMessage
+		givenRequest         string
+		expectedRequest      string
+		expectedReject       bool
+		expectedNbr          int
+		expectedDebugMessage []string
+		expectedRules        []string
+		expectedError        string
+	}{
+		{
+			description:     "Bidder excluded and gpid set",
+			givenRequest:    `{"id":"r","site":{"domain":"example.com"},"device":{"geo":{"country":"DEU"}},"imp":[{"id":"1","tagid":"slot","banner":{},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":1},"rubicon":{"accountId":1}}}}}]}`,
+			expectedRequest: `{"id":"r","site":{"domain":"example.com"},"device":{"geo":{"country":"DEU"}},"imp":[{"id":"1","tagid":"slot","banner":{},"ext":{"gpid":"slot","prebid":{"bidder":{"rubicon":{"accountId":1}}}}}]}`,
+			expectedRules:   []string{"drop-bidder-de", "gpid"},
+		},
+		{
+			description:     "Tmax capped for app traffic",
+			givenRequest:    `{"id":"r","app":{"bundle":"com.example"},"tmax":1000,"imp":[{"id":"1","banner":{}}]}`,
+			expectedRequest: `{"id":"r","app":{"bundle":"com.example"},"tmax":500,"imp":[{"id":"1","banner":{}}]}`,
+			expectedRules:   []string{"gpid", "cap-tmax"},
+		},
+		{
+			description:     "Request rejected",
+			givenRequest:    `{"id":"r","dooh":{"id":"d"},"imp":[{"id":"1","banner":{}}]}`,
+			expectedRequest: `{"id":"r","dooh":{"id":"d"},"imp":[{"id":"1","banner":{}}]}`,
+			expectedReject:  true,
+			expectedNbr:     2,
+			expectedRules:   []string{"gpid", "reject-dooh"},
+		},
+		{
+			description:     "Trace in debug mode",
+			givenRequest:    `{"id":"r","test":1,"site":{"domain":"example.com"},"imp":[{"id":"1","banner":{}}]}`,
+			expectedRequest: `{"id":"r","test":1,"site":{"domain":"example.com"},"imp":[{"id":"1","banner":{}}]}`,
+			expectedDebugMessage: []string{
+				"Rule drop-bidder-de not matched for request: country condition",
+				"Rule gpid matched for request: set imp.*.ext.gpid",
+				"Rule cap-tmax not matched for request: channel condition",
+				"Rule reject-dooh not matched for request: channel condition",
+			},
+			expectedRules: []string{"gpid"},
+		},
+		{
+			description:        "Account rules appended to the host rules",
+			givenAccountConfig: json.RawMessage(`{"rules": [{"name": "no-eids", "actions": [{"type": "remove", "path": "user.eids", "where": {"source": ["a.com"]}}]}]}`),
+			givenRequest:       `{"id":"r","app":{"bundle":"b"},"user":{"eids":[{"source":"a.com"},{"source":"b.com"}]},"imp":[{"id":"1","banner":{}}]}`,
+			expectedRequest:    `{"id":"r","app":{"bundle":"b"},"tmax":500,"user":{"eids":[{"source":"b.com"}]},"imp":[{"id":"1","banner":{}}]}`,
+			expectedRules:      []string{"gpid", "cap-tmax", "no-eids"},
+		},
+		{
+			description:        "Module disabled for the account",
+			givenAccountConfig: json.RawMessage(`{"enabled": false}`),
+			givenRequest:       `{"id":"r","dooh":{"id":"d"},"imp":[{"id":"1","banner":{}}]}`,
+			expectedRequest:    `{"id":"r","dooh":{"id":"d"},"imp":[{"id":"1","banner":{}}]}`,
+		},
+		{
+			description:        "Invalid account rules",
+			givenAccountConfig: json.RawMessage(`{"rules": [{"name": "r", "actions": []}]}`),
+			givenRequest:       `{"id":"r","imp":[{"id":"1","banner":{}}]}`,
+			expectedRequest:    `{"id":"r","imp":[{"id":"1","banner":{}}]}`,
+			expectedError:      "invalid rules: account rules[0]: rule r: actions are required",
+		},
+	}
+
+	module, err := Builder(json.RawMessage(testHostConfig), moduledeps.ModuleDeps{DataCenter: "eu-west"})
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			request := &openrtb2.BidRequest{}
+			require.NoError(t, json.Unmarshal([]byte(test.givenRequest), request))
+			payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: request}}
+
+			result, err := module.(Module).HandleProcessedAuctionHook(
+				context.Background(),
+				hookstage.ModuleInvocationContext{AccountConfig: test.givenAccountConfig},
+				payload,
+			)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				require.NoError(t, err)
+			}
+
+			actual, err := json.Marshal(payload.Request.BidRequest)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expectedRequest, string(actual))
+			assert.Equal(t, test.expectedReject, result.Reject)
+			assert.Equal(t, test.expectedNbr, result.NbrCode)
+			assert.Equal(t, test.expectedDebugMessage, result.DebugMessages)
+			assert.Equal(t, test.expectedRules, matchedRules(result.AnalyticsTags))
+		})
+	}
+}
+
+func TestHandleBidderRequestHook(t *testing.T) {
+	config := `{"rules": [
+		{"name": "drop-bidder", "stage": "bidder_request", "conditions": {"datacenter": ["eu-west"]}, "actions": [{"type": "exclude_bidders", "bidders": ["rubicon"]}]},
+		{"name": "no-eids", "stage": "bidder_request", "conditions": {"bidder": ["appnexus"]}, "actions": [{"type": "remove", "path": "user.eids", "where": {"source": ["a.com"]}}]}
+	]}`
+
+	testCases := []struct {
+		description     string
+		givenBidder     string
+		givenRequest    string
+		expectedRequest string
+		expectedReject  bool
+	}{
+		{
+			description:     "Eids removed for the bidder",
+			givenBidder:     "appnexus",
+			givenRequest:    `{"id":"r","user":{"eids":[{"source":"a.com"},{"source":"b.com"}]},"imp":[{"id":"1","banner":{}}]}`,
+			expectedRequest: `{"id":"r","user":{"eids":[{"source":"b.com"}]},"imp":[{"id":"1","banner":{}}]}`,
+		},
+		{
+			description:     "Bidder excluded",
+			givenBidder:     "rubicon",
+			givenRequest:    `{"id":"r","user":{"eids":[{"source":"a.com"}]},"imp":[{"id":"1","banner":{}}]}`,
+			expectedRequest: `{"id":"r","user":{"eids":[{"source":"a.com"}]},"imp":[{"id":"1","banner":{}}]}`,
+			expectedReject:  true,
+		},
+		{
+			description:     "No rule matched",
+			givenBidder:     "pubmatic",
+			givenRequest:    `{"id":"r","user":{"eids":[{"source":"a.com"}]},"imp":[{"id":"1","banner":{}}]}`,
+			expectedRequest: `{"id":"r","user":{"eids":[{"source":"a.com"}]},"imp":[{"id":"1","banner":{}}]}`,
+		},
+	}
+
+	module, err := Builder(json.RawMessage(config), moduledeps.ModuleDeps{DataCenter: "eu-west"})
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			request := &openrtb2.BidRequest{}
+			require.NoError(t, json.Unmarshal([]byte(test.givenRequest), request))
+			payload := hookstage.BidderRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: request}, Bidder: test.givenBidder}
+
+			result, err := module.(Module).HandleBidderRequestHook(context.Background(), hookstage.ModuleInvocationContext{}, payload)
+			require.NoError(t, err)
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				require.NoError(t, err)
+			}
+
+			actual, err := json.Marshal(payload.Request.BidRequest)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expectedRequest, string(actual))
+			assert.Equal(t, test.expectedReject, result.Reject)
+		})
+	}
+}
+
+func TestHandleRawBidderResponseHook(t *testing.T) {
+	config := `{"rules": [
+		{"name": "no-video", "stage": "raw_bidder_response", "conditions": {"country": ["FRA"], "media_type": ["video"]}, "actions": [{"type": "reject"}]},
+		{"name": "deal", "stage": "raw_bidder_response", "conditions": {"bidder": ["appnexus"]}, "actions": [{"type": "set", "path": "dealid", "value": "d1"}, {"type": "analytics_tag", "values": {"deal": true}}]}
+	]}`
+
+	module, err := Builder(json.RawMessage(config), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+
+	payload := hookstage.RawBidderResponsePayload{
+		Bidder: "appnexus",
+		BidderResponse: &adapters.BidderResponse{
+			Bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "b1", ImpID: "1", Price: 1}, BidType: openrtb_ext.BidTypeBanner},
+				{Bid: &openrtb2.Bid{ID: "b2", ImpID: "2", Price: 2}, BidType: openrtb_ext.BidTypeVideo},
+			},
+		},
+	}
+	moduleCtx := hookstage.ModuleContext{attributesCtxKey: attributes{country: "FRA"}}
+
+	result, err := module.(Module).HandleRawBidderResponseHook(context.Background(), hookstage.ModuleInvocationContext{ModuleContext: moduleCtx}, payload)
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+
+	for _, mut := range result.ChangeSet.Mutations() {
+		payload, err = mut.Apply(payload)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, payload.BidderResponse.Bids, 1)
+	assert.Equal(t, &openrtb2.Bid{ID: "b1", ImpID: "1", Price: 1, DealID: "d1"}, payload.BidderResponse.Bids[0].Bid)
+	assert.Equal(t, openrtb_ext.BidTypeBanner, payload.BidderResponse.Bids[0].BidType)
+
+	expectedResults := []hookanalytics.Result{
+		{
+			Status:    hookanalytics.ResultStatusModify,
+			Values:    map[string]interface{}{"rule": "deal", "actions": []string{"set dealid", "analytics_tag"}, "deal": true},
+			AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"b1"}},
+		},
+		{
+			Status:    hookanalytics.ResultStatusBlock,
+			Values:    map[string]interface{}{"rule": "no-video", "actions": []string{"reject with nbr 0"}},
+			AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"b2"}},
+		},
+	}
+	require.Len(t, result.AnalyticsTags.Activities, 1)
+	assert.Equal(t, expectedResults, result.AnalyticsTags.Activities[0].Results)
+}
+
+func matchedRules(tags hookanalytics.Analytics) []string {
+	var rules []string
+	for _, activity := range tags.Activities {
+		for _, result := range activity.Results {
+			rules = append(rules, result.Values[ruleAnalyticKey].(string))
+		}
+	}
+	return rules
+}
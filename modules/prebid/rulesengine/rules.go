@@ -0,0 +1,202 @@
+package rulesengine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prebid/prebid-server/v3/hooks"
+)
+
+const (
+	actionSet            = "set"
+	actionRemove         = "remove"
+	actionExcludeBidders = "exclude_bidders"
+	actionReject         = "reject"
+	actionAnalyticsTag   = "analytics_tag"
+)
+
+var supportedStages = []string{
+	hooks.StageProcessedAuctionRequest.String(),
+	hooks.StageBidderRequest.String(),
+	hooks.StageRawBidderResponse.String(),
+}
+
+var supportedChannels = []string{channelWeb, channelApp, channelDOOH}
+
+// supportedMediaTypes are listed in the order of the imp fields.
+var supportedMediaTypes = []string{"banner", "video", "audio", "native"}
+
+type rule struct {
+	name       string
+	conditions conditionsConfig
+	actions    []action
+}
+
+type action struct {
+	kind    string
+	path    fieldPath
+	from    fieldPath
+	value   []byte
+	where   map[string][]string
+	bidders []string
+	nbr     int
+	values  map[string]interface{}
+}
+
+// ruleSet holds the rules of each stage in the order they were configured.
+type ruleSet map[string][]rule
+
+// newRuleSet validates the host rules followed by the account rules and groups them by stage.
+func newRuleSet(hostRules, accountRules []ruleConfig) (ruleSet, error) {
+	rules := ruleSet{}
+	if err := rules.add("host", hostRules); err != nil {
+		return nil, err
+	}
+	if err := rules.add("account", accountRules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (rules ruleSet) add(source string, configs []ruleConfig) error {
+	for i, ruleCfg := range configs {
+		stage, r, err := newRule(ruleCfg)
+		if err != nil {
+			return fmt.Errorf("%s rules[%d]: %s", source, i, err)
+		}
+		rules[stage] = append(rules[stage], r)
+	}
+	return nil
+}
+
+func newRule(cfg ruleConfig) (string, rule, error) {
+	r := rule{name: cfg.Name, conditions: cfg.Conditions}
+	if cfg.Name == "" {
+		return "", r, errors.New("name is required")
+	}
+
+	stage := cfg.Stage
+	if stage == "" {
+		stage = hooks.StageProcessedAuctionRequest.String()
+	}
+	if !slices.Contains(supportedStages, stage) {
+		return "", r, fmt.Errorf("rule %s: unsupported stage %q", cfg.Name, stage)
+	}
+
+	if err := validateConditions(stage, cfg.Conditions); err != nil {
+		return "", r, fmt.Errorf("rule %s: %s", cfg.Name, err)
+	}
+
+	if len(cfg.Actions) == 0 {
+		return "", r, fmt.Errorf("rule %s: actions are required", cfg.Name)
+	}
+	for i, actionCfg := range cfg.Actions {
+		a, err := newAction(stage, actionCfg)
+		if err != nil {
+			return "", r, fmt.Errorf("rule %s: actions[%d]: %s", cfg.Name, i, err)
+		}
+		r.actions = append(r.actions, a)
+	}
+
+	return stage, r, nil
+}
+
+func validateConditions(stage string, conditions conditionsConfig) error {
+	for _, channel := range conditions.Channel {
+		if !slices.Contains(supportedChannels, channel) {
+			return fmt.Errorf("unsupported channel %q", channel)
+		}
+	}
+	for _, mediaType := range conditions.MediaType {
+		if !slices.Contains(supportedMediaTypes, mediaType) {
+			return fmt.Errorf("unsupported media type %q", mediaType)
+		}
+	}
+	if len(conditions.Bidder) > 0 && stage == hooks.StageProcessedAuctionRequest.String() {
+		return fmt.Errorf("bidder condition is not supported at the %s stage", stage)
+	}
+	return nil
+}
+
+func newAction(stage string, cfg actionConfig) (action, error) {
+	a := action{kind: cfg.Type}
+
+	switch cfg.Type {
+	case actionSet:
+		path, err := newFieldPath(cfg.Path)
+		if err != nil {
+			return a, err
+		}
+		a.path = path
+
+		if (len(cfg.Value) == 0) == (cfg.From == "") {
+			return a, errors.New("either value or from is required")
+		}
+		a.value = cfg.Value
+		if cfg.From != "" {
+			if a.from, err = newFieldPath(cfg.From); err != nil {
+				return a, err
+			}
+			if a.from.wildcards() > a.path.wildcards() {
+				return a, fmt.Errorf("from %q has more wildcards than path %q", cfg.From, cfg.Path)
+			}
+		}
+
+	case actionRemove:
+		path, err := newFieldPath(cfg.Path)
+		if err != nil {
+			return a, err
+		}
+		if path[len(path)-1] == wildcard {
+			return a, fmt.Errorf("path %q must not end with a wildcard, use where to remove array elements", cfg.Path)
+		}
+		for field, values := range cfg.Where {
+			if len(values) == 0 {
+				return a, fmt.Errorf("where %s has no values", field)
+			}
+		}
+		a.path = path
+		a.where = cfg.Where
+
+	case actionExcludeBidders:
+		if stage == hooks.StageRawBidderResponse.String() {
+			return a, fmt.Errorf("%s is not supported at the %s stage", cfg.Type, stage)
+		}
+		if len(cfg.Bidders) == 0 {
+			return a, errors.New("bidders are required")
+		}
+		a.bidders = cfg.Bidders
+
+	case actionReject:
+		if cfg.NBR < 0 {
+			return a, fmt.Errorf("invalid nbr %d", cfg.NBR)
+		}
+		a.nbr = cfg.NBR
+
+	case actionAnalyticsTag:
+		if len(cfg.Values) == 0 {
+			return a, errors.New("values are required")
+		}
+		a.values = cfg.Values
+
+	default:
+		return a, fmt.Errorf("unsupported action type %q", cfg.Type)
+	}
+
+	return a, nil
+}
+
+// String describes the action in the trace.
+func (a action) String() string {
+	switch a.kind {
+	case actionSet, actionRemove:
+		return a.kind + " " + strings.Join(a.path, ".")
+	case actionExcludeBidders:
+		return a.kind + " " + strings.Join(a.bidders, ",")
+	case actionReject:
+		return fmt.Sprintf("%s with nbr %d", a.kind, a.nbr)
+	}
+	return a.kind
+}
@@ -0,0 +1,200 @@
+package rulesengine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRuleSet(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenRules    string
+		expectedError string
+	}{
+		{
+			description: "Valid rules",
+			givenRules: `[
+				{"name": "cap-tmax", "conditions": {"channel": ["app"]}, "actions": [{"type": "set", "path": "tmax", "value": 500}]},
+				{"name": "gpid", "actions": [{"type": "set", "path": "imp.*.ext.gpid", "from": "imp.*.tagid"}]},
+				{"name": "eids", "stage": "bidder_request", "conditions": {"bidder": ["appnexus"]}, "actions": [{"type": "remove", "path": "user.eids", "where": {"source": ["example.com"]}}]},
+				{"name": "drop", "stage": "raw_bidder_response", "conditions": {"media_type": ["video"]}, "actions": [{"type": "reject"}, {"type": "analytics_tag", "values": {"reason": "video"}}]}
+			]`,
+		},
+		{
+			description:   "Missing name",
+			givenRules:    `[{"actions": [{"type": "reject"}]}]`,
+			expectedError: "host rules[0]: name is required",
+		},
+		{
+			description:   "Unsupported stage",
+			givenRules:    `[{"name": "r", "stage": "auction_response", "actions": [{"type": "reject"}]}]`,
+			expectedError: `host rules[0]: rule r: unsupported stage "auction_response"`,
+		},
+		{
+			description:   "Unsupported channel",
+			givenRules:    `[{"name": "r", "conditions": {"channel": ["ctv"]}, "actions": [{"type": "reject"}]}]`,
+			expectedError: `host rules[0]: rule r: unsupported channel "ctv"`,
+		},
+		{
+			description:   "Unsupported media type",
+			givenRules:    `[{"name": "r", "conditions": {"media_type": ["display"]}, "actions": [{"type": "reject"}]}]`,
+			expectedError: `host rules[0]: rule r: unsupported media type "display"`,
+		},
+		{
+			description:   "Bidder condition before the bidder requests",
+			givenRules:    `[{"name": "r", "conditions": {"bidder": ["appnexus"]}, "actions": [{"type": "reject"}]}]`,
+			expectedError: "host rules[0]: rule r: bidder condition is not supported at the processed_auction_request stage",
+		},
+		{
+			description:   "No actions",
+			givenRules:    `[{"name": "r"}]`,
+			expectedError: "host rules[0]: rule r: actions are required",
+		},
+		{
+			description:   "Unsupported action",
+			givenRules:    `[{"name": "r", "actions": [{"type": "reject"}, {"type": "drop"}]}]`,
+			expectedError: `host rules[0]: rule r: actions[1]: unsupported action type "drop"`,
+		},
+		{
+			description:   "Set without value",
+			givenRules:    `[{"name": "r", "actions": [{"type": "set", "path": "tmax"}]}]`,
+			expectedError: "host rules[0]: rule r: actions[0]: either value or from is required",
+		},
+		{
+			description:   "Set with value and from",
+			givenRules:    `[{"name": "r", "actions": [{"type": "set", "path": "tmax", "value": 1, "from": "at"}]}]`,
+			expectedError: "host rules[0]: rule r: actions[0]: either value or from is required",
+		},
+		{
+			description:   "Set from unbound wildcard",
+			givenRules:    `[{"name": "r", "actions": [{"type": "set", "path": "ext.gpid", "from": "imp.*.tagid"}]}]`,
+			expectedError: `host rules[0]: rule r: actions[0]: from "imp.*.tagid" has more wildcards than path "ext.gpid"`,
+		},
+		{
+			description:   "Invalid path",
+			givenRules:    `[{"name": "r", "actions": [{"type": "remove", "path": "user..eids"}]}]`,
+			expectedError: `host rules[0]: rule r: actions[0]: invalid path "user..eids"`,
+		},
+		{
+			description:   "Remove path ending with a wildcard",
+			givenRules:    `[{"name": "r", "actions": [{"type": "remove", "path": "user.eids.*"}]}]`,
+			expectedError: `host rules[0]: rule r: actions[0]: path "user.eids.*" must not end with a wildcard, use where to remove array elements`,
+		},
+		{
+			description:   "Remove where without values",
+			givenRules:    `[{"name": "r", "actions": [{"type": "remove", "path": "user.eids", "where": {"source": []}}]}]`,
+			expectedError: "host rules[0]: rule r: actions[0]: where source has no values",
+		},
+		{
+			description:   "Exclude bidders after the bidder requests",
+			givenRules:    `[{"name": "r", "stage": "raw_bidder_response", "actions": [{"type": "exclude_bidders", "bidders": ["appnexus"]}]}]`,
+			expectedError: "host rules[0]: rule r: actions[0]: exclude_bidders is not supported at the raw_bidder_response stage",
+		},
+		{
+			description:   "Exclude bidders without bidders",
+			givenRules:    `[{"name": "r", "actions": [{"type": "exclude_bidders"}]}]`,
+			expectedError: "host rules[0]: rule r: actions[0]: bidders are required",
+		},
+		{
+			description:   "Reject with negative nbr",
+			givenRules:    `[{"name": "r", "actions": [{"type": "reject", "nbr": -1}]}]`,
+			expectedError: "host rules[0]: rule r: actions[0]: invalid nbr -1",
+		},
+		{
+			description:   "Analytics tag without values",
+			givenRules:    `[{"name": "r", "actions": [{"type": "analytics_tag"}]}]`,
+			expectedError: "host rules[0]: rule r: actions[0]: values are required",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var rules []ruleConfig
+			require.NoError(t, json.Unmarshal([]byte(test.givenRules), &rules))
+
+			_, err := newRuleSet(rules, nil)
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestNewRuleSetAccountRules(t *testing.T) {
+	hostRules := []ruleConfig{{Name: "host", Actions: []actionConfig{{Type: "reject"}}}}
+	accountRules := []ruleConfig{{Name: "valid", Actions: []actionConfig{{Type: "reject"}}}, {Name: "invalid"}}
+
+	_, err := newRuleSet(hostRules, accountRules)
+	assert.EqualError(t, err, "account rules[1]: rule invalid: actions are required")
+
+	rules, err := newRuleSet(hostRules, accountRules[:1])
+	require.NoError(t, err)
+	assert.Len(t, rules["processed_auction_request"], 2)
+}
+
+func TestConditionsMatch(t *testing.T) {
+	attrs := attributes{
+		channel:    channelWeb,
+		domain:     "www.example.com",
+		country:    "DEU",
+		deviceType: 2,
+		gppSID:     []int8{2, 7},
+		bidder:     "appnexus",
+		mediaTypes: []string{"banner", "video"},
+		dataCenter: "eu-west",
+	}
+
+	testCases := []struct {
+		description       string
+		givenConditions   conditionsConfig
+		expectedMatch     bool
+		expectedCondition string
+	}{
+		{
+			description:   "No conditions",
+			expectedMatch: true,
+		},
+		{
+			description: "All conditions matched",
+			givenConditions: conditionsConfig{
+				Channel:    []string{channelApp, channelWeb},
+				Domain:     []string{"WWW.EXAMPLE.COM"},
+				Country:    []string{"deu", "fra"},
+				DeviceType: []int{2},
+				GPPSID:     []int8{7},
+				Bidder:     []string{"AppNexus"},
+				MediaType:  []string{"video"},
+				DataCenter: []string{"eu-west"},
+			},
+			expectedMatch: true,
+		},
+		{
+			description:       "Bundle not matched for web traffic",
+			givenConditions:   conditionsConfig{Bundle: []string{"com.example"}},
+			expectedCondition: "bundle",
+		},
+		{
+			description:       "GPP section not matched",
+			givenConditions:   conditionsConfig{Country: []string{"DEU"}, GPPSID: []int8{8}},
+			expectedCondition: "gpp_sid",
+		},
+		{
+			description:       "Data center not matched",
+			givenConditions:   conditionsConfig{DataCenter: []string{"us-east"}},
+			expectedCondition: "datacenter",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			matched, condition := test.givenConditions.match(attrs)
+			assert.Equal(t, test.expectedMatch, matched)
+			assert.Equal(t, test.expectedCondition, condition)
+		})
+	}
+}
@@ -11,6 +11,8 @@ type AdPod struct {
 	PodId     int64            `json:"podid"`
 	Targeting []VideoTargeting `json:"targeting"`
 	Errors    []string         `json:"errors"`
+	// UnfilledSlots is the number of slots of the pod which no bid could fill
+	UnfilledSlots int `json:"unfilledslots,omitempty"`
 }
 
 type VideoTargeting struct {
@@ -99,7 +99,9 @@ type PriceFloorModelGroup struct {
 	SkipRate     int                `json:"skiprate,omitempty"`
 	Schema       PriceFloorSchema   `json:"schema,omitempty"`
 	Values       map[string]float64 `json:"values,omitempty"`
-	Default      float64            `json:"default,omitempty"`
+	// Currencies overrides the model group currency for individual rules, keyed by rule
+	Currencies map[string]string `json:"currencies,omitempty"`
+	Default    float64           `json:"default,omitempty"`
 }
 
 func (mg PriceFloorModelGroup) Copy() PriceFloorModelGroup {
@@ -114,18 +116,23 @@ func (mg PriceFloorModelGroup) Copy() PriceFloorModelGroup {
 	}
 
 	newMg.Schema.Delimiter = mg.Schema.Delimiter
+	newMg.Schema.Timezone = mg.Schema.Timezone
 	newMg.Schema.Fields = make([]string, len(mg.Schema.Fields))
 	copy(newMg.Schema.Fields, mg.Schema.Fields)
 	newMg.Values = make(map[string]float64, len(mg.Values))
 	for key, val := range mg.Values {
 		newMg.Values[key] = val
 	}
+	newMg.Currencies = maps.Clone(mg.Currencies)
 	return *newMg
 }
 
 type PriceFloorSchema struct {
 	Fields    []string `json:"fields,omitempty"`
 	Delimiter string   `json:"delimiter,omitempty"`
+	// Timezone is the IANA time zone of the publisher, which the hourOfDay and dayOfWeek fields are evaluated in.
+	// It defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
 }
 
 type PriceFloorEnforcement struct {
@@ -184,10 +191,12 @@ func (data *PriceFloorData) DeepCopy() *PriceFloorData {
 		eachGroup.ModelVersion = data.ModelGroups[i].ModelVersion
 		eachGroup.SkipRate = data.ModelGroups[i].SkipRate
 		eachGroup.Values = maps.Clone(data.ModelGroups[i].Values)
+		eachGroup.Currencies = maps.Clone(data.ModelGroups[i].Currencies)
 		eachGroup.Default = data.ModelGroups[i].Default
 		eachGroup.Schema = PriceFloorSchema{
 			Fields:    slices.Clone(data.ModelGroups[i].Schema.Fields),
 			Delimiter: data.ModelGroups[i].Schema.Delimiter,
+			Timezone:  data.ModelGroups[i].Schema.Timezone,
 		}
 		newModelGroups[i] = eachGroup
 	}
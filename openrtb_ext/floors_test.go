@@ -338,10 +338,14 @@ func TestFloorRulesDeepCopy(t *testing.T) {
 							Schema: PriceFloorSchema{
 								Fields:    []string{"a", "b", "c"},
 								Delimiter: "|",
+								Timezone:  "America/New_York",
 							},
 							Values: map[string]float64{
 								"*|*|*": 20,
 							},
+							Currencies: map[string]string{
+								"*|*|*": "USD",
+							},
 							Default: 1,
 						},
 					},
@@ -381,10 +385,14 @@ func TestFloorRulesDeepCopy(t *testing.T) {
 							Schema: PriceFloorSchema{
 								Fields:    []string{"a", "b", "c"},
 								Delimiter: "|",
+								Timezone:  "America/New_York",
 							},
 							Values: map[string]float64{
 								"*|*|*": 20,
 							},
+							Currencies: map[string]string{
+								"*|*|*": "USD",
+							},
 							Default: 1,
 						},
 					},
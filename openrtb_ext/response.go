@@ -31,6 +31,28 @@ type ExtResponseDebug struct {
 	HttpCalls map[BidderName][]*ExtHttpCall `json:"httpcalls,omitempty"`
 	// Request after resolution of stored requests and debug overrides
 	ResolvedRequest json.RawMessage `json:"resolvedrequest,omitempty"`
+	// TrafficShaping defines the contract for bidresponse.ext.debug.trafficshaping
+	TrafficShaping map[BidderName][]ExtTrafficShapingImp `json:"trafficshaping,omitempty"`
+	// USNat defines the contract for bidresponse.ext.debug.usnat
+	USNat []ExtUSNatDecision `json:"usnat,omitempty"`
+}
+
+// ExtUSNatDecision defines the contract for bidresponse.ext.debug.usnat[i]
+// It explains an activity decided by a GPP US National or state section.
+type ExtUSNatDecision struct {
+	Activity  string   `json:"activity"`
+	Component string   `json:"component"`
+	SectionID int      `json:"sectionid"`
+	Allowed   bool     `json:"allowed"`
+	Reasons   []string `json:"reasons,omitempty"`
+}
+
+// ExtTrafficShapingImp defines the contract for bidresponse.ext.debug.trafficshaping.{bidder}[i]
+// It lists an imp which was not sent to the bidder, and the historical bid rate that decision was based on.
+type ExtTrafficShapingImp struct {
+	ImpID    string  `json:"impid"`
+	BidRate  float64 `json:"bidrate"`
+	Requests int     `json:"requests"`
 }
 
 // ExtResponseSyncData defines the contract for bidresponse.ext.usersync.{bidder}
@@ -49,6 +71,19 @@ type ExtResponsePrebid struct {
 	Targeting        map[string]string `json:"targeting,omitempty"`
 	// SeatNonBid holds the array of Bids which are either rejected, no bids inside bidresponse.ext.prebid.seatnonbid
 	SeatNonBid []SeatNonBid `json:"seatnonbid,omitempty"`
+	// AdPods reports how each ad pod of the request was filled
+	AdPods []ExtAdPod `json:"adpods,omitempty"`
+}
+
+// ExtAdPod defines the contract for bidresponse.ext.prebid.adpods[i]
+type ExtAdPod struct {
+	PodID          string   `json:"podid"`
+	Slots          int      `json:"slots,omitempty"`
+	FilledSlots    int      `json:"filledslots"`
+	UnfilledSlots  int      `json:"unfilledslots,omitempty"`
+	Duration       int      `json:"duration,omitempty"`
+	FilledDuration int      `json:"filledduration"`
+	UnfilledImpIDs []string `json:"unfilledimps,omitempty"`
 }
 
 // FledgeResponse defines the contract for bidresponse.ext.fledge
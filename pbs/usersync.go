@@ -23,6 +23,9 @@ type UserSyncDeps struct {
 	RecaptchaSecret  string
 	HostCookieConfig *config.HostCookie
 	PriorityGroups   [][]string
+	UIDStoreConfig   *config.UIDStore
+	// UIDStore is nil unless the UID store is enabled
+	UIDStore usersync.UIDStore
 }
 
 // Struct for parsing json in google's response
@@ -59,8 +62,8 @@ func (deps *UserSyncDeps) VerifyRecaptcha(response string) error {
 func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	optout := r.FormValue("optout")
 	rr := r.FormValue("g-recaptcha-response")
-	encoder := usersync.Base64Encoder{}
-	decoder := usersync.Base64Decoder{}
+	encoder := usersync.NewEncoder(deps.HostCookieConfig)
+	decoder := usersync.NewDecoder(deps.HostCookieConfig)
 
 	if rr == "" {
 		http.Redirect(w, r, fmt.Sprintf("%s/static/optout.html", deps.ExternalUrl), http.StatusMovedPermanently)
@@ -80,6 +83,7 @@ func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httpr
 	pc := usersync.ReadCookie(r, decoder, deps.HostCookieConfig)
 	usersync.SyncHostCookie(r, pc, deps.HostCookieConfig)
 	pc.SetOptOut(optout != "")
+	deps.updateStoredOptOut(w, r, pc)
 
 	// Write Cookie
 	encodedCookie, err := encoder.Encode(pc)
@@ -95,3 +99,22 @@ func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httpr
 		http.Redirect(w, r, deps.HostCookieConfig.OptOutURL, http.StatusMovedPermanently)
 	}
 }
+
+// updateStoredOptOut records an opt out in the UID store, replacing the stored UIDs, so that it's honored even
+// if the browser drops the uids cookie. An opt in clears it.
+func (deps *UserSyncDeps) updateStoredOptOut(w http.ResponseWriter, r *http.Request, pc *usersync.Cookie) {
+	if deps.UIDStore == nil {
+		return
+	}
+	var err error
+	if pc.AllowSyncs() {
+		if idCookie, cookieErr := r.Cookie(deps.UIDStoreConfig.IDCookieName); cookieErr == nil {
+			err = deps.UIDStore.Delete(r.Context(), idCookie.Value)
+		}
+	} else {
+		err = usersync.WriteStoredUIDs(r.Context(), w, r, pc, deps.UIDStore, deps.UIDStoreConfig, deps.HostCookieConfig, false)
+	}
+	if err != nil {
+		glog.Warningf("Opt Out failed to update the UID store: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package privacy
 
 import (
+	gpplib "github.com/prebid/go-gpp"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
 )
@@ -26,6 +27,15 @@ func NewRequestFromBidRequest(r openrtb_ext.RequestWrapper) ActivityRequest {
 type ActivityRequest struct {
 	policies   *Policies
 	bidRequest *openrtb_ext.RequestWrapper
+	// gpp is the GPP string of the bid request, when it was already parsed.
+	gpp *gpplib.GppContainer
+}
+
+// WithGPP returns the request along with its GPP string already parsed, so the rules don't parse it again for
+// every bidder and activity. A malformed GPP string should be given as an empty container.
+func (r ActivityRequest) WithGPP(gpp gpplib.GppContainer) ActivityRequest {
+	r.gpp = &gpp
+	return r
 }
 
 func (r ActivityRequest) IsPolicies() bool {
@@ -40,15 +50,30 @@ type ActivityControl struct {
 	plans      map[Activity]ActivityPlan
 	IPv6Config config.IPv6
 	IPv4Config config.IPv4
+	usnatTrace *usnatTrace
 }
 
 func NewActivityControl(cfg *config.AccountPrivacy) ActivityControl {
 	ac := ActivityControl{}
 
-	if cfg == nil || cfg.AllowActivities == nil {
+	if cfg == nil {
 		return ac
 	}
 
+	if cfg.AllowActivities != nil {
+		ac = newActivityControlFromRules(cfg)
+	}
+
+	if cfg.USNat.Enabled {
+		ac.addUSNatRules(cfg.USNat)
+	}
+
+	return ac
+}
+
+func newActivityControlFromRules(cfg *config.AccountPrivacy) ActivityControl {
+	ac := ActivityControl{}
+
 	plans := make(map[Activity]ActivityPlan, 8)
 	plans[ActivitySyncUser] = buildPlan(cfg.AllowActivities.SyncUser)
 	plans[ActivityFetchBids] = buildPlan(cfg.AllowActivities.FetchBids)
@@ -66,6 +91,29 @@ func NewActivityControl(cfg *config.AccountPrivacy) ActivityControl {
 	return ac
 }
 
+// addUSNatRules appends the GPP US National and state sections rule to the activities it decides, after the
+// rules of the account so those take precedence.
+func (e *ActivityControl) addUSNatRules(cfg config.AccountUSNat) {
+	if e.plans == nil {
+		e.plans = make(map[Activity]ActivityPlan, len(usnatActivities))
+	}
+	e.usnatTrace = &usnatTrace{}
+
+	for _, activity := range usnatActivities {
+		plan, ok := e.plans[activity]
+		if !ok {
+			plan = ActivityPlan{defaultResult: defaultActivityResult}
+		}
+		plan.rules = append(plan.rules, newUSNatRule(activity, cfg, e.usnatTrace))
+		e.plans[activity] = plan
+	}
+}
+
+// USNatDecisions returns the activities decided by the GPP US National and state sections so far.
+func (e ActivityControl) USNatDecisions() []openrtb_ext.ExtUSNatDecision {
+	return e.usnatTrace.get()
+}
+
 func buildPlan(activity config.Activity) ActivityPlan {
 	return ActivityPlan{
 		rules:         cfgToRules(activity.Rules),
@@ -100,13 +148,25 @@ func cfgToDefaultResult(activityDefault *bool) bool {
 }
 
 func (e ActivityControl) Allow(activity Activity, target Component, request ActivityRequest) bool {
+	return e.Decide(activity, target, request).Allowed
+}
+
+// ActivityDecision is the outcome of an activity along with the rule which decided it.
+type ActivityDecision struct {
+	Allowed bool
+	// Rule is the index of the first account rule which matched, or -1 if the default result was used.
+	Rule int
+}
+
+// Decide evaluates the activity like Allow, and reports which rule of the account decided it.
+func (e ActivityControl) Decide(activity Activity, target Component, request ActivityRequest) ActivityDecision {
 	plan, planDefined := e.plans[activity]
 
 	if !planDefined {
-		return defaultActivityResult
+		return ActivityDecision{Allowed: defaultActivityResult, Rule: -1}
 	}
 
-	return plan.Evaluate(target, request)
+	return plan.decide(target, request)
 }
 
 type ActivityPlan struct {
@@ -115,11 +175,15 @@ type ActivityPlan struct {
 }
 
 func (p ActivityPlan) Evaluate(target Component, request ActivityRequest) bool {
-	for _, rule := range p.rules {
+	return p.decide(target, request).Allowed
+}
+
+func (p ActivityPlan) decide(target Component, request ActivityRequest) ActivityDecision {
+	for i, rule := range p.rules {
 		result := rule.Evaluate(target, request)
 		if result == ActivityDeny || result == ActivityAllow {
-			return result == ActivityAllow
+			return ActivityDecision{Allowed: result == ActivityAllow, Rule: i}
 		}
 	}
-	return p.defaultResult
+	return ActivityDecision{Allowed: p.defaultResult, Rule: -1}
 }
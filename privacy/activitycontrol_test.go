@@ -142,6 +142,55 @@ func TestActivityControlAllow(t *testing.T) {
 	}
 }
 
+func TestActivityControlDecide(t *testing.T) {
+	plan := ActivityPlan{
+		defaultResult: false,
+		rules: []Rule{
+			ConditionRule{result: ActivityAllow, componentName: []string{"bidderA"}},
+			ConditionRule{result: ActivityDeny, componentType: []string{"bidder"}},
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		activityControl  ActivityControl
+		target           Component
+		expectedDecision ActivityDecision
+	}{
+		{
+			name:             "activity_not_defined",
+			activityControl:  ActivityControl{},
+			target:           Component{Type: "bidder", Name: "bidderA"},
+			expectedDecision: ActivityDecision{Allowed: true, Rule: -1},
+		},
+		{
+			name:             "first_rule_matched",
+			activityControl:  ActivityControl{plans: map[Activity]ActivityPlan{ActivityFetchBids: plan}},
+			target:           Component{Type: "bidder", Name: "bidderA"},
+			expectedDecision: ActivityDecision{Allowed: true, Rule: 0},
+		},
+		{
+			name:             "second_rule_matched",
+			activityControl:  ActivityControl{plans: map[Activity]ActivityPlan{ActivityFetchBids: plan}},
+			target:           Component{Type: "bidder", Name: "bidderB"},
+			expectedDecision: ActivityDecision{Allowed: false, Rule: 1},
+		},
+		{
+			name:             "default_result",
+			activityControl:  ActivityControl{plans: map[Activity]ActivityPlan{ActivityFetchBids: plan}},
+			target:           Component{Type: "analytics", Name: "analyticsA"},
+			expectedDecision: ActivityDecision{Allowed: false, Rule: -1},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			decision := test.activityControl.Decide(ActivityFetchBids, test.target, ActivityRequest{})
+			assert.Equal(t, test.expectedDecision, decision)
+		})
+	}
+}
+
 func TestActivityRequest(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		r := ActivityRequest{}
@@ -0,0 +1,113 @@
+package gpp
+
+import (
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspct"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/prebid/go-gpp/sections/uspva"
+)
+
+// The values of the US opt-out and consent fields which restrict processing. Zero means the field doesn't apply.
+const (
+	usOptedOut  byte = 1
+	usNoConsent byte = 1
+)
+
+// USSectionIDs are the GPP US National and state sections, in the order they are enforced.
+var USSectionIDs = []gppConstants.SectionID{
+	gppConstants.SectionUSPNAT,
+	gppConstants.SectionUSPCA,
+	gppConstants.SectionUSPVA,
+	gppConstants.SectionUSPCO,
+	gppConstants.SectionUSPUT,
+	gppConstants.SectionUSPCT,
+}
+
+// usPreciseGeoIndex is the position of precise geolocation within the sensitive data processing field of each
+// section. Colorado doesn't list precise geolocation as sensitive data.
+var usPreciseGeoIndex = map[gppConstants.SectionID]int{
+	gppConstants.SectionUSPNAT: 7,
+	gppConstants.SectionUSPCA:  2,
+	gppConstants.SectionUSPVA:  7,
+	gppConstants.SectionUSPUT:  7,
+	gppConstants.SectionUSPCT:  7,
+}
+
+// USSignals are the privacy choices of a GPP US National or state section, normalized across the sections.
+type USSignals struct {
+	SectionID                 gppConstants.SectionID
+	SaleOptOut                bool
+	SharingOptOut             bool
+	TargetedAdvertisingOptOut bool
+	// SensitiveDataOptOut is true if the user opted out of, or didn't consent to, processing any sensitive data.
+	SensitiveDataOptOut bool
+	PreciseGeoOptOut    bool
+	// KnownChild is true if the user is a known child and there's no consent to process their data.
+	KnownChild bool
+	GPC        bool
+}
+
+// ReadUSSignals returns the signals of the section if it's a parsed US National or state section.
+func ReadUSSignals(section gpplib.Section) (USSignals, bool) {
+	signals := USSignals{}
+
+	var sensitiveData, knownChild []byte
+	switch s := section.(type) {
+	case uspnat.USPNAT:
+		signals.SaleOptOut = s.CoreSegment.SaleOptOut == usOptedOut
+		signals.SharingOptOut = s.CoreSegment.SharingOptOut == usOptedOut
+		signals.TargetedAdvertisingOptOut = s.CoreSegment.TargetedAdvertisingOptOut == usOptedOut
+		signals.GPC = s.GPCSegment.Gpc
+		sensitiveData, knownChild = s.CoreSegment.SensitiveDataProcessing, s.CoreSegment.KnownChildSensitiveDataConsents
+	case uspca.USPCA:
+		signals.SaleOptOut = s.CoreSegment.SaleOptOut == usOptedOut
+		signals.SharingOptOut = s.CoreSegment.SharingOptOut == usOptedOut
+		signals.GPC = s.GPCSegment.Gpc
+		sensitiveData, knownChild = s.CoreSegment.SensitiveDataProcessing, s.CoreSegment.KnownChildSensitiveDataConsents
+	case uspva.USPVA:
+		signals = readCommonUSSignals(s.CoreSegment, sections.CommonUSGPCSegment{})
+		sensitiveData, knownChild = s.CoreSegment.SensitiveDataProcessing, s.CoreSegment.KnownChildSensitiveDataConsents
+	case uspco.USPCO:
+		signals = readCommonUSSignals(s.CoreSegment, s.GPCSegment)
+		sensitiveData, knownChild = s.CoreSegment.SensitiveDataProcessing, s.CoreSegment.KnownChildSensitiveDataConsents
+	case usput.USPUT:
+		signals.SaleOptOut = s.CoreSegment.SaleOptOut == usOptedOut
+		signals.TargetedAdvertisingOptOut = s.CoreSegment.TargetedAdvertisingOptOut == usOptedOut
+		sensitiveData, knownChild = s.CoreSegment.SensitiveDataProcessing, []byte{s.CoreSegment.KnownChildSensitiveDataConsents}
+	case uspct.USPCT:
+		signals = readCommonUSSignals(s.CoreSegment, s.GPCSegment)
+		sensitiveData, knownChild = s.CoreSegment.SensitiveDataProcessing, s.CoreSegment.KnownChildSensitiveDataConsents
+	default:
+		return USSignals{}, false
+	}
+
+	signals.SectionID = section.GetID()
+	for i, value := range sensitiveData {
+		if value != usNoConsent {
+			continue
+		}
+		signals.SensitiveDataOptOut = true
+		if index, ok := usPreciseGeoIndex[signals.SectionID]; ok && index == i {
+			signals.PreciseGeoOptOut = true
+		}
+	}
+	for _, value := range knownChild {
+		if value == usNoConsent {
+			signals.KnownChild = true
+		}
+	}
+	return signals, true
+}
+
+func readCommonUSSignals(core sections.CommonUSCoreSegment, gpc sections.CommonUSGPCSegment) USSignals {
+	return USSignals{
+		SaleOptOut:                core.SaleOptOut == usOptedOut,
+		TargetedAdvertisingOptOut: core.TargetedAdvertisingOptOut == usOptedOut,
+		GPC:                       gpc.Gpc,
+	}
+}
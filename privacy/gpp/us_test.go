@@ -0,0 +1,135 @@
+package gpp
+
+import (
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspca"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspct"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/go-gpp/sections/usput"
+	"github.com/prebid/go-gpp/sections/uspva"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadUSSignals(t *testing.T) {
+	tcf2, _ := gpplib.Parse("DBABMA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA")
+
+	testCases := []struct {
+		desc            string
+		section         gpplib.Section
+		expectedSignals USSignals
+		expectedOK      bool
+	}{
+		{
+			desc: "usnat_no_choices",
+			section: uspnat.USPNAT{
+				SectionID: gppConstants.SectionUSPNAT,
+				CoreSegment: uspnat.USPNATCoreSegment{
+					SaleOptOut:                      2,
+					SharingOptOut:                   2,
+					TargetedAdvertisingOptOut:       2,
+					SensitiveDataProcessing:         make([]byte, 16),
+					KnownChildSensitiveDataConsents: []byte{0, 0, 0},
+				},
+			},
+			expectedSignals: USSignals{SectionID: gppConstants.SectionUSPNAT},
+			expectedOK:      true,
+		},
+		{
+			desc: "usnat_opted_out",
+			section: uspnat.USPNAT{
+				SectionID: gppConstants.SectionUSPNAT,
+				CoreSegment: uspnat.USPNATCoreSegment{
+					SaleOptOut:                      1,
+					SharingOptOut:                   1,
+					TargetedAdvertisingOptOut:       1,
+					SensitiveDataProcessing:         []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0},
+					KnownChildSensitiveDataConsents: []byte{0, 1, 0},
+				},
+				GPCSegment: sections.CommonUSGPCSegment{Gpc: true},
+			},
+			expectedSignals: USSignals{
+				SectionID:                 gppConstants.SectionUSPNAT,
+				SaleOptOut:                true,
+				SharingOptOut:             true,
+				TargetedAdvertisingOptOut: true,
+				SensitiveDataOptOut:       true,
+				PreciseGeoOptOut:          true,
+				KnownChild:                true,
+				GPC:                       true,
+			},
+			expectedOK: true,
+		},
+		{
+			desc: "usca_sensitive_data_other_than_precise_geo",
+			section: uspca.USPCA{
+				SectionID: gppConstants.SectionUSPCA,
+				CoreSegment: uspca.USPCACoreSegment{
+					SharingOptOut:           1,
+					SensitiveDataProcessing: []byte{1, 0, 0, 0, 0, 0, 0, 0, 0},
+				},
+			},
+			expectedSignals: USSignals{
+				SectionID:           gppConstants.SectionUSPCA,
+				SharingOptOut:       true,
+				SensitiveDataOptOut: true,
+			},
+			expectedOK: true,
+		},
+		{
+			desc: "usva_targeted_advertising",
+			section: uspva.USPVA{
+				SectionID:   gppConstants.SectionUSPVA,
+				CoreSegment: sections.CommonUSCoreSegment{TargetedAdvertisingOptOut: 1},
+			},
+			expectedSignals: USSignals{SectionID: gppConstants.SectionUSPVA, TargetedAdvertisingOptOut: true},
+			expectedOK:      true,
+		},
+		{
+			desc: "usco_has_no_precise_geo",
+			section: uspco.USPCO{
+				SectionID:   gppConstants.SectionUSPCO,
+				CoreSegment: sections.CommonUSCoreSegment{SensitiveDataProcessing: []byte{0, 0, 1, 1, 1, 1, 1}},
+				GPCSegment:  sections.CommonUSGPCSegment{Gpc: true},
+			},
+			expectedSignals: USSignals{SectionID: gppConstants.SectionUSPCO, SensitiveDataOptOut: true, GPC: true},
+			expectedOK:      true,
+		},
+		{
+			desc: "usut_known_child",
+			section: usput.USPUT{
+				SectionID:   gppConstants.SectionUSPUT,
+				CoreSegment: usput.USPUTCoreSegment{SaleOptOut: 1, KnownChildSensitiveDataConsents: 1},
+			},
+			expectedSignals: USSignals{SectionID: gppConstants.SectionUSPUT, SaleOptOut: true, KnownChild: true},
+			expectedOK:      true,
+		},
+		{
+			desc: "usct_precise_geo",
+			section: uspct.USPCT{
+				SectionID:   gppConstants.SectionUSPCT,
+				CoreSegment: sections.CommonUSCoreSegment{SensitiveDataProcessing: []byte{0, 0, 0, 0, 0, 0, 0, 1}},
+			},
+			expectedSignals: USSignals{SectionID: gppConstants.SectionUSPCT, SensitiveDataOptOut: true, PreciseGeoOptOut: true},
+			expectedOK:      true,
+		},
+		{
+			desc:            "not_a_us_section",
+			section:         tcf2.Sections[0],
+			expectedSignals: USSignals{},
+			expectedOK:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			signals, ok := ReadUSSignals(tc.section)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedSignals, signals)
+		})
+	}
+}
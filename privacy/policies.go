@@ -1,6 +1,9 @@
 package privacy
 
+import gpplib "github.com/prebid/go-gpp"
+
 // Policies contains privacy signals and consent for non-OpenRTB activities.
 type Policies struct {
 	GPPSID []int8
+	GPP    gpplib.GppContainer
 }
@@ -0,0 +1,185 @@
+package privacy
+
+import (
+	"sync"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	gppPolicy "github.com/prebid/prebid-server/v3/privacy/gpp"
+)
+
+// usnatActivities are the activities decided by the GPP US National and state sections.
+var usnatActivities = []Activity{
+	ActivitySyncUser,
+	ActivityTransmitUserFPD,
+	ActivityTransmitPreciseGeo,
+	ActivityTransmitUniqueRequestIDs,
+}
+
+// USNatRule decides an activity from the GPP US National and state sections of the request listed in the GPP SID.
+// It denies the activity if a section carries a choice which restricts it, and abstains otherwise.
+type USNatRule struct {
+	activity   Activity
+	sections   []gppConstants.SectionID
+	enforceGPC bool
+	trace      *usnatTrace
+}
+
+func newUSNatRule(activity Activity, cfg config.AccountUSNat, trace *usnatTrace) USNatRule {
+	rule := USNatRule{
+		activity:   activity,
+		sections:   gppPolicy.USSectionIDs,
+		enforceGPC: cfg.EnforceGPC,
+		trace:      trace,
+	}
+	if len(cfg.Sections) > 0 {
+		rule.sections = make([]gppConstants.SectionID, 0, len(cfg.Sections))
+		for _, id := range cfg.Sections {
+			rule.sections = append(rule.sections, gppConstants.SectionID(id))
+		}
+	}
+	return rule
+}
+
+func (r USNatRule) Evaluate(target Component, request ActivityRequest) ActivityResult {
+	gppSID := getGPPSID(request)
+	if !r.inScope(gppSID) {
+		return ActivityAbstain
+	}
+
+	gpp := getGPP(request)
+	result := ActivityAbstain
+	for _, section := range gpp.Sections {
+		if !r.enforces(section.GetID(), gppSID) {
+			continue
+		}
+		signals, ok := gppPolicy.ReadUSSignals(section)
+		if !ok {
+			continue
+		}
+
+		reasons := r.restrictions(signals)
+		r.trace.record(openrtb_ext.ExtUSNatDecision{
+			Activity:  r.activity.String(),
+			Component: target.Name,
+			SectionID: int(signals.SectionID),
+			Allowed:   len(reasons) == 0,
+			Reasons:   reasons,
+		})
+		if len(reasons) > 0 {
+			result = ActivityDeny
+		}
+	}
+	return result
+}
+
+// inScope returns true if one of the GPP SIDs is a section enforced by the rule.
+func (r USNatRule) inScope(gppSID []int8) bool {
+	for _, id := range r.sections {
+		if gppPolicy.IsSIDInList(gppSID, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// enforces returns true if the section is listed in the GPP SIDs and enforced by the rule.
+func (r USNatRule) enforces(id gppConstants.SectionID, gppSID []int8) bool {
+	if !gppPolicy.IsSIDInList(gppSID, id) {
+		return false
+	}
+	for _, section := range r.sections {
+		if section == id {
+			return true
+		}
+	}
+	return false
+}
+
+// restrictions returns the choices of the user which restrict the activity.
+func (r USNatRule) restrictions(signals gppPolicy.USSignals) []string {
+	var reasons []string
+	add := func(restricted bool, reason string) {
+		if restricted {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	if r.activity == ActivityTransmitPreciseGeo {
+		add(signals.PreciseGeoOptOut, "precise_geo_opt_out")
+		add(signals.KnownChild, "known_child")
+		return reasons
+	}
+
+	add(signals.SaleOptOut, "sale_opt_out")
+	add(signals.SharingOptOut, "sharing_opt_out")
+	add(signals.TargetedAdvertisingOptOut, "targeted_advertising_opt_out")
+	if r.activity == ActivityTransmitUserFPD {
+		add(signals.SensitiveDataOptOut, "sensitive_data_opt_out")
+	}
+	add(signals.KnownChild, "known_child")
+	add(r.enforceGPC && signals.GPC, "gpc")
+	return reasons
+}
+
+func getGPP(request ActivityRequest) gpplib.GppContainer {
+	if request.IsPolicies() {
+		return request.policies.GPP
+	}
+
+	if request.gpp != nil {
+		return *request.gpp
+	}
+
+	if request.IsBidRequest() && request.bidRequest.Regs != nil && request.bidRequest.Regs.GPP != "" {
+		// a malformed GPP string carries no choices to enforce, as with the other privacy policies
+		if gpp, errs := gpplib.Parse(request.bidRequest.Regs.GPP); len(errs) == 0 {
+			return gpp
+		}
+	}
+
+	return gpplib.GppContainer{}
+}
+
+// usnatTrace collects the decisions of the US National and state sections for the debug output. An activity
+// decided again for the same component and section is only recorded once.
+type usnatTrace struct {
+	mu        sync.Mutex
+	decisions []openrtb_ext.ExtUSNatDecision
+	seen      map[usnatDecisionKey]struct{}
+}
+
+type usnatDecisionKey struct {
+	activity  string
+	component string
+	sectionID int
+}
+
+func (t *usnatTrace) record(decision openrtb_ext.ExtUSNatDecision) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := usnatDecisionKey{activity: decision.Activity, component: decision.Component, sectionID: decision.SectionID}
+	if _, ok := t.seen[key]; ok {
+		return
+	}
+	if t.seen == nil {
+		t.seen = make(map[usnatDecisionKey]struct{})
+	}
+	t.seen[key] = struct{}{}
+	t.decisions = append(t.decisions, decision)
+}
+
+func (t *usnatTrace) get() []openrtb_ext.ExtUSNatDecision {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]openrtb_ext.ExtUSNatDecision(nil), t.decisions...)
+}
@@ -0,0 +1,211 @@
+package privacy
+
+import (
+	"testing"
+
+	gpplib "github.com/prebid/go-gpp"
+	gppConstants "github.com/prebid/go-gpp/constants"
+	"github.com/prebid/go-gpp/sections"
+	"github.com/prebid/go-gpp/sections/uspco"
+	"github.com/prebid/go-gpp/sections/uspnat"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUSNatRuleEvaluate(t *testing.T) {
+	saleOptOut := uspnat.USPNAT{
+		SectionID: gppConstants.SectionUSPNAT,
+		CoreSegment: uspnat.USPNATCoreSegment{
+			SaleOptOut:              1,
+			SharingOptOut:           2,
+			SensitiveDataProcessing: make([]byte, 16),
+		},
+	}
+	preciseGeoOptOut := uspnat.USPNAT{
+		SectionID: gppConstants.SectionUSPNAT,
+		CoreSegment: uspnat.USPNATCoreSegment{
+			SensitiveDataProcessing: []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0},
+		},
+	}
+	gpcOnly := uspco.USPCO{
+		SectionID:  gppConstants.SectionUSPCO,
+		GPCSegment: sections.CommonUSGPCSegment{Gpc: true},
+	}
+
+	testCases := []struct {
+		name           string
+		activity       Activity
+		cfg            config.AccountUSNat
+		gppSID         []int8
+		sections       []gpplib.Section
+		expectedResult ActivityResult
+	}{
+		{
+			name:           "sale_opt_out_denies_sync",
+			activity:       ActivitySyncUser,
+			gppSID:         []int8{7},
+			sections:       []gpplib.Section{saleOptOut},
+			expectedResult: ActivityDeny,
+		},
+		{
+			name:           "sale_opt_out_allows_precise_geo",
+			activity:       ActivityTransmitPreciseGeo,
+			gppSID:         []int8{7},
+			sections:       []gpplib.Section{saleOptOut},
+			expectedResult: ActivityAbstain,
+		},
+		{
+			name:           "precise_geo_opt_out_denies_precise_geo",
+			activity:       ActivityTransmitPreciseGeo,
+			gppSID:         []int8{7},
+			sections:       []gpplib.Section{preciseGeoOptOut},
+			expectedResult: ActivityDeny,
+		},
+		{
+			name:           "precise_geo_opt_out_denies_fpd_as_sensitive_data",
+			activity:       ActivityTransmitUserFPD,
+			gppSID:         []int8{7},
+			sections:       []gpplib.Section{preciseGeoOptOut},
+			expectedResult: ActivityDeny,
+		},
+		{
+			name:           "section_not_in_gpp_sid",
+			activity:       ActivitySyncUser,
+			gppSID:         []int8{2},
+			sections:       []gpplib.Section{saleOptOut},
+			expectedResult: ActivityAbstain,
+		},
+		{
+			name:           "section_not_enforced_by_account",
+			activity:       ActivitySyncUser,
+			cfg:            config.AccountUSNat{Sections: []int{8}},
+			gppSID:         []int8{7},
+			sections:       []gpplib.Section{saleOptOut},
+			expectedResult: ActivityAbstain,
+		},
+		{
+			name:           "gpc_not_enforced",
+			activity:       ActivitySyncUser,
+			gppSID:         []int8{10},
+			sections:       []gpplib.Section{gpcOnly},
+			expectedResult: ActivityAbstain,
+		},
+		{
+			name:           "gpc_enforced",
+			activity:       ActivitySyncUser,
+			cfg:            config.AccountUSNat{EnforceGPC: true},
+			gppSID:         []int8{10},
+			sections:       []gpplib.Section{gpcOnly},
+			expectedResult: ActivityDeny,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			rule := newUSNatRule(test.activity, test.cfg, nil)
+			request := NewRequestFromPolicies(Policies{GPPSID: test.gppSID, GPP: gpplib.GppContainer{Sections: test.sections}})
+
+			result := rule.Evaluate(Component{Type: ComponentTypeBidder, Name: "bidderA"}, request)
+			assert.Equal(t, test.expectedResult, result)
+		})
+	}
+}
+
+func TestUSNatRuleEvaluateBidRequest(t *testing.T) {
+	gpp, err := gpplib.Encode([]gpplib.Section{
+		uspnat.USPNAT{
+			SectionID: gppConstants.SectionUSPNAT,
+			CoreSegment: uspnat.USPNATCoreSegment{
+				Version:                         1,
+				TargetedAdvertisingOptOut:       1,
+				SensitiveDataProcessing:         make([]byte, 16),
+				KnownChildSensitiveDataConsents: make([]byte, 3),
+			},
+			GPCSegment: sections.CommonUSGPCSegment{SubsectionType: 1},
+		},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		regs           *openrtb2.Regs
+		expectedResult ActivityResult
+	}{
+		{
+			name:           "targeted_advertising_opt_out",
+			regs:           &openrtb2.Regs{GPP: gpp, GPPSID: []int8{7}},
+			expectedResult: ActivityDeny,
+		},
+		{
+			name:           "malformed_gpp",
+			regs:           &openrtb2.Regs{GPP: "malformed", GPPSID: []int8{7}},
+			expectedResult: ActivityAbstain,
+		},
+		{
+			name:           "no_regs",
+			expectedResult: ActivityAbstain,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			rule := newUSNatRule(ActivityTransmitUniqueRequestIDs, config.AccountUSNat{}, nil)
+			request := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Regs: test.regs}})
+
+			result := rule.Evaluate(Component{Type: ComponentTypeBidder, Name: "bidderA"}, request)
+			assert.Equal(t, test.expectedResult, result)
+		})
+	}
+
+	t.Run("parsed_gpp", func(t *testing.T) {
+		rule := newUSNatRule(ActivityTransmitUniqueRequestIDs, config.AccountUSNat{}, nil)
+		parsed, errs := gpplib.Parse(gpp)
+		require.Empty(t, errs)
+		// The GPP string already parsed is used in place of the one of the bid request
+		request := NewRequestFromBidRequest(openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Regs: &openrtb2.Regs{GPP: "malformed", GPPSID: []int8{7}}}}).WithGPP(parsed)
+
+		assert.Equal(t, ActivityDeny, rule.Evaluate(Component{Type: ComponentTypeBidder, Name: "bidderA"}, request))
+	})
+}
+
+func TestActivityControlUSNat(t *testing.T) {
+	privacyConf := &config.AccountPrivacy{
+		AllowActivities: &config.AllowActivities{
+			SyncUser: getTestActivityConfig(true),
+		},
+		USNat: config.AccountUSNat{Enabled: true},
+	}
+	optOut := uspnat.USPNAT{
+		SectionID: gppConstants.SectionUSPNAT,
+		CoreSegment: uspnat.USPNATCoreSegment{
+			SaleOptOut:                      1,
+			SensitiveDataProcessing:         make([]byte, 16),
+			KnownChildSensitiveDataConsents: []byte{0, 1, 0},
+		},
+	}
+	request := NewRequestFromPolicies(Policies{GPPSID: []int8{7}, GPP: gpplib.GppContainer{Sections: []gpplib.Section{optOut}}})
+
+	ac := NewActivityControl(privacyConf)
+
+	assert.True(t, ac.Allow(ActivitySyncUser, Component{Type: ComponentTypeBidder, Name: "bidderA"}, request), "account rules take precedence")
+	assert.False(t, ac.Allow(ActivitySyncUser, Component{Type: ComponentTypeBidder, Name: "bidderB"}, request))
+	assert.False(t, ac.Allow(ActivitySyncUser, Component{Type: ComponentTypeBidder, Name: "bidderB"}, request), "decided again")
+	assert.False(t, ac.Allow(ActivityTransmitPreciseGeo, Component{Type: ComponentTypeBidder, Name: "bidderB"}, request), "known child")
+	assert.True(t, ac.Allow(ActivityFetchBids, Component{Type: ComponentTypeBidder, Name: "bidderB"}, request))
+
+	expectedDecisions := []openrtb_ext.ExtUSNatDecision{
+		{Activity: "syncUser", Component: "bidderB", SectionID: 7, Allowed: false, Reasons: []string{"sale_opt_out", "known_child"}},
+		{Activity: "transmitPreciseGeo", Component: "bidderB", SectionID: 7, Allowed: false, Reasons: []string{"known_child"}},
+	}
+	assert.Equal(t, expectedDecisions, ac.USNatDecisions())
+}
+
+func TestActivityControlUSNatDisabled(t *testing.T) {
+	ac := NewActivityControl(&config.AccountPrivacy{USNat: config.AccountUSNat{Enabled: false}})
+
+	assert.Equal(t, ActivityControl{}, ac)
+	assert.Nil(t, ac.USNatDecisions())
+}
@@ -5,12 +5,15 @@ import (
 	"net/http/pprof"
 	"time"
 
+	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/currency"
 	"github.com/prebid/prebid-server/v3/endpoints"
+	"github.com/prebid/prebid-server/v3/floorsreport"
+	"github.com/prebid/prebid-server/v3/usersync"
 	"github.com/prebid/prebid-server/v3/version"
 )
 
-func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration) *http.ServeMux {
+func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration, reloadConfig func() (config.ReloadReport, error), storedDataAdmin http.Handler, floorsReporter *floorsreport.Reporter, uidStore usersync.UIDStore, consentDiagnostics http.HandlerFunc) *http.ServeMux {
 	// Add endpoints to the admin server
 	// Making sure to add pprof routes
 	mux := http.NewServeMux()
@@ -23,5 +26,18 @@ func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval
 	// Register prebid-server defined admin handlers
 	mux.HandleFunc("/currency/rates", endpoints.NewCurrencyRatesEndpoint(rateConverter, rateConverterFetchingInterval))
 	mux.HandleFunc("/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
+	mux.HandleFunc("/config/reload", endpoints.NewConfigReloadEndpoint(reloadConfig))
+	if storedDataAdmin != nil {
+		mux.Handle("/stored_data/", storedDataAdmin)
+	}
+	if floorsReporter != nil {
+		mux.HandleFunc("/floors/report", endpoints.NewFloorsReportEndpoint(floorsReporter))
+	}
+	if uidStore != nil {
+		mux.HandleFunc("/uid_store/delete", endpoints.NewUIDStoreDeleteEndpoint(uidStore))
+	}
+	if consentDiagnostics != nil {
+		mux.HandleFunc("/consent/diagnostics", consentDiagnostics)
+	}
 	return mux
 }
@@ -0,0 +1,27 @@
+#!/bin/bash
+# Download the latest GDPR vendor lists into the snapshot bundled in the Prebid Server binary.
+#
+# Usage: scripts/vendorlist-snapshot.sh [spec versions...]
+#
+# The spec versions default to 2 and 3. Run it from the root of the repository before building.
+
+set -e
+
+snapshot=gdpr/vendorlists
+specversions=${@:-2 3}
+
+for spec in $specversions; do
+  tmp=$(mktemp)
+  curl -sSf "https://vendor-list.consensu.org/v${spec}/vendor-list.json" -o "$tmp"
+
+  version=$(grep -o '"vendorListVersion": *[0-9]*' "$tmp" | grep -o '[0-9]*$')
+  if [ -z "$version" ]; then
+    echo "The v${spec} vendor list has no vendorListVersion" >&2
+    rm -f "$tmp"
+    exit 1
+  fi
+
+  mkdir -p "$snapshot/v${spec}"
+  mv "$tmp" "$snapshot/v${spec}/vendor-list-v${version}.json"
+  echo "Added $snapshot/v${spec}/vendor-list-v${version}.json"
+done
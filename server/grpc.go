@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/endpoints/openrtb2"
+	"google.golang.org/grpc"
+)
+
+func newGRPCServer(cfg *config.Configuration, auction *openrtb2.GRPCAuctionServer) *grpc.Server {
+	var opts []grpc.ServerOption
+	if cfg.MaxRequestSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(int(cfg.MaxRequestSize)))
+	}
+
+	server := grpc.NewServer(opts...)
+	openrtb2.RegisterGRPCAuctionServer(server, auction)
+	return server
+}
+
+func grpcAddress(cfg *config.Configuration) string {
+	return cfg.Host + ":" + strconv.Itoa(cfg.GRPCPort)
+}
+
+func runGRPCServer(server *grpc.Server, name string, listener net.Listener) (err error) {
+	if server == nil {
+		err = fmt.Errorf(">> Server is a nil_ptr.")
+		glog.Errorf("%s server quit with error: %v", name, err)
+		return
+	} else if listener == nil {
+		err = fmt.Errorf(">> Listener is a nil.")
+		glog.Errorf("%s server quit with error: %v", name, err)
+		return
+	}
+
+	glog.Infof("%s server starting on: %s", name, listener.Addr())
+	if err = server.Serve(listener); err != nil {
+		glog.Errorf("%s server quit with error: %v", name, err)
+	}
+	return
+}
+
+// shutdownGRPCAfterSignals is the gRPC counterpart of shutdownAfterSignals. In-flight auctions are given
+// the same amount of time to finish before the remaining connections are closed.
+func shutdownGRPCAfterSignals(server *grpc.Server, address string, stopper <-chan os.Signal, done chan<- struct{}) {
+	sig := <-stopper
+
+	var s struct{}
+	glog.Infof("Stopping %s because of signal: %s", address, sig.String())
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		glog.Errorf("Failed to gracefully shutdown %s, forcing it to stop", address)
+		server.Stop()
+	}
+	done <- s
+}
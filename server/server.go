@@ -14,12 +14,15 @@ import (
 	"github.com/NYTimes/gziphandler"
 	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/endpoints/openrtb2"
 	"github.com/prebid/prebid-server/v3/metrics"
 	metricsconfig "github.com/prebid/prebid-server/v3/metrics/config"
 )
 
 // Listen blocks forever, serving PBS requests on the given port. This will block forever, until the process is shut down.
-func Listen(cfg *config.Configuration, handler http.Handler, adminHandler http.Handler, metrics *metricsconfig.DetailedMetricsEngine) (err error) {
+//
+// If cfg.GRPC is enabled, grpcAuction is also served on cfg.GRPCPort.
+func Listen(cfg *config.Configuration, handler http.Handler, adminHandler http.Handler, grpcAuction *openrtb2.GRPCAuctionServer, metrics *metricsconfig.DetailedMetricsEngine) (err error) {
 	stopSignals := make(chan os.Signal, 1)
 	signal.Notify(stopSignals, syscall.SIGTERM, syscall.SIGINT)
 
@@ -27,6 +30,7 @@ func Listen(cfg *config.Configuration, handler http.Handler, adminHandler http.H
 	stopAdmin := make(chan os.Signal)
 	stopMain := make(chan os.Signal)
 	stopPrometheus := make(chan os.Signal)
+	stopGRPC := make(chan os.Signal)
 	stopChannels := []chan<- os.Signal{stopMain}
 	done := make(chan struct{})
 
@@ -67,6 +71,19 @@ func Listen(cfg *config.Configuration, handler http.Handler, adminHandler http.H
 		go runServer(adminServer, "Admin", adminListener)
 	}
 
+	if cfg.GRPC.Enabled && grpcAuction != nil {
+		stopChannels = append(stopChannels, stopGRPC)
+		grpcServer := newGRPCServer(cfg, grpcAuction)
+		go shutdownGRPCAfterSignals(grpcServer, grpcAddress(cfg), stopGRPC, done)
+
+		var grpcListener net.Listener
+		if grpcListener, err = newTCPListener(grpcAddress(cfg), metrics); err != nil {
+			glog.Errorf("Error listening for TCP connections on %s: %v for gRPC server", grpcAddress(cfg), err)
+			return
+		}
+		go runGRPCServer(grpcServer, "gRPC", grpcListener)
+	}
+
 	if cfg.Metrics.Prometheus.Port != 0 {
 		var (
 			prometheusListener net.Listener
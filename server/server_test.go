@@ -10,9 +10,11 @@ import (
 	"time"
 
 	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/endpoints/openrtb2"
 	metricsconfig "github.com/prebid/prebid-server/v3/metrics/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 )
 
 func TestServerShutdown(t *testing.T) {
@@ -161,6 +163,23 @@ func TestNewAdminServer(t *testing.T) {
 	ret.Close()
 }
 
+func TestNewGRPCServer(t *testing.T) {
+	cfg := &config.Configuration{Host: "prebid.com", GRPCPort: 8001}
+
+	server := newGRPCServer(cfg, &openrtb2.GRPCAuctionServer{})
+	require.NotNil(t, server)
+	assert.Contains(t, server.GetServiceInfo(), "prebid.openrtb.Auction")
+	assert.Equal(t, "prebid.com:8001", grpcAddress(cfg))
+}
+
+func TestRunGRPCServer(t *testing.T) {
+	err := runGRPCServer(nil, "mockServer_name", nil)
+	assert.Error(t, err, "runGRPCServer(nil, 'mockName', nil) : didn't trigger any error.")
+
+	err = runGRPCServer(grpc.NewServer(), "mockServer_name", nil)
+	assert.Error(t, err, "runGRPCServer(not_nil, 'mockName', nil) : didn't trigger any error.")
+}
+
 func TestRunServer(t *testing.T) {
 	const mockName = "mockServer_name"
 
@@ -191,6 +210,6 @@ func TestListen(t *testing.T) {
 		}
 	)
 
-	err := Listen(cfg, handler, adminHandler, metrics)
+	err := Listen(cfg, handler, adminHandler, nil, metrics)
 	assert.NotEqual(t, nil, err, "err : isNil()")
 }
@@ -0,0 +1,123 @@
+package db_fetcher
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
+)
+
+// NewWriter returns a Writer for Stored Requests and Imps which reads through the fetcher, and writes with
+// the queries described in config.DatabaseWriterQueries.
+func NewWriter(provider db_provider.DbProvider, fetcher stored_requests.Fetcher, queries config.DatabaseWriterQueries) stored_requests.Writer {
+	if provider == nil {
+		glog.Fatalf("The Database Stored Request Writer requires a database connection. Please report this as a bug.")
+	}
+	return &dbWriter{
+		provider: provider,
+		fetcher:  fetcher,
+		queries:  queries,
+	}
+}
+
+type dbWriter struct {
+	provider db_provider.DbProvider
+	fetcher  stored_requests.Fetcher
+	queries  config.DatabaseWriterQueries
+}
+
+func (writer *dbWriter) List(ctx context.Context, dataType stored_requests.DataType, offset, limit int) ([]string, error) {
+	if err := checkWritable(dataType); err != nil {
+		return nil, err
+	}
+
+	rows, err := writer.provider.QueryContext(ctx, writer.queries.ListQuery,
+		db_provider.QueryParam{Name: "DATA_TYPE", Value: string(dataType)},
+		db_provider.QueryParam{Name: "LIMIT", Value: limit},
+		db_provider.QueryParam{Name: "OFFSET", Value: offset},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			glog.Errorf("error closing DB connection: %v", err)
+		}
+	}()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (writer *dbWriter) Get(ctx context.Context, dataType stored_requests.DataType, id string) (json.RawMessage, error) {
+	if err := checkWritable(dataType); err != nil {
+		return nil, err
+	}
+
+	var requestIDs, impIDs []string
+	if dataType == stored_requests.RequestData {
+		requestIDs = []string{id}
+	} else {
+		impIDs = []string{id}
+	}
+	requestData, impData, errs := writer.fetcher.FetchRequests(ctx, requestIDs, impIDs)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	if dataType == stored_requests.RequestData {
+		return requestData[id], nil
+	}
+	return impData[id], nil
+}
+
+func (writer *dbWriter) Save(ctx context.Context, dataType stored_requests.DataType, id string, data json.RawMessage) error {
+	if err := checkWritable(dataType); err != nil {
+		return err
+	}
+	// $DATA_TYPE must be replaced before $DATA, which is a prefix of it
+	return writer.exec(ctx, writer.queries.UpsertQuery,
+		db_provider.QueryParam{Name: "DATA_TYPE", Value: string(dataType)},
+		db_provider.QueryParam{Name: "ID", Value: id},
+		db_provider.QueryParam{Name: "DATA", Value: string(data)},
+	)
+}
+
+func (writer *dbWriter) Delete(ctx context.Context, dataType stored_requests.DataType, id string) error {
+	if err := checkWritable(dataType); err != nil {
+		return err
+	}
+	return writer.exec(ctx, writer.queries.DeleteQuery,
+		db_provider.QueryParam{Name: "DATA_TYPE", Value: string(dataType)},
+		db_provider.QueryParam{Name: "ID", Value: id},
+	)
+}
+
+// exec runs a query which returns no rows. DbProvider only offers QueryContext, which works as well.
+func (writer *dbWriter) exec(ctx context.Context, template string, params ...db_provider.QueryParam) error {
+	rows, err := writer.provider.QueryContext(ctx, template, params...)
+	if err != nil {
+		return err
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+// checkWritable rejects accounts, which the database fetcher doesn't read.
+func checkWritable(dataType stored_requests.DataType) error {
+	if dataType != stored_requests.RequestData && dataType != stored_requests.ImpData {
+		return stored_requests.ErrWriteUnsupported
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package db_fetcher
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
+	"github.com/stretchr/testify/assert"
+)
+
+var testWriterQueries = config.DatabaseWriterQueries{
+	ListQuery:   "SELECT id FROM stored_data WHERE type = $DATA_TYPE ORDER BY id LIMIT $LIMIT OFFSET $OFFSET",
+	UpsertQuery: "INSERT INTO stored_data (id, type, data) VALUES ($ID, $DATA_TYPE, $DATA)",
+	DeleteQuery: "DELETE FROM stored_data WHERE id = $ID AND type = $DATA_TYPE",
+}
+
+func newTestWriter(t *testing.T, fetchQuery string) (sqlmock.Sqlmock, stored_requests.Writer) {
+	provider, mock, err := db_provider.NewDbProviderMock()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	fetcher := &dbFetcher{provider: provider, queryTemplate: fetchQuery, responseQueryTemplate: fetchQuery}
+	return mock, NewWriter(provider, fetcher, testWriterQueries)
+}
+
+func expectQuery(mock sqlmock.Sqlmock, query string, args ...driver.Value) *sqlmock.ExpectedQuery {
+	return mock.ExpectQuery(fmt.Sprintf("^%s$", regexp.QuoteMeta(query))).WithArgs(args...)
+}
+
+func TestWriterList(t *testing.T) {
+	mock, writer := newTestWriter(t, "")
+	expectQuery(mock, testWriterQueries.ListQuery, "imp", 2, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("imp-1").AddRow("imp-2"))
+
+	ids, err := writer.List(context.Background(), stored_requests.ImpData, 4, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"imp-1", "imp-2"}, ids)
+	assertMockExpectations(t, mock)
+}
+
+func TestWriterGet(t *testing.T) {
+	fetchQuery := "SELECT id, data, type FROM stored_data WHERE id IN $REQUEST_ID_LIST OR id IN $IMP_ID_LIST"
+	mock, writer := newTestWriter(t, fetchQuery)
+	expectQuery(mock, fetchQuery, "request-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "data", "dataType"}).AddRow("request-1", `{"id":"request-1"}`, "request"))
+	expectQuery(mock, fetchQuery, "missing").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "data", "dataType"}))
+
+	data, err := writer.Get(context.Background(), stored_requests.RequestData, "request-1")
+	assert.NoError(t, err)
+	assert.Equal(t, json.RawMessage(`{"id":"request-1"}`), data)
+
+	_, err = writer.Get(context.Background(), stored_requests.ImpData, "missing")
+	assert.Equal(t, stored_requests.NotFoundError{ID: "missing", DataType: "Imp"}, err)
+	assertMockExpectations(t, mock)
+}
+
+func TestWriterSaveAndDelete(t *testing.T) {
+	mock, writer := newTestWriter(t, "")
+	expectQuery(mock, testWriterQueries.UpsertQuery, "request", "request-1", `{"id":"request-1"}`).
+		WillReturnRows(sqlmock.NewRows(nil))
+	expectQuery(mock, testWriterQueries.DeleteQuery, "request", "request-1").
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	assert.NoError(t, writer.Save(context.Background(), stored_requests.RequestData, "request-1", json.RawMessage(`{"id":"request-1"}`)))
+	assert.NoError(t, writer.Delete(context.Background(), stored_requests.RequestData, "request-1"))
+	assertMockExpectations(t, mock)
+}
+
+func TestWriterAccountsUnsupported(t *testing.T) {
+	_, writer := newTestWriter(t, "")
+
+	_, err := writer.List(context.Background(), stored_requests.AccountData, 0, 10)
+	assert.Equal(t, stored_requests.ErrWriteUnsupported, err)
+	assert.Equal(t, stored_requests.ErrWriteUnsupported, writer.Save(context.Background(), stored_requests.AccountData, "account", nil))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/prebid/prebid-server/v3/stored_requests"
 	"github.com/prebid/prebid-server/v3/util/jsonutil"
@@ -18,19 +19,31 @@ import (
 //
 // This expects each file in the directory to be named "{config_id}.json".
 // For example, when asked to fetch the request with ID == "23", it will return the data from "directory/23.json".
+//
+// The returned fetcher is also a stored_requests.Writer, which writes the files back to the directory.
 func NewFileFetcher(directory string) (stored_requests.AllFetcher, error) {
 	storedData, err := collectStoredData(directory, FileSystem{make(map[string]FileSystem), make(map[string]json.RawMessage)}, nil)
-	return &eagerFetcher{storedData, nil}, err
+	return &eagerFetcher{FileSystem: storedData, directory: directory}, err
 }
 
 type eagerFetcher struct {
 	FileSystem FileSystem
 	Categories map[string]map[string]stored_requests.Category
+	directory  string
+	// lock guards FileSystem, which writes replace rather than modify since the fetched maps are shared.
+	lock sync.RWMutex
+}
+
+func (fetcher *eagerFetcher) fileSystem() FileSystem {
+	fetcher.lock.RLock()
+	defer fetcher.lock.RUnlock()
+	return fetcher.FileSystem
 }
 
 func (fetcher *eagerFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
-	storedRequests := fetcher.FileSystem.Directories["stored_requests"].Files
-	storedImpressions := fetcher.FileSystem.Directories["stored_imps"].Files
+	fileSystem := fetcher.fileSystem()
+	storedRequests := fileSystem.Directories["stored_requests"].Files
+	storedImpressions := fileSystem.Directories["stored_imps"].Files
 	errs := appendErrors("Request", requestIDs, storedRequests, nil)
 	errs = appendErrors("Imp", impIDs, storedImpressions, errs)
 	return storedRequests, storedImpressions, errs
@@ -38,7 +51,7 @@ func (fetcher *eagerFetcher) FetchRequests(ctx context.Context, requestIDs []str
 
 // Fetch Responses - Implements the interface to read the stored response information from the fetcher's FileSystem, the directory name is "stored_responses"
 func (fetcher *eagerFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
-	storedRespFS, found := fetcher.FileSystem.Directories["stored_responses"]
+	storedRespFS, found := fetcher.fileSystem().Directories["stored_responses"]
 	if !found {
 		return nil, append(errs, errors.New(`no "stored_responses" directory found`))
 	}
@@ -52,7 +65,7 @@ func (fetcher *eagerFetcher) FetchAccount(ctx context.Context, accountDefaultsJS
 	if len(accountID) == 0 {
 		return nil, []error{fmt.Errorf("Cannot look up an empty accountID")}
 	}
-	accountJSON, ok := fetcher.FileSystem.Directories["accounts"].Files[accountID]
+	accountJSON, ok := fetcher.fileSystem().Directories["accounts"].Files[accountID]
 	if !ok {
 		return nil, []error{stored_requests.NotFoundError{
 			ID:       accountID,
@@ -0,0 +1,134 @@
+package file_fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/prebid/prebid-server/v3/stored_requests"
+)
+
+// directoryNames maps each type of stored data to the directory its files are read from.
+var directoryNames = map[stored_requests.DataType]string{
+	stored_requests.RequestData: "stored_requests",
+	stored_requests.ImpData:     "stored_imps",
+	stored_requests.AccountData: "accounts",
+}
+
+func (fetcher *eagerFetcher) List(ctx context.Context, dataType stored_requests.DataType, offset, limit int) ([]string, error) {
+	directoryName, ok := directoryNames[dataType]
+	if !ok {
+		return nil, stored_requests.ErrWriteUnsupported
+	}
+	files := fetcher.fileSystem().Directories[directoryName].Files
+
+	ids := make([]string, 0, len(files))
+	for id := range files {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	if offset >= len(ids) {
+		return []string{}, nil
+	}
+	ids = ids[offset:]
+	if limit < len(ids) {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+func (fetcher *eagerFetcher) Get(ctx context.Context, dataType stored_requests.DataType, id string) (json.RawMessage, error) {
+	directoryName, ok := directoryNames[dataType]
+	if !ok {
+		return nil, stored_requests.ErrWriteUnsupported
+	}
+	data, ok := fetcher.fileSystem().Directories[directoryName].Files[id]
+	if !ok {
+		return nil, stored_requests.NotFoundError{ID: id, DataType: string(dataType)}
+	}
+	return data, nil
+}
+
+// Save writes the data to "{directory}/{type directory}/{id}.json", replacing the file atomically, and
+// makes it visible to the fetcher.
+func (fetcher *eagerFetcher) Save(ctx context.Context, dataType stored_requests.DataType, id string, data json.RawMessage) error {
+	directoryName, ok := directoryNames[dataType]
+	if !ok {
+		return stored_requests.ErrWriteUnsupported
+	}
+	if err := stored_requests.ValidateID(id); err != nil {
+		return err
+	}
+
+	fetcher.lock.Lock()
+	defer fetcher.lock.Unlock()
+
+	directory := filepath.Join(fetcher.directory, directoryName)
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(directory, "."+id+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(file.Name(), filepath.Join(directory, id+".json")); err != nil {
+		return err
+	}
+
+	fetcher.replaceFiles(directoryName, func(files map[string]json.RawMessage) {
+		files[id] = data
+	})
+	return nil
+}
+
+func (fetcher *eagerFetcher) Delete(ctx context.Context, dataType stored_requests.DataType, id string) error {
+	directoryName, ok := directoryNames[dataType]
+	if !ok {
+		return stored_requests.ErrWriteUnsupported
+	}
+	if err := stored_requests.ValidateID(id); err != nil {
+		return err
+	}
+
+	fetcher.lock.Lock()
+	defer fetcher.lock.Unlock()
+
+	if err := os.Remove(filepath.Join(fetcher.directory, directoryName, id+".json")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	fetcher.replaceFiles(directoryName, func(files map[string]json.RawMessage) {
+		delete(files, id)
+	})
+	return nil
+}
+
+// replaceFiles swaps in a modified copy of the files of a directory. Maps handed out by the Fetch methods
+// are never written to. The caller must hold the write lock.
+func (fetcher *eagerFetcher) replaceFiles(directoryName string, modify func(map[string]json.RawMessage)) {
+	oldDirectory := fetcher.FileSystem.Directories[directoryName]
+	files := make(map[string]json.RawMessage, len(oldDirectory.Files)+1)
+	for id, data := range oldDirectory.Files {
+		files[id] = data
+	}
+	modify(files)
+
+	directories := make(map[string]FileSystem, len(fetcher.FileSystem.Directories)+1)
+	for name, directory := range fetcher.FileSystem.Directories {
+		directories[name] = directory
+	}
+	directories[directoryName] = FileSystem{Directories: oldDirectory.Directories, Files: files}
+
+	fetcher.FileSystem = FileSystem{Directories: directories, Files: fetcher.FileSystem.Files}
+}
@@ -0,0 +1,70 @@
+package file_fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileWriter(t *testing.T) {
+	directory := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(directory, "stored_requests"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(directory, "stored_requests", "b.json"), []byte(`{"id":"b"}`), 0644))
+
+	fetcher, err := NewFileFetcher(directory)
+	if !assert.NoError(t, err) {
+		return
+	}
+	writer := fetcher.(stored_requests.Writer)
+	ctx := context.Background()
+
+	// Maps handed out before a write must not change
+	requestsBefore, _, _ := fetcher.FetchRequests(ctx, []string{"b"}, nil)
+
+	assert.NoError(t, writer.Save(ctx, stored_requests.RequestData, "a", json.RawMessage(`{"id":"a"}`)))
+	assert.NoError(t, writer.Save(ctx, stored_requests.RequestData, "c", json.RawMessage(`{"id":"c"}`)))
+	assert.NoError(t, writer.Save(ctx, stored_requests.AccountData, "account", json.RawMessage(`{"id":"account"}`)))
+	assert.Len(t, requestsBefore, 1)
+
+	ids, err := writer.List(ctx, stored_requests.RequestData, 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids)
+	ids, err = writer.List(ctx, stored_requests.RequestData, 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, ids)
+	ids, err = writer.List(ctx, stored_requests.ImpData, 0, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	requests, _, errs := fetcher.FetchRequests(ctx, []string{"a"}, nil)
+	assert.Empty(t, errs)
+	assert.Equal(t, json.RawMessage(`{"id":"a"}`), requests["a"])
+	account, errs := fetcher.FetchAccount(ctx, nil, "account")
+	assert.Empty(t, errs)
+	assert.Equal(t, json.RawMessage(`{"id":"account"}`), account)
+
+	fileData, err := os.ReadFile(filepath.Join(directory, "stored_requests", "a.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"a"}`, string(fileData))
+
+	// The files are read back on the next start
+	reloaded, err := NewFileFetcher(directory)
+	assert.NoError(t, err)
+	data, err := reloaded.(stored_requests.Writer).Get(ctx, stored_requests.AccountData, "account")
+	assert.NoError(t, err)
+	assert.Equal(t, json.RawMessage(`{"id":"account"}`), data)
+
+	assert.NoError(t, writer.Delete(ctx, stored_requests.RequestData, "b"))
+	_, err = writer.Get(ctx, stored_requests.RequestData, "b")
+	assert.Equal(t, stored_requests.NotFoundError{ID: "b", DataType: "request"}, err)
+	_, err = os.Stat(filepath.Join(directory, "stored_requests", "b.json"))
+	assert.True(t, os.IsNotExist(err))
+	assert.NoError(t, writer.Delete(ctx, stored_requests.RequestData, "b"), "deleting a missing ID isn't an error")
+
+	assert.Error(t, writer.Save(ctx, stored_requests.RequestData, "../escape", json.RawMessage(`{}`)))
+}
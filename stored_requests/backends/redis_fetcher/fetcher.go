@@ -0,0 +1,193 @@
+package redis_fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/redis/go-redis/v9"
+	jsonpatch "gopkg.in/evanphx/json-patch.v5"
+)
+
+// NewFetcher returns a Fetcher which reads Stored data from Redis.
+//
+// Each piece of Stored data is expected to be a JSON string kept under a key made of
+// the configured prefix followed by its ID. With the default prefixes, this looks like:
+//
+//	stored_requests:{request_id}  -> { ... stored data for the request ... }
+//	stored_imps:{imp_id}          -> { ... stored data for the imp ... }
+//	stored_responses:{resp_id}    -> { ... stored data for the response ... }
+//	accounts:{account_id}         -> { ... config data for the account ... }
+//
+// Category mappings follow the file_fetcher layout, where each key holds the full
+// mapping for an ad server (and optionally a publisher):
+//
+//	categories:{primaryAdServer}_{publisherId} -> { "IAB1-1": { "id": "...", "name": "..." }, ... }
+//
+// Category mappings are cached after their first use. They are dropped from the cache
+// on keyspace notifications for their key when redis.events is enabled.
+//
+// All the IDs needed for a single call are looked up in one pipelined round trip.
+func NewFetcher(client redis.UniversalClient, prefixes config.RedisKeyPrefixes) stored_requests.AllFetcher {
+	if client == nil {
+		glog.Fatalf("The Redis Stored Request Fetcher requires a Redis client. Please report this as a bug.")
+	}
+	return &redisFetcher{
+		client:     client,
+		prefixes:   prefixes,
+		categories: make(map[string]map[string]stored_requests.Category),
+	}
+}
+
+// redisFetcher fetches Stored data from Redis. This should be instantiated through the NewFetcher() function.
+type redisFetcher struct {
+	client   redis.UniversalClient
+	prefixes config.RedisKeyPrefixes
+
+	categoriesMutex sync.RWMutex
+	categories      map[string]map[string]stored_requests.Category
+}
+
+func (fetcher *redisFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	if len(requestIDs) == 0 && len(impIDs) == 0 {
+		return nil, nil, nil
+	}
+
+	var requestCmd, impCmd *redis.SliceCmd
+	_, err := fetcher.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		if len(requestIDs) > 0 {
+			requestCmd = pipe.MGet(ctx, buildKeys(fetcher.prefixes.Requests, requestIDs)...)
+		}
+		if len(impIDs) > 0 {
+			impCmd = pipe.MGet(ctx, buildKeys(fetcher.prefixes.Imps, impIDs)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, []error{fmt.Errorf("Error fetching Stored Requests from Redis: %v", err)}
+	}
+
+	requestData, errs := unpackValues("Request", requestIDs, requestCmd, nil)
+	impData, errs := unpackValues("Imp", impIDs, impCmd, errs)
+	return requestData, impData, errs
+}
+
+func (fetcher *redisFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	cmd := fetcher.client.MGet(ctx, buildKeys(fetcher.prefixes.Responses, ids)...)
+	if err := cmd.Err(); err != nil {
+		return nil, []error{fmt.Errorf("Error fetching Stored Responses from Redis: %v", err)}
+	}
+	return unpackValues("Response", ids, cmd, nil)
+}
+
+// FetchAccount fetches the host account configuration for a publisher
+func (fetcher *redisFetcher) FetchAccount(ctx context.Context, accountDefaultsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	if len(accountID) == 0 {
+		return nil, []error{fmt.Errorf("Cannot look up an empty accountID")}
+	}
+
+	accountJSON, err := fetcher.client.Get(ctx, fetcher.prefixes.Accounts+accountID).Bytes()
+	if err == redis.Nil {
+		return nil, []error{stored_requests.NotFoundError{
+			ID:       accountID,
+			DataType: "Account",
+		}}
+	}
+	if err != nil {
+		return nil, []error{fmt.Errorf("Error fetching account %s from Redis: %v", accountID, err)}
+	}
+
+	if accountDefaultsJSON == nil {
+		return accountJSON, nil
+	}
+	completeJSON, err := jsonpatch.MergePatch(accountDefaultsJSON, accountJSON)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return completeJSON, nil
+}
+
+func (fetcher *redisFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
+	dataName := primaryAdServer
+	if len(publisherId) != 0 {
+		dataName = primaryAdServer + "_" + publisherId
+	}
+
+	fetcher.categoriesMutex.RLock()
+	data, ok := fetcher.categories[dataName]
+	fetcher.categoriesMutex.RUnlock()
+
+	if !ok {
+		categoriesJSON, err := fetcher.client.Get(ctx, fetcher.prefixes.Categories+dataName).Bytes()
+		if err == redis.Nil {
+			return "", fmt.Errorf("Unable to find mapping file for adserver: '%s', publisherId: '%s'", primaryAdServer, publisherId)
+		}
+		if err != nil {
+			return "", fmt.Errorf("Error fetching categories for adserver: '%s', publisherId: '%s' from Redis: %v", primaryAdServer, publisherId, err)
+		}
+
+		data = make(map[string]stored_requests.Category)
+		if err := jsonutil.UnmarshalValid(categoriesJSON, &data); err != nil {
+			return "", fmt.Errorf("Unable to unmarshal categories for adserver: '%s', publisherId: '%s'", primaryAdServer, publisherId)
+		}
+
+		fetcher.categoriesMutex.Lock()
+		fetcher.categories[dataName] = data
+		fetcher.categoriesMutex.Unlock()
+	}
+
+	if category, ok := data[iabCategory]; ok && len(category.Id) > 0 {
+		return category.Id, nil
+	}
+	return "", fmt.Errorf("Unable to find category for adserver '%s', publisherId: '%s', iab category: '%s'", primaryAdServer, publisherId, iabCategory)
+}
+
+// InvalidateCategories drops the cached category mappings with the given names,
+// so they are read again from Redis on their next use.
+func (fetcher *redisFetcher) InvalidateCategories(names ...string) {
+	fetcher.categoriesMutex.Lock()
+	defer fetcher.categoriesMutex.Unlock()
+	for _, name := range names {
+		delete(fetcher.categories, name)
+	}
+}
+
+func buildKeys(prefix string, ids []string) []string {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = prefix + id
+	}
+	return keys
+}
+
+// unpackValues maps the MGET results back onto their IDs, flagging every ID without a value as not found.
+func unpackValues(dataType string, ids []string, cmd *redis.SliceCmd, errs []error) (map[string]json.RawMessage, []error) {
+	data := make(map[string]json.RawMessage, len(ids))
+	if cmd == nil {
+		return data, errs
+	}
+
+	for i, value := range cmd.Val() {
+		if i >= len(ids) {
+			break
+		}
+		if str, ok := value.(string); ok {
+			data[ids[i]] = json.RawMessage(str)
+			continue
+		}
+		errs = append(errs, stored_requests.NotFoundError{
+			ID:       ids[i],
+			DataType: dataType,
+		})
+	}
+	return data, errs
+}
@@ -0,0 +1,199 @@
+package redis_fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/stored_requests"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPrefixes = config.RedisKeyPrefixes{
+	Requests:   "stored_requests:",
+	Imps:       "stored_imps:",
+	Responses:  "stored_responses:",
+	Accounts:   "accounts:",
+	Categories: "categories:",
+}
+
+func TestFetchRequests(t *testing.T) {
+	server, fetcher := newTestFetcher(t)
+	server.Set("stored_requests:req-1", `{"id":"req-1"}`)
+	server.Set("stored_imps:imp-1", `{"id":"imp-1"}`)
+	server.Set("stored_imps:imp-2", `{"id":"imp-2"}`)
+
+	testCases := []struct {
+		description      string
+		requestIDs       []string
+		impIDs           []string
+		expectedRequests map[string]json.RawMessage
+		expectedImps     map[string]json.RawMessage
+		expectedErrs     []error
+	}{
+		{
+			description: "no-ids",
+		},
+		{
+			description:      "requests-and-imps",
+			requestIDs:       []string{"req-1"},
+			impIDs:           []string{"imp-1", "imp-2"},
+			expectedRequests: map[string]json.RawMessage{"req-1": json.RawMessage(`{"id":"req-1"}`)},
+			expectedImps: map[string]json.RawMessage{
+				"imp-1": json.RawMessage(`{"id":"imp-1"}`),
+				"imp-2": json.RawMessage(`{"id":"imp-2"}`),
+			},
+		},
+		{
+			description:      "imps-only",
+			impIDs:           []string{"imp-2"},
+			expectedRequests: map[string]json.RawMessage{},
+			expectedImps:     map[string]json.RawMessage{"imp-2": json.RawMessage(`{"id":"imp-2"}`)},
+		},
+		{
+			description:      "missing-ids",
+			requestIDs:       []string{"req-1", "req-missing"},
+			impIDs:           []string{"imp-missing"},
+			expectedRequests: map[string]json.RawMessage{"req-1": json.RawMessage(`{"id":"req-1"}`)},
+			expectedImps:     map[string]json.RawMessage{},
+			expectedErrs: []error{
+				stored_requests.NotFoundError{ID: "req-missing", DataType: "Request"},
+				stored_requests.NotFoundError{ID: "imp-missing", DataType: "Imp"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			requests, imps, errs := fetcher.FetchRequests(context.Background(), test.requestIDs, test.impIDs)
+			assert.Equal(t, test.expectedRequests, requests)
+			assert.Equal(t, test.expectedImps, imps)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
+
+func TestFetchRequestsServerDown(t *testing.T) {
+	server, fetcher := newTestFetcher(t)
+	server.Close()
+
+	requests, imps, errs := fetcher.FetchRequests(context.Background(), []string{"req-1"}, nil)
+	assert.Nil(t, requests)
+	assert.Nil(t, imps)
+	assert.Len(t, errs, 1)
+}
+
+func TestFetchResponses(t *testing.T) {
+	server, fetcher := newTestFetcher(t)
+	server.Set("stored_responses:resp-1", `{"seatbid":[]}`)
+
+	data, errs := fetcher.FetchResponses(context.Background(), []string{"resp-1", "resp-2"})
+	assert.Equal(t, map[string]json.RawMessage{"resp-1": json.RawMessage(`{"seatbid":[]}`)}, data)
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "resp-2", DataType: "Response"}}, errs)
+
+	data, errs = fetcher.FetchResponses(context.Background(), nil)
+	assert.Nil(t, data)
+	assert.Nil(t, errs)
+}
+
+func TestFetchAccount(t *testing.T) {
+	server, fetcher := newTestFetcher(t)
+	server.Set("accounts:valid", `{"disabled":false,"id":"valid"}`)
+
+	testCases := []struct {
+		description     string
+		accountDefaults json.RawMessage
+		accountID       string
+		expectedJSON    string
+		expectedErrs    []error
+	}{
+		{
+			description:  "no-defaults",
+			accountID:    "valid",
+			expectedJSON: `{"disabled":false,"id":"valid"}`,
+		},
+		{
+			description:     "merged-with-defaults",
+			accountDefaults: json.RawMessage(`{"disabled":true,"price_granularity":"low"}`),
+			accountID:       "valid",
+			expectedJSON:    `{"disabled":false,"id":"valid","price_granularity":"low"}`,
+		},
+		{
+			description:  "not-found",
+			accountID:    "missing",
+			expectedErrs: []error{stored_requests.NotFoundError{ID: "missing", DataType: "Account"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			account, errs := fetcher.FetchAccount(context.Background(), test.accountDefaults, test.accountID)
+			assert.Equal(t, test.expectedErrs, errs)
+			if test.expectedJSON != "" {
+				assert.JSONEq(t, test.expectedJSON, string(account))
+			} else {
+				assert.Nil(t, account)
+			}
+		})
+	}
+
+	_, errs := fetcher.FetchAccount(context.Background(), nil, "")
+	assert.Len(t, errs, 1)
+}
+
+func TestFetchCategories(t *testing.T) {
+	server, fetcher := newTestFetcher(t)
+	server.Set("categories:freewheel", `{"IAB1-1":{"id":"Beverages","name":"Beverages"}}`)
+	server.Set("categories:freewheel_pub1", `{"IAB1-1":{"id":"PubBeverages","name":"Beverages"}}`)
+	server.Set("categories:broken", `{"IAB1-1":`)
+
+	category, err := fetcher.FetchCategories(context.Background(), "freewheel", "", "IAB1-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Beverages", category)
+
+	category, err = fetcher.FetchCategories(context.Background(), "freewheel", "pub1", "IAB1-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "PubBeverages", category)
+
+	// Mappings are kept in memory once read.
+	server.Del("categories:freewheel")
+	category, err = fetcher.FetchCategories(context.Background(), "freewheel", "", "IAB1-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Beverages", category)
+
+	_, err = fetcher.FetchCategories(context.Background(), "freewheel", "", "IAB2-2")
+	assert.EqualError(t, err, "Unable to find category for adserver 'freewheel', publisherId: '', iab category: 'IAB2-2'")
+
+	_, err = fetcher.FetchCategories(context.Background(), "dfp", "", "IAB1-1")
+	assert.EqualError(t, err, "Unable to find mapping file for adserver: 'dfp', publisherId: ''")
+
+	_, err = fetcher.FetchCategories(context.Background(), "broken", "", "IAB1-1")
+	assert.EqualError(t, err, "Unable to unmarshal categories for adserver: 'broken', publisherId: ''")
+}
+
+func TestInvalidateCategories(t *testing.T) {
+	server, fetcher := newTestFetcher(t)
+	server.Set("categories:freewheel", `{"IAB1-1":{"id":"Beverages","name":"Beverages"}}`)
+
+	category, err := fetcher.FetchCategories(context.Background(), "freewheel", "", "IAB1-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Beverages", category)
+
+	server.Set("categories:freewheel", `{"IAB1-1":{"id":"Drinks","name":"Drinks"}}`)
+	fetcher.(*redisFetcher).InvalidateCategories("freewheel")
+
+	category, err = fetcher.FetchCategories(context.Background(), "freewheel", "", "IAB1-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Drinks", category)
+}
+
+func newTestFetcher(t *testing.T) (*miniredis.Miniredis, stored_requests.AllFetcher) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return server, NewFetcher(client, testPrefixes)
+}
@@ -16,26 +16,30 @@ import (
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/file_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/http_fetcher"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/redis_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/caches/memory"
 	"github.com/prebid/prebid-server/v3/stored_requests/caches/nil_cache"
 	"github.com/prebid/prebid-server/v3/stored_requests/events"
 	apiEvents "github.com/prebid/prebid-server/v3/stored_requests/events/api"
 	databaseEvents "github.com/prebid/prebid-server/v3/stored_requests/events/database"
 	httpEvents "github.com/prebid/prebid-server/v3/stored_requests/events/http"
+	redisEvents "github.com/prebid/prebid-server/v3/stored_requests/events/redis"
 	"github.com/prebid/prebid-server/v3/util/task"
+	"github.com/redis/go-redis/v9"
 )
 
 // CreateStoredRequests returns three things:
 //
 // 1. A Fetcher which can be used to get Stored Requests
 // 2. A function which should be called on shutdown for graceful cleanups.
+// 3. A Writer which can be used to save Stored Requests, or nil if no configured backend supports writes.
 //
 // If any errors occur, the program will exit with an error message.
 // It probably means you have a bad config or networking issue.
 //
 // As a side-effect, it will add some endpoints to the router if the config calls for it.
 // In the future we should look for ways to simplify this so that it's not doing two things.
-func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, provider db_provider.DbProvider) (fetcher stored_requests.AllFetcher, shutdown func()) {
+func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, provider db_provider.DbProvider) (fetcher stored_requests.AllFetcher, shutdown func(), writer stored_requests.Writer) {
 	// Create database connection if given options for one
 	if cfg.Database.ConnectionInfo.Database != "" {
 		if provider == nil {
@@ -55,14 +59,24 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 		}
 	}
 
-	eventProducers := newEventProducers(cfg, client, provider, metricsEngine, router)
-	fetcher = newFetcher(cfg, client, provider)
+	var redisClient redis.UniversalClient
+	if cfg.Redis.Enabled {
+		glog.Infof("Connecting to Redis for Stored %s. address=%s, db=%d", cfg.DataType(), cfg.Redis.Address, cfg.Redis.Database)
+		redisClient = newRedisClient(cfg.Redis)
+	}
+
+	fetcher = newFetcher(cfg, client, provider, redisClient)
+	eventProducers := newEventProducers(cfg, client, provider, redisClient, fetcher, metricsEngine, router)
+	writer = newWriter(cfg, fetcher, provider)
 
 	var shutdown1 func()
 
 	if cfg.InMemoryCache.Type != "" {
 		cache := newCache(cfg)
 		fetcher = stored_requests.WithCache(fetcher, cache, metricsEngine)
+		if writer != nil {
+			writer = stored_requests.WriterWithCache(writer, cache)
+		}
 		shutdown1 = addListeners(cache, eventProducers)
 	}
 
@@ -71,6 +85,20 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 			shutdown1()
 		}
 
+		for _, ep := range eventProducers {
+			if redisEP, ok := ep.(*redisEvents.RedisEvents); ok {
+				if err := redisEP.Stop(); err != nil {
+					glog.Errorf("Error closing Redis keyspace subscription: %v", err)
+				}
+			}
+		}
+
+		if redisClient != nil {
+			if err := redisClient.Close(); err != nil {
+				glog.Errorf("Error closing Redis connection: %v", err)
+			}
+		}
+
 		if provider == nil {
 			return
 		}
@@ -91,6 +119,10 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 // 4. A Fetcher which can be used to get Account data
 // 5. A Fetcher which can be used to get Category Mapping data
 // 6. A Fetcher which can be used to get Stored Requests for /openrtb2/video
+// 7. A Fetcher which can be used to get Stored Responses
+// 8. A Writer which can be used to save Stored Requests, Imps and Accounts through the admin API
+//
+// The /openrtb2/amp and /openrtb2/video fetchers don't see the writes until they're refreshed.
 //
 // If any errors occur, the program will exit with an error message.
 // It probably means you have a bad config or networking issue.
@@ -103,16 +135,17 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 	accountsFetcher stored_requests.AccountFetcher,
 	categoriesFetcher stored_requests.CategoryFetcher,
 	videoFetcher stored_requests.Fetcher,
-	storedRespFetcher stored_requests.Fetcher) {
+	storedRespFetcher stored_requests.Fetcher,
+	writer stored_requests.Writer) {
 
 	var provider db_provider.DbProvider
 
-	fetcher1, shutdown1 := CreateStoredRequests(&cfg.StoredRequests, metricsEngine, client, router, provider)
-	fetcher2, shutdown2 := CreateStoredRequests(&cfg.StoredRequestsAMP, metricsEngine, client, router, provider)
-	fetcher3, shutdown3 := CreateStoredRequests(&cfg.CategoryMapping, metricsEngine, client, router, provider)
-	fetcher4, shutdown4 := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, provider)
-	fetcher5, shutdown5 := CreateStoredRequests(&cfg.Accounts, metricsEngine, client, router, provider)
-	fetcher6, shutdown6 := CreateStoredRequests(&cfg.StoredResponses, metricsEngine, client, router, provider)
+	fetcher1, shutdown1, writer1 := CreateStoredRequests(&cfg.StoredRequests, metricsEngine, client, router, provider)
+	fetcher2, shutdown2, _ := CreateStoredRequests(&cfg.StoredRequestsAMP, metricsEngine, client, router, provider)
+	fetcher3, shutdown3, _ := CreateStoredRequests(&cfg.CategoryMapping, metricsEngine, client, router, provider)
+	fetcher4, shutdown4, _ := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, provider)
+	fetcher5, shutdown5, writer5 := CreateStoredRequests(&cfg.Accounts, metricsEngine, client, router, provider)
+	fetcher6, shutdown6, _ := CreateStoredRequests(&cfg.StoredResponses, metricsEngine, client, router, provider)
 
 	fetcher = fetcher1.(stored_requests.Fetcher)
 	ampFetcher = fetcher2.(stored_requests.Fetcher)
@@ -121,6 +154,16 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 	accountsFetcher = fetcher5.(stored_requests.AccountFetcher)
 	storedRespFetcher = fetcher6.(stored_requests.Fetcher)
 
+	writers := stored_requests.WritersByDataType{}
+	if writer1 != nil {
+		writers[stored_requests.RequestData] = writer1
+		writers[stored_requests.ImpData] = writer1
+	}
+	if writer5 != nil {
+		writers[stored_requests.AccountData] = writer5
+	}
+	writer = writers
+
 	shutdown = func() {
 		shutdown1()
 		shutdown2()
@@ -149,7 +192,7 @@ func addListeners(cache stored_requests.Cache, eventProducers []events.EventProd
 	}
 }
 
-func newFetcher(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider) (fetcher stored_requests.AllFetcher) {
+func newFetcher(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, redisClient redis.UniversalClient) (fetcher stored_requests.AllFetcher) {
 	idList := make(stored_requests.MultiFetcher, 0, 3)
 
 	if cfg.Files.Enabled {
@@ -168,11 +211,36 @@ func newFetcher(cfg *config.StoredRequests, client *http.Client, provider db_pro
 		glog.Infof("Loading Stored %s data via HTTP. endpoint=%s", cfg.DataType(), cfg.HTTP.Endpoint)
 		idList = append(idList, http_fetcher.NewFetcher(client, cfg.HTTP.Endpoint))
 	}
+	if redisClient != nil {
+		glog.Infof("Loading Stored %s data via Redis. address=%s", cfg.DataType(), cfg.Redis.Address)
+		idList = append(idList, redis_fetcher.NewFetcher(redisClient, cfg.Redis.KeyPrefixes))
+	}
 
 	fetcher = consolidate(cfg.DataType(), idList)
 	return
 }
 
+// newWriter returns the Writer for the section's stored data, or nil if none of its backends can be written to.
+// The database is written to if it has writer queries. Otherwise, the files are.
+func newWriter(cfg *config.StoredRequests, fetcher stored_requests.AllFetcher, provider db_provider.DbProvider) stored_requests.Writer {
+	if cfg.Database.WriterQueries.Enabled() {
+		glog.Infof("Saving Stored %s data via Database.", cfg.DataType())
+		dbFetcher := db_fetcher.NewFetcher(provider, cfg.Database.FetcherQueries.QueryTemplate, cfg.Database.FetcherQueries.QueryTemplate)
+		return db_fetcher.NewWriter(provider, dbFetcher, cfg.Database.WriterQueries)
+	}
+
+	fetchers := []stored_requests.AllFetcher{fetcher}
+	if multiFetcher, ok := fetcher.(stored_requests.MultiFetcher); ok {
+		fetchers = multiFetcher
+	}
+	for _, f := range fetchers {
+		if writer, ok := f.(stored_requests.Writer); ok {
+			return writer
+		}
+	}
+	return nil
+}
+
 func newCache(cfg *config.StoredRequests) stored_requests.Cache {
 	cache := stored_requests.Cache{
 		Requests:  &nil_cache.NilCache{},
@@ -193,7 +261,7 @@ func newCache(cfg *config.StoredRequests) stored_requests.Cache {
 	return cache
 }
 
-func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, metricsEngine metrics.MetricsEngine, router *httprouter.Router) (eventProducers []events.EventProducer) {
+func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, redisClient redis.UniversalClient, fetcher stored_requests.AllFetcher, metricsEngine metrics.MetricsEngine, router *httprouter.Router) (eventProducers []events.EventProducer) {
 	if cfg.CacheEvents.Enabled {
 		eventProducers = append(eventProducers, newEventsAPI(router, cfg.CacheEvents.Endpoint))
 	}
@@ -216,9 +284,26 @@ func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider
 		dbEventTickerTask.Start()
 		eventProducers = append(eventProducers, dbEventProducer)
 	}
+	if redisClient != nil && cfg.Redis.Events.Enabled {
+		eventProducers = append(eventProducers, redisEvents.NewRedisEvents(redisClient, cfg.Redis.Database, cfg.Redis.KeyPrefixes, cfg.Redis.TimeoutDuration(), findCategoriesInvalidator(fetcher)))
+	}
 	return
 }
 
+// findCategoriesInvalidator returns the fetcher caching category mappings, or nil if there is none.
+func findCategoriesInvalidator(fetcher stored_requests.AllFetcher) redisEvents.CategoriesInvalidator {
+	fetchers := []stored_requests.AllFetcher{fetcher}
+	if multiFetcher, ok := fetcher.(stored_requests.MultiFetcher); ok {
+		fetchers = multiFetcher
+	}
+	for _, f := range fetchers {
+		if invalidator, ok := f.(redisEvents.CategoriesInvalidator); ok {
+			return invalidator
+		}
+	}
+	return nil
+}
+
 func newEventsAPI(router *httprouter.Router, endpoint string) events.EventProducer {
 	producer, handler := apiEvents.NewEventsAPI()
 	router.POST(endpoint, handler)
@@ -233,6 +318,18 @@ func newHttpEvents(client *http.Client, timeout time.Duration, refreshRate time.
 	return httpEvents.NewHTTPEvents(client, endpoint, ctxProducer, refreshRate)
 }
 
+func newRedisClient(cfg config.RedisConfig) redis.UniversalClient {
+	return redis.NewClient(&redis.Options{
+		Addr:         cfg.Address,
+		Username:     cfg.Username,
+		Password:     cfg.Password,
+		DB:           cfg.Database,
+		DialTimeout:  cfg.TimeoutDuration(),
+		ReadTimeout:  cfg.TimeoutDuration(),
+		WriteTimeout: cfg.TimeoutDuration(),
+	})
+}
+
 func newFilesystem(dataType config.DataType, configPath string) stored_requests.AllFetcher {
 	glog.Infof("Loading Stored %s data from filesystem at path %s", dataType, configPath)
 	fetcher, err := file_fetcher.NewFileFetcher(configPath)
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/metrics"
@@ -19,8 +20,10 @@ import (
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/db_provider"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/backends/http_fetcher"
+	"github.com/prebid/prebid-server/v3/stored_requests/backends/redis_fetcher"
 	"github.com/prebid/prebid-server/v3/stored_requests/events"
 	httpEvents "github.com/prebid/prebid-server/v3/stored_requests/events/http"
+	redisEvents "github.com/prebid/prebid-server/v3/stored_requests/events/redis"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -117,7 +120,7 @@ func TestNewEmptyFetcher(t *testing.T) {
 	}
 
 	for _, test := range testCases {
-		fetcher := newFetcher(test.config, nil, db_provider.DbProviderMock{})
+		fetcher := newFetcher(test.config, nil, db_provider.DbProviderMock{}, nil)
 		assert.NotNil(t, fetcher, "The fetcher should be non-nil.")
 		if test.emptyFetcher {
 			assert.Equal(t, empty_fetcher.EmptyFetcher{}, fetcher, "Empty fetcher should be returned")
@@ -132,7 +135,7 @@ func TestNewHTTPFetcher(t *testing.T) {
 		HTTP: config.HTTPFetcherConfig{
 			Endpoint: "stored-requests.prebid.com",
 		},
-	}, nil, nil)
+	}, nil, nil, nil)
 	if httpFetcher, ok := fetcher.(*http_fetcher.HttpFetcher); ok {
 		if httpFetcher.Endpoint != "stored-requests.prebid.com?" {
 			t.Errorf("The HTTP fetcher is using the wrong endpoint. Expected %s, got %s", "stored-requests.prebid.com?", httpFetcher.Endpoint)
@@ -142,6 +145,74 @@ func TestNewHTTPFetcher(t *testing.T) {
 	}
 }
 
+func TestNewWriter(t *testing.T) {
+	filesConfig := &config.StoredRequests{
+		Files: config.FileFetcherConfig{Enabled: true, Path: "../backends/file_fetcher/test"},
+		HTTP:  config.HTTPFetcherConfig{Endpoint: "stored-requests.prebid.com"},
+	}
+	fetcher := newFetcher(filesConfig, nil, nil, nil)
+	assert.IsType(t, stored_requests.MultiFetcher{}, fetcher)
+	assert.Same(t, fetcher.(stored_requests.MultiFetcher)[0], newWriter(filesConfig, fetcher, nil), "the files should be written to")
+
+	httpConfig := &config.StoredRequests{HTTP: config.HTTPFetcherConfig{Endpoint: "stored-requests.prebid.com"}}
+	assert.Nil(t, newWriter(httpConfig, newFetcher(httpConfig, nil, nil, nil), nil), "the HTTP backend can't be written to")
+
+	dbConfig := &config.StoredRequests{
+		Files: config.FileFetcherConfig{Enabled: true, Path: "../backends/file_fetcher/test"},
+		Database: config.DatabaseConfig{
+			FetcherQueries: config.DatabaseFetcherQueries{QueryTemplate: "SELECT id, data, type FROM stored_data WHERE id in $REQUEST_ID_LIST"},
+			WriterQueries:  config.DatabaseWriterQueries{ListQuery: "list", UpsertQuery: "upsert", DeleteQuery: "delete"},
+		},
+	}
+	writer := newWriter(dbConfig, newFetcher(dbConfig, nil, db_provider.DbProviderMock{}, nil), db_provider.DbProviderMock{})
+	_, isFileWriter := writer.(stored_requests.AllFetcher)
+	assert.NotNil(t, writer)
+	assert.False(t, isFileWriter, "the database should be written to")
+}
+
+func TestNewRedisFetcher(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.Set("stored_requests:1", `{"id":"1"}`)
+
+	cfg := &config.StoredRequests{
+		Redis: config.RedisConfig{
+			Enabled:     true,
+			Address:     server.Addr(),
+			Timeout:     100,
+			KeyPrefixes: config.RedisKeyPrefixes{Requests: "stored_requests:"},
+		},
+	}
+	redisClient := newRedisClient(cfg.Redis)
+	defer redisClient.Close()
+
+	fetcher := newFetcher(cfg, nil, nil, redisClient)
+	requestData, _, errs := fetcher.FetchRequests(context.Background(), []string{"1"}, nil)
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"id":"1"}`, string(requestData["1"]))
+	assert.IsType(t, redis_fetcher.NewFetcher(redisClient, cfg.Redis.KeyPrefixes), fetcher)
+}
+
+func TestNewRedisEventProducers(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	cfg := &config.StoredRequests{
+		Redis: config.RedisConfig{
+			Enabled: true,
+			Address: server.Addr(),
+			Timeout: 100,
+			Events:  config.RedisEventsConfig{Enabled: true},
+		},
+	}
+	redisClient := newRedisClient(cfg.Redis)
+	defer redisClient.Close()
+
+	evProducers := newEventProducers(cfg, nil, nil, redisClient, nil, &metrics.MetricsEngineMock{}, nil)
+	assertProducerLength(t, evProducers, 1)
+	if redisEP, ok := evProducers[0].(*redisEvents.RedisEvents); assert.True(t, ok, "Expected a RedisEvents producer") {
+		assert.NoError(t, redisEP.Stop())
+	}
+}
+
 func TestNewHTTPEvents(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -158,7 +229,7 @@ func TestNewHTTPEvents(t *testing.T) {
 
 	metricsMock := &metrics.MetricsEngineMock{}
 
-	evProducers := newEventProducers(cfg, server1.Client(), nil, metricsMock, nil)
+	evProducers := newEventProducers(cfg, server1.Client(), nil, nil, nil, metricsMock, nil)
 	assertSliceLength(t, evProducers, 1)
 	assertHttpWithURL(t, evProducers[0], server1.URL)
 }
@@ -224,7 +295,7 @@ func TestNewDatabaseEventProducers(t *testing.T) {
 	}
 	mock.ExpectQuery("^" + regexp.QuoteMeta(cfg.Database.CacheInitialization.Query) + "$").WillReturnError(errors.New("Query failed"))
 
-	evProducers := newEventProducers(cfg, client, provider, metricsMock, nil)
+	evProducers := newEventProducers(cfg, client, provider, nil, nil, metricsMock, nil)
 	assertProducerLength(t, evProducers, 1)
 
 	assertExpectationsMet(t, mock)
@@ -0,0 +1,204 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/stored_requests/events"
+	"github.com/redis/go-redis/v9"
+)
+
+// keyspaceChannelPrefix is the pub/sub channel Redis publishes keyspace notifications on, without the database number.
+const keyspaceChannelPrefix = "__keyspace@"
+
+// NewRedisEvents makes an EventProducer which creates events from Redis keyspace notifications.
+//
+// It subscribes to the notifications for every key starting with one of the configured prefixes.
+// Whenever a key is written, its new value is read back and published as a Save.
+// Whenever a key is deleted, expired or evicted, its ID is published as an Invalidation.
+// Category mappings aren't kept in the Stored data caches, so any change to a categories key
+// drops that mapping from the categories cache instead, if one is given.
+//
+// Keyspace notifications are disabled by default on Redis servers. They must be turned on for
+// keyspace events of generic and string commands, expired and evicted keys included, for this to work:
+//
+//	CONFIG SET notify-keyspace-events K$gxe
+func NewRedisEvents(client redis.UniversalClient, database int, prefixes config.RedisKeyPrefixes, timeout time.Duration, categories CategoriesInvalidator) *RedisEvents {
+	e := &RedisEvents{
+		client:        client,
+		prefixes:      prefixes,
+		timeout:       timeout,
+		categories:    categories,
+		saves:         make(chan events.Save, 1),
+		invalidations: make(chan events.Invalidation, 1),
+	}
+
+	patterns := make([]string, 0, 5)
+	for _, prefix := range []string{prefixes.Requests, prefixes.Imps, prefixes.Responses, prefixes.Accounts, prefixes.Categories} {
+		if prefix != "" {
+			patterns = append(patterns, fmt.Sprintf("%s%d__:%s*", keyspaceChannelPrefix, database, prefix))
+		}
+	}
+
+	glog.Infof("Subscribing to Redis keyspace notifications for %v", patterns)
+	e.pubsub = client.PSubscribe(context.Background(), patterns...)
+	go e.listen(e.pubsub.Channel())
+	return e
+}
+
+// CategoriesInvalidator drops cached category mappings, such as the ones kept by the Redis fetcher.
+type CategoriesInvalidator interface {
+	// InvalidateCategories drops the mappings of the given names, made of the ad server and optional publisher ID.
+	InvalidateCategories(names ...string)
+}
+
+type RedisEvents struct {
+	client        redis.UniversalClient
+	prefixes      config.RedisKeyPrefixes
+	timeout       time.Duration
+	categories    CategoriesInvalidator
+	pubsub        *redis.PubSub
+	saves         chan events.Save
+	invalidations chan events.Invalidation
+}
+
+func (e *RedisEvents) Saves() <-chan events.Save {
+	return e.saves
+}
+
+func (e *RedisEvents) Invalidations() <-chan events.Invalidation {
+	return e.invalidations
+}
+
+// Stop unsubscribes from the keyspace notifications. No more events will be produced after it returns.
+func (e *RedisEvents) Stop() error {
+	return e.pubsub.Close()
+}
+
+func (e *RedisEvents) listen(messages <-chan *redis.Message) {
+	for msg := range messages {
+		key := keyFromChannel(msg.Channel)
+		if key == "" {
+			continue
+		}
+
+		if id, target := e.classify(key); target == targetCategories {
+			if e.categories != nil {
+				e.categories.InvalidateCategories(id)
+			}
+			continue
+		}
+
+		switch msg.Payload {
+		case "set", "setrange", "append", "rename_to", "restore", "copy_to":
+			e.save(key)
+		case "del", "unlink", "expired", "evicted", "rename_from":
+			e.invalidate(key)
+		}
+	}
+}
+
+func (e *RedisEvents) save(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	value, err := e.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		// The key was removed before we got to it. A matching "del" notification will follow.
+		return
+	}
+	if err != nil {
+		glog.Errorf("Failed to read Redis key %s after keyspace notification: %v", key, err)
+		return
+	}
+
+	save := events.Save{}
+	data := map[string]json.RawMessage{}
+	switch id, target := e.classify(key); target {
+	case targetRequests:
+		data[id] = value
+		save.Requests = data
+	case targetImps:
+		data[id] = value
+		save.Imps = data
+	case targetResponses:
+		data[id] = value
+		save.Responses = data
+	case targetAccounts:
+		data[id] = value
+		save.Accounts = data
+	default:
+		return
+	}
+	e.saves <- save
+}
+
+func (e *RedisEvents) invalidate(key string) {
+	invalidation := events.Invalidation{}
+	switch id, target := e.classify(key); target {
+	case targetRequests:
+		invalidation.Requests = []string{id}
+	case targetImps:
+		invalidation.Imps = []string{id}
+	case targetResponses:
+		invalidation.Responses = []string{id}
+	case targetAccounts:
+		invalidation.Accounts = []string{id}
+	default:
+		return
+	}
+	e.invalidations <- invalidation
+}
+
+type target int
+
+const (
+	targetNone target = iota
+	targetRequests
+	targetImps
+	targetResponses
+	targetAccounts
+	targetCategories
+)
+
+// classify finds the type of Stored data held by key, preferring the longest matching prefix.
+func (e *RedisEvents) classify(key string) (string, target) {
+	bestPrefix := ""
+	bestTarget := targetNone
+	candidates := []struct {
+		prefix string
+		target target
+	}{
+		{e.prefixes.Requests, targetRequests},
+		{e.prefixes.Imps, targetImps},
+		{e.prefixes.Responses, targetResponses},
+		{e.prefixes.Accounts, targetAccounts},
+		{e.prefixes.Categories, targetCategories},
+	}
+	for _, c := range candidates {
+		if c.prefix != "" && strings.HasPrefix(key, c.prefix) && len(c.prefix) > len(bestPrefix) {
+			bestPrefix = c.prefix
+			bestTarget = c.target
+		}
+	}
+	if bestTarget == targetNone {
+		return "", targetNone
+	}
+	return strings.TrimPrefix(key, bestPrefix), bestTarget
+}
+
+// keyFromChannel extracts the key from a channel named like "__keyspace@0__:some:key".
+func keyFromChannel(channel string) string {
+	if !strings.HasPrefix(channel, keyspaceChannelPrefix) {
+		return ""
+	}
+	if i := strings.Index(channel, "__:"); i >= 0 {
+		return channel[i+3:]
+	}
+	return ""
+}
@@ -0,0 +1,213 @@
+package redis
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/stored_requests/events"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPrefixes = config.RedisKeyPrefixes{
+	Requests:  "stored_requests:",
+	Imps:      "stored_imps:",
+	Responses: "stored_responses:",
+	Accounts:  "accounts:",
+}
+
+type fakeCategoriesInvalidator struct {
+	names chan string
+}
+
+func (f fakeCategoriesInvalidator) InvalidateCategories(names ...string) {
+	for _, name := range names {
+		f.names <- name
+	}
+}
+
+func TestSaves(t *testing.T) {
+	testCases := []struct {
+		description  string
+		key          string
+		value        string
+		expectedSave events.Save
+	}{
+		{
+			description:  "request",
+			key:          "stored_requests:req-1",
+			value:        `{"id":"req-1"}`,
+			expectedSave: events.Save{Requests: map[string]json.RawMessage{"req-1": json.RawMessage(`{"id":"req-1"}`)}},
+		},
+		{
+			description:  "imp",
+			key:          "stored_imps:imp-1",
+			value:        `{"id":"imp-1"}`,
+			expectedSave: events.Save{Imps: map[string]json.RawMessage{"imp-1": json.RawMessage(`{"id":"imp-1"}`)}},
+		},
+		{
+			description:  "response",
+			key:          "stored_responses:resp-1",
+			value:        `{"seatbid":[]}`,
+			expectedSave: events.Save{Responses: map[string]json.RawMessage{"resp-1": json.RawMessage(`{"seatbid":[]}`)}},
+		},
+		{
+			description:  "account",
+			key:          "accounts:acc-1",
+			value:        `{"id":"acc-1"}`,
+			expectedSave: events.Save{Accounts: map[string]json.RawMessage{"acc-1": json.RawMessage(`{"id":"acc-1"}`)}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			server, ev := newTestEvents(t)
+			server.Set(test.key, test.value)
+			server.Publish("__keyspace@0__:"+test.key, "set")
+
+			select {
+			case save := <-ev.Saves():
+				assert.Equal(t, test.expectedSave, save)
+			case <-time.After(time.Second):
+				t.Fatal("Timed out waiting for a save")
+			}
+		})
+	}
+}
+
+func TestInvalidations(t *testing.T) {
+	testCases := []struct {
+		description          string
+		key                  string
+		event                string
+		expectedInvalidation events.Invalidation
+	}{
+		{
+			description:          "request-deleted",
+			key:                  "stored_requests:req-1",
+			event:                "del",
+			expectedInvalidation: events.Invalidation{Requests: []string{"req-1"}},
+		},
+		{
+			description:          "imp-expired",
+			key:                  "stored_imps:imp-1",
+			event:                "expired",
+			expectedInvalidation: events.Invalidation{Imps: []string{"imp-1"}},
+		},
+		{
+			description:          "response-unlinked",
+			key:                  "stored_responses:resp-1",
+			event:                "unlink",
+			expectedInvalidation: events.Invalidation{Responses: []string{"resp-1"}},
+		},
+		{
+			description:          "account-evicted",
+			key:                  "accounts:acc-1",
+			event:                "evicted",
+			expectedInvalidation: events.Invalidation{Accounts: []string{"acc-1"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			server, ev := newTestEvents(t)
+			server.Publish("__keyspace@0__:"+test.key, test.event)
+
+			select {
+			case invalidation := <-ev.Invalidations():
+				assert.Equal(t, test.expectedInvalidation, invalidation)
+			case <-time.After(time.Second):
+				t.Fatal("Timed out waiting for an invalidation")
+			}
+		})
+	}
+}
+
+func TestIgnoredNotifications(t *testing.T) {
+	server, ev := newTestEvents(t)
+
+	// A set notification for a key which no longer exists, and an unrelated command.
+	server.Publish("__keyspace@0__:stored_requests:gone", "set")
+	server.Publish("__keyspace@0__:stored_requests:req-1", "expire")
+	// Only this one should produce an event.
+	server.Publish("__keyspace@0__:stored_imps:imp-1", "del")
+
+	select {
+	case invalidation := <-ev.Invalidations():
+		assert.Equal(t, events.Invalidation{Imps: []string{"imp-1"}}, invalidation)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for an invalidation")
+	}
+	assert.Empty(t, ev.Saves())
+}
+
+func TestCategoriesInvalidation(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	invalidator := fakeCategoriesInvalidator{names: make(chan string, 2)}
+	ev := NewRedisEvents(client, 0, config.RedisKeyPrefixes{Categories: "categories:"}, time.Second, invalidator)
+	t.Cleanup(func() {
+		ev.Stop()
+		client.Close()
+	})
+	require.Eventually(t, func() bool {
+		return server.PubSubNumPat() > 0
+	}, time.Second, 10*time.Millisecond)
+
+	server.Set("categories:freewheel_pub1", `{}`)
+	server.Publish("__keyspace@0__:categories:freewheel_pub1", "set")
+	server.Publish("__keyspace@0__:categories:freewheel", "del")
+
+	for _, expected := range []string{"freewheel_pub1", "freewheel"} {
+		select {
+		case name := <-invalidator.names:
+			assert.Equal(t, expected, name)
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for a categories invalidation")
+		}
+	}
+	assert.Empty(t, ev.Saves())
+	assert.Empty(t, ev.Invalidations())
+}
+
+func TestClassify(t *testing.T) {
+	ev := &RedisEvents{prefixes: config.RedisKeyPrefixes{Requests: "sr:", Imps: "sr:imp:"}}
+
+	id, target := ev.classify("sr:imp:1")
+	assert.Equal(t, "1", id)
+	assert.Equal(t, targetImps, target)
+
+	id, target = ev.classify("sr:1")
+	assert.Equal(t, "1", id)
+	assert.Equal(t, targetRequests, target)
+
+	_, target = ev.classify("other:1")
+	assert.Equal(t, targetNone, target)
+}
+
+func TestKeyFromChannel(t *testing.T) {
+	assert.Equal(t, "stored_requests:1", keyFromChannel("__keyspace@0__:stored_requests:1"))
+	assert.Equal(t, "a:b__:c", keyFromChannel("__keyspace@12__:a:b__:c"))
+	assert.Equal(t, "", keyFromChannel("__keyevent@0__:set"))
+}
+
+func newTestEvents(t *testing.T) (*miniredis.Miniredis, *RedisEvents) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	ev := NewRedisEvents(client, 0, testPrefixes, time.Second, nil)
+	t.Cleanup(func() {
+		ev.Stop()
+		client.Close()
+	})
+
+	// Wait for the subscription to be registered before publishing anything.
+	require.Eventually(t, func() bool {
+		return server.PubSubNumPat() > 0
+	}, time.Second, 10*time.Millisecond)
+	return server, ev
+}
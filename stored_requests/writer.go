@@ -0,0 +1,139 @@
+package stored_requests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DataType names the kinds of stored data which can be managed through a Writer.
+type DataType string
+
+const (
+	RequestData DataType = "request"
+	ImpData     DataType = "imp"
+	AccountData DataType = "account"
+)
+
+// ErrWriteUnsupported is returned by a Writer asked to store a DataType its backend can't hold.
+var ErrWriteUnsupported = errors.New("this stored data can't be written by the configured backend")
+
+// Writer persists stored data to a backend. It backs the stored data admin API.
+//
+// Implementations must be safe for concurrent access by multiple goroutines.
+type Writer interface {
+	// List returns the IDs of the stored data of the given type in ascending order, skipping the first
+	// offset ones and returning at most limit.
+	List(ctx context.Context, dataType DataType, offset, limit int) ([]string, error)
+	// Get returns the stored data with the given ID, or a NotFoundError.
+	Get(ctx context.Context, dataType DataType, id string) (json.RawMessage, error)
+	// Save inserts or replaces the stored data with the given ID.
+	Save(ctx context.Context, dataType DataType, id string, data json.RawMessage) error
+	// Delete removes the stored data with the given ID. Deleting an ID which doesn't exist is not an error.
+	Delete(ctx context.Context, dataType DataType, id string) error
+}
+
+// ValidateID rejects IDs which can't be safely used as a key by every backend, like ones which would
+// escape the directory of the filesystem backend.
+func ValidateID(id string) error {
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf(`invalid stored data ID "%s"`, id)
+	}
+	return nil
+}
+
+// WritersByDataType is a Writer which hands each DataType to the Writer of the backend it's read from.
+type WritersByDataType map[DataType]Writer
+
+func (w WritersByDataType) writer(dataType DataType) (Writer, error) {
+	if writer, ok := w[dataType]; ok && writer != nil {
+		return writer, nil
+	}
+	return nil, ErrWriteUnsupported
+}
+
+func (w WritersByDataType) List(ctx context.Context, dataType DataType, offset, limit int) ([]string, error) {
+	writer, err := w.writer(dataType)
+	if err != nil {
+		return nil, err
+	}
+	return writer.List(ctx, dataType, offset, limit)
+}
+
+func (w WritersByDataType) Get(ctx context.Context, dataType DataType, id string) (json.RawMessage, error) {
+	writer, err := w.writer(dataType)
+	if err != nil {
+		return nil, err
+	}
+	return writer.Get(ctx, dataType, id)
+}
+
+func (w WritersByDataType) Save(ctx context.Context, dataType DataType, id string, data json.RawMessage) error {
+	writer, err := w.writer(dataType)
+	if err != nil {
+		return err
+	}
+	return writer.Save(ctx, dataType, id, data)
+}
+
+func (w WritersByDataType) Delete(ctx context.Context, dataType DataType, id string) error {
+	writer, err := w.writer(dataType)
+	if err != nil {
+		return err
+	}
+	return writer.Delete(ctx, dataType, id)
+}
+
+// WriterWithCache returns a Writer which invalidates the data it writes from the cache in front of the
+// backend's Fetcher. Cached accounts are merged with the account defaults, so the cache is never filled
+// with the written data directly: the next fetch reads it back from the backend.
+func WriterWithCache(writer Writer, cache Cache) Writer {
+	return &cachedWriter{writer: writer, cache: cache}
+}
+
+type cachedWriter struct {
+	writer Writer
+	cache  Cache
+}
+
+func (w *cachedWriter) cacheFor(dataType DataType) CacheJSON {
+	switch dataType {
+	case RequestData:
+		return w.cache.Requests
+	case ImpData:
+		return w.cache.Imps
+	case AccountData:
+		return w.cache.Accounts
+	}
+	return nil
+}
+
+func (w *cachedWriter) List(ctx context.Context, dataType DataType, offset, limit int) ([]string, error) {
+	return w.writer.List(ctx, dataType, offset, limit)
+}
+
+func (w *cachedWriter) Get(ctx context.Context, dataType DataType, id string) (json.RawMessage, error) {
+	return w.writer.Get(ctx, dataType, id)
+}
+
+func (w *cachedWriter) Save(ctx context.Context, dataType DataType, id string, data json.RawMessage) error {
+	if err := w.writer.Save(ctx, dataType, id, data); err != nil {
+		return err
+	}
+	if cache := w.cacheFor(dataType); cache != nil {
+		cache.Invalidate(ctx, []string{id})
+	}
+	return nil
+}
+
+func (w *cachedWriter) Delete(ctx context.Context, dataType DataType, id string) error {
+	if err := w.writer.Delete(ctx, dataType, id); err != nil {
+		return err
+	}
+	if cache := w.cacheFor(dataType); cache != nil {
+		cache.Invalidate(ctx, []string{id})
+	}
+	return nil
+}
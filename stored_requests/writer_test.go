@@ -0,0 +1,89 @@
+package stored_requests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidateID(t *testing.T) {
+	for _, id := range []string{"", ".", "..", "a/b", `a\b`, "../a"} {
+		assert.Error(t, ValidateID(id), id)
+	}
+	for _, id := range []string{"a", "a.b", "1-2_3"} {
+		assert.NoError(t, ValidateID(id), id)
+	}
+}
+
+func TestWritersByDataType(t *testing.T) {
+	ctx := context.Background()
+	requestWriter := &mockWriter{}
+	requestWriter.On("Save", ctx, RequestData, "1", json.RawMessage(`{}`)).Return(nil)
+	requestWriter.On("Get", ctx, RequestData, "1").Return(json.RawMessage(`{}`), nil)
+	requestWriter.On("List", ctx, RequestData, 0, 10).Return([]string{"1"}, nil)
+	requestWriter.On("Delete", ctx, RequestData, "1").Return(nil)
+
+	writer := WritersByDataType{RequestData: requestWriter, ImpData: nil}
+
+	assert.NoError(t, writer.Save(ctx, RequestData, "1", json.RawMessage(`{}`)))
+	data, err := writer.Get(ctx, RequestData, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, json.RawMessage(`{}`), data)
+	ids, err := writer.List(ctx, RequestData, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids)
+	assert.NoError(t, writer.Delete(ctx, RequestData, "1"))
+	requestWriter.AssertExpectations(t)
+
+	_, err = writer.Get(ctx, ImpData, "1")
+	assert.Equal(t, ErrWriteUnsupported, err)
+	assert.Equal(t, ErrWriteUnsupported, writer.Save(ctx, AccountData, "1", json.RawMessage(`{}`)))
+}
+
+func TestWriterWithCache(t *testing.T) {
+	ctx := context.Background()
+	accountCache := &mockCache{}
+	accountCache.On("Invalidate", ctx, []string{"account"}).Return()
+
+	backend := &mockWriter{}
+	backend.On("Save", ctx, AccountData, "account", json.RawMessage(`{}`)).Return(nil)
+	backend.On("Delete", ctx, AccountData, "account").Return(nil)
+	backend.On("Delete", ctx, AccountData, "broken").Return(errors.New("failure"))
+	backend.On("Save", ctx, RequestData, "request", json.RawMessage(`{}`)).Return(nil)
+
+	writer := WriterWithCache(backend, Cache{Accounts: accountCache})
+
+	assert.NoError(t, writer.Save(ctx, AccountData, "account", json.RawMessage(`{}`)))
+	assert.NoError(t, writer.Delete(ctx, AccountData, "account"))
+	assert.Error(t, writer.Delete(ctx, AccountData, "broken"))
+	assert.NoError(t, writer.Save(ctx, RequestData, "request", json.RawMessage(`{}`)), "types without a cache are still written")
+
+	backend.AssertExpectations(t)
+	accountCache.AssertNumberOfCalls(t, "Invalidate", 2)
+}
+
+type mockWriter struct {
+	mock.Mock
+}
+
+func (w *mockWriter) List(ctx context.Context, dataType DataType, offset, limit int) ([]string, error) {
+	args := w.Called(ctx, dataType, offset, limit)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (w *mockWriter) Get(ctx context.Context, dataType DataType, id string) (json.RawMessage, error) {
+	args := w.Called(ctx, dataType, id)
+	return args.Get(0).(json.RawMessage), args.Error(1)
+}
+
+func (w *mockWriter) Save(ctx context.Context, dataType DataType, id string, data json.RawMessage) error {
+	return w.Called(ctx, dataType, id, data).Error(0)
+}
+
+func (w *mockWriter) Delete(ctx context.Context, dataType DataType, id string) error {
+	return w.Called(ctx, dataType, id).Error(0)
+}
@@ -0,0 +1,60 @@
+package trafficshaping
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+)
+
+// Key identifies the kind of traffic a bid rate is tracked for.
+type Key struct {
+	Account    string
+	Bidder     openrtb_ext.BidderName
+	Country    string
+	DeviceType adcom1.DeviceType
+	Size       string
+}
+
+// KeyFor builds the key for an imp sent to a bidder.
+func KeyFor(account string, bidder openrtb_ext.BidderName, req *openrtb2.BidRequest, imp *openrtb2.Imp) Key {
+	key := Key{
+		Account: account,
+		Bidder:  bidder,
+		Size:    impSize(imp),
+	}
+	if req.Device != nil {
+		key.DeviceType = req.Device.DeviceType
+		if req.Device.Geo != nil {
+			key.Country = strings.ToUpper(req.Device.Geo.Country)
+		}
+	}
+	return key
+}
+
+// impSize describes the size of an imp: the first banner format, or the media type and player size for
+// the other media types.
+func impSize(imp *openrtb2.Imp) string {
+	switch {
+	case imp.Banner != nil:
+		if len(imp.Banner.Format) > 0 {
+			return fmt.Sprintf("%dx%d", imp.Banner.Format[0].W, imp.Banner.Format[0].H)
+		}
+		if imp.Banner.W != nil && imp.Banner.H != nil {
+			return fmt.Sprintf("%dx%d", *imp.Banner.W, *imp.Banner.H)
+		}
+		return "banner"
+	case imp.Video != nil:
+		if imp.Video.W != nil && imp.Video.H != nil {
+			return fmt.Sprintf("video_%dx%d", *imp.Video.W, *imp.Video.H)
+		}
+		return "video"
+	case imp.Native != nil:
+		return "native"
+	case imp.Audio != nil:
+		return "audio"
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package trafficshaping
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v3/openrtb_ext"
+	"github.com/prebid/prebid-server/v3/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyFor(t *testing.T) {
+	testCases := []struct {
+		description string
+		req         *openrtb2.BidRequest
+		imp         *openrtb2.Imp
+		expected    Key
+	}{
+		{
+			description: "banner-format",
+			req:         &openrtb2.BidRequest{Device: &openrtb2.Device{DeviceType: adcom1.DeviceMobile, Geo: &openrtb2.Geo{Country: "usa"}}},
+			imp:         &openrtb2.Imp{Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 728, H: 90}}}},
+			expected:    Key{Account: "acct", Bidder: openrtb_ext.BidderAppnexus, Country: "USA", DeviceType: adcom1.DeviceMobile, Size: "300x250"},
+		},
+		{
+			description: "banner-size",
+			req:         &openrtb2.BidRequest{},
+			imp:         &openrtb2.Imp{Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](320), H: ptrutil.ToPtr[int64](50)}},
+			expected:    Key{Account: "acct", Bidder: openrtb_ext.BidderAppnexus, Size: "320x50"},
+		},
+		{
+			description: "video",
+			req:         &openrtb2.BidRequest{Device: &openrtb2.Device{DeviceType: adcom1.DeviceTV}},
+			imp:         &openrtb2.Imp{Video: &openrtb2.Video{W: ptrutil.ToPtr[int64](640), H: ptrutil.ToPtr[int64](480)}},
+			expected:    Key{Account: "acct", Bidder: openrtb_ext.BidderAppnexus, DeviceType: adcom1.DeviceTV, Size: "video_640x480"},
+		},
+		{
+			description: "native",
+			req:         &openrtb2.BidRequest{},
+			imp:         &openrtb2.Imp{Native: &openrtb2.Native{}},
+			expected:    Key{Account: "acct", Bidder: openrtb_ext.BidderAppnexus, Size: "native"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, KeyFor("acct", openrtb_ext.BidderAppnexus, test.req, test.imp))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package trafficshaping
+
+import (
+	"sync"
+	"time"
+)
+
+// Model keeps the number of requests and bids per key over a rolling window of one minute buckets.
+type Model struct {
+	lock    sync.Mutex
+	window  int
+	maxKeys int
+	keys    map[Key][]bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	minute   int64
+	requests int
+	bids     int
+}
+
+// NewModel creates a model with a window of windowMinutes, which tracks at most maxKeys keys.
+func NewModel(windowMinutes int, maxKeys int) *Model {
+	return &Model{
+		window:  windowMinutes,
+		maxKeys: maxKeys,
+		keys:    make(map[Key][]bucket),
+		now:     time.Now,
+	}
+}
+
+// Record counts a request for the key, and whether the bidder bid on it.
+func (m *Model) Record(key Key, bid bool) {
+	minute := m.now().Unix() / 60
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	buckets, ok := m.keys[key]
+	if !ok {
+		if len(m.keys) >= m.maxKeys {
+			m.sweep(minute)
+		}
+		if len(m.keys) >= m.maxKeys {
+			return
+		}
+		buckets = make([]bucket, m.window)
+		m.keys[key] = buckets
+	}
+
+	b := &buckets[minute%int64(m.window)]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+	b.requests++
+	if bid {
+		b.bids++
+	}
+}
+
+// BidRate returns the ratio of requests for the key which got a bid over the window, and the number of
+// requests it is based on.
+func (m *Model) BidRate(key Key) (rate float64, requests int) {
+	minute := m.now().Unix() / 60
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var bids int
+	for _, b := range m.keys[key] {
+		if m.inWindow(b, minute) {
+			requests += b.requests
+			bids += b.bids
+		}
+	}
+	if requests == 0 {
+		return 0, 0
+	}
+	return float64(bids) / float64(requests), requests
+}
+
+// sweep drops the keys which had no requests within the window.
+func (m *Model) sweep(minute int64) {
+	for key, buckets := range m.keys {
+		stale := true
+		for _, b := range buckets {
+			if b.requests > 0 && m.inWindow(b, minute) {
+				stale = false
+				break
+			}
+		}
+		if stale {
+			delete(m.keys, key)
+		}
+	}
+}
+
+func (m *Model) inWindow(b bucket, minute int64) bool {
+	return b.minute > minute-int64(m.window)
+}
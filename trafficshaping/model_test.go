@@ -0,0 +1,60 @@
+package trafficshaping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelBidRate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	model := NewModel(2, 10)
+	model.now = func() time.Time { return now }
+	key := Key{Account: "acct", Size: "300x250"}
+
+	rate, requests := model.BidRate(key)
+	assert.Equal(t, 0.0, rate)
+	assert.Equal(t, 0, requests)
+
+	model.Record(key, true)
+	model.Record(key, false)
+	now = now.Add(time.Minute)
+	model.Record(key, false)
+	model.Record(key, false)
+
+	rate, requests = model.BidRate(key)
+	assert.Equal(t, 0.25, rate)
+	assert.Equal(t, 4, requests)
+
+	// The first minute falls out of the window
+	now = now.Add(time.Minute)
+	rate, requests = model.BidRate(key)
+	assert.Equal(t, 0.0, rate)
+	assert.Equal(t, 2, requests)
+
+	now = now.Add(time.Minute)
+	rate, requests = model.BidRate(key)
+	assert.Equal(t, 0.0, rate)
+	assert.Equal(t, 0, requests)
+}
+
+func TestModelMaxKeys(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	model := NewModel(1, 1)
+	model.now = func() time.Time { return now }
+	first := Key{Size: "300x250"}
+	second := Key{Size: "728x90"}
+
+	model.Record(first, true)
+	model.Record(second, true)
+	_, requests := model.BidRate(second)
+	assert.Equal(t, 0, requests, "new keys are not tracked once max_keys is reached")
+
+	// Stale keys make room for new ones
+	now = now.Add(time.Minute)
+	model.Record(second, true)
+	_, requests = model.BidRate(second)
+	assert.Equal(t, 1, requests)
+	assert.Len(t, model.keys, 1)
+}
@@ -0,0 +1,56 @@
+package trafficshaping
+
+import (
+	"math/rand"
+
+	"github.com/prebid/prebid-server/v3/config"
+)
+
+// Shaper decides which bidder calls are skipped because the bidder rarely bids on that kind of traffic.
+// A nil *Shaper is valid, and never throttles anything.
+type Shaper struct {
+	model  *Model
+	random func() float64
+}
+
+// Decision is the outcome of Shaper.Decide, along with the bid rate it was based on.
+type Decision struct {
+	Throttled bool
+	BidRate   float64
+	Requests  int
+}
+
+// NewShaper creates a shaper for the host config, or returns nil if traffic shaping is disabled.
+func NewShaper(cfg config.TrafficShaping) *Shaper {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Shaper{
+		model:  NewModel(cfg.WindowMinutes, cfg.MaxKeys),
+		random: rand.Float64,
+	}
+}
+
+// Decide returns whether the call for the key should be skipped. Calls are only throttled once there
+// have been at least MinRequests of them within the window, and even then ExplorationRate of them are
+// let through so that the bid rate keeps getting measured.
+func (s *Shaper) Decide(account config.AccountTrafficShaping, key Key) Decision {
+	if s == nil || !account.Enabled {
+		return Decision{}
+	}
+	rate, requests := s.model.BidRate(key)
+	decision := Decision{BidRate: rate, Requests: requests}
+	if requests < account.MinRequests || rate >= account.BidRateThreshold {
+		return decision
+	}
+	decision.Throttled = s.random() >= account.ExplorationRate
+	return decision
+}
+
+// Record counts a call made for the key, and whether the bidder bid on it.
+func (s *Shaper) Record(key Key, bid bool) {
+	if s == nil {
+		return
+	}
+	s.model.Record(key, bid)
+}
@@ -0,0 +1,88 @@
+package trafficshaping
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewShaperDisabled(t *testing.T) {
+	shaper := NewShaper(config.TrafficShaping{Enabled: false})
+	assert.Nil(t, shaper)
+
+	account := config.AccountTrafficShaping{Enabled: true, BidRateThreshold: 1}
+	shaper.Record(Key{}, false)
+	assert.Equal(t, Decision{}, shaper.Decide(account, Key{}))
+}
+
+func TestShaperDecide(t *testing.T) {
+	key := Key{Account: "acct", Size: "300x250"}
+	account := config.AccountTrafficShaping{
+		Enabled:          true,
+		MinRequests:      4,
+		BidRateThreshold: 0.2,
+		ExplorationRate:  0.1,
+	}
+
+	testCases := []struct {
+		description string
+		account     func(config.AccountTrafficShaping) config.AccountTrafficShaping
+		bids        []bool
+		random      float64
+		expected    Decision
+	}{
+		{
+			description: "account-disabled",
+			account: func(a config.AccountTrafficShaping) config.AccountTrafficShaping {
+				a.Enabled = false
+				return a
+			},
+			bids:     []bool{false, false, false, false},
+			expected: Decision{},
+		},
+		{
+			description: "below-min-requests",
+			bids:        []bool{false, false, false},
+			expected:    Decision{Requests: 3},
+		},
+		{
+			description: "above-threshold",
+			bids:        []bool{true, false, false, false},
+			expected:    Decision{BidRate: 0.25, Requests: 4},
+			random:      0.5,
+		},
+		{
+			description: "below-threshold",
+			account: func(a config.AccountTrafficShaping) config.AccountTrafficShaping {
+				a.BidRateThreshold = 0.5
+				return a
+			},
+			bids:     []bool{true, false, false, false},
+			random:   0.5,
+			expected: Decision{Throttled: true, BidRate: 0.25, Requests: 4},
+		},
+		{
+			description: "below-threshold-explored",
+			bids:        []bool{false, false, false, false},
+			random:      0.05,
+			expected:    Decision{Requests: 4},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			shaper := NewShaper(config.TrafficShaping{Enabled: true, WindowMinutes: 60, MaxKeys: 10})
+			shaper.random = func() float64 { return test.random }
+			for _, bid := range test.bids {
+				shaper.Record(key, bid)
+			}
+
+			accountCfg := account
+			if test.account != nil {
+				accountCfg = test.account(account)
+			}
+			assert.Equal(t, test.expected, shaper.Decide(accountCfg, key))
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package usersync
 
 import (
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/prebid/prebid-server/v3/config"
 	"github.com/prebid/prebid-server/v3/openrtb_ext"
@@ -14,15 +16,16 @@ type Chooser interface {
 	Choose(request Request, cookie *Cookie) Result
 }
 
-// NewChooser returns a new instance of the standard chooser implementation.
-func NewChooser(bidderSyncerLookup map[string]Syncer, biddersKnown map[string]struct{}, bidderInfo map[string]config.BidderInfo) Chooser {
+// NewChooser returns a new instance of the standard chooser implementation. The bidders are ranked by value
+// when a ValueTracker is given.
+func NewChooser(bidderSyncerLookup map[string]Syncer, biddersKnown map[string]struct{}, bidderInfo map[string]config.BidderInfo, valueTracker *ValueTracker) Chooser {
 	bidders := make([]string, 0, len(bidderSyncerLookup))
 
 	for k := range bidderSyncerLookup {
 		bidders = append(bidders, k)
 	}
 
-	return standardChooser{
+	chooser := standardChooser{
 		bidderSyncerLookup:       bidderSyncerLookup,
 		biddersAvailable:         bidders,
 		bidderChooser:            standardBidderChooser{shuffler: randomShuffler{}},
@@ -30,10 +33,23 @@ func NewChooser(bidderSyncerLookup map[string]Syncer, biddersKnown map[string]st
 		biddersKnown:             biddersKnown,
 		bidderInfo:               bidderInfo,
 	}
+
+	if valueTracker != nil {
+		chooser.bidderRanker = newValueBidderRanker(valueTracker, bidderSyncerLookup)
+		chooser.valueTracker = valueTracker
+		chooser.staleUIDAge = valueTracker.cfg.StaleUIDAge()
+		if valueTracker.cfg.Deterministic {
+			sort.Strings(chooser.biddersAvailable)
+			chooser.bidderChooser = standardBidderChooser{shuffler: noShuffler{}}
+		}
+	}
+
+	return chooser
 }
 
 // Request specifies a user sync request.
 type Request struct {
+	Account        string
 	Bidders        []string
 	Cooperative    Cooperative
 	Limit          int
@@ -55,6 +71,8 @@ type Result struct {
 	BiddersEvaluated []BidderEvaluation
 	Status           Status
 	SyncersChosen    []SyncerChoice
+	// Ranking explains the order of the bidders when ranked by value, in that order.
+	Ranking []BidderRanking
 }
 
 // BidderEvaluation specifies which bidders were considered to be synced.
@@ -122,6 +140,10 @@ type standardChooser struct {
 	normalizeValidBidderName func(name string) (openrtb_ext.BidderName, bool)
 	biddersKnown             map[string]struct{}
 	bidderInfo               map[string]config.BidderInfo
+	// bidderRanker, valueTracker and staleUIDAge are only set when the bidders are ranked by value.
+	bidderRanker bidderRanker
+	valueTracker *ValueTracker
+	staleUIDAge  time.Duration
 }
 
 // Choose randomly selects user syncers which are permitted by the user's privacy settings and
@@ -143,6 +165,10 @@ func (c standardChooser) Choose(request Request, cookie *Cookie) Result {
 	syncersChosen := make([]SyncerChoice, 0)
 
 	bidders := c.bidderChooser.choose(request.Bidders, c.biddersAvailable, request.Cooperative)
+	var ranking []BidderRanking
+	if c.bidderRanker != nil {
+		bidders, ranking = c.bidderRanker.rank(request, bidders, cookie)
+	}
 	for i := 0; i < len(bidders) && (limitDisabled || len(syncersChosen) < request.Limit); i++ {
 		if _, ok := biddersSeen[bidders[i]]; ok {
 			continue
@@ -152,11 +178,12 @@ func (c standardChooser) Choose(request Request, cookie *Cookie) Result {
 		biddersEvaluated = append(biddersEvaluated, evaluation)
 		if evaluation.Status == StatusOK {
 			syncersChosen = append(syncersChosen, SyncerChoice{Bidder: bidders[i], Syncer: syncer})
+			c.valueTracker.RecordSyncOffered(syncer.Key())
 		}
 		biddersSeen[bidders[i]] = struct{}{}
 	}
 
-	return Result{Status: StatusOK, BiddersEvaluated: biddersEvaluated, SyncersChosen: syncersChosen}
+	return Result{Status: StatusOK, BiddersEvaluated: biddersEvaluated, SyncersChosen: syncersChosen, Ranking: ranking}
 }
 
 func (c standardChooser) evaluate(bidder string, syncersSeen map[string]struct{}, syncTypeFilter SyncTypeFilter, privacy Privacy, cookie *Cookie, GPPSID string) (Syncer, BidderEvaluation) {
@@ -184,7 +211,7 @@ func (c standardChooser) evaluate(bidder string, syncersSeen map[string]struct{}
 		return nil, BidderEvaluation{Status: StatusRejectedByFilter, Bidder: bidder, SyncerKey: syncer.Key()}
 	}
 
-	if cookie.HasLiveSync(syncer.Key()) {
+	if cookie.HasLiveSync(syncer.Key()) && !c.isStale(cookie, syncer.Key()) {
 		return nil, BidderEvaluation{Status: StatusAlreadySynced, Bidder: bidder, SyncerKey: syncer.Key()}
 	}
 
@@ -215,3 +242,13 @@ func (c standardChooser) evaluate(bidder string, syncersSeen map[string]struct{}
 
 	return syncer, BidderEvaluation{Status: StatusOK, Bidder: bidder, SyncerKey: syncer.Key()}
 }
+
+// isStale returns true if the UID of the syncer is old enough to sync again. It's never stale unless the
+// bidders are ranked by value with a stale UID age.
+func (c standardChooser) isStale(cookie *Cookie, key string) bool {
+	if c.staleUIDAge <= 0 {
+		return false
+	}
+	age, ok := cookie.uidAge(key, time.Now())
+	return ok && age >= c.staleUIDAge
+}
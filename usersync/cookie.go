@@ -42,18 +42,28 @@ func NewCookie() *Cookie {
 
 // ReadCookie reads the cookie from the request
 func ReadCookie(r *http.Request, decoder Decoder, host *config.HostCookie) *Cookie {
+	cookie, _ := ReadCookieWithError(r, decoder, host)
+	return cookie
+}
+
+// ReadCookieWithError reads the cookie from the request like ReadCookie, also returning the error of a cookie
+// which failed to decrypt. The cookie returned is empty in that case.
+func ReadCookieWithError(r *http.Request, decoder Decoder, host *config.HostCookie) (*Cookie, error) {
 	if hostOptOutCookie := checkHostCookieOptOut(r, host); hostOptOutCookie != nil {
-		return hostOptOutCookie
+		return hostOptOutCookie, nil
 	}
 
 	// Read cookie from request
 	cookieFromRequest, err := r.Cookie(uidCookieName)
 	if err != nil {
-		return NewCookie()
+		return NewCookie(), nil
+	}
+	if errDecoder, ok := decoder.(errorDecoder); ok {
+		return errDecoder.DecodeWithError(cookieFromRequest.Value)
 	}
 	decodedCookie := decoder.Decode(cookieFromRequest.Value)
 
-	return decodedCookie
+	return decodedCookie, nil
 }
 
 // PrepareCookieForWrite ejects UIDs as long as the cookie is too full
@@ -199,6 +209,23 @@ func (cookie *Cookie) HasLiveSync(key string) bool {
 	return isLive
 }
 
+// uidAge returns how long ago the UID for the given syncer key was synced, from its expiration. It returns
+// false if the cookie has no UID for the key.
+func (cookie *Cookie) uidAge(key string, now time.Time) (time.Duration, bool) {
+	if cookie == nil {
+		return 0, false
+	}
+	uid, ok := cookie.uids[key]
+	if !ok {
+		return 0, false
+	}
+	age := uidTTL - uid.Expires.Sub(now)
+	if age < 0 {
+		age = 0
+	}
+	return age, true
+}
+
 // HasAnyLiveSyncs returns true if this cookie has at least one active sync.
 func (cookie *Cookie) HasAnyLiveSyncs() bool {
 	now := time.Now()
@@ -10,6 +10,11 @@ type Decoder interface {
 	Decode(encodedValue string) *Cookie
 }
 
+// errorDecoder is a Decoder which tells why a value was decoded to an empty cookie.
+type errorDecoder interface {
+	DecodeWithError(encodedValue string) (*Cookie, error)
+}
+
 type Base64Decoder struct{}
 
 func (d Base64Decoder) Decode(encodedValue string) *Cookie {
@@ -0,0 +1,143 @@
+package usersync
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// encryptedCookieVersion is the first byte of an encrypted cookie. Legacy cookies start with the '{' of their JSON.
+const encryptedCookieVersion byte = 1
+
+// ErrCookieDecryption is returned for a uids cookie which can't be authenticated and decrypted.
+var ErrCookieDecryption = errors.New("uids cookie failed to decrypt")
+
+// NewEncoder returns the Encoder of the uids cookie set up by the host cookie config.
+func NewEncoder(cfg *config.HostCookie) Encoder {
+	if !cfg.Encryption.Enabled {
+		return Base64Encoder{}
+	}
+	aeads := newAEADs(cfg.Encryption.DecodedKeys())
+	return EncryptedEncoder{keyID: cfg.Encryption.ActiveKeyID, aead: aeads[cfg.Encryption.ActiveKeyID]}
+}
+
+// NewDecoder returns the Decoder of the uids cookie set up by the host cookie config.
+func NewDecoder(cfg *config.HostCookie) Decoder {
+	if !cfg.Encryption.Enabled {
+		return Base64Decoder{}
+	}
+	return EncryptedDecoder{aeads: newAEADs(cfg.Encryption.DecodedKeys()), allowLegacy: cfg.Encryption.AllowLegacy}
+}
+
+func newAEADs(keys map[string][]byte) map[string]cipher.AEAD {
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for id, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			continue
+		}
+		if aead, err := cipher.NewGCM(block); err == nil {
+			aeads[id] = aead
+		}
+	}
+	return aeads
+}
+
+// EncryptedEncoder encrypts and authenticates the cookie with AES-GCM. The value is the base64 encoding of
+// the version byte, the length and ID of the key, the nonce and the sealed JSON. The header is authenticated
+// along with the JSON so the key ID can't be swapped.
+type EncryptedEncoder struct {
+	keyID string
+	aead  cipher.AEAD
+}
+
+func (e EncryptedEncoder) Encode(c *Cookie) (string, error) {
+	if e.aead == nil {
+		return "", fmt.Errorf("uids cookie encryption key %q is not available", e.keyID)
+	}
+	j, err := jsonutil.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	header := encryptedCookieHeader(e.keyID)
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	value := make([]byte, 0, len(header)+len(nonce)+len(j)+e.aead.Overhead())
+	value = append(value, header...)
+	value = append(value, nonce...)
+	value = e.aead.Seal(value, nonce, j, header)
+	return base64.URLEncoding.EncodeToString(value), nil
+}
+
+func encryptedCookieHeader(keyID string) []byte {
+	header := make([]byte, 0, 2+len(keyID))
+	header = append(header, encryptedCookieVersion, byte(len(keyID)))
+	return append(header, keyID...)
+}
+
+// EncryptedDecoder reads the cookies of an EncryptedEncoder with any of the configured keys, and the legacy
+// base64 cookies when allowed.
+type EncryptedDecoder struct {
+	aeads       map[string]cipher.AEAD
+	allowLegacy bool
+}
+
+func (d EncryptedDecoder) Decode(encodedValue string) *Cookie {
+	cookie, _ := d.DecodeWithError(encodedValue)
+	return cookie
+}
+
+// DecodeWithError decodes the cookie like Decode, also returning why it fell back to an empty cookie.
+func (d EncryptedDecoder) DecodeWithError(encodedValue string) (*Cookie, error) {
+	value, err := base64.URLEncoding.DecodeString(encodedValue)
+	if err != nil || len(value) == 0 {
+		return NewCookie(), fmt.Errorf("%w: malformed value", ErrCookieDecryption)
+	}
+
+	if value[0] != encryptedCookieVersion {
+		legacy := Base64Decoder{}.Decode(encodedValue)
+		if !d.allowLegacy {
+			// The syncs of a legacy cookie are dropped, but not the choice of a user who opted out
+			cookie := NewCookie()
+			cookie.SetOptOut(legacy.optOut)
+			return cookie, fmt.Errorf("%w: legacy cookies are not allowed", ErrCookieDecryption)
+		}
+		return legacy, nil
+	}
+
+	if len(value) < 2 || len(value) < 2+int(value[1]) {
+		return NewCookie(), fmt.Errorf("%w: malformed value", ErrCookieDecryption)
+	}
+	headerLength := 2 + int(value[1])
+	keyID := string(value[2:headerLength])
+	aead, ok := d.aeads[keyID]
+	if !ok {
+		return NewCookie(), fmt.Errorf("%w: unknown key ID %q", ErrCookieDecryption, keyID)
+	}
+
+	header, sealed := value[:headerLength], value[headerLength:]
+	if len(sealed) < aead.NonceSize() {
+		return NewCookie(), fmt.Errorf("%w: malformed value", ErrCookieDecryption)
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	j, err := aead.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return NewCookie(), fmt.Errorf("%w: %v", ErrCookieDecryption, err)
+	}
+
+	var cookie Cookie
+	if err := jsonutil.UnmarshalValid(j, &cookie); err != nil {
+		return NewCookie(), fmt.Errorf("%w: %v", ErrCookieDecryption, err)
+	}
+	return &cookie, nil
+}
@@ -0,0 +1,164 @@
+package usersync
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testKey2024 = "MDEyMzQ1Njc4OWFiY2RlZg=="
+	testKey2025 = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+)
+
+func encryptionHostCookie(activeKeyID string, allowLegacy bool, keys map[string]string) *config.HostCookie {
+	return &config.HostCookie{
+		Encryption: config.CookieEncryption{
+			Enabled:     true,
+			Keys:        keys,
+			ActiveKeyID: activeKeyID,
+			AllowLegacy: allowLegacy,
+		},
+	}
+}
+
+func TestNewEncoderDecoderDisabled(t *testing.T) {
+	host := &config.HostCookie{}
+	assert.Equal(t, Base64Encoder{}, NewEncoder(host))
+	assert.Equal(t, Base64Decoder{}, NewDecoder(host))
+}
+
+func TestEncryptedEncoderDecoder(t *testing.T) {
+	keys := map[string]string{"2024": testKey2024, "2025": testKey2025}
+	cookie := NewCookie()
+	cookie.Sync("adnxs", "123")
+
+	testCases := []struct {
+		description   string
+		encodeHost    *config.HostCookie
+		decodeHost    *config.HostCookie
+		expectedUID   string
+		expectedError error
+	}{
+		{
+			description: "same-key",
+			encodeHost:  encryptionHostCookie("2025", false, keys),
+			decodeHost:  encryptionHostCookie("2025", false, keys),
+			expectedUID: "123",
+		},
+		{
+			description: "rotated-key-still-decrypts",
+			encodeHost:  encryptionHostCookie("2024", false, keys),
+			decodeHost:  encryptionHostCookie("2025", false, keys),
+			expectedUID: "123",
+		},
+		{
+			description:   "removed-key",
+			encodeHost:    encryptionHostCookie("2024", false, keys),
+			decodeHost:    encryptionHostCookie("2025", false, map[string]string{"2025": testKey2025}),
+			expectedError: ErrCookieDecryption,
+		},
+		{
+			description:   "wrong-key-with-same-id",
+			encodeHost:    encryptionHostCookie("2025", false, keys),
+			decodeHost:    encryptionHostCookie("2025", false, map[string]string{"2025": testKey2024}),
+			expectedError: ErrCookieDecryption,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			encoded, err := NewEncoder(test.encodeHost).Encode(cookie)
+			assert.NoError(t, err)
+			assert.NotContains(t, encoded, base64.URLEncoding.EncodeToString([]byte("123")))
+
+			decoded, err := NewDecoder(test.decodeHost).(EncryptedDecoder).DecodeWithError(encoded)
+			if test.expectedError != nil {
+				assert.True(t, errors.Is(err, test.expectedError), "error %v", err)
+				assert.Empty(t, decoded.GetUIDs())
+				return
+			}
+			assert.NoError(t, err)
+			uid, _, _ := decoded.GetUID("adnxs")
+			assert.Equal(t, test.expectedUID, uid)
+		})
+	}
+}
+
+func TestEncryptedDecoderRejectsTampering(t *testing.T) {
+	host := encryptionHostCookie("2025", false, map[string]string{"2025": testKey2025})
+	cookie := NewCookie()
+	cookie.Sync("adnxs", "123")
+	encoded, err := NewEncoder(host).Encode(cookie)
+	assert.NoError(t, err)
+
+	value, err := base64.URLEncoding.DecodeString(encoded)
+	assert.NoError(t, err)
+	value[len(value)-1] ^= 0xFF
+
+	decoder := NewDecoder(host).(EncryptedDecoder)
+	_, err = decoder.DecodeWithError(base64.URLEncoding.EncodeToString(value))
+	assert.True(t, errors.Is(err, ErrCookieDecryption), "tampered value")
+
+	_, err = decoder.DecodeWithError("not base64!")
+	assert.True(t, errors.Is(err, ErrCookieDecryption), "malformed value")
+
+	_, err = decoder.DecodeWithError(base64.URLEncoding.EncodeToString([]byte{encryptedCookieVersion, 200, 'a'}))
+	assert.True(t, errors.Is(err, ErrCookieDecryption), "truncated header")
+}
+
+func TestEncryptedDecoderLegacy(t *testing.T) {
+	legacyCookie := NewCookie()
+	legacyCookie.Sync("adnxs", "123")
+	legacy, err := Base64Encoder{}.Encode(legacyCookie)
+	assert.NoError(t, err)
+
+	keys := map[string]string{"2025": testKey2025}
+
+	allowed := NewDecoder(encryptionHostCookie("2025", true, keys)).(EncryptedDecoder)
+	decoded, err := allowed.DecodeWithError(legacy)
+	assert.NoError(t, err)
+	assert.True(t, decoded.HasLiveSync("adnxs"))
+
+	rejected := NewDecoder(encryptionHostCookie("2025", false, keys)).(EncryptedDecoder)
+	decoded, err = rejected.DecodeWithError(legacy)
+	assert.True(t, errors.Is(err, ErrCookieDecryption))
+	assert.False(t, decoded.HasLiveSync("adnxs"))
+	assert.True(t, decoded.AllowSyncs())
+
+	legacyCookie.SetOptOut(true)
+	legacyOptOut, err := Base64Encoder{}.Encode(legacyCookie)
+	assert.NoError(t, err)
+	decoded, err = rejected.DecodeWithError(legacyOptOut)
+	assert.True(t, errors.Is(err, ErrCookieDecryption))
+	assert.False(t, decoded.AllowSyncs(), "the opt-out of a legacy cookie is kept")
+}
+
+func TestReadCookieWithError(t *testing.T) {
+	host := encryptionHostCookie("2025", false, map[string]string{"2025": testKey2025})
+
+	cookie := NewCookie()
+	cookie.Sync("adnxs", "123")
+	encoded, err := NewEncoder(host).Encode(cookie)
+	assert.NoError(t, err)
+
+	request := httptest.NewRequest("GET", "/getuids", nil)
+	request.AddCookie(&http.Cookie{Name: uidCookieName, Value: encoded})
+	read, err := ReadCookieWithError(request, NewDecoder(host), host)
+	assert.NoError(t, err)
+	assert.True(t, read.HasLiveSync("adnxs"))
+
+	// The plain decoder reports no error, as before
+	request = httptest.NewRequest("GET", "/getuids", nil)
+	request.AddCookie(&http.Cookie{Name: uidCookieName, Value: "garbage"})
+	_, err = ReadCookieWithError(request, Base64Decoder{}, &config.HostCookie{})
+	assert.NoError(t, err)
+
+	_, err = ReadCookieWithError(request, NewDecoder(host), host)
+	assert.True(t, errors.Is(err, ErrCookieDecryption))
+}
@@ -13,3 +13,8 @@ type randomShuffler struct{}
 func (randomShuffler) shuffle(v []string) {
 	rand.Shuffle(len(v), func(i, j int) { v[i], v[j] = v[j], v[i] })
 }
+
+// noShuffler keeps the order of the elements, for the deterministic value ranking.
+type noShuffler struct{}
+
+func (noShuffler) shuffle(v []string) {}
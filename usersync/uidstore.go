@@ -0,0 +1,112 @@
+package usersync
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// UIDStore keeps the bidder UIDs of users server side, keyed by the host's first-party ID for the user.
+// It keeps syncs working for the browsers which drop or cap the uids cookie.
+type UIDStore interface {
+	// Get returns a cookie holding the UIDs and opt out stored for the first-party ID, or nil if there are none.
+	Get(ctx context.Context, id string) (*Cookie, error)
+	// Save stores the UIDs and opt out of the cookie for the first-party ID, replacing what was there.
+	Save(ctx context.Context, id string, cookie *Cookie) error
+	// Delete removes everything stored for the first-party ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// NewUIDStore creates the store described by the host config, or returns nil if the UID store is disabled.
+func NewUIDStore(cfg config.UIDStore) UIDStore {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Type == config.UIDStoreTypeRedis {
+		client := redis.NewClient(&redis.Options{
+			Addr:         cfg.Redis.Address,
+			Username:     cfg.Redis.Username,
+			Password:     cfg.Redis.Password,
+			DB:           cfg.Redis.Database,
+			DialTimeout:  cfg.Redis.TimeoutDuration(),
+			ReadTimeout:  cfg.Redis.TimeoutDuration(),
+			WriteTimeout: cfg.Redis.TimeoutDuration(),
+		})
+		return NewRedisUIDStore(client, cfg.Redis.KeyPrefix, cfg.TTL())
+	}
+	return NewMemoryUIDStore(cfg.MaxUsers, cfg.TTL())
+}
+
+// ReadStoredUIDs adds the UIDs stored for the request's first-party ID to the cookie, for the syncer keys
+// the cookie doesn't have a UID for. An opt out in either of them opts the cookie out.
+func ReadStoredUIDs(ctx context.Context, r *http.Request, cookie *Cookie, store UIDStore, cfg *config.UIDStore) error {
+	if store == nil || !cookie.AllowSyncs() {
+		return nil
+	}
+	id := firstPartyID(r, cfg)
+	if id == "" {
+		return nil
+	}
+
+	stored, err := store.Get(ctx, id)
+	if err != nil || stored == nil {
+		return err
+	}
+	if !stored.AllowSyncs() {
+		cookie.SetOptOut(true)
+		return nil
+	}
+	for key, entry := range stored.uids {
+		if _, ok := cookie.uids[key]; !ok {
+			cookie.uids[key] = entry
+		}
+	}
+	return nil
+}
+
+// WriteStoredUIDs stores the UIDs and opt out of the cookie for the request's first-party ID. If the request
+// doesn't have a first-party ID yet, one is created and set on the response. It must be called before the
+// cookie is prepared for writing, so that the store keeps the UIDs ejected from the cookie for its size.
+func WriteStoredUIDs(ctx context.Context, w http.ResponseWriter, r *http.Request, cookie *Cookie, store UIDStore, cfg *config.UIDStore, host *config.HostCookie, setSiteCookie bool) error {
+	if store == nil {
+		return nil
+	}
+	id := EnsureFirstPartyID(w, r, cfg, host, setSiteCookie)
+	return store.Save(ctx, id, cookie)
+}
+
+// EnsureFirstPartyID returns the first-party ID of the request. If the request doesn't have one, a new ID is
+// created and set on the response.
+func EnsureFirstPartyID(w http.ResponseWriter, r *http.Request, cfg *config.UIDStore, host *config.HostCookie, setSiteCookie bool) string {
+	if id := firstPartyID(r, cfg); id != "" {
+		return id
+	}
+
+	id := uuid.Must(uuid.NewV4()).String()
+	idCookie := &http.Cookie{
+		Name:    cfg.IDCookieName,
+		Value:   id,
+		Expires: time.Now().Add(host.TTLDuration()),
+		Path:    "/",
+	}
+	if host.Domain != "" {
+		idCookie.Domain = host.Domain
+	}
+	if setSiteCookie {
+		idCookie.Secure = true
+		idCookie.SameSite = http.SameSiteNoneMode
+	}
+	w.Header().Add("Set-Cookie", idCookie.String())
+	return id
+}
+
+func firstPartyID(r *http.Request, cfg *config.UIDStore) string {
+	if idCookie, err := r.Cookie(cfg.IDCookieName); err == nil {
+		return idCookie.Value
+	}
+	return ""
+}
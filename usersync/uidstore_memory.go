@@ -0,0 +1,97 @@
+package usersync
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+)
+
+// memoryUIDStore is a UIDStore which keeps the UIDs in memory, for a single instance deployment. Once it holds
+// maxUsers, saving a new user drops the least recently saved one.
+type memoryUIDStore struct {
+	lock     sync.Mutex
+	ttl      time.Duration
+	maxUsers int
+	now      func() time.Time
+	// order holds the *memoryUIDEntry, from the most to the least recently saved
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryUIDEntry struct {
+	id      string
+	data    []byte
+	expires time.Time
+}
+
+// NewMemoryUIDStore creates a UIDStore which keeps up to maxUsers users in memory, each for ttl after it was saved.
+func NewMemoryUIDStore(maxUsers int, ttl time.Duration) UIDStore {
+	return &memoryUIDStore{
+		ttl:      ttl,
+		maxUsers: maxUsers,
+		now:      time.Now,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (s *memoryUIDStore) Get(_ context.Context, id string) (*Cookie, error) {
+	s.lock.Lock()
+	element, ok := s.entries[id]
+	if ok && !s.now().Before(element.Value.(*memoryUIDEntry).expires) {
+		s.remove(element)
+		ok = false
+	}
+	var data []byte
+	if ok {
+		data = element.Value.(*memoryUIDEntry).data
+	}
+	s.lock.Unlock()
+
+	if !ok {
+		return nil, nil
+	}
+	cookie := NewCookie()
+	if err := jsonutil.Unmarshal(data, cookie); err != nil {
+		return nil, err
+	}
+	return cookie, nil
+}
+
+func (s *memoryUIDStore) Save(_ context.Context, id string, cookie *Cookie) error {
+	data, err := jsonutil.Marshal(cookie)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	entry := &memoryUIDEntry{id: id, data: data, expires: s.now().Add(s.ttl)}
+	if element, ok := s.entries[id]; ok {
+		element.Value = entry
+		s.order.MoveToFront(element)
+		return nil
+	}
+	s.entries[id] = s.order.PushFront(entry)
+	for s.order.Len() > s.maxUsers {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+func (s *memoryUIDStore) Delete(_ context.Context, id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if element, ok := s.entries[id]; ok {
+		s.remove(element)
+	}
+	return nil
+}
+
+func (s *memoryUIDStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.entries, element.Value.(*memoryUIDEntry).id)
+}
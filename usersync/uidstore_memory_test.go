@@ -0,0 +1,72 @@
+package usersync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryUIDStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryUIDStore(2, time.Hour).(*memoryUIDStore)
+	store.now = func() time.Time { return now }
+
+	cookie := NewCookie()
+	cookie.Sync("adnxs", "123")
+	require.NoError(t, store.Save(ctx, "user1", cookie))
+
+	stored, err := store.Get(ctx, "user1")
+	require.NoError(t, err)
+	uid, found, _ := stored.GetUID("adnxs")
+	assert.True(t, found)
+	assert.Equal(t, "123", uid)
+
+	cookie.Sync("rubicon", "456")
+	assert.Equal(t, map[string]string{"adnxs": "123"}, stored.GetUIDs(), "stored cookies aren't shared with the caller")
+
+	missing, err := store.Get(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	require.NoError(t, store.Delete(ctx, "user1"))
+	deleted, _ := store.Get(ctx, "user1")
+	assert.Nil(t, deleted)
+}
+
+func TestMemoryUIDStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryUIDStore(2, time.Hour).(*memoryUIDStore)
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Save(ctx, "user1", NewCookie()))
+	now = now.Add(59 * time.Minute)
+	stored, _ := store.Get(ctx, "user1")
+	assert.NotNil(t, stored)
+
+	now = now.Add(time.Minute)
+	stored, _ = store.Get(ctx, "user1")
+	assert.Nil(t, stored)
+	assert.Empty(t, store.entries)
+}
+
+func TestMemoryUIDStoreMaxUsers(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryUIDStore(2, time.Hour)
+
+	require.NoError(t, store.Save(ctx, "user1", NewCookie()))
+	require.NoError(t, store.Save(ctx, "user2", NewCookie()))
+	require.NoError(t, store.Save(ctx, "user1", NewCookie()))
+	require.NoError(t, store.Save(ctx, "user3", NewCookie()))
+
+	user1, _ := store.Get(ctx, "user1")
+	user2, _ := store.Get(ctx, "user2")
+	user3, _ := store.Get(ctx, "user3")
+	assert.NotNil(t, user1)
+	assert.Nil(t, user2, "the least recently saved user is dropped")
+	assert.NotNil(t, user3)
+}
@@ -0,0 +1,61 @@
+package usersync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/util/jsonutil"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisUIDStore is a UIDStore which keeps the UIDs of each user as JSON under "{keyPrefix}{id}" in Redis, or a
+// compatible server, so that they're shared by all the instances. The keys expire ttl after their last save.
+type redisUIDStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+	ttl       time.Duration
+}
+
+// NewRedisUIDStore creates a UIDStore which keeps the UIDs in Redis.
+func NewRedisUIDStore(client redis.UniversalClient, keyPrefix string, ttl time.Duration) UIDStore {
+	return &redisUIDStore{
+		client:    client,
+		keyPrefix: keyPrefix,
+		ttl:       ttl,
+	}
+}
+
+func (s *redisUIDStore) Get(ctx context.Context, id string) (*Cookie, error) {
+	data, err := s.client.Get(ctx, s.keyPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading stored UIDs from Redis: %v", err)
+	}
+	cookie := NewCookie()
+	if err := jsonutil.Unmarshal(data, cookie); err != nil {
+		return nil, err
+	}
+	return cookie, nil
+}
+
+func (s *redisUIDStore) Save(ctx context.Context, id string, cookie *Cookie) error {
+	data, err := jsonutil.Marshal(cookie)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Set(ctx, s.keyPrefix+id, data, s.ttl).Err(); err != nil {
+		return fmt.Errorf("Error saving stored UIDs to Redis: %v", err)
+	}
+	return nil
+}
+
+func (s *redisUIDStore) Delete(ctx context.Context, id string) error {
+	if err := s.client.Del(ctx, s.keyPrefix+id).Err(); err != nil {
+		return fmt.Errorf("Error deleting stored UIDs from Redis: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package usersync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisUIDStore(t *testing.T) (*miniredis.Miniredis, UIDStore) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return server, NewRedisUIDStore(client, "uids:", time.Hour)
+}
+
+func TestRedisUIDStore(t *testing.T) {
+	ctx := context.Background()
+	server, store := newTestRedisUIDStore(t)
+
+	cookie := NewCookie()
+	cookie.Sync("adnxs", "123")
+	require.NoError(t, store.Save(ctx, "user1", cookie))
+	assert.True(t, server.Exists("uids:user1"))
+	assert.Equal(t, time.Hour, server.TTL("uids:user1"))
+
+	stored, err := store.Get(ctx, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"adnxs": "123"}, stored.GetUIDs())
+
+	missing, err := store.Get(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	require.NoError(t, store.Delete(ctx, "user1"))
+	assert.False(t, server.Exists("uids:user1"))
+
+	server.FastForward(time.Hour)
+	require.NoError(t, store.Save(ctx, "user2", cookie))
+	server.FastForward(time.Hour)
+	expired, err := store.Get(ctx, "user2")
+	assert.NoError(t, err)
+	assert.Nil(t, expired)
+}
+
+func TestRedisUIDStoreErrors(t *testing.T) {
+	ctx := context.Background()
+	server, store := newTestRedisUIDStore(t)
+	server.Set("uids:corrupt", "{")
+
+	_, err := store.Get(ctx, "corrupt")
+	assert.Error(t, err)
+
+	server.Close()
+	_, err = store.Get(ctx, "user1")
+	assert.ErrorContains(t, err, "Error reading stored UIDs from Redis")
+	assert.ErrorContains(t, store.Save(ctx, "user1", NewCookie()), "Error saving stored UIDs to Redis")
+	assert.ErrorContains(t, store.Delete(ctx, "user1"), "Error deleting stored UIDs from Redis")
+}
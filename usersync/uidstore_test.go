@@ -0,0 +1,146 @@
+package usersync
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testUIDStoreConfig = config.UIDStore{Enabled: true, Type: config.UIDStoreTypeMemory, IDCookieName: "pbs_fpid", TTLSeconds: 3600, MaxUsers: 10}
+
+func TestNewUIDStore(t *testing.T) {
+	assert.Nil(t, NewUIDStore(config.UIDStore{Enabled: false}))
+	assert.IsType(t, &memoryUIDStore{}, NewUIDStore(testUIDStoreConfig))
+
+	redisCfg := testUIDStoreConfig
+	redisCfg.Type = config.UIDStoreTypeRedis
+	redisCfg.Redis = config.UIDStoreRedis{Address: "localhost:6379", Timeout: 50, KeyPrefix: "uids:"}
+	assert.IsType(t, &redisUIDStore{}, NewUIDStore(redisCfg))
+}
+
+func TestReadStoredUIDs(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryUIDStore(10, time.Hour)
+
+	stored := NewCookie()
+	stored.Sync("adnxs", "stored-adnxs")
+	stored.Sync("rubicon", "stored-rubicon")
+	require.NoError(t, store.Save(ctx, "user1", stored))
+	optedOut := NewCookie()
+	optedOut.SetOptOut(true)
+	require.NoError(t, store.Save(ctx, "user2", optedOut))
+
+	testCases := []struct {
+		name           string
+		id             string
+		cookieUIDs     map[string]string
+		cookieOptOut   bool
+		store          UIDStore
+		expectedUIDs   map[string]string
+		expectedOptOut bool
+	}{
+		{
+			name:         "cookie-takes-precedence",
+			id:           "user1",
+			cookieUIDs:   map[string]string{"adnxs": "cookie-adnxs"},
+			store:        store,
+			expectedUIDs: map[string]string{"adnxs": "cookie-adnxs", "rubicon": "stored-rubicon"},
+		},
+		{
+			name:         "no-first-party-id",
+			cookieUIDs:   map[string]string{"adnxs": "cookie-adnxs"},
+			store:        store,
+			expectedUIDs: map[string]string{"adnxs": "cookie-adnxs"},
+		},
+		{
+			name:         "unknown-first-party-id",
+			id:           "unknown",
+			store:        store,
+			expectedUIDs: map[string]string{},
+		},
+		{
+			name:           "stored-opt-out",
+			id:             "user2",
+			cookieUIDs:     map[string]string{"adnxs": "cookie-adnxs"},
+			store:          store,
+			expectedUIDs:   map[string]string{},
+			expectedOptOut: true,
+		},
+		{
+			name:           "cookie-opt-out",
+			id:             "user1",
+			cookieOptOut:   true,
+			store:          store,
+			expectedUIDs:   map[string]string{},
+			expectedOptOut: true,
+		},
+		{
+			name:         "no-store",
+			id:           "user1",
+			expectedUIDs: map[string]string{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/getuids", nil)
+			if test.id != "" {
+				r.AddCookie(&http.Cookie{Name: "pbs_fpid", Value: test.id})
+			}
+			cookie := NewCookie()
+			for key, uid := range test.cookieUIDs {
+				cookie.Sync(key, uid)
+			}
+			cookie.SetOptOut(test.cookieOptOut)
+
+			err := ReadStoredUIDs(ctx, r, cookie, test.store, &testUIDStoreConfig)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedUIDs, cookie.GetUIDs())
+			assert.Equal(t, test.expectedOptOut, !cookie.AllowSyncs())
+		})
+	}
+}
+
+func TestWriteStoredUIDs(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryUIDStore(10, time.Hour)
+	host := &config.HostCookie{Domain: "example.com", TTL: 90}
+	cookie := NewCookie()
+	cookie.Sync("adnxs", "123")
+
+	// Without a first-party ID, one is created
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/setuid", nil)
+	require.NoError(t, WriteStoredUIDs(ctx, w, r, cookie, store, &testUIDStoreConfig, host, true))
+
+	setCookies := w.Result().Cookies()
+	require.Len(t, setCookies, 1)
+	assert.Equal(t, "pbs_fpid", setCookies[0].Name)
+	assert.Equal(t, "example.com", setCookies[0].Domain)
+	assert.True(t, setCookies[0].Secure)
+	stored, _ := store.Get(ctx, setCookies[0].Value)
+	require.NotNil(t, stored)
+	assert.Equal(t, map[string]string{"adnxs": "123"}, stored.GetUIDs())
+
+	// With a first-party ID, it's reused
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/setuid", nil)
+	r.AddCookie(&http.Cookie{Name: "pbs_fpid", Value: "user1"})
+	require.NoError(t, WriteStoredUIDs(ctx, w, r, cookie, store, &testUIDStoreConfig, host, false))
+
+	assert.Empty(t, w.Result().Cookies())
+	stored, _ = store.Get(ctx, "user1")
+	assert.NotNil(t, stored)
+
+	// Without a store, nothing happens
+	w = httptest.NewRecorder()
+	assert.NoError(t, WriteStoredUIDs(ctx, w, r, cookie, nil, &testUIDStoreConfig, host, false))
+	assert.Empty(t, w.Result().Cookies())
+}
@@ -0,0 +1,125 @@
+package usersync
+
+import (
+	"sort"
+	"time"
+)
+
+// BidderRanking explains the rank given to a bidder by the value ranking.
+type BidderRanking struct {
+	Bidder string
+	// Score orders the bidders, highest first. It's the uplift weighted by the sync success rate and UID age.
+	Score float64
+	// ValueWithUID and ValueWithoutUID are the CPM won per auction by the bidder with and without a UID.
+	ValueWithUID    float64
+	ValueWithoutUID float64
+	// ValueEstimated is true when too few auctions were observed, the account average uplift is used instead.
+	ValueEstimated  bool
+	SyncSuccessRate float64
+	// UIDAge is the age of the UID of the bidder in the cookie. It's 0 when there's none.
+	UIDAge time.Duration
+}
+
+// bidderRanker reorders the bidders chosen by the bidderChooser before the limit of the request applies.
+type bidderRanker interface {
+	// rank returns the unique bidders in their new order, along with the explanation of their rank.
+	rank(request Request, bidders []string, cookie *Cookie) ([]string, []BidderRanking)
+}
+
+// valueBidderRanker ranks the bidders by the value a UID brings to them, as learned by the ValueTracker.
+type valueBidderRanker struct {
+	tracker    *ValueTracker
+	syncerKeys map[string]string
+	now        func() time.Time
+}
+
+func newValueBidderRanker(tracker *ValueTracker, bidderSyncerLookup map[string]Syncer) valueBidderRanker {
+	syncerKeys := make(map[string]string, len(bidderSyncerLookup))
+	for bidder, syncer := range bidderSyncerLookup {
+		syncerKeys[bidder] = syncer.Key()
+	}
+	return valueBidderRanker{tracker: tracker, syncerKeys: syncerKeys, now: time.Now}
+}
+
+func (r valueBidderRanker) rank(request Request, bidders []string, cookie *Cookie) ([]string, []BidderRanking) {
+	unique := make([]string, 0, len(bidders))
+	seen := make(map[string]struct{}, len(bidders))
+	for _, bidder := range bidders {
+		if _, ok := seen[bidder]; !ok {
+			seen[bidder] = struct{}{}
+			unique = append(unique, bidder)
+		}
+	}
+
+	estimates := r.tracker.estimate(request.Account, unique, r.syncerKeys)
+	averageUplift := averageKnownUplift(estimates)
+	now := r.now()
+
+	rankings := make([]BidderRanking, len(unique))
+	tieBreakers := make(map[string]float64, len(unique))
+	for i, bidder := range unique {
+		estimate := estimates[bidder]
+		uplift := averageUplift
+		if estimate.valueKnown {
+			uplift = estimate.uplift()
+		}
+
+		ageWeight := 1.0
+		uidAge, hasUID := cookie.uidAge(r.syncerKeys[bidder], now)
+		if hasUID && uidAge < uidTTL {
+			// The older the UID, the more a sync refreshes it
+			ageWeight = float64(uidAge) / float64(uidTTL)
+		}
+
+		rankings[i] = BidderRanking{
+			Bidder:          bidder,
+			Score:           uplift * estimate.successRate * ageWeight,
+			ValueWithUID:    estimate.valueWithUID,
+			ValueWithoutUID: estimate.valueWithoutUID,
+			ValueEstimated:  !estimate.valueKnown,
+			SyncSuccessRate: estimate.successRate,
+			UIDAge:          uidAge,
+		}
+		tieBreakers[bidder] = estimate.successRate * ageWeight
+	}
+
+	// The bidders of the request are ranked ahead of the ones added by cooperative syncing, as the bidderChooser
+	// orders them. Stable, so the bidders without any difference keep the order of the bidderChooser.
+	requested := make(map[string]struct{}, len(request.Bidders))
+	for _, bidder := range request.Bidders {
+		requested[bidder] = struct{}{}
+	}
+	sort.SliceStable(rankings, func(i, j int) bool {
+		_, iRequested := requested[rankings[i].Bidder]
+		_, jRequested := requested[rankings[j].Bidder]
+		if iRequested != jRequested {
+			return iRequested
+		}
+		if rankings[i].Score != rankings[j].Score {
+			return rankings[i].Score > rankings[j].Score
+		}
+		return tieBreakers[rankings[i].Bidder] > tieBreakers[rankings[j].Bidder]
+	})
+
+	ranked := make([]string, len(rankings))
+	for i, ranking := range rankings {
+		ranked[i] = ranking.Bidder
+	}
+	return ranked, rankings
+}
+
+// averageKnownUplift is the value given to the bidders not yet observed enough, so they aren't starved of syncs.
+func averageKnownUplift(estimates map[string]bidderEstimate) float64 {
+	var sum float64
+	var count int
+	for _, estimate := range estimates {
+		if estimate.valueKnown {
+			sum += estimate.uplift()
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
@@ -0,0 +1,126 @@
+package usersync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueBidderRankerRank(t *testing.T) {
+	tracker := NewValueTracker(config.UserSyncValueRanking{Enabled: true, MinAuctions: 1, MinSyncs: 2, Window: 100, MaxAccounts: 10})
+	tracker.RecordAuction("acct", "a", true, 3)
+	tracker.RecordAuction("acct", "a", false, 1)
+	tracker.RecordAuction("acct", "b", true, 1.5)
+	tracker.RecordAuction("acct", "b", false, 1)
+	tracker.RecordAuction("acct", "c", true, 3)
+	tracker.RecordAuction("acct", "c", false, 1)
+	tracker.RecordSyncOffered("keyC")
+	tracker.RecordSyncOffered("keyC")
+	tracker.RecordSyncOffered("keyC")
+	tracker.RecordSyncOffered("keyC")
+	tracker.RecordSyncCompleted("keyC")
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ranker := newValueBidderRanker(tracker, map[string]Syncer{
+		"a": fakeSyncer{key: "keyA"},
+		"b": fakeSyncer{key: "keyB"},
+		"c": fakeSyncer{key: "keyC"},
+		"d": fakeSyncer{key: "keyD"},
+		"e": fakeSyncer{key: "keyE"},
+	})
+	ranker.now = func() time.Time { return now }
+
+	// e has a UID synced 7 days ago, so half of its value is left to refresh
+	cookie := NewCookie()
+	cookie.uids["keyE"] = UIDEntry{UID: "e-uid", Expires: now.Add(uidTTL - 7*24*time.Hour)}
+
+	ranked, rankings := ranker.rank(Request{Account: "acct"}, []string{"d", "e", "b", "c", "a", "b"}, cookie)
+
+	assert.Equal(t, []string{"a", "d", "e", "b", "c"}, ranked, "b and c tie, b syncs more often")
+	assert.Equal(t, []BidderRanking{
+		{Bidder: "a", Score: 2, ValueWithUID: 3, ValueWithoutUID: 1, SyncSuccessRate: 1},
+		{Bidder: "d", Score: 1.5, ValueEstimated: true, SyncSuccessRate: 1},
+		{Bidder: "e", Score: 0.75, ValueEstimated: true, SyncSuccessRate: 1, UIDAge: 7 * 24 * time.Hour},
+		{Bidder: "b", Score: 0.5, ValueWithUID: 1.5, ValueWithoutUID: 1, SyncSuccessRate: 1},
+		{Bidder: "c", Score: 0.5, ValueWithUID: 3, ValueWithoutUID: 1, SyncSuccessRate: 0.25},
+	}, rankings)
+}
+
+func TestValueBidderRankerRankRequestedFirst(t *testing.T) {
+	tracker := NewValueTracker(config.UserSyncValueRanking{Enabled: true, MinAuctions: 1, MinSyncs: 1, Window: 100, MaxAccounts: 10})
+	tracker.RecordAuction("acct", "a", true, 3)
+	tracker.RecordAuction("acct", "a", false, 1)
+	tracker.RecordAuction("acct", "b", true, 1.5)
+	tracker.RecordAuction("acct", "b", false, 1)
+	tracker.RecordAuction("acct", "c", true, 2)
+	tracker.RecordAuction("acct", "c", false, 1)
+	ranker := newValueBidderRanker(tracker, map[string]Syncer{"a": fakeSyncer{key: "keyA"}, "b": fakeSyncer{key: "keyB"}, "c": fakeSyncer{key: "keyC"}})
+
+	ranked, _ := ranker.rank(Request{Account: "acct", Bidders: []string{"b", "c"}}, []string{"b", "c", "a", "c"}, NewCookie())
+
+	assert.Equal(t, []string{"c", "b", "a"}, ranked, "the cooperative bidders come after the requested ones, whatever their value")
+}
+
+func TestValueBidderRankerRankNothingKnown(t *testing.T) {
+	tracker := NewValueTracker(config.UserSyncValueRanking{Enabled: true, MinAuctions: 1, MinSyncs: 1, Window: 100, MaxAccounts: 10})
+	ranker := newValueBidderRanker(tracker, map[string]Syncer{"a": fakeSyncer{key: "keyA"}, "b": fakeSyncer{key: "keyB"}})
+
+	ranked, _ := ranker.rank(Request{Account: "acct"}, []string{"b", "a"}, NewCookie())
+
+	assert.Equal(t, []string{"b", "a"}, ranked, "the order of the bidder chooser is kept")
+}
+
+func TestChooserChooseValueRanking(t *testing.T) {
+	tracker := NewValueTracker(config.UserSyncValueRanking{Enabled: true, MinAuctions: 1, MinSyncs: 10, Window: 100, MaxAccounts: 10, StaleUIDAgeHours: 24, Deterministic: true})
+	tracker.RecordAuction("acct", "rubicon", true, 1.5)
+	tracker.RecordAuction("acct", "rubicon", false, 1)
+	tracker.RecordAuction("acct", "pubmatic", true, 3)
+	tracker.RecordAuction("acct", "pubmatic", false, 1)
+
+	syncers := map[string]Syncer{
+		"appnexus": fakeSyncer{key: "adnxs", supportsIFrame: true},
+		"pubmatic": fakeSyncer{key: "pubmatic", supportsIFrame: true},
+		"rubicon":  fakeSyncer{key: "rubicon", supportsIFrame: true},
+		"ix":       fakeSyncer{key: "ix", supportsIFrame: true},
+	}
+	chooser := NewChooser(syncers, map[string]struct{}{}, map[string]config.BidderInfo{}, tracker)
+	assert.Equal(t, []string{"appnexus", "ix", "pubmatic", "rubicon"}, chooser.(standardChooser).biddersAvailable, "sorted when deterministic")
+
+	cookie := NewCookie()
+	// A fresh UID isn't synced again, a stale one is
+	cookie.uids["ix"] = UIDEntry{UID: "fresh", Expires: time.Now().Add(uidTTL - time.Hour)}
+	cookie.uids["adnxs"] = UIDEntry{UID: "stale", Expires: time.Now().Add(uidTTL - 48*time.Hour)}
+
+	request := Request{
+		Account:        "acct",
+		Cooperative:    Cooperative{Enabled: true},
+		Limit:          2,
+		Privacy:        &fakePrivacy{gdprAllowsHostCookie: true, gdprAllowsBidderSync: true, ccpaAllowsBidderSync: true, activityAllowUserSync: true},
+		SyncTypeFilter: SyncTypeFilter{IFrame: NewUniformBidderFilter(BidderFilterModeInclude), Redirect: NewUniformBidderFilter(BidderFilterModeExclude)},
+	}
+
+	result := chooser.Choose(request, cookie)
+
+	assert.Equal(t, StatusOK, result.Status)
+	assert.Equal(t, []SyncerChoice{{Bidder: "pubmatic", Syncer: syncers["pubmatic"]}, {Bidder: "rubicon", Syncer: syncers["rubicon"]}}, result.SyncersChosen)
+
+	rankedBidders := make([]string, len(result.Ranking))
+	for i, ranking := range result.Ranking {
+		rankedBidders[i] = ranking.Bidder
+	}
+	assert.Equal(t, []string{"pubmatic", "rubicon", "appnexus", "ix"}, rankedBidders)
+
+	assert.Equal(t, syncSuccess{offered: 1}, *tracker.syncs["pubmatic"], "offered syncs are recorded")
+	assert.Equal(t, syncSuccess{offered: 1}, *tracker.syncs["rubicon"], "offered syncs are recorded")
+
+	request.Limit = 0
+	result = chooser.Choose(request, cookie)
+	assert.Equal(t, []BidderEvaluation{
+		{Bidder: "pubmatic", SyncerKey: "pubmatic", Status: StatusOK},
+		{Bidder: "rubicon", SyncerKey: "rubicon", Status: StatusOK},
+		{Bidder: "appnexus", SyncerKey: "adnxs", Status: StatusOK},
+		{Bidder: "ix", SyncerKey: "ix", Status: StatusAlreadySynced},
+	}, result.BiddersEvaluated)
+}
@@ -0,0 +1,209 @@
+package usersync
+
+import (
+	"sync"
+
+	"github.com/prebid/prebid-server/v3/config"
+)
+
+// ValueTracker learns the value of syncing each bidder from the outcomes observed by Prebid Server: the CPM
+// a bidder wins in the auctions of an account with and without a UID, and the share of the syncs offered by
+// /cookie_sync which complete at /setuid. It's safe for concurrent use, and a nil *ValueTracker records nothing.
+type ValueTracker struct {
+	cfg config.UserSyncValueRanking
+
+	// accountsMu only guards the accounts map, each account has its own lock so the auctions of different
+	// accounts don't contend.
+	accountsMu sync.RWMutex
+	accounts   map[string]*accountValues
+
+	syncsMu sync.Mutex
+	syncs   map[string]*syncSuccess
+}
+
+// accountValues holds the auction outcomes of the bidders of an account.
+type accountValues struct {
+	mu      sync.RWMutex
+	bidders map[string]*bidderValue
+}
+
+// NewValueTracker returns nil if the value ranking is disabled.
+func NewValueTracker(cfg config.UserSyncValueRanking) *ValueTracker {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &ValueTracker{
+		cfg:      cfg,
+		accounts: make(map[string]*accountValues),
+		syncs:    make(map[string]*syncSuccess),
+	}
+}
+
+// bidderValue holds the auction outcomes of a bidder for the users with and without a UID.
+type bidderValue struct {
+	withUID    valueSample
+	withoutUID valueSample
+}
+
+// valueSample sums the CPM won by a bidder over the auctions it took part in.
+type valueSample struct {
+	auctions float64
+	cpm      float64
+}
+
+func (s *valueSample) add(cpm float64, window int) {
+	s.auctions++
+	s.cpm += cpm
+	if s.auctions >= float64(window) {
+		s.auctions /= 2
+		s.cpm /= 2
+	}
+}
+
+// value is the CPM won per auction, which is the win rate times the average winning CPM.
+func (s valueSample) value() float64 {
+	if s.auctions == 0 {
+		return 0
+	}
+	return s.cpm / s.auctions
+}
+
+// syncSuccess counts the syncs offered to a syncer and the ones which completed.
+type syncSuccess struct {
+	offered   float64
+	completed float64
+}
+
+// RecordAuction records that the bidder took part in an auction of the account, winning the given CPM in total.
+func (t *ValueTracker) RecordAuction(account, bidder string, hasUID bool, cpm float64) {
+	if t == nil {
+		return
+	}
+	values := t.accountValues(account)
+	if values == nil {
+		return
+	}
+	values.mu.Lock()
+	defer values.mu.Unlock()
+
+	value, ok := values.bidders[bidder]
+	if !ok {
+		value = &bidderValue{}
+		values.bidders[bidder] = value
+	}
+	if hasUID {
+		value.withUID.add(cpm, t.cfg.Window)
+	} else {
+		value.withoutUID.add(cpm, t.cfg.Window)
+	}
+}
+
+// accountValues returns the values of the account, adding it unless MaxAccounts are already tracked, in which
+// case it returns nil.
+func (t *ValueTracker) accountValues(account string) *accountValues {
+	t.accountsMu.RLock()
+	values, ok := t.accounts[account]
+	t.accountsMu.RUnlock()
+	if ok {
+		return values
+	}
+
+	t.accountsMu.Lock()
+	defer t.accountsMu.Unlock()
+	if values, ok := t.accounts[account]; ok {
+		return values
+	}
+	if len(t.accounts) >= t.cfg.MaxAccounts {
+		return nil
+	}
+	values = &accountValues{bidders: make(map[string]*bidderValue)}
+	t.accounts[account] = values
+	return values
+}
+
+// RecordSyncOffered records that /cookie_sync returned a sync of the syncer.
+func (t *ValueTracker) RecordSyncOffered(syncerKey string) {
+	if t == nil {
+		return
+	}
+	t.syncsMu.Lock()
+	defer t.syncsMu.Unlock()
+
+	success := t.syncSuccess(syncerKey)
+	success.offered++
+	if success.offered >= float64(t.cfg.Window) {
+		success.offered /= 2
+		success.completed /= 2
+	}
+}
+
+// RecordSyncCompleted records that /setuid received a UID of the syncer.
+func (t *ValueTracker) RecordSyncCompleted(syncerKey string) {
+	if t == nil {
+		return
+	}
+	t.syncsMu.Lock()
+	defer t.syncsMu.Unlock()
+
+	t.syncSuccess(syncerKey).completed++
+}
+
+func (t *ValueTracker) syncSuccess(syncerKey string) *syncSuccess {
+	success, ok := t.syncs[syncerKey]
+	if !ok {
+		success = &syncSuccess{}
+		t.syncs[syncerKey] = success
+	}
+	return success
+}
+
+// bidderEstimate is what the tracker knows about the value of syncing a bidder.
+type bidderEstimate struct {
+	valueWithUID    float64
+	valueWithoutUID float64
+	// valueKnown is false until enough auctions were observed both with and without a UID.
+	valueKnown  bool
+	successRate float64
+}
+
+// uplift is the CPM per auction a UID adds to the bidder. A UID which doesn't help is worth nothing.
+func (e bidderEstimate) uplift() float64 {
+	if uplift := e.valueWithUID - e.valueWithoutUID; uplift > 0 {
+		return uplift
+	}
+	return 0
+}
+
+// estimate returns what the tracker knows about each bidder for the account. The syncer keys map the bidders
+// to their syncer, to look up the sync success rate.
+func (t *ValueTracker) estimate(account string, bidders []string, syncerKeys map[string]string) map[string]bidderEstimate {
+	t.accountsMu.RLock()
+	values := t.accounts[account]
+	t.accountsMu.RUnlock()
+	if values != nil {
+		values.mu.RLock()
+		defer values.mu.RUnlock()
+	}
+	t.syncsMu.Lock()
+	defer t.syncsMu.Unlock()
+
+	estimates := make(map[string]bidderEstimate, len(bidders))
+	for _, bidder := range bidders {
+		estimate := bidderEstimate{successRate: 1}
+		if values != nil {
+			if value, ok := values.bidders[bidder]; ok {
+				estimate.valueWithUID = value.withUID.value()
+				estimate.valueWithoutUID = value.withoutUID.value()
+				estimate.valueKnown = value.withUID.auctions >= float64(t.cfg.MinAuctions) && value.withoutUID.auctions >= float64(t.cfg.MinAuctions)
+			}
+		}
+		if success, ok := t.syncs[syncerKeys[bidder]]; ok && success.offered > 0 && success.offered >= float64(t.cfg.MinSyncs) {
+			estimate.successRate = success.completed / success.offered
+			if estimate.successRate > 1 {
+				estimate.successRate = 1
+			}
+		}
+		estimates[bidder] = estimate
+	}
+	return estimates
+}
@@ -0,0 +1,112 @@
+package usersync
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func testValueRankingConfig() config.UserSyncValueRanking {
+	return config.UserSyncValueRanking{Enabled: true, MinAuctions: 2, MinSyncs: 2, Window: 100, MaxAccounts: 10}
+}
+
+func TestNewValueTracker(t *testing.T) {
+	assert.Nil(t, NewValueTracker(config.UserSyncValueRanking{Enabled: false}))
+	assert.NotNil(t, NewValueTracker(testValueRankingConfig()))
+}
+
+func TestValueTrackerNilSafe(t *testing.T) {
+	var tracker *ValueTracker
+	assert.NotPanics(t, func() {
+		tracker.RecordAuction("acct", "a", true, 1)
+		tracker.RecordSyncOffered("keyA")
+		tracker.RecordSyncCompleted("keyA")
+	})
+}
+
+func TestValueTrackerEstimate(t *testing.T) {
+	tracker := NewValueTracker(testValueRankingConfig())
+
+	// a: wins 2.0 per auction with a UID, 0.5 without
+	tracker.RecordAuction("acct", "a", true, 4)
+	tracker.RecordAuction("acct", "a", true, 0)
+	tracker.RecordAuction("acct", "a", false, 1)
+	tracker.RecordAuction("acct", "a", false, 0)
+	// b: not enough auctions without a UID
+	tracker.RecordAuction("acct", "b", true, 3)
+	tracker.RecordAuction("acct", "b", true, 3)
+	tracker.RecordAuction("acct", "b", false, 1)
+	// another account
+	tracker.RecordAuction("other", "c", true, 1)
+
+	tracker.RecordSyncOffered("keyA")
+	tracker.RecordSyncOffered("keyA")
+	tracker.RecordSyncOffered("keyA")
+	tracker.RecordSyncOffered("keyA")
+	tracker.RecordSyncCompleted("keyA")
+	tracker.RecordSyncOffered("keyB")
+	tracker.RecordSyncCompleted("keyB")
+
+	estimates := tracker.estimate("acct", []string{"a", "b", "c"}, map[string]string{"a": "keyA", "b": "keyB", "c": "keyC"})
+
+	assert.Equal(t, bidderEstimate{valueWithUID: 2, valueWithoutUID: 0.5, valueKnown: true, successRate: 0.25}, estimates["a"])
+	assert.Equal(t, 1.5, estimates["a"].uplift())
+	assert.Equal(t, bidderEstimate{valueWithUID: 3, valueWithoutUID: 1, valueKnown: false, successRate: 1}, estimates["b"], "too few auctions and syncs")
+	assert.Equal(t, bidderEstimate{successRate: 1}, estimates["c"], "other account")
+}
+
+func TestValueTrackerUpliftNeverNegative(t *testing.T) {
+	assert.Equal(t, 0.0, bidderEstimate{valueWithUID: 1, valueWithoutUID: 2}.uplift())
+}
+
+func TestValueTrackerWindow(t *testing.T) {
+	cfg := testValueRankingConfig()
+	cfg.Window = 4
+	tracker := NewValueTracker(cfg)
+
+	for i := 0; i < 4; i++ {
+		tracker.RecordAuction("acct", "a", true, 1)
+		tracker.RecordSyncOffered("keyA")
+	}
+	tracker.RecordSyncCompleted("keyA")
+
+	value := tracker.accounts["acct"].bidders["a"].withUID
+	assert.Equal(t, valueSample{auctions: 2, cpm: 2}, value, "halved at the window")
+	assert.Equal(t, syncSuccess{offered: 2, completed: 1}, *tracker.syncs["keyA"])
+}
+
+func TestValueTrackerMaxAccounts(t *testing.T) {
+	cfg := testValueRankingConfig()
+	cfg.MaxAccounts = 1
+	tracker := NewValueTracker(cfg)
+
+	tracker.RecordAuction("first", "a", true, 1)
+	tracker.RecordAuction("second", "a", true, 1)
+	tracker.RecordAuction("first", "b", true, 1)
+
+	assert.Len(t, tracker.accounts, 1)
+	assert.Len(t, tracker.accounts["first"].bidders, 2)
+}
+
+func TestValueTrackerConcurrentRecording(t *testing.T) {
+	tracker := NewValueTracker(testValueRankingConfig())
+
+	var wg sync.WaitGroup
+	for _, account := range []string{"first", "second", "third"} {
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(account string) {
+				defer wg.Done()
+				tracker.RecordAuction(account, "a", true, 1)
+				tracker.estimate(account, []string{"a"}, map[string]string{"a": "keyA"})
+			}(account)
+		}
+	}
+	wg.Wait()
+
+	for _, account := range []string{"first", "second", "third"} {
+		assert.Equal(t, 10.0, tracker.accounts[account].bidders["a"].withUID.auctions, account)
+	}
+}
@@ -0,0 +1,255 @@
+package vastunwrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+)
+
+// ErrInvalidVAST is wrapped by the errors which mean the creative itself is broken, as opposed to the
+// VAST not being resolved in time.
+var ErrInvalidVAST = errors.New("invalid VAST")
+
+func invalidf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidVAST, fmt.Sprintf(format, args...))
+}
+
+// maxRedirects caps the redirects followed when fetching one VAST document.
+const maxRedirects = 3
+
+// blockedPrefixes are the ranges, besides the loopback, private, link-local, multicast and unspecified
+// addresses, which VAST URLs must not reach: "this network" and the shared address space some clouds
+// serve their metadata from.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// Unwrapper follows the wrapper chains of video bids down to their inline VAST.
+// A nil *Unwrapper is valid, and never resolves anything.
+type Unwrapper struct {
+	client           *http.Client
+	maxWrapperDepth  int
+	maxResponseBytes int64
+	timeout          time.Duration
+}
+
+// Result is the inline VAST a bid resolved to.
+type Result struct {
+	// VAST is the inline VAST document, with the impression and error trackers of the wrappers added.
+	VAST string
+	// Version is the version of the inline VAST document.
+	Version string
+	// WrapperVersion is the version of the bid's own VAST if it was a wrapper, and empty otherwise.
+	WrapperVersion string
+	// Wrappers is the number of documents fetched to reach the inline VAST.
+	Wrappers int
+	// Duration is the duration of the first linear creative, or 0 if it doesn't have one.
+	Duration   time.Duration
+	MediaFiles []MediaFile
+}
+
+// NewUnwrapper creates an unwrapper for the host config, or returns nil if VAST unwrapping is disabled.
+// The VAST URLs come from the bids, so the client refuses to connect to the host's own network, and doesn't
+// use a proxy which would hide the address connected to.
+func NewUnwrapper(cfg config.VASTUnwrap) *Unwrapper {
+	if !cfg.Enabled {
+		return nil
+	}
+	dialer := &net.Dialer{
+		Timeout: time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		Control: checkDialAddress,
+	}
+	client := &http.Client{
+		CheckRedirect: checkRedirect,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			MaxConnsPerHost:     cfg.HttpClient.MaxConnsPerHost,
+			MaxIdleConns:        cfg.HttpClient.MaxIdleConns,
+			MaxIdleConnsPerHost: cfg.HttpClient.MaxIdleConnsPerHost,
+			IdleConnTimeout:     time.Duration(cfg.HttpClient.IdleConnTimeout) * time.Second,
+		},
+	}
+	return NewUnwrapperWithClient(cfg, client)
+}
+
+// NewUnwrapperWithClient creates an unwrapper which fetches the VAST with the client, or returns nil if VAST
+// unwrapping is disabled. Unlike NewUnwrapper's, the client doesn't restrict the addresses connected to.
+func NewUnwrapperWithClient(cfg config.VASTUnwrap, client *http.Client) *Unwrapper {
+	if !cfg.Enabled {
+		return nil
+	}
+	return newUnwrapper(client, cfg.MaxWrapperDepth, cfg.MaxResponseBytes, time.Duration(cfg.TimeoutMs)*time.Millisecond)
+}
+
+func newUnwrapper(client *http.Client, maxWrapperDepth int, maxResponseBytes int64, timeout time.Duration) *Unwrapper {
+	return &Unwrapper{
+		client:           client,
+		maxWrapperDepth:  maxWrapperDepth,
+		maxResponseBytes: maxResponseBytes,
+		timeout:          timeout,
+	}
+}
+
+// Timeout is the most time an auction may spend resolving its bids.
+func (u *Unwrapper) Timeout() time.Duration {
+	if u == nil {
+		return 0
+	}
+	return u.timeout
+}
+
+// Unwrap resolves the VAST adm of a bid. Bids without adm can't be resolved, as their nurl is the win notice
+// which must only be fired by the winning bid. Errors wrapping ErrInvalidVAST mean the creative is broken,
+// while others mean it couldn't be resolved, for example before ctx expired.
+func (u *Unwrapper) Unwrap(ctx context.Context, adm string) (*Result, error) {
+	if u == nil {
+		return nil, errors.New("VAST unwrapping is disabled")
+	}
+	if adm == "" {
+		return nil, errors.New("bid has no adm")
+	}
+
+	result := &Result{}
+	vastXML := []byte(adm)
+
+	var impressions, errorURLs []string
+	for {
+		doc, err := parseDocument(vastXML)
+		if err != nil {
+			return nil, err
+		}
+		ad := doc.Ads[0]
+
+		if ad.InLine != nil {
+			result.VAST = addTrackers(string(vastXML), impressions, errorURLs)
+			result.Version = doc.Version
+			return result, fillCreative(result, ad.InLine)
+		}
+		if ad.Wrapper == nil {
+			return nil, invalidf("VAST ad has neither InLine nor Wrapper")
+		}
+
+		if result.Wrappers == 0 {
+			result.WrapperVersion = doc.Version
+		}
+		if result.Wrappers >= u.maxWrapperDepth {
+			return nil, invalidf("VAST has more than %d wrappers", u.maxWrapperDepth)
+		}
+		tagURI := strings.TrimSpace(ad.Wrapper.VASTAdTagURI)
+		if tagURI == "" {
+			return nil, invalidf("VAST wrapper has no VASTAdTagURI")
+		}
+		impressions = append(impressions, ad.Wrapper.Impressions...)
+		errorURLs = append(errorURLs, ad.Wrapper.Errors...)
+
+		if vastXML, err = u.fetch(ctx, tagURI); err != nil {
+			return nil, err
+		}
+		result.Wrappers++
+	}
+}
+
+func fillCreative(result *Result, inline *inLine) error {
+	for _, creative := range inline.Creatives {
+		if creative.Linear == nil {
+			continue
+		}
+		for _, mediaFile := range creative.Linear.MediaFiles {
+			mediaFile.Type = strings.TrimSpace(mediaFile.Type)
+			mediaFile.URL = strings.TrimSpace(mediaFile.URL)
+			result.MediaFiles = append(result.MediaFiles, mediaFile)
+		}
+		if result.Duration == 0 && creative.Linear.Duration != "" {
+			duration, err := parseDuration(creative.Linear.Duration)
+			if err != nil {
+				return invalidf("%v", err)
+			}
+			result.Duration = duration
+		}
+	}
+	return nil
+}
+
+// checkDialAddress is the Control hook of the dialer, so the address is checked after it's resolved, for
+// every connection including the ones of redirects.
+func checkDialAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return invalidf("VAST URL resolved to %s which isn't a host and port", address)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return invalidf("VAST URL resolved to %s which isn't an IP", host)
+	}
+	if isBlockedAddr(ip) {
+		return invalidf("VAST URL resolved to the blocked address %s", host)
+	}
+	return nil
+}
+
+func isBlockedAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > maxRedirects {
+		return invalidf("VAST URL redirected more than %d times", maxRedirects)
+	}
+	return checkScheme(req.URL)
+}
+
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return invalidf("VAST URL %s uses the unsupported scheme %q", u.Redacted(), u.Scheme)
+	}
+	return nil
+}
+
+func (u *Unwrapper) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, invalidf("VAST URL %s isn't valid: %v", url, err)
+	}
+	if err := checkScheme(req.URL); err != nil {
+		return nil, err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		// Blocked addresses and redirects wrap ErrInvalidVAST
+		return nil, fmt.Errorf("GET %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned status %d", url, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, u.maxResponseBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("GET %s failed: %v", url, err)
+	}
+	if int64(len(body)) > u.maxResponseBytes {
+		return nil, invalidf("GET %s returned more than %d bytes", url, u.maxResponseBytes)
+	}
+	return body, nil
+}
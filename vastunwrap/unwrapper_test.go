@@ -0,0 +1,249 @@
+package vastunwrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v3/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const inlineVAST = `<VAST version="4.0"><Ad><InLine><Creatives><Creative><Linear>` +
+	`<Duration>00:00:15.000</Duration>` +
+	`<MediaFiles><MediaFile type="video/mp4"><![CDATA[https://cdn.example.com/ad.mp4]]></MediaFile></MediaFiles>` +
+	`</Linear></Creative></Creatives></InLine></Ad></VAST>`
+
+func wrapperVAST(version, tagURI string) string {
+	return `<VAST version="` + version + `"><Ad><Wrapper>` +
+		`<VASTAdTagURI><![CDATA[` + tagURI + `]]></VASTAdTagURI>` +
+		`<Impression><![CDATA[` + tagURI + `/imp]]></Impression>` +
+		`<Error><![CDATA[` + tagURI + `/error]]></Error>` +
+		`</Wrapper></Ad></VAST>`
+}
+
+// newFixtureServer serves /inline, wrappers /wrapper/N which point at /wrapper/N-1, down to /wrapper/0
+// which points at /inline, and /redirect/N which redirects N times before serving /inline.
+func newFixtureServer(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var depth int
+		switch {
+		case r.URL.Path == "/inline":
+			w.Write([]byte(inlineVAST))
+		case r.URL.Path == "/slow":
+			<-r.Context().Done()
+		case r.URL.Path == "/large":
+			w.Write(make([]byte, 2048))
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/wrapper/0":
+			w.Write([]byte(wrapperVAST("3.0", server.URL+"/inline")))
+		case r.URL.Path == "/redirect/0":
+			http.Redirect(w, r, "/inline", http.StatusFound)
+		case strings.HasPrefix(r.URL.Path, "/redirect/"):
+			fmt.Sscanf(r.URL.Path, "/redirect/%d", &depth)
+			http.Redirect(w, r, fmt.Sprintf("/redirect/%d", depth-1), http.StatusFound)
+		default:
+			if _, err := fmt.Sscanf(r.URL.Path, "/wrapper/%d", &depth); err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(wrapperVAST("3.0", fmt.Sprintf("%s/wrapper/%d", server.URL, depth-1))))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewUnwrapper(t *testing.T) {
+	assert.Nil(t, NewUnwrapper(config.VASTUnwrap{Enabled: false}))
+
+	unwrapper := NewUnwrapper(config.VASTUnwrap{Enabled: true, MaxWrapperDepth: 3, TimeoutMs: 150, MaxResponseBytes: 1024})
+	require.NotNil(t, unwrapper)
+	assert.Equal(t, 150*time.Millisecond, unwrapper.Timeout())
+	assert.Equal(t, time.Duration(0), (*Unwrapper)(nil).Timeout())
+
+	assert.Nil(t, NewUnwrapperWithClient(config.VASTUnwrap{Enabled: false}, http.DefaultClient))
+	assert.NotNil(t, NewUnwrapperWithClient(config.VASTUnwrap{Enabled: true, MaxWrapperDepth: 3, TimeoutMs: 150, MaxResponseBytes: 1024}, http.DefaultClient))
+}
+
+func TestUnwrap(t *testing.T) {
+	server := newFixtureServer(t)
+	unwrapper := newUnwrapper(server.Client(), 3, 1024, time.Second)
+
+	testCases := []struct {
+		name             string
+		adm              string
+		expectedWrappers int
+		expectedWrapper  string
+		expectedVAST     string
+		expectedErr      string
+		expectedInvalid  bool
+	}{
+		{
+			name:         "inline-adm",
+			adm:          inlineVAST,
+			expectedVAST: inlineVAST,
+		},
+		{
+			name:             "wrapper-chain",
+			adm:              wrapperVAST("4.1", server.URL+"/wrapper/1"),
+			expectedWrappers: 3,
+			expectedWrapper:  "4.1",
+			expectedVAST: inlineVAST[:len(inlineVAST)-len("</InLine></Ad></VAST>")] +
+				`<Error><![CDATA[` + server.URL + `/wrapper/1/error]]></Error>` +
+				`<Error><![CDATA[` + server.URL + `/wrapper/0/error]]></Error>` +
+				`<Error><![CDATA[` + server.URL + `/inline/error]]></Error>` +
+				`<Impression><![CDATA[` + server.URL + `/wrapper/1/imp]]></Impression>` +
+				`<Impression><![CDATA[` + server.URL + `/wrapper/0/imp]]></Impression>` +
+				`<Impression><![CDATA[` + server.URL + `/inline/imp]]></Impression>` +
+				`</InLine></Ad></VAST>`,
+		},
+		{
+			name:            "too-many-wrappers",
+			adm:             wrapperVAST("3.0", server.URL+"/wrapper/5"),
+			expectedErr:     "invalid VAST: VAST has more than 3 wrappers",
+			expectedInvalid: true,
+		},
+		{
+			name:        "no-adm",
+			expectedErr: "bid has no adm",
+		},
+		{
+			name:            "not-xml",
+			adm:             "<div>banner</div>",
+			expectedErr:     "invalid VAST: VAST isn't valid XML: expected element type <VAST> but have <div>",
+			expectedInvalid: true,
+		},
+		{
+			name:            "no-ads",
+			adm:             `<VAST version="3.0"></VAST>`,
+			expectedErr:     "invalid VAST: VAST has no ads",
+			expectedInvalid: true,
+		},
+		{
+			name:        "missing",
+			adm:         wrapperVAST("3.0", server.URL+"/missing"),
+			expectedErr: "GET " + server.URL + "/missing returned status 404",
+		},
+		{
+			name:            "unsupported-scheme",
+			adm:             wrapperVAST("3.0", "file:///etc/passwd"),
+			expectedErr:     `invalid VAST: VAST URL file:///etc/passwd uses the unsupported scheme "file"`,
+			expectedInvalid: true,
+		},
+		{
+			name:            "too-large",
+			adm:             wrapperVAST("3.0", server.URL+"/large"),
+			expectedErr:     "invalid VAST: GET " + server.URL + "/large returned more than 1024 bytes",
+			expectedInvalid: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := unwrapper.Unwrap(context.Background(), test.adm)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				assert.Equal(t, test.expectedInvalid, errors.Is(err, ErrInvalidVAST))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedVAST, result.VAST)
+			assert.Equal(t, "4.0", result.Version)
+			assert.Equal(t, test.expectedWrapper, result.WrapperVersion)
+			assert.Equal(t, test.expectedWrappers, result.Wrappers)
+			assert.Equal(t, 15*time.Second, result.Duration)
+			assert.Equal(t, []MediaFile{{Type: "video/mp4", URL: "https://cdn.example.com/ad.mp4"}}, result.MediaFiles)
+		})
+	}
+}
+
+func TestUnwrapBlockedAddress(t *testing.T) {
+	server := newFixtureServer(t)
+	unwrapper := NewUnwrapper(config.VASTUnwrap{Enabled: true, MaxWrapperDepth: 3, TimeoutMs: 1000, MaxResponseBytes: 1024})
+
+	_, err := unwrapper.Unwrap(context.Background(), wrapperVAST("3.0", server.URL+"/inline"))
+	assert.ErrorContains(t, err, "VAST URL resolved to the blocked address 127.0.0.1")
+	assert.True(t, errors.Is(err, ErrInvalidVAST), "the test server listens on the loopback")
+}
+
+func TestCheckDialAddress(t *testing.T) {
+	testCases := []struct {
+		name    string
+		address string
+		blocked bool
+	}{
+		{name: "public-ipv4", address: "93.184.216.34:80"},
+		{name: "public-ipv6", address: "[2606:4700::1]:443"},
+		{name: "loopback-ipv4", address: "127.0.0.1:80", blocked: true},
+		{name: "loopback-ipv6", address: "[::1]:80", blocked: true},
+		{name: "loopback-ipv4-mapped", address: "[::ffff:127.0.0.1]:80", blocked: true},
+		{name: "private-10", address: "10.1.2.3:80", blocked: true},
+		{name: "private-172", address: "172.16.0.1:80", blocked: true},
+		{name: "private-192", address: "192.168.1.1:80", blocked: true},
+		{name: "private-ipv6", address: "[fd00::1]:80", blocked: true},
+		{name: "link-local-metadata", address: "169.254.169.254:80", blocked: true},
+		{name: "link-local-ipv6", address: "[fe80::1]:80", blocked: true},
+		{name: "unspecified-ipv4", address: "0.0.0.0:80", blocked: true},
+		{name: "unspecified-ipv6", address: "[::]:80", blocked: true},
+		{name: "this-network", address: "0.1.2.3:80", blocked: true},
+		{name: "shared-address-space", address: "100.100.100.200:80", blocked: true},
+		{name: "multicast", address: "224.0.0.1:80", blocked: true},
+		{name: "not-an-ip", address: "example.com:80", blocked: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkDialAddress("tcp", test.address, nil)
+			if test.blocked {
+				assert.True(t, errors.Is(err, ErrInvalidVAST))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUnwrapRedirects(t *testing.T) {
+	server := newFixtureServer(t)
+	unwrapper := newUnwrapper(&http.Client{Transport: server.Client().Transport, CheckRedirect: checkRedirect}, 3, 1024, time.Second)
+
+	result, err := unwrapper.Unwrap(context.Background(), wrapperVAST("3.0", server.URL+"/redirect/2"))
+	require.NoError(t, err, "the last redirect is to /inline")
+	assert.Equal(t, 1, result.Wrappers)
+
+	_, err = unwrapper.Unwrap(context.Background(), wrapperVAST("3.0", server.URL+"/redirect/3"))
+	assert.ErrorContains(t, err, "VAST URL redirected more than 3 times")
+	assert.True(t, errors.Is(err, ErrInvalidVAST))
+}
+
+func TestCheckRedirectScheme(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "gopher://internal:70/", nil)
+	err := checkRedirect(req, []*http.Request{{}})
+	assert.EqualError(t, err, `invalid VAST: VAST URL gopher://internal:70/ uses the unsupported scheme "gopher"`)
+}
+
+func TestUnwrapTimeout(t *testing.T) {
+	server := newFixtureServer(t)
+	unwrapper := newUnwrapper(server.Client(), 3, 1024, time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := unwrapper.Unwrap(ctx, wrapperVAST("3.0", server.URL+"/slow"))
+
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrInvalidVAST), "running out of time doesn't mean the VAST is invalid")
+}
+
+func TestUnwrapNil(t *testing.T) {
+	_, err := (*Unwrapper)(nil).Unwrap(context.Background(), inlineVAST)
+	assert.EqualError(t, err, "VAST unwrapping is disabled")
+}
@@ -0,0 +1,54 @@
+package vastunwrap
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// Validate checks that the resolved VAST has media files, and that its duration, mime types and protocols
+// are allowed by the video imp. Imps which don't constrain a property accept any value of it.
+func Validate(result *Result, video *openrtb2.Video) error {
+	if len(result.MediaFiles) == 0 {
+		return invalidf("VAST has no linear media files")
+	}
+	if video == nil {
+		return nil
+	}
+
+	if video.MinDuration > 0 || video.MaxDuration > 0 {
+		if result.Duration == 0 {
+			return invalidf("VAST has no linear duration")
+		}
+		seconds := int64(math.Round(result.Duration.Seconds()))
+		if video.MinDuration > 0 && seconds < video.MinDuration {
+			return invalidf("VAST duration %ds is shorter than the imp minduration %ds", seconds, video.MinDuration)
+		}
+		if video.MaxDuration > 0 && seconds > video.MaxDuration {
+			return invalidf("VAST duration %ds is longer than the imp maxduration %ds", seconds, video.MaxDuration)
+		}
+	}
+
+	if len(video.MIMEs) > 0 {
+		mimeAllowed := slices.ContainsFunc(result.MediaFiles, func(mediaFile MediaFile) bool {
+			return slices.ContainsFunc(video.MIMEs, func(mime string) bool { return strings.EqualFold(mime, mediaFile.Type) })
+		})
+		if !mimeAllowed {
+			return invalidf("VAST has no media file of the imp mimes %s", strings.Join(video.MIMEs, ", "))
+		}
+	}
+
+	if len(video.Protocols) > 0 {
+		if p, ok := protocol(result.Version, false); ok && !slices.Contains(video.Protocols, p) {
+			return invalidf("VAST version %s isn't in the imp protocols", result.Version)
+		}
+		if result.WrapperVersion != "" {
+			if p, ok := protocol(result.WrapperVersion, true); ok && !slices.Contains(video.Protocols, p) {
+				return invalidf("VAST %s wrappers aren't in the imp protocols", result.WrapperVersion)
+			}
+		}
+	}
+	return nil
+}
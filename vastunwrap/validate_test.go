@@ -0,0 +1,94 @@
+package vastunwrap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	mp4 := []MediaFile{{Type: "video/mp4", URL: "https://cdn.example.com/ad.mp4"}}
+
+	testCases := []struct {
+		name        string
+		result      Result
+		video       *openrtb2.Video
+		expectedErr string
+	}{
+		{
+			name:        "no-media-files",
+			result:      Result{Version: "3.0", Duration: 15 * time.Second},
+			video:       &openrtb2.Video{},
+			expectedErr: "invalid VAST: VAST has no linear media files",
+		},
+		{
+			name:   "no-video-constraints",
+			result: Result{Version: "3.0", MediaFiles: mp4},
+		},
+		{
+			name:   "matches-imp",
+			result: Result{Version: "4.0", WrapperVersion: "3.0", Duration: 15400 * time.Millisecond, MediaFiles: mp4},
+			video: &openrtb2.Video{
+				MIMEs:       []string{"video/webm", "VIDEO/MP4"},
+				MinDuration: 5,
+				MaxDuration: 15,
+				Protocols:   []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST40, adcom1.CreativeVAST30Wrapper},
+			},
+		},
+		{
+			name:        "no-duration",
+			result:      Result{Version: "3.0", MediaFiles: mp4},
+			video:       &openrtb2.Video{MaxDuration: 30},
+			expectedErr: "invalid VAST: VAST has no linear duration",
+		},
+		{
+			name:        "too-short",
+			result:      Result{Version: "3.0", Duration: 4 * time.Second, MediaFiles: mp4},
+			video:       &openrtb2.Video{MinDuration: 5},
+			expectedErr: "invalid VAST: VAST duration 4s is shorter than the imp minduration 5s",
+		},
+		{
+			name:        "too-long",
+			result:      Result{Version: "3.0", Duration: 31 * time.Second, MediaFiles: mp4},
+			video:       &openrtb2.Video{MaxDuration: 30},
+			expectedErr: "invalid VAST: VAST duration 31s is longer than the imp maxduration 30s",
+		},
+		{
+			name:        "mime-not-allowed",
+			result:      Result{Version: "3.0", MediaFiles: mp4},
+			video:       &openrtb2.Video{MIMEs: []string{"video/webm", "video/ogg"}},
+			expectedErr: "invalid VAST: VAST has no media file of the imp mimes video/webm, video/ogg",
+		},
+		{
+			name:        "inline-protocol-not-allowed",
+			result:      Result{Version: "4.0", MediaFiles: mp4},
+			video:       &openrtb2.Video{Protocols: []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST30}},
+			expectedErr: "invalid VAST: VAST version 4.0 isn't in the imp protocols",
+		},
+		{
+			name:        "wrapper-protocol-not-allowed",
+			result:      Result{Version: "3.0", WrapperVersion: "4.0", MediaFiles: mp4},
+			video:       &openrtb2.Video{Protocols: []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST30, adcom1.CreativeVAST30Wrapper}},
+			expectedErr: "invalid VAST: VAST 4.0 wrappers aren't in the imp protocols",
+		},
+		{
+			name:   "unknown-version-not-checked",
+			result: Result{Version: "9.9", MediaFiles: mp4},
+			video:  &openrtb2.Video{Protocols: []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST30}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(&test.result, test.video)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package vastunwrap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+)
+
+// document is the part of a VAST document needed to follow wrappers and validate the inline ad.
+type document struct {
+	XMLName xml.Name `xml:"VAST"`
+	Version string   `xml:"version,attr"`
+	Ads     []ad     `xml:"Ad"`
+}
+
+type ad struct {
+	InLine  *inLine  `xml:"InLine"`
+	Wrapper *wrapper `xml:"Wrapper"`
+}
+
+type wrapper struct {
+	VASTAdTagURI string   `xml:"VASTAdTagURI"`
+	Impressions  []string `xml:"Impression"`
+	Errors       []string `xml:"Error"`
+}
+
+type inLine struct {
+	Creatives []creative `xml:"Creatives>Creative"`
+}
+
+type creative struct {
+	Linear *linear `xml:"Linear"`
+}
+
+type linear struct {
+	Duration   string      `xml:"Duration"`
+	MediaFiles []MediaFile `xml:"MediaFiles>MediaFile"`
+}
+
+// MediaFile is a media file of a linear creative.
+type MediaFile struct {
+	Type string `xml:"type,attr"`
+	URL  string `xml:",chardata"`
+}
+
+func parseDocument(vastXML []byte) (*document, error) {
+	var doc document
+	if err := xml.Unmarshal(vastXML, &doc); err != nil {
+		return nil, invalidf("VAST isn't valid XML: %v", err)
+	}
+	if len(doc.Ads) == 0 {
+		return nil, invalidf("VAST has no ads")
+	}
+	return &doc, nil
+}
+
+// parseDuration parses a VAST HH:MM:SS or HH:MM:SS.mmm duration.
+func parseDuration(duration string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(duration), ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("duration %q isn't HH:MM:SS", duration)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 {
+		return 0, fmt.Errorf("duration %q isn't HH:MM:SS", duration)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("duration %q isn't HH:MM:SS", duration)
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil || seconds < 0 || seconds >= 60 {
+		return 0, fmt.Errorf("duration %q isn't HH:MM:SS", duration)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second)), nil
+}
+
+// addTrackers adds the impression and error trackers of the wrappers to the first inline ad, as a player
+// playing the inline VAST directly would otherwise never call them.
+func addTrackers(inlineXML string, impressions, errorURLs []string) string {
+	if len(impressions) == 0 && len(errorURLs) == 0 {
+		return inlineXML
+	}
+	end := strings.Index(inlineXML, "</InLine>")
+	if end < 0 {
+		return inlineXML
+	}
+
+	var trackers strings.Builder
+	for _, url := range errorURLs {
+		writeTracker(&trackers, "Error", url)
+	}
+	for _, url := range impressions {
+		writeTracker(&trackers, "Impression", url)
+	}
+	return inlineXML[:end] + trackers.String() + inlineXML[end:]
+}
+
+func writeTracker(trackers *strings.Builder, element, url string) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return
+	}
+	trackers.WriteString("<" + element + "><![CDATA[")
+	trackers.WriteString(strings.ReplaceAll(url, "]]>", "]]]]><![CDATA[>"))
+	trackers.WriteString("]]></" + element + ">")
+}
+
+var inlineProtocols = map[string]adcom1.MediaCreativeSubtype{
+	"1.0": adcom1.CreativeVAST10,
+	"2.0": adcom1.CreativeVAST20,
+	"3.0": adcom1.CreativeVAST30,
+	"4.0": adcom1.CreativeVAST40,
+	"4.1": adcom1.CreativeVAST41,
+	"4.2": adcom1.CreativeVAST42,
+}
+
+var wrapperProtocols = map[string]adcom1.MediaCreativeSubtype{
+	"1.0": adcom1.CreativeVAST10Wrapper,
+	"2.0": adcom1.CreativeVAST20Wrapper,
+	"3.0": adcom1.CreativeVAST30Wrapper,
+	"4.0": adcom1.CreativeVAST40Wrapper,
+	"4.1": adcom1.CreativeVAST41Wrapper,
+	"4.2": adcom1.CreativeVAST42Wrapper,
+}
+
+// protocol returns the OpenRTB protocol of a VAST version, like 4.1 or 4.1.0, and false if it isn't known.
+func protocol(version string, wrapper bool) (adcom1.MediaCreativeSubtype, bool) {
+	parts := strings.SplitN(strings.TrimSpace(version), ".", 3)
+	if len(parts) == 1 {
+		parts = append(parts, "0")
+	}
+	majorMinor := parts[0] + "." + parts[1]
+	if wrapper {
+		p, ok := wrapperProtocols[majorMinor]
+		return p, ok
+	}
+	p, ok := inlineProtocols[majorMinor]
+	return p, ok
+}
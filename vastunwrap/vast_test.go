@@ -0,0 +1,66 @@
+package vastunwrap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	testCases := []struct {
+		duration    string
+		expected    time.Duration
+		expectedErr bool
+	}{
+		{duration: "00:00:30", expected: 30 * time.Second},
+		{duration: " 01:02:03.500 ", expected: time.Hour + 2*time.Minute + 3500*time.Millisecond},
+		{duration: "00:30", expectedErr: true},
+		{duration: "00:60:00", expectedErr: true},
+		{duration: "00:00:xx", expectedErr: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.duration, func(t *testing.T) {
+			duration, err := parseDuration(test.duration)
+			assert.Equal(t, test.expectedErr, err != nil)
+			assert.Equal(t, test.expected, duration)
+		})
+	}
+}
+
+func TestAddTrackers(t *testing.T) {
+	inline := `<VAST version="3.0"><Ad><InLine><AdSystem>x</AdSystem></InLine></Ad></VAST>`
+
+	assert.Equal(t, inline, addTrackers(inline, nil, nil))
+	assert.Equal(t, `<VAST version="3.0"><Ad><InLine><AdSystem>x</AdSystem>`+
+		`<Error><![CDATA[https://err.example.com]]></Error>`+
+		`<Impression><![CDATA[https://imp.example.com?a=]]]]><![CDATA[>]]></Impression>`+
+		`</InLine></Ad></VAST>`,
+		addTrackers(inline, []string{" https://imp.example.com?a=]]> ", ""}, []string{"https://err.example.com"}))
+}
+
+func TestProtocol(t *testing.T) {
+	testCases := []struct {
+		version    string
+		wrapper    bool
+		expected   adcom1.MediaCreativeSubtype
+		expectedOK bool
+	}{
+		{version: "3.0", expected: adcom1.CreativeVAST30, expectedOK: true},
+		{version: "4.1.0", expected: adcom1.CreativeVAST41, expectedOK: true},
+		{version: "2", wrapper: true, expected: adcom1.CreativeVAST20Wrapper, expectedOK: true},
+		{version: "4.2", wrapper: true, expected: adcom1.CreativeVAST42Wrapper, expectedOK: true},
+		{version: "", expectedOK: false},
+		{version: "5.0", expectedOK: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.version, func(t *testing.T) {
+			p, ok := protocol(test.version, test.wrapper)
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expected, p)
+		})
+	}
+}